@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/bootstrap"
+)
+
+var revision = "local"
+
+// closeTimeout bounds closing the repositories
+const closeTimeout = 10 * time.Second
+
+// Compares the ticks and liquidations stored within DATADIFF_FROM - DATADIFF_TO in the REPOSITORY_* repository
+// against the DATADIFF_AGAINST_REPOSITORY_* one and reports the missing and differing records.
+// It exits with 1 when the repositories differ, so it can gate a migration
+func main() {
+	fmt.Printf("Exchange Data Importer repository comparison: %s\n", revision)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	job, err := bootstrap.NewBuilder().
+		ValidateDataDiffOptions().
+		WithLogger(ctx).
+		WithRepository(ctx).
+		BuildDataDiff(ctx)
+	if err != nil {
+		fmt.Printf("Error building repository comparison: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, runErr := job.Run(ctx)
+	fmt.Printf("Ticks: %d compared against %d, %d missing, %d only in the compared repository, %d differing\n",
+		result.Ticks, result.AgainstTicks, result.MissingTicks, result.ExtraTicks, result.DifferingTicks)
+	fmt.Printf("Liquidations: %d compared against %d, %d missing, %d only in the compared repository, %d differing\n",
+		result.Liquidations, result.AgainstLiquidations, result.MissingLiquidations, result.ExtraLiquidations, result.DifferingLiquidations)
+	for _, diff := range result.Differences {
+		fmt.Println(diff)
+	}
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	if err := job.Close(closeCtx); err != nil {
+		fmt.Printf("Error closing repositories: %v\n", err)
+	}
+
+	if runErr != nil {
+		fmt.Printf("Error comparing repositories: %v\n", runErr)
+		os.Exit(1)
+	}
+	if !result.Equal() {
+		os.Exit(1)
+	}
+}
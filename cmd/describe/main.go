@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ayankousky/exchange-data-importer/internal/bootstrap"
+	"github.com/ayankousky/exchange-data-importer/internal/dictionary"
+)
+
+// Prints the data dictionary of the stored ticks, liquidations and bars: storage name, meaning, unit, window and
+// the decimals of the configured PRECISION_* options, as a table or as JSON with DESCRIBE_FORMAT=json
+func main() {
+	records, format, err := bootstrap.NewBuilder().
+		ValidateDescribeOptions().
+		BuildDictionary()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building data dictionary: %v\n", err)
+		os.Exit(1)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(records)
+	} else {
+		err = dictionary.Print(os.Stdout, records)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error printing data dictionary: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -2,44 +2,151 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
-
-	_ "github.com/mattn/go-sqlite3"
+	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/bootstrap"
+	"github.com/ayankousky/exchange-data-importer/internal/selfcheck"
+	"github.com/ayankousky/exchange-data-importer/internal/soak"
+)
+
+var (
+	revision  = "local"
+	buildDate = ""
 )
 
-var revision = "local"
+// shutdownTimeout bounds the whole graceful shutdown
+const shutdownTimeout = 30 * time.Second
 
 func main() {
-	fmt.Printf("Exchange Data Importer: %s\n", revision)
+	build := bootstrap.NewBuildInfo(revision, buildDate)
+	if len(os.Args) > 1 && os.Args[1] == "version" {
+		fmt.Println(build)
+		return
+	}
+
+	fmt.Printf("Exchange Data Importer: %s\n", build)
 	// Create context that can be canceled by system signals
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	// Build the application
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		if !check(ctx) {
+			stop()
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "soak" {
+		if !runSoak(ctx) {
+			stop()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Build an importer per enabled exchange
 	app, err := bootstrap.NewBuilder().
+		ValidateOptions().
 		WithLogger(ctx).
-		WithExchange(ctx).
-		WithRepository(ctx).
-		WithNotifiers(ctx).
-		WithTelemetry(ctx, revision).
-		Build()
+		WithTelemetry(ctx, build).
+		BuildExchanges(ctx)
 	if err != nil {
 		fmt.Printf("Error building application: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Start the application
-	if err := app.Start(ctx); err != nil {
-		fmt.Printf("Error starting application: %v\n", err)
-		os.Exit(1)
+	// Export the in-memory history on SIGUSR1 (not on Windows) to debug indicators without restarting
+	snapshots := make(chan os.Signal, 1)
+	notifySnapshot(snapshots)
+	defer signal.Stop(snapshots)
+	go func() {
+		for range snapshots {
+			if _, err := app.ExportHistory(); err != nil {
+				fmt.Printf("Error exporting history: %v\n", err)
+			}
+		}
+	}()
+
+	// Start the importers; it blocks until the context is canceled
+	startErr := app.Start(ctx)
+	if startErr != nil {
+		fmt.Printf("Error starting application: %v\n", startErr)
 	}
 
-	// Wait for shutdown signal
-	<-ctx.Done()
 	fmt.Println("Shutting down gracefully...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := app.Stop(shutdownCtx); err != nil {
+		fmt.Printf("Error stopping application: %v\n", err)
+	}
+
+	if startErr != nil {
+		os.Exit(1)
+	}
+}
+
+// check verifies the configured exchanges, repositories and notifiers against the live services once and prints
+// a pass/fail report, it reports whether every check passed
+func check(ctx context.Context) bool {
+	job, err := bootstrap.NewBuilder().
+		ValidateCheckOptions().
+		WithLogger(ctx).
+		BuildCheck(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building self-check: %v\n", err)
+		return false
+	}
+
+	report := job.Run(ctx)
+	var printErr error
+	if job.Format() == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		printErr = enc.Encode(report)
+	} else {
+		printErr = selfcheck.Print(os.Stdout, report)
+	}
+	if printErr != nil {
+		fmt.Fprintf(os.Stderr, "Error printing report: %v\n", printErr)
+	}
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := job.Close(closeCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error closing self-check: %v\n", err)
+	}
+	return printErr == nil && report.Passed()
+}
+
+// runSoak publishes synthetic ticks of SOAK_SYMBOLS tickers at SOAK_RATE per second for SOAK_DURATION to the notifiers
+// configured with NOTIFY_*, and reports the throughput, queue depth and drop rate of every client. It reports whether
+// the soak test ran
+func runSoak(ctx context.Context) bool {
+	job, err := bootstrap.NewBuilder().
+		ValidateSoakOptions().
+		WithLogger(ctx).
+		WithNotifiers(ctx).
+		BuildSoak()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building soak test: %v\n", err)
+		return false
+	}
+
+	report := job.Run(ctx, func(progress soak.Report) {
+		progress.Print(os.Stdout)
+		fmt.Println()
+	})
+	fmt.Println("Final report:")
+	report.Print(os.Stdout)
+
+	if err := job.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error closing notifiers: %v\n", err)
+	}
+	return true
 }
@@ -124,8 +124,10 @@ func TestMainApplicationFlow(t *testing.T) {
 				// Cancel context to trigger shutdown
 				cancel()
 
-				// Wait briefly to allow for cleanup
-				time.Sleep(100 * time.Millisecond)
+				// Stop the remaining components
+				stopCtx, stopCancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer stopCancel()
+				assert.NoError(t, app.Stop(stopCtx))
 			}
 		})
 	}
@@ -0,0 +1,7 @@
+//go:build postgres
+
+package main
+
+// The postgres repository is opened with the pgx driver, linked in only when built with -tags postgres
+// so the default build doesn't carry it
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifySnapshot relays SIGUSR1 to the channel exporting the in-memory history
+func notifySnapshot(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
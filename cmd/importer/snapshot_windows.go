@@ -0,0 +1,8 @@
+//go:build windows
+
+package main
+
+import "os"
+
+// notifySnapshot is a no-op, Windows has no SIGUSR1 to request a snapshot with
+func notifySnapshot(chan<- os.Signal) {}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/bootstrap"
+	"github.com/ayankousky/exchange-data-importer/internal/leaderboard"
+)
+
+// closeTimeout bounds closing the repository
+const closeTimeout = 10 * time.Second
+
+// Prints the liquidation leaderboards of the REPOSITORY_* repository: the symbols with the largest notional
+// liquidated within LEADERBOARD_WINDOW and the largest single liquidations of the current UTC day,
+// as tables or as JSON with LEADERBOARD_FORMAT=json
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	job, err := bootstrap.NewBuilder().
+		ValidateLeaderboardOptions().
+		WithLogger(ctx).
+		WithRepository(ctx).
+		BuildLeaderboard(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building leaderboards: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, runErr := job.Run(ctx)
+	if runErr == nil {
+		if job.Format() == "json" {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			runErr = enc.Encode(report)
+		} else {
+			runErr = leaderboard.Print(os.Stdout, report)
+		}
+	}
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	if err := job.Close(closeCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Error closing repository: %v\n", err)
+	}
+
+	if runErr != nil {
+		fmt.Fprintf(os.Stderr, "Error ranking liquidations: %v\n", runErr)
+		os.Exit(1)
+	}
+}
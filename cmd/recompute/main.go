@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/bootstrap"
+)
+
+var revision = "local"
+
+// closeTimeout bounds closing the repositories
+const closeTimeout = 10 * time.Second
+
+// Recomputes the indicators of stored ticks within RECOMPUTE_FROM - RECOMPUTE_TO
+// and writes them to the recomputed collection tagged with the current indicators version
+func main() {
+	fmt.Printf("Exchange Data Importer indicator recomputation: %s\n", revision)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	job, err := bootstrap.NewBuilder().
+		ValidateRecomputeOptions().
+		WithLogger(ctx).
+		WithRepository(ctx).
+		BuildRecompute()
+	if err != nil {
+		fmt.Printf("Error building recomputation job: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, runErr := job.Run(ctx)
+	fmt.Printf("Recomputed %d ticks with indicators version %d (%d with another stored version, %d warmup ticks, %d invalid tickers skipped)\n",
+		result.Ticks, result.Version, result.IndicatorsDrift, result.WarmupTicks, result.TickersSkipped)
+
+	closeCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+	if err := job.Close(closeCtx); err != nil {
+		fmt.Printf("Error closing repositories: %v\n", err)
+	}
+
+	if runErr != nil {
+		fmt.Printf("Error recomputing indicators: %v\n", runErr)
+		os.Exit(1)
+	}
+}
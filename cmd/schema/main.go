@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ayankousky/exchange-data-importer/internal/schema"
+)
+
+// Writes the JSON Schema of the published event payloads to -dir, or with -check compares the schemas
+// of a previous version in -dir with the current payloads and fails on breaking changes
+func main() {
+	dir := flag.String("dir", "schema", "directory of the schema files")
+	check := flag.Bool("check", false, "report breaking changes against the schemas in -dir instead of writing them")
+	flag.Parse()
+
+	if !*check {
+		if err := schema.Write(*dir); err != nil {
+			fmt.Printf("Error writing schemas: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %d schemas to %s\n", len(schema.Payloads()), *dir)
+		return
+	}
+
+	changes, err := schema.Check(*dir)
+	if err != nil {
+		fmt.Printf("Error checking schemas: %v\n", err)
+		os.Exit(1)
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) > 0 {
+		fmt.Printf("%d breaking changes against %s\n", len(changes), *dir)
+		os.Exit(1)
+	}
+	fmt.Printf("No breaking changes against %s\n", *dir)
+}
@@ -2,47 +2,339 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
+	"github.com/ayankousky/exchange-data-importer/internal/composite"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+	"github.com/ayankousky/exchange-data-importer/internal/outage"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/ayankousky/exchange-data-importer/internal/importer"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
 )
 
+// Component names used to declare shutdown dependencies
+const (
+	componentTelemetry    = "telemetry"
+	componentRepositories = "repositories"
+	componentNotifiers    = "notifiers"
+	componentEventBus     = "eventbus"
+	componentOpsAlerts    = "opsalerts"
+	componentOutages      = "outages"
+	componentImporter     = "importer"
+)
+
 // App represents the bootstrapped application
 type App struct {
 	logger            *zap.Logger
+	logLevel          zap.AtomicLevel
 	exchange          exchanges.Exchange
 	importer          *importer.Importer
 	repositoryFactory importer.RepositoryFactory
+	events            *eventbus.Bus
+	notifier          *notifier.Notifier
 	notifiers         []NotifierConfig
+	opsAlerts         *opsalert.Monitor
+	outages           *outage.Tracker
+	compositor        *composite.Compositor
 	telemetry         telemetry.Provider
+	sharedTelemetry   bool // the provider is shared with the apps of the other exchanges and shut down by Apps
 	options           *Options
+	manifest          Manifest
+
+	lifecycle *lifecycle
+
+	mu              sync.Mutex
+	cancelImport    context.CancelFunc
+	importDone      chan error
+	cancelOpsAlerts context.CancelFunc
+	opsAlertsDone   chan struct{}
+	cancelOutages   context.CancelFunc
+	outagesDone     chan struct{}
 }
 
 // NotifierConfig holds notifier configuration
 type NotifierConfig struct {
+	Name     string // kind of client, e.g. redis
 	Client   notify.Client
 	Topic    string
 	Strategy notify.Strategy
+	Shadow   bool // events are formatted, logged and counted, but not sent; Client is nil
 }
 
-// Start initializes and starts the application
+// Start starts all components and blocks until the import loop stops.
+// Cancelling ctx stops the import loop; call Stop afterwards to release the remaining components.
 func (a *App) Start(ctx context.Context) error {
-	// Add notifiers to the importer
-	for _, notifier := range a.notifiers {
-		if err := a.importer.WithNotifier(notifier.Client, notifier.Topic, notifier.Strategy); err != nil {
-			a.logger.Warn("Error adding notifier", zap.Error(err))
-		}
+	if err := a.lifecycle.start(ctx); err != nil {
+		return err
 	}
 
-	// Start handling imports
-	if err := a.importer.Start(ctx); err != nil {
+	a.mu.Lock()
+	done := a.importDone
+	a.mu.Unlock()
+
+	if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
 		return fmt.Errorf("starting import loop: %w", err)
 	}
+	return nil
+}
+
+// Stop shuts the components down in reverse dependency order: the importer and its websockets first,
+// then the event bus and notifiers, then repositories and finally telemetry so nothing is lost on the way out.
+func (a *App) Stop(ctx context.Context) error {
+	return a.lifecycle.stop(ctx)
+}
+
+// LogLevel returns the current log level
+func (a *App) LogLevel() zapcore.Level {
+	return a.logLevel.Level()
+}
 
+// SetLogLevel changes the log level at runtime, e.g. from the admin interface
+func (a *App) SetLogLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("parsing log level: %w", err)
+	}
+	a.logLevel.SetLevel(parsed)
+	a.logger.Info("Log level changed", zap.Stringer("level", parsed))
 	return nil
 }
+
+// NotifierCircuits returns the circuit breaker state of every notifier client, e.g. for a health check
+func (a *App) NotifierCircuits() []notifier.Circuit {
+	return a.notifier.Circuits()
+}
+
+// ExportHistory writes the in-memory tick and ticker history of the importer to a JSON file in the snapshot
+// directory and returns its path, so indicator issues can be debugged without restarting the importer
+func (a *App) ExportHistory() (string, error) {
+	name := fmt.Sprintf("%s-history-%s.json", a.exchange.GetName(), time.Now().UTC().Format("20060102T150405Z"))
+	path := filepath.Join(a.options.Snapshot.Dir, name)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("creating history snapshot: %w", err)
+	}
+	if err := a.importer.ExportHistory(file); err != nil {
+		_ = file.Close()
+		return "", fmt.Errorf("exporting history: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("closing history snapshot: %w", err)
+	}
+	a.logger.Info("History snapshot exported", zap.String("path", path))
+	return path, nil
+}
+
+// registerComponents declares every component with its dependencies
+func (a *App) registerComponents() {
+	a.lifecycle = newLifecycle(a.logger)
+
+	a.lifecycle.add(component{
+		name: componentTelemetry,
+		stop: func(ctx context.Context) error {
+			if a.sharedTelemetry {
+				return nil
+			}
+			return waitFor(ctx, a.telemetry.Shutdown)
+		},
+	})
+
+	a.lifecycle.add(component{
+		name:      componentRepositories,
+		dependsOn: []string{componentTelemetry},
+		stop: func(ctx context.Context) error {
+			if closer, ok := a.repositoryFactory.(interface{ Close(context.Context) error }); ok {
+				return closer.Close(ctx)
+			}
+			return nil
+		},
+	})
+
+	a.lifecycle.add(component{
+		name:      componentNotifiers,
+		dependsOn: []string{componentTelemetry},
+		start: func(_ context.Context) error {
+			for _, n := range a.notifiers {
+				var err error
+				if n.Shadow {
+					err = a.notifier.SubscribeShadow(n.Topic, n.Name, n.Strategy)
+				} else {
+					err = a.notifier.Subscribe(n.Topic, n.Client, n.Strategy)
+				}
+				if err != nil {
+					return fmt.Errorf("subscribing %s notifier: %w", n.Name, err)
+				}
+			}
+			return a.writeManifest()
+		},
+		stop: func(_ context.Context) error {
+			return closeNotifiers(a.notifiers)
+		},
+	})
+
+	a.lifecycle.add(component{
+		name:      componentOutages,
+		dependsOn: []string{componentRepositories},
+		start: func(ctx context.Context) error {
+			outagesCtx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+
+			a.mu.Lock()
+			a.cancelOutages = cancel
+			a.outagesDone = done
+			a.mu.Unlock()
+
+			go func() {
+				defer close(done)
+				a.outages.Run(outagesCtx)
+			}()
+			return nil
+		},
+		stop: func(ctx context.Context) error {
+			a.mu.Lock()
+			cancel, done := a.cancelOutages, a.outagesDone
+			a.mu.Unlock()
+			if cancel == nil {
+				return nil
+			}
+
+			// The event bus is drained by now, the outages still open end at shutdown
+			cancel()
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			a.outages.Close(ctx)
+			return nil
+		},
+	})
+
+	a.lifecycle.add(component{
+		name:      componentEventBus,
+		dependsOn: []string{componentNotifiers, componentOutages, componentTelemetry},
+		stop: func(ctx context.Context) error {
+			// Drains events already published so notifiers see them before closing
+			return waitFor(ctx, a.events.Close)
+		},
+	})
+
+	a.lifecycle.add(component{
+		name:      componentOpsAlerts,
+		dependsOn: []string{componentEventBus},
+		start: func(ctx context.Context) error {
+			alertsCtx, cancel := context.WithCancel(ctx)
+			done := make(chan struct{})
+
+			a.mu.Lock()
+			a.cancelOpsAlerts = cancel
+			a.opsAlertsDone = done
+			a.mu.Unlock()
+
+			go func() {
+				defer close(done)
+				a.opsAlerts.Run(alertsCtx)
+			}()
+			return nil
+		},
+		stop: func(ctx context.Context) error {
+			a.mu.Lock()
+			cancel, done := a.cancelOpsAlerts, a.opsAlertsDone
+			a.mu.Unlock()
+			if cancel == nil {
+				return nil
+			}
+
+			// Alerts are published on the bus, so the monitor stops before it
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+
+	a.lifecycle.add(component{
+		name:      componentImporter,
+		dependsOn: []string{componentEventBus, componentRepositories, componentTelemetry},
+		start: func(ctx context.Context) error {
+			importCtx, cancel := context.WithCancel(ctx)
+			done := make(chan error, 1)
+
+			a.mu.Lock()
+			a.cancelImport = cancel
+			a.importDone = done
+			a.mu.Unlock()
+
+			go func() {
+				done <- a.importer.Start(importCtx)
+				close(done)
+			}()
+			return nil
+		},
+		stop: func(ctx context.Context) error {
+			a.mu.Lock()
+			cancel, done := a.cancelImport, a.importDone
+			a.mu.Unlock()
+			if cancel == nil {
+				return nil
+			}
+
+			// Cancelling the import context also closes the exchange websockets
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// closeNotifiers closes every client once, clients subscribed to several topics are shared
+func closeNotifiers(notifiers []NotifierConfig) error {
+	var errs []error
+	closed := make(map[notify.Client]struct{})
+	for _, n := range notifiers {
+		closer, ok := n.Client.(interface{ Close() error })
+		if _, seen := closed[n.Client]; !ok || seen {
+			continue
+		}
+		closed[n.Client] = struct{}{}
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitFor runs fn and waits for it to return or for ctx to expire
+func waitFor(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,122 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/server/api"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"go.uber.org/zap/zapcore"
+)
+
+// Apps runs the App of every enabled exchange in one process. Each App has its own exchange client, repositories
+// and notifiers, they share the logger and the telemetry provider
+type Apps struct {
+	apps      []*App
+	build     BuildInfo
+	telemetry telemetry.Provider
+	api       *api.Server // serves the data of every App, nil when the API is disabled
+}
+
+// Apps returns the App of every enabled exchange, in the order the exchanges are listed in the options
+func (g *Apps) Apps() []*App {
+	return g.apps
+}
+
+// Start starts every App and blocks until all their import loops stop. An App failing stops the others too,
+// so the process exits and is restarted as a whole, as a single importer would be.
+// Cancelling ctx stops the import loops; call Stop afterwards to release the remaining components.
+func (g *Apps) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make([]error, len(g.apps))
+	var wg sync.WaitGroup
+	for i, app := range g.apps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := app.Start(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", app.exchange.GetName(), err)
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Stop stops the API and every App, then shuts the shared telemetry down once nothing records into it anymore
+func (g *Apps) Stop(ctx context.Context) error {
+	var apiErr error
+	if g.api != nil {
+		if err := g.api.Close(); err != nil {
+			apiErr = fmt.Errorf("api: %w", err)
+		}
+	}
+
+	errs := make([]error, len(g.apps))
+	var wg sync.WaitGroup
+	for i, app := range g.apps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := app.Stop(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", app.exchange.GetName(), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := waitFor(ctx, g.telemetry.Shutdown); err != nil {
+		errs = append(errs, fmt.Errorf("telemetry: %w", err))
+	}
+	return errors.Join(append(errs, apiErr)...)
+}
+
+// ExportHistory exports the in-memory history of every App and returns the paths of the files written
+func (g *Apps) ExportHistory() ([]string, error) {
+	var paths []string
+	var errs []error
+	for _, app := range g.apps {
+		path, err := app.ExportHistory()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", app.exchange.GetName(), err))
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths, errors.Join(errs...)
+}
+
+// LogLevel returns the current log level, shared by the Apps
+func (g *Apps) LogLevel() zapcore.Level {
+	return g.apps[0].LogLevel()
+}
+
+// SetLogLevel changes the log level of every App at runtime, e.g. from the admin route of the API
+func (g *Apps) SetLogLevel(level string) error {
+	for _, app := range g.apps {
+		if err := app.SetLogLevel(level); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifierCircuits returns the circuit breaker state of the notifier clients of every App, keyed by exchange name
+func (g *Apps) NotifierCircuits() map[string][]notifier.Circuit {
+	circuits := make(map[string][]notifier.Circuit, len(g.apps))
+	for _, app := range g.apps {
+		circuits[app.exchange.GetName()] = app.NotifierCircuits()
+	}
+	return circuits
+}
+
+// Build returns the build info of the binary, e.g. for the health route of the API
+func (g *Apps) Build() any {
+	return g.build
+}
@@ -3,53 +3,121 @@ package bootstrap
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/memory"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/sqlite"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/maintenance"
+	"github.com/ayankousky/exchange-data-importer/internal/metrics"
 	"github.com/ayankousky/exchange-data-importer/internal/notifier"
 	notificationStrategies "github.com/ayankousky/exchange-data-importer/internal/notifier/strategies"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+	"github.com/ayankousky/exchange-data-importer/internal/outage"
+	"github.com/ayankousky/exchange-data-importer/internal/symbolmeta"
 	"go.uber.org/zap"
 
 	"github.com/ayankousky/exchange-data-importer/internal/importer"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
 	binanceExchange "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/binance"
 	bybitExchange "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/bybit"
+	coinbaseExchange "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/coinbase"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/coinglass"
 	okxExchange "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/okx"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/mongo"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/postgres"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/server/api"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/server/ws"
 )
 
+// outageRepositoryFactory is implemented by the repository factories able to store outage records
+type outageRepositoryFactory interface {
+	GetOutageRepository(name string) (domain.OutageRepository, error)
+}
+
+// alertRepositoryFactory is implemented by the repository factories able to store the fired market alerts
+type alertRepositoryFactory interface {
+	GetAlertRepository(name string) (domain.AlertRepository, error)
+}
+
 // Builder builds the App instance
 type Builder struct {
 	app *App
 	err error
+
+	// exchangeKind and repositoryKind are used to tag telemetry
+	exchangeKind   string
+	repositoryKind string
+
+	// repositoryOps records the operations of the persistent repositories, it gets the telemetry in Build
+	repositoryOps *instrument.Recorder
+
+	// build of the binary, recorded in the run manifest
+	build BuildInfo
+
+	// clients registered with WithClient, added to the notifiers of every exchange
+	clients []clientConfig
+
+	// ws is the WebSocket server shared by the Apps of every exchange, they can't listen on the same address
+	ws *sharedWSServer
+}
+
+// sharedWSServer creates the WebSocket server once
+type sharedWSServer struct {
+	once   sync.Once
+	server *ws.Server
+	err    error
 }
 
-// NewBuilder creates a new Builder instance
+// clientConfig is an in-process notify.Client and the topics it's subscribed to
+type clientConfig struct {
+	name   string
+	client notify.Client
+	topics []string
+}
+
+// NewBuilder creates a new Builder instance, the options are read from the command line and the environment
 func NewBuilder() *Builder {
+	return NewBuilderWithArgs(os.Args[1:])
+}
+
+// NewBuilderWithArgs creates a new Builder instance reading the options from args in place of the command line,
+// e.g. when the importer runs as a library
+func NewBuilderWithArgs(args []string) *Builder {
 	app := &App{}
 
-	app.logger, _ = infrastructure.NewLogger("development", "exchange-data-importer")
+	app.logger, app.logLevel, _ = infrastructure.NewLoggerWithConfig(infrastructure.LoggerConfig{
+		Env:     "development",
+		Service: "exchange-data-importer",
+	})
 	app.repositoryFactory = memory.NewInMemoryRepoFactory()
 	app.telemetry = &telemetry.NoopProvider{}
 
 	builder := &Builder{
-		app: app,
+		app:            app,
+		repositoryKind: "memory",
 	}
-	builder.fetchOptions()
+	builder.fetchOptions(args)
 
 	return builder
 }
 
 // fetchOptions automatically fetches options from env/flags
-func (b *Builder) fetchOptions() *Builder {
+func (b *Builder) fetchOptions(args []string) *Builder {
 	if b.err != nil {
 		return b
 	}
 
-	opts, err := ParseOptions()
+	opts, err := ParseOptionsArgs(args)
 	if err != nil {
 		b.err = fmt.Errorf("parsing options: %w", err)
 		return b
@@ -59,19 +127,39 @@ func (b *Builder) fetchOptions() *Builder {
 	return b
 }
 
+// ValidateOptions checks the options before any component is created, reporting all problems at once
+func (b *Builder) ValidateOptions() *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.app.options.Validate(); err != nil {
+		b.err = err
+	}
+	return b
+}
+
 // WithLogger initializes the logger
 func (b *Builder) WithLogger(_ context.Context) *Builder {
 	if b.err != nil {
 		return b
 	}
 
-	logger, err := infrastructure.NewLogger(b.app.options.Env, b.app.options.ServiceName)
+	logger, level, err := infrastructure.NewLoggerWithConfig(infrastructure.LoggerConfig{
+		Env:                b.app.options.Env,
+		Service:            b.app.options.ServiceName,
+		Level:              b.app.options.Log.Level,
+		Format:             b.app.options.Log.Format,
+		SamplingInitial:    b.app.options.Log.Sampling.Initial,
+		SamplingThereafter: b.app.options.Log.Sampling.Thereafter,
+	})
 	if err != nil {
 		b.err = fmt.Errorf("creating logger: %w", err)
 		return b
 	}
 
 	b.app.logger = logger
+	b.app.logLevel = level
 	return b
 }
 
@@ -83,28 +171,57 @@ func (b *Builder) WithExchange(_ context.Context) *Builder {
 
 	if b.app.options.Exchange.Binance.Enabled {
 		b.app.exchange = binanceExchange.NewBinance(binanceExchange.Config{
-			Name:   b.app.options.ServiceName,
-			APIUrl: b.app.options.Exchange.Binance.APIUrl,
-			WSUrl:  b.app.options.Exchange.Binance.WSUrl,
+			Name:       b.app.options.ExchangeName(),
+			APIUrl:     b.app.options.Exchange.Binance.APIUrl,
+			HTTPClient: exchanges.NewHTTPClient(httpHeaders(b.app.options.Exchange.Binance.Headers)),
+			WSUrl:      b.app.options.Exchange.Binance.WSUrl,
+			Websocket:  b.app.options.Exchange.Binance.WS.config(b.app.options.Exchange.Binance.Headers),
+
+			EventAtFallback: exchanges.EventAtFallback(b.app.options.Exchange.Binance.EventAtFallback),
 		})
+		b.exchangeKind = "binance"
 		return b
 	}
 
 	if b.app.options.Exchange.Bybit.Enabled {
 		b.app.exchange = bybitExchange.NewBybit(bybitExchange.Config{
-			Name:   b.app.options.ServiceName,
-			APIUrl: b.app.options.Exchange.Bybit.APIUrl,
-			WSUrl:  b.app.options.Exchange.Bybit.WSUrl,
+			Name:       b.app.options.ExchangeName(),
+			APIUrl:     b.app.options.Exchange.Bybit.APIUrl,
+			HTTPClient: exchanges.NewHTTPClient(httpHeaders(b.app.options.Exchange.Bybit.Headers)),
+			WSUrl:      b.app.options.Exchange.Bybit.WSUrl,
+			Categories: b.app.options.Exchange.Bybit.Categories,
+			Websocket:  b.app.options.Exchange.Bybit.WS.config(b.app.options.Exchange.Bybit.Headers),
+
+			EventAtFallback: exchanges.EventAtFallback(b.app.options.Exchange.Bybit.EventAtFallback),
 		})
+		b.exchangeKind = "bybit"
 		return b
 	}
 
 	if b.app.options.Exchange.OKX.Enabled {
 		b.app.exchange = okxExchange.NewOKX(okxExchange.Config{
-			Name:   b.app.options.ServiceName,
-			APIUrl: b.app.options.Exchange.OKX.APIUrl,
-			WSUrl:  b.app.options.Exchange.OKX.WSUrl,
+			Name:       b.app.options.ExchangeName(),
+			APIUrl:     b.app.options.Exchange.OKX.APIUrl,
+			HTTPClient: exchanges.NewHTTPClient(httpHeaders(b.app.options.Exchange.OKX.Headers)),
+			WSUrl:      b.app.options.Exchange.OKX.WSUrl,
+			InstTypes:  b.app.options.Exchange.OKX.InstTypes,
+			Websocket:  b.app.options.Exchange.OKX.WS.config(b.app.options.Exchange.OKX.Headers),
+
+			EventAtFallback: exchanges.EventAtFallback(b.app.options.Exchange.OKX.EventAtFallback),
+		})
+		b.exchangeKind = "okx"
+		return b
+	}
+
+	if b.app.options.Exchange.Coinbase.Enabled {
+		b.app.exchange = coinbaseExchange.NewCoinbase(coinbaseExchange.Config{
+			Name:       b.app.options.ExchangeName(),
+			APIUrl:     b.app.options.Exchange.Coinbase.APIUrl,
+			HTTPClient: exchanges.NewHTTPClient(httpHeaders(b.app.options.Exchange.Coinbase.Headers)),
+
+			EventAtFallback: exchanges.EventAtFallback(b.app.options.Exchange.Coinbase.EventAtFallback),
 		})
+		b.exchangeKind = "coinbase"
 		return b
 	}
 
@@ -118,32 +235,87 @@ func (b *Builder) WithRepository(ctx context.Context) *Builder {
 		return b
 	}
 
-	if b.app.options.Repository.Mongo.Enabled {
-		mongoClient, err := infrastructure.NewMongoClient(ctx, b.app.options.Repository.Mongo.URL)
+	b.repositoryOps = instrument.NewRecorder(b.app.logger, b.app.options.Repository.SlowOp)
+	repoFactory, kind, err := b.newRepositoryFactory(ctx, b.app.options.Repository, b.repositoryOps)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if repoFactory != nil {
+		b.app.repositoryFactory = repoFactory
+		b.repositoryKind = kind
+	}
+	return b
+}
+
+// newRepositoryFactory creates the factory of the enabled persistent repository and returns its kind,
+// a nil factory when none is enabled. Its repositories record their operations with ops
+func (b *Builder) newRepositoryFactory(ctx context.Context, opts RepositoryOptions, ops *instrument.Recorder) (importer.RepositoryFactory, string, error) {
+	if opts.Mongo.Enabled {
+		mongoClient, err := infrastructure.NewMongoClient(ctx, opts.Mongo.URL)
 		if err != nil {
-			b.err = fmt.Errorf("creating mongo client: %w", err)
-			return b
+			return nil, "", fmt.Errorf("creating mongo client: %w", err)
 		}
 		repoFactory, err := mongo.NewMongoRepoFactory(mongoClient)
 		if err != nil {
-			b.err = fmt.Errorf("creating repository factory: %w", err)
-			return b
+			return nil, "", fmt.Errorf("creating repository factory: %w", err)
 		}
-		b.app.repositoryFactory = repoFactory
-		return b
+		if err := setCodec(repoFactory, opts.Codec); err != nil {
+			return nil, "", fmt.Errorf("creating repository factory: %w", err)
+		}
+		return repoFactory.WithInstrumentation(ops), "mongo", nil
 	}
 
-	if b.app.options.Repository.Sqlite.Enabled && b.app.options.Repository.Sqlite.Path != "" {
-		dsn := fmt.Sprintf("file:%s_%s?cache=shared&_foreign_keys=on", b.app.options.ServiceName, b.app.options.Repository.Sqlite.Path)
+	if opts.Sqlite.Enabled && opts.Sqlite.Path != "" {
+		dsn := sqlite.DSN(fmt.Sprintf("%s_%s", b.app.options.ServiceName, opts.Sqlite.Path))
 		repoFactory, err := sqlite.NewSQLiteRepoFactory(dsn)
 		if err != nil {
-			b.err = fmt.Errorf("creating repository factory: %w", err)
-			return b
+			return nil, "", fmt.Errorf("creating repository factory: %w", err)
 		}
-		b.app.repositoryFactory = repoFactory
+		if err := setCodec(repoFactory, opts.Codec); err != nil {
+			return nil, "", fmt.Errorf("creating repository factory: %w", err)
+		}
+		return repoFactory.WithInstrumentation(ops), "sqlite", nil
+	}
+
+	if opts.Postgres.Enabled {
+		repoFactory, err := postgres.NewPostgresRepoFactory(ctx, opts.Postgres.URL, opts.Postgres.Partitioning)
+		if err != nil {
+			return nil, "", fmt.Errorf("creating repository factory: %w", err)
+		}
+		if err := setCodec(repoFactory, opts.Codec); err != nil {
+			return nil, "", fmt.Errorf("creating repository factory: %w", err)
+		}
+		return repoFactory.WithInstrumentation(ops), "postgres", nil
+	}
+
+	return nil, "", nil
+}
+
+// setCodec sets the named codec on the sink, an empty name keeps the default of the sink
+func setCodec(sink codec.Sink, name string) error {
+	if name == "" {
+		return nil
+	}
+	c, err := codec.Lookup(name)
+	if err != nil {
+		return err
+	}
+	return sink.SetCodec(c)
+}
+
+// WithClient subscribes an in-process client, e.g. a notify.ChannelNotifier, to the topics. It's added to the
+// notifiers by WithNotifiers, or by BuildExchanges to the notifiers of every exchange, so it's called before them
+func (b *Builder) WithClient(name string, client notify.Client, topics ...string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if client == nil || len(topics) == 0 {
+		b.err = fmt.Errorf("client %q: a client and at least one topic are required", name)
 		return b
 	}
 
+	b.clients = append(b.clients, clientConfig{name: name, client: client, topics: topics})
 	return b
 }
 
@@ -168,15 +340,32 @@ func (b *Builder) WithNotifiers(ctx context.Context) *Builder {
 
 	// Initialize Redis notifier if configured
 	if b.app.options.Notify.Redis.Topics != "" {
-		redisClient, err := infrastructure.NewRedisClient(ctx, b.app.options.Notify.Redis.URL, 1)
+		redis := b.app.options.Notify.Redis
+		redisClient, err := infrastructure.NewRedisClient(ctx, infrastructure.RedisConfig{
+			Mode:             redis.Mode,
+			URL:              redis.URL,
+			Addrs:            redis.Addrs,
+			MasterName:       redis.MasterName,
+			DB:               redis.DB,
+			Username:         redis.Username,
+			Password:         redis.Password,
+			SentinelUsername: redis.SentinelUsername,
+			SentinelPassword: redis.SentinelPassword,
+			TLS:              redis.TLS,
+			TLSCAFile:        redis.TLSCAFile,
+			TLSSkipVerify:    redis.TLSSkipVerify,
+			PoolSize:         redis.PoolSize,
+			MinIdleConns:     redis.MinIdleConns,
+		})
 		if err != nil {
 			b.app.logger.Warn("Failed to initialize Redis notifier", zap.Error(err))
 		} else {
 			for _, topic := range splitTopics(b.app.options.Notify.Redis.Topics) {
 				notifiers = append(notifiers, NotifierConfig{
-					Client:   notify.NewRedisNotifier(redisClient, fmt.Sprintf("%s:%s", b.app.options.ServiceName, topic)),
+					Name:     "redis",
+					Client:   notify.NewRedisNotifier(redisClient, fmt.Sprintf("%s:%s", b.app.options.ServiceName, topic)).WithPipeline(redis.Pipeline),
 					Topic:    topic,
-					Strategy: &notificationStrategies.MarketDataStrategy{},
+					Strategy: b.topicStrategy(topic, "redis", b.app.options.Notify.Redis.Alert, b.app.options.Notify.Redis.MarketDataFormat, b.marketDataStrategy(b.app.options.Notify.Redis.MarketDataFormat)),
 				})
 			}
 		}
@@ -192,16 +381,14 @@ func (b *Builder) WithNotifiers(ctx context.Context) *Builder {
 		if err != nil {
 			b.app.logger.Warn("Failed to initialize Telegram notifier", zap.Error(err))
 		} else {
-			var tgAlertThresholds = notificationStrategies.AlertStrategyThresholds{
-				AvgPrice1mChange:    2.0,
-				AvgPrice20mChange:   5.0,
-				TickerPrice1mChange: 15.0,
-			}
+			thresholds := b.app.options.Notify.Telegram.Alert
 			for _, topic := range splitTopics(b.app.options.Notify.Telegram.Topics) {
+				// telegram only sends human-readable alerts, whatever the topic
 				notifiers = append(notifiers, NotifierConfig{
+					Name:     "telegram",
 					Client:   tgNotifier,
 					Topic:    topic,
-					Strategy: notificationStrategies.NewAlertStrategy(tgAlertThresholds),
+					Strategy: b.alertStrategy("telegram", thresholds),
 				})
 			}
 		}
@@ -212,9 +399,147 @@ func (b *Builder) WithNotifiers(ctx context.Context) *Builder {
 		stdoutNotifier := notify.NewConsoleNotifier()
 		for _, topic := range splitTopics(b.app.options.Notify.Stdout.Topics) {
 			notifiers = append(notifiers, NotifierConfig{
+				Name:     "stdout",
 				Client:   stdoutNotifier,
 				Topic:    topic,
-				Strategy: notificationStrategies.NewTickInfoStrategy(),
+				Strategy: b.topicStrategy(topic, "stdout", b.app.options.Notify.Stdout.Alert, "", notificationStrategies.NewTickInfoStrategy()),
+			})
+		}
+	}
+
+	// Initialize InfluxDB notifier if configured
+	if b.app.options.Notify.Influx.Topics != "" {
+		influxNotifier, err := notify.NewInfluxNotifier(
+			b.app.options.Notify.Influx.URL,
+			b.app.options.Notify.Influx.Org,
+			b.app.options.Notify.Influx.Bucket,
+			b.app.options.Notify.Influx.Token,
+		)
+		if err != nil {
+			b.app.logger.Warn("Failed to initialize InfluxDB notifier", zap.Error(err))
+		} else {
+			tags := map[string]string{}
+			if b.app.options.ServiceName != "" {
+				tags["service"] = b.app.options.ServiceName
+			}
+			if b.app.exchange != nil {
+				tags["exchange"] = b.app.exchange.GetName()
+			}
+			for _, topic := range splitTopics(b.app.options.Notify.Influx.Topics) {
+				notifiers = append(notifiers, NotifierConfig{
+					Name:     "influx",
+					Client:   influxNotifier,
+					Topic:    topic,
+					Strategy: notificationStrategies.NewLineProtocolStrategy(tags),
+				})
+			}
+		}
+	}
+
+	// Initialize webhook notifier if configured
+	if b.app.options.Notify.Webhook.Topics != "" {
+		webhookNotifier, err := notify.NewWebhookNotifier(b.app.options.Notify.Webhook.URL, b.app.options.Notify.Webhook.Token)
+		if err != nil {
+			b.app.logger.Warn("Failed to initialize webhook notifier", zap.Error(err))
+		} else {
+			for _, topic := range splitTopics(b.app.options.Notify.Webhook.Topics) {
+				notifiers = append(notifiers, NotifierConfig{
+					Name:     "webhook",
+					Client:   webhookNotifier,
+					Topic:    topic,
+					Strategy: notificationStrategies.NewAlertmanagerStrategy(b.app.options.ServiceName, b.app.options.OpsAlerts.ExternalURL),
+				})
+			}
+		}
+	}
+
+	// Initialize Grafana annotations notifier if configured
+	if b.app.options.Notify.Grafana.Topics != "" {
+		grafanaNotifier, err := notify.NewGrafanaNotifier(b.app.options.Notify.Grafana.URL, b.app.options.Notify.Grafana.Token)
+		if err != nil {
+			b.app.logger.Warn("Failed to initialize grafana notifier", zap.Error(err))
+		} else {
+			for _, topic := range splitTopics(b.app.options.Notify.Grafana.Topics) {
+				notifiers = append(notifiers, NotifierConfig{
+					Name:     "grafana",
+					Client:   grafanaNotifier,
+					Topic:    topic,
+					Strategy: &notificationStrategies.GrafanaAnnotationStrategy{},
+				})
+			}
+		}
+	}
+
+	// Initialize WebSocket server if configured
+	if b.app.options.Notify.WS.Topics != "" {
+		wsOpts := b.app.options.Notify.WS
+		wsServer, err := b.wsServer(splitTopics(wsOpts.Topics))
+		if err != nil {
+			b.app.logger.Warn("Failed to initialize WebSocket server", zap.Error(err))
+		} else {
+			for _, topic := range splitTopics(wsOpts.Topics) {
+				notifiers = append(notifiers, NotifierConfig{
+					Name:     "ws",
+					Client:   wsServer,
+					Topic:    topic,
+					Strategy: b.topicStrategy(topic, "ws", wsOpts.Alert, wsOpts.MarketDataFormat, b.marketDataStrategy(wsOpts.MarketDataFormat)),
+				})
+			}
+		}
+	}
+
+	// Initialize shadow subscriptions if configured, they need no client
+	if b.app.options.Notify.Shadow.Topics != "" {
+		shadowOpts := b.app.options.Notify.Shadow
+		for _, topic := range splitTopics(shadowOpts.Topics) {
+			notifiers = append(notifiers, NotifierConfig{
+				Name:     "shadow",
+				Topic:    topic,
+				Strategy: b.topicStrategy(topic, "", shadowOpts.Alert, shadowOpts.MarketDataFormat, b.marketDataStrategy(shadowOpts.MarketDataFormat)),
+				Shadow:   true,
+			})
+		}
+	}
+
+	// Initialize file sink notifier if configured
+	if b.app.options.Notify.File.Topics != "" {
+		fileOpts := b.app.options.Notify.File
+		fileNotifier, err := notify.NewFileSinkNotifier(notify.FileSinkConfig{
+			Dir:       fileOpts.Dir,
+			Prefix:    fileOpts.Prefix,
+			MaxBytes:  fileOpts.MaxSizeMB << 20,
+			MaxAge:    fileOpts.MaxAge,
+			Compress:  fileOpts.Compress,
+			Fsync:     fileOpts.Fsync,
+			MaxFiles:  fileOpts.MaxFiles,
+			Retention: fileOpts.Retention,
+		})
+		if err == nil {
+			err = setCodec(fileNotifier, fileOpts.Codec)
+		}
+		if err != nil {
+			b.app.logger.Warn("Failed to initialize file sink notifier", zap.Error(err))
+		} else {
+			for _, topic := range splitTopics(fileOpts.Topics) {
+				notifiers = append(notifiers, NotifierConfig{
+					Name:     "file",
+					Client:   fileNotifier,
+					Topic:    topic,
+					Strategy: b.topicStrategy(topic, "file", fileOpts.Alert, fileOpts.MarketDataFormat, b.marketDataStrategy(fileOpts.MarketDataFormat)),
+				})
+			}
+		}
+	}
+
+	// Add the in-process clients registered with WithClient
+	embeddedOpts := b.app.options.Notify.Embedded
+	for _, c := range b.clients {
+		for _, topic := range c.topics {
+			notifiers = append(notifiers, NotifierConfig{
+				Name:     c.name,
+				Client:   c.client,
+				Topic:    topic,
+				Strategy: b.topicStrategy(topic, c.name, embeddedOpts.Alert, embeddedOpts.MarketDataFormat, b.marketDataStrategy(embeddedOpts.MarketDataFormat)),
 			})
 		}
 	}
@@ -223,15 +548,203 @@ func (b *Builder) WithNotifiers(ctx context.Context) *Builder {
 	return b
 }
 
-// WithTelemetry initializes telemetry (e.g., metrics and tracing)
-func (b *Builder) WithTelemetry(ctx context.Context, revision string) *Builder {
+// wsServer returns the WebSocket server serving the topics, it's created by the first call and shared with
+// the builders of the other exchanges
+func (b *Builder) wsServer(topics []string) (*ws.Server, error) {
+	if b.ws == nil {
+		b.ws = &sharedWSServer{}
+	}
+	b.ws.once.Do(func() {
+		wsOpts := b.app.options.Notify.WS
+		b.ws.server, b.ws.err = ws.NewServer(ws.Config{
+			Addr:   wsOpts.Addr,
+			Path:   wsOpts.Path,
+			Topics: topics,
+			Token:  wsOpts.Token,
+			Buffer: wsOpts.Buffer,
+
+			KeyframeInterval: wsOpts.KeyframeInterval,
+
+			Logger: b.app.logger.Named("ws"),
+		})
+	})
+	return b.ws.server, b.ws.err
+}
+
+// topicStrategy returns the strategy formatting a topic for the client named notifierName. Market data, market
+// alerts and minute bars are formatted the same way by every client, in the MARKET_DATA format and with the alert
+// thresholds of the client; other topics use its default strategy
+func (b *Builder) topicStrategy(topic, notifierName string, thresholds AlertThresholdsOptions, marketDataFormat string, fallback notify.Strategy) notify.Strategy {
+	switch notifier.Topic(topic) {
+	case notifier.MarketDataTopic:
+		return b.marketDataStrategy(marketDataFormat)
+	case notifier.AlertTopic:
+		return b.alertStrategy(notifierName, thresholds)
+	case notifier.BarsTopic:
+		return &notificationStrategies.BarStrategy{}
+	case notifier.AnnotationTopic:
+		return &notificationStrategies.GrafanaAnnotationStrategy{}
+	default:
+		return fallback
+	}
+}
+
+// Formats of the MARKET_DATA events
+const (
+	marketDataFormatTicker = "ticker"
+	marketDataFormatDigest = "digest"
+)
+
+// marketDataStrategy returns the strategy sending an event per ticker, or a single digest event per tick,
+// the priority symbols first. An empty format is the NOTIFY_MARKET_DATA_FORMAT of every notifier
+func (b *Builder) marketDataStrategy(format string) notify.Strategy {
+	if format == "" {
+		format = b.app.options.Notify.MarketDataFormat
+	}
+	priority := make([]domain.TickerName, 0, len(b.app.options.Priority.Symbols))
+	for _, symbol := range b.app.options.Priority.Symbols {
+		priority = append(priority, domain.TickerName(symbol))
+	}
+	if format == marketDataFormatDigest {
+		return &notificationStrategies.MarketDataDigestStrategy{Priority: priority}
+	}
+	return &notificationStrategies.MarketDataStrategy{Priority: priority}
+}
+
+// alertStrategy returns a market alert strategy with the given thresholds, comparing tickers to
+// their composite price when it's enabled. The alerts are published on the bus to be stored with the name of
+// the notifier, shadow subscriptions pass an empty name as their alerts are never sent
+func (b *Builder) alertStrategy(notifierName string, thresholds AlertThresholdsOptions) notify.Strategy {
+	strategy := notificationStrategies.NewAlertStrategy(notificationStrategies.AlertStrategyThresholds{
+		AvgPrice1mChange:    thresholds.AvgPrice1mChange,
+		AvgPrice20mChange:   thresholds.AvgPrice20mChange,
+		TickerPrice1mChange: thresholds.TickerPrice1mChange,
+		FairPriceDeviation:  b.app.options.Composite.AlertDeviation,
+
+		CategoryPrice1mChange: thresholds.CategoryPrice1mChange,
+
+		TickerPrice1mChangeBySymbol: notificationStrategies.NewSymbolThresholds(thresholds.TickerPrice1mChangeBySymbol),
+
+		Persistence: thresholds.Persistence,
+	})
+	if b.app.compositor != nil {
+		strategy.WithFairPrices(b.app.compositor)
+	}
+	if notifierName != "" {
+		exchange := b.app.options.ExchangeName()
+		strategy.WithRecorder(func(alert domain.Alert) {
+			alert.Exchange = exchange
+			alert.Notifier = notifierName
+			b.app.events.Publish(eventbus.AlertFired, alert)
+		})
+	}
+	return strategy
+}
+
+// tickCheckNames are the checks TICK_CHECKS_REJECT accepts
+var tickCheckNames = []string{domain.CheckFetchDuration, domain.CheckTickersCount, domain.CheckAvgChange}
+
+// coinglassExchanges names the supported exchanges as Coinglass does
+var coinglassExchanges = map[string]string{
+	"binance":  "Binance",
+	"bybit":    "Bybit",
+	"okx":      "OKX",
+	"coinbase": "Coinbase",
+}
+
+// liquidationSources returns the enabled external providers of the liquidations of the exchange
+func (b *Builder) liquidationSources() []exchanges.LiquidationSource {
+	var sources []exchanges.LiquidationSource
+
+	if opts := b.app.options.Liquidations.Coinglass; opts.Enabled {
+		exchange := opts.Exchange
+		if exchange == "" {
+			exchange = coinglassExchanges[b.exchangeKind]
+		}
+		sources = append(sources, coinglass.NewCoinglass(coinglass.Config{
+			APIUrl:       opts.APIUrl,
+			Exchange:     exchange,
+			PollInterval: opts.PollInterval,
+			HTTPClient:   exchanges.NewHTTPClient(httpHeaders(map[string]string{"CG-API-KEY": opts.APIKey})),
+		}))
+	}
+
+	return sources
+}
+
+// tickChecks returns the enabled tick checks, rejecting the ticks for the checks listed in TICK_CHECKS_REJECT
+// and flagging them as suspect otherwise
+func (b *Builder) tickChecks() []domain.TickCheck {
+	opts := b.app.options.TickChecks
+	severity := func(name string) domain.CheckSeverity {
+		if slices.Contains(opts.Reject, name) {
+			return domain.SeverityReject
+		}
+		return domain.SeveritySuspect
+	}
+
+	var checks []domain.TickCheck
+	if opts.FetchDuration > 0 {
+		checks = append(checks, domain.FetchDurationCheck(opts.FetchDuration, severity(domain.CheckFetchDuration)))
+	}
+	if opts.TickersDrop > 0 {
+		checks = append(checks, domain.TickersCountCheck(opts.TickersDrop, severity(domain.CheckTickersCount)))
+	}
+	if opts.AvgChange > 0 {
+		checks = append(checks, domain.AvgChangeCheck(opts.AvgChange, severity(domain.CheckAvgChange)))
+	}
+	return checks
+}
+
+// outageRepository returns the repository storing the exchange outage records
+func (b *Builder) outageRepository() (domain.OutageRepository, error) {
+	factory, ok := b.app.repositoryFactory.(outageRepositoryFactory)
+	if !ok {
+		return nil, fmt.Errorf("repository %s does not support outage records", b.repositoryKind)
+	}
+	repo, err := factory.GetOutageRepository(b.app.options.ExchangeName())
+	if err != nil {
+		return nil, fmt.Errorf("creating outage repository: %w", err)
+	}
+	return repo, nil
+}
+
+// alertRepository returns the repository storing the fired market alerts
+func (b *Builder) alertRepository() (domain.AlertRepository, error) {
+	factory, ok := b.app.repositoryFactory.(alertRepositoryFactory)
+	if !ok {
+		return nil, fmt.Errorf("repository %s does not support alerts", b.repositoryKind)
+	}
+	repo, err := factory.GetAlertRepository(b.app.options.ExchangeName())
+	if err != nil {
+		return nil, fmt.Errorf("creating alert repository: %w", err)
+	}
+	return repo, nil
+}
+
+// storeAlert returns the event bus handler storing the fired market alerts, a failed store is logged and the alert
+// dropped, it's never worth delaying the notifications for
+func storeAlert(repo domain.AlertRepository, logger *zap.Logger) eventbus.Handler {
+	return func(ctx context.Context, event eventbus.Event) {
+		alert, ok := event.Payload.(domain.Alert)
+		if !ok {
+			return
+		}
+		if err := repo.Create(ctx, alert); err != nil {
+			logger.Error("Failed to store alert", zap.String("notifier", alert.Notifier), zap.Time("tick_key", alert.TickKey), zap.Error(err))
+		}
+	}
+}
+
+// WithTelemetry initializes telemetry (e.g., metrics and tracing), tagged with the build
+func (b *Builder) WithTelemetry(ctx context.Context, build BuildInfo) *Builder {
 	if b.err != nil {
 		return b
 	}
 
-	revisionTag := fmt.Sprintf("revision:%s", revision)
+	b.build = build
 
-	// Initialize datadog provider
+	var providers []telemetry.Provider
 	if b.app.options.Telemetry.Datadog.Enabled {
 		datadogConfig := &telemetry.DatadogConfig{
 			AgentHost:       b.app.options.Telemetry.Datadog.AgentHost,
@@ -241,38 +754,288 @@ func (b *Builder) WithTelemetry(ctx context.Context, revision string) *Builder {
 			EnableTracing:   b.app.options.Telemetry.Datadog.EnabledTracing,
 			EnableMetrics:   b.app.options.Telemetry.Datadog.EnabledMetrics,
 			EnableProfiling: b.app.options.Telemetry.Datadog.EnabledProfiling,
-			Tags:            []string{revisionTag},
+			Tags:            build.tags(),
 		}
 
 		fmt.Printf("Datadog Config: %+v\n", datadogConfig)
-		telemetryProvider := telemetry.NewDatadogProvider(datadogConfig)
-		if err := telemetryProvider.Initialize(ctx); err != nil {
-			b.err = fmt.Errorf("initializing telemetry provider: %w", err)
+		providers = append(providers, telemetry.NewDatadogProvider(datadogConfig))
+	}
+	if prometheus := b.app.options.Telemetry.Prometheus; prometheus.Enabled {
+		tags := append(build.tags(), "service:"+b.app.options.ServiceName)
+		if b.app.options.Env != "" {
+			tags = append(tags, "env:"+b.app.options.Env)
 		}
-		b.app.telemetry = telemetryProvider
+		providers = append(providers, telemetry.NewPrometheusProvider(&telemetry.PrometheusConfig{
+			Addr:      prometheus.Addr,
+			Path:      prometheus.Path,
+			Namespace: prometheus.Namespace,
+			Tags:      tags,
+			Metrics:   metrics.Catalog(),
+		}))
+	}
+	if otel := b.app.options.Telemetry.OTel; otel.Enabled {
+		providers = append(providers, telemetry.NewOTelProvider(&telemetry.OTelConfig{
+			Endpoint:       otel.Endpoint,
+			Protocol:       otel.Protocol,
+			Insecure:       otel.Insecure,
+			ServiceName:    b.app.options.ServiceName,
+			ServiceEnv:     b.app.options.Env,
+			Tags:           build.tags(),
+			Metrics:        metrics.Catalog(),
+			ExportInterval: otel.Interval,
+			EnableTracing:  otel.EnabledTracing,
+			EnableMetrics:  otel.EnabledMetrics,
+		}))
 	}
 
+	var telemetryProvider telemetry.Provider
+	switch len(providers) {
+	case 0:
+		return b
+	case 1:
+		telemetryProvider = providers[0]
+	default:
+		telemetryProvider = telemetry.NewMultiProvider(providers...)
+	}
+	if err := telemetryProvider.Initialize(ctx); err != nil {
+		b.err = fmt.Errorf("initializing telemetry provider: %w", err)
+	}
+	b.app.telemetry = telemetryProvider
 	return b
 }
 
+// BuildExchanges builds an App per enabled exchange from the options of the exchange, see exchangeOptions.
+// It takes the place of WithExchange, WithRepository, WithComposite, WithNotifiers and Build: the Apps share
+// the logger and the telemetry provider, so it runs after WithLogger and WithTelemetry
+func (b *Builder) BuildExchanges(ctx context.Context) (*Apps, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	enabled := b.app.options.enabledExchanges()
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("no exchange configured")
+	}
+
+	apps := &Apps{build: b.build, telemetry: b.app.telemetry}
+	for _, kind := range enabled {
+		app, err := b.exchangeBuilder(kind).
+			WithExchange(ctx).
+			WithRepository(ctx).
+			WithComposite().
+			WithNotifiers(ctx).
+			Build()
+		if err != nil {
+			return nil, fmt.Errorf("building %s importer: %w", kind, err)
+		}
+		apps.apps = append(apps.apps, app)
+	}
+
+	if b.app.options.API.Enabled {
+		sources := make(map[string]api.Source, len(apps.apps))
+		for _, app := range apps.apps {
+			sources[app.exchange.GetName()] = app.importer
+		}
+		server, err := api.NewServer(api.Config{
+			Addr:    b.app.options.API.Addr,
+			Token:   b.app.options.API.Token,
+			Sources: sources,
+			Admin:   apps,
+			Health:  apps,
+			Logger:  b.app.logger.Named("api"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("starting API server: %w", err)
+		}
+		apps.api = server
+	}
+	return apps, nil
+}
+
+// exchangeBuilder returns a builder of the App of one of the enabled exchanges, built from the options
+// of the exchange and sharing the logger and the telemetry provider
+func (b *Builder) exchangeBuilder(kind string) *Builder {
+	options := b.app.options.exchangeOptions(kind)
+	if b.ws == nil {
+		b.ws = &sharedWSServer{}
+	}
+	logger := b.app.logger
+	if len(b.app.options.enabledExchanges()) > 1 {
+		logger = logger.With(zap.String("instance", options.ExchangeName()))
+	}
+
+	return &Builder{
+		app: &App{
+			logger:            logger,
+			logLevel:          b.app.logLevel,
+			repositoryFactory: memory.NewInMemoryRepoFactory(),
+			telemetry:         b.app.telemetry,
+			sharedTelemetry:   true,
+			options:           options,
+		},
+		repositoryKind: "memory",
+		build:          b.build,
+		clients:        b.clients,
+		ws:             b.ws,
+	}
+}
+
 // Build returns the built App instance
 func (b *Builder) Build() (*App, error) {
 	if b.err != nil {
 		return nil, b.err
 	}
-	notifier := notifier.New(b.app.logger) // currently hardcoded as there is no alternatives
+	b.app.telemetry = telemetry.NewTaggedProvider(b.app.telemetry, map[string]string{
+		telemetry.TagExchange:   b.exchangeKind,
+		telemetry.TagRepository: b.repositoryKind,
+	})
+	b.repositoryOps.WithTelemetry(b.app.telemetry)
+	for _, n := range b.app.notifiers {
+		if redis, ok := n.Client.(*notify.RedisNotifier); ok {
+			redis.WithTelemetry(b.app.telemetry)
+		}
+	}
+	b.app.events = eventbus.New(b.app.logger).WithTelemetry(b.app.telemetry)
+	b.app.notifier = notifier.New(b.app.logger).WithTelemetry(b.app.telemetry) // currently hardcoded as there is no alternatives
+	b.app.notifier.WithCircuitBreaker(notifier.CircuitBreakerConfig{
+		Threshold: b.app.options.Notify.Breaker.Threshold,
+		Cooldown:  b.app.options.Notify.Breaker.Cooldown,
+	})
+	b.app.events.Subscribe("notifier", func(ctx context.Context, event eventbus.Event) {
+		b.app.notifier.Notify(ctx, event.Payload)
+	}, eventbus.TickBuilt, eventbus.OperationalAlert, eventbus.BarsClosed)
+
+	maintenanceWindows, err := maintenance.Parse(b.app.options.maintenanceWindows())
+	if err != nil {
+		return nil, fmt.Errorf("parsing maintenance windows: %w", err)
+	}
+	var symbolMeta *symbolmeta.Catalog
+	if file := b.app.options.SymbolMeta.File; file != "" {
+		if symbolMeta, err = symbolmeta.Load(file); err != nil {
+			return nil, fmt.Errorf("loading symbol metadata: %w", err)
+		}
+	}
+
+	opsAlertLabels := map[string]string{"service": b.app.options.ServiceName}
+	if b.app.exchange != nil {
+		opsAlertLabels["exchange"] = b.app.exchange.GetName()
+	}
+	tickGap := b.app.options.OpsAlerts.TickGap
+	if !importer.Mode(b.app.options.ImportMode).RunsTickers() {
+		tickGap = 0 // no tick is built, the gap would always fire
+	}
+	b.app.opsAlerts = opsalert.NewMonitor(opsalert.Rules{
+		TickGap:                        tickGap,
+		ReconnectStormCount:            b.app.options.OpsAlerts.ReconnectStormCount,
+		ReconnectStormWindow:           b.app.options.OpsAlerts.ReconnectStormWindow,
+		RepositoryFailureResolveAfter:  b.app.options.OpsAlerts.RepositoryResolveAfter,
+		StreamSilence:                  b.app.options.OpsAlerts.StreamSilence,
+		LiquidationStreamSilence:       b.app.options.OpsAlerts.LiquidationStreamSilence,
+		StreamSpikeFactor:              b.app.options.OpsAlerts.StreamSpikeFactor,
+		StreamBaseline:                 b.app.options.OpsAlerts.StreamBaseline,
+		SuspectLiquidationResolveAfter: b.app.options.OpsAlerts.SuspectLiquidationsResolveAfter,
+		ClockSkew:                      b.app.options.OpsAlerts.ClockSkew,
+	}, opsAlertLabels, func(alert opsalert.Alert) {
+		b.app.events.Publish(eventbus.OperationalAlert, alert)
+	}, b.app.logger).WithTelemetry(b.app.telemetry).WithMaintenance(maintenanceWindows)
+	b.app.events.Subscribe("opsalert", b.app.opsAlerts.Handle, eventbus.TickBuilt, eventbus.ImportDegraded, eventbus.StreamRateMeasured, eventbus.LiquidationStorageMeasured, eventbus.LiquidationReceived)
+
+	if b.app.compositor != nil {
+		b.app.compositor.WithTelemetry(b.app.telemetry)
+		b.app.events.Subscribe("composite", b.app.compositor.Handle, eventbus.TickBuilt)
+	}
+
+	outages, err := b.outageRepository()
+	if err != nil {
+		return nil, err
+	}
+	b.app.outages = outage.NewTracker(outages, b.app.options.Outages.ResolveAfter, b.app.logger).WithTelemetry(b.app.telemetry)
+	b.app.events.Subscribe("outage", b.app.outages.Handle, eventbus.ImportDegraded, eventbus.TickBuilt, eventbus.LiquidationReceived)
+
+	alerts, err := b.alertRepository()
+	if err != nil {
+		return nil, err
+	}
+	b.app.events.Subscribe("alerts", storeAlert(alerts, b.app.logger), eventbus.AlertFired)
 
 	b.app.importer = importer.New(&importer.Config{
-		Exchange:          b.app.exchange,
-		RepositoryFactory: b.app.repositoryFactory,
-		NotifierService:   notifier,
-		Logger:            b.app.logger,
-		Telemetry:         b.app.telemetry,
+		Exchange:                b.app.exchange,
+		RepositoryFactory:       b.app.repositoryFactory,
+		EventBus:                b.app.events,
+		Mode:                    importer.Mode(b.app.options.ImportMode),
+		PublishOrder:            importer.PublishOrder(b.app.options.PublishOrder),
+		LiquidationSources:      b.liquidationSources(),
+		LiquidationsBackfill:    b.app.options.Liquidations.Backfill,
+		ArchiveRawLiquidations:  b.app.options.Liquidations.ArchiveRaw,
+		MaxLiquidationDeviation: b.app.options.Liquidations.MaxPriceDeviation,
+		LogTickSummary:          b.app.options.Log.Ticks,
+		LiquidationStorage: importer.LiquidationStorageConfig{
+			SpikeFactor: b.app.options.Liquidations.Storage.SpikeFactor,
+			Baseline:    b.app.options.Liquidations.Storage.Baseline,
+			BatchSize:   b.app.options.Liquidations.Storage.BatchSize,
+		},
+		Liquidity: importer.LiquidityFilter{
+			MinNotional:  b.app.options.Liquidity.MinNotional,
+			DropIlliquid: b.app.options.Liquidity.Drop,
+		},
+		SymbolMapper: exchanges.SymbolMapper{
+			QuoteAliases: b.app.options.Symbols.QuoteAliases,
+			Quotes:       b.app.options.Symbols.Quotes,
+		},
+		HighRes: importer.HighResConfig{
+			Symbols:  b.app.options.HighRes.Symbols,
+			Interval: b.app.options.HighRes.Interval,
+		},
+		Priority: importer.PriorityConfig{
+			Symbols:  b.app.options.Priority.Symbols,
+			Deadline: b.app.options.Priority.Deadline,
+		},
+		StorageSampling: importer.StorageSamplingConfig{
+			Tiers: b.app.options.Symbols.Sampling.Tiers,
+			Every: b.app.options.Symbols.Sampling.Every,
+		},
+		NormalizeUSD:         b.app.options.USD.Normalize,
+		Bars:                 b.app.options.Bars.Enabled,
+		FundingInterval:      b.app.options.Funding.Interval,
+		OpenInterestInterval: b.app.options.OpenInterest.Interval,
+		TradeSymbols:         b.app.options.Trades.Symbols,
+		Quality: importer.QualityConfig{
+			FetchBudget:            b.app.options.Quality.FetchBudget,
+			LiquidationsStaleAfter: b.app.options.Quality.LiquidationsStaleAfter,
+		},
+		SymbolRegistry: importer.SymbolRegistryConfig{
+			Enabled:     b.app.options.Symbols.Registry,
+			DelistAfter: b.app.options.Symbols.DelistAfter,
+		},
+		TickChecks:     b.tickChecks(),
+		Precision:      b.app.options.Precision.precision(),
+		Maintenance:    maintenanceWindows,
+		SymbolMeta:     symbolMeta,
+		WarmUp:         b.app.options.WarmUp,
+		HistoryTimeout: b.app.options.HistoryTimeout,
+		MaxSymbols:     b.app.options.Symbols.MaxTracked,
+		TickOffset:     b.app.options.tickOffset(),
+		Logger:         b.app.logger,
+		Telemetry:      b.app.telemetry,
 	})
 
 	if b.app.exchange == nil || b.app.importer == nil {
 		return nil, fmt.Errorf("missing required dependencies")
 	}
 
+	b.app.manifest = Manifest{
+		Service:    b.app.options.ServiceName,
+		Exchange:   b.app.exchange.GetName(),
+		Repository: b.repositoryKind,
+		ImportMode: b.app.options.ImportMode,
+		BuildInfo:  b.build,
+		Notifiers:  notifierSubscriptions(b.app.notifiers),
+	}
+
+	b.app.registerComponents()
+	if _, err := b.app.lifecycle.startOrder(); err != nil {
+		return nil, fmt.Errorf("ordering components: %w", err)
+	}
+
 	return b.app, nil
 }
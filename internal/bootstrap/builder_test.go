@@ -2,31 +2,40 @@ package bootstrap
 
 import (
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/server/ws"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	notificationStrategies "github.com/ayankousky/exchange-data-importer/internal/notifier/strategies"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 // newTestOptions returns Options configured for testing.
 func newTestOptions(exchangeEnabled bool) *Options {
-	return &Options{
+	opts := &Options{
 		Env:         "test",
 		ServiceName: "test-service",
-		Exchange: ExchangeOptions{
-			Binance: struct {
-				Enabled bool   `long:"enabled" env:"ENABLED" description:"Enable Binance exchange"`
-				APIUrl  string `long:"api-url" env:"API_URL" description:"(optional) Binance API URL"`
-				WSUrl   string `long:"ws-url" env:"WS_URL" description:"(optional) Binance WebSocket URL"`
-			}{
-				Enabled: exchangeEnabled,
-				APIUrl:  "https://dummy-api.binance.com",
-				WSUrl:   "wss://dummy-ws.binance.com",
-			},
-		},
-		Repository: RepositoryOptions{},
-		Notify:     NotifyOptions{}, // leave empty for tests
+		Repository:  RepositoryOptions{},
+		Notify:      NotifyOptions{}, // leave empty for tests
 	}
+	opts.Exchange.Binance.Enabled = exchangeEnabled
+	opts.Exchange.Binance.APIUrl = "https://dummy-api.binance.com"
+	opts.Exchange.Binance.WSUrl = "wss://dummy-ws.binance.com"
+	return opts
 }
 
 func TestBuilder(t *testing.T) {
@@ -63,6 +72,26 @@ func TestBuilder(t *testing.T) {
 			validate: func(t *testing.T, app *App) {
 				assert.NotNil(t, app.exchange, "exchange should be set")
 				assert.NotNil(t, app.importer, "importer should be set")
+				assert.Nil(t, app.compositor, "composite price is disabled without peers")
+			},
+		},
+		{
+			name: "build with composite price peers",
+			setupBuilder: func() *Builder {
+				b := NewBuilder()
+				b.app.options = newTestOptions(true)
+				b.app.options.Composite.Peers = []string{"okx"}
+				ctx := context.Background()
+				b.WithLogger(ctx)
+				b.WithExchange(ctx)
+				b.WithRepository(ctx)
+				b.WithComposite()
+				b.WithNotifiers(ctx)
+				return b
+			},
+			wantBuildErr: false,
+			validate: func(t *testing.T, app *App) {
+				assert.NotNil(t, app.compositor, "compositor should be set")
 			},
 		},
 	}
@@ -85,35 +114,41 @@ func TestBuilder(t *testing.T) {
 	}
 }
 
+func TestBuilder_OpsAlertsFollowTheBus(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	ctx := context.Background()
+	b.WithLogger(ctx)
+	b.WithExchange(ctx)
+	b.WithRepository(ctx)
+	b.WithNotifiers(ctx)
+	app, err := b.Build()
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var fired []string
+	app.events.Subscribe("test", func(_ context.Context, event eventbus.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		fired = append(fired, event.Payload.(opsalert.Alert).Name())
+	}, eventbus.OperationalAlert)
+
+	app.events.Publish(eventbus.LiquidationStorageMeasured, eventbus.StorageRate{Stored: 40, Interval: time.Second, Baseline: 2, Spiking: true})
+	app.events.Flush()
+	app.opsAlerts.Evaluate()
+	app.events.Flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, fired, opsalert.NameLiquidationStorageSpike)
+}
+
 func TestBuilderWithEmptyNotifiers(t *testing.T) {
 	// Setup builder with empty notifier topics
 	b := NewBuilder()
 	opts := newTestOptions(true)
-	opts.Notify = NotifyOptions{
-		Redis: struct {
-			URL    string `long:"url" env:"URL" description:"Redis URL"`
-			Topics string `long:"topics" env:"TOPICS" description:"Comma-separated list of topics"`
-		}{
-			URL:    "redis://dummy",
-			Topics: "",
-		},
-		Telegram: struct {
-			BotToken string `long:"bot-token" env:"BOT_TOKEN" description:"Telegram bot token"`
-			ChatID   string `long:"chat-id" env:"CHAT_ID" description:"Telegram chat ID"`
-			Interval int    `long:"interval" env:"INTERVAL" description:"Min interval in seconds between notifications"`
-			Topics   string `long:"topics" env:"TOPICS" description:"Comma-separated list of topics"`
-		}{
-			BotToken: "",
-			ChatID:   "",
-			Interval: 0,
-			Topics:   "",
-		},
-		Stdout: struct {
-			Topics string `long:"topics" env:"TOPICS" description:"Comma-separated list of topics"`
-		}{
-			Topics: "random topic",
-		},
-	}
+	opts.Notify.Redis.URL = "redis://dummy"
+	opts.Notify.Stdout.Topics = "random topic"
 	b.app.options = opts
 
 	ctx := context.Background()
@@ -123,8 +158,346 @@ func TestBuilderWithEmptyNotifiers(t *testing.T) {
 	assert.Equal(t, 1, len(b.app.notifiers), "no notifiers should be configured when topics are empty")
 }
 
+func TestBuilderWithClient(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	client := notify.NewChannelNotifier(1)
+
+	b.WithClient("embedded", client, string(notifier.MarketDataTopic), string(notifier.AlertTopic))
+	b.WithNotifiers(context.Background())
+	require.NoError(t, b.err)
+	require.Len(t, b.app.notifiers, 2)
+	assert.Equal(t, "embedded", b.app.notifiers[0].Name)
+	assert.Same(t, client, b.app.notifiers[0].Client)
+	assert.Equal(t, string(notifier.AlertTopic), b.app.notifiers[1].Topic)
+
+	assert.Len(t, b.exchangeBuilder("binance").clients, 1, "the clients are added to the notifiers of every exchange")
+
+	b.WithClient("embedded", client)
+	assert.EqualError(t, b.err, `client "embedded": a client and at least one topic are required`)
+}
+
+func TestBuilderWithWSServer(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Notify.WS.Addr = "127.0.0.1:0"
+	b.app.options.Notify.WS.Topics = "TICK_INFO,ALERT_MARKET_STATE"
+
+	b.WithNotifiers(context.Background())
+	require.NoError(t, b.err)
+	require.Len(t, b.app.notifiers, 2)
+	server, ok := b.app.notifiers[0].Client.(*ws.Server)
+	require.True(t, ok)
+	defer server.Close()
+	assert.Equal(t, "ws", b.app.notifiers[1].Name)
+	assert.Same(t, server, b.app.notifiers[1].Client)
+
+	exchangeBuilder := b.exchangeBuilder("binance")
+	exchangeBuilder.WithNotifiers(context.Background())
+	require.NotEmpty(t, exchangeBuilder.app.notifiers)
+	assert.Same(t, server, exchangeBuilder.app.notifiers[0].Client, "the server is shared by the exchanges")
+}
+
+func TestBuilderTopicStrategies(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	loose := AlertThresholdsOptions{AvgPrice1mChange: 4, AvgPrice20mChange: 10, TickerPrice1mChange: 30}
+	fallback := notificationStrategies.NewTickInfoStrategy()
+
+	assert.Same(t, fallback, b.topicStrategy(string(notifier.TickInfoTopic), "stdout", loose, "", fallback))
+	assert.IsType(t, &notificationStrategies.BarStrategy{}, b.topicStrategy(string(notifier.BarsTopic), "redis", loose, "", fallback))
+
+	// the MARKET_DATA format of every notifier, unless the notifier overrides it
+	b.app.options.Notify.MarketDataFormat = marketDataFormatDigest
+	assert.IsType(t, &notificationStrategies.MarketDataDigestStrategy{}, b.topicStrategy(string(notifier.MarketDataTopic), "stdout", loose, "", fallback))
+	assert.IsType(t, &notificationStrategies.MarketDataStrategy{}, b.topicStrategy(string(notifier.MarketDataTopic), "file", loose, marketDataFormatTicker, fallback))
+
+	// a tick moving the market by 3% in a minute alerts with the default thresholds only
+	tick := &domain.Tick{Avg: domain.TickAvg{Change1m: 3}, Data: map[domain.TickerName]*domain.Ticker{}}
+	strict := AlertThresholdsOptions{AvgPrice1mChange: 2, AvgPrice20mChange: 5, TickerPrice1mChange: 15}
+	assert.NotEmpty(t, b.topicStrategy(string(notifier.AlertTopic), "", strict, "", fallback).Format(tick))
+	assert.Empty(t, b.topicStrategy(string(notifier.AlertTopic), "", loose, "", fallback).Format(tick))
+}
+
+func TestBuilderAlertStrategyStoresAlerts(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.events = eventbus.New(zap.NewNop())
+	defer b.app.events.Close()
+
+	repo := &domainMocks.AlertRepositoryMock{
+		CreateFunc: func(_ context.Context, _ domain.Alert) error { return nil },
+	}
+	b.app.events.Subscribe("alerts", storeAlert(repo, zap.NewNop()), eventbus.AlertFired)
+
+	thresholds := AlertThresholdsOptions{AvgPrice1mChange: 2, AvgPrice20mChange: 5, TickerPrice1mChange: 15}
+	startAt := time.Date(2025, 1, 1, 12, 0, 0, 500_000_000, time.UTC)
+	tick := &domain.Tick{StartAt: startAt, Avg: domain.TickAvg{Change1m: 3}, Data: map[domain.TickerName]*domain.Ticker{}}
+
+	require.NotEmpty(t, b.topicStrategy(string(notifier.AlertTopic), "telegram", thresholds, "", nil).Format(tick))
+	require.NotEmpty(t, b.topicStrategy(string(notifier.AlertTopic), "", thresholds, "", nil).Format(tick), "shadow alert")
+	b.app.events.Flush()
+
+	calls := repo.CreateCalls()
+	require.Len(t, calls, 1, "shadow alerts are never sent, so never stored")
+	alert := calls[0].Alert
+	assert.Equal(t, "test-service", alert.Exchange)
+	assert.Equal(t, "telegram", alert.Notifier)
+	assert.Equal(t, startAt.Truncate(time.Second), alert.TickKey)
+	assert.Equal(t, domain.AlertThresholds{AvgPrice1mChange: 2, AvgPrice20mChange: 5, TickerPrice1mChange: 15}, alert.Thresholds)
+}
+
+func TestBuilderMarketDataStrategy(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Priority.Symbols = []string{"BTCUSDT"}
+
+	assert.Equal(t, &notificationStrategies.MarketDataStrategy{Priority: []domain.TickerName{"BTCUSDT"}},
+		b.marketDataStrategy(marketDataFormatTicker))
+	assert.Equal(t, &notificationStrategies.MarketDataDigestStrategy{Priority: []domain.TickerName{"BTCUSDT"}},
+		b.marketDataStrategy(marketDataFormatDigest))
+}
+
+func TestBuilderTickChecks(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	assert.Empty(t, b.tickChecks())
+
+	b.app.options.TickChecks = TickChecksOptions{FetchDuration: time.Second, AvgChange: 10, Reject: []string{"avg_change"}}
+	checks := b.tickChecks()
+	require.Len(t, checks, 2)
+	assert.Equal(t, domain.CheckFetchDuration, checks[0].Name)
+	assert.Equal(t, domain.SeveritySuspect, checks[0].Severity)
+	assert.Equal(t, domain.CheckAvgChange, checks[1].Name)
+	assert.Equal(t, domain.SeverityReject, checks[1].Severity)
+}
+
+func TestOptions_ExchangeName(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(o *Options)
+		want   string
+	}{
+		{name: "service name of existing deployments", modify: func(o *Options) {}, want: "test-service"},
+		{name: "instance name", modify: func(o *Options) { o.Exchange.Binance.Name = "binance-main" }, want: "binance-main"},
+		{name: "binance default", modify: func(o *Options) { o.ServiceName = "" }, want: "binance-perp"},
+		{
+			name: "bybit default",
+			modify: func(o *Options) {
+				o.ServiceName = ""
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.Bybit.Enabled = true
+			},
+			want: "bybit-linear",
+		},
+		{
+			name: "okx default with instrument types",
+			modify: func(o *Options) {
+				o.ServiceName = ""
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.OKX.Enabled = true
+				o.Exchange.OKX.InstTypes = []string{"SWAP", "FUTURES"}
+			},
+			want: "okx-swap-futures",
+		},
+		{
+			name: "coinbase default",
+			modify: func(o *Options) {
+				o.ServiceName = ""
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.Coinbase.Enabled = true
+			},
+			want: "coinbase-perp",
+		},
+		{
+			name: "name of the disabled exchange is ignored",
+			modify: func(o *Options) {
+				o.Exchange.Bybit.Name = "bybit-main"
+			},
+			want: "test-service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newTestOptions(true)
+			tt.modify(opts)
+			assert.Equal(t, tt.want, opts.ExchangeName())
+		})
+	}
+}
+
+func TestBuilderExchangeName(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Exchange.Binance.Name = "binance-main"
+	b.WithExchange(context.Background())
+
+	assert.NoError(t, b.err)
+	assert.Equal(t, "binance-main", b.app.exchange.GetName())
+}
+
+func TestWebsocketOptions_Headers(t *testing.T) {
+	ws := WebsocketOptions{Headers: map[string]string{"user-agent": "importer-ws"}}
+	cfg := ws.config(map[string]string{"User-Agent": "importer", "X-Api-Key": "secret"})
+	assert.Equal(t, "importer-ws", cfg.Headers.Get("User-Agent"), "websocket headers override the exchange headers")
+	assert.Equal(t, "secret", cfg.Headers.Get("X-Api-Key"))
+
+	assert.Nil(t, WebsocketOptions{}.config(nil).Headers)
+}
+
 func TestMain(m *testing.M) {
 	// Clear os.Args to prevent interference with flag parsing.
 	os.Args = []string{os.Args[0]}
 	os.Exit(m.Run())
 }
+
+func TestApp_SetLogLevel(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Log.Level = "warn"
+	b.WithLogger(context.Background())
+	assert.NoError(t, b.err)
+
+	app := b.app
+	assert.Equal(t, "warn", app.LogLevel().String())
+
+	assert.NoError(t, app.SetLogLevel("debug"))
+	assert.Equal(t, "debug", app.LogLevel().String())
+	assert.Error(t, app.SetLogLevel("verbose"))
+}
+
+func TestOptions_ExchangeOptions(t *testing.T) {
+	t.Run("single exchange", func(t *testing.T) {
+		opts := newTestOptions(true)
+		opts.Manifest.Path = "/tmp/manifest.json"
+
+		scoped := opts.exchangeOptions("binance")
+		assert.Equal(t, "test-service", scoped.ServiceName)
+		assert.Equal(t, "test-service", scoped.ExchangeName())
+		assert.Equal(t, "/tmp/manifest.json", scoped.Manifest.Path)
+	})
+
+	t.Run("several exchanges", func(t *testing.T) {
+		opts := newTestOptions(true)
+		opts.Exchange.Bybit.Enabled = true
+		opts.Composite.Peers = []string{"bybit-linear", "okx-swap"}
+		opts.Manifest.Path = "/tmp/manifest.json"
+
+		binance := opts.exchangeOptions("binance")
+		assert.Equal(t, []string{"binance"}, binance.enabledExchanges())
+		assert.Equal(t, "test-service-binance-perp", binance.ServiceName)
+		assert.Equal(t, "binance-perp", binance.ExchangeName())
+		assert.Equal(t, []string{"bybit-linear", "okx-swap"}, binance.Composite.Peers)
+		assert.Equal(t, "/tmp/manifest.binance-perp.json", binance.Manifest.Path)
+
+		bybit := opts.exchangeOptions("bybit")
+		assert.Equal(t, []string{"bybit"}, bybit.enabledExchanges())
+		assert.Equal(t, "test-service-bybit-linear", bybit.ServiceName)
+		assert.Equal(t, "bybit-linear", bybit.ExchangeName())
+		assert.Equal(t, []string{"okx-swap"}, bybit.Composite.Peers, "the exchange leaves itself out of its peers")
+
+		assert.Equal(t, []string{"bybit-linear", "okx-swap"}, opts.Composite.Peers, "the options are left untouched")
+		assert.Equal(t, "test-service", opts.ServiceName)
+	})
+}
+
+func TestBuilder_BuildExchanges(t *testing.T) {
+	ctx := context.Background()
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Exchange.Bybit.Enabled = true
+	b.app.options.Exchange.Bybit.APIUrl = "https://dummy-api.bybit.com"
+	b.app.options.Exchange.Bybit.WSUrl = "wss://dummy-ws.bybit.com"
+
+	apps, err := b.WithLogger(ctx).BuildExchanges(ctx)
+	require.NoError(t, err)
+	require.Len(t, apps.Apps(), 2)
+
+	var names []string
+	for _, app := range apps.Apps() {
+		names = append(names, app.exchange.GetName())
+		assert.True(t, app.sharedTelemetry, "telemetry is shut down once by Apps")
+		assert.Equal(t, "test-service-"+app.exchange.GetName(), app.Manifest().Service)
+	}
+	assert.Equal(t, []string{"binance-perp", "bybit-linear"}, names)
+	assert.Nil(t, apps.api, "the API is disabled by default")
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	assert.NoError(t, apps.Stop(stopCtx))
+}
+
+func TestBuilder_WithPrometheusTelemetry(t *testing.T) {
+	ctx := context.Background()
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Telemetry.Prometheus.Enabled = true
+	b.app.options.Telemetry.Prometheus.Addr = "127.0.0.1:0"
+	b.app.options.Telemetry.Prometheus.Path = "/metrics"
+	b.app.options.Telemetry.Prometheus.Namespace = "importer"
+
+	b.WithLogger(ctx).WithTelemetry(ctx, BuildInfo{Revision: "abc1234", GoVersion: "go1.23"})
+	require.NoError(t, b.err)
+	provider, ok := b.app.telemetry.(*telemetry.PrometheusProvider)
+	require.True(t, ok)
+	defer provider.Shutdown()
+
+	provider.IncrementCounter("tick.fetch.errors", 1)
+	resp, err := http.Get("http://" + provider.Addr() + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `importer_tick_fetch_errors_total{env="test",exchange="",go_version="go1.23",repository="",revision="abc1234",service="test-service",symbols=""} 1`)
+}
+
+func TestBuilder_WithSeveralTelemetryProviders(t *testing.T) {
+	ctx := context.Background()
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Telemetry.Prometheus.Enabled = true
+	b.app.options.Telemetry.Prometheus.Addr = "127.0.0.1:0"
+	b.app.options.Telemetry.OTel.Enabled = true
+	b.app.options.Telemetry.OTel.Endpoint = "127.0.0.1:4317"
+	b.app.options.Telemetry.OTel.Insecure = true
+	b.app.options.Telemetry.OTel.EnabledTracing = true
+
+	b.WithLogger(ctx).WithTelemetry(ctx, BuildInfo{Revision: "abc1234"})
+	require.NoError(t, b.err)
+	provider, ok := b.app.telemetry.(*telemetry.MultiProvider)
+	require.True(t, ok, "the metrics and spans go to every enabled provider")
+	provider.Shutdown()
+}
+
+func TestBuilder_BuildExchangesWithAPI(t *testing.T) {
+	ctx := context.Background()
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.API.Enabled = true
+	b.app.options.API.Addr = "127.0.0.1:0"
+
+	b.build = NewBuildInfo("abc123", "")
+
+	apps, err := b.WithLogger(ctx).BuildExchanges(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, apps.api)
+
+	resp, err := http.Get("http://" + apps.api.Addr() + "/liquidations")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get("http://" + apps.api.Addr() + "/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var health struct {
+		Build BuildInfo `json:"build"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	assert.Equal(t, "abc123", health.Build.Revision, "the health route serves the build")
+
+	stopCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	assert.NoError(t, apps.Stop(stopCtx))
+}
@@ -0,0 +1,39 @@
+package bootstrap
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// BuildInfo identifies the build of a binary, the revision and build date are set by the linker
+type BuildInfo struct {
+	Revision  string `json:"revision"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// NewBuildInfo returns the build info of the running binary
+func NewBuildInfo(revision, buildDate string) BuildInfo {
+	return BuildInfo{
+		Revision:  revision,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// String formats the build info for the version command and the startup line
+func (b BuildInfo) String() string {
+	if b.BuildDate == "" {
+		return fmt.Sprintf("%s (%s)", b.Revision, b.GoVersion)
+	}
+	return fmt.Sprintf("%s (built %s, %s)", b.Revision, b.BuildDate, b.GoVersion)
+}
+
+// tags returns the telemetry tags of the build
+func (b BuildInfo) tags() []string {
+	tags := []string{"revision:" + b.Revision, "go_version:" + b.GoVersion}
+	if b.BuildDate != "" {
+		tags = append(tags, "build_date:"+b.BuildDate)
+	}
+	return tags
+}
@@ -0,0 +1,163 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	notificationStrategies "github.com/ayankousky/exchange-data-importer/internal/notifier/strategies"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+	"github.com/ayankousky/exchange-data-importer/internal/selfcheck"
+)
+
+// checkEventType is the event type of the test notifications of the clients taking a plain message
+const checkEventType = "SELF_CHECK"
+
+// repositoryProber is implemented by the repository factories able to check the database is writable
+type repositoryProber interface {
+	Probe(ctx context.Context, name string) error
+}
+
+// CheckJob verifies the exchanges, repositories and notifiers of the importer against the live services
+type CheckJob struct {
+	probes  []selfcheck.Probe
+	apps    []*App
+	timeout time.Duration
+	format  string
+}
+
+// ValidateCheckOptions checks the self-check options before any component is created
+func (b *Builder) ValidateCheckOptions() *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.app.options.ValidateCheck(); err != nil {
+		b.err = err
+	}
+	return b
+}
+
+// BuildCheck returns the self-check of every enabled exchange, wired as the importer would be, it requires WithLogger
+func (b *Builder) BuildCheck(ctx context.Context) (*CheckJob, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	job := &CheckJob{
+		timeout: b.app.options.Check.Timeout,
+		format:  b.app.options.Check.Format,
+	}
+	for _, kind := range b.app.options.enabledExchanges() {
+		exchangeBuilder := b.exchangeBuilder(kind).
+			WithExchange(ctx).
+			WithRepository(ctx).
+			WithNotifiers(ctx)
+		if exchangeBuilder.err != nil {
+			_ = job.Close(ctx)
+			return nil, fmt.Errorf("building %s check: %w", kind, exchangeBuilder.err)
+		}
+		job.apps = append(job.apps, exchangeBuilder.app)
+		job.probes = append(job.probes, exchangeBuilder.checkProbes()...)
+	}
+	if len(job.apps) == 0 {
+		return nil, fmt.Errorf("no exchange configured")
+	}
+	return job, nil
+}
+
+// checkProbes returns the probes of the exchange, its repository and every notifier client. The in-memory repository
+// stores nothing and isn't probed, shadow subscriptions send nothing and aren't either
+func (b *Builder) checkProbes() []selfcheck.Probe {
+	name := b.app.exchange.GetName()
+	probes := []selfcheck.Probe{
+		selfcheck.Tickers(b.app.exchange),
+		selfcheck.Liquidations(b.app.exchange, b.app.options.Check.Liquidations),
+	}
+
+	if prober, ok := b.app.repositoryFactory.(repositoryProber); ok {
+		probes = append(probes, selfcheck.Repository(name+"/"+b.repositoryKind, func(ctx context.Context) error {
+			return prober.Probe(ctx, name)
+		}))
+	}
+
+	// clients subscribed to several topics are shared, they get a single test event
+	checked := make(map[notify.Client]struct{})
+	for _, n := range b.app.notifiers {
+		if _, seen := checked[n.Client]; n.Shadow || seen {
+			continue
+		}
+		checked[n.Client] = struct{}{}
+		target := fmt.Sprintf("%s/%s:%s", name, n.Name, n.Topic)
+		probes = append(probes, selfcheck.Notification(target, n.Client, b.checkEvent(n.Name, time.Now().UTC())))
+	}
+	return probes
+}
+
+// checkEvent returns the test event sent with a notifier client, in the form the client expects. Alertmanager and
+// Grafana get a self-check alert already resolved, so it never pages anyone
+func (b *Builder) checkEvent(client string, at time.Time) notify.Event {
+	service, exchange := b.app.options.ServiceName, b.app.exchange.GetName()
+	alert := opsalert.Alert{
+		Status: opsalert.StatusResolved,
+		Labels: map[string]string{
+			opsalert.LabelAlertName: opsalert.NameSelfCheck,
+			opsalert.LabelSeverity:  opsalert.SeverityInfo,
+			"service":               service,
+			"exchange":              exchange,
+		},
+		Annotations: map[string]string{
+			opsalert.AnnotationSummary:     "Importer self-check",
+			opsalert.AnnotationDescription: "Test notification sent by exchange-importer check",
+		},
+		StartsAt: at,
+		EndsAt:   at,
+	}
+
+	switch client {
+	case "webhook":
+		return notificationStrategies.NewAlertmanagerStrategy(service, b.app.options.OpsAlerts.ExternalURL).Format(alert)[0]
+	case "grafana":
+		return (&notificationStrategies.GrafanaAnnotationStrategy{}).Format(alert)[0]
+	case "influx":
+		return notify.Event{
+			Time:      at,
+			EventType: checkEventType,
+			Data:      fmt.Sprintf("importer_self_check,exchange=%s ok=1i %d", exchange, at.UnixNano()),
+		}
+	default:
+		return notify.Event{
+			Time:      at,
+			EventType: checkEventType,
+			Data:      fmt.Sprintf("Self-check of %s (%s): test notification", service, exchange),
+		}
+	}
+}
+
+// Run runs every probe and returns the report
+func (j *CheckJob) Run(ctx context.Context) selfcheck.Report {
+	return selfcheck.Run(ctx, j.probes, j.timeout)
+}
+
+// Format returns the output format of CHECK_FORMAT
+func (j *CheckJob) Format() string {
+	return j.format
+}
+
+// Close stops the retry workers of the notifiers and releases the repositories
+func (j *CheckJob) Close(ctx context.Context) error {
+	var errs []error
+	for _, app := range j.apps {
+		if err := closeNotifiers(app.notifiers); err != nil {
+			errs = append(errs, err)
+		}
+		if closer, ok := app.repositoryFactory.(interface{ Close(context.Context) error }); ok {
+			if err := closer.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/composite"
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// compositePriceRepositoryFactory is implemented by the repository factories able to store composite prices
+type compositePriceRepositoryFactory interface {
+	GetCompositePriceRepository() (domain.CompositePriceRepository, error)
+}
+
+// WithComposite initializes the cross-exchange composite price when peers are configured,
+// it requires WithRepository
+func (b *Builder) WithComposite() *Builder {
+	if b.err != nil || len(b.app.options.Composite.Peers) == 0 {
+		return b
+	}
+
+	factory, ok := b.app.repositoryFactory.(compositePriceRepositoryFactory)
+	if !ok {
+		b.err = fmt.Errorf("repository %s does not support composite prices", b.repositoryKind)
+		return b
+	}
+	output, err := factory.GetCompositePriceRepository()
+	if err != nil {
+		b.err = fmt.Errorf("creating composite price repository: %w", err)
+		return b
+	}
+
+	peers := make(map[string]domain.TickRepository, len(b.app.options.Composite.Peers))
+	for _, peer := range b.app.options.Composite.Peers {
+		repo, err := b.app.repositoryFactory.GetTickRepository(peer)
+		if err != nil {
+			b.err = fmt.Errorf("creating tick repository of peer %s: %w", peer, err)
+			return b
+		}
+		peers[peer] = repo
+	}
+
+	b.app.compositor = composite.New(composite.Config{
+		Exchange:   b.app.options.ExchangeName(),
+		Peers:      peers,
+		Output:     output,
+		MaxAge:     b.app.options.Composite.MaxAge,
+		MinSources: b.app.options.Composite.MinSources,
+		Logger:     b.app.logger,
+	})
+	return b
+}
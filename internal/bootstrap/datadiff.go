@@ -0,0 +1,151 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/importer"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/mongo"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/postgres"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/sqlite"
+	"go.uber.org/zap"
+)
+
+// liquidationRepositories holds the liquidation repository type of every persistent repository kind,
+// so the options are checked against what the repositories implement
+var liquidationRepositories = map[string]domain.LiquidationRepository{
+	"mongo":    (*mongo.Liquidation)(nil),
+	"sqlite":   (*sqlite.LiquidationRepository)(nil),
+	"postgres": (*postgres.LiquidationRepository)(nil),
+}
+
+// readsLiquidationRanges reports whether the liquidations of the repository kind can be read back, its tick
+// repository always can be
+func readsLiquidationRanges(kind string) bool {
+	_, ok := liquidationRepositories[kind].(domain.LiquidationRangeRepository)
+	return ok
+}
+
+// dataDiffRepositoryKinds returns the repository kinds the comparison reads from, sorted
+func dataDiffRepositoryKinds() []string {
+	var kinds []string
+	for kind := range liquidationRepositories {
+		if readsLiquidationRanges(kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// DataDiffJob compares the ticks and liquidations of a range stored in two repositories
+type DataDiffJob struct {
+	differ    *importer.DataDiffer
+	factories []importer.RepositoryFactory
+	from, to  time.Time
+	logger    *zap.Logger
+}
+
+// ValidateDataDiffOptions checks the comparison options before any component is created
+func (b *Builder) ValidateDataDiffOptions() *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.app.options.ValidateDataDiff(time.Now()); err != nil {
+		b.err = err
+	}
+	return b
+}
+
+// BuildDataDiff returns the comparison job of the REPOSITORY_* repository against the DATADIFF_AGAINST_REPOSITORY_*
+// one, it requires WithLogger and WithRepository
+func (b *Builder) BuildDataDiff(ctx context.Context) (*DataDiffJob, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	from, to, err := b.app.options.DataDiff.parseRange(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	againstOps := instrument.NewRecorder(b.app.logger, b.app.options.DataDiff.Against.Repository.SlowOp)
+	against, againstKind, err := b.newRepositoryFactory(ctx, b.app.options.DataDiff.Against.Repository, againstOps)
+	if err != nil {
+		return nil, fmt.Errorf("creating repository to compare against: %w", err)
+	}
+	if against == nil {
+		return nil, fmt.Errorf("no repository to compare against configured")
+	}
+
+	name := b.app.options.ExchangeName()
+	againstName := name
+	if b.app.options.DataDiff.Against.Name != "" {
+		againstName = b.app.options.DataDiff.Against.Name
+	}
+
+	ticks, liquidations, err := dataDiffRepositories(b.app.repositoryFactory, b.repositoryKind, name)
+	if err != nil {
+		return nil, err
+	}
+	againstTicks, againstLiquidations, err := dataDiffRepositories(against, againstKind, againstName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataDiffJob{
+		differ: importer.NewDataDiffer(importer.DataDiffConfig{
+			Ticks:               ticks,
+			AgainstTicks:        againstTicks,
+			Liquidations:        liquidations,
+			AgainstLiquidations: againstLiquidations,
+			Window:              b.app.options.DataDiff.Window,
+			MaxReported:         b.app.options.DataDiff.MaxReported,
+			Logger:              b.app.logger,
+		}),
+		factories: []importer.RepositoryFactory{b.app.repositoryFactory, against},
+		from:      from,
+		to:        to,
+		logger:    b.app.logger,
+	}, nil
+}
+
+// dataDiffRepositories returns the tick and liquidation repositories of an exchange read by the comparison
+func dataDiffRepositories(factory importer.RepositoryFactory, kind, name string) (domain.TickRepository, domain.LiquidationRangeRepository, error) {
+	ticks, err := factory.GetTickRepository(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating %s tick repository: %w", kind, err)
+	}
+	liquidationRepo, err := factory.GetLiquidationRepository(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating %s liquidation repository: %w", kind, err)
+	}
+	liquidations, ok := liquidationRepo.(domain.LiquidationRangeRepository)
+	if !ok {
+		return nil, nil, fmt.Errorf("repository %s does not support reading liquidations back", kind)
+	}
+	return ticks, liquidations, nil
+}
+
+// Run compares the configured range
+func (j *DataDiffJob) Run(ctx context.Context) (importer.DataDiffResult, error) {
+	j.logger.Info("Comparing repositories", zap.Time("from", j.from), zap.Time("to", j.to))
+	return j.differ.Run(ctx, j.from, j.to)
+}
+
+// Close releases both repositories
+func (j *DataDiffJob) Close(ctx context.Context) error {
+	var errs []error
+	for _, factory := range j.factories {
+		if closer, ok := factory.(interface{ Close(context.Context) error }); ok {
+			errs = append(errs, closer.Close(ctx))
+		}
+	}
+	return errors.Join(errs...)
+}
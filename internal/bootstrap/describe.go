@@ -0,0 +1,26 @@
+package bootstrap
+
+import (
+	"github.com/ayankousky/exchange-data-importer/internal/dictionary"
+)
+
+// ValidateDescribeOptions checks the data dictionary options
+func (b *Builder) ValidateDescribeOptions() *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.app.options.ValidateDescribe(); err != nil {
+		b.err = err
+	}
+	return b
+}
+
+// BuildDictionary returns the data dictionary of the stored records with the configured PRECISION_* decimals
+// and the output format of DESCRIBE_FORMAT
+func (b *Builder) BuildDictionary() ([]dictionary.Record, string, error) {
+	if b.err != nil {
+		return nil, "", b.err
+	}
+	return dictionary.Describe(*b.app.options.Precision.precision()), b.app.options.Describe.Format, nil
+}
@@ -0,0 +1,78 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/importer"
+	"github.com/ayankousky/exchange-data-importer/internal/leaderboard"
+)
+
+// LeaderboardJob ranks the liquidations stored for the exchange
+type LeaderboardJob struct {
+	repo     domain.LiquidationLeaderboardRepository
+	factory  importer.RepositoryFactory
+	exchange string
+	config   leaderboard.Config
+	format   string
+}
+
+// ValidateLeaderboardOptions checks the leaderboard options before any component is created
+func (b *Builder) ValidateLeaderboardOptions() *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.app.options.ValidateLeaderboard(); err != nil {
+		b.err = err
+	}
+	return b
+}
+
+// BuildLeaderboard returns the leaderboard job of the REPOSITORY_* repository, it requires WithLogger and WithRepository
+func (b *Builder) BuildLeaderboard(_ context.Context) (*LeaderboardJob, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	name := b.app.options.ExchangeName()
+	liquidationRepo, err := b.app.repositoryFactory.GetLiquidationRepository(name)
+	if err != nil {
+		return nil, fmt.Errorf("creating %s liquidation repository: %w", b.repositoryKind, err)
+	}
+	repo, ok := liquidationRepo.(domain.LiquidationLeaderboardRepository)
+	if !ok {
+		return nil, fmt.Errorf("repository %s does not support ranking liquidations", b.repositoryKind)
+	}
+
+	return &LeaderboardJob{
+		repo:     repo,
+		factory:  b.app.repositoryFactory,
+		exchange: name,
+		config: leaderboard.Config{
+			Window: b.app.options.Leaderboard.Window,
+			Limit:  b.app.options.Leaderboard.Limit,
+		},
+		format: b.app.options.Leaderboard.Format,
+	}, nil
+}
+
+// Run builds the leaderboards as of now
+func (j *LeaderboardJob) Run(ctx context.Context) (leaderboard.Report, error) {
+	return leaderboard.Build(ctx, j.repo, j.exchange, j.config, time.Now())
+}
+
+// Format returns the output format of LEADERBOARD_FORMAT
+func (j *LeaderboardJob) Format() string {
+	return j.format
+}
+
+// Close releases the repository
+func (j *LeaderboardJob) Close(ctx context.Context) error {
+	if closer, ok := j.factory.(interface{ Close(context.Context) error }); ok {
+		return closer.Close(ctx)
+	}
+	return nil
+}
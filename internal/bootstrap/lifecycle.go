@@ -0,0 +1,139 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultStopTimeout bounds the shutdown of a single component
+const DefaultStopTimeout = 5 * time.Second
+
+// component is a part of the application with explicit start and stop steps.
+// Components start after their dependencies and stop before them.
+type component struct {
+	name      string
+	dependsOn []string
+	start     func(ctx context.Context) error // optional
+	stop      func(ctx context.Context) error // optional
+	timeout   time.Duration                   // stop timeout, DefaultStopTimeout when zero
+}
+
+// lifecycle starts and stops components in dependency order
+type lifecycle struct {
+	components []component
+	logger     *zap.Logger
+}
+
+func newLifecycle(logger *zap.Logger) *lifecycle {
+	return &lifecycle{logger: logger}
+}
+
+// add registers a component; dependencies may be registered later
+func (l *lifecycle) add(c component) {
+	l.components = append(l.components, c)
+}
+
+// startOrder returns components sorted so that every component comes after its dependencies.
+// Components without ordering constraints keep their registration order.
+func (l *lifecycle) startOrder() ([]component, error) {
+	byName := make(map[string]component, len(l.components))
+	for _, c := range l.components {
+		if _, ok := byName[c.name]; ok {
+			return nil, fmt.Errorf("component %q registered twice", c.name)
+		}
+		byName[c.name] = c
+	}
+
+	ordered := make([]component, 0, len(l.components))
+	state := make(map[string]int, len(l.components)) // 1 = visiting, 2 = done
+	var visit func(c component, path []string) error
+	visit = func(c component, path []string) error {
+		switch state[c.name] {
+		case 1:
+			return fmt.Errorf("dependency cycle: %v", append(path, c.name))
+		case 2:
+			return nil
+		}
+		state[c.name] = 1
+		for _, dep := range c.dependsOn {
+			d, ok := byName[dep]
+			if !ok {
+				return fmt.Errorf("component %q depends on unknown component %q", c.name, dep)
+			}
+			if err := visit(d, append(path, c.name)); err != nil {
+				return err
+			}
+		}
+		state[c.name] = 2
+		ordered = append(ordered, c)
+		return nil
+	}
+
+	for _, c := range l.components {
+		if err := visit(c, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// start starts components in dependency order, stopping the already started ones on failure
+func (l *lifecycle) start(ctx context.Context) error {
+	ordered, err := l.startOrder()
+	if err != nil {
+		return err
+	}
+
+	for n, c := range ordered {
+		if c.start == nil {
+			continue
+		}
+		if err := c.start(ctx); err != nil {
+			stopErr := l.stopComponents(context.Background(), ordered[:n])
+			return errors.Join(fmt.Errorf("starting %s: %w", c.name, err), stopErr)
+		}
+	}
+	return nil
+}
+
+// stop stops all components in reverse dependency order
+func (l *lifecycle) stop(ctx context.Context) error {
+	ordered, err := l.startOrder()
+	if err != nil {
+		return err
+	}
+	return l.stopComponents(ctx, ordered)
+}
+
+// stopComponents stops the given components (in start order) from last to first.
+// Every component gets its own timeout; a failing or slow component does not prevent the others from stopping.
+func (l *lifecycle) stopComponents(ctx context.Context, started []component) error {
+	var errs []error
+	for _, c := range slices.Backward(started) {
+		if c.stop == nil {
+			continue
+		}
+
+		timeout := c.timeout
+		if timeout <= 0 {
+			timeout = DefaultStopTimeout
+		}
+		stopCtx, cancel := context.WithTimeout(ctx, timeout)
+		startedAt := time.Now()
+		err := c.stop(stopCtx)
+		cancel()
+
+		if err != nil {
+			l.logger.Error("Failed to stop component", zap.String("component", c.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("stopping %s: %w", c.name, err))
+			continue
+		}
+		l.logger.Info("Component stopped", zap.String("component", c.name), zap.Duration("duration", time.Since(startedAt)))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,100 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLifecycle_Order(t *testing.T) {
+	var events []string
+	record := func(action, name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			events = append(events, action+":"+name)
+			return nil
+		}
+	}
+
+	l := newLifecycle(zap.NewNop())
+	// Registered out of order on purpose
+	for _, c := range []struct {
+		name string
+		deps []string
+	}{
+		{name: "importer", deps: []string{"eventbus", "repositories"}},
+		{name: "eventbus", deps: []string{"notifiers"}},
+		{name: "notifiers", deps: []string{"telemetry"}},
+		{name: "repositories", deps: []string{"telemetry"}},
+		{name: "telemetry"},
+	} {
+		l.add(component{name: c.name, dependsOn: c.deps, start: record("start", c.name), stop: record("stop", c.name)})
+	}
+
+	require.NoError(t, l.start(context.Background()))
+	require.NoError(t, l.stop(context.Background()))
+
+	assert.Equal(t, []string{
+		"start:telemetry", "start:notifiers", "start:eventbus", "start:repositories", "start:importer",
+		"stop:importer", "stop:repositories", "stop:eventbus", "stop:notifiers", "stop:telemetry",
+	}, events)
+}
+
+func TestLifecycle_InvalidDependencies(t *testing.T) {
+	l := newLifecycle(zap.NewNop())
+	l.add(component{name: "a", dependsOn: []string{"missing"}})
+	_, err := l.startOrder()
+	assert.ErrorContains(t, err, "unknown component")
+
+	l = newLifecycle(zap.NewNop())
+	l.add(component{name: "a", dependsOn: []string{"b"}})
+	l.add(component{name: "b", dependsOn: []string{"a"}})
+	_, err = l.startOrder()
+	assert.ErrorContains(t, err, "dependency cycle")
+}
+
+func TestLifecycle_StopContinuesAfterFailureAndTimeout(t *testing.T) {
+	var stopped []string
+	l := newLifecycle(zap.NewNop())
+	l.add(component{name: "telemetry", stop: func(ctx context.Context) error {
+		stopped = append(stopped, "telemetry")
+		return nil
+	}})
+	l.add(component{name: "slow", dependsOn: []string{"telemetry"}, timeout: 10 * time.Millisecond, stop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	l.add(component{name: "broken", dependsOn: []string{"slow"}, stop: func(ctx context.Context) error {
+		return errors.New("boom")
+	}})
+
+	err := l.stop(context.Background())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "stopping broken: boom")
+	assert.Equal(t, []string{"telemetry"}, stopped)
+}
+
+func TestLifecycle_StartFailureStopsStartedComponents(t *testing.T) {
+	var stopped []string
+	l := newLifecycle(zap.NewNop())
+	l.add(component{name: "first", stop: func(ctx context.Context) error {
+		stopped = append(stopped, "first")
+		return nil
+	}})
+	l.add(component{name: "second", dependsOn: []string{"first"}, start: func(ctx context.Context) error {
+		return errors.New("cannot start")
+	}, stop: func(ctx context.Context) error {
+		stopped = append(stopped, "second")
+		return nil
+	}})
+
+	err := l.start(context.Background())
+
+	assert.ErrorContains(t, err, "starting second: cannot start")
+	assert.Equal(t, []string{"first"}, stopped)
+}
@@ -0,0 +1,91 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Manifest records how a run of the importer is wired, so what was published where can be audited afterwards
+type Manifest struct {
+	Service    string `json:"service"`
+	Exchange   string `json:"exchange"`
+	Repository string `json:"repository"`
+	ImportMode string `json:"import_mode"`
+	BuildInfo
+	StartedAt time.Time              `json:"started_at"`
+	Notifiers []NotifierSubscription `json:"notifiers"`
+}
+
+// NotifierSubscription is the resolved wiring of a notifier client to a topic
+type NotifierSubscription struct {
+	Client   string `json:"client"`
+	Topic    string `json:"topic"`
+	Strategy string `json:"strategy"` // type of the strategy, e.g. strategies.MarketDataStrategy
+	Shadow   bool   `json:"shadow,omitempty"`
+}
+
+// notifierSubscriptions returns the wiring of the configured notifiers
+func notifierSubscriptions(notifiers []NotifierConfig) []NotifierSubscription {
+	subscriptions := make([]NotifierSubscription, 0, len(notifiers))
+	for _, n := range notifiers {
+		subscriptions = append(subscriptions, NotifierSubscription{
+			Client:   n.Name,
+			Topic:    n.Topic,
+			Strategy: strings.TrimPrefix(fmt.Sprintf("%T", n.Strategy), "*"),
+			Shadow:   n.Shadow,
+		})
+	}
+	return subscriptions
+}
+
+// Manifest returns the wiring of the run
+func (a *App) Manifest() Manifest {
+	return a.manifest
+}
+
+// writeManifest logs the notifier wiring and writes the run manifest to MANIFEST_PATH when it's set
+func (a *App) writeManifest() error {
+	a.manifest.StartedAt = time.Now().UTC()
+	for _, s := range a.manifest.Notifiers {
+		a.logger.Info("Notifier subscribed",
+			zap.String("client", s.Client),
+			zap.String("topic", s.Topic),
+			zap.String("strategy", s.Strategy),
+			zap.Bool("shadow", s.Shadow),
+		)
+	}
+
+	path := a.options.Manifest.Path
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(a.manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling run manifest: %w", err)
+	}
+
+	// write next to the target and rename, so a reader never sees a partial manifest
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating run manifest: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing run manifest: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing run manifest: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("renaming run manifest: %w", err)
+	}
+	a.logger.Info("Run manifest written", zap.String("path", path))
+	return nil
+}
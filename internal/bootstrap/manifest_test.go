@@ -0,0 +1,55 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApp_WriteManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	opts := newTestOptions(true)
+	opts.ImportMode = "full"
+	opts.Notify.Stdout.Topics = "TICK_INFO,MINUTE_BARS"
+	opts.Notify.Shadow.Topics = "ALERT_MARKET_STATE"
+	opts.Manifest.Path = path
+
+	ctx := context.Background()
+	b := NewBuilder()
+	b.app.options = opts
+	app, err := b.WithLogger(ctx).WithExchange(ctx).WithRepository(ctx).WithNotifiers(ctx).WithTelemetry(ctx, NewBuildInfo("abc123", "2025-01-01T00:00:00Z")).Build()
+	require.NoError(t, err)
+
+	wantNotifiers := []NotifierSubscription{
+		{Client: "stdout", Topic: "TICK_INFO", Strategy: "strategies.TickInfoStrategy"},
+		{Client: "stdout", Topic: "MINUTE_BARS", Strategy: "strategies.BarStrategy"},
+		{Client: "shadow", Topic: "ALERT_MARKET_STATE", Strategy: "strategies.AlertStrategy", Shadow: true},
+	}
+	assert.Equal(t, wantNotifiers, app.Manifest().Notifiers)
+
+	require.NoError(t, app.writeManifest())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var manifest Manifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, "test-service", manifest.Service)
+	assert.Equal(t, "test-service", manifest.Exchange)
+	assert.Equal(t, "memory", manifest.Repository)
+	assert.Equal(t, "full", manifest.ImportMode)
+	assert.Equal(t, "abc123", manifest.Revision)
+	assert.Equal(t, "2025-01-01T00:00:00Z", manifest.BuildDate)
+	assert.Equal(t, runtime.Version(), manifest.GoVersion)
+	assert.False(t, manifest.StartedAt.IsZero())
+	assert.Equal(t, wantNotifiers, manifest.Notifiers)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
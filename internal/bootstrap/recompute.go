@@ -0,0 +1,94 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/importer"
+	"go.uber.org/zap"
+)
+
+// recomputedTickRepositoryFactory is implemented by the persistent repository factories
+type recomputedTickRepositoryFactory interface {
+	GetRecomputedTickRepository(name string) (domain.RecomputedTickRepository, error)
+}
+
+// RecomputeJob recalculates the indicators of a range of stored ticks and writes versioned results
+type RecomputeJob struct {
+	recomputer *importer.Recomputer
+	factory    importer.RepositoryFactory
+	from, to   time.Time
+	logger     *zap.Logger
+}
+
+// ValidateRecomputeOptions checks the recomputation job options before any component is created
+func (b *Builder) ValidateRecomputeOptions() *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.app.options.ValidateRecompute(time.Now()); err != nil {
+		b.err = err
+	}
+	return b
+}
+
+// BuildRecompute returns the indicator recomputation job, it requires WithLogger and WithRepository
+func (b *Builder) BuildRecompute() (*RecomputeJob, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	from, to, err := b.app.options.Recompute.parseRange(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	factory, ok := b.app.repositoryFactory.(recomputedTickRepositoryFactory)
+	if !ok {
+		return nil, fmt.Errorf("repository %s does not support recomputation", b.repositoryKind)
+	}
+	ticks, err := b.app.repositoryFactory.GetTickRepository(b.app.options.ExchangeName())
+	if err != nil {
+		return nil, fmt.Errorf("creating tick repository: %w", err)
+	}
+	output, err := factory.GetRecomputedTickRepository(b.app.options.ExchangeName())
+	if err != nil {
+		return nil, fmt.Errorf("creating recomputed tick repository: %w", err)
+	}
+
+	return &RecomputeJob{
+		recomputer: importer.NewRecomputer(importer.RecomputeConfig{
+			Ticks:     ticks,
+			Output:    output,
+			Window:    b.app.options.Recompute.Window,
+			Precision: b.app.options.Precision.precision(),
+			Telemetry: b.app.telemetry,
+			Logger:    b.app.logger,
+		}),
+		factory: b.app.repositoryFactory,
+		from:    from,
+		to:      to,
+		logger:  b.app.logger,
+	}, nil
+}
+
+// Run recomputes the configured range
+func (j *RecomputeJob) Run(ctx context.Context) (importer.RecomputeResult, error) {
+	j.logger.Info("Recomputing indicators",
+		zap.Time("from", j.from),
+		zap.Time("to", j.to),
+		zap.Int("version", domain.IndicatorsVersion),
+	)
+	return j.recomputer.Run(ctx, j.from, j.to)
+}
+
+// Close releases the repositories
+func (j *RecomputeJob) Close(ctx context.Context) error {
+	if closer, ok := j.factory.(interface{ Close(context.Context) error }); ok {
+		return closer.Close(ctx)
+	}
+	return nil
+}
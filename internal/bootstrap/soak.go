@@ -0,0 +1,70 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/soak"
+)
+
+// SoakJob drives the configured notifiers with synthetic ticks and reports how they keep up
+type SoakJob struct {
+	runner    *soak.Runner
+	notifiers []NotifierConfig
+}
+
+// ValidateSoakOptions checks the notification load simulation options before any component is created
+func (b *Builder) ValidateSoakOptions() *Builder {
+	if b.err != nil {
+		return b
+	}
+
+	if err := b.app.options.ValidateSoak(); err != nil {
+		b.err = err
+	}
+	return b
+}
+
+// BuildSoak returns the notification load simulation, it requires WithLogger and WithNotifiers
+func (b *Builder) BuildSoak() (*SoakJob, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.app.notifiers) == 0 {
+		return nil, fmt.Errorf("no notifier could be initialized")
+	}
+
+	subscriptions := make([]soak.Subscription, 0, len(b.app.notifiers))
+	for _, n := range b.app.notifiers {
+		if n.Shadow {
+			continue // nothing is delivered, there's no load to measure
+		}
+		subscriptions = append(subscriptions, soak.Subscription{
+			Name:     n.Name,
+			Topic:    n.Topic,
+			Client:   n.Client,
+			Strategy: n.Strategy,
+		})
+	}
+
+	opts := b.app.options.Soak
+	return &SoakJob{
+		runner: soak.New(soak.Config{
+			Rate:           opts.Rate,
+			Symbols:        opts.Symbols,
+			Duration:       opts.Duration,
+			ReportInterval: opts.ReportInterval,
+		}, subscriptions, b.app.logger),
+		notifiers: b.app.notifiers,
+	}, nil
+}
+
+// Run publishes the synthetic ticks, progress reports are passed to onReport
+func (j *SoakJob) Run(ctx context.Context, onReport func(soak.Report)) soak.Report {
+	return j.runner.OnReport(onReport).Run(ctx)
+}
+
+// Close stops the retry workers of the notifiers
+func (j *SoakJob) Close() error {
+	return closeNotifiers(j.notifiers)
+}
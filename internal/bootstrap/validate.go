@@ -0,0 +1,882 @@
+package bootstrap
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/importer"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	bybitExchange "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/bybit"
+	okxExchange "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/okx"
+	"github.com/ayankousky/exchange-data-importer/internal/maintenance"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/symbolmeta"
+)
+
+// prometheusNamespace matches the valid prefixes of the Prometheus metric names
+var prometheusNamespace = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// OptionsError lists every problem found in the options, in a stable order
+type OptionsError struct {
+	Problems []string
+}
+
+// Error renders one problem per line
+func (e *OptionsError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// optionsValidator collects the problems of a validation pass
+type optionsValidator struct {
+	problems []string
+}
+
+func (v *optionsValidator) addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the options as a whole and reports all problems at once,
+// so a misconfiguration is visible at startup instead of being ignored deep in the builder
+func (o *Options) Validate() error {
+	v := &optionsValidator{}
+	o.validateExchange(v)
+	o.validateRepository(v)
+	o.validateNotify(v)
+	o.validateThresholds(v)
+	o.validatePrecision(v)
+	o.validateImportMode(v)
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &OptionsError{Problems: v.problems}
+}
+
+// ValidateCheck checks the options used by the self-check, the importer options and the check durations
+func (o *Options) ValidateCheck() error {
+	v := &optionsValidator{}
+	o.validateExchange(v)
+	o.validateRepository(v)
+	o.validateNotify(v)
+	if o.Check.Timeout <= 0 {
+		v.addf("CHECK_TIMEOUT: must be positive, got %s", o.Check.Timeout)
+	}
+	if o.Check.Liquidations <= 0 {
+		v.addf("CHECK_LIQUIDATIONS: must be positive, got %s", o.Check.Liquidations)
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &OptionsError{Problems: v.problems}
+}
+
+// ValidateRecompute checks the options used by the indicator recomputation job,
+// it needs a persistent repository and a range but no exchange
+func (o *Options) ValidateRecompute(now time.Time) error {
+	v := &optionsValidator{}
+	if o.ServiceName == "" {
+		v.addf("SERVICE_NAME: required to locate the stored ticks")
+	}
+	if !o.Repository.Mongo.Enabled && !o.Repository.Sqlite.Enabled {
+		v.addf("REPOSITORY_*_ENABLED: no repository enabled, enable mongo or sqlite")
+	}
+	o.validateRepository(v)
+	if _, _, err := o.Recompute.parseRange(now); err != nil {
+		v.addf("%s", err)
+	}
+	if o.Recompute.Window <= 0 {
+		v.addf("RECOMPUTE_WINDOW: must be positive, got %s", o.Recompute.Window)
+	}
+	o.validatePrecision(v)
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &OptionsError{Problems: v.problems}
+}
+
+// ValidateDataDiff checks the options used by the repository comparison tool,
+// it needs two persistent repositories and a range but no exchange
+func (o *Options) ValidateDataDiff(now time.Time) error {
+	v := &optionsValidator{}
+	if o.ServiceName == "" {
+		v.addf("SERVICE_NAME: required to locate the stored data")
+	}
+	validateDataDiffRepository(v, "REPOSITORY", "no repository enabled", o.Repository)
+	o.validateRepository(v)
+	against := o.DataDiff.Against.Repository
+	validateDataDiffRepository(v, "DATADIFF_AGAINST_REPOSITORY", "no repository to compare against enabled", against)
+	validateRepositoryOptions(v, "DATADIFF_AGAINST_REPOSITORY", against)
+	if _, _, err := o.DataDiff.parseRange(now); err != nil {
+		v.addf("%s", err)
+	}
+	if o.DataDiff.Window <= 0 {
+		v.addf("DATADIFF_WINDOW: must be positive, got %s", o.DataDiff.Window)
+	}
+	if o.DataDiff.MaxReported <= 0 {
+		v.addf("DATADIFF_MAX_REPORTED: must be positive, got %d", o.DataDiff.MaxReported)
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &OptionsError{Problems: v.problems}
+}
+
+// validateDataDiffRepository checks a repository of the comparison is enabled and reads its liquidations back
+func validateDataDiffRepository(v *optionsValidator, prefix, missing string, opts RepositoryOptions) {
+	kinds := dataDiffRepositoryKinds()
+	enabled := opts.enabledRepositories()
+	if len(enabled) == 0 {
+		v.addf("%s_*_ENABLED: %s, enable %s", prefix, missing, strings.Join(kinds, ", "))
+	}
+	for _, kind := range enabled {
+		if !slices.Contains(kinds, kind) {
+			v.addf("%s_%s_ENABLED: the %s repository can't read liquidations back, enable %s",
+				prefix, strings.ToUpper(kind), kind, strings.Join(kinds, ", "))
+		}
+	}
+}
+
+// ValidateLeaderboard checks the options used by the liquidation leaderboards,
+// they read a persistent repository but need no exchange
+func (o *Options) ValidateLeaderboard() error {
+	v := &optionsValidator{}
+	if o.ServiceName == "" {
+		v.addf("SERVICE_NAME: required to locate the stored data")
+	}
+	if !o.Repository.Mongo.Enabled && !o.Repository.Sqlite.Enabled {
+		v.addf("REPOSITORY_*_ENABLED: no repository enabled, enable mongo or sqlite")
+	}
+	o.validateRepository(v)
+	if o.Leaderboard.Window <= 0 {
+		v.addf("LEADERBOARD_WINDOW: must be positive, got %s", o.Leaderboard.Window)
+	}
+	if o.Leaderboard.Limit <= 0 {
+		v.addf("LEADERBOARD_LIMIT: must be positive, got %d", o.Leaderboard.Limit)
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &OptionsError{Problems: v.problems}
+}
+
+// ValidateSoak checks the options used by the notification load simulation,
+// it needs at least one notifier but no exchange or repository
+func (o *Options) ValidateSoak() error {
+	v := &optionsValidator{}
+	notify := o.Notify
+	if notify.Redis.Topics == "" && notify.Telegram.Topics == "" && notify.Stdout.Topics == "" &&
+		notify.Influx.Topics == "" && notify.Webhook.Topics == "" && notify.Grafana.Topics == "" && notify.File.Topics == "" &&
+		notify.WS.Topics == "" {
+		v.addf("NOTIFY_*_TOPICS: no notifier configured, set the topics of at least one")
+	}
+	o.validateNotify(v)
+	if o.Soak.Rate <= 0 {
+		v.addf("SOAK_RATE: must be positive, got %g", o.Soak.Rate)
+	}
+	if o.Soak.Symbols <= 0 {
+		v.addf("SOAK_SYMBOLS: must be positive, got %d", o.Soak.Symbols)
+	}
+	if o.Soak.Duration <= 0 {
+		v.addf("SOAK_DURATION: must be positive, got %s", o.Soak.Duration)
+	}
+	if o.Soak.ReportInterval < 0 {
+		v.addf("SOAK_REPORT_INTERVAL: must not be negative, got %s", o.Soak.ReportInterval)
+	}
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &OptionsError{Problems: v.problems}
+}
+
+// ValidateDescribe checks the options of the data dictionary, only the precision is described
+func (o *Options) ValidateDescribe() error {
+	v := &optionsValidator{}
+	o.validatePrecision(v)
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &OptionsError{Problems: v.problems}
+}
+
+// servesFundingRates reports whether the exchange kind implements exchanges.FundingRateFetcher
+func servesFundingRates(kind string) bool {
+	return kind == "binance" || kind == "bybit" || kind == "okx"
+}
+
+// servesOpenInterest reports whether the exchange kind implements exchanges.OpenInterestFetcher
+func servesOpenInterest(kind string) bool {
+	return kind == "binance" || kind == "okx"
+}
+
+// enabledExchanges returns the names of the enabled exchanges
+func (o *Options) enabledExchanges() []string {
+	var enabled []string
+	if o.Exchange.Binance.Enabled {
+		enabled = append(enabled, "binance")
+	}
+	if o.Exchange.Bybit.Enabled {
+		enabled = append(enabled, "bybit")
+	}
+	if o.Exchange.OKX.Enabled {
+		enabled = append(enabled, "okx")
+	}
+	if o.Exchange.Coinbase.Enabled {
+		enabled = append(enabled, "coinbase")
+	}
+	return enabled
+}
+
+// nonBinanceExchanges returns the names of the enabled exchanges other than binance, which the binance-only options
+// would not apply to
+func (o *Options) nonBinanceExchanges() []string {
+	return slices.DeleteFunc(o.enabledExchanges(), func(kind string) bool { return kind == "binance" })
+}
+
+func (o *Options) validateExchange(v *optionsValidator) {
+	enabled := o.enabledExchanges()
+	if len(enabled) == 0 {
+		v.addf("EXCHANGE_*_ENABLED: no exchange enabled, enable at least one of binance, bybit, okx, coinbase")
+	}
+	if len(enabled) > 1 {
+		// every exchange keys its collections with its name, two importers must not share them
+		names := make(map[string]string, len(enabled))
+		for _, kind := range enabled {
+			name := o.exchangeOptions(kind).ExchangeName()
+			if other, ok := names[name]; ok {
+				v.addf("EXCHANGE_*_NAME: %s and %s are both named %q, the enabled exchanges must have distinct names", other, kind, name)
+			}
+			names[name] = kind
+		}
+	}
+
+	validCategories := []string{bybitExchange.CategoryLinear, bybitExchange.CategoryInverse, bybitExchange.CategoryOption}
+	for _, category := range o.Exchange.Bybit.Categories {
+		if !slices.Contains(validCategories, category) {
+			v.addf("EXCHANGE_BYBIT_CATEGORIES: unknown category %q (valid: %s)", category, strings.Join(validCategories, ", "))
+		}
+	}
+
+	validInstTypes := []string{okxExchange.InstTypeSwap, okxExchange.InstTypeFutures, okxExchange.InstTypeOption}
+	for _, instType := range o.Exchange.OKX.InstTypes {
+		if !slices.Contains(validInstTypes, instType) {
+			v.addf("EXCHANGE_OKX_INST_TYPES: unknown instrument type %q (valid: %s)", instType, strings.Join(validInstTypes, ", "))
+		}
+	}
+
+	validateEventAtFallback(v, "EXCHANGE_BINANCE_EVENT_AT_FALLBACK", o.Exchange.Binance.EventAtFallback)
+	validateEventAtFallback(v, "EXCHANGE_BYBIT_EVENT_AT_FALLBACK", o.Exchange.Bybit.EventAtFallback)
+	validateEventAtFallback(v, "EXCHANGE_OKX_EVENT_AT_FALLBACK", o.Exchange.OKX.EventAtFallback)
+	validateEventAtFallback(v, "EXCHANGE_COINBASE_EVENT_AT_FALLBACK", o.Exchange.Coinbase.EventAtFallback)
+	validateMaintenance(v, "EXCHANGE_BINANCE_MAINTENANCE", o.Exchange.Binance.Maintenance)
+	validateMaintenance(v, "EXCHANGE_BYBIT_MAINTENANCE", o.Exchange.Bybit.Maintenance)
+	validateMaintenance(v, "EXCHANGE_OKX_MAINTENANCE", o.Exchange.OKX.Maintenance)
+	validateMaintenance(v, "EXCHANGE_COINBASE_MAINTENANCE", o.Exchange.Coinbase.Maintenance)
+	validateTickOffset(v, "EXCHANGE_BINANCE_TICK_OFFSET", o.Exchange.Binance.TickOffset)
+	validateTickOffset(v, "EXCHANGE_BYBIT_TICK_OFFSET", o.Exchange.Bybit.TickOffset)
+	validateTickOffset(v, "EXCHANGE_OKX_TICK_OFFSET", o.Exchange.OKX.TickOffset)
+	validateTickOffset(v, "EXCHANGE_COINBASE_TICK_OFFSET", o.Exchange.Coinbase.TickOffset)
+
+	validateWebsocket(v, "EXCHANGE_BINANCE_WS", o.Exchange.Binance.WS)
+	validateWebsocket(v, "EXCHANGE_BYBIT_WS", o.Exchange.Bybit.WS)
+	validateWebsocket(v, "EXCHANGE_OKX_WS", o.Exchange.OKX.WS)
+}
+
+// validateTickOffset reports an offset outside the tick interval
+func validateTickOffset(v *optionsValidator, name string, offset time.Duration) {
+	if offset < 0 || offset >= importer.TickInterval {
+		v.addf("%s: must be at least 0 and below the tick interval %s, got %s", name, importer.TickInterval, offset)
+	}
+}
+
+func validateMaintenance(v *optionsValidator, name string, windows []string) {
+	if _, err := maintenance.Parse(windows); err != nil {
+		v.addf("%s: %s", name, err)
+	}
+}
+
+func validateEventAtFallback(v *optionsValidator, name, fallback string) {
+	if fallback != "" && !slices.Contains(exchanges.EventAtFallbacks, exchanges.EventAtFallback(fallback)) {
+		v.addf("%s: unknown fallback %q (valid: exchange, received, reject)", name, fallback)
+	}
+}
+
+func validateWebsocket(v *optionsValidator, prefix string, ws WebsocketOptions) {
+	if ws.HandshakeTimeout < 0 {
+		v.addf("%s_HANDSHAKE_TIMEOUT: must not be negative, got %s", prefix, ws.HandshakeTimeout)
+	}
+	if ws.ReadBufferSize < 0 {
+		v.addf("%s_READ_BUFFER_SIZE: must not be negative, got %d", prefix, ws.ReadBufferSize)
+	}
+	if ws.PingInterval < 0 {
+		v.addf("%s_PING_INTERVAL: must not be negative, got %s", prefix, ws.PingInterval)
+	}
+}
+
+func (o *Options) validateImportMode(v *optionsValidator) {
+	if order := importer.PublishOrder(o.PublishOrder); order != "" && !slices.Contains(importer.PublishOrders, order) {
+		v.addf("PUBLISH_ORDER: unknown order %q (valid: store-first, notify-first)", o.PublishOrder)
+	}
+
+	mode := importer.Mode(o.ImportMode)
+	if mode != "" && !slices.Contains(importer.Modes, mode) {
+		v.addf("IMPORT_MODE: unknown mode %q (valid: full, tickers, liquidations)", o.ImportMode)
+		return
+	}
+	if !mode.RunsLiquidations() && o.Liquidations.Backfill != 0 {
+		v.addf("LIQUIDATIONS_BACKFILL: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if !mode.RunsLiquidations() && o.Liquidations.ArchiveRaw {
+		v.addf("LIQUIDATIONS_ARCHIVE_RAW: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if !mode.RunsLiquidations() && o.Liquidations.Coinglass.Enabled {
+		v.addf("LIQUIDATIONS_COINGLASS_ENABLED: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if mode.RunsTickers() {
+		return
+	}
+
+	// these features are built from ticks
+	if len(o.HighRes.Symbols) > 0 {
+		v.addf("HIGH_RES_SYMBOLS: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if len(o.Trades.Symbols) > 0 {
+		v.addf("TRADES_SYMBOLS: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if len(o.Priority.Symbols) > 0 {
+		v.addf("PRIORITY_SYMBOLS: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if o.Bars.Enabled {
+		v.addf("BARS_ENABLED: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if o.Funding.Interval != 0 {
+		v.addf("FUNDING_INTERVAL: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if o.OpenInterest.Interval != 0 {
+		v.addf("OPEN_INTEREST_INTERVAL: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if o.SymbolMeta.File != "" {
+		v.addf("SYMBOL_META_FILE: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if o.Symbols.Registry {
+		v.addf("SYMBOLS_REGISTRY: has no effect when IMPORT_MODE is %s", mode)
+	}
+	if len(o.Composite.Peers) > 0 {
+		v.addf("COMPOSITE_PEERS: has no effect when IMPORT_MODE is %s", mode)
+	}
+}
+
+func (o *Options) validateTickChecks(v *optionsValidator) {
+	checks := o.TickChecks
+	if checks.FetchDuration < 0 {
+		v.addf("TICK_CHECKS_FETCH_DURATION: must not be negative, got %s", checks.FetchDuration)
+	}
+	if checks.TickersDrop < 0 || checks.TickersDrop >= 1 {
+		v.addf("TICK_CHECKS_TICKERS_DROP: must be between 0 and 1, got %g", checks.TickersDrop)
+	}
+	if checks.AvgChange < 0 {
+		v.addf("TICK_CHECKS_AVG_CHANGE: must not be negative, got %g", checks.AvgChange)
+	}
+	for _, name := range checks.Reject {
+		if !slices.Contains(tickCheckNames, name) {
+			v.addf("TICK_CHECKS_REJECT: unknown check %q (valid: %s)", name, strings.Join(tickCheckNames, ", "))
+		}
+	}
+}
+
+func (o *Options) validateRepository(v *optionsValidator) {
+	validateRepositoryOptions(v, "REPOSITORY", o.Repository)
+}
+
+// enabledRepositories returns the names of the enabled persistent repositories
+func (o RepositoryOptions) enabledRepositories() []string {
+	var enabled []string
+	if o.Mongo.Enabled {
+		enabled = append(enabled, "mongo")
+	}
+	if o.Sqlite.Enabled {
+		enabled = append(enabled, "sqlite")
+	}
+	if o.Postgres.Enabled {
+		enabled = append(enabled, "postgres")
+	}
+	return enabled
+}
+
+func validateRepositoryOptions(v *optionsValidator, prefix string, opts RepositoryOptions) {
+	mongoOpts, sqliteOpts, postgresOpts := opts.Mongo, opts.Sqlite, opts.Postgres
+	if enabled := opts.enabledRepositories(); len(enabled) > 1 {
+		v.addf("%s_*_ENABLED: only one repository can be enabled, got %s", prefix, strings.Join(enabled, ", "))
+	}
+	if mongoOpts.Enabled && mongoOpts.URL == "" {
+		v.addf("%s_MONGO_URL: required when the mongo repository is enabled", prefix)
+	}
+	if sqliteOpts.Enabled && sqliteOpts.Path == "" {
+		v.addf("%s_SQLITE_PATH: required when the sqlite repository is enabled", prefix)
+	}
+	if postgresOpts.Enabled && postgresOpts.URL == "" {
+		v.addf("%s_POSTGRES_URL: required when the postgres repository is enabled", prefix)
+	}
+	validateCodec(v, prefix+"_CODEC", opts.Codec)
+	if postgresOpts.Enabled && opts.Codec != "" && opts.Codec != codec.JSON.Name() {
+		v.addf("%s_CODEC: postgres stores JSONB documents, only json is supported, got %q", prefix, opts.Codec)
+	}
+	if opts.SlowOp < 0 {
+		v.addf("%s_SLOW_OP: must not be negative, got %s", prefix, opts.SlowOp)
+	}
+}
+
+// validateCodec checks the codec name is empty, for the default of the sink, or one of the codecs
+func validateCodec(v *optionsValidator, name, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := codec.Lookup(value); err != nil {
+		v.addf("%s: must be one of %s, got %q", name, strings.Join(codec.Names(), ", "), value)
+	}
+}
+
+// validateMarketDataFormat checks the MARKET_DATA format is empty, for the format of every notifier, or a known one
+func validateMarketDataFormat(v *optionsValidator, name, value string) {
+	if value != "" && value != marketDataFormatTicker && value != marketDataFormatDigest {
+		v.addf("%s: must be one of %s, %s, got %q", name, marketDataFormatTicker, marketDataFormatDigest, value)
+	}
+}
+
+func (o *Options) validateNotify(v *optionsValidator) {
+	notify := o.Notify
+
+	validateMarketDataFormat(v, "NOTIFY_MARKET_DATA_FORMAT", notify.MarketDataFormat)
+	validateMarketDataFormat(v, "NOTIFY_REDIS_MARKET_DATA_FORMAT", notify.Redis.MarketDataFormat)
+	validateMarketDataFormat(v, "NOTIFY_SHADOW_MARKET_DATA_FORMAT", notify.Shadow.MarketDataFormat)
+	validateMarketDataFormat(v, "NOTIFY_FILE_MARKET_DATA_FORMAT", notify.File.MarketDataFormat)
+	validateMarketDataFormat(v, "NOTIFY_WS_MARKET_DATA_FORMAT", notify.WS.MarketDataFormat)
+	validateMarketDataFormat(v, "NOTIFY_EMBEDDED_MARKET_DATA_FORMAT", notify.Embedded.MarketDataFormat)
+
+	if notify.Breaker.Threshold < 0 {
+		v.addf("NOTIFY_BREAKER_THRESHOLD: must not be negative, got %d", notify.Breaker.Threshold)
+	}
+	if notify.Breaker.Threshold > 0 && notify.Breaker.Cooldown <= 0 {
+		v.addf("NOTIFY_BREAKER_COOLDOWN: must be positive, got %s", notify.Breaker.Cooldown)
+	}
+
+	validateTopics(v, "NOTIFY_REDIS_TOPICS", notify.Redis.Topics)
+	if notify.Redis.Topics != "" {
+		switch notify.Redis.Mode {
+		case infrastructure.RedisModeCluster:
+			if len(notify.Redis.Addrs) == 0 {
+				v.addf("NOTIFY_REDIS_ADDRS: required in cluster mode")
+			}
+		case infrastructure.RedisModeSentinel:
+			if len(notify.Redis.Addrs) == 0 {
+				v.addf("NOTIFY_REDIS_ADDRS: required in sentinel mode")
+			}
+			if notify.Redis.MasterName == "" {
+				v.addf("NOTIFY_REDIS_MASTER_NAME: required in sentinel mode")
+			}
+		default:
+			if notify.Redis.URL == "" {
+				v.addf("NOTIFY_REDIS_URL: required when redis topics are set")
+			}
+		}
+	}
+	if notify.Redis.TLSCAFile != "" {
+		if _, err := os.Stat(notify.Redis.TLSCAFile); err != nil {
+			v.addf("NOTIFY_REDIS_TLS_CA_FILE: %s", err)
+		}
+	}
+	if notify.Redis.PoolSize < 0 {
+		v.addf("NOTIFY_REDIS_POOL_SIZE: must not be negative, got %d", notify.Redis.PoolSize)
+	}
+	if notify.Redis.MinIdleConns < 0 || (notify.Redis.PoolSize > 0 && notify.Redis.MinIdleConns > notify.Redis.PoolSize) {
+		v.addf("NOTIFY_REDIS_MIN_IDLE_CONNS: must be between 0 and NOTIFY_REDIS_POOL_SIZE (%d), got %d", notify.Redis.PoolSize, notify.Redis.MinIdleConns)
+	}
+	if notify.Redis.Pipeline < 0 {
+		v.addf("NOTIFY_REDIS_PIPELINE: must not be negative, got %d", notify.Redis.Pipeline)
+	}
+	validateAlertThresholds(v, "NOTIFY_REDIS_ALERT", notify.Redis.Alert)
+
+	validateTopics(v, "NOTIFY_TELEGRAM_TOPICS", notify.Telegram.Topics)
+	if notify.Telegram.Topics != "" {
+		if notify.Telegram.BotToken == "" {
+			v.addf("NOTIFY_TELEGRAM_BOT_TOKEN: required when telegram topics are set")
+		}
+		if notify.Telegram.ChatID == "" {
+			v.addf("NOTIFY_TELEGRAM_CHAT_ID: required when telegram topics are set")
+		}
+	}
+	if notify.Telegram.Interval < 0 {
+		v.addf("NOTIFY_TELEGRAM_INTERVAL: must not be negative, got %d", notify.Telegram.Interval)
+	}
+	validateAlertThresholds(v, "NOTIFY_TELEGRAM_ALERT", notify.Telegram.Alert)
+
+	validateTopics(v, "NOTIFY_STDOUT_TOPICS", notify.Stdout.Topics)
+	validateAlertThresholds(v, "NOTIFY_STDOUT_ALERT", notify.Stdout.Alert)
+
+	validateTopics(v, "NOTIFY_INFLUX_TOPICS", notify.Influx.Topics)
+	if notify.Influx.Topics != "" {
+		if notify.Influx.URL == "" {
+			v.addf("NOTIFY_INFLUX_URL: required when influx topics are set")
+		}
+		if notify.Influx.Org == "" {
+			v.addf("NOTIFY_INFLUX_ORG: required when influx topics are set")
+		}
+		if notify.Influx.Bucket == "" {
+			v.addf("NOTIFY_INFLUX_BUCKET: required when influx topics are set")
+		}
+	}
+
+	validateTopics(v, "NOTIFY_WEBHOOK_TOPICS", notify.Webhook.Topics)
+	if notify.Webhook.Topics != "" && notify.Webhook.URL == "" {
+		v.addf("NOTIFY_WEBHOOK_URL: required when webhook topics are set")
+	}
+
+	validateTopics(v, "NOTIFY_GRAFANA_TOPICS", notify.Grafana.Topics)
+	if notify.Grafana.Topics != "" && notify.Grafana.URL == "" {
+		v.addf("NOTIFY_GRAFANA_URL: required when grafana topics are set")
+	}
+
+	validateTopics(v, "NOTIFY_SHADOW_TOPICS", notify.Shadow.Topics)
+	validateAlertThresholds(v, "NOTIFY_SHADOW_ALERT", notify.Shadow.Alert)
+
+	ws := notify.WS
+	validateTopics(v, "NOTIFY_WS_TOPICS", ws.Topics)
+	if ws.Topics != "" {
+		if ws.Addr == "" {
+			v.addf("NOTIFY_WS_ADDR: required when ws topics are set")
+		}
+		if !strings.HasPrefix(ws.Path, "/") {
+			v.addf("NOTIFY_WS_PATH: must start with /, got %q", ws.Path)
+		}
+		if ws.Buffer <= 0 {
+			v.addf("NOTIFY_WS_BUFFER: must be positive, got %d", ws.Buffer)
+		}
+		if ws.KeyframeInterval <= 0 {
+			v.addf("NOTIFY_WS_KEYFRAME_INTERVAL: must be positive, got %s", ws.KeyframeInterval)
+		}
+	}
+	validateAlertThresholds(v, "NOTIFY_WS_ALERT", ws.Alert)
+
+	file := notify.File
+	validateTopics(v, "NOTIFY_FILE_TOPICS", file.Topics)
+	if file.Topics != "" {
+		if file.Dir == "" {
+			v.addf("NOTIFY_FILE_DIR: required when file topics are set")
+		}
+		if file.MaxSizeMB <= 0 {
+			v.addf("NOTIFY_FILE_MAX_SIZE_MB: must be positive, got %d", file.MaxSizeMB)
+		}
+	}
+	if file.Format != "" && file.Format != "csv" {
+		v.addf("NOTIFY_FILE_FORMAT: only csv is supported, got %q", file.Format)
+	}
+	if file.MaxAge < 0 {
+		v.addf("NOTIFY_FILE_MAX_AGE: must not be negative, got %s", file.MaxAge)
+	}
+	if file.MaxFiles < 0 {
+		v.addf("NOTIFY_FILE_MAX_FILES: must not be negative, got %d", file.MaxFiles)
+	}
+	if file.Retention < 0 {
+		v.addf("NOTIFY_FILE_RETENTION: must not be negative, got %s", file.Retention)
+	}
+	validateCodec(v, "NOTIFY_FILE_CODEC", file.Codec)
+	validateAlertThresholds(v, "NOTIFY_FILE_ALERT", file.Alert)
+}
+
+// validateAlertThresholds reports negative market alert thresholds of a notifier
+func validateAlertThresholds(v *optionsValidator, prefix string, thresholds AlertThresholdsOptions) {
+	if thresholds.AvgPrice1mChange < 0 {
+		v.addf("%s_AVG_PRICE_1M_CHANGE: must not be negative, got %g", prefix, thresholds.AvgPrice1mChange)
+	}
+	if thresholds.AvgPrice20mChange < 0 {
+		v.addf("%s_AVG_PRICE_20M_CHANGE: must not be negative, got %g", prefix, thresholds.AvgPrice20mChange)
+	}
+	if thresholds.TickerPrice1mChange < 0 {
+		v.addf("%s_TICKER_PRICE_1M_CHANGE: must not be negative, got %g", prefix, thresholds.TickerPrice1mChange)
+	}
+	if thresholds.CategoryPrice1mChange < 0 {
+		v.addf("%s_CATEGORY_PRICE_1M_CHANGE: must not be negative, got %g", prefix, thresholds.CategoryPrice1mChange)
+	}
+	if thresholds.Persistence < 0 || thresholds.Persistence > domain.MaxTickHistory {
+		v.addf("%s_PERSISTENCE: must be between 0 and %d, got %d", prefix, domain.MaxTickHistory, thresholds.Persistence)
+	}
+	for _, pattern := range slices.Sorted(maps.Keys(thresholds.TickerPrice1mChangeBySymbol)) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			v.addf("%s_TICKER_PRICE_1M_CHANGE_BY_SYMBOL: invalid pattern %q", prefix, pattern)
+		}
+		if threshold := thresholds.TickerPrice1mChangeBySymbol[pattern]; threshold < 0 {
+			v.addf("%s_TICKER_PRICE_1M_CHANGE_BY_SYMBOL: must not be negative, got %g for %s", prefix, threshold, pattern)
+		}
+	}
+}
+
+// validateTopics reports every entry of a comma-separated topics list that isn't a notifier.Topic
+func validateTopics(v *optionsValidator, name, topics string) {
+	for _, topic := range strings.Split(topics, ",") {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+		if err := notifier.Topic(topic).Validate(); err != nil {
+			v.addf("%s: unknown topic %q (valid: %s)", name, topic, validTopicsList())
+		}
+	}
+}
+
+// validTopicsList renders the known topics for error messages
+func validTopicsList() string {
+	topics := make([]string, 0, len(notifier.Topics()))
+	for _, topic := range notifier.Topics() {
+		topics = append(topics, string(topic))
+	}
+	return strings.Join(topics, ", ")
+}
+
+func (o *Options) validatePrecision(v *optionsValidator) {
+	fields := []struct {
+		name     string
+		decimals int
+	}{
+		{"PRECISION_CHANGE", o.Precision.Change},
+		{"PRECISION_RSI", o.Precision.RSI},
+		{"PRECISION_AVG_QUOTE", o.Precision.AvgQuote},
+		{"PRECISION_AVG_TREND", o.Precision.AvgTrend},
+		{"PRECISION_AVG_BUY_10", o.Precision.AvgBuy10},
+		{"PRECISION_NOTIONAL", o.Precision.Notional},
+	}
+	for _, field := range fields {
+		if field.decimals < -1 || field.decimals > domain.MaxPrecision {
+			v.addf("%s: must be between -1 (unrounded) and %d decimals, got %d", field.name, domain.MaxPrecision, field.decimals)
+		}
+	}
+}
+
+func (o *Options) validateThresholds(v *optionsValidator) {
+	if o.WarmUp < 0 {
+		v.addf("WARM_UP: must not be negative, got %s", o.WarmUp)
+	}
+	if o.HistoryTimeout < 0 {
+		v.addf("HISTORY_TIMEOUT: must not be negative, got %s", o.HistoryTimeout)
+	}
+	if o.Liquidity.MinNotional < 0 {
+		v.addf("LIQUIDITY_MIN_NOTIONAL: must not be negative, got %g", o.Liquidity.MinNotional)
+	}
+	if o.Liquidity.Drop && o.Liquidity.MinNotional == 0 {
+		v.addf("LIQUIDITY_DROP: has no effect without LIQUIDITY_MIN_NOTIONAL")
+	}
+
+	if len(o.HighRes.Symbols) > 0 {
+		if o.HighRes.Interval < importer.MinSubTickInterval {
+			v.addf("HIGH_RES_INTERVAL: must be at least %s, got %s", importer.MinSubTickInterval, o.HighRes.Interval)
+		}
+		if others := o.nonBinanceExchanges(); len(others) > 0 {
+			v.addf("HIGH_RES_SYMBOLS: high-resolution sampling is only supported by binance, got %s", strings.Join(others, ", "))
+		}
+	}
+
+	if len(o.Trades.Symbols) > 0 {
+		if others := o.nonBinanceExchanges(); len(others) > 0 {
+			v.addf("TRADES_SYMBOLS: trades are only streamed from binance, got %s", strings.Join(others, ", "))
+		}
+	}
+
+	if interval := o.Funding.Interval; interval != 0 {
+		if interval < importer.MinFundingInterval {
+			v.addf("FUNDING_INTERVAL: must be at least %s, got %s", importer.MinFundingInterval, interval)
+		}
+		if enabled := o.enabledExchanges(); len(enabled) > 0 && !slices.ContainsFunc(enabled, servesFundingRates) {
+			v.addf("FUNDING_INTERVAL: funding rates are only served by binance, bybit and okx, got %s", strings.Join(enabled, ", "))
+		}
+	}
+
+	if interval := o.OpenInterest.Interval; interval != 0 {
+		if interval < importer.MinOpenInterestInterval {
+			v.addf("OPEN_INTEREST_INTERVAL: must be at least %s, got %s", importer.MinOpenInterestInterval, interval)
+		}
+		if enabled := o.enabledExchanges(); len(enabled) > 0 && !slices.ContainsFunc(enabled, servesOpenInterest) {
+			v.addf("OPEN_INTEREST_INTERVAL: open interest is only polled from binance and okx (bybit tickers carry it), got %s", strings.Join(enabled, ", "))
+		}
+	}
+
+	if file := o.SymbolMeta.File; file != "" {
+		if _, err := symbolmeta.Load(file); err != nil {
+			v.addf("SYMBOL_META_FILE: %s", err)
+		}
+	}
+
+	if o.Symbols.Registry && o.Symbols.DelistAfter <= 0 {
+		v.addf("SYMBOLS_DELIST_AFTER: must be positive, got %s", o.Symbols.DelistAfter)
+	}
+	for _, quote := range o.Symbols.Quotes {
+		if quote == "" || strings.ToUpper(quote) != quote {
+			v.addf("SYMBOLS_QUOTES: must be upper case assets, got %q", quote)
+		}
+	}
+	for _, quote := range slices.Sorted(maps.Keys(o.Symbols.QuoteAliases)) {
+		if alias := o.Symbols.QuoteAliases[quote]; quote == "" || alias == "" || strings.ToUpper(quote+alias) != quote+alias {
+			v.addf("SYMBOLS_QUOTE_ALIASES: must map upper case assets, got %q:%q", quote, alias)
+		}
+	}
+
+	if o.API.Enabled {
+		if o.API.Addr == "" {
+			v.addf("API_ADDR: required when the API is enabled")
+		} else if o.Notify.WS.Topics != "" && o.API.Addr == o.Notify.WS.Addr {
+			v.addf("API_ADDR: must differ from NOTIFY_WS_ADDR, got %s for both", o.API.Addr)
+		}
+	}
+
+	if prometheus := o.Telemetry.Prometheus; prometheus.Enabled {
+		switch {
+		case prometheus.Addr == "":
+			v.addf("TELEMETRY_PROMETHEUS_ADDR: required when the Prometheus metrics are enabled")
+		case o.API.Enabled && prometheus.Addr == o.API.Addr:
+			v.addf("TELEMETRY_PROMETHEUS_ADDR: must differ from API_ADDR, got %s for both", prometheus.Addr)
+		case o.Notify.WS.Topics != "" && prometheus.Addr == o.Notify.WS.Addr:
+			v.addf("TELEMETRY_PROMETHEUS_ADDR: must differ from NOTIFY_WS_ADDR, got %s for both", prometheus.Addr)
+		}
+		if !strings.HasPrefix(prometheus.Path, "/") {
+			v.addf("TELEMETRY_PROMETHEUS_PATH: must start with /, got %q", prometheus.Path)
+		}
+		if prometheus.Namespace != "" && !prometheusNamespace.MatchString(prometheus.Namespace) {
+			v.addf("TELEMETRY_PROMETHEUS_NAMESPACE: must be letters, digits and underscores starting with a letter, got %q", prometheus.Namespace)
+		}
+	}
+	if otel := o.Telemetry.OTel; otel.Enabled {
+		if !otel.EnabledTracing && !otel.EnabledMetrics {
+			v.addf("TELEMETRY_OTEL_ENABLED: nothing to export, enable TELEMETRY_OTEL_ENABLED_TRACING or TELEMETRY_OTEL_ENABLED_METRICS")
+		}
+		if strings.Contains(otel.Endpoint, "://") {
+			v.addf("TELEMETRY_OTEL_ENDPOINT: must be host:port without a scheme, got %q", otel.Endpoint)
+		}
+		if otel.EnabledMetrics && otel.Interval <= 0 {
+			v.addf("TELEMETRY_OTEL_INTERVAL: must be positive, got %s", otel.Interval)
+		}
+	}
+
+	if coinglass := o.Liquidations.Coinglass; coinglass.Enabled {
+		if coinglass.APIKey == "" {
+			v.addf("LIQUIDATIONS_COINGLASS_API_KEY: required when the coinglass source is enabled")
+		}
+		if coinglass.PollInterval <= 0 {
+			v.addf("LIQUIDATIONS_COINGLASS_POLL_INTERVAL: must be positive, got %s", coinglass.PollInterval)
+		}
+	}
+	if o.Liquidations.MaxPriceDeviation < 0 {
+		v.addf("LIQUIDATIONS_MAX_PRICE_DEVIATION: must not be negative, got %g", o.Liquidations.MaxPriceDeviation)
+	}
+	if storage := o.Liquidations.Storage; storage.SpikeFactor != 0 && storage.SpikeFactor <= 1 {
+		v.addf("LIQUIDATIONS_STORAGE_SPIKE_FACTOR: must be 0 (disabled) or above 1, got %g", storage.SpikeFactor)
+	}
+	if o.Liquidations.Storage.Baseline < 0 {
+		v.addf("LIQUIDATIONS_STORAGE_BASELINE: must not be negative, got %s", o.Liquidations.Storage.Baseline)
+	}
+	if o.Liquidations.Storage.BatchSize < 0 {
+		v.addf("LIQUIDATIONS_STORAGE_BATCH_SIZE: must not be negative, got %d", o.Liquidations.Storage.BatchSize)
+	}
+	if backfill := o.Liquidations.Backfill; backfill != 0 {
+		if backfill < 0 {
+			v.addf("LIQUIDATIONS_BACKFILL: must not be negative, got %s", backfill)
+		}
+		if !o.Liquidations.Coinglass.Enabled {
+			v.addf("LIQUIDATIONS_BACKFILL: requires LIQUIDATIONS_COINGLASS_ENABLED, the exchanges don't serve past liquidations")
+		}
+	}
+	o.validateTickChecks(v)
+	if o.Symbols.MaxTracked < 0 {
+		v.addf("SYMBOLS_MAX_TRACKED: must not be negative, got %d", o.Symbols.MaxTracked)
+	}
+	for _, top := range slices.Sorted(maps.Keys(o.Symbols.Sampling.Tiers)) {
+		if every := o.Symbols.Sampling.Tiers[top]; top < 1 || every < 1 {
+			v.addf("SYMBOLS_SAMPLING_TIERS: must map positive ranks to positive intervals, got %d:%d", top, every)
+		}
+	}
+	if o.Symbols.Sampling.Every < 0 {
+		v.addf("SYMBOLS_SAMPLING_EVERY: must not be negative, got %d", o.Symbols.Sampling.Every)
+	}
+	if o.Quality.FetchBudget < 0 {
+		v.addf("QUALITY_FETCH_BUDGET: must not be negative, got %s", o.Quality.FetchBudget)
+	}
+	if o.Quality.LiquidationsStaleAfter < 0 {
+		v.addf("QUALITY_LIQUIDATIONS_STALE_AFTER: must not be negative, got %s", o.Quality.LiquidationsStaleAfter)
+	}
+	if len(o.Priority.Symbols) > 0 && (o.Priority.Deadline <= 0 || o.Priority.Deadline >= importer.TickInterval) {
+		v.addf("PRIORITY_DEADLINE: must be between 0 and the %s tick interval, got %s", importer.TickInterval, o.Priority.Deadline)
+	}
+
+	opsAlerts := o.OpsAlerts
+	if opsAlerts.TickGap < 0 {
+		v.addf("OPS_ALERTS_TICK_GAP: must not be negative, got %s", opsAlerts.TickGap)
+	}
+	if opsAlerts.ReconnectStormCount < 0 {
+		v.addf("OPS_ALERTS_RECONNECT_STORM_COUNT: must not be negative, got %d", opsAlerts.ReconnectStormCount)
+	}
+	if opsAlerts.ReconnectStormCount > 0 && opsAlerts.ReconnectStormWindow <= 0 {
+		v.addf("OPS_ALERTS_RECONNECT_STORM_WINDOW: must be positive, got %s", opsAlerts.ReconnectStormWindow)
+	}
+	if opsAlerts.RepositoryResolveAfter < 0 {
+		v.addf("OPS_ALERTS_REPOSITORY_RESOLVE_AFTER: must not be negative, got %s", opsAlerts.RepositoryResolveAfter)
+	}
+	if opsAlerts.StreamSilence < 0 {
+		v.addf("OPS_ALERTS_STREAM_SILENCE: must not be negative, got %s", opsAlerts.StreamSilence)
+	}
+	if opsAlerts.LiquidationStreamSilence < 0 {
+		v.addf("OPS_ALERTS_LIQUIDATION_STREAM_SILENCE: must not be negative, got %s", opsAlerts.LiquidationStreamSilence)
+	}
+	if opsAlerts.StreamSpikeFactor < 0 || (opsAlerts.StreamSpikeFactor > 0 && opsAlerts.StreamSpikeFactor <= 1) {
+		v.addf("OPS_ALERTS_STREAM_SPIKE_FACTOR: must be 0 or greater than 1, got %g", opsAlerts.StreamSpikeFactor)
+	}
+	if opsAlerts.StreamSpikeFactor > 0 && opsAlerts.StreamBaseline <= 0 {
+		v.addf("OPS_ALERTS_STREAM_BASELINE: must be positive, got %s", opsAlerts.StreamBaseline)
+	}
+	if opsAlerts.SuspectLiquidationsResolveAfter < 0 {
+		v.addf("OPS_ALERTS_SUSPECT_LIQUIDATIONS_RESOLVE_AFTER: must not be negative, got %s", opsAlerts.SuspectLiquidationsResolveAfter)
+	}
+	if opsAlerts.ClockSkew < 0 {
+		v.addf("OPS_ALERTS_CLOCK_SKEW: must not be negative, got %s", opsAlerts.ClockSkew)
+	}
+
+	if composite := o.Composite; len(composite.Peers) > 0 {
+		if !o.Repository.Mongo.Enabled {
+			v.addf("COMPOSITE_PEERS: the composite price reads the ticks of other importers and requires the mongo repository")
+		}
+		// with several exchanges enabled, each leaves its own name out of the peers
+		if name := o.ExchangeName(); len(o.enabledExchanges()) == 1 && slices.Contains(composite.Peers, name) {
+			v.addf("COMPOSITE_PEERS: must not contain the own exchange name %q", name)
+		}
+		if composite.MaxAge <= 0 {
+			v.addf("COMPOSITE_MAX_AGE: must be positive, got %s", composite.MaxAge)
+		}
+		if composite.MinSources < 1 {
+			v.addf("COMPOSITE_MIN_SOURCES: must be at least 1, got %d", composite.MinSources)
+		}
+	}
+	if o.Composite.AlertDeviation < 0 {
+		v.addf("COMPOSITE_ALERT_DEVIATION: must not be negative, got %g", o.Composite.AlertDeviation)
+	}
+	if o.Composite.AlertDeviation > 0 && len(o.Composite.Peers) == 0 {
+		v.addf("COMPOSITE_ALERT_DEVIATION: has no effect without COMPOSITE_PEERS")
+	}
+
+	if o.Outages.ResolveAfter < 0 {
+		v.addf("OUTAGES_RESOLVE_AFTER: must not be negative, got %s", o.Outages.ResolveAfter)
+	}
+
+	if o.Log.Sampling.Thereafter < 0 {
+		v.addf("LOG_SAMPLING_THEREAFTER: must not be negative, got %d", o.Log.Sampling.Thereafter)
+	}
+}
@@ -0,0 +1,1075 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	notificationStrategies "github.com/ayankousky/exchange-data-importer/internal/notifier/strategies"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(o *Options)
+		wantProblems []string
+	}{
+		{
+			name:   "valid options",
+			modify: func(o *Options) {},
+		},
+		{
+			name:         "no exchange",
+			modify:       func(o *Options) { o.Exchange.Binance.Enabled = false },
+			wantProblems: []string{"EXCHANGE_*_ENABLED: no exchange enabled, enable at least one of binance, bybit, okx, coinbase"},
+		},
+		{
+			name: "several exchanges",
+			modify: func(o *Options) {
+				o.Exchange.Bybit.Enabled = true
+				o.Exchange.OKX.Enabled = true
+				o.Exchange.Coinbase.Enabled = true
+			},
+		},
+		{
+			name: "several exchanges sharing a name",
+			modify: func(o *Options) {
+				o.Exchange.Binance.Name = "perp"
+				o.Exchange.Bybit.Enabled = true
+				o.Exchange.Bybit.Name = "perp"
+			},
+			wantProblems: []string{`EXCHANGE_*_NAME: binance and bybit are both named "perp", the enabled exchanges must have distinct names`},
+		},
+		{
+			name: "unknown exchange instrument types",
+			modify: func(o *Options) {
+				o.Exchange.Bybit.Categories = []string{"linear", "spot"}
+				o.Exchange.OKX.InstTypes = []string{"swap"}
+			},
+			wantProblems: []string{
+				`EXCHANGE_BYBIT_CATEGORIES: unknown category "spot" (valid: linear, inverse, option)`,
+				`EXCHANGE_OKX_INST_TYPES: unknown instrument type "swap" (valid: SWAP, FUTURES, OPTION)`,
+			},
+		},
+		{
+			name: "unknown event at fallback",
+			modify: func(o *Options) {
+				o.Exchange.Binance.EventAtFallback = "received"
+				o.Exchange.Bybit.EventAtFallback = "local"
+			},
+			wantProblems: []string{
+				`EXCHANGE_BYBIT_EVENT_AT_FALLBACK: unknown fallback "local" (valid: exchange, received, reject)`,
+			},
+		},
+		{
+			name: "negative websocket settings",
+			modify: func(o *Options) {
+				o.Exchange.Binance.WS.HandshakeTimeout = -time.Second
+				o.Exchange.Binance.WS.ReadBufferSize = -1
+				o.Exchange.OKX.WS.PingInterval = -time.Second
+			},
+			wantProblems: []string{
+				"EXCHANGE_BINANCE_WS_HANDSHAKE_TIMEOUT: must not be negative, got -1s",
+				"EXCHANGE_BINANCE_WS_READ_BUFFER_SIZE: must not be negative, got -1",
+				"EXCHANGE_OKX_WS_PING_INTERVAL: must not be negative, got -1s",
+			},
+		},
+		{
+			name: "repository requirements",
+			modify: func(o *Options) {
+				o.Repository.Mongo.Enabled = true
+				o.Repository.Sqlite.Enabled = true
+				o.Repository.SlowOp = -time.Second
+			},
+			wantProblems: []string{
+				"REPOSITORY_*_ENABLED: only one repository can be enabled, got mongo, sqlite",
+				"REPOSITORY_MONGO_URL: required when the mongo repository is enabled",
+				"REPOSITORY_SQLITE_PATH: required when the sqlite repository is enabled",
+				"REPOSITORY_SLOW_OP: must not be negative, got -1s",
+			},
+		},
+		{
+			name: "codecs",
+			modify: func(o *Options) {
+				o.Repository.Codec = "xml"
+				o.Notify.File.Codec = "yaml"
+			},
+			wantProblems: []string{
+				`REPOSITORY_CODEC: must be one of json, bson, json-gzip, cbor, got "xml"`,
+				`NOTIFY_FILE_CODEC: must be one of json, bson, json-gzip, cbor, got "yaml"`,
+			},
+		},
+		{
+			name: "market data formats",
+			modify: func(o *Options) {
+				o.Notify.MarketDataFormat = "ticker"
+				o.Notify.File.MarketDataFormat = "digest"
+				o.Notify.WS.MarketDataFormat = "bars"
+			},
+			wantProblems: []string{`NOTIFY_WS_MARKET_DATA_FORMAT: must be one of ticker, digest, got "bars"`},
+		},
+		{
+			name: "postgres codec",
+			modify: func(o *Options) {
+				o.Repository.Sqlite.Enabled = false
+				o.Repository.Postgres.Enabled = true
+				o.Repository.Postgres.URL = "postgres://localhost/exchange"
+				o.Repository.Codec = "cbor"
+			},
+			wantProblems: []string{`REPOSITORY_CODEC: postgres stores JSONB documents, only json is supported, got "cbor"`},
+		},
+		{
+			name: "postgres repository requirements",
+			modify: func(o *Options) {
+				o.Repository.Sqlite.Enabled = true
+				o.Repository.Sqlite.Path = "data.db"
+				o.Repository.Postgres.Enabled = true
+			},
+			wantProblems: []string{
+				"REPOSITORY_*_ENABLED: only one repository can be enabled, got sqlite, postgres",
+				"REPOSITORY_POSTGRES_URL: required when the postgres repository is enabled",
+			},
+		},
+		{
+			name: "unknown topics",
+			modify: func(o *Options) {
+				o.Notify.Stdout.Topics = "TICK_INFO, TICKS"
+				o.Notify.Redis.URL = "redis://localhost"
+				o.Notify.Redis.Topics = "market_data"
+				o.Notify.Shadow.Topics = "ALERT_MARKET_STATE,ALERTS"
+			},
+			wantProblems: []string{
+				`NOTIFY_REDIS_TOPICS: unknown topic "market_data" (valid: MARKET_DATA, ALERT_MARKET_STATE, TICK_INFO, TIME_SERIES, OPS_ALERT, MINUTE_BARS, ANNOTATIONS)`,
+				`NOTIFY_STDOUT_TOPICS: unknown topic "TICKS" (valid: MARKET_DATA, ALERT_MARKET_STATE, TICK_INFO, TIME_SERIES, OPS_ALERT, MINUTE_BARS, ANNOTATIONS)`,
+				`NOTIFY_SHADOW_TOPICS: unknown topic "ALERTS" (valid: MARKET_DATA, ALERT_MARKET_STATE, TICK_INFO, TIME_SERIES, OPS_ALERT, MINUTE_BARS, ANNOTATIONS)`,
+			},
+		},
+		{
+			name: "redis sentinel",
+			modify: func(o *Options) {
+				o.Notify.Redis.Topics = "MARKET_DATA"
+				o.Notify.Redis.Mode = "sentinel"
+				o.Notify.Redis.TLSCAFile = "missing-ca.pem"
+			},
+			wantProblems: []string{
+				"NOTIFY_REDIS_ADDRS: required in sentinel mode",
+				"NOTIFY_REDIS_MASTER_NAME: required in sentinel mode",
+				"NOTIFY_REDIS_TLS_CA_FILE: stat missing-ca.pem: no such file or directory",
+			},
+		},
+		{
+			name: "redis cluster",
+			modify: func(o *Options) {
+				o.Notify.Redis.Topics = "MARKET_DATA"
+				o.Notify.Redis.Mode = "cluster"
+			},
+			wantProblems: []string{"NOTIFY_REDIS_ADDRS: required in cluster mode"},
+		},
+		{
+			name: "tracked symbols",
+			modify: func(o *Options) {
+				o.Symbols.MaxTracked = -1
+			},
+			wantProblems: []string{"SYMBOLS_MAX_TRACKED: must not be negative, got -1"},
+		},
+		{
+			name: "storage sampling",
+			modify: func(o *Options) {
+				o.Symbols.Sampling.Tiers = map[int]int{50: 1, 0: 5, 200: 0}
+				o.Symbols.Sampling.Every = -1
+			},
+			wantProblems: []string{
+				"SYMBOLS_SAMPLING_TIERS: must map positive ranks to positive intervals, got 0:5",
+				"SYMBOLS_SAMPLING_TIERS: must map positive ranks to positive intervals, got 200:0",
+				"SYMBOLS_SAMPLING_EVERY: must not be negative, got -1",
+			},
+		},
+		{
+			name: "redis pool",
+			modify: func(o *Options) {
+				o.Notify.Redis.URL = "redis://localhost"
+				o.Notify.Redis.Topics = "MARKET_DATA"
+				o.Notify.Redis.PoolSize = 4
+				o.Notify.Redis.MinIdleConns = 8
+				o.Notify.Redis.Pipeline = -1
+			},
+			wantProblems: []string{
+				"NOTIFY_REDIS_MIN_IDLE_CONNS: must be between 0 and NOTIFY_REDIS_POOL_SIZE (4), got 8",
+				"NOTIFY_REDIS_PIPELINE: must not be negative, got -1",
+			},
+		},
+		{
+			name: "quote assets",
+			modify: func(o *Options) {
+				o.Symbols.Quotes = []string{"USDT", "usdc"}
+				o.Symbols.QuoteAliases = map[string]string{"USDT": "USD", "USDC": ""}
+			},
+			wantProblems: []string{
+				`SYMBOLS_QUOTES: must be upper case assets, got "usdc"`,
+				`SYMBOLS_QUOTE_ALIASES: must map upper case assets, got "USDC":""`,
+			},
+		},
+		{
+			name: "alert persistence",
+			modify: func(o *Options) {
+				o.Notify.Stdout.Alert.Persistence = 3
+				o.Notify.Shadow.Alert.Persistence = 100
+			},
+			wantProblems: []string{
+				"NOTIFY_SHADOW_ALERT_PERSISTENCE: must be between 0 and 25, got 100",
+			},
+		},
+		{
+			name: "notifier requirements",
+			modify: func(o *Options) {
+				o.Notify.Redis.Topics = "MARKET_DATA"
+				o.Notify.Telegram.Topics = "ALERT_MARKET_STATE"
+				o.Notify.Influx.Topics = "TIME_SERIES"
+				o.Notify.Webhook.Topics = "OPS_ALERT"
+				o.Notify.Grafana.Topics = "ANNOTATIONS"
+				o.Notify.File.Topics = "MARKET_DATA"
+				o.Notify.WS.Topics = "TICK_INFO"
+				o.Notify.WS.Addr = ""
+				o.Notify.WS.Path = "ws"
+				o.Notify.WS.Buffer = 0
+			},
+			wantProblems: []string{
+				"NOTIFY_REDIS_URL: required when redis topics are set",
+				"NOTIFY_TELEGRAM_BOT_TOKEN: required when telegram topics are set",
+				"NOTIFY_TELEGRAM_CHAT_ID: required when telegram topics are set",
+				"NOTIFY_INFLUX_URL: required when influx topics are set",
+				"NOTIFY_INFLUX_ORG: required when influx topics are set",
+				"NOTIFY_INFLUX_BUCKET: required when influx topics are set",
+				"NOTIFY_WEBHOOK_URL: required when webhook topics are set",
+				"NOTIFY_GRAFANA_URL: required when grafana topics are set",
+				"NOTIFY_WS_ADDR: required when ws topics are set",
+				`NOTIFY_WS_PATH: must start with /, got "ws"`,
+				"NOTIFY_WS_BUFFER: must be positive, got 0",
+				"NOTIFY_WS_KEYFRAME_INTERVAL: must be positive, got 0s",
+				"NOTIFY_FILE_DIR: required when file topics are set",
+				"NOTIFY_FILE_MAX_SIZE_MB: must be positive, got 0",
+			},
+		},
+		{
+			name: "threshold sanity",
+			modify: func(o *Options) {
+				o.Liquidity.MinNotional = -1
+				o.HighRes.Symbols = []string{"BTCUSDT"}
+				o.HighRes.Interval = 10 * time.Millisecond
+				o.Notify.File.Retention = -time.Hour
+				o.Notify.File.Format = "parquet"
+				o.WarmUp = -time.Minute
+				o.HistoryTimeout = -time.Second
+			},
+			wantProblems: []string{
+				`NOTIFY_FILE_FORMAT: only csv is supported, got "parquet"`,
+				"NOTIFY_FILE_RETENTION: must not be negative, got -1h0m0s",
+				"WARM_UP: must not be negative, got -1m0s",
+				"HISTORY_TIMEOUT: must not be negative, got -1s",
+				"LIQUIDITY_MIN_NOTIONAL: must not be negative, got -1",
+				"HIGH_RES_INTERVAL: must be at least 100ms, got 10ms",
+			},
+		},
+		{
+			name: "maintenance windows",
+			modify: func(o *Options) {
+				o.Exchange.Bybit.Maintenance = []string{"Tue 06:00-08:00", "Tues 06:00-08:00"}
+			},
+			wantProblems: []string{
+				`EXCHANGE_BYBIT_MAINTENANCE: window "Tues 06:00-08:00": unknown day "Tues"`,
+			},
+		},
+		{
+			name: "tick offset",
+			modify: func(o *Options) {
+				o.Exchange.Bybit.TickOffset = 300 * time.Millisecond
+				o.Exchange.OKX.TickOffset = time.Second
+				o.Exchange.Coinbase.TickOffset = -time.Millisecond
+			},
+			wantProblems: []string{
+				"EXCHANGE_OKX_TICK_OFFSET: must be at least 0 and below the tick interval 1s, got 1s",
+				"EXCHANGE_COINBASE_TICK_OFFSET: must be at least 0 and below the tick interval 1s, got -1ms",
+			},
+		},
+		{
+			name: "liquidation storage",
+			modify: func(o *Options) {
+				o.Liquidations.Storage.SpikeFactor = 0.5
+				o.Liquidations.Storage.Baseline = -time.Minute
+				o.Liquidations.Storage.BatchSize = -1
+			},
+			wantProblems: []string{
+				"LIQUIDATIONS_STORAGE_SPIKE_FACTOR: must be 0 (disabled) or above 1, got 0.5",
+				"LIQUIDATIONS_STORAGE_BASELINE: must not be negative, got -1m0s",
+				"LIQUIDATIONS_STORAGE_BATCH_SIZE: must not be negative, got -1",
+			},
+		},
+		{
+			name: "suspect liquidations",
+			modify: func(o *Options) {
+				o.Liquidations.MaxPriceDeviation = -1
+				o.OpsAlerts.SuspectLiquidationsResolveAfter = -time.Minute
+			},
+			wantProblems: []string{
+				"LIQUIDATIONS_MAX_PRICE_DEVIATION: must not be negative, got -1",
+				"OPS_ALERTS_SUSPECT_LIQUIDATIONS_RESOLVE_AFTER: must not be negative, got -1m0s",
+			},
+		},
+		{
+			name: "negative clock skew",
+			modify: func(o *Options) {
+				o.OpsAlerts.ClockSkew = -time.Second
+			},
+			wantProblems: []string{"OPS_ALERTS_CLOCK_SKEW: must not be negative, got -1s"},
+		},
+		{
+			name: "precision out of range",
+			modify: func(o *Options) {
+				o.Precision.Change = -1
+				o.Precision.RSI = -2
+				o.Precision.Notional = 13
+			},
+			wantProblems: []string{
+				"PRECISION_RSI: must be between -1 (unrounded) and 12 decimals, got -2",
+				"PRECISION_NOTIONAL: must be between -1 (unrounded) and 12 decimals, got 13",
+			},
+		},
+		{
+			name: "notifier alert thresholds",
+			modify: func(o *Options) {
+				o.Notify.Redis.Alert.AvgPrice1mChange = -1
+				o.Notify.Telegram.Alert.TickerPrice1mChange = -15
+				o.Notify.Telegram.Alert.CategoryPrice1mChange = -3
+				o.Notify.Stdout.Alert.TickerPrice1mChangeBySymbol = map[string]float64{"BTC*": 1, "[ETH": 2, "SOL*": -3}
+				o.Notify.File.Alert.AvgPrice20mChange = -5
+				o.Notify.Breaker.Threshold = 3
+				o.Notify.Breaker.Cooldown = 0
+			},
+			wantProblems: []string{
+				"NOTIFY_BREAKER_COOLDOWN: must be positive, got 0s",
+				"NOTIFY_REDIS_ALERT_AVG_PRICE_1M_CHANGE: must not be negative, got -1",
+				"NOTIFY_TELEGRAM_ALERT_TICKER_PRICE_1M_CHANGE: must not be negative, got -15",
+				"NOTIFY_TELEGRAM_ALERT_CATEGORY_PRICE_1M_CHANGE: must not be negative, got -3",
+				"NOTIFY_STDOUT_ALERT_TICKER_PRICE_1M_CHANGE_BY_SYMBOL: must not be negative, got -3 for SOL*",
+				`NOTIFY_STDOUT_ALERT_TICKER_PRICE_1M_CHANGE_BY_SYMBOL: invalid pattern "[ETH"`,
+				"NOTIFY_FILE_ALERT_AVG_PRICE_20M_CHANGE: must not be negative, got -5",
+			},
+		},
+		{
+			name: "missing symbol metadata file",
+			modify: func(o *Options) {
+				o.SymbolMeta.File = "/nonexistent/symbols.json"
+			},
+			wantProblems: []string{
+				"SYMBOL_META_FILE: reading symbol metadata: open /nonexistent/symbols.json: no such file or directory",
+			},
+		},
+		{
+			name: "operational alert rules",
+			modify: func(o *Options) {
+				o.OpsAlerts.TickGap = -time.Second
+				o.OpsAlerts.ReconnectStormCount = 5
+				o.OpsAlerts.ReconnectStormWindow = 0
+				o.OpsAlerts.StreamSpikeFactor = 0.5
+				o.OpsAlerts.StreamBaseline = 0
+				o.OpsAlerts.LiquidationStreamSilence = -time.Minute
+				o.Outages.ResolveAfter = -time.Second
+			},
+			wantProblems: []string{
+				"OPS_ALERTS_TICK_GAP: must not be negative, got -1s",
+				"OPS_ALERTS_RECONNECT_STORM_WINDOW: must be positive, got 0s",
+				"OPS_ALERTS_LIQUIDATION_STREAM_SILENCE: must not be negative, got -1m0s",
+				"OPS_ALERTS_STREAM_SPIKE_FACTOR: must be 0 or greater than 1, got 0.5",
+				"OPS_ALERTS_STREAM_BASELINE: must be positive, got 0s",
+				"OUTAGES_RESOLVE_AFTER: must not be negative, got -1s",
+			},
+		},
+		{
+			name: "high resolution sampling on an unsupported exchange",
+			modify: func(o *Options) {
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.OKX.Enabled = true
+				o.HighRes.Symbols = []string{"BTC-USDT-SWAP"}
+				o.HighRes.Interval = 250 * time.Millisecond
+			},
+			wantProblems: []string{"HIGH_RES_SYMBOLS: high-resolution sampling is only supported by binance, got okx"},
+		},
+		{
+			name: "trades on an unsupported exchange",
+			modify: func(o *Options) {
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.Bybit.Enabled = true
+				o.Trades.Symbols = []string{"BTCUSDT"}
+			},
+			wantProblems: []string{"TRADES_SYMBOLS: trades are only streamed from binance, got bybit"},
+		},
+		{
+			name: "binance-only options with several exchanges",
+			modify: func(o *Options) {
+				o.Exchange.Bybit.Enabled = true
+				o.HighRes.Symbols = []string{"BTCUSDT"}
+				o.HighRes.Interval = 250 * time.Millisecond
+				o.Trades.Symbols = []string{"BTCUSDT"}
+			},
+			wantProblems: []string{
+				"HIGH_RES_SYMBOLS: high-resolution sampling is only supported by binance, got bybit",
+				"TRADES_SYMBOLS: trades are only streamed from binance, got bybit",
+			},
+		},
+		{
+			name: "funding rates on an unsupported exchange",
+			modify: func(o *Options) {
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.Coinbase.Enabled = true
+				o.Funding.Interval = time.Second
+			},
+			wantProblems: []string{
+				"FUNDING_INTERVAL: must be at least 10s, got 1s",
+				"FUNDING_INTERVAL: funding rates are only served by binance, bybit and okx, got coinbase",
+			},
+		},
+		{
+			name: "open interest polled from an exchange without the endpoint",
+			modify: func(o *Options) {
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.Bybit.Enabled = true
+				o.OpenInterest.Interval = 10 * time.Second
+			},
+			wantProblems: []string{
+				"OPEN_INTEREST_INTERVAL: must be at least 15s, got 10s",
+				"OPEN_INTEREST_INTERVAL: open interest is only polled from binance and okx (bybit tickers carry it), got bybit",
+			},
+		},
+		{
+			name: "negative quality budgets",
+			modify: func(o *Options) {
+				o.Quality.FetchBudget = -time.Second
+				o.Quality.LiquidationsStaleAfter = -time.Minute
+			},
+			wantProblems: []string{
+				"QUALITY_FETCH_BUDGET: must not be negative, got -1s",
+				"QUALITY_LIQUIDATIONS_STALE_AFTER: must not be negative, got -1m0s",
+			},
+		},
+		{
+			name: "symbol registry without a delisting delay",
+			modify: func(o *Options) {
+				o.Symbols.Registry = true
+				o.Symbols.DelistAfter = 0
+			},
+			wantProblems: []string{"SYMBOLS_DELIST_AFTER: must be positive, got 0s"},
+		},
+		{
+			name: "api on the address of the websocket server",
+			modify: func(o *Options) {
+				o.API.Enabled = true
+				o.API.Addr = ":8090"
+				o.Notify.WS.Topics = "TICK_INFO"
+				o.Notify.WS.Addr = ":8090"
+				o.Notify.WS.Path = "/ws"
+				o.Notify.WS.Buffer = 1024
+				o.Notify.WS.KeyframeInterval = 10 * time.Second
+			},
+			wantProblems: []string{"API_ADDR: must differ from NOTIFY_WS_ADDR, got :8090 for both"},
+		},
+		{
+			name: "api without an address",
+			modify: func(o *Options) {
+				o.API.Enabled = true
+				o.API.Addr = ""
+			},
+			wantProblems: []string{"API_ADDR: required when the API is enabled"},
+		},
+		{
+			name: "prometheus on the address of the api",
+			modify: func(o *Options) {
+				o.API.Enabled = true
+				o.API.Addr = ":8080"
+				o.Telemetry.Prometheus.Enabled = true
+				o.Telemetry.Prometheus.Addr = ":8080"
+				o.Telemetry.Prometheus.Path = "metrics"
+				o.Telemetry.Prometheus.Namespace = "exchange-importer"
+			},
+			wantProblems: []string{
+				"TELEMETRY_PROMETHEUS_ADDR: must differ from API_ADDR, got :8080 for both",
+				`TELEMETRY_PROMETHEUS_PATH: must start with /, got "metrics"`,
+				`TELEMETRY_PROMETHEUS_NAMESPACE: must be letters, digits and underscores starting with a letter, got "exchange-importer"`,
+			},
+		},
+		{
+			name: "otel without signals",
+			modify: func(o *Options) {
+				o.Telemetry.OTel.Enabled = true
+				o.Telemetry.OTel.Endpoint = "http://collector:4317"
+			},
+			wantProblems: []string{
+				"TELEMETRY_OTEL_ENABLED: nothing to export, enable TELEMETRY_OTEL_ENABLED_TRACING or TELEMETRY_OTEL_ENABLED_METRICS",
+				`TELEMETRY_OTEL_ENDPOINT: must be host:port without a scheme, got "http://collector:4317"`,
+			},
+		},
+		{
+			name: "tick checks",
+			modify: func(o *Options) {
+				o.TickChecks.FetchDuration = -time.Second
+				o.TickChecks.TickersDrop = 1
+				o.TickChecks.AvgChange = -1
+				o.TickChecks.Reject = []string{"avg_change", "ticker_count"}
+			},
+			wantProblems: []string{
+				"TICK_CHECKS_FETCH_DURATION: must not be negative, got -1s",
+				"TICK_CHECKS_TICKERS_DROP: must be between 0 and 1, got 1",
+				"TICK_CHECKS_AVG_CHANGE: must not be negative, got -1",
+				`TICK_CHECKS_REJECT: unknown check "ticker_count" (valid: fetch_duration, tickers_count, avg_change)`,
+			},
+		},
+		{
+			name: "priority deadline beyond the tick interval",
+			modify: func(o *Options) {
+				o.Priority.Symbols = []string{"BTCUSDT", "ETHUSDT"}
+				o.Priority.Deadline = time.Second
+			},
+			wantProblems: []string{"PRIORITY_DEADLINE: must be between 0 and the 1s tick interval, got 1s"},
+		},
+		{
+			name: "composite price requirements",
+			modify: func(o *Options) {
+				o.Composite.Peers = []string{"okx", "test-service"}
+				o.Composite.MinSources = 0
+				o.Composite.AlertDeviation = -1
+			},
+			wantProblems: []string{
+				"COMPOSITE_PEERS: the composite price reads the ticks of other importers and requires the mongo repository",
+				`COMPOSITE_PEERS: must not contain the own exchange name "test-service"`,
+				"COMPOSITE_MAX_AGE: must be positive, got 0s",
+				"COMPOSITE_MIN_SOURCES: must be at least 1, got 0",
+				"COMPOSITE_ALERT_DEVIATION: must not be negative, got -1",
+			},
+		},
+		{
+			name: "liquidations backfill without coinglass",
+			modify: func(o *Options) {
+				o.Liquidations.Backfill = -time.Minute
+			},
+			wantProblems: []string{
+				"LIQUIDATIONS_BACKFILL: must not be negative, got -1m0s",
+				"LIQUIDATIONS_BACKFILL: requires LIQUIDATIONS_COINGLASS_ENABLED, the exchanges don't serve past liquidations",
+			},
+		},
+		{
+			name: "liquidations backfill from coinglass",
+			modify: func(o *Options) {
+				o.Exchange.Binance.Enabled = false
+				o.Exchange.Bybit.Enabled = true
+				o.Liquidations.Backfill = 30 * time.Minute
+				o.Liquidations.Coinglass.Enabled = true
+				o.Liquidations.Coinglass.APIKey = "secret"
+				o.Liquidations.Coinglass.PollInterval = 5 * time.Second
+			},
+		},
+		{
+			name: "liquidations options without liquidations",
+			modify: func(o *Options) {
+				o.ImportMode = "tickers"
+				o.Liquidations.Backfill = time.Hour
+				o.Liquidations.ArchiveRaw = true
+				o.Liquidations.Coinglass.Enabled = true
+				o.Liquidations.Coinglass.APIKey = "secret"
+				o.Liquidations.Coinglass.PollInterval = 5 * time.Second
+			},
+			wantProblems: []string{
+				"LIQUIDATIONS_BACKFILL: has no effect when IMPORT_MODE is tickers",
+				"LIQUIDATIONS_ARCHIVE_RAW: has no effect when IMPORT_MODE is tickers",
+				"LIQUIDATIONS_COINGLASS_ENABLED: has no effect when IMPORT_MODE is tickers",
+			},
+		},
+		{
+			name: "coinglass source requirements",
+			modify: func(o *Options) {
+				o.Liquidations.Coinglass.Enabled = true
+			},
+			wantProblems: []string{
+				"LIQUIDATIONS_COINGLASS_API_KEY: required when the coinglass source is enabled",
+				"LIQUIDATIONS_COINGLASS_POLL_INTERVAL: must be positive, got 0s",
+			},
+		},
+		{
+			name:         "unknown publish order",
+			modify:       func(o *Options) { o.PublishOrder = "store-last" },
+			wantProblems: []string{`PUBLISH_ORDER: unknown order "store-last" (valid: store-first, notify-first)`},
+		},
+		{
+			name:   "tickers only",
+			modify: func(o *Options) { o.ImportMode = "tickers" },
+		},
+		{
+			name:         "unknown import mode",
+			modify:       func(o *Options) { o.ImportMode = "ticks" },
+			wantProblems: []string{`IMPORT_MODE: unknown mode "ticks" (valid: full, tickers, liquidations)`},
+		},
+		{
+			name: "tick features in the liquidations mode",
+			modify: func(o *Options) {
+				o.ImportMode = "liquidations"
+				o.HighRes.Symbols = []string{"BTCUSDT"}
+				o.HighRes.Interval = 250 * time.Millisecond
+				o.Trades.Symbols = []string{"BTCUSDT"}
+				o.Priority.Symbols = []string{"BTCUSDT"}
+				o.Priority.Deadline = 800 * time.Millisecond
+				o.Bars.Enabled = true
+				o.Funding.Interval = time.Minute
+				o.OpenInterest.Interval = time.Minute
+				o.Symbols.Registry = true
+				o.Symbols.DelistAfter = time.Hour
+			},
+			wantProblems: []string{
+				"HIGH_RES_SYMBOLS: has no effect when IMPORT_MODE is liquidations",
+				"TRADES_SYMBOLS: has no effect when IMPORT_MODE is liquidations",
+				"PRIORITY_SYMBOLS: has no effect when IMPORT_MODE is liquidations",
+				"BARS_ENABLED: has no effect when IMPORT_MODE is liquidations",
+				"FUNDING_INTERVAL: has no effect when IMPORT_MODE is liquidations",
+				"OPEN_INTEREST_INTERVAL: has no effect when IMPORT_MODE is liquidations",
+				"SYMBOLS_REGISTRY: has no effect when IMPORT_MODE is liquidations",
+			},
+		},
+		{
+			name:         "composite alert without peers",
+			modify:       func(o *Options) { o.Composite.AlertDeviation = 1 },
+			wantProblems: []string{"COMPOSITE_ALERT_DEVIATION: has no effect without COMPOSITE_PEERS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newTestOptions(true)
+			tt.modify(opts)
+
+			err := opts.Validate()
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var optsErr *OptionsError
+			require.ErrorAs(t, err, &optsErr)
+			assert.Equal(t, tt.wantProblems, optsErr.Problems)
+		})
+	}
+}
+
+func TestOptions_ValidateRecompute(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		modify       func(o *Options)
+		wantProblems []string
+	}{
+		{
+			name:   "valid options",
+			modify: func(o *Options) {},
+		},
+		{
+			name:   "to defaults to now",
+			modify: func(o *Options) { o.Recompute.To = "" },
+		},
+		{
+			name: "missing service and repository",
+			modify: func(o *Options) {
+				o.ServiceName = ""
+				o.Repository.Sqlite.Enabled = false
+			},
+			wantProblems: []string{
+				"SERVICE_NAME: required to locate the stored ticks",
+				"REPOSITORY_*_ENABLED: no repository enabled, enable mongo or sqlite",
+			},
+		},
+		{
+			name: "invalid range",
+			modify: func(o *Options) {
+				o.Recompute.From = "yesterday"
+				o.Recompute.Window = 0
+			},
+			wantProblems: []string{
+				`RECOMPUTE_FROM: "yesterday" is not an RFC3339 time`,
+				"RECOMPUTE_WINDOW: must be positive, got 0s",
+			},
+		},
+		{
+			name:         "reversed range",
+			modify:       func(o *Options) { o.Recompute.To = "2024-12-31T00:00:00Z" },
+			wantProblems: []string{"RECOMPUTE_FROM: must be before RECOMPUTE_TO, got 2025-01-01T00:00:00Z - 2024-12-31T00:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newTestOptions(false)
+			opts.Repository.Sqlite.Enabled = true
+			opts.Repository.Sqlite.Path = "exchange.db"
+			opts.Recompute = RecomputeOptions{From: "2025-01-01T00:00:00Z", To: "2025-01-01T12:00:00Z", Window: time.Hour}
+			tt.modify(opts)
+
+			err := opts.ValidateRecompute(now)
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var optsErr *OptionsError
+			require.ErrorAs(t, err, &optsErr)
+			assert.Equal(t, tt.wantProblems, optsErr.Problems)
+		})
+	}
+}
+
+func TestBuilder_BuildRecompute(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(false)
+	b.app.options.Recompute = RecomputeOptions{From: "2025-01-01T00:00:00Z", Window: time.Hour}
+
+	job, err := b.WithLogger(context.Background()).BuildRecompute()
+	require.NoError(t, err)
+
+	result, err := job.Run(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, result.Ticks, "the memory repository stores no ticks")
+	assert.NoError(t, job.Close(context.Background()))
+}
+
+func TestOptions_ValidateDataDiff(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		modify       func(o *Options)
+		wantProblems []string
+	}{
+		{
+			name:   "valid options",
+			modify: func(o *Options) {},
+		},
+		{
+			name: "missing repositories",
+			modify: func(o *Options) {
+				o.Repository.Sqlite.Enabled = false
+				o.DataDiff.Against.Repository.Mongo.Enabled = false
+			},
+			wantProblems: []string{
+				"REPOSITORY_*_ENABLED: no repository enabled, enable mongo, postgres, sqlite",
+				"DATADIFF_AGAINST_REPOSITORY_*_ENABLED: no repository to compare against enabled, enable mongo, postgres, sqlite",
+			},
+		},
+		{
+			name: "postgres repositories",
+			modify: func(o *Options) {
+				o.Repository.Sqlite.Enabled = false
+				o.Repository.Postgres.Enabled = true
+				o.Repository.Postgres.URL = "postgres://localhost:5432/exchange"
+				o.DataDiff.Against.Repository.Mongo.Enabled = false
+				o.DataDiff.Against.Repository.Postgres.Enabled = true
+				o.DataDiff.Against.Repository.Postgres.URL = "postgres://replica:5432/exchange"
+			},
+		},
+		{
+			name: "invalid settings",
+			modify: func(o *Options) {
+				o.DataDiff.Against.Repository.Mongo.URL = ""
+				o.DataDiff.From = "2025-01-03T00:00:00Z"
+				o.DataDiff.To = ""
+				o.DataDiff.Window = 0
+				o.DataDiff.MaxReported = 0
+			},
+			wantProblems: []string{
+				"DATADIFF_AGAINST_REPOSITORY_MONGO_URL: required when the mongo repository is enabled",
+				"DATADIFF_FROM: must be before DATADIFF_TO, got 2025-01-03T00:00:00Z - 2025-01-02T00:00:00Z",
+				"DATADIFF_WINDOW: must be positive, got 0s",
+				"DATADIFF_MAX_REPORTED: must be positive, got 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newTestOptions(false)
+			opts.Repository.Sqlite.Enabled = true
+			opts.Repository.Sqlite.Path = "exchange.db"
+			opts.DataDiff = DataDiffOptions{From: "2025-01-01T00:00:00Z", To: "2025-01-01T12:00:00Z", Window: time.Hour, MaxReported: 100}
+			opts.DataDiff.Against.Repository.Mongo.Enabled = true
+			opts.DataDiff.Against.Repository.Mongo.URL = "mongodb://localhost:27017"
+			tt.modify(opts)
+
+			err := opts.ValidateDataDiff(now)
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var optsErr *OptionsError
+			require.ErrorAs(t, err, &optsErr)
+			assert.Equal(t, tt.wantProblems, optsErr.Problems)
+		})
+	}
+}
+
+func TestBuilder_BuildDataDiff(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(false)
+	b.app.options.DataDiff = DataDiffOptions{From: "2025-01-01T00:00:00Z", Window: time.Hour}
+
+	_, err := b.WithLogger(context.Background()).BuildDataDiff(context.Background())
+	assert.EqualError(t, err, "no repository to compare against configured")
+
+	ticks, liquidations, err := dataDiffRepositories(b.app.repositoryFactory, b.repositoryKind, "test-service")
+	require.NoError(t, err, "the memory repository reads liquidations back")
+	assert.NotNil(t, ticks)
+	assert.NotNil(t, liquidations)
+}
+
+func TestOptions_ValidateLeaderboard(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(o *Options)
+		wantProblems []string
+	}{
+		{
+			name:   "valid options",
+			modify: func(o *Options) {},
+		},
+		{
+			name: "no repository",
+			modify: func(o *Options) {
+				o.ServiceName = ""
+				o.Repository.Sqlite.Enabled = false
+			},
+			wantProblems: []string{
+				"SERVICE_NAME: required to locate the stored data",
+				"REPOSITORY_*_ENABLED: no repository enabled, enable mongo or sqlite",
+			},
+		},
+		{
+			name: "empty leaderboards",
+			modify: func(o *Options) {
+				o.Leaderboard.Window = 0
+				o.Leaderboard.Limit = 0
+			},
+			wantProblems: []string{
+				"LEADERBOARD_WINDOW: must be positive, got 0s",
+				"LEADERBOARD_LIMIT: must be positive, got 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newTestOptions(false)
+			opts.Repository.Sqlite.Enabled = true
+			opts.Repository.Sqlite.Path = "exchange.db"
+			opts.Leaderboard = LeaderboardOptions{Window: time.Hour, Limit: 10, Format: "text"}
+			tt.modify(opts)
+
+			err := opts.ValidateLeaderboard()
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var optsErr *OptionsError
+			require.ErrorAs(t, err, &optsErr)
+			assert.Equal(t, tt.wantProblems, optsErr.Problems)
+		})
+	}
+}
+
+func TestBuilder_BuildLeaderboard(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(false)
+	b.app.options.Leaderboard = LeaderboardOptions{Window: time.Hour, Limit: 5, Format: "json"}
+
+	job, err := b.WithLogger(context.Background()).BuildLeaderboard(context.Background())
+	require.NoError(t, err, "the memory repository ranks liquidations")
+	assert.Equal(t, "json", job.Format())
+
+	report, err := job.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test-service", report.Exchange)
+	assert.Empty(t, report.TopSymbols.Symbols)
+	assert.NoError(t, job.Close(context.Background()))
+}
+
+func TestOptions_ValidateCheck(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(o *Options)
+		wantProblems []string
+	}{
+		{
+			name:   "valid options",
+			modify: func(o *Options) {},
+		},
+		{
+			name: "importer problems",
+			modify: func(o *Options) {
+				o.Exchange.Binance.Enabled = false
+				o.Notify.Stdout.Topics = "TICKS"
+			},
+			wantProblems: []string{
+				"EXCHANGE_*_ENABLED: no exchange enabled, enable at least one of binance, bybit, okx, coinbase",
+				`NOTIFY_STDOUT_TOPICS: unknown topic "TICKS" (valid: MARKET_DATA, ALERT_MARKET_STATE, TICK_INFO, TIME_SERIES, OPS_ALERT, MINUTE_BARS, ANNOTATIONS)`,
+			},
+		},
+		{
+			name: "no check time",
+			modify: func(o *Options) {
+				o.Check.Timeout = 0
+				o.Check.Liquidations = -time.Second
+			},
+			wantProblems: []string{
+				"CHECK_TIMEOUT: must be positive, got 0s",
+				"CHECK_LIQUIDATIONS: must be positive, got -1s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newTestOptions(true)
+			opts.Check = CheckOptions{Timeout: 10 * time.Second, Liquidations: 10 * time.Second, Format: "text"}
+			tt.modify(opts)
+
+			err := opts.ValidateCheck()
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var optsErr *OptionsError
+			require.ErrorAs(t, err, &optsErr)
+			assert.Equal(t, tt.wantProblems, optsErr.Problems)
+		})
+	}
+}
+
+func TestBuilder_BuildCheck(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.app.options.Check = CheckOptions{Timeout: time.Second, Liquidations: time.Second, Format: "json"}
+	b.app.options.Notify.Stdout.Topics = "TICK_INFO,OPS_ALERT"
+	b.app.options.Notify.Shadow.Topics = "MARKET_DATA"
+
+	job, err := b.WithLogger(context.Background()).BuildCheck(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "json", job.Format())
+
+	var probes []string
+	for _, probe := range job.probes {
+		probes = append(probes, probe.Name+" "+probe.Target)
+	}
+	assert.Equal(t, []string{
+		"tickers test-service",
+		"liquidations test-service",
+		"notification test-service/stdout:TICK_INFO",
+	}, probes, "the memory repository and shadow subscriptions are not probed, shared clients are probed once")
+	assert.NoError(t, job.Close(context.Background()))
+}
+
+func TestBuilder_CheckEvent(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(true)
+	b.WithExchange(context.Background())
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "Self-check of test-service (test-service): test notification", b.checkEvent("telegram", at).Data)
+	assert.Equal(t, fmt.Sprintf("importer_self_check,exchange=test-service ok=1i %d", at.UnixNano()), b.checkEvent("influx", at).Data)
+
+	webhook, ok := b.checkEvent("webhook", at).Data.(notificationStrategies.AlertmanagerMessage)
+	require.True(t, ok)
+	assert.Equal(t, "resolved", webhook.Status, "the test alert never fires")
+	assert.Equal(t, opsalert.NameSelfCheck, webhook.GroupLabels[opsalert.LabelAlertName])
+
+	grafana, ok := b.checkEvent("grafana", at).Data.(notificationStrategies.GrafanaAnnotation)
+	require.True(t, ok)
+	assert.Equal(t, "Resolved: Importer self-check", grafana.Text)
+}
+
+func TestOptions_ValidateSoak(t *testing.T) {
+	tests := []struct {
+		name         string
+		modify       func(o *Options)
+		wantProblems []string
+	}{
+		{
+			name:   "valid options",
+			modify: func(o *Options) {},
+		},
+		{
+			name:         "no notifier",
+			modify:       func(o *Options) { o.Notify.Stdout.Topics = "" },
+			wantProblems: []string{"NOTIFY_*_TOPICS: no notifier configured, set the topics of at least one"},
+		},
+		{
+			name: "invalid load",
+			modify: func(o *Options) {
+				o.Soak.Rate = 0
+				o.Soak.Symbols = -1
+				o.Soak.Duration = 0
+				o.Soak.ReportInterval = -time.Second
+			},
+			wantProblems: []string{
+				"SOAK_RATE: must be positive, got 0",
+				"SOAK_SYMBOLS: must be positive, got -1",
+				"SOAK_DURATION: must be positive, got 0s",
+				"SOAK_REPORT_INTERVAL: must not be negative, got -1s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := newTestOptions(false)
+			opts.Notify.Stdout.Topics = "TICK_INFO"
+			opts.Soak = SoakOptions{Rate: 1, Symbols: 10, Duration: time.Minute, ReportInterval: time.Second}
+			tt.modify(opts)
+
+			err := opts.ValidateSoak()
+			if len(tt.wantProblems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var optsErr *OptionsError
+			require.ErrorAs(t, err, &optsErr)
+			assert.Equal(t, tt.wantProblems, optsErr.Problems)
+		})
+	}
+}
+
+func TestOptions_ValidateDescribe(t *testing.T) {
+	opts := newTestOptions(false)
+	opts.Precision = PrecisionOptions{Change: 2, RSI: 1, AvgQuote: 4, AvgTrend: 2, AvgBuy10: 6, Notional: -1}
+	assert.NoError(t, opts.ValidateDescribe())
+
+	opts.Precision.AvgQuote = 13
+	var optsErr *OptionsError
+	require.ErrorAs(t, opts.ValidateDescribe(), &optsErr)
+	assert.Equal(t, []string{"PRECISION_AVG_QUOTE: must be between -1 (unrounded) and 12 decimals, got 13"}, optsErr.Problems)
+}
+
+func TestOptionsError_Error(t *testing.T) {
+	err := &OptionsError{Problems: []string{"A: first", "B: second"}}
+	assert.Equal(t, "invalid configuration:\n  - A: first\n  - B: second", err.Error())
+}
+
+func TestBuilder_ValidateOptions(t *testing.T) {
+	b := NewBuilder()
+	b.app.options = newTestOptions(false)
+
+	_, err := b.ValidateOptions().WithLogger(context.Background()).Build()
+
+	var optsErr *OptionsError
+	assert.ErrorAs(t, err, &optsErr)
+}
@@ -0,0 +1,161 @@
+package composite
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"go.uber.org/zap"
+)
+
+// Defaults for Config
+const (
+	DefaultMaxAge     = 5 * time.Second
+	DefaultMinSources = 2
+)
+
+// Config holds the configuration of the Compositor
+type Config struct {
+	Exchange   string                           // name of the exchange imported by this process
+	Peers      map[string]domain.TickRepository // ticks stored by the importers of other exchanges, keyed by exchange name
+	Output     domain.CompositePriceRepository
+	MaxAge     time.Duration // peer ticks older than this are left out, 0 uses DefaultMaxAge
+	MinSources int           // exchanges required to build a composite, 0 uses DefaultMinSources
+	Logger     *zap.Logger
+}
+
+// Compositor builds composite index prices from the ticks of the local exchange and the latest ticks
+// the importers of the other exchanges stored, and keeps the last ones as a fair price reference
+type Compositor struct {
+	exchange   string
+	peers      map[string]domain.TickRepository
+	output     domain.CompositePriceRepository
+	maxAge     time.Duration
+	minSources int
+	telemetry  telemetry.Provider
+	logger     *zap.Logger
+
+	peerTicks map[string]*domain.Tick // latest tick loaded per peer, only used by Handle
+
+	mu     sync.RWMutex
+	latest map[domain.TickerName]domain.CompositePrice
+}
+
+// New creates a new Compositor
+func New(cfg Config) *Compositor {
+	c := &Compositor{
+		exchange:   cfg.Exchange,
+		peers:      cfg.Peers,
+		output:     cfg.Output,
+		maxAge:     cfg.MaxAge,
+		minSources: cfg.MinSources,
+		telemetry:  &telemetry.NoopProvider{},
+		logger:     cfg.Logger,
+		peerTicks:  make(map[string]*domain.Tick, len(cfg.Peers)),
+		latest:     make(map[domain.TickerName]domain.CompositePrice),
+	}
+	if c.maxAge <= 0 {
+		c.maxAge = DefaultMaxAge
+	}
+	if c.minSources <= 0 {
+		c.minSources = DefaultMinSources
+	}
+	if c.logger == nil {
+		c.logger = zap.NewNop()
+	}
+	c.logger = c.logger.With(zap.String("component", "composite"))
+	return c
+}
+
+// WithTelemetry sets the telemetry provider
+func (c *Compositor) WithTelemetry(provider telemetry.Provider) *Compositor {
+	if provider != nil {
+		c.telemetry = provider
+	}
+	return c
+}
+
+// Handle builds and stores the composite prices for a built tick, it matches eventbus.Handler
+func (c *Compositor) Handle(ctx context.Context, event eventbus.Event) {
+	if event.Type != eventbus.TickBuilt {
+		return
+	}
+	tick, ok := event.Payload.(*domain.Tick)
+	if !ok || tick == nil {
+		return
+	}
+	start := time.Now()
+
+	now := tick.CreatedAt
+	if now.IsZero() {
+		now = event.Time
+	}
+	sources := append(c.loadPeers(ctx, now), Source{Exchange: c.exchange, Tick: tick})
+	prices := Build(now, sources, c.minSources)
+
+	latest := make(map[domain.TickerName]domain.CompositePrice, len(prices))
+	for _, price := range prices {
+		latest[price.Symbol] = price
+	}
+	c.mu.Lock()
+	c.latest = latest
+	c.mu.Unlock()
+
+	if err := c.output.CreateMany(ctx, prices); err != nil {
+		c.telemetry.IncrementCounter(telemetryCompositeErrors, 1, "stage:store")
+		c.logger.Error("Failed to store composite prices", zap.Error(err))
+	}
+
+	c.telemetry.Gauge(telemetryCompositeSymbols, float64(len(prices)))
+	c.telemetry.Gauge(telemetryCompositeSources, float64(len(sources)))
+	c.telemetry.Timing(telemetryCompositeDuration, time.Since(start))
+}
+
+// loadPeers returns the peer ticks created within maxAge before now. Only ticks newer than the
+// last loaded one are queried, a peer failing to load keeps its last tick until it gets stale
+func (c *Compositor) loadPeers(ctx context.Context, now time.Time) []Source {
+	staleBefore := now.Add(-c.maxAge)
+
+	var sources []Source
+	for exchange, repo := range c.peers {
+		since := staleBefore
+		if last := c.peerTicks[exchange]; last != nil && last.CreatedAt.After(since) {
+			since = last.CreatedAt.Add(time.Millisecond) // stored times are truncated to milliseconds
+		}
+
+		ticks, err := repo.GetHistorySince(ctx, since)
+		if err != nil {
+			c.telemetry.IncrementCounter(telemetryCompositeErrors, 1, "stage:load")
+			c.logger.Warn("Failed to load peer ticks", zap.String("peer", exchange), zap.Error(err))
+		} else if len(ticks) > 0 {
+			c.peerTicks[exchange] = &ticks[len(ticks)-1]
+		}
+
+		if last := c.peerTicks[exchange]; last != nil && !last.CreatedAt.Before(staleBefore) {
+			sources = append(sources, Source{Exchange: exchange, Tick: last})
+		}
+	}
+	return sources
+}
+
+// Latest returns the last composite price of a canonical symbol
+func (c *Compositor) Latest(symbol domain.TickerName) (domain.CompositePrice, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	price, ok := c.latest[symbol]
+	return price, ok
+}
+
+// FairPrice returns the last composite price of the canonical symbol of an exchange symbol,
+// in the quote asset of the symbol
+func (c *Compositor) FairPrice(symbol domain.TickerName) (float64, bool) {
+	canonical, ok := Canonical(symbol)
+	if !ok {
+		return 0, false
+	}
+	price, ok := c.Latest(canonical)
+	return price.Price, ok
+}
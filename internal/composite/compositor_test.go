@@ -0,0 +1,111 @@
+package composite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositor_Handle(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	peerTicks := []domain.Tick{
+		*tickOf(start, &domain.Ticker{Symbol: "BTC-USDT-SWAP", Ask: 105, Bid: 103}),
+	}
+	peer := &domainMocks.TickRepositoryMock{
+		GetHistorySinceFunc: func(ctx context.Context, since time.Time) ([]domain.Tick, error) {
+			var result []domain.Tick
+			for _, tick := range peerTicks {
+				if !tick.CreatedAt.Before(since) {
+					result = append(result, tick)
+				}
+			}
+			return result, nil
+		},
+	}
+	var stored [][]domain.CompositePrice
+	output := &domainMocks.CompositePriceRepositoryMock{
+		CreateManyFunc: func(ctx context.Context, prices []domain.CompositePrice) error {
+			stored = append(stored, prices)
+			return nil
+		},
+	}
+	compositor := New(Config{
+		Exchange: "binance",
+		Peers:    map[string]domain.TickRepository{"okx": peer},
+		Output:   output,
+		MaxAge:   5 * time.Second,
+	})
+
+	handle := func(at time.Time) {
+		compositor.Handle(context.Background(), eventbus.Event{
+			Type:    eventbus.TickBuilt,
+			Time:    at,
+			Payload: tickOf(at, &domain.Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 99}),
+		})
+	}
+
+	handle(start.Add(time.Second))
+	require.Len(t, stored, 1)
+	require.Len(t, stored[0], 1)
+	assert.InDelta(t, 102, stored[0][0].Price, 1e-9)
+
+	fair, ok := compositor.FairPrice("BTC-USDT-SWAP")
+	assert.True(t, ok)
+	assert.InDelta(t, 102, fair, 1e-9)
+	_, ok = compositor.FairPrice("ETHUSDT")
+	assert.False(t, ok)
+
+	handle(start.Add(2 * time.Second))
+	calls := peer.GetHistorySinceCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, start.Add(-4*time.Second), calls[0].Since)
+	assert.Equal(t, start.Add(time.Millisecond), calls[1].Since, "only ticks newer than the loaded one are queried")
+	require.Len(t, stored, 2)
+	assert.Len(t, stored[1], 1, "the last peer tick is reused while fresh")
+
+	handle(start.Add(6 * time.Second))
+	require.Len(t, stored, 3)
+	assert.Empty(t, stored[2], "the stale peer tick is left out")
+	_, ok = compositor.Latest("BTCUSDT")
+	assert.False(t, ok)
+}
+
+func TestCompositor_HandleErrors(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repoErr := errors.New("db is down")
+
+	compositor := New(Config{
+		Exchange: "binance",
+		Peers: map[string]domain.TickRepository{
+			"okx": &domainMocks.TickRepositoryMock{
+				GetHistorySinceFunc: func(ctx context.Context, since time.Time) ([]domain.Tick, error) {
+					return nil, repoErr
+				},
+			},
+		},
+		Output: &domainMocks.CompositePriceRepositoryMock{
+			CreateManyFunc: func(ctx context.Context, prices []domain.CompositePrice) error {
+				return repoErr
+			},
+		},
+		MinSources: 1,
+	})
+
+	compositor.Handle(context.Background(), eventbus.Event{
+		Type:    eventbus.TickBuilt,
+		Time:    at,
+		Payload: tickOf(at, &domain.Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 99}),
+	})
+
+	price, ok := compositor.Latest("BTCUSDT")
+	require.True(t, ok, "the local tick alone satisfies MinSources and failures to store keep the reference")
+	assert.InDelta(t, 100, price.Price, 1e-9)
+}
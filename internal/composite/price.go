@@ -0,0 +1,89 @@
+package composite
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+// Source is the latest tick built for one exchange
+type Source struct {
+	Exchange string
+	Tick     *domain.Tick
+}
+
+// Canonical returns the exchange independent name of a symbol, its base and quote assets concatenated, see
+// exchanges.SymbolMapper. The quotes aren't normalized, composite prices are kept in the quote asset of the symbol
+func Canonical(symbol domain.TickerName) (domain.TickerName, bool) {
+	canonical, ok := exchanges.SymbolMapper{}.MapSymbol(string(symbol))
+	return domain.TickerName(canonical), ok
+}
+
+// Build combines the tickers of the sources into a composite price per canonical symbol listed by at least
+// minSources exchanges. Sources are weighted by their top of the book USD notional, or equally when any of
+// them has no known notional. Illiquid tickers are left out
+func Build(at time.Time, sources []Source, minSources int) []domain.CompositePrice {
+	bySymbol := make(map[domain.TickerName][]domain.CompositeSource)
+	notionals := make(map[domain.TickerName][]float64)
+	for _, src := range sources {
+		if src.Tick == nil {
+			continue
+		}
+		for _, ticker := range src.Tick.Data {
+			if ticker == nil || ticker.Illiquid || ticker.Ask <= 0 || ticker.Bid <= 0 {
+				continue
+			}
+			symbol, ok := Canonical(ticker.Symbol)
+			if !ok {
+				continue
+			}
+
+			// prices normalized to USD are converted back to the quote asset of the canonical symbol
+			mid := (ticker.Ask + ticker.Bid) / 2
+			if ticker.QuoteRate > 0 {
+				mid /= ticker.QuoteRate
+			}
+			bySymbol[symbol] = append(bySymbol[symbol], domain.CompositeSource{
+				Exchange: src.Exchange,
+				Symbol:   ticker.Symbol,
+				Price:    mid,
+			})
+			notionals[symbol] = append(notionals[symbol], ticker.Notional)
+		}
+	}
+
+	prices := make([]domain.CompositePrice, 0, len(bySymbol))
+	for symbol, contributions := range bySymbol {
+		if len(contributions) < max(minSources, 1) {
+			continue
+		}
+
+		weights := notionals[symbol]
+		if slices.Contains(weights, 0) {
+			weights = slices.Repeat([]float64{1}, len(contributions))
+		}
+		var total float64
+		for _, w := range weights {
+			total += w
+		}
+
+		var price float64
+		for i := range contributions {
+			contributions[i].Weight = weights[i] / total
+			price += contributions[i].Price * contributions[i].Weight
+		}
+		slices.SortFunc(contributions, func(a, b domain.CompositeSource) int { return strings.Compare(a.Exchange, b.Exchange) })
+
+		prices = append(prices, domain.CompositePrice{
+			Symbol:    symbol,
+			CreatedAt: at,
+			Price:     price,
+			Sources:   contributions,
+		})
+	}
+	slices.SortFunc(prices, func(a, b domain.CompositePrice) int { return strings.Compare(string(a.Symbol), string(b.Symbol)) })
+	return prices
+}
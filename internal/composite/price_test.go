@@ -0,0 +1,107 @@
+package composite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		symbol domain.TickerName
+		want   domain.TickerName
+		wantOk bool
+	}{
+		{symbol: "BTCUSDT", want: "BTCUSDT", wantOk: true},
+		{symbol: "ETHBTC", want: "ETHBTC", wantOk: true},
+		{symbol: "BTCUSD", want: "BTCUSD", wantOk: true},
+		{symbol: "BTC-USDT", want: "BTCUSDT", wantOk: true},
+		{symbol: "BTC-USDT-SWAP", want: "BTCUSDT", wantOk: true},
+		{symbol: "BTC-USD-SWAP", want: "BTCUSD", wantOk: true},
+		{symbol: "BTC-USD-250328", wantOk: false},
+		{symbol: "BTC-USD-250328-90000-C", wantOk: false},
+		{symbol: "BTC-28MAR25", wantOk: false},
+		{symbol: "BTCUSDT_250328", wantOk: false},
+		{symbol: "USDT", wantOk: false},
+		{symbol: "XYZ", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.symbol), func(t *testing.T) {
+			got, ok := Canonical(tt.symbol)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// tickOf returns a tick holding the given tickers
+func tickOf(at time.Time, tickers ...*domain.Ticker) *domain.Tick {
+	tick := &domain.Tick{CreatedAt: at, Data: make(map[domain.TickerName]*domain.Ticker)}
+	for _, ticker := range tickers {
+		tick.Data[ticker.Symbol] = ticker
+	}
+	return tick
+}
+
+func TestBuild(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("weighted by notional", func(t *testing.T) {
+		prices := Build(at, []Source{
+			{Exchange: "binance", Tick: tickOf(at, &domain.Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 99, Notional: 3000})},
+			{Exchange: "okx", Tick: tickOf(at, &domain.Ticker{Symbol: "BTC-USDT-SWAP", Ask: 105, Bid: 103, Notional: 1000})},
+		}, 2)
+
+		require.Len(t, prices, 1)
+		assert.Equal(t, domain.TickerName("BTCUSDT"), prices[0].Symbol)
+		assert.Equal(t, at, prices[0].CreatedAt)
+		assert.InDelta(t, 101, prices[0].Price, 1e-9)
+		assert.Equal(t, []domain.CompositeSource{
+			{Exchange: "binance", Symbol: "BTCUSDT", Price: 100, Weight: 0.75},
+			{Exchange: "okx", Symbol: "BTC-USDT-SWAP", Price: 104, Weight: 0.25},
+		}, prices[0].Sources)
+	})
+
+	t.Run("equal weights when a notional is unknown", func(t *testing.T) {
+		prices := Build(at, []Source{
+			{Exchange: "binance", Tick: tickOf(at, &domain.Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 99, Notional: 3000})},
+			{Exchange: "okx", Tick: tickOf(at, &domain.Ticker{Symbol: "BTC-USDT-SWAP", Ask: 105, Bid: 103})},
+		}, 2)
+
+		require.Len(t, prices, 1)
+		assert.InDelta(t, 102, prices[0].Price, 1e-9)
+	})
+
+	t.Run("normalized prices are converted back to the quote asset", func(t *testing.T) {
+		prices := Build(at, []Source{
+			{Exchange: "binance", Tick: tickOf(at, &domain.Ticker{Symbol: "ETHBTC", Ask: 101, Bid: 99, QuoteRate: 2000})},
+			{Exchange: "okx", Tick: tickOf(at, &domain.Ticker{Symbol: "ETH-BTC", Ask: 0.06, Bid: 0.04})},
+		}, 2)
+
+		require.Len(t, prices, 1)
+		assert.InDelta(t, 0.05, prices[0].Price, 1e-9)
+	})
+
+	t.Run("skips symbols below the minimum of sources and illiquid tickers", func(t *testing.T) {
+		prices := Build(at, []Source{
+			{Exchange: "binance", Tick: tickOf(at,
+				&domain.Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 99},
+				&domain.Ticker{Symbol: "ETHUSDT", Ask: 11, Bid: 9},
+				&domain.Ticker{Symbol: "BTCUSDT_250328", Ask: 111, Bid: 109},
+			)},
+			{Exchange: "okx", Tick: tickOf(at,
+				&domain.Ticker{Symbol: "BTC-USDT-SWAP", Ask: 105, Bid: 103},
+				&domain.Ticker{Symbol: "ETH-USDT-SWAP", Ask: 11, Bid: 9, Illiquid: true},
+			)},
+			{Exchange: "bybit"},
+		}, 2)
+
+		require.Len(t, prices, 1)
+		assert.Equal(t, domain.TickerName("BTCUSDT"), prices[0].Symbol)
+		assert.Len(t, prices[0].Sources, 2)
+	})
+}
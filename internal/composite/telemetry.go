@@ -0,0 +1,28 @@
+package composite
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for gauges, counters and timings
+const (
+	// telemetryCompositeSymbols reports the number of canonical symbols with a composite price
+	telemetryCompositeSymbols = "composite.symbols"
+
+	// telemetryCompositeSources reports the number of exchanges with a fresh tick
+	telemetryCompositeSources = "composite.sources"
+
+	// telemetryCompositeErrors counts failures to load peer ticks or store composite prices
+	telemetryCompositeErrors = "composite.errors"
+
+	// telemetryCompositeDuration measures loading peer ticks, building and storing the composite prices
+	telemetryCompositeDuration = "composite.duration"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryCompositeSymbols, Kind: telemetry.KindGauge, Description: "Canonical symbols with a composite price"},
+		{Name: telemetryCompositeSources, Kind: telemetry.KindGauge, Description: "Exchanges contributing to the composite prices"},
+		{Name: telemetryCompositeErrors, Kind: telemetry.KindCounter, Description: "Composite price failures", Tags: []string{"stage"}},
+		{Name: telemetryCompositeDuration, Kind: telemetry.KindTiming, Description: "Composite price build duration"},
+	}
+}
@@ -0,0 +1,197 @@
+// Package dictionary describes the stored records field by field: storage name, meaning, unit, window and
+// the decimals they are rounded to with the configured precision
+package dictionary
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// Record describes a stored record
+type Record struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Fields      []Field `json:"fields"`
+}
+
+// Field describes a field of a stored record, nested fields are named by their dotted path
+type Field struct {
+	Name     string `json:"name"`               // storage name, e.g. avg.a_pd
+	GoName   string `json:"go_name"`            // struct and field of the domain type, e.g. TickAvg.AskChange
+	Type     string `json:"type"`               // JSON type of the stored value
+	Meaning  string `json:"meaning"`            // what the value is
+	Unit     string `json:"unit,omitempty"`     // unit of the value
+	Window   string `json:"window,omitempty"`   // period the value is computed over
+	Decimals *int   `json:"decimals,omitempty"` // decimals the value is rounded to, nil when stored as received
+}
+
+// symbolKey names the keys of the tickers map in field paths
+const symbolKey = "<symbol>"
+
+// Describe returns the dictionary of the ticks, liquidations, bars, funding rates, trades and symbols with the decimals
+// of precision
+func Describe(precision domain.Precision) []Record {
+	return []Record{
+		{
+			Name:        "tick",
+			Description: fmt.Sprintf("Market snapshot of a tick, indicators version %d", domain.IndicatorsVersion),
+			Fields:      describe(reflect.TypeFor[domain.Tick](), "", precision),
+		},
+		{
+			Name:        "liquidation",
+			Description: "Forced order streamed from the exchange or an external provider",
+			Fields:      describe(reflect.TypeFor[domain.Liquidation](), "", precision),
+		},
+		{
+			Name:        "bar",
+			Description: "Minute OHLC bar of the mid price of a ticker",
+			Fields:      describe(reflect.TypeFor[domain.Bar](), "", precision),
+		},
+		{
+			Name:        "funding_rate",
+			Description: "Funding rate snapshot of a perpetual futures symbol",
+			Fields:      describe(reflect.TypeFor[domain.FundingRate](), "", precision),
+		},
+		{
+			Name:        "trade",
+			Description: "Aggregated trade of a symbol of TRADES_SYMBOLS",
+			Fields:      describe(reflect.TypeFor[domain.Trade](), "", precision),
+		},
+		{
+			Name:        "symbol",
+			Description: "Record of a symbol of the exchange in the registry enabled by SYMBOLS_REGISTRY",
+			Fields:      describe(reflect.TypeFor[domain.Symbol](), "", precision),
+		},
+	}
+}
+
+// describe lists the stored fields of a struct and of the structs nested in it
+func describe(t reflect.Type, prefix string, precision domain.Precision) []Field {
+	var fields []Field
+	for sf := range fieldsOf(t) {
+		name, ok := storageName(sf)
+		if !ok {
+			continue
+		}
+		goName := t.Name() + "." + sf.Name
+		e := registry[goName]
+		field := Field{
+			Name:    prefix + name,
+			GoName:  goName,
+			Type:    typeName(sf.Type),
+			Meaning: e.meaning,
+			Unit:    e.unit,
+			Window:  e.window,
+		}
+		if e.decimals != nil && e.decimals(precision) >= 0 {
+			decimals := e.decimals(precision)
+			field.Decimals = &decimals
+		}
+		fields = append(fields, field)
+
+		switch nested := indirect(sf.Type); {
+		case nested.Kind() == reflect.Struct && nested != reflect.TypeFor[time.Time]():
+			fields = append(fields, describe(nested, field.Name+".", precision)...)
+		case nested.Kind() == reflect.Map && indirect(nested.Elem()).Kind() == reflect.Struct:
+			fields = append(fields, describe(indirect(nested.Elem()), field.Name+"."+symbolKey+".", precision)...)
+		case nested.Kind() == reflect.Slice && indirect(nested.Elem()).Kind() == reflect.Struct:
+			fields = append(fields, describe(indirect(nested.Elem()), field.Name+"[].", precision)...)
+		}
+	}
+	return fields
+}
+
+// fieldsOf yields the exported fields of a struct in declaration order
+func fieldsOf(t reflect.Type) func(yield func(reflect.StructField) bool) {
+	return func(yield func(reflect.StructField) bool) {
+		for i := range t.NumField() {
+			if sf := t.Field(i); sf.IsExported() && !yield(sf) {
+				return
+			}
+		}
+	}
+}
+
+// storageName returns the name a field is stored under, from its json tag
+func storageName(sf reflect.StructField) (string, bool) {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return sf.Name, true
+	}
+	return name, true
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// typeName returns the JSON type of a stored value
+func typeName(t reflect.Type) string {
+	t = indirect(t)
+	switch {
+	case t == reflect.TypeFor[time.Time]():
+		return "time"
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "bytes"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		return "[]" + typeName(t.Elem())
+	case reflect.Map:
+		return "map"
+	default:
+		return "object"
+	}
+}
+
+// Print writes the records as aligned tables
+func Print(w io.Writer, records []Record) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, record := range records {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "%s: %s\n", strings.ToUpper(record.Name), record.Description)
+		fmt.Fprintln(tw, "FIELD\tTYPE\tUNIT\tWINDOW\tDECIMALS\tMEANING")
+		for _, f := range record.Fields {
+			decimals := "-"
+			if f.Decimals != nil {
+				decimals = strconv.Itoa(*f.Decimals)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", f.Name, f.Type, dash(f.Unit), dash(f.Window), decimals, f.Meaning)
+		}
+		// flush per record so the columns of a record do not widen the others
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("print %s: %w", record.Name, err)
+		}
+	}
+	return nil
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package dictionary
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+func TestDescribe_EveryFieldRegistered(t *testing.T) {
+	described := make(map[string]bool)
+	for _, record := range Describe(domain.DefaultPrecision()) {
+		for _, f := range record.Fields {
+			described[f.GoName] = true
+			assert.NotEmpty(t, f.Meaning, "%s (%s) has no registry entry", f.Name, f.GoName)
+		}
+	}
+	for goName := range registry {
+		assert.True(t, described[goName], "registry entry %s does not match a stored field", goName)
+	}
+}
+
+func TestDescribe_Fields(t *testing.T) {
+	records := Describe(domain.DefaultPrecision())
+	require.Len(t, records, 6)
+
+	fields := make(map[string]Field)
+	for _, f := range records[0].Fields {
+		fields[f.Name] = f
+	}
+
+	ll2 := fields["ll_2"]
+	assert.Equal(t, "integer", ll2.Type)
+	assert.Equal(t, "2s before start_at", ll2.Window)
+	assert.Nil(t, ll2.Decimals)
+
+	avgAskChange := fields["avg_w.a_pd"]
+	assert.Equal(t, "TickAvg.AskChange", avgAskChange.GoName)
+	require.NotNil(t, avgAskChange.Decimals)
+	assert.Equal(t, 4, *avgAskChange.Decimals)
+
+	assert.Contains(t, fields, "data.<symbol>.s24.ln")
+	assert.Equal(t, "time", fields["start_at"].Type)
+	assert.Equal(t, "[]string", fields["suspect"].Type)
+}
+
+func TestDescribe_Precision(t *testing.T) {
+	precision := domain.DefaultPrecision()
+	precision.RSI = 3
+	precision.Notional = -1
+
+	fields := make(map[string]Field)
+	for _, f := range Describe(precision)[0].Fields {
+		fields[f.Name] = f
+	}
+
+	require.NotNil(t, fields["data.<symbol>.rsi_20"].Decimals)
+	assert.Equal(t, 3, *fields["data.<symbol>.rsi_20"].Decimals)
+	assert.Nil(t, fields["data.<symbol>.n"].Decimals, "unrounded fields have no decimals")
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, Describe(domain.DefaultPrecision())))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "TICK: "))
+	assert.Contains(t, out, "LIQUIDATION: ")
+	assert.Contains(t, out, "BAR: ")
+	assert.Regexp(t, `ll_2\s+integer\s+count\s+2s before start_at\s+-\s+Long liquidations`, out)
+}
@@ -0,0 +1,173 @@
+package dictionary
+
+import "github.com/ayankousky/exchange-data-importer/internal/domain"
+
+// Units of the described fields
+const (
+	unitPercent = "%"
+	unitUSD     = "USD"
+	unitQuote   = "quote asset"
+	unitBase    = "base asset"
+	unitCount   = "count"
+	unitMillis  = "ms"
+	unitUTC     = "UTC time"
+	unitRatio   = "fraction"
+)
+
+// entry is the registry description of a stored field, keyed by "<struct>.<field>" of the Go type
+type entry struct {
+	meaning  string
+	unit     string
+	window   string
+	decimals func(domain.Precision) int // nil for fields stored as received or not rounded
+}
+
+func change(p domain.Precision) int   { return p.Change }
+func rsi(p domain.Precision) int      { return p.RSI }
+func avgQuote(p domain.Precision) int { return p.AvgQuote }
+func avgTrend(p domain.Precision) int { return p.AvgTrend }
+func avgBuy10(p domain.Precision) int { return p.AvgBuy10 }
+func notional(p domain.Precision) int { return p.Notional }
+
+// registry describes every stored field, a test fails when a field of a described struct is missing
+var registry = map[string]entry{
+	"Tick.StartAt":           {meaning: "Start of the tick, the liquidation windows end here", unit: unitUTC},
+	"Tick.FetchedAt":         {meaning: "Time the tickers were received from the exchange", unit: unitUTC},
+	"Tick.CreatedAt":         {meaning: "Time the tick was built", unit: unitUTC},
+	"Tick.FetchDuration":     {meaning: "Time taken to fetch the tickers", unit: unitMillis},
+	"Tick.HandlingDuration":  {meaning: "Time taken to build the tick once fetched", unit: unitMillis},
+	"Tick.EventSkew":         {meaning: "Median of fetched_at minus the exchange timestamps of the tickers, negative when the exchange clock runs ahead", unit: unitMillis},
+	"Tick.AvgBuy10":          {meaning: "Mean of the market average ask change (avg.a_pd) of the last 10 ticks", unit: unitPercent, window: "10 ticks", decimals: avgBuy10},
+	"Tick.LL1":               {meaning: "Long liquidations (forced sells)", unit: unitCount, window: "1s before start_at"},
+	"Tick.LL2":               {meaning: "Long liquidations (forced sells)", unit: unitCount, window: "2s before start_at"},
+	"Tick.LL5":               {meaning: "Long liquidations (forced sells)", unit: unitCount, window: "5s before start_at"},
+	"Tick.LL60":              {meaning: "Long liquidations (forced sells)", unit: unitCount, window: "60s before start_at"},
+	"Tick.SL1":               {meaning: "Short liquidations (forced buys)", unit: unitCount, window: "1s before start_at"},
+	"Tick.SL2":               {meaning: "Short liquidations (forced buys)", unit: unitCount, window: "2s before start_at"},
+	"Tick.SL10":              {meaning: "Short liquidations (forced buys)", unit: unitCount, window: "10s before start_at"},
+	"Tick.BuyVolume1":        {meaning: "Notional of the taker buy trades of TRADES_SYMBOLS", unit: unitUSD, window: "1s before start_at", decimals: notional},
+	"Tick.SellVolume1":       {meaning: "Notional of the taker sell trades of TRADES_SYMBOLS", unit: unitUSD, window: "1s before start_at", decimals: notional},
+	"Tick.IndicatorsVersion": {meaning: "Version of the indicator calculations, 0 before versioning"},
+	"Tick.Skipped":           {meaning: "Low-priority symbols left out because the tick ran past its deadline"},
+	"Tick.Suspect":           {meaning: "Failed suspect-severity tick checks as \"<check>: <problem>\""},
+	"Tick.Maintenance":       {meaning: "Built within a scheduled maintenance window of the exchange"},
+	"Tick.Quality":           {meaning: "Reliability of the data of the tick, zero for ticks stored before quality scoring"},
+	"Tick.Errors":            {meaning: "Stages that failed while building the tick, stored anyway with the fields they fill unreliable"},
+	"Tick.WarmingUp":         {meaning: "Built before the history covered WARM_UP, 20-minute indicators are incomplete and no market alert is raised"},
+	"Tick.Avg":               {meaning: "Averages of the liquid tickers, every ticker weighing the same"},
+	"Tick.AvgWeighted":       {meaning: "Averages of the liquid tickers weighted by their top of the book notional"},
+	"Tick.CategoryAvg":       {meaning: "Averages of the liquid tickers of every symbol category, keyed by category"},
+	"Tick.Data":              {meaning: "Tickers of the tick keyed by symbol"},
+
+	"TickQuality.Score":              {meaning: "Mean of the component scores, from 0 to 1", unit: unitRatio},
+	"TickQuality.Updated":            {meaning: "Share of the symbols of the tick and the previous one present in the tick", unit: unitRatio},
+	"TickQuality.ConversionFailures": {meaning: "Share of the fetched tickers that failed conversion", unit: unitRatio},
+	"TickQuality.FetchBudget":        {meaning: "Fetch duration over QUALITY_FETCH_BUDGET, scored 1 up to 1 and 0 from 2", unit: unitRatio},
+	"TickQuality.LiquidationsAge":    {meaning: "Time since the last message of the liquidation stream, -1 when not streamed", unit: unitMillis},
+
+	"TickError.Stage":  {meaning: "Failed stage: liquidations_history, convert_tickers or build_tickers"},
+	"TickError.Fields": {meaning: "Stored fields left unreliable, e.g. ll_1; tickers failing a stage are missing from data"},
+	"TickError.Error":  {meaning: "Error of the stage"},
+
+	"TickAvg.Change1m":     {meaning: "Average change of the bid", unit: unitPercent, window: "1m", decimals: avgTrend},
+	"TickAvg.Change20m":    {meaning: "Average change of the bid", unit: unitPercent, window: "20m", decimals: avgTrend},
+	"TickAvg.Max10":        {meaning: "Average distance of the ask to its max", unit: unitPercent, window: "10m", decimals: avgTrend},
+	"TickAvg.Min10":        {meaning: "Average distance of the ask to its min", unit: unitPercent, window: "10m", decimals: avgTrend},
+	"TickAvg.AskChange":    {meaning: "Average change of the ask since the previous tick, each capped to ±1%", unit: unitPercent, window: "1 tick", decimals: avgQuote},
+	"TickAvg.BidChange":    {meaning: "Average change of the bid since the previous tick, each capped to ±1%", unit: unitPercent, window: "1 tick", decimals: avgQuote},
+	"TickAvg.TickersCount": {meaning: "Tickers averaged", unit: unitCount},
+
+	"Ticker.Symbol":          {meaning: "Symbol of the exchange"},
+	"Ticker.EventAt":         {meaning: "Time of the prices on the exchange, see ets", unit: unitUTC},
+	"Ticker.CreatedAt":       {meaning: "Start of the tick the ticker belongs to", unit: unitUTC},
+	"Ticker.Ask":             {meaning: "Best ask, in USD when normalized (qr set)", unit: unitQuote},
+	"Ticker.Bid":             {meaning: "Best bid, in USD when normalized (qr set)", unit: unitQuote},
+	"Ticker.RSI20":           {meaning: "Wilder RSI of the minute moves", window: "20m", decimals: rsi},
+	"Ticker.AskChange":       {meaning: "Change of the ask since the previous tick", unit: unitPercent, window: "1 tick", decimals: change},
+	"Ticker.BidChange":       {meaning: "Change of the bid since the previous tick", unit: unitPercent, window: "1 tick", decimals: change},
+	"Ticker.Change1m":        {meaning: "Change of the bid", unit: unitPercent, window: "1m", decimals: change},
+	"Ticker.Change20m":       {meaning: "Change of the bid", unit: unitPercent, window: "20m", decimals: change},
+	"Ticker.Max":             {meaning: "Highest ask of the current minute", unit: unitQuote, window: "1m"},
+	"Ticker.Min":             {meaning: "Lowest ask of the current minute", unit: unitQuote, window: "1m"},
+	"Ticker.Max10":           {meaning: "Highest ask", unit: unitQuote, window: "10m"},
+	"Ticker.Min10":           {meaning: "Lowest ask", unit: unitQuote, window: "10m"},
+	"Ticker.Max10Diff":       {meaning: "Distance of the ask to max_10, (ask - max_10) / max_10", unit: unitPercent, window: "10m", decimals: change},
+	"Ticker.Min10Diff":       {meaning: "Distance of the ask to min_10, (ask - min_10) / min_10", unit: unitPercent, window: "10m", decimals: change},
+	"Ticker.Illiquid":        {meaning: "Top of the book notional below LIQUIDITY_MIN_NOTIONAL, left out of averages and alerts"},
+	"Ticker.QuoteRate":       {meaning: "USD rate of the quote asset ask/bid were normalized with, 0 when stored as quoted"},
+	"Ticker.EventAtSource":   {meaning: "Source of et: exchange, response or received"},
+	"Ticker.Notional":        {meaning: "Notional of the thinner side of the top of the book", unit: unitUSD, decimals: notional},
+	"Ticker.LastLiquidation": {meaning: "Latest streamed liquidation of the symbol"},
+	"Ticker.Stats24h":        {meaning: "Statistics of the symbol", window: "24h"},
+	"Ticker.OpenInterest":    {meaning: "Size of the open positions, 0 when the exchange doesn't report it", unit: unitBase},
+	"Ticker.OIChange1m":      {meaning: "Change of the open interest", unit: unitPercent, window: "1m", decimals: change},
+	"Ticker.OIChange20m":     {meaning: "Change of the open interest", unit: unitPercent, window: "20m", decimals: change},
+	"Ticker.Categories":      {meaning: "Categories of the symbol from SYMBOL_META_FILE, e.g. DeFi"},
+	"Ticker.Tier":            {meaning: "Market-cap tier of the symbol from SYMBOL_META_FILE"},
+	"Ticker.Canonical":       {meaning: "Exchange independent symbol, base and quote assets with SYMBOLS_QUOTE_ALIASES applied"},
+
+	"LastLiquidation.Price":    {meaning: "Price of the forced order", unit: unitQuote},
+	"LastLiquidation.Side":     {meaning: "SELL for a long liquidation, BUY for a short one"},
+	"LastLiquidation.Notional": {meaning: "Notional of the forced order, 0 without a known rate", unit: unitUSD, decimals: notional},
+	"LastLiquidation.Age":      {meaning: "Time between the liquidation and start_at", unit: unitMillis},
+
+	"RollingStats.High":                {meaning: "Highest mid price", unit: unitQuote, window: "24h"},
+	"RollingStats.Low":                 {meaning: "Lowest mid price", unit: unitQuote, window: "24h"},
+	"RollingStats.Change":              {meaning: "Change of the mid price since the start of the window", unit: unitPercent, window: "24h", decimals: change},
+	"RollingStats.LiquidationNotional": {meaning: "Notional liquidated", unit: unitUSD, window: "24h", decimals: notional},
+	"RollingStats.Since":               {meaning: "Start of the data, later than the window start until the importer ran that long", unit: unitUTC},
+
+	"Liquidation.Order":      {meaning: "Forced order"},
+	"Liquidation.EventAt":    {meaning: "Time of the liquidation on the exchange", unit: unitUTC},
+	"Liquidation.StoredAt":   {meaning: "Time the liquidation was received", unit: unitUTC},
+	"Liquidation.ReceivedAt": {meaning: "Time the importer received it from its stream, empty when backfilled", unit: unitUTC},
+	"Liquidation.WindowAt":   {meaning: "Time counted at in the ll/sl windows, later than et when it arrived late", unit: unitUTC},
+	"Liquidation.Raw":        {meaning: "Gzip-compressed exchange frame, with LIQUIDATIONS_ARCHIVE_RAW"},
+	"Liquidation.Source":     {meaning: "External provider that reported it, empty for the exchange"},
+	"Liquidation.Suspect":    {meaning: "Failed sanity checks as \"<check>: <problem>\""},
+
+	"Order.EventAt":    {meaning: "Time of the order on the exchange", unit: unitUTC},
+	"Order.Symbol":     {meaning: "Symbol of the exchange"},
+	"Order.Side":       {meaning: "SELL for a long liquidation, BUY for a short one"},
+	"Order.Price":      {meaning: "Price of the forced order", unit: unitQuote},
+	"Order.Quantity":   {meaning: "Quantity of the forced order, contract and USD sizes converted to base units", unit: unitBase},
+	"Order.TotalPrice": {meaning: "Price times quantity", unit: unitQuote},
+	"Order.Quote":      {meaning: "Quote asset of the price and the total price, empty if unknown"},
+	"Order.USDValue":   {meaning: "Notional of the forced order, 0 without a known rate", unit: unitUSD, decimals: notional},
+
+	"Bar.Symbol":      {meaning: "Symbol of the exchange"},
+	"Bar.StartAt":     {meaning: "Start of the minute", unit: unitUTC},
+	"Bar.Open":        {meaning: "First mid price of the minute", unit: unitQuote, window: "1m"},
+	"Bar.High":        {meaning: "Highest mid price of the minute", unit: unitQuote, window: "1m"},
+	"Bar.Low":         {meaning: "Lowest mid price of the minute", unit: unitQuote, window: "1m"},
+	"Bar.Close":       {meaning: "Last mid price of the minute", unit: unitQuote, window: "1m"},
+	"Bar.MaxSpread":   {meaning: "Widest ask/bid spread relative to the mid price", unit: unitPercent, window: "1m"},
+	"Bar.LiqNotional": {meaning: "Notional liquidated", unit: unitUSD, window: "1m", decimals: notional},
+	"Bar.Samples":     {meaning: "Tickers the bar was built from", unit: unitCount},
+
+	"FundingRate.Symbol":        {meaning: "Symbol of the exchange"},
+	"FundingRate.Rate":          {meaning: "Funding rate of the current period, 0.0001 is 0.01%", unit: unitRatio},
+	"FundingRate.MarkPrice":     {meaning: "Mark price, 0 when the exchange doesn't report it with the rate", unit: unitQuote},
+	"FundingRate.NextFundingAt": {meaning: "Settlement of the current funding period", unit: unitUTC},
+	"FundingRate.EventAt":       {meaning: "Time the rate was reported by the exchange", unit: unitUTC},
+	"FundingRate.CreatedAt":     {meaning: "Time the snapshot was taken", unit: unitUTC},
+
+	"Trade.Symbol":   {meaning: "Symbol of the exchange"},
+	"Trade.ID":       {meaning: "Exchange id of the aggregated trade"},
+	"Trade.Side":     {meaning: "Side of the taker, BUY when the buyer took the ask"},
+	"Trade.Price":    {meaning: "Price of the fills", unit: unitQuote},
+	"Trade.Quantity": {meaning: "Quantity of the fills, contracts for coin-margined instruments", unit: unitBase},
+	"Trade.USDValue": {meaning: "Notional of the trade, 0 without a known rate", unit: unitUSD, decimals: notional},
+	"Trade.EventAt":  {meaning: "Time of the trade on the exchange", unit: unitUTC},
+	"Trade.StoredAt": {meaning: "Time the trade was received", unit: unitUTC},
+
+	"Symbol.Symbol":        {meaning: "Symbol of the exchange"},
+	"Symbol.InstType":      {meaning: "Exchange specific instrument type the symbol was fetched for, e.g. SWAP"},
+	"Symbol.Base":          {meaning: "Base asset, empty if unknown"},
+	"Symbol.Quote":         {meaning: "Quote asset, empty if unknown"},
+	"Symbol.ContractValue": {meaning: "Face value of a contract of coin-margined instruments, 0 for linear ones", unit: unitUSD},
+	"Symbol.Status":        {meaning: "active, or delisted once missing from the ticker fetches for SYMBOLS_DELIST_AFTER"},
+	"Symbol.FirstSeenAt":   {meaning: "Start of the first tick the symbol was fetched in", unit: unitUTC},
+	"Symbol.LastSeenAt":    {meaning: "Start of the latest tick the symbol was fetched in", unit: unitUTC},
+	"Symbol.DelistedAt":    {meaning: "Start of the tick the symbol was marked delisted, empty while active", unit: unitUTC},
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate moq --out mocks/alert_repository.go --pkg mocks --with-resets --skip-ensure . AlertRepository
+
+// Alert is a market alert sent to a notifier. It's stored with the tick that triggered it and the thresholds in
+// effect, so backtests of strategy changes can be compared to what was actually fired
+type Alert struct {
+	Exchange   string          `db:"exchange" json:"exchange" bson:"exchange"`
+	Notifier   string          `db:"notifier" json:"notifier" bson:"notifier"` // kind of client the alert was sent to, e.g. telegram
+	TickKey    time.Time       `db:"tick_key" json:"tick_key" bson:"tick_key"` // storage key of the triggering tick, see Tick.StorageKey
+	Thresholds AlertThresholds `db:"thresholds" json:"thresholds" bson:"thresholds"`
+	Symbols    []TickerName    `db:"symbols" json:"symbols,omitempty" bson:"symbols,omitempty"` // tickers past their threshold
+	Message    string          `db:"message" json:"message" bson:"message"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at" bson:"created_at"`
+}
+
+// AlertThresholds are the market alert thresholds an Alert was fired with, changes in %
+type AlertThresholds struct {
+	AvgPrice1mChange    float64 `json:"avg_price_1m_change" bson:"avg_price_1m_change"`
+	AvgPrice20mChange   float64 `json:"avg_price_20m_change" bson:"avg_price_20m_change"`
+	TickerPrice1mChange float64 `json:"ticker_price_1m_change" bson:"ticker_price_1m_change"`
+	FairPriceDeviation  float64 `json:"fair_price_deviation,omitempty" bson:"fair_price_deviation,omitempty"`
+
+	CategoryPrice1mChange float64 `json:"category_price_1m_change,omitempty" bson:"category_price_1m_change,omitempty"`
+
+	// TickerPrice1mChangeBySymbol are the TickerPrice1mChange overrides keyed by symbol pattern
+	TickerPrice1mChangeBySymbol map[string]float64 `json:"ticker_price_1m_change_by_symbol,omitempty" bson:"ticker_price_1m_change_by_symbol,omitempty"`
+
+	// Persistence is the number of consecutive ticks the thresholds were crossed in before alerting
+	Persistence int `json:"persistence,omitempty" bson:"persistence,omitempty"`
+}
+
+// AlertRepository represents the alert repository contract
+type AlertRepository interface {
+	Create(ctx context.Context, alert Alert) error
+}
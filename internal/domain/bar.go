@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate moq --out mocks/bar_repository.go --pkg mocks --with-resets --skip-ensure . BarRepository
+
+// Bar is a finalized 1-minute bar of a symbol built from the mid prices of the tickers of the minute
+type Bar struct {
+	Symbol      TickerName `db:"s" json:"s" bson:"s"`
+	StartAt     time.Time  `db:"st" json:"st" bson:"st"` // start of the minute
+	Open        float64    `db:"o" json:"o" bson:"o"`
+	High        float64    `db:"h" json:"h" bson:"h"`
+	Low         float64    `db:"l" json:"l" bson:"l"`
+	Close       float64    `db:"c" json:"c" bson:"c"`
+	MaxSpread   float64    `db:"spr" json:"spr" bson:"spr"` // widest ask/bid spread of the minute, % of the mid price
+	LiqNotional float64    `db:"liq" json:"liq" bson:"liq"` // USD notional liquidated during the minute
+	Samples     int        `db:"n" json:"n" bson:"n"`       // tickers the bar was built from
+}
+
+// BarRepository represents the minute bar repository contract
+type BarRepository interface {
+	CreateMany(ctx context.Context, bars []Bar) error
+}
+
+// NewBar starts the bar of the minute of the ticker
+func NewBar(ticker *Ticker) *Bar {
+	mid := ticker.Mid()
+	return &Bar{
+		Symbol:    ticker.Symbol,
+		StartAt:   ticker.CreatedAt.Truncate(time.Minute),
+		Open:      mid,
+		High:      mid,
+		Low:       mid,
+		Close:     mid,
+		MaxSpread: ticker.Spread(),
+		Samples:   1,
+	}
+}
+
+// Update adds a ticker of the same minute to the bar
+func (b *Bar) Update(ticker *Ticker) {
+	mid := ticker.Mid()
+	b.High = max(b.High, mid)
+	b.Low = min(b.Low, mid)
+	b.Close = mid
+	b.MaxSpread = max(b.MaxSpread, ticker.Spread())
+	b.Samples++
+}
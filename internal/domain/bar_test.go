@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBar(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 1, 0, 0, time.UTC)
+	bar := NewBar(&Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 99, CreatedAt: start.Add(5 * time.Second)})
+	bar.Update(&Ticker{Symbol: "BTCUSDT", Ask: 106, Bid: 104, CreatedAt: start.Add(10 * time.Second)})
+	bar.Update(&Ticker{Symbol: "BTCUSDT", Ask: 98.5, Bid: 97.5, CreatedAt: start.Add(20 * time.Second)})
+	bar.Update(&Ticker{Symbol: "BTCUSDT", Ask: 103, Bid: 99, CreatedAt: start.Add(30 * time.Second)})
+
+	assert.Equal(t, Bar{
+		Symbol:    "BTCUSDT",
+		StartAt:   start,
+		Open:      100,
+		High:      105,
+		Low:       98,
+		Close:     101,
+		MaxSpread: 4 / 101.0 * 100,
+		Samples:   4,
+	}, *bar)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Now returns the current time in UTC. The importer stamps its receive and creation times with it, so the stored
+// times don't depend on the time zone of the host, e.g. SQLite compares them as text
+func Now() time.Time {
+	return time.Now().UTC()
+}
+
+// EventSkew returns how much later an event was received by the importer than the exchange reported it happened,
+// negative when the exchange clock runs ahead of the local one. It's false when either time is unknown
+func EventSkew(eventAt, receivedAt time.Time) (time.Duration, bool) {
+	if eventAt.IsZero() || receivedAt.IsZero() {
+		return 0, false
+	}
+	return receivedAt.Sub(eventAt), true
+}
+
+// MedianDuration returns the median of the durations, 0 without any. It sorts them in place
+func MedianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	slices.Sort(durations)
+	mid := len(durations) / 2
+	if len(durations)%2 == 0 {
+		return (durations[mid-1] + durations[mid]) / 2
+	}
+	return durations[mid]
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate moq --out mocks/composite_price_repository.go --pkg mocks --with-resets --skip-ensure . CompositePriceRepository
+
+// CompositePrice is the fair price of a canonical symbol combined from the prices of several exchanges
+type CompositePrice struct {
+	Symbol    TickerName        `db:"s" json:"s" bson:"s"`    // canonical symbol, base and quote assets concatenated, e.g. BTCUSDT
+	CreatedAt time.Time         `db:"ct" json:"ct" bson:"ct"` // date when the composite was built
+	Price     float64           `db:"p" json:"p" bson:"p"`    // weighted mid price in the quote asset
+	Sources   []CompositeSource `db:"src" json:"src" bson:"src"`
+}
+
+// CompositeSource is the contribution of a single exchange to a CompositePrice
+type CompositeSource struct {
+	Exchange string     `db:"ex" json:"ex" bson:"ex"`
+	Symbol   TickerName `db:"s" json:"s" bson:"s"` // symbol as listed on the exchange
+	Price    float64    `db:"p" json:"p" bson:"p"` // mid price in the quote asset
+	Weight   float64    `db:"w" json:"w" bson:"w"` // share of the composite, the weights of a composite sum up to 1
+}
+
+// CompositePriceRepository represents the composite price repository contract
+type CompositePriceRepository interface {
+	CreateMany(ctx context.Context, prices []CompositePrice) error
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+//go:generate moq --out mocks/funding_rate_repository.go --pkg mocks --with-resets --skip-ensure . FundingRateRepository
+
+// FundingRate is a snapshot of the funding rate of a perpetual futures symbol
+type FundingRate struct {
+	Symbol        TickerName `db:"s" json:"s" bson:"s"`
+	Rate          float64    `db:"r" json:"r" bson:"r"`                        // rate of the current funding period as a fraction, 0.0001 is 0.01%
+	MarkPrice     float64    `db:"mp" json:"mp,omitempty" bson:"mp,omitempty"` // 0 if the exchange doesn't report it with the rate
+	NextFundingAt time.Time  `db:"nft" json:"nft" bson:"nft"`                  // settlement of the current funding period
+	EventAt       time.Time  `db:"et" json:"et" bson:"et"`                     // date when the rate was reported by the exchange
+	CreatedAt     time.Time  `db:"ct" json:"ct" bson:"ct"`                     // date when the snapshot was taken
+}
+
+// FundingRateRepository represents the funding rate repository contract
+type FundingRateRepository interface {
+	CreateMany(ctx context.Context, rates []FundingRate) error
+}
+
+// Validate performs validation of the FundingRate
+func (f *FundingRate) Validate() error {
+	if f.Symbol == "" {
+		return ValidationError{
+			Field: "Symbol",
+			Err:   fmt.Errorf("symbol cannot be empty"),
+		}
+	}
+
+	if math.IsNaN(f.Rate) || math.IsInf(f.Rate, 0) {
+		return ValidationError{
+			Field: "Rate",
+			Err:   fmt.Errorf("rate must be a finite number, got %f", f.Rate),
+		}
+	}
+
+	if f.MarkPrice < 0 {
+		return ValidationError{
+			Field: "MarkPrice",
+			Err:   fmt.Errorf("mark price cannot be negative, got %f", f.MarkPrice),
+		}
+	}
+
+	if f.CreatedAt.IsZero() {
+		return ValidationError{
+			Field: "CreatedAt",
+			Err:   fmt.Errorf("created time cannot be zero"),
+		}
+	}
+
+	return nil
+}
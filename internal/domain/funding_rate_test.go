@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFundingRate_Validate(t *testing.T) {
+	valid := FundingRate{Symbol: "BTCUSDT", Rate: -0.0003, MarkPrice: 95000, CreatedAt: time.Now()}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(f *FundingRate)
+		field  string
+	}{
+		{name: "empty symbol", modify: func(f *FundingRate) { f.Symbol = "" }, field: "Symbol"},
+		{name: "NaN rate", modify: func(f *FundingRate) { f.Rate = math.NaN() }, field: "Rate"},
+		{name: "infinite rate", modify: func(f *FundingRate) { f.Rate = math.Inf(1) }, field: "Rate"},
+		{name: "negative mark price", modify: func(f *FundingRate) { f.MarkPrice = -1 }, field: "MarkPrice"},
+		{name: "zero created time", modify: func(f *FundingRate) { f.CreatedAt = time.Time{} }, field: "CreatedAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate := valid
+			tt.modify(&rate)
+			var validationErr ValidationError
+			assert.ErrorAs(t, rate.Validate(), &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
@@ -1,12 +1,20 @@
 package domain
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
+	"math"
+	"strings"
 	"time"
+
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
 )
 
 //go:generate moq --out mocks/liquidation_repository.go --pkg mocks --with-resets --skip-ensure . LiquidationRepository
+//go:generate moq --out mocks/liquidation_range_repository.go --pkg mocks --with-resets --skip-ensure . LiquidationRangeRepository
 
 // LiquidationType represents the type of liquidation
 type LiquidationType string
@@ -24,6 +32,65 @@ type Liquidation struct {
 	Order    Order     `json:"o"`
 	EventAt  time.Time `db:"et" json:"et" bson:"et"` // event could come from exchange with a delay
 	StoredAt time.Time `db:"st" json:"st" bson:"st"` // time when the event was stored in the database
+
+	// ReceivedAt is the time the importer received the liquidation from its stream, in UTC. Zero for the backfilled
+	// liquidations, which are stored as if streamed when they happened
+	ReceivedAt time.Time `db:"rt" json:"rt,omitempty" bson:"rt,omitempty"`
+
+	// WindowAt is the time the liquidation is counted at in the LL/SL windows: its event time, or the start of the
+	// first window not counted yet when it arrived after its own window was counted. See WindowTime
+	WindowAt time.Time `db:"wt" json:"wt,omitempty" bson:"wt,omitempty"`
+
+	// Raw is the gzip-compressed exchange frame the liquidation was parsed from, only set when raw liquidations are
+	// archived, so parsing bugs can be corrected by reprocessing. See CompressRaw and RawFrame
+	Raw []byte `db:"raw" json:"raw,omitempty" bson:"raw,omitempty"`
+
+	// Source is the external provider that reported the liquidation, empty when it comes from the exchange itself
+	Source string `db:"src" json:"src,omitempty" bson:"src,omitempty"`
+
+	// Suspect lists the failed sanity checks as "<check>: <problem>", e.g. a price far from the book of the symbol,
+	// the liquidation is stored anyway
+	Suspect []string `db:"sus" json:"sus,omitempty" bson:"sus,omitempty"`
+}
+
+// Names of the sanity checks of liquidations listed in Liquidation.Suspect
+const (
+	// LiquidationCheckPrice is the check of the liquidation price against the book, see CheckPrice
+	LiquidationCheckPrice = "price"
+
+	// LiquidationCheckSymbol is the check of the liquidation symbol against the symbol registry
+	LiquidationCheckSymbol = "symbol"
+)
+
+// CompressRaw compresses an exchange frame to be archived in Liquidation.Raw
+func CompressRaw(frame []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(frame); err != nil {
+		return nil, fmt.Errorf("compressing raw frame: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("compressing raw frame: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RawFrame returns the decompressed exchange frame of the liquidation, nil if it was not archived
+func (l *Liquidation) RawFrame() ([]byte, error) {
+	if len(l.Raw) == 0 {
+		return nil, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(l.Raw))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing raw frame: %w", err)
+	}
+	defer r.Close()
+
+	frame, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing raw frame: %w", err)
+	}
+	return frame, nil
 }
 
 // Validate performs validation of the Liquidation
@@ -75,6 +142,65 @@ func (l *Liquidation) Validate() error {
 	return nil
 }
 
+// LiquidationMatchWindow is the largest difference between the event times of the same liquidation reported twice,
+// e.g. by the stream with its event time and by a REST endpoint with the order time
+const LiquidationMatchWindow = time.Second
+
+// WindowTime returns the time the liquidation is counted at in the LL/SL windows,
+// the event time for the liquidations stored without a window time
+func (l *Liquidation) WindowTime() time.Time {
+	if l.WindowAt.IsZero() {
+		return l.EventAt
+	}
+	return l.WindowAt
+}
+
+// SameAs reports whether both liquidations describe the same forced order
+func (l *Liquidation) SameAs(other Liquidation) bool {
+	diff := l.EventAt.Sub(other.EventAt)
+	return l.Order.Symbol == other.Order.Symbol &&
+		l.Order.Side == other.Order.Side &&
+		l.Order.Price == other.Order.Price &&
+		l.Order.Quantity == other.Order.Quantity &&
+		diff <= LiquidationMatchWindow && diff >= -LiquidationMatchWindow
+}
+
+// CheckPrice fails liquidations priced more than maxDeviation % away from the side of the book they trade against,
+// the bid for a forced sell and the ask for a forced buy, which happens on wrong exchange data or flash events
+func (l *Liquidation) CheckPrice(bid, ask, maxDeviation float64) error {
+	side, reference := "bid", bid
+	if l.Order.Side == OrderSideBuy {
+		side, reference = "ask", ask
+	}
+	if reference <= 0 {
+		return nil
+	}
+	deviation := mathutils.PercDiff(l.Order.Price, reference, 2)
+	if math.Abs(deviation) <= maxDeviation {
+		return nil
+	}
+	return fmt.Errorf("%s at %g is %g%% away from the %s %g (max %g%%)",
+		strings.ToLower(string(l.Order.Side)), l.Order.Price, deviation, side, reference, maxDeviation)
+}
+
+// LastLiquidation is the latest liquidation of a symbol seen by the live stream before a tick was built
+type LastLiquidation struct {
+	Price    float64   `db:"p" json:"p" bson:"p"`
+	Side     OrderSide `db:"sd" json:"sd" bson:"sd"`                  // SELL for a long liquidation, BUY for a short one
+	Notional float64   `db:"n" json:"n,omitempty" bson:"n,omitempty"` // USD value, 0 if the quote asset has no known rate
+	Age      int64     `db:"age" json:"age" bson:"age"`               // milliseconds between the liquidation and the start of the tick
+}
+
+// NewLastLiquidation describes the liquidation as seen by a tick started at startAt
+func NewLastLiquidation(l Liquidation, startAt time.Time) *LastLiquidation {
+	return &LastLiquidation{
+		Price:    l.Order.Price,
+		Side:     l.Order.Side,
+		Notional: l.Order.USDValue,
+		Age:      max(startAt.Sub(l.EventAt).Milliseconds(), 0),
+	}
+}
+
 // LiquidationsHistory represents the liquidation history at a specific point in time
 type LiquidationsHistory struct {
 	LongLiquidations1s   int64
@@ -91,3 +217,42 @@ type LiquidationRepository interface {
 	Create(ctx context.Context, l Liquidation) error
 	GetLiquidationsHistory(ctx context.Context, timeAt time.Time) (LiquidationsHistory, error)
 }
+
+// LiquidationBackfillRepository is implemented by the liquidation repositories able to store liquidations fetched
+// after the fact without duplicating the ones already stored from the stream
+type LiquidationBackfillRepository interface {
+	// CreateMissing stores the liquidations with no stored match (see Liquidation.SameAs) and returns how many were stored
+	CreateMissing(ctx context.Context, liquidations []Liquidation) (int, error)
+}
+
+// LiquidationBatchRepository is implemented by the liquidation repositories able to store several liquidations
+// in a single write, used while the storage rate spikes
+type LiquidationBatchRepository interface {
+	// CreateMany stores the liquidations in a single write
+	CreateMany(ctx context.Context, liquidations []Liquidation) error
+}
+
+// LiquidationRangeRepository is implemented by the liquidation repositories able to read back stored liquidations
+type LiquidationRangeRepository interface {
+	// GetRange returns the liquidations that happened within [from, to) ordered by their event time
+	GetRange(ctx context.Context, from, to time.Time) ([]Liquidation, error)
+}
+
+// LiquidationSymbolNotional is the USD notional liquidated on a symbol within a range
+type LiquidationSymbolNotional struct {
+	Symbol   TickerName `json:"symbol"`
+	Notional float64    `json:"notional"` // USD value of all its liquidations
+	Long     float64    `json:"long"`     // USD value of its long liquidations (forced sells)
+	Short    float64    `json:"short"`    // USD value of its short liquidations (forced buys)
+	Count    int64      `json:"count"`
+}
+
+// LiquidationLeaderboardRepository is implemented by the liquidation repositories able to rank stored liquidations
+// by their USD value. Liquidations of quote assets without a known rate have none and rank last
+type LiquidationLeaderboardRepository interface {
+	// TopSymbolsByNotional returns the limit symbols with the largest notional liquidated within [from, to), largest first
+	TopSymbolsByNotional(ctx context.Context, from, to time.Time, limit int) ([]LiquidationSymbolNotional, error)
+
+	// LargestLiquidations returns the limit liquidations with the largest USD value within [from, to), largest first
+	LargestLiquidations(ctx context.Context, from, to time.Time, limit int) ([]Liquidation, error)
+}
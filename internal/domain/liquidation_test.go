@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLiquidation_Validate(t *testing.T) {
@@ -123,3 +124,100 @@ func TestLiquidation_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestNewLastLiquidation(t *testing.T) {
+	startAt := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+	liq := Liquidation{
+		Order:   Order{Symbol: "BTCUSDT", Side: OrderSideSell, Price: 50000, Quantity: 2, TotalPrice: 100000, USDValue: 100000},
+		EventAt: startAt.Add(-2500 * time.Millisecond),
+	}
+
+	assert.Equal(t, &LastLiquidation{Price: 50000, Side: OrderSideSell, Notional: 100000, Age: 2500}, NewLastLiquidation(liq, startAt))
+
+	liq.EventAt = startAt.Add(time.Second)
+	assert.Zero(t, NewLastLiquidation(liq, startAt).Age, "a liquidation received after the tick started is reported as just happened")
+}
+
+func TestLiquidation_SameAs(t *testing.T) {
+	eventAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	streamed := Liquidation{
+		Order:   Order{Symbol: "BTCUSDT", Side: OrderSideSell, Price: 50000, Quantity: 0.5},
+		EventAt: eventAt,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(l *Liquidation)
+		want   bool
+	}{
+		{name: "same order reported with the order time", modify: func(l *Liquidation) { l.EventAt = eventAt.Add(-40 * time.Millisecond) }, want: true},
+		{name: "other symbol", modify: func(l *Liquidation) { l.Order.Symbol = "ETHUSDT" }},
+		{name: "other side", modify: func(l *Liquidation) { l.Order.Side = OrderSideBuy }},
+		{name: "other quantity", modify: func(l *Liquidation) { l.Order.Quantity = 0.4 }},
+		{name: "later liquidation at the same price", modify: func(l *Liquidation) { l.EventAt = eventAt.Add(2 * time.Second) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched := streamed
+			tt.modify(&fetched)
+			assert.Equal(t, tt.want, streamed.SameAs(fetched))
+		})
+	}
+}
+
+func TestLiquidation_RawFrame(t *testing.T) {
+	var l Liquidation
+	frame, err := l.RawFrame()
+	require.NoError(t, err)
+	assert.Nil(t, frame, "not archived")
+
+	original := []byte(`{"e":"forceOrder","E":1568014460893,"o":{"s":"BTCUSDT","S":"SELL","q":"0.014","p":"9910"}}`)
+	l.Raw, err = CompressRaw(original)
+	require.NoError(t, err)
+	frame, err = l.RawFrame()
+	require.NoError(t, err)
+	assert.Equal(t, original, frame)
+
+	l.Raw = original
+	_, err = l.RawFrame()
+	assert.Error(t, err, "not compressed")
+}
+
+func TestLiquidation_WindowTime(t *testing.T) {
+	eventAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := Liquidation{EventAt: eventAt}
+	assert.Equal(t, eventAt, l.WindowTime(), "stored before the watermark")
+
+	l.WindowAt = eventAt.Add(3 * time.Second)
+	assert.Equal(t, eventAt.Add(3*time.Second), l.WindowTime())
+}
+
+func TestLiquidation_CheckPrice(t *testing.T) {
+	tests := []struct {
+		name    string
+		side    OrderSide
+		price   float64
+		wantErr string
+	}{
+		{name: "forced sell near the bid", side: OrderSideSell, price: 49500},
+		{name: "forced buy near the ask", side: OrderSideBuy, price: 50500},
+		{name: "forced sell far below the bid", side: OrderSideSell, price: 40000, wantErr: "sell at 40000 is -20% away from the bid 50000 (max 10%)"},
+		{name: "forced buy far above the ask", side: OrderSideBuy, price: 60000, wantErr: "buy at 60000 is 19.98% away from the ask 50010 (max 10%)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := Liquidation{Order: Order{Symbol: "BTCUSDT", Side: tt.side, Price: tt.price}}
+			err := l.CheckPrice(50000, 50010, 10)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	l := Liquidation{Order: Order{Side: OrderSideSell, Price: 1}}
+	assert.NoError(t, l.CheckPrice(0, 0, 10), "no book to check against")
+}
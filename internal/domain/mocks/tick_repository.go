@@ -22,6 +22,9 @@ import (
 //			GetHistorySinceFunc: func(ctx context.Context, since time.Time) ([]domain.Tick, error) {
 //				panic("mock out the GetHistorySince method")
 //			},
+//			GetRangeFunc: func(ctx context.Context, from time.Time, to time.Time) ([]domain.Tick, error) {
+//				panic("mock out the GetRange method")
+//			},
 //		}
 //
 //		// use mockedTickRepository in code that requires domain.TickRepository
@@ -35,6 +38,9 @@ type TickRepositoryMock struct {
 	// GetHistorySinceFunc mocks the GetHistorySince method.
 	GetHistorySinceFunc func(ctx context.Context, since time.Time) ([]domain.Tick, error)
 
+	// GetRangeFunc mocks the GetRange method.
+	GetRangeFunc func(ctx context.Context, from time.Time, to time.Time) ([]domain.Tick, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Create holds details about calls to the Create method.
@@ -51,9 +57,19 @@ type TickRepositoryMock struct {
 			// Since is the since argument value.
 			Since time.Time
 		}
+		// GetRange holds details about calls to the GetRange method.
+		GetRange []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// From is the from argument value.
+			From time.Time
+			// To is the to argument value.
+			To time.Time
+		}
 	}
 	lockCreate          sync.RWMutex
 	lockGetHistorySince sync.RWMutex
+	lockGetRange        sync.RWMutex
 }
 
 // Create calls CreateFunc.
@@ -142,6 +158,53 @@ func (mock *TickRepositoryMock) ResetGetHistorySinceCalls() {
 	mock.lockGetHistorySince.Unlock()
 }
 
+// GetRange calls GetRangeFunc.
+func (mock *TickRepositoryMock) GetRange(ctx context.Context, from time.Time, to time.Time) ([]domain.Tick, error) {
+	if mock.GetRangeFunc == nil {
+		panic("TickRepositoryMock.GetRangeFunc: method is nil but TickRepository.GetRange was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		From time.Time
+		To   time.Time
+	}{
+		Ctx:  ctx,
+		From: from,
+		To:   to,
+	}
+	mock.lockGetRange.Lock()
+	mock.calls.GetRange = append(mock.calls.GetRange, callInfo)
+	mock.lockGetRange.Unlock()
+	return mock.GetRangeFunc(ctx, from, to)
+}
+
+// GetRangeCalls gets all the calls that were made to GetRange.
+// Check the length with:
+//
+//	len(mockedTickRepository.GetRangeCalls())
+func (mock *TickRepositoryMock) GetRangeCalls() []struct {
+	Ctx  context.Context
+	From time.Time
+	To   time.Time
+} {
+	var calls []struct {
+		Ctx  context.Context
+		From time.Time
+		To   time.Time
+	}
+	mock.lockGetRange.RLock()
+	calls = mock.calls.GetRange
+	mock.lockGetRange.RUnlock()
+	return calls
+}
+
+// ResetGetRangeCalls reset all the calls that were made to GetRange.
+func (mock *TickRepositoryMock) ResetGetRangeCalls() {
+	mock.lockGetRange.Lock()
+	mock.calls.GetRange = nil
+	mock.lockGetRange.Unlock()
+}
+
 // ResetCalls reset all the calls that were made to all mocked methods.
 func (mock *TickRepositoryMock) ResetCalls() {
 	mock.lockCreate.Lock()
@@ -151,4 +214,8 @@ func (mock *TickRepositoryMock) ResetCalls() {
 	mock.lockGetHistorySince.Lock()
 	mock.calls.GetHistorySince = nil
 	mock.lockGetHistorySince.Unlock()
+
+	mock.lockGetRange.Lock()
+	mock.calls.GetRange = nil
+	mock.lockGetRange.Unlock()
 }
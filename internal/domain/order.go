@@ -22,8 +22,10 @@ type Order struct {
 	Symbol     TickerName `db:"s" json:"s" bson:"s"`
 	Side       OrderSide  `db:"sd" json:"sd" bson:"sd" validate:"required,oneof=BUY SELL"`
 	Price      float64    `db:"p" json:"p" bson:"p"`
-	Quantity   float64    `db:"q" json:"q" bson:"q"`
+	Quantity   float64    `db:"q" json:"q" bson:"q"` // in base asset units whatever the exchange reports sizes in
 	TotalPrice float64    `db:"tp" json:"tp" bson:"tp"`
+	Quote      string     `db:"qc" json:"qc,omitempty" bson:"qc,omitempty"`    // quote asset of Price and TotalPrice, empty if unknown
+	USDValue   float64    `db:"usd" json:"usd,omitempty" bson:"usd,omitempty"` // notional in USD, 0 if the quote asset has no known rate
 }
 
 // Validate performs validation of the Order
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate moq --out mocks/outage_repository.go --pkg mocks --with-resets --skip-ensure . OutageRepository
+
+// OutageReason tells why the importer was blind to the exchange
+type OutageReason string
+
+const (
+	// OutageWSDisconnect means a websocket stream was disconnected or failing to reconnect
+	OutageWSDisconnect OutageReason = "ws_disconnect"
+
+	// OutageHTTPFailure means the exchange REST API requests were failing
+	OutageHTTPFailure OutageReason = "http_failure"
+)
+
+// Outage is a period the importer received no data of a stage from the exchange, so analytics can tell
+// "nothing happened" (e.g. no liquidations) from "nothing was seen"
+type Outage struct {
+	Exchange  string       `db:"exchange" json:"exchange" bson:"exchange"`
+	Stage     string       `db:"stage" json:"stage" bson:"stage"` // failing import stage, e.g. liquidations_stream
+	Reason    OutageReason `db:"reason" json:"reason" bson:"reason"`
+	StartAt   time.Time    `db:"start_at" json:"start_at" bson:"start_at"` // first failure
+	EndAt     time.Time    `db:"end_at" json:"end_at" bson:"end_at"`       // data received again, or the last failure when nothing proves the recovery
+	Failures  int          `db:"failures" json:"failures" bson:"failures"`
+	LastError string       `db:"last_error" json:"last_error" bson:"last_error"`
+}
+
+// Duration returns how long the outage lasted
+func (o Outage) Duration() time.Duration {
+	return o.EndAt.Sub(o.StartAt)
+}
+
+// OutageRepository represents the outage repository contract
+type OutageRepository interface {
+	Create(ctx context.Context, outage Outage) error
+}
@@ -0,0 +1,27 @@
+package domain
+
+// MaxPrecision is the largest number of decimals a stored field can be rounded to
+const MaxPrecision = 12
+
+// Precision holds the number of decimals stored indicators are rounded to, per group of fields.
+// A negative number stores the field unrounded
+type Precision struct {
+	Change   int // ticker a_pd, b_pd, pd, pd_20, max_10_diff and min_10_diff, in percent
+	RSI      int // ticker rsi_20
+	AvgQuote int // tick avg a_pd and s_pd, the average ask/bid change since the previous tick
+	AvgTrend int // tick avg pd, pd_20, max_10 and min_10
+	AvgBuy10 int // tick tick_avg_buy_open
+	Notional int // USD notionals: ticker n, bar and 24h stats liquidation notionals
+}
+
+// DefaultPrecision returns the precision indicators were always stored with
+func DefaultPrecision() Precision {
+	return Precision{
+		Change:   2,
+		RSI:      1,
+		AvgQuote: 4,
+		AvgTrend: 2,
+		AvgBuy10: 6,
+		Notional: 2,
+	}
+}
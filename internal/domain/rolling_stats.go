@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// RollingStatsWindow is the window of RollingStats
+const RollingStatsWindow = 24 * time.Hour
+
+// RollingStatsBucket is the resolution of RollingStats, the window moves forward one bucket at a time
+const RollingStatsBucket = 5 * time.Minute
+
+// RollingStats are the statistics of a symbol over the last RollingStatsWindow, computed from the mid prices of the
+// imported tickers and the streamed liquidations, so they are consistent across exchanges unlike the venue's 24h stats
+type RollingStats struct {
+	High                float64   `db:"h" json:"h" bson:"h"`
+	Low                 float64   `db:"l" json:"l" bson:"l"`
+	Change              float64   `db:"pd" json:"pd" bson:"pd"`          // % change of the mid price since the start of the window
+	LiquidationNotional float64   `db:"ln" json:"ln" bson:"ln"`          // USD notional liquidated within the window
+	Since               time.Time `db:"since" json:"since" bson:"since"` // start of the data, later than the window start until the importer ran that long
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+//go:generate moq --out mocks/sub_tick_repository.go --pkg mocks --with-resets --skip-ensure . SubTickRepository
+
+// SubTick is a high-resolution top of the book sample of a single symbol taken between regular ticks
+type SubTick struct {
+	Symbol    TickerName `db:"s" json:"s" bson:"s"`    // symbol
+	EventAt   time.Time  `db:"et" json:"et" bson:"et"` // date when the book changed on the exchange
+	CreatedAt time.Time  `db:"ct" json:"ct" bson:"ct"` // date when the sample was taken
+	Ask       float64    `db:"ask" json:"ask" bson:"ask"`
+	Bid       float64    `db:"bid" json:"bid" bson:"bid"`
+	AskQty    float64    `db:"ask_qty" json:"ask_qty" bson:"ask_qty"`
+	BidQty    float64    `db:"bid_qty" json:"bid_qty" bson:"bid_qty"`
+}
+
+// SubTickRepository represents the sub-tick repository contract
+type SubTickRepository interface {
+	CreateMany(ctx context.Context, subTicks []SubTick) error
+}
+
+// Validate performs validation of the SubTick
+func (s *SubTick) Validate() error {
+	if s.Symbol == "" {
+		return ValidationError{
+			Field: "Symbol",
+			Err:   fmt.Errorf("symbol cannot be empty"),
+		}
+	}
+
+	if s.EventAt.IsZero() {
+		return ValidationError{
+			Field: "EventAt",
+			Err:   fmt.Errorf("event time cannot be zero"),
+		}
+	}
+
+	if s.CreatedAt.IsZero() {
+		return ValidationError{
+			Field: "CreatedAt",
+			Err:   fmt.Errorf("created time cannot be zero"),
+		}
+	}
+
+	if s.Ask <= 0 || s.Bid <= 0 {
+		return ValidationError{
+			Field: "Bid/Ask",
+			Err:   fmt.Errorf("bid (%f) and ask (%f) prices must be greater than 0", s.Bid, s.Ask),
+		}
+	}
+
+	if s.Bid >= s.Ask {
+		return ValidationError{
+			Field: "Bid/Ask",
+			Err:   fmt.Errorf("bid price (%f) must be less than ask price (%f)", s.Bid, s.Ask),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubTick_Validate(t *testing.T) {
+	now := time.Now()
+	valid := SubTick{Symbol: "BTCUSDT", EventAt: now, CreatedAt: now, Ask: 101, Bid: 100}
+
+	tests := []struct {
+		name     string
+		modify   func(s *SubTick)
+		errField string
+	}{
+		{name: "valid", modify: func(s *SubTick) {}},
+		{name: "empty symbol", modify: func(s *SubTick) { s.Symbol = "" }, errField: "Symbol"},
+		{name: "zero event time", modify: func(s *SubTick) { s.EventAt = time.Time{} }, errField: "EventAt"},
+		{name: "zero created time", modify: func(s *SubTick) { s.CreatedAt = time.Time{} }, errField: "CreatedAt"},
+		{name: "zero price", modify: func(s *SubTick) { s.Bid = 0 }, errField: "Bid/Ask"},
+		{name: "crossed book", modify: func(s *SubTick) { s.Bid = 102 }, errField: "Bid/Ask"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subTick := valid
+			tt.modify(&subTick)
+
+			err := subTick.Validate()
+			if tt.errField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var valErr ValidationError
+			assert.ErrorAs(t, err, &valErr)
+			assert.Equal(t, tt.errField, valErr.Field)
+		})
+	}
+}
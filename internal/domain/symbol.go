@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate moq --out mocks/symbol_repository.go --pkg mocks --with-resets --skip-ensure . SymbolRepository
+
+// SymbolStatus tells whether a symbol is still listed on the exchange
+type SymbolStatus string
+
+const (
+	// SymbolActive means the symbol was seen in the recent ticker fetches
+	SymbolActive SymbolStatus = "active"
+
+	// SymbolDelisted means the symbol is missing from the ticker fetches since a while, it's active again once seen
+	SymbolDelisted SymbolStatus = "delisted"
+)
+
+// Symbol is an instrument of an exchange as seen by the importer, one record per symbol kept up to date,
+// so listings and delistings can be analyzed and streamed symbols checked against the known instruments
+type Symbol struct {
+	Symbol        TickerName   `db:"s" json:"s" bson:"s"`
+	InstType      string       `db:"it" json:"it,omitempty" bson:"it,omitempty"` // exchange specific instrument type, e.g. SWAP
+	Base          string       `db:"b" json:"b,omitempty" bson:"b,omitempty"`
+	Quote         string       `db:"q" json:"q,omitempty" bson:"q,omitempty"`
+	ContractValue float64      `db:"cv" json:"cv,omitempty" bson:"cv,omitempty"` // USD face value of a contract of coin-margined instruments
+	Status        SymbolStatus `db:"st" json:"st" bson:"st"`
+	FirstSeenAt   time.Time    `db:"fs" json:"fs" bson:"fs"` // start of the first tick the symbol was fetched in
+	LastSeenAt    time.Time    `db:"ls" json:"ls" bson:"ls"` // start of the latest tick the symbol was fetched in
+	DelistedAt    time.Time    `db:"dt" json:"dt,omitempty" bson:"dt,omitempty"`
+}
+
+// SymbolRepository represents the symbol registry repository contract
+type SymbolRepository interface {
+	// SaveMany upserts the symbols, replacing the records of the same symbols
+	SaveMany(ctx context.Context, symbols []Symbol) error
+
+	// GetAll returns the stored symbols
+	GetAll(ctx context.Context) ([]Symbol, error)
+}
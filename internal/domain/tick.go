@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/pkg/utils"
@@ -10,10 +12,16 @@ import (
 )
 
 //go:generate moq --out mocks/tick_repository.go --pkg mocks --with-resets --skip-ensure . TickRepository
+//go:generate moq --out mocks/recomputed_tick_repository.go --pkg mocks --with-resets --skip-ensure . RecomputedTickRepository
+//go:generate moq --out mocks/tick_page_repository.go --pkg mocks --with-resets --skip-ensure . TickPageRepository
 
 const (
 	// MaxTickHistory is the maximum number of tick snapshots to keep in memory
 	MaxTickHistory = 25
+
+	// IndicatorsVersion identifies the indicator calculations of this code,
+	// bump it whenever a change alters the values of stored indicators
+	IndicatorsVersion = 3
 )
 
 // Tick represents a snapshot of market data for multiple tickers at a specific point in time
@@ -27,6 +35,11 @@ type Tick struct {
 	FetchDuration    int64 `db:"fetch_duration" json:"fetch_duration" bson:"fetch_duration"`
 	HandlingDuration int64 `db:"handling_duration" json:"handling_duration" bson:"handling_duration"`
 
+	// EventSkew is the median of how much later the tickers were fetched than the exchange stamped them, see
+	// MedianEventSkew, 0 when no ticker has an exchange timestamp. Drifting away from the usual lag of the exchange
+	// it points to a skewed clock on either side
+	EventSkew int64 `db:"event_skew" json:"event_skew,omitempty" bson:"event_skew,omitempty"`
+
 	AvgBuy10 float64 `db:"tick_avg_buy_open" json:"tick_avg_buy_open" bson:"tick_avg_buy_open"`
 	LL1      int64   `db:"ll_1" json:"ll_1" bson:"ll_1"`    // 1s second total long liquidations
 	LL2      int64   `db:"ll_2" json:"ll_2" bson:"ll_2"`    // 2s second total long liquidations
@@ -36,7 +49,42 @@ type Tick struct {
 	SL2      int64   `db:"sl_2" json:"sl_2" bson:"sl_2"`    // 2s second total short liquidations
 	SL10     int64   `db:"sl_10" json:"sl_10" bson:"sl_10"` // 10s second total short liquidations
 
+	// BuyVolume1 and SellVolume1 are the USD notional of the taker buy and sell trades of the traded symbols
+	// in the second before StartAt, 0 when trades aren't imported
+	BuyVolume1  float64 `db:"bv_1" json:"bv_1,omitempty" bson:"bv_1,omitempty"`
+	SellVolume1 float64 `db:"sv_1" json:"sv_1,omitempty" bson:"sv_1,omitempty"`
+
+	// IndicatorsVersion is the IndicatorsVersion the indicators were calculated with, 0 for ticks stored before versioning
+	IndicatorsVersion int `db:"indicators_version" json:"indicators_version,omitempty" bson:"indicators_version,omitempty"`
+
+	// Skipped lists the low-priority symbols left out because the tick ran past its handling deadline,
+	// their tickers are missing from Data
+	Skipped []TickerName `db:"skipped" json:"skipped,omitempty" bson:"skipped,omitempty"`
+
+	// Suspect lists the failed suspect-severity TickChecks as "<check>: <problem>", the tick is stored anyway
+	Suspect []string `db:"suspect" json:"suspect,omitempty" bson:"suspect,omitempty"`
+
+	// Maintenance marks the ticks built within a scheduled maintenance window of the exchange,
+	// their prices may be frozen or missing
+	Maintenance bool `db:"maintenance" json:"maintenance,omitempty" bson:"maintenance,omitempty"`
+
+	// WarmingUp marks the ticks built before the history covered the configured warm-up, their 20-minute
+	// indicators (pd_20, rsi_20) are incomplete and they raise no market alerts
+	WarmingUp bool `db:"warming_up" json:"warming_up,omitempty" bson:"warming_up,omitempty"`
+
+	// Quality scores the reliability of the data of the tick, see TickQuality
+	Quality TickQuality `db:"quality" json:"quality" bson:"quality"`
+
+	// Errors annotates the stages that failed while building the tick, it's stored anyway with the fields the stages
+	// fill left unreliable, so consumers can filter degraded ticks
+	Errors []TickError `db:"errors" json:"errors,omitempty" bson:"errors,omitempty"`
+
 	Avg TickAvg `db:"avg" json:"avg" bson:"avg"`
+	// AvgWeighted are the averages weighted by the top of the book notional of the tickers, so the liquid pairs
+	// drive them instead of the hundreds of micro-caps; tickers without a known notional are left out
+	AvgWeighted TickAvg `db:"avg_w" json:"avg_w" bson:"avg_w"`
+	// CategoryAvg are the averages of the liquid tickers of every symbol category, see Ticker.Categories
+	CategoryAvg map[string]TickAvg `db:"cat_avg" json:"cat_avg,omitempty" bson:"cat_avg,omitempty"`
 	// store data as map to be able to query by ticker name or project the data
 	Data map[TickerName]*Ticker `db:"data" json:"data" bson:"data"`
 }
@@ -52,14 +100,106 @@ type TickAvg struct {
 	TickersCount int16   `db:"tickers_count" json:"tickers_count" bson:"tickers_count"`
 }
 
+// Stages of building a tick reported in TickError.Stage
+const (
+	// TickStageLiquidations is the query of the liquidation counts, ll_* and sl_* are 0 when it fails
+	TickStageLiquidations = "liquidations_history"
+
+	// TickStageConvertTickers is the conversion of the fetched tickers, the tickers failing it are missing from Data
+	TickStageConvertTickers = "convert_tickers"
+
+	// TickStageBuildTickers is the calculation of the ticker indicators, the tickers failing it are missing from Data
+	TickStageBuildTickers = "build_tickers"
+)
+
+// liquidationFields are the stored fields filled by TickStageLiquidations
+var liquidationFields = []string{"ll_1", "ll_2", "ll_5", "ll_60", "sl_1", "sl_2", "sl_10"}
+
+// TickError is a stage that failed while building a tick
+type TickError struct {
+	Stage  string   `db:"stage" json:"stage" bson:"stage"`
+	Fields []string `db:"fields" json:"fields,omitempty" bson:"fields,omitempty"` // stored fields left unreliable, e.g. ll_1
+	Error  string   `db:"error" json:"error" bson:"error"`
+}
+
+// AddError annotates the tick with a failed stage
+func (t *Tick) AddError(stage string, err error) {
+	tickErr := TickError{Stage: stage, Error: err.Error()}
+	if stage == TickStageLiquidations {
+		tickErr.Fields = slices.Clone(liquidationFields)
+	}
+	t.Errors = append(t.Errors, tickErr)
+}
+
+// Clone returns a deep copy of the tick sharing no memory with it, so the copy can be read while the tick is modified
+func (t *Tick) Clone() *Tick {
+	clone := *t
+	clone.Skipped = slices.Clone(t.Skipped)
+	clone.Suspect = slices.Clone(t.Suspect)
+	clone.CategoryAvg = maps.Clone(t.CategoryAvg)
+	if t.Errors != nil {
+		clone.Errors = make([]TickError, len(t.Errors))
+		for idx, tickErr := range t.Errors {
+			tickErr.Fields = slices.Clone(tickErr.Fields)
+			clone.Errors[idx] = tickErr
+		}
+	}
+	if t.Data != nil {
+		clone.Data = make(map[TickerName]*Ticker, len(t.Data))
+		for name, ticker := range t.Data {
+			clone.Data[name] = ticker.Clone()
+		}
+	}
+	return &clone
+}
+
 // TickRepository represents the tick snapshot repository contract
 type TickRepository interface {
+	// Create stores the tick, replacing the tick stored with the same StorageKey,
+	// so storing a tick again after a failure reported for a write that went through doesn't create a duplicate
 	Create(ctx context.Context, ts Tick) error
 	GetHistorySince(ctx context.Context, since time.Time) ([]Tick, error)
+	GetRange(ctx context.Context, from, to time.Time) ([]Tick, error)
 }
 
-// CalculateIndicators calculates the indicators for the current tick based on the history data
-func (t *Tick) CalculateIndicators(history *utils.RingBuffer[*Tick]) {
+// TickPageRepository is implemented by the tick repositories able to read a page of stored ticks, so a reader
+// doesn't load the whole range to keep its first ticks
+type TickPageRepository interface {
+	// GetPage returns at most limit ticks created since the given time ordered by their creation time
+	GetPage(ctx context.Context, since time.Time, limit int) ([]Tick, error)
+}
+
+// RecomputedTickRepository stores ticks with recomputed indicators, keyed by IndicatorsVersion and StartAt
+// so that recomputing the same range again replaces the previous results of that version
+type RecomputedTickRepository interface {
+	SaveMany(ctx context.Context, ticks []Tick) error
+}
+
+// StorageKey returns the second the tick started at, a repository (one per exchange) stores one tick per second
+func (t *Tick) StorageKey() time.Time {
+	return t.StartAt.Truncate(time.Second)
+}
+
+// MedianEventSkew returns the median EventSkew of the tickers stamped by the exchange (their own or the response
+// timestamp) against FetchedAt, false when none is. Tickers stamped with the receive time carry no exchange clock
+func (t *Tick) MedianEventSkew() (time.Duration, bool) {
+	skews := make([]time.Duration, 0, len(t.Data))
+	for _, ticker := range t.Data {
+		if ticker == nil || ticker.EventAtSource == EventAtSourceReceived {
+			continue
+		}
+		if skew, ok := EventSkew(ticker.EventAt, t.FetchedAt); ok {
+			skews = append(skews, skew)
+		}
+	}
+	if len(skews) == 0 {
+		return 0, false
+	}
+	return MedianDuration(skews), true
+}
+
+// CalculateIndicators calculates the indicators for the current tick based on the history data, rounded with precision
+func (t *Tick) CalculateIndicators(history *utils.RingBuffer[*Tick], precision Precision) {
 	if history.Len() < 2 {
 		return
 	}
@@ -71,38 +211,80 @@ func (t *Tick) CalculateIndicators(history *utils.RingBuffer[*Tick]) {
 		for i := history.Len() - 10; i < history.Len(); i++ {
 			sumTickAvgBuyOpen += history.At(i).Avg.AskChange
 		}
-		t.AvgBuy10 = mathutils.Round(sumTickAvgBuyOpen/10, 6)
+		t.AvgBuy10 = mathutils.RoundTo(sumTickAvgBuyOpen/10, precision.AvgBuy10)
 	}
 
-	// Calculate the averages for the current tick
-	var sumSellDiff, sumBuyDiff, sumPd, sumPd20, sumMax10, sumMin10, count float64
+	// Calculate the simple and the notional weighted averages for the current tick, and the simple ones per category
+	var mean, weighted tickAvgSum
+	var categories map[string]*tickAvgSum
 	for _, tickerCurrData := range t.Data {
+		if tickerCurrData.Illiquid {
+			continue
+		}
 		tickerPrevData, ok := prevTick.Data[tickerCurrData.Symbol]
 		if !ok {
 			continue
 		}
-		count++
 
-		buyDiff := mathutils.Clamp(mathutils.PercDiff(tickerCurrData.Ask, tickerPrevData.Ask, 2), -1, 1)
-		sellDiff := mathutils.Clamp(mathutils.PercDiff(tickerCurrData.Bid, tickerPrevData.Bid, 2), -1, 1)
-		sumBuyDiff += buyDiff
-		sumSellDiff += sellDiff
+		mean.add(tickerCurrData, tickerPrevData, 1, precision)
+		if tickerCurrData.Notional > 0 {
+			weighted.add(tickerCurrData, tickerPrevData, tickerCurrData.Notional, precision)
+		}
+		for _, category := range tickerCurrData.Categories {
+			if categories == nil {
+				categories = make(map[string]*tickAvgSum)
+			}
+			sum, ok := categories[category]
+			if !ok {
+				sum = &tickAvgSum{}
+				categories[category] = sum
+			}
+			sum.add(tickerCurrData, tickerPrevData, 1, precision)
+		}
+	}
+	mean.apply(&t.Avg, precision)
+	weighted.apply(&t.AvgWeighted, precision)
+	if len(categories) > 0 {
+		t.CategoryAvg = make(map[string]TickAvg, len(categories))
+		for category, sum := range categories {
+			var avg TickAvg
+			sum.apply(&avg, precision)
+			t.CategoryAvg[category] = avg
+		}
+	}
+}
 
-		sumPd += tickerCurrData.Change1m
-		sumPd20 += tickerCurrData.Change20m
+// tickAvgSum accumulates the weighted ticker changes averaged into a TickAvg
+type tickAvgSum struct {
+	sellDiff, buyDiff, pd, pd20, max10, min10 float64
+	weight                                    float64
+	count                                     int16
+}
 
-		sumMax10 += mathutils.PercDiff(tickerCurrData.Ask, tickerCurrData.Max10, -1)
-		sumMin10 += mathutils.PercDiff(tickerCurrData.Ask, tickerCurrData.Min10, -1)
-	}
-	if count > 0 {
-		t.Avg.BidChange = mathutils.Round(sumSellDiff/count, 4)
-		t.Avg.AskChange = mathutils.Round(sumBuyDiff/count, 4)
-		t.Avg.Change1m = mathutils.Round(sumPd/count, 2)
-		t.Avg.Change20m = mathutils.Round(sumPd20/count, 2)
-		t.Avg.Max10 = mathutils.Round(sumMax10/count, 2)
-		t.Avg.Min10 = mathutils.Round(sumMin10/count, 2)
-		t.Avg.TickersCount = int16(count)
+// add adds the changes of a ticker since the previous tick with the given weight
+func (s *tickAvgSum) add(curr, prev *Ticker, weight float64, precision Precision) {
+	s.buyDiff += weight * mathutils.Clamp(mathutils.PercDiff(curr.Ask, prev.Ask, precision.Change), -1, 1)
+	s.sellDiff += weight * mathutils.Clamp(mathutils.PercDiff(curr.Bid, prev.Bid, precision.Change), -1, 1)
+	s.pd += weight * curr.Change1m
+	s.pd20 += weight * curr.Change20m
+	s.max10 += weight * mathutils.PercDiff(curr.Ask, curr.Max10, -1)
+	s.min10 += weight * mathutils.PercDiff(curr.Ask, curr.Min10, -1)
+	s.weight += weight
+	s.count++
+}
+
+// apply sets the averages, it leaves avg untouched when no ticker was added
+func (s *tickAvgSum) apply(avg *TickAvg, precision Precision) {
+	if s.count == 0 {
+		return
 	}
+	avg.BidChange = mathutils.RoundTo(s.sellDiff/s.weight, precision.AvgQuote)
+	avg.AskChange = mathutils.RoundTo(s.buyDiff/s.weight, precision.AvgQuote)
+	avg.Change1m = mathutils.RoundTo(s.pd/s.weight, precision.AvgTrend)
+	avg.Change20m = mathutils.RoundTo(s.pd20/s.weight, precision.AvgTrend)
+	avg.Max10 = mathutils.RoundTo(s.max10/s.weight, precision.AvgTrend)
+	avg.Min10 = mathutils.RoundTo(s.min10/s.weight, precision.AvgTrend)
+	avg.TickersCount = s.count
 }
 
 // SetTicker sets a ticker in the tick snapshot
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// CheckSeverity tells what a failed TickCheck does to the tick
+type CheckSeverity string
+
+const (
+	// SeverityReject drops the tick like a failed Tick.Validate
+	SeverityReject CheckSeverity = "reject"
+
+	// SeveritySuspect keeps the tick and lists the failed check in Tick.Suspect
+	SeveritySuspect CheckSeverity = "suspect"
+)
+
+// Names of the built-in tick checks
+const (
+	CheckFetchDuration = "fetch_duration"
+	CheckTickersCount  = "tickers_count"
+	CheckAvgChange     = "avg_change"
+)
+
+// TickCheck is a cross-field check of a built tick. Check receives the previous ticks, oldest first,
+// and returns an error describing the problem when the tick fails it
+type TickCheck struct {
+	Name     string
+	Severity CheckSeverity
+	Check    func(tick *Tick, previous []*Tick) error
+}
+
+// TickValidator runs Tick.Validate and the registered checks on built ticks
+type TickValidator struct {
+	mu     sync.RWMutex
+	checks []TickCheck
+}
+
+// NewTickValidator creates a TickValidator running the given checks in order
+func NewTickValidator(checks ...TickCheck) *TickValidator {
+	v := &TickValidator{}
+	for _, check := range checks {
+		v.Register(check)
+	}
+	return v
+}
+
+// Register adds a check, a check registered under an existing name replaces it
+func (v *TickValidator) Register(check TickCheck) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if i := slices.IndexFunc(v.checks, func(c TickCheck) bool { return c.Name == check.Name }); i >= 0 {
+		v.checks[i] = check
+		return
+	}
+	v.checks = append(v.checks, check)
+}
+
+// Checks returns the names of the registered checks
+func (v *TickValidator) Checks() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	names := make([]string, 0, len(v.checks))
+	for _, check := range v.checks {
+		names = append(names, check.Name)
+	}
+	return names
+}
+
+// Validate validates the tick and runs every check. A failed reject check is returned as a ValidationError
+// after all checks ran, failed suspect checks are listed in tick.Suspect as "<name>: <problem>"
+func (v *TickValidator) Validate(tick *Tick, previous []*Tick) error {
+	if err := tick.Validate(); err != nil {
+		return err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var rejected []error
+	for _, check := range v.checks {
+		err := check.Check(tick, previous)
+		if err == nil {
+			continue
+		}
+		if check.Severity == SeverityReject {
+			rejected = append(rejected, ValidationError{Field: check.Name, Err: err})
+			continue
+		}
+		tick.Suspect = append(tick.Suspect, fmt.Sprintf("%s: %v", check.Name, err))
+	}
+	return errors.Join(rejected...)
+}
+
+// FetchDurationCheck fails ticks whose tickers took longer than limit to fetch, e.g. the import interval,
+// as their prices are older than the tick claims
+func FetchDurationCheck(limit time.Duration, severity CheckSeverity) TickCheck {
+	return TickCheck{
+		Name:     CheckFetchDuration,
+		Severity: severity,
+		Check: func(tick *Tick, _ []*Tick) error {
+			if took := time.Duration(tick.FetchDuration) * time.Millisecond; took > limit {
+				return fmt.Errorf("fetched in %s, limit %s", took, limit)
+			}
+			return nil
+		},
+	}
+}
+
+// TickersCountCheck fails ticks holding less tickers than the previous tick by more than maxDrop (0.5 for 50%),
+// e.g. a truncated exchange response; the first tick isn't checked
+func TickersCountCheck(maxDrop float64, severity CheckSeverity) TickCheck {
+	return TickCheck{
+		Name:     CheckTickersCount,
+		Severity: severity,
+		Check: func(tick *Tick, previous []*Tick) error {
+			if len(previous) == 0 {
+				return nil
+			}
+			prevCount := len(previous[len(previous)-1].Data)
+			if prevCount == 0 {
+				return nil
+			}
+			if drop := 1 - float64(len(tick.Data))/float64(prevCount); drop > maxDrop {
+				return fmt.Errorf("%d tickers, %d in the previous tick", len(tick.Data), prevCount)
+			}
+			return nil
+		},
+	}
+}
+
+// AvgChangeCheck fails ticks whose market average moved by more than limit % in a minute, which happens
+// on bad exchange data far more often than on an actual market move of the whole market
+func AvgChangeCheck(limit float64, severity CheckSeverity) TickCheck {
+	return TickCheck{
+		Name:     CheckAvgChange,
+		Severity: severity,
+		Check: func(tick *Tick, _ []*Tick) error {
+			if change := tick.Avg.Change1m; math.Abs(change) > limit {
+				return fmt.Errorf("average changed %.2f%% in 1m, limit %g%%", change, limit)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCheckedTick(tickers int) *Tick {
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := &Tick{StartAt: startAt, FetchedAt: startAt, CreatedAt: startAt, Data: make(map[TickerName]*Ticker)}
+	for i := 0; i < tickers; i++ {
+		symbol := TickerName(string(rune('A'+i)) + "USDT")
+		tick.Data[symbol] = &Ticker{Symbol: symbol, Ask: 1, Bid: 1}
+	}
+	return tick
+}
+
+func TestTickValidator_Validate(t *testing.T) {
+	previous := []*Tick{newCheckedTick(10)}
+
+	tests := []struct {
+		name        string
+		tick        func() *Tick
+		checks      []TickCheck
+		wantErr     []string // fields of the returned ValidationErrors
+		wantSuspect []string
+	}{
+		{
+			name:   "passing checks",
+			tick:   func() *Tick { return newCheckedTick(10) },
+			checks: []TickCheck{FetchDurationCheck(time.Second, SeverityReject), TickersCountCheck(0.5, SeverityReject)},
+		},
+		{
+			name: "suspect checks mark the tick",
+			tick: func() *Tick {
+				tick := newCheckedTick(4)
+				tick.FetchDuration = 1500
+				return tick
+			},
+			checks: []TickCheck{FetchDurationCheck(time.Second, SeveritySuspect), TickersCountCheck(0.5, SeveritySuspect)},
+			wantSuspect: []string{
+				"fetch_duration: fetched in 1.5s, limit 1s",
+				"tickers_count: 4 tickers, 10 in the previous tick",
+			},
+		},
+		{
+			name: "reject checks fail the tick after every check ran",
+			tick: func() *Tick {
+				tick := newCheckedTick(10)
+				tick.FetchDuration = 1500
+				tick.Avg.Change1m = -12
+				return tick
+			},
+			checks:      []TickCheck{AvgChangeCheck(10, SeverityReject), FetchDurationCheck(time.Second, SeveritySuspect)},
+			wantErr:     []string{CheckAvgChange},
+			wantSuspect: []string{"fetch_duration: fetched in 1.5s, limit 1s"},
+		},
+		{
+			name:    "tick validation runs first",
+			tick:    func() *Tick { return &Tick{} },
+			checks:  []TickCheck{AvgChangeCheck(10, SeverityReject)},
+			wantErr: []string{"StartAt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tick := tt.tick()
+			err := NewTickValidator(tt.checks...).Validate(tick, previous)
+
+			var fields []string
+			for _, e := range unwrapJoined(err) {
+				var validationErr ValidationError
+				require.True(t, errors.As(e, &validationErr), "unexpected error %v", e)
+				fields = append(fields, validationErr.Field)
+			}
+			assert.Equal(t, tt.wantErr, fields)
+			assert.Equal(t, tt.wantSuspect, tick.Suspect)
+		})
+	}
+}
+
+func TestTickValidator_Register(t *testing.T) {
+	v := NewTickValidator(FetchDurationCheck(time.Second, SeveritySuspect), AvgChangeCheck(10, SeveritySuspect))
+	v.Register(FetchDurationCheck(time.Second, SeverityReject))
+	v.Register(TickCheck{Name: "custom", Severity: SeverityReject, Check: func(*Tick, []*Tick) error { return errors.New("bad") }})
+	assert.Equal(t, []string{CheckFetchDuration, CheckAvgChange, "custom"}, v.Checks(), "a check replaces the one of the same name in place")
+
+	tick := newCheckedTick(1)
+	tick.FetchDuration = 2000
+	err := v.Validate(tick, nil)
+	assert.ErrorContains(t, err, "fetch_duration")
+	assert.ErrorContains(t, err, "custom: bad")
+	assert.Empty(t, tick.Suspect)
+}
+
+func TestTickersCountCheck_NoHistory(t *testing.T) {
+	check := TickersCountCheck(0.5, SeverityReject)
+	assert.NoError(t, check.Check(newCheckedTick(1), nil))
+	assert.NoError(t, check.Check(newCheckedTick(1), []*Tick{{}}))
+	assert.NoError(t, check.Check(newCheckedTick(5), []*Tick{newCheckedTick(10)}), "a drop of exactly the limit passes")
+}
+
+// unwrapJoined returns the errors joined with errors.Join, or err itself
+func unwrapJoined(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+)
+
+// qualityDecimals are the decimals the quality score and its components are stored with
+const qualityDecimals = 3
+
+// TickQuality scores how reliable the data of a tick is, so consumers can weight or discard low-quality ticks.
+// Every component is scored from 0 to 1 and Score is their mean; ticks stored before scoring have a zero quality
+type TickQuality struct {
+	Score float64 `db:"score" json:"score" bson:"score"`
+
+	// Updated is the share of the symbols of this and the previous tick present in this tick
+	Updated float64 `db:"upd" json:"upd" bson:"upd"`
+
+	// ConversionFailures is the share of the fetched tickers that failed conversion
+	ConversionFailures float64 `db:"cf" json:"cf" bson:"cf"`
+
+	// FetchBudget is the fetch duration over the fetch budget, above 1 when the fetch ran over budget
+	FetchBudget float64 `db:"fb" json:"fb" bson:"fb"`
+
+	// LiquidationsAge is the time since the last message of the liquidation stream in milliseconds,
+	// -1 when liquidations aren't streamed
+	LiquidationsAge int64 `db:"liq_age" json:"liq_age" bson:"liq_age"`
+}
+
+// Components returns the scores of the components of the quality, keyed by component. A fetch scores 1 within
+// its budget down to 0 at twice the budget, the liquidation stream 1 until staleAfter down to 0 at twice staleAfter
+func (q TickQuality) Components(staleAfter time.Duration) map[string]float64 {
+	components := map[string]float64{
+		"updated":    q.Updated,
+		"conversion": 1 - q.ConversionFailures,
+		"fetch":      decay(q.FetchBudget),
+	}
+	if q.LiquidationsAge >= 0 && staleAfter > 0 {
+		components["liquidations"] = decay(float64(q.LiquidationsAge) / float64(staleAfter.Milliseconds()))
+	}
+	return components
+}
+
+// CalculateScore sets Score to the mean of the component scores, see Components
+func (q *TickQuality) CalculateScore(staleAfter time.Duration) {
+	components := q.Components(staleAfter)
+	var sum float64
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		sum += components[name]
+	}
+	q.Updated = mathutils.RoundTo(q.Updated, qualityDecimals)
+	q.ConversionFailures = mathutils.RoundTo(q.ConversionFailures, qualityDecimals)
+	q.FetchBudget = mathutils.RoundTo(q.FetchBudget, qualityDecimals)
+	q.Score = mathutils.RoundTo(sum/float64(len(components)), qualityDecimals)
+}
+
+// UpdatedShare returns the share of the symbols of the tick and the previous one present in the tick, 1 without
+// a previous tick
+func (t *Tick) UpdatedShare(previous *Tick) float64 {
+	if previous == nil {
+		return 1
+	}
+	expected := len(t.Data)
+	for symbol := range previous.Data {
+		if _, ok := t.Data[symbol]; !ok {
+			expected++
+		}
+	}
+	if expected == 0 {
+		return 1
+	}
+	return float64(len(t.Data)) / float64(expected)
+}
+
+// decay scores a ratio to its limit: 1 up to the limit, down to 0 at twice the limit
+func decay(ratio float64) float64 {
+	return min(max(2-ratio, 0), 1)
+}
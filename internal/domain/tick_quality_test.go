@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickQuality_CalculateScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		quality    TickQuality
+		wantScore  float64
+		components map[string]float64
+	}{
+		{
+			name:       "healthy tick",
+			quality:    TickQuality{Updated: 1, FetchBudget: 0.4, LiquidationsAge: 1000},
+			wantScore:  1,
+			components: map[string]float64{"updated": 1, "conversion": 1, "fetch": 1, "liquidations": 1},
+		},
+		{
+			name:       "liquidations not streamed",
+			quality:    TickQuality{Updated: 0.9, ConversionFailures: 0.1, FetchBudget: 1.5, LiquidationsAge: -1},
+			wantScore:  0.767,
+			components: map[string]float64{"updated": 0.9, "conversion": 0.9, "fetch": 0.5},
+		},
+		{
+			name:       "stale liquidation stream and slow fetch",
+			quality:    TickQuality{Updated: 1, FetchBudget: 3, LiquidationsAge: (3 * time.Minute).Milliseconds()},
+			wantScore:  0.625,
+			components: map[string]float64{"updated": 1, "conversion": 1, "fetch": 0, "liquidations": 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quality := tt.quality
+			quality.CalculateScore(2 * time.Minute)
+			assert.Equal(t, tt.wantScore, quality.Score)
+			assert.InDeltaMapValues(t, tt.components, quality.Components(2*time.Minute), 1e-9)
+		})
+	}
+}
+
+func TestTick_UpdatedShare(t *testing.T) {
+	previous := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {}, "ETHUSDT": {}, "SOLUSDT": {}}}
+	tick := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {}, "ETHUSDT": {}, "XRPUSDT": {}}}
+
+	assert.Equal(t, 0.75, tick.UpdatedShare(previous), "SOLUSDT is missing")
+	assert.Equal(t, 1.0, tick.UpdatedShare(nil))
+	assert.Equal(t, 1.0, (&Tick{}).UpdatedShare(&Tick{}))
+}
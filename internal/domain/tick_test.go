@@ -7,6 +7,7 @@ import (
 	"github.com/ayankousky/exchange-data-importer/pkg/utils"
 	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCalculateIndicators(t *testing.T) {
@@ -49,7 +50,7 @@ func TestCalculateIndicators(t *testing.T) {
 
 	// Execute CalculateIndicators
 	currentTick, _ := history.Last()
-	currentTick.CalculateIndicators(history)
+	currentTick.CalculateIndicators(history, DefaultPrecision())
 
 	// Validate results
 	assert.Equal(t, 0.45, currentTick.AvgBuy10, "AvgBuy10 should match expected value")
@@ -62,14 +63,63 @@ func TestCalculateIndicators(t *testing.T) {
 	assert.Equal(t, int16(2), currentTick.Avg.TickersCount, "TickersCount should match expected value")
 
 	currentTick.Data["BTCUSDT"].Ask *= 10
-	currentTick.CalculateIndicators(history)
+	currentTick.CalculateIndicators(history, DefaultPrecision())
 	assert.Equal(t, 0.74, currentTick.Avg.AskChange, "Cover the case when diff more than 1% BidChange")
 
 	currentTick.Data["BTCUSDT"].Ask /= 100
-	currentTick.CalculateIndicators(history)
+	currentTick.CalculateIndicators(history, DefaultPrecision())
 	assert.Equal(t, -0.26, currentTick.Avg.AskChange, "Cover the case when diff more than 1% BidChange")
 }
 
+func TestCalculateIndicators_Weighted(t *testing.T) {
+	history := utils.NewRingBuffer[*Tick](2)
+	history.Push(&Tick{Data: map[TickerName]*Ticker{
+		"BTCUSDT":  {Symbol: "BTCUSDT", Ask: 100, Bid: 99},
+		"DUSTUSDT": {Symbol: "DUSTUSDT", Ask: 1, Bid: 0.9},
+		"NEWUSDT":  {Symbol: "NEWUSDT", Ask: 10, Bid: 9},
+	}})
+	history.Push(&Tick{Data: map[TickerName]*Ticker{
+		"BTCUSDT":  {Symbol: "BTCUSDT", Ask: 100, Bid: 99, Change1m: 1, Change20m: 2, Notional: 900_000},
+		"DUSTUSDT": {Symbol: "DUSTUSDT", Ask: 1, Bid: 0.9, Change1m: 11, Change20m: 22, Notional: 100_000},
+		"NEWUSDT":  {Symbol: "NEWUSDT", Ask: 10, Bid: 9, Change1m: 50, Change20m: 50}, // no known notional
+	}})
+
+	currentTick, _ := history.Last()
+	currentTick.CalculateIndicators(history, DefaultPrecision())
+
+	assert.Equal(t, 20.67, currentTick.Avg.Change1m, "the simple mean weighs every ticker equally")
+	assert.Equal(t, int16(3), currentTick.Avg.TickersCount)
+	assert.Equal(t, 2.0, currentTick.AvgWeighted.Change1m, "the weighted average follows the liquid ticker")
+	assert.Equal(t, 4.0, currentTick.AvgWeighted.Change20m)
+	assert.Equal(t, int16(2), currentTick.AvgWeighted.TickersCount, "tickers without a notional are left out")
+}
+
+func TestCalculateIndicators_Categories(t *testing.T) {
+	history := utils.NewRingBuffer[*Tick](2)
+	history.Push(&Tick{Data: map[TickerName]*Ticker{
+		"BTCUSDT":  {Symbol: "BTCUSDT", Ask: 100, Bid: 99},
+		"UNIUSDT":  {Symbol: "UNIUSDT", Ask: 10, Bid: 9},
+		"AAVEUSDT": {Symbol: "AAVEUSDT", Ask: 200, Bid: 199},
+		"CRVUSDT":  {Symbol: "CRVUSDT", Ask: 1, Bid: 0.9},
+	}})
+	history.Push(&Tick{Data: map[TickerName]*Ticker{
+		"BTCUSDT":  {Symbol: "BTCUSDT", Ask: 100, Bid: 99, Change1m: 1, Categories: []string{"L1"}},
+		"UNIUSDT":  {Symbol: "UNIUSDT", Ask: 10, Bid: 9, Change1m: -2, Categories: []string{"DeFi"}},
+		"AAVEUSDT": {Symbol: "AAVEUSDT", Ask: 200, Bid: 199, Change1m: -4, Categories: []string{"DeFi", "lending"}},
+		"CRVUSDT":  {Symbol: "CRVUSDT", Ask: 1, Bid: 0.9, Change1m: -30, Categories: []string{"DeFi"}, Illiquid: true},
+	}})
+
+	currentTick, _ := history.Last()
+	currentTick.CalculateIndicators(history, DefaultPrecision())
+
+	assert.Len(t, currentTick.CategoryAvg, 3)
+	assert.Equal(t, 1.0, currentTick.CategoryAvg["L1"].Change1m)
+	assert.Equal(t, -3.0, currentTick.CategoryAvg["DeFi"].Change1m, "illiquid tickers are left out")
+	assert.Equal(t, int16(2), currentTick.CategoryAvg["DeFi"].TickersCount)
+	assert.Equal(t, -4.0, currentTick.CategoryAvg["lending"].Change1m)
+	assert.Equal(t, -1.67, currentTick.Avg.Change1m)
+}
+
 func TestTick_Validate(t *testing.T) {
 	defaultDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	validTicker := &Ticker{
@@ -175,6 +225,61 @@ func TestTick_Validate(t *testing.T) {
 	}
 }
 
+func TestTick_StorageKey(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 5, 0, time.UTC)
+	first := Tick{StartAt: start.Add(3 * time.Millisecond)}
+	retried := Tick{StartAt: start.Add(900 * time.Millisecond)}
+	next := Tick{StartAt: start.Add(time.Second)}
+
+	assert.Equal(t, start, first.StorageKey())
+	assert.Equal(t, first.StorageKey(), retried.StorageKey())
+	assert.NotEqual(t, first.StorageKey(), next.StorageKey())
+}
+
+func TestTick_Clone(t *testing.T) {
+	tick := &Tick{
+		StartAt:     time.Date(2025, 1, 1, 12, 0, 5, 0, time.UTC),
+		LL1:         3,
+		Skipped:     []TickerName{"DOGEUSDT"},
+		Suspect:     []string{"avg_range: out of range"},
+		Errors:      []TickError{{Stage: TickStageLiquidations, Fields: []string{"ll_1"}, Error: "timeout"}},
+		CategoryAvg: map[string]TickAvg{"L1": {Change1m: 1}},
+		Data: map[TickerName]*Ticker{
+			"BTCUSDT": {
+				Symbol:          "BTCUSDT",
+				Ask:             100,
+				Categories:      []string{"L1"},
+				LastLiquidation: &LastLiquidation{Price: 99},
+				Stats24h:        &RollingStats{High: 110},
+			},
+		},
+	}
+	clone := tick.Clone()
+	assert.Equal(t, tick, clone)
+
+	tick.LL1 = 4
+	tick.Skipped[0] = "SHIBUSDT"
+	tick.Suspect[0] = "changed"
+	tick.Errors[0].Fields[0] = "sl_1"
+	tick.CategoryAvg["L1"] = TickAvg{Change1m: 2}
+	tick.Data["BTCUSDT"].Ask = 101
+	tick.Data["BTCUSDT"].Categories[0] = "meme"
+	tick.Data["BTCUSDT"].LastLiquidation.Price = 98
+	tick.Data["BTCUSDT"].Stats24h.High = 120
+	tick.Data["ETHUSDT"] = &Ticker{Symbol: "ETHUSDT"}
+
+	assert.Equal(t, int64(3), clone.LL1)
+	assert.Equal(t, []TickerName{"DOGEUSDT"}, clone.Skipped)
+	assert.Equal(t, []string{"avg_range: out of range"}, clone.Suspect)
+	assert.Equal(t, []string{"ll_1"}, clone.Errors[0].Fields)
+	assert.Len(t, clone.Data, 1)
+	assert.Equal(t, 1.0, clone.CategoryAvg["L1"].Change1m)
+	assert.Equal(t, 100.0, clone.Data["BTCUSDT"].Ask)
+	assert.Equal(t, []string{"L1"}, clone.Data["BTCUSDT"].Categories)
+	assert.Equal(t, 99.0, clone.Data["BTCUSDT"].LastLiquidation.Price)
+	assert.Equal(t, 110.0, clone.Data["BTCUSDT"].Stats24h.High)
+}
+
 func TestCalculateIndicators_EdgeCases(t *testing.T) {
 	t.Run("empty history", func(t *testing.T) {
 		// Create a new empty history
@@ -200,7 +305,7 @@ func TestCalculateIndicators_EdgeCases(t *testing.T) {
 		initialAvgBidChange := tick.Avg.BidChange
 
 		// Call CalculateIndicators with empty history
-		tick.CalculateIndicators(history)
+		tick.CalculateIndicators(history, DefaultPrecision())
 
 		// Values should remain unchanged
 		assert.Equal(t, initialAvgAskChange, tick.Avg.AskChange, "AskChange should remain unchanged with empty history")
@@ -231,7 +336,7 @@ func TestCalculateIndicators_EdgeCases(t *testing.T) {
 		initialAvgBidChange := tick.Avg.BidChange
 
 		// Call CalculateIndicators with history of length 1
-		tick.CalculateIndicators(history)
+		tick.CalculateIndicators(history, DefaultPrecision())
 
 		// Values should remain unchanged
 		assert.Equal(t, initialAvgAskChange, tick.Avg.AskChange, "AskChange should remain unchanged with history length of 1")
@@ -287,10 +392,60 @@ func TestCalculateIndicators_EdgeCases(t *testing.T) {
 		history.Push(secondTick)
 
 		// Call CalculateIndicators
-		secondTick.CalculateIndicators(history)
+		secondTick.CalculateIndicators(history, DefaultPrecision())
 
 		// Only ETHUSDT should contribute to the averages
 		// BTCUSDT should be skipped since it's not in the previous tick
 		assert.Equal(t, int16(1), secondTick.Avg.TickersCount, "Only one ticker should be counted in averages")
 	})
+
+	t.Run("illiquid tickers are excluded from averages", func(t *testing.T) {
+		history := utils.NewRingBuffer[*Tick](MaxTickHistory)
+		history.Push(&Tick{
+			Data: map[TickerName]*Ticker{
+				"ETHUSDT":  {Symbol: "ETHUSDT", Ask: 2000, Bid: 1990},
+				"DUSTUSDT": {Symbol: "DUSTUSDT", Ask: 0.01, Bid: 0.009},
+			},
+		})
+		tick := &Tick{
+			Data: map[TickerName]*Ticker{
+				"ETHUSDT":  {Symbol: "ETHUSDT", Ask: 2000, Bid: 1990, Change1m: 1},
+				"DUSTUSDT": {Symbol: "DUSTUSDT", Ask: 0.02, Bid: 0.019, Change1m: 100, Illiquid: true},
+			},
+		}
+		history.Push(tick)
+
+		tick.CalculateIndicators(history, DefaultPrecision())
+
+		assert.Equal(t, int16(1), tick.Avg.TickersCount, "Illiquid ticker should not be counted in averages")
+		assert.Equal(t, 1.0, tick.Avg.Change1m)
+	})
+}
+
+func TestTick_MedianEventSkew(t *testing.T) {
+	fetchedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tick := &Tick{FetchedAt: fetchedAt, Data: map[TickerName]*Ticker{}}
+	_, ok := tick.MedianEventSkew()
+	assert.False(t, ok, "no tickers")
+
+	tick.Data["BTCUSDT"] = &Ticker{EventAt: fetchedAt.Add(-time.Second), EventAtSource: "exchange"}
+	tick.Data["ETHUSDT"] = &Ticker{EventAt: fetchedAt.Add(-3 * time.Second), EventAtSource: "response"}
+	tick.Data["SOLUSDT"] = &Ticker{EventAt: fetchedAt.Add(-time.Hour)} // stored before the source was recorded
+	tick.Data["XRPUSDT"] = &Ticker{EventAt: fetchedAt, EventAtSource: EventAtSourceReceived}
+	tick.Data["DOGEUSDT"] = &Ticker{}
+
+	skew, ok := tick.MedianEventSkew()
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, skew, "the receive-stamped and the unstamped tickers are left out")
+
+	tick.Data["ETHUSDT"].EventAt = fetchedAt.Add(2 * time.Second)
+	tick.Data["SOLUSDT"].EventAt = fetchedAt.Add(2 * time.Second)
+	skew, _ = tick.MedianEventSkew()
+	assert.Equal(t, -2*time.Second, skew, "an exchange clock ahead skews negatively")
+}
+
+func TestMedianDuration(t *testing.T) {
+	assert.Zero(t, MedianDuration(nil))
+	assert.Equal(t, 2*time.Second, MedianDuration([]time.Duration{3 * time.Second, time.Second, 2 * time.Second}))
+	assert.Equal(t, 1500*time.Millisecond, MedianDuration([]time.Duration{2 * time.Second, time.Second}))
 }
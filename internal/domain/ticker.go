@@ -2,17 +2,19 @@ package domain
 
 import (
 	"fmt"
-	"math"
+	"slices"
 	"time"
 
-	"github.com/ayankousky/exchange-data-importer/pkg/utils"
 	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
-	"github.com/ayankousky/exchange-data-importer/pkg/utils/tradeutils"
 )
 
 // TickerName represents a market symbol
 type TickerName string
 
+// EventAtSourceReceived is the EventAtSource of the tickers the exchange gave no timestamp for, stamped with
+// the local receive time instead
+const EventAtSourceReceived = "received"
+
 // Ticker represents a market symbol's snapshot at a given time
 // Use short names to save space in the database but readable enough for human
 type Ticker struct {
@@ -37,13 +39,67 @@ type Ticker struct {
 	Min10     float64 `db:"min_10"    json:"min_10"    bson:"min_10"`
 	Max10Diff float64 `db:"max_10_diff" json:"max_10_diff" bson:"max_10_diff"` // (Ask - Max10) / Max10 * 100
 	Min10Diff float64 `db:"min_10_diff" json:"min_10_diff" bson:"min_10_diff"` // (Ask - Min10) / Min10 * 100
+
+	// Illiquid marks dust pairs with a bid/ask notional below the configured minimum,
+	// they are stored but kept out of market averages and alerts
+	Illiquid bool `db:"il" json:"il,omitempty" bson:"il,omitempty"`
+
+	// QuoteRate is the USD rate of the quote asset Ask/Bid were normalized with,
+	// 0 when the prices are stored as quoted (USDT/USD or normalization disabled)
+	QuoteRate float64 `db:"qr" json:"qr,omitempty" bson:"qr,omitempty"`
+
+	// EventAtSource tells where EventAt comes from: exchange (the ticker's own timestamp), response
+	// (the exchange response time) or received (the local receive time), empty in ticks stored before it was recorded
+	EventAtSource string `db:"ets" json:"ets,omitempty" bson:"ets,omitempty"`
+
+	// Notional is the USD value of the thinner side of the top of the book, 0 when the quote asset has no known rate
+	Notional float64 `db:"n" json:"n,omitempty" bson:"n,omitempty"`
+
+	// LastLiquidation is the latest liquidation of the symbol from the live stream, nil if none was seen
+	LastLiquidation *LastLiquidation `db:"ll" json:"ll,omitempty" bson:"ll,omitempty"`
+
+	// Stats24h are the statistics of the symbol over the last 24 hours, see RollingStats
+	Stats24h *RollingStats `db:"s24" json:"s24,omitempty" bson:"s24,omitempty"`
+
+	// OpenInterest is the size of the open positions in base asset units, 0 when the exchange doesn't report it.
+	// OIChange1m and OIChange20m are its % changes since last minute and the last 20 minutes
+	OpenInterest float64 `db:"oi" json:"oi,omitempty" bson:"oi,omitempty"`
+	OIChange1m   float64 `db:"oi_pd" json:"oi_pd,omitempty" bson:"oi_pd,omitempty"`
+	OIChange20m  float64 `db:"oi_pd_20" json:"oi_pd_20,omitempty" bson:"oi_pd_20,omitempty"`
+
+	// Categories (L1, meme, DeFi...) and Tier (market-cap tier) annotate the symbol from the symbol metadata file,
+	// empty without one. The tick averages the tickers of every category into Tick.CategoryAvg
+	Categories []string `db:"cat" json:"cat,omitempty" bson:"cat,omitempty"`
+	Tier       string   `db:"tier" json:"tier,omitempty" bson:"tier,omitempty"`
+
+	// Canonical is the exchange independent symbol, its base and normalized quote assets concatenated
+	// (BTCUSDT for BTC-USDT-SWAP), empty for dated futures and options. See exchanges.SymbolMapper
+	Canonical TickerName `db:"cs" json:"cs,omitempty" bson:"cs,omitempty"`
+}
+
+// Clone returns a deep copy of the ticker, nil for a nil ticker
+func (t *Ticker) Clone() *Ticker {
+	if t == nil {
+		return nil
+	}
+	clone := *t
+	clone.Categories = slices.Clone(t.Categories)
+	if t.LastLiquidation != nil {
+		lastLiquidation := *t.LastLiquidation
+		clone.LastLiquidation = &lastLiquidation
+	}
+	if t.Stats24h != nil {
+		stats := *t.Stats24h
+		clone.Stats24h = &stats
+	}
+	return &clone
 }
 
 // CalculateIndicators calculates the indicators for current moment based on the history data
-// each history item is a minute of data
-func (t *Ticker) CalculateIndicators(history *utils.RingBuffer[*Ticker], lastTick *Tick) {
+// each history item is a minute of data, the last one being the live minute, rounded with precision
+func (t *Ticker) CalculateIndicators(history *TickerHistory, lastTick *Tick, precision Precision) {
 	// Safety checks
-	if t == nil || lastTick == nil || lastTick.Data == nil {
+	if t == nil || history == nil || lastTick == nil || lastTick.Data == nil {
 		return
 	}
 	prevTicker, ok := lastTick.Data[t.Symbol]
@@ -55,39 +111,31 @@ func (t *Ticker) CalculateIndicators(history *utils.RingBuffer[*Ticker], lastTic
 	if historyLength < 2 {
 		return
 	}
+	live := history.At(historyLength - 1)
 
-	t.Change1m = mathutils.PercDiff(t.Bid, history.At(historyLength-2).Bid, 2)
-
-	// Evaluate the last 10 Tickers for max/min
-	min10, max10 := math.MaxFloat64, -1*math.MaxFloat64
-	startPos := max(historyLength-10, 0)
-	for i := startPos; i < historyLength; i++ {
-		h := history.At(i)
-		if h.Ask > max10 {
-			max10 = h.Ask
-		}
-		if h.Ask < min10 {
-			min10 = h.Ask
-		}
+	t.Change1m = mathutils.PercDiff(t.Bid, history.At(historyLength-2).Bid, precision.Change)
+	if t.OpenInterest > 0 {
+		t.OIChange1m = mathutils.PercDiff(t.OpenInterest, history.At(historyLength-2).OpenInterest, precision.Change)
 	}
-	t.Max10 = max10
-	t.Min10 = min10
-	t.Max10Diff = mathutils.PercDiff(t.Ask, t.Max10, 2)
-	t.Min10Diff = mathutils.PercDiff(t.Ask, t.Min10, 2)
 
-	t.AskChange = mathutils.PercDiff(t.Ask, prevTicker.Ask, 2)
-	t.BidChange = mathutils.PercDiff(t.Bid, prevTicker.Bid, 2)
+	// Max/min of the last 10 minutes
+	t.Max10, t.Min10 = history.extremes(live)
+	t.Max10Diff = mathutils.PercDiff(t.Ask, t.Max10, precision.Change)
+	t.Min10Diff = mathutils.PercDiff(t.Ask, t.Min10, precision.Change)
+
+	t.AskChange = mathutils.PercDiff(t.Ask, prevTicker.Ask, precision.Change)
+	t.BidChange = mathutils.PercDiff(t.Bid, prevTicker.Bid, precision.Change)
 
 	// For last 20 minutes calculate: rsi
 	if historyLength > 21 {
-		t.Change20m = mathutils.PercDiff(t.Bid, history.At(historyLength-21).Bid, 2)
+		t.Change20m = mathutils.PercDiff(t.Bid, history.At(historyLength-21).Bid, precision.Change)
+		if t.OpenInterest > 0 {
+			t.OIChange20m = mathutils.PercDiff(t.OpenInterest, history.At(historyLength-21).OpenInterest, precision.Change)
+		}
 
-		// calculate RSI
-		bidHistory := make([]float64, 20)
-		for i := 0; i < 20; i++ {
-			bidHistory[i] = history.At(historyLength - 20 + i).Bid
+		if rsi, ok := history.rsi(live); ok {
+			t.RSI20 = mathutils.RoundTo(rsi, precision.RSI)
 		}
-		t.RSI20 = mathutils.Round(tradeutils.CalculateRSI(bidHistory, 20), 1)
 	}
 }
 
@@ -138,3 +186,17 @@ func (t *Ticker) Validate() error {
 
 	return nil
 }
+
+// Mid returns the mid price between the best ask and bid
+func (t *Ticker) Mid() float64 {
+	return (t.Ask + t.Bid) / 2
+}
+
+// Spread returns the ask/bid spread in % of the mid price
+func (t *Ticker) Spread() float64 {
+	mid := t.Mid()
+	if mid == 0 {
+		return 0
+	}
+	return (t.Ask - t.Bid) / mid * 100
+}
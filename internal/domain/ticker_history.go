@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"github.com/ayankousky/exchange-data-importer/pkg/indicators"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils"
+)
+
+const (
+	// extremesWindow is the number of minutes used for Max10/Min10
+	extremesWindow = 10
+
+	// rsiPeriod is the number of minute moves used for RSI20
+	rsiPeriod = 20
+)
+
+// TickerHistory keeps one Ticker per minute for a symbol together with streaming indicator state.
+// The newest entry is the live minute and keeps changing until the next minute starts, so the
+// streaming state only covers closed minutes and is combined with the live entry on demand.
+// This keeps Ticker.CalculateIndicators O(1) per symbol regardless of the window sizes.
+//
+// The Wilder RSI state is rebuilt from the closed minutes kept in the buffer whenever a minute closes,
+// so RSI20 depends on those minutes only: a restart or a recompute replaying the same minutes yields
+// the same value, indicators.WilderRSIOf of their bids followed by the live bid.
+type TickerHistory struct {
+	buffer *utils.RingBuffer[*Ticker]
+
+	closedMax *indicators.Rolling
+	closedMin *indicators.Rolling
+	closedRSI *indicators.WilderRSI
+}
+
+// NewTickerHistory creates a TickerHistory keeping up to size minutes
+func NewTickerHistory(size int) *TickerHistory {
+	// Closed minutes used together with the live one must not exceed what the buffer holds
+	closedWindow := min(extremesWindow, size) - 1
+	return &TickerHistory{
+		buffer:    utils.NewRingBuffer[*Ticker](size),
+		closedMax: indicators.NewRollingMax(closedWindow),
+		closedMin: indicators.NewRollingMin(closedWindow),
+		closedRSI: indicators.NewWilderRSI(rsiPeriod),
+	}
+}
+
+// Push starts a new minute with the given ticker, closing the previous live minute
+func (h *TickerHistory) Push(ticker *Ticker) {
+	if live, ok := h.buffer.Last(); ok {
+		h.closedMax.Push(live.Ask)
+		h.closedMin.Push(live.Ask)
+	}
+	h.buffer.Push(ticker)
+
+	h.closedRSI = indicators.NewWilderRSI(rsiPeriod)
+	closed := h.buffer.Values()
+	for _, t := range closed[:len(closed)-1] {
+		h.closedRSI.Push(t.Bid)
+	}
+}
+
+// Last returns the live minute, if any
+func (h *TickerHistory) Last() (*Ticker, bool) {
+	return h.buffer.Last()
+}
+
+// At returns the entry at index i (0=oldest, Len()-1=live minute)
+func (h *TickerHistory) At(i int) *Ticker {
+	return h.buffer.At(i)
+}
+
+// Len returns the number of minutes in the history
+func (h *TickerHistory) Len() int {
+	return h.buffer.Len()
+}
+
+// extremes returns the max and min ask over the last extremesWindow minutes including the live one
+func (h *TickerHistory) extremes(live *Ticker) (float64, float64) {
+	if h.closedMax.Len() == 0 {
+		return live.Ask, live.Ask
+	}
+	return max(h.closedMax.Value(), live.Ask), min(h.closedMin.Value(), live.Ask)
+}
+
+// rsi returns the Wilder RSI including the live minute bid
+func (h *TickerHistory) rsi(live *Ticker) (float64, bool) {
+	return h.closedRSI.Peek(live.Bid)
+}
+
+// Values returns the entries from the oldest to the live minute
+func (h *TickerHistory) Values() []*Ticker {
+	return h.buffer.Values()
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/ayankousky/exchange-data-importer/pkg/indicators"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTickerHistory_ExtremesMatchRescan(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(5, 6))
+	history := NewTickerHistory(MaxTickHistory)
+	lastTick := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Ask: 100, Bid: 99}}}
+
+	for minute := 0; minute < 60; minute++ {
+		live := &Ticker{Symbol: "BTCUSDT"}
+		history.Push(live)
+
+		// Several updates within the same minute only change the live entry
+		for update := 0; update < 3; update++ {
+			live.Ask = float64(90 + rnd.IntN(20))
+			live.Bid = live.Ask - 1
+
+			ticker := &Ticker{Symbol: "BTCUSDT", Ask: live.Ask, Bid: live.Bid}
+			ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
+			if history.Len() < 2 {
+				continue
+			}
+
+			wantMax, wantMin := live.Ask, live.Ask
+			for i := max(history.Len()-extremesWindow, 0); i < history.Len(); i++ {
+				wantMax = max(wantMax, history.At(i).Ask)
+				wantMin = min(wantMin, history.At(i).Ask)
+			}
+			assert.Equal(t, wantMax, ticker.Max10, "minute %d", minute)
+			assert.Equal(t, wantMin, ticker.Min10, "minute %d", minute)
+		}
+	}
+}
+
+func TestTickerHistory_RSIRequiresFullWindow(t *testing.T) {
+	history := NewTickerHistory(MaxTickHistory)
+	lastTick := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Ask: 100, Bid: 99}}}
+
+	for i := 0; i < MaxTickHistory; i++ {
+		// Alternate 2 up, 1 down
+		bid := 100 + float64(i/3+i%3)
+		history.Push(&Ticker{Symbol: "BTCUSDT", Ask: bid + 1, Bid: bid})
+
+		ticker := &Ticker{Symbol: "BTCUSDT", Ask: bid + 1, Bid: bid}
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
+		if history.Len() <= 21 {
+			assert.Zero(t, ticker.RSI20, "len %d", history.Len())
+			continue
+		}
+		assert.Greater(t, ticker.RSI20, 50.0)
+		assert.Less(t, ticker.RSI20, 100.0)
+	}
+}
+
+func TestTickerHistory_RSIIndependentOfUptime(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(7, 8))
+	bids := make([]float64, 200)
+	for i := range bids {
+		bids[i] = float64(90 + rnd.IntN(20))
+	}
+	lastTick := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Ask: 100, Bid: 99}}}
+	// the live minute is the last entry of the history
+	rsi := func(history *TickerHistory) float64 {
+		live, _ := history.Last()
+		ticker := &Ticker{Symbol: "BTCUSDT", Ask: live.Ask, Bid: live.Bid}
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
+		return ticker.RSI20
+	}
+
+	longRunning := NewTickerHistory(MaxTickHistory)
+	for _, bid := range bids {
+		longRunning.Push(&Ticker{Symbol: "BTCUSDT", Ask: bid + 1, Bid: bid})
+	}
+	restarted := NewTickerHistory(MaxTickHistory)
+	for _, bid := range bids[len(bids)-MaxTickHistory:] {
+		restarted.Push(&Ticker{Symbol: "BTCUSDT", Ask: bid + 1, Bid: bid})
+	}
+
+	want := rsi(longRunning)
+	assert.NotZero(t, want)
+	assert.Equal(t, want, rsi(restarted), "a fresh history fed the same minutes yields the same RSI20")
+
+	batch, ok := indicators.WilderRSIOf(bids[len(bids)-MaxTickHistory:], rsiPeriod)
+	require.True(t, ok)
+	assert.Equal(t, want, mathutils.Round(batch, 1), "the batch function reproduces the stored RSI20")
+}
+
+func BenchmarkTicker_CalculateIndicators(b *testing.B) {
+	history := NewTickerHistory(MaxTickHistory)
+	for i := 0; i < MaxTickHistory; i++ {
+		history.Push(&Ticker{Symbol: "BTCUSDT", Ask: 100 + float64(i%7), Bid: 99 + float64(i%5)})
+	}
+	lastTick := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Ask: 100, Bid: 99}}}
+	ticker := &Ticker{Symbol: "BTCUSDT", Ask: 103, Bid: 102}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
+	}
+}
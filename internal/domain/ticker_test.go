@@ -4,7 +4,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/ayankousky/exchange-data-importer/pkg/utils"
 	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
 	"github.com/stretchr/testify/assert"
 )
@@ -12,7 +11,7 @@ import (
 func TestTicker_CalculateIndicators(t *testing.T) {
 	// Prepare test data
 	historySize := 32
-	history := utils.NewRingBuffer[*Ticker](historySize)
+	history := NewTickerHistory(historySize)
 	for i := 0; i < historySize; i++ {
 		history.Push(&Ticker{
 			Symbol:    "BTCUSDT",
@@ -37,7 +36,7 @@ func TestTicker_CalculateIndicators(t *testing.T) {
 			Max10:     mathutils.Round(ticker.Max10*0.99, 4),
 			Min10:     mathutils.Round(ticker.Min10*0.99, 4),
 		}}}
-	ticker.CalculateIndicators(history, prevTick)
+	ticker.CalculateIndicators(history, prevTick, DefaultPrecision())
 
 	// Validate results
 	assert.Equal(t, 3069.0, ticker.Bid, "Bid should remain unchanged")
@@ -50,9 +49,39 @@ func TestTicker_CalculateIndicators(t *testing.T) {
 	assert.Equal(t, 40.91, ticker.Min10Diff, "Min10Diff should match expected value")
 
 	ticker.Ask = ticker.Ask * 0.9
-	ticker.CalculateIndicators(history, prevTick)
+	ticker.CalculateIndicators(history, prevTick, DefaultPrecision())
 	assert.Equal(t, -7.0, ticker.Max10Diff, "Max10Diff should increase negative if ask reduced")
 	assert.Equal(t, 26.82, ticker.Min10Diff, "Min10Diff should reduce if ask reduced")
+
+	precision := DefaultPrecision()
+	precision.Change = 4
+	ticker.CalculateIndicators(history, prevTick, precision)
+	assert.Equal(t, 26.8182, ticker.Min10Diff, "Min10Diff with the configured precision")
+
+	precision.Change = -1
+	ticker.CalculateIndicators(history, prevTick, precision)
+	assert.InDelta(t, 26.818181, ticker.Min10Diff, 1e-6, "Min10Diff unrounded")
+	assert.NotEqual(t, 26.8182, ticker.Min10Diff)
+}
+
+func TestTicker_CalculateIndicatorsOpenInterest(t *testing.T) {
+	history := NewTickerHistory(MaxTickHistory)
+	for i := range 22 {
+		history.Push(&Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 100, OpenInterest: 1000 + 10*float64(i)})
+	}
+	ticker, _ := history.Last()
+	prevTick := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Ask: 101, Bid: 100}}}
+
+	ticker.CalculateIndicators(history, prevTick, DefaultPrecision())
+	assert.Equal(t, 0.0, ticker.Change1m, "the price is flat")
+	assert.Equal(t, 0.83, ticker.OIChange1m, "1210 against 1200")
+	assert.Equal(t, 19.8, ticker.OIChange20m, "1210 against 1010")
+
+	unknown := &Ticker{Symbol: "BTCUSDT", Ask: 101, Bid: 100}
+	history.Push(unknown)
+	unknown.CalculateIndicators(history, prevTick, DefaultPrecision())
+	assert.Zero(t, unknown.OIChange1m, "no change without the open interest")
+	assert.Zero(t, unknown.OIChange20m)
 }
 
 func TestTicker_Validate(t *testing.T) {
@@ -169,19 +198,19 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 	// Test for nil safety checks
 	t.Run("nil ticker", func(t *testing.T) {
 		var ticker *Ticker
-		history := utils.NewRingBuffer[*Ticker](10)
+		history := NewTickerHistory(10)
 		lastTick := &Tick{Data: map[TickerName]*Ticker{"BTCUSDT": {Symbol: "BTCUSDT"}}}
 
 		// This should not panic
-		ticker.CalculateIndicators(history, lastTick)
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
 	})
 
 	t.Run("nil lastTick", func(t *testing.T) {
 		ticker := &Ticker{Symbol: "BTCUSDT", Ask: 100, Bid: 99}
-		history := utils.NewRingBuffer[*Ticker](10)
+		history := NewTickerHistory(10)
 
 		// This should not panic
-		ticker.CalculateIndicators(history, nil)
+		ticker.CalculateIndicators(history, nil, DefaultPrecision())
 
 		// Values should remain unchanged
 		assert.Equal(t, TickerName("BTCUSDT"), ticker.Symbol)
@@ -192,11 +221,11 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 
 	t.Run("symbol not in lastTick", func(t *testing.T) {
 		ticker := &Ticker{Symbol: "BTCUSDT", Ask: 100, Bid: 99}
-		history := utils.NewRingBuffer[*Ticker](10)
+		history := NewTickerHistory(10)
 		lastTick := &Tick{Data: map[TickerName]*Ticker{"ETHUSDT": {Symbol: "ETHUSDT"}}}
 
 		// This should not panic
-		ticker.CalculateIndicators(history, lastTick)
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
 
 		// Values should remain unchanged
 		assert.Equal(t, TickerName("BTCUSDT"), ticker.Symbol)
@@ -207,11 +236,11 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 
 	t.Run("nil lastTick.Data", func(t *testing.T) {
 		ticker := &Ticker{Symbol: "BTCUSDT", Ask: 100, Bid: 99}
-		history := utils.NewRingBuffer[*Ticker](10)
+		history := NewTickerHistory(10)
 		lastTick := &Tick{} // Data is nil
 
 		// This should not panic
-		ticker.CalculateIndicators(history, lastTick)
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
 
 		// Values should remain unchanged
 		assert.Equal(t, TickerName("BTCUSDT"), ticker.Symbol)
@@ -222,7 +251,7 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 
 	t.Run("history length < 2", func(t *testing.T) {
 		ticker := &Ticker{Symbol: "BTCUSDT", Ask: 100, Bid: 99}
-		history := utils.NewRingBuffer[*Ticker](10)
+		history := NewTickerHistory(10)
 
 		// Add just one item to history
 		history.Push(&Ticker{Symbol: "BTCUSDT", Ask: 95, Bid: 94})
@@ -232,7 +261,7 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 		}}
 
 		// This should not compute anything
-		ticker.CalculateIndicators(history, lastTick)
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
 
 		// Values should remain unchanged
 		assert.Equal(t, 0.0, ticker.Change1m)
@@ -243,7 +272,7 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 
 	t.Run("history length between 2 and 21", func(t *testing.T) {
 		ticker := &Ticker{Symbol: "BTCUSDT", Ask: 100, Bid: 99}
-		history := utils.NewRingBuffer[*Ticker](10)
+		history := NewTickerHistory(10)
 
 		// Add 5 items to history
 		for i := 0; i < 5; i++ {
@@ -259,7 +288,7 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 		}}
 
 		// Execute
-		ticker.CalculateIndicators(history, lastTick)
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
 
 		// Should calculate 1m change but not 20m change
 		assert.NotEqual(t, 0.0, ticker.Change1m)
@@ -273,7 +302,7 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 
 	t.Run("history length >= 21", func(t *testing.T) {
 		ticker := &Ticker{Symbol: "BTCUSDT", Ask: 100, Bid: 99}
-		history := utils.NewRingBuffer[*Ticker](30)
+		history := NewTickerHistory(30)
 
 		// Add 25 items to history with increasing values
 		for i := 0; i < 25; i++ {
@@ -289,7 +318,7 @@ func TestTicker_CalculateIndicators_EdgeCases(t *testing.T) {
 		}}
 
 		// Execute
-		ticker.CalculateIndicators(history, lastTick)
+		ticker.CalculateIndicators(history, lastTick, DefaultPrecision())
 
 		// Should calculate all indicators
 		assert.NotEqual(t, 0.0, ticker.Change1m)
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+//go:generate moq --out mocks/trade_repository.go --pkg mocks --with-resets --skip-ensure . TradeRepository
+
+// Trade is an aggregated trade of a symbol: the fills of a taker order at the same price
+type Trade struct {
+	Symbol   TickerName `db:"s" json:"s" bson:"s"`
+	ID       int64      `db:"id" json:"id" bson:"id"` // exchange id of the aggregated trade
+	Side     OrderSide  `db:"sd" json:"sd" bson:"sd"` // side of the taker, BUY when the buyer took the ask
+	Price    float64    `db:"p" json:"p" bson:"p"`
+	Quantity float64    `db:"q" json:"q" bson:"q"`
+	USDValue float64    `db:"usd" json:"usd,omitempty" bson:"usd,omitempty"` // notional in USD, 0 if the quote asset has no known rate
+	EventAt  time.Time  `db:"et" json:"et" bson:"et"`                        // date when the trade happened on the exchange
+	StoredAt time.Time  `db:"st" json:"st" bson:"st"`                        // date when the trade was received
+}
+
+// TradeRepository represents the trade repository contract
+type TradeRepository interface {
+	CreateMany(ctx context.Context, trades []Trade) error
+}
+
+// Validate performs validation of the Trade
+func (t *Trade) Validate() error {
+	if t.Symbol == "" {
+		return ValidationError{
+			Field: "Symbol",
+			Err:   fmt.Errorf("symbol cannot be empty"),
+		}
+	}
+
+	if t.Side != OrderSideBuy && t.Side != OrderSideSell {
+		return ValidationError{
+			Field: "Side",
+			Err:   fmt.Errorf("invalid taker side: %s for %s", t.Side, t.Symbol),
+		}
+	}
+
+	if t.Price <= 0 {
+		return ValidationError{
+			Field: "Price",
+			Err:   fmt.Errorf("price must be greater than 0 for %s", t.Symbol),
+		}
+	}
+
+	if t.Quantity <= 0 {
+		return ValidationError{
+			Field: "Quantity",
+			Err:   fmt.Errorf("quantity must be greater than 0 for %s", t.Symbol),
+		}
+	}
+
+	if t.EventAt.IsZero() {
+		return ValidationError{
+			Field: "EventAt",
+			Err:   fmt.Errorf("event time cannot be zero for %s", t.Symbol),
+		}
+	}
+
+	return nil
+}
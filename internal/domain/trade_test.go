@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrade_Validate(t *testing.T) {
+	valid := Trade{Symbol: "BTCUSDT", ID: 1, Side: OrderSideBuy, Price: 95000, Quantity: 0.1, EventAt: time.Now()}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(tr *Trade)
+		field  string
+	}{
+		{name: "empty symbol", modify: func(tr *Trade) { tr.Symbol = "" }, field: "Symbol"},
+		{name: "unknown side", modify: func(tr *Trade) { tr.Side = "LONG" }, field: "Side"},
+		{name: "zero price", modify: func(tr *Trade) { tr.Price = 0 }, field: "Price"},
+		{name: "negative quantity", modify: func(tr *Trade) { tr.Quantity = -1 }, field: "Quantity"},
+		{name: "zero event time", modify: func(tr *Trade) { tr.EventAt = time.Time{} }, field: "EventAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trade := valid
+			tt.modify(&trade)
+			var validationErr ValidationError
+			assert.ErrorAs(t, trade.Validate(), &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"go.uber.org/zap"
+)
+
+// DefaultBufferSize is the number of events a subscriber may lag behind before new events are dropped
+const DefaultBufferSize = 64
+
+// Handler processes a single event delivered to a subscription
+type Handler func(ctx context.Context, event Event)
+
+// Bus fans out published events to independent subscribers.
+// Every subscriber owns a buffered channel and a goroutine, so a slow consumer never blocks the publisher
+// or other consumers: when its buffer is full the event is dropped for that subscriber only.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+	closed        bool
+	workers       sync.WaitGroup
+
+	pendingMu   sync.Mutex
+	pendingCond *sync.Cond
+	pending     int
+
+	bufferSize int
+	telemetry  telemetry.Provider
+	logger     *zap.Logger
+}
+
+type subscription struct {
+	name    string
+	types   map[EventType]struct{}
+	events  chan Event
+	handler Handler
+	dropped atomic.Int64
+}
+
+// SubscriberStats reports the backlog of a subscriber
+type SubscriberStats struct {
+	Name    string
+	Queued  int   // events waiting in the buffer
+	Dropped int64 // events dropped because the buffer was full
+}
+
+// New creates a new Bus
+func New(logger *zap.Logger) *Bus {
+	b := &Bus{
+		bufferSize: DefaultBufferSize,
+		telemetry:  &telemetry.NoopProvider{},
+		logger:     logger.With(zap.String("component", "eventbus")),
+	}
+	b.pendingCond = sync.NewCond(&b.pendingMu)
+	return b
+}
+
+// WithTelemetry sets the telemetry provider used to report dropped events
+func (b *Bus) WithTelemetry(provider telemetry.Provider) *Bus {
+	if provider != nil {
+		b.telemetry = provider
+	}
+	return b
+}
+
+// WithBufferSize overrides the buffer size of subscriptions created afterwards
+func (b *Bus) WithBufferSize(size int) *Bus {
+	if size > 0 {
+		b.bufferSize = size
+	}
+	return b
+}
+
+// Subscribe registers a handler for the given event types. Without types the handler receives every event
+func (b *Bus) Subscribe(name string, handler Handler, types ...EventType) {
+	sub := &subscription{
+		name:    name,
+		types:   make(map[EventType]struct{}, len(types)),
+		events:  make(chan Event, b.bufferSize),
+		handler: handler,
+	}
+	for _, t := range types {
+		sub.types[t] = struct{}{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		b.logger.Warn("Subscription to a closed bus ignored", zap.String("subscriber", name))
+		return
+	}
+	b.subscriptions = append(b.subscriptions, sub)
+
+	b.workers.Add(1)
+	go func() {
+		defer b.workers.Done()
+		for event := range sub.events {
+			b.dispatch(sub, event)
+		}
+	}()
+}
+
+// Publish delivers the event to every interested subscriber without blocking
+func (b *Bus) Publish(eventType EventType, payload any) {
+	event := Event{Type: eventType, Time: time.Now(), Payload: payload}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.subscriptions {
+		if !sub.accepts(eventType) {
+			continue
+		}
+
+		b.addPending(1)
+		select {
+		case sub.events <- event:
+		default:
+			b.addPending(-1)
+			sub.dropped.Add(1)
+			b.telemetry.IncrementCounter(telemetryEventsDropped, 1,
+				fmt.Sprintf("subscriber:%s", sub.name), fmt.Sprintf("event:%s", eventType))
+			b.logger.Warn("Subscriber is lagging, event dropped",
+				zap.String("subscriber", sub.name),
+				zap.String("event", string(eventType)),
+			)
+		}
+	}
+}
+
+// Stats returns the backlog of every subscriber in subscription order
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make([]SubscriberStats, 0, len(b.subscriptions))
+	for _, sub := range b.subscriptions {
+		stats = append(stats, SubscriberStats{
+			Name:    sub.name,
+			Queued:  len(sub.events),
+			Dropped: sub.dropped.Load(),
+		})
+	}
+	return stats
+}
+
+// Flush blocks until every event accepted so far has been handled
+func (b *Bus) Flush() {
+	b.pendingMu.Lock()
+	defer b.pendingMu.Unlock()
+	for b.pending > 0 {
+		b.pendingCond.Wait()
+	}
+}
+
+// Close stops accepting events and waits for subscribers to drain their buffers
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, sub := range b.subscriptions {
+		close(sub.events)
+	}
+	b.mu.Unlock()
+
+	b.workers.Wait()
+}
+
+// dispatch runs the subscriber handler, isolating the bus from its panics
+func (b *Bus) dispatch(sub *subscription, event Event) {
+	defer b.addPending(-1)
+	defer func() {
+		if r := recover(); r != nil {
+			b.telemetry.IncrementCounter(telemetryHandlerPanics, 1, fmt.Sprintf("subscriber:%s", sub.name))
+			b.logger.Error("Subscriber panic",
+				zap.String("subscriber", sub.name),
+				zap.String("event", string(event.Type)),
+				zap.Any("panic", r),
+				zap.ByteString("stack", debug.Stack()),
+			)
+		}
+	}()
+
+	sub.handler(context.Background(), event)
+}
+
+func (b *Bus) addPending(delta int) {
+	b.pendingMu.Lock()
+	b.pending += delta
+	if b.pending == 0 {
+		b.pendingCond.Broadcast()
+	}
+	b.pendingMu.Unlock()
+}
+
+func (s *subscription) accepts(eventType EventType) bool {
+	if len(s.types) == 0 {
+		return true
+	}
+	_, ok := s.types[eventType]
+	return ok
+}
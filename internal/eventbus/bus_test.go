@@ -0,0 +1,124 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestBus_RoutesEventsByType(t *testing.T) {
+	bus := New(zap.NewNop())
+	defer bus.Close()
+
+	var mu sync.Mutex
+	received := map[string][]EventType{}
+	record := func(name string) Handler {
+		return func(ctx context.Context, event Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[name] = append(received[name], event.Type)
+		}
+	}
+
+	bus.Subscribe("ticks", record("ticks"), TickBuilt)
+	bus.Subscribe("health", record("health"), ImportDegraded, LiquidationReceived)
+	bus.Subscribe("all", record("all"))
+
+	bus.Publish(TickBuilt, nil)
+	bus.Publish(LiquidationReceived, nil)
+	bus.Publish(ImportDegraded, Degradation{Stage: "fetch"})
+	bus.Flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []EventType{TickBuilt}, received["ticks"])
+	assert.Equal(t, []EventType{LiquidationReceived, ImportDegraded}, received["health"])
+	assert.Equal(t, []EventType{TickBuilt, LiquidationReceived, ImportDegraded}, received["all"])
+}
+
+func TestBus_SlowSubscriberDoesNotBlockPublisher(t *testing.T) {
+	bus := New(zap.NewNop()).WithBufferSize(1)
+
+	release := make(chan struct{})
+	bus.Subscribe("slow", func(ctx context.Context, event Event) {
+		<-release
+	}, TickBuilt)
+
+	var fastCount int
+	var mu sync.Mutex
+	bus.Subscribe("fast", func(ctx context.Context, event Event) {
+		mu.Lock()
+		fastCount++
+		mu.Unlock()
+	}, TickBuilt)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			bus.Publish(TickBuilt, i)
+			time.Sleep(time.Millisecond)
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publisher was blocked by a slow subscriber")
+	}
+
+	stats := bus.Stats()
+	assert.Equal(t, "slow", stats[0].Name)
+	assert.Equal(t, 1, stats[0].Queued)
+	assert.Positive(t, stats[0].Dropped)
+	assert.Equal(t, "fast", stats[1].Name)
+	assert.Zero(t, stats[1].Dropped)
+
+	close(release)
+	bus.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 10, fastCount)
+}
+
+func TestBus_RecoversHandlerPanic(t *testing.T) {
+	bus := New(zap.NewNop())
+	defer bus.Close()
+
+	var handled int
+	bus.Subscribe("panicking", func(ctx context.Context, event Event) {
+		if event.Payload == "boom" {
+			panic("boom")
+		}
+		handled++
+	})
+
+	bus.Publish(TickBuilt, "boom")
+	bus.Publish(TickBuilt, "ok")
+	bus.Flush()
+
+	assert.Equal(t, 1, handled)
+}
+
+func TestBus_PublishAfterCloseIsNoop(t *testing.T) {
+	bus := New(zap.NewNop())
+
+	var handled int
+	bus.Subscribe("sub", func(ctx context.Context, event Event) {
+		handled++
+	})
+	bus.Publish(TickBuilt, nil)
+	bus.Close()
+
+	assert.NotPanics(t, func() {
+		bus.Publish(TickBuilt, nil)
+		bus.Close()
+	})
+	bus.Flush()
+	assert.Equal(t, 1, handled)
+}
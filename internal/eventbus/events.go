@@ -0,0 +1,103 @@
+package eventbus
+
+import "time"
+
+// EventType identifies what happened inside the importer
+type EventType string
+
+const (
+	// TickBuilt is published once a tick has been built, validated and stored (before storing it with the
+	// notify-first publish order). Payload is *domain.Tick
+	TickBuilt EventType = "tick_built"
+
+	// LiquidationReceived is published for every valid liquidation coming from the exchange.
+	// Payload is domain.Liquidation, without its archived Raw frame
+	LiquidationReceived EventType = "liquidation_received"
+
+	// ImportDegraded is published when a step of the import fails. Payload is Degradation
+	ImportDegraded EventType = "import_degraded"
+
+	// OperationalAlert is published when an operational alert starts firing or gets resolved. Payload is opsalert.Alert
+	OperationalAlert EventType = "operational_alert"
+
+	// BarsClosed is published when ticks of a new minute finalize the minute bars of the previous one. Payload is []domain.Bar
+	BarsClosed EventType = "bars_closed"
+
+	// AlertFired is published for every market alert formatted for a notifier. Payload is domain.Alert
+	AlertFired EventType = "alert_fired"
+
+	// StreamRateMeasured is published every second for every running exchange stream. Payload is StreamRate
+	StreamRateMeasured EventType = "stream_rate_measured"
+
+	// LiquidationStorageMeasured is published every second while liquidations are imported. Payload is StorageRate
+	LiquidationStorageMeasured EventType = "liquidation_storage_measured"
+)
+
+// Import stages reported in Degradation.Stage
+const (
+	StageFetchTickers         = "fetch_tickers"
+	StageConvertTickers       = "convert_tickers"
+	StageValidateTick         = "validate_tick"
+	StageStoreTick            = "store_tick"
+	StageLiquidations         = "liquidations_stream"
+	StageStoreLiquidation     = "store_liquidation"
+	StageBackfillLiquidations = "backfill_liquidations"
+	StageSubTicksStream       = "sub_ticks_stream"
+	StageStoreSubTicks        = "store_sub_ticks"
+	StageStoreBars            = "store_bars"
+	StageFetchFundingRates    = "fetch_funding_rates"
+	StageStoreFundingRates    = "store_funding_rates"
+	StageFetchOpenInterest    = "fetch_open_interest"
+	StageTradesStream         = "trades_stream"
+	StageStoreTrades          = "store_trades"
+	StageStoreSymbols         = "store_symbols"
+)
+
+// Event is a single message travelling through the bus
+type Event struct {
+	Type    EventType
+	Time    time.Time
+	Payload any
+}
+
+// Degradation describes a failed import step
+type Degradation struct {
+	Exchange string
+	Stage    string
+	Err      error
+}
+
+// StreamRate is the number of messages an exchange stream delivered within an interval.
+// Stream is the degradation stage the stream reports its failures with, e.g. StageLiquidations
+type StreamRate struct {
+	Exchange string
+	Stream   string
+	Messages int64
+	Interval time.Duration
+}
+
+// PerSecond returns the messages per second
+func (r StreamRate) PerSecond() float64 {
+	if r.Interval <= 0 {
+		return 0
+	}
+	return float64(r.Messages) / r.Interval.Seconds()
+}
+
+// StorageRate is the number of liquidations stored within an interval, compared to the trailing baseline.
+// Spiking is set while the rate exceeds the baseline by the configured factor
+type StorageRate struct {
+	Exchange string
+	Stored   int64
+	Interval time.Duration
+	Baseline float64 // moving average of the stored liquidations per second, before this measurement
+	Spiking  bool
+}
+
+// PerSecond returns the stored liquidations per second
+func (r StorageRate) PerSecond() float64 {
+	if r.Interval <= 0 {
+		return 0
+	}
+	return float64(r.Stored) / r.Interval.Seconds()
+}
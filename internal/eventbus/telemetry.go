@@ -0,0 +1,20 @@
+package eventbus
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for counters
+const (
+	// telemetryEventsDropped counts events dropped because a subscriber buffer was full
+	telemetryEventsDropped = "eventbus.events.dropped"
+
+	// telemetryHandlerPanics counts panics recovered from subscriber handlers
+	telemetryHandlerPanics = "eventbus.handler.panics"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryEventsDropped, Kind: telemetry.KindCounter, Description: "Events dropped because a subscriber buffer was full", Tags: []string{"subscriber", "event"}},
+		{Name: telemetryHandlerPanics, Kind: telemetry.KindCounter, Description: "Panics recovered from subscriber handlers", Tags: []string{"subscriber"}},
+	}
+}
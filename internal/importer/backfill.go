@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"go.uber.org/zap"
+)
+
+// startLiquidationsBackfill stores the liquidations of the backfill window preceding until, the start of the
+// liquidation stream, if configured and supported by the exchange or one of its liquidation sources, and the repository
+func (i *Importer) startLiquidationsBackfill(ctx context.Context, until time.Time) {
+	if i.liquidationsBackfill <= 0 {
+		return
+	}
+	fetcher, ok := i.liquidationHistoryFetcher()
+	if !ok {
+		i.logger.Warn("Liquidations backfill is not supported by the exchange nor its liquidation sources", zap.String("exchange", i.exchange.GetName()))
+		return
+	}
+	repository, ok := i.liquidationRepository.(domain.LiquidationBackfillRepository)
+	if !ok {
+		i.logger.Warn("Liquidations backfill is not supported by the repository")
+		return
+	}
+
+	i.supervisor.Go(ctx, "liquidations-backfill", func(ctx context.Context) error {
+		if err := i.backfillLiquidations(ctx, fetcher, repository, until.Add(-i.liquidationsBackfill), until); err != nil {
+			i.publishDegraded(eventbus.StageBackfillLiquidations, err)
+			i.logger.Error("Failed to backfill liquidations", zap.Error(err))
+		}
+		return nil
+	})
+}
+
+// liquidationHistoryFetcher returns the exchange if it serves past liquidations, else the first liquidation source doing so
+func (i *Importer) liquidationHistoryFetcher() (exchanges.LiquidationHistoryFetcher, bool) {
+	if fetcher, ok := i.exchange.(exchanges.LiquidationHistoryFetcher); ok {
+		return fetcher, true
+	}
+	for _, source := range i.liquidationSources {
+		if fetcher, ok := source.(exchanges.LiquidationHistoryFetcher); ok {
+			return fetcher, true
+		}
+	}
+	return nil, false
+}
+
+// backfillLiquidations fetches the liquidations within [from, to) and stores the ones not streamed already
+func (i *Importer) backfillLiquidations(ctx context.Context, fetcher exchanges.LiquidationHistoryFetcher, repository domain.LiquidationBackfillRepository, from, to time.Time) error {
+	fetched, err := fetcher.FetchLiquidations(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("fetching liquidations: %w", err)
+	}
+
+	liquidations := make([]domain.Liquidation, 0, len(fetched))
+	for _, liq := range fetched {
+		domainLiq := i.convertLiquidationToDomain(liq)
+		// stored as if streamed when it happened, so it counts in its own window only
+		domainLiq.StoredAt = domainLiq.EventAt
+		domainLiq.WindowAt = domainLiq.EventAt
+		if err := domainLiq.Validate(); err != nil {
+			i.logger.Warn("Backfilled liquidation validation failed", zap.Error(err))
+			continue
+		}
+		liquidations = append(liquidations, domainLiq)
+		// the stats only counted the streamed liquidations, which start at to
+		i.rollingStats.addLiquidation(domainLiq)
+	}
+
+	created, err := repository.CreateMissing(ctx, liquidations)
+	i.telemetry.IncrementCounter(telemetryLiquidationsBackfilled, int64(created))
+	if err != nil {
+		return fmt.Errorf("storing liquidations: %w", err)
+	}
+
+	i.logger.Info("Liquidations backfilled",
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.Int("fetched", len(fetched)),
+		zap.Int("stored", created),
+	)
+	return nil
+}
@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liquidationHistoryFunc serves past liquidations for the backfill
+type liquidationHistoryFunc func(ctx context.Context, from, to time.Time) ([]exchanges.Liquidation, error)
+
+func (f liquidationHistoryFunc) FetchLiquidations(ctx context.Context, from, to time.Time) ([]exchanges.Liquidation, error) {
+	return f(ctx, from, to)
+}
+
+func TestBackfillLiquidations(t *testing.T) {
+	ts := setupTest()
+	until := time.Date(2025, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	// streamed before the backfill ran, reported by the stream with its event time
+	repository := &memory.InMemoryLiquidationRepository{}
+	streamed := domain.Liquidation{
+		Order:    domain.Order{Symbol: "BTCUSDT", EventAt: until.Add(-time.Second), Side: domain.OrderSideSell, Price: 50000, Quantity: 0.1, TotalPrice: 5000},
+		EventAt:  until.Add(-time.Second),
+		StoredAt: until.Add(-time.Second),
+	}
+	require.NoError(t, repository.Create(context.Background(), streamed))
+
+	var requested [2]time.Time
+	fetcher := liquidationHistoryFunc(func(ctx context.Context, from, to time.Time) ([]exchanges.Liquidation, error) {
+		requested = [2]time.Time{from, to}
+		return []exchanges.Liquidation{
+			{Symbol: "ETHUSDT", Side: exchanges.ShortLiquidated, Price: 3000, Quantity: 2, TotalPrice: 6000, EventAt: until.Add(-5 * time.Minute)},
+			{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 50000, Quantity: 0.1, TotalPrice: 5000, EventAt: until.Add(-time.Second - 30*time.Millisecond)},
+			{Symbol: "BTCUSDT", Side: "UNKNOWN", Price: 50000, Quantity: 0.1, TotalPrice: 5000, EventAt: until.Add(-time.Minute)},
+		}, nil
+	})
+
+	from := until.Add(-10 * time.Minute)
+	require.NoError(t, ts.importer.backfillLiquidations(context.Background(), fetcher, repository, from, until))
+	assert.Equal(t, [2]time.Time{from, until}, requested)
+
+	history, err := repository.GetLiquidationsHistory(context.Background(), until.Add(-5*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), history.ShortLiquidations1s, "the backfilled liquidation counts when it happened")
+
+	history, err = repository.GetLiquidationsHistory(context.Background(), until)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), history.LongLiquidations60s, "the streamed liquidation isn't stored twice")
+}
+
+func TestStartLiquidationsBackfillReportsFailure(t *testing.T) {
+	ts := setupTest()
+	ts.importer.liquidationsBackfill = time.Hour
+	ts.importer.liquidationRepository = &memory.InMemoryLiquidationRepository{}
+	ts.importer.exchange = struct {
+		exchanges.Exchange
+		exchanges.LiquidationHistoryFetcher
+	}{ts.exchange, liquidationHistoryFunc(func(ctx context.Context, from, to time.Time) ([]exchanges.Liquidation, error) {
+		return nil, errors.New("endpoint out of maintenance")
+	})}
+
+	degraded := make(chan eventbus.Degradation, 1)
+	ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+		degraded <- event.Payload.(eventbus.Degradation)
+	}, eventbus.ImportDegraded)
+
+	ts.importer.startLiquidationsBackfill(context.Background(), time.Now())
+
+	select {
+	case degradation := <-degraded:
+		assert.Equal(t, eventbus.StageBackfillLiquidations, degradation.Stage)
+		assert.ErrorContains(t, degradation.Err, "endpoint out of maintenance")
+	case <-time.After(time.Second):
+		t.Fatal("the failed backfill wasn't reported")
+	}
+}
+
+func TestLiquidationHistoryFetcher(t *testing.T) {
+	ts := setupTest()
+
+	_, ok := ts.importer.liquidationHistoryFetcher()
+	assert.False(t, ok, "neither the exchange nor a source serves past liquidations")
+
+	history := liquidationHistoryFunc(func(ctx context.Context, from, to time.Time) ([]exchanges.Liquidation, error) {
+		return []exchanges.Liquidation{{Symbol: "BTCUSDT", Source: "coinglass"}}, nil
+	})
+	ts.importer.liquidationSources = []exchanges.LiquidationSource{
+		&channelSource{name: "stream-only"},
+		struct {
+			exchanges.LiquidationSource
+			exchanges.LiquidationHistoryFetcher
+		}{&channelSource{name: "coinglass"}, history},
+	}
+
+	fetcher, ok := ts.importer.liquidationHistoryFetcher()
+	require.True(t, ok)
+	liquidations, err := fetcher.FetchLiquidations(context.Background(), time.Time{}, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "coinglass", liquidations[0].Source, "the first source serving past liquidations backfills")
+}
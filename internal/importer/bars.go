@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+	"go.uber.org/zap"
+)
+
+// barLiquidationsRetention is how long the liquidation notional of a minute is kept for its bar to close
+const barLiquidationsRetention = 5 * time.Minute
+
+// barKey identifies the minute of a symbol
+type barKey struct {
+	symbol domain.TickerName
+	minute int64 // unix seconds of the start of the minute
+}
+
+// barCollector gathers the bars closed while building a tick and the liquidated notional of the minutes they cover.
+// A bar is closed by the first ticker of the next minute of its symbol
+type barCollector struct {
+	mu           sync.Mutex
+	closed       []domain.Bar
+	liquidations map[barKey]float64
+	decimals     int // of the liquidated notional
+}
+
+func newBarCollector(decimals int) *barCollector {
+	return &barCollector{liquidations: make(map[barKey]float64), decimals: decimals}
+}
+
+// addLiquidation adds the USD notional of a liquidation to the minute it happened in
+func (c *barCollector) addLiquidation(liq domain.Liquidation) {
+	key := barKey{symbol: liq.Order.Symbol, minute: liq.EventAt.Truncate(time.Minute).Unix()}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.liquidations[key] += liq.Order.USDValue
+}
+
+// close finalizes a bar with the liquidated notional of its minute
+func (c *barCollector) close(bar *domain.Bar) {
+	key := barKey{symbol: bar.Symbol, minute: bar.StartAt.Unix()}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	bar.LiqNotional = mathutils.RoundTo(c.liquidations[key], c.decimals)
+	delete(c.liquidations, key)
+	c.closed = append(c.closed, *bar)
+}
+
+// take returns the closed bars sorted by symbol and forgets the liquidations of minutes older than the retention
+func (c *barCollector) take(now time.Time) []domain.Bar {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bars := c.closed
+	c.closed = nil
+	staleBefore := now.Add(-barLiquidationsRetention).Unix()
+	for key := range c.liquidations {
+		if key.minute < staleBefore {
+			delete(c.liquidations, key)
+		}
+	}
+
+	slices.SortFunc(bars, func(a, b domain.Bar) int {
+		return strings.Compare(string(a.Symbol), string(b.Symbol))
+	})
+	return bars
+}
+
+// flushBars publishes and stores the bars closed by the last tick
+func (i *Importer) flushBars(ctx context.Context, now time.Time) {
+	bars := i.bars.take(now)
+	if len(bars) == 0 {
+		return
+	}
+
+	i.events.Publish(eventbus.BarsClosed, bars)
+	if err := i.barRepository.CreateMany(ctx, bars); err != nil {
+		i.publishDegraded(eventbus.StageStoreBars, err)
+		i.logger.Error("Failed to store minute bars", zap.Error(err))
+		return
+	}
+	i.telemetry.IncrementCounter(telemetryBarsStored, int64(len(bars)))
+}
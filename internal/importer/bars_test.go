@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarCollector(t *testing.T) {
+	minute := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	liquidation := func(symbol domain.TickerName, at time.Time, usdValue float64) domain.Liquidation {
+		return domain.Liquidation{Order: domain.Order{Symbol: symbol, USDValue: usdValue}, EventAt: at}
+	}
+
+	c := newBarCollector(domain.DefaultPrecision().Notional)
+	c.addLiquidation(liquidation("BTCUSDT", minute.Add(10*time.Second), 1000.123))
+	c.addLiquidation(liquidation("BTCUSDT", minute.Add(50*time.Second), 500))
+	c.addLiquidation(liquidation("BTCUSDT", minute.Add(time.Minute), 700)) // next minute
+	c.addLiquidation(liquidation("ETHUSDT", minute, 300))
+
+	c.close(&domain.Bar{Symbol: "ETHUSDT", StartAt: minute.Add(-time.Minute)})
+	c.close(&domain.Bar{Symbol: "BTCUSDT", StartAt: minute})
+
+	bars := c.take(minute.Add(time.Minute))
+	require.Len(t, bars, 2)
+	assert.Equal(t, domain.TickerName("BTCUSDT"), bars[0].Symbol, "bars are sorted by symbol")
+	assert.Equal(t, 1500.12, bars[0].LiqNotional)
+	assert.Zero(t, bars[1].LiqNotional, "liquidations of another minute are not counted")
+	assert.Empty(t, c.take(minute.Add(time.Minute)))
+
+	c.take(minute.Add(barLiquidationsRetention + time.Minute))
+	assert.Len(t, c.liquidations, 1, "only the liquidations of the retained minute are kept")
+	c.take(minute.Add(barLiquidationsRetention + 2*time.Minute))
+	assert.Empty(t, c.liquidations)
+}
+
+func TestImportTickEmitsBars(t *testing.T) {
+	ts := setupTest()
+	var stored []domain.Bar
+	barRepo := &domainMocks.BarRepositoryMock{
+		CreateManyFunc: func(ctx context.Context, bars []domain.Bar) error {
+			stored = append(stored, bars...)
+			return nil
+		},
+	}
+	ts.repoFactory.GetBarRepositoryFunc = func(name string) (domain.BarRepository, error) {
+		return barRepo, nil
+	}
+	importer := New(&Config{
+		Exchange:          ts.exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		Bars:              true,
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+
+	var mu sync.Mutex
+	var published [][]domain.Bar
+	ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, event.Payload.([]domain.Bar))
+	}, eventbus.BarsClosed)
+
+	minute := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	importer.bars.addLiquidation(domain.Liquidation{Order: domain.Order{Symbol: "BTCUSDT", USDValue: 2500}, EventAt: minute.Add(30 * time.Second)})
+
+	buildAt := func(at time.Time, ask, bid float64) {
+		tick := &domain.Tick{StartAt: at, Data: make(map[domain.TickerName]*domain.Ticker)}
+		importer.buildTick(context.Background(), tick, []exchanges.Ticker{{Symbol: "BTCUSDT", AskPrice: ask, BidPrice: bid, EventAt: at}})
+		importer.flushBars(context.Background(), at)
+	}
+	buildAt(minute, 101, 99)
+	buildAt(minute.Add(20*time.Second), 111, 109)
+	buildAt(minute.Add(40*time.Second), 96, 94)
+	assert.Empty(t, stored, "the bar of the live minute isn't emitted")
+
+	buildAt(minute.Add(time.Minute), 105, 103)
+	ts.events.Flush()
+
+	want := domain.Bar{
+		Symbol:      "BTCUSDT",
+		StartAt:     minute,
+		Open:        100,
+		High:        110,
+		Low:         95,
+		Close:       95,
+		MaxSpread:   2.0 / 95 * 100, // widest spread at the lowest mid
+		LiqNotional: 2500,
+		Samples:     3,
+	}
+	assert.Equal(t, []domain.Bar{want}, stored)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, [][]domain.Bar{{want}}, published)
+}
+
+func TestFlushBarsStoreError(t *testing.T) {
+	ts := setupTest()
+	ts.repoFactory.GetBarRepositoryFunc = func(name string) (domain.BarRepository, error) {
+		return &domainMocks.BarRepositoryMock{
+			CreateManyFunc: func(ctx context.Context, bars []domain.Bar) error {
+				return errors.New("db is down")
+			},
+		}, nil
+	}
+	importer := New(&Config{
+		Exchange:          ts.exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		Bars:              true,
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+
+	var mu sync.Mutex
+	var stages []string
+	ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		stages = append(stages, event.Payload.(eventbus.Degradation).Stage)
+	}, eventbus.ImportDegraded)
+
+	importer.bars.close(&domain.Bar{Symbol: "BTCUSDT", StartAt: time.Now().Truncate(time.Minute)})
+	importer.flushBars(context.Background(), time.Now())
+	ts.events.Flush()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{eventbus.StageStoreBars}, stages)
+}
+
+func TestNewSkipsBarRepositoryWhenDisabled(t *testing.T) {
+	ts := setupTest()
+	assert.Nil(t, ts.importer.bars)
+	assert.Empty(t, ts.repoFactory.GetBarRepositoryCalls())
+}
@@ -0,0 +1,349 @@
+package importer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"go.uber.org/zap"
+)
+
+// DefaultDataDiffMaxReported is the number of differences listed in a DataDiffResult, all of them are counted
+const DefaultDataDiffMaxReported = 100
+
+// DataDiffConfig holds the configuration of the comparison of the data stored in two repositories,
+// the source and the one it's compared against, e.g. the old and the new backend of a migration
+type DataDiffConfig struct {
+	Ticks               domain.TickRepository
+	AgainstTicks        domain.TickRepository
+	Liquidations        domain.LiquidationRangeRepository
+	AgainstLiquidations domain.LiquidationRangeRepository
+	Window              time.Duration // range loaded from both repositories per batch, 0 uses DefaultRecomputeWindow
+	MaxReported         int           // differences listed in the result, 0 uses DefaultDataDiffMaxReported
+	Logger              *zap.Logger
+}
+
+// DataDifference is a record missing from one of the repositories or stored differently in them
+type DataDifference struct {
+	Record  string    // tick or liquidation
+	At      time.Time // StorageKey of the tick, event time of the liquidation
+	Symbol  string    // symbol of the ticker or the liquidation, empty for tick fields
+	Problem string
+}
+
+// String formats the difference as a line of the report
+func (d DataDifference) String() string {
+	if d.Symbol == "" {
+		return fmt.Sprintf("%s %s: %s", d.Record, d.At.UTC().Format(time.RFC3339Nano), d.Problem)
+	}
+	return fmt.Sprintf("%s %s %s: %s", d.Record, d.At.UTC().Format(time.RFC3339Nano), d.Symbol, d.Problem)
+}
+
+// DataDiffResult summarizes a comparison. Missing records are stored only in the source repository,
+// extra ones only in the repository it's compared against
+type DataDiffResult struct {
+	Ticks                 int // ticks read from the source
+	AgainstTicks          int // ticks read from the compared repository
+	MissingTicks          int
+	ExtraTicks            int
+	DifferingTicks        int
+	Liquidations          int
+	AgainstLiquidations   int
+	MissingLiquidations   int
+	ExtraLiquidations     int
+	DifferingLiquidations int
+
+	// Differences lists the first MaxReported differences in the order they were found
+	Differences []DataDifference
+}
+
+// Equal reports whether both repositories hold the same data
+func (r DataDiffResult) Equal() bool {
+	return r.MissingTicks+r.ExtraTicks+r.DifferingTicks+r.MissingLiquidations+r.ExtraLiquidations+r.DifferingLiquidations == 0
+}
+
+// DataDiffer compares the ticks and liquidations stored in two repositories over a range
+type DataDiffer struct {
+	ticks               domain.TickRepository
+	againstTicks        domain.TickRepository
+	liquidations        domain.LiquidationRangeRepository
+	againstLiquidations domain.LiquidationRangeRepository
+	window              time.Duration
+	maxReported         int
+	logger              *zap.Logger
+}
+
+// NewDataDiffer creates a new DataDiffer
+func NewDataDiffer(cfg DataDiffConfig) *DataDiffer {
+	d := &DataDiffer{
+		ticks:               cfg.Ticks,
+		againstTicks:        cfg.AgainstTicks,
+		liquidations:        cfg.Liquidations,
+		againstLiquidations: cfg.AgainstLiquidations,
+		window:              cfg.Window,
+		maxReported:         cfg.MaxReported,
+		logger:              cfg.Logger,
+	}
+	if d.window <= 0 {
+		d.window = DefaultRecomputeWindow
+	}
+	if d.maxReported <= 0 {
+		d.maxReported = DefaultDataDiffMaxReported
+	}
+	if d.logger == nil {
+		d.logger = zap.NewNop()
+	}
+	return d
+}
+
+// Run compares the ticks created and the liquidations that happened within [from, to)
+func (d *DataDiffer) Run(ctx context.Context, from, to time.Time) (DataDiffResult, error) {
+	var result DataDiffResult
+	if !from.Before(to) {
+		return result, fmt.Errorf("invalid range: from %s must be before to %s", from, to)
+	}
+
+	for start := from; start.Before(to); start = start.Add(d.window) {
+		end := start.Add(d.window)
+		if end.After(to) {
+			end = to
+		}
+		if err := d.diffTicks(ctx, start, end, &result); err != nil {
+			return result, err
+		}
+		if err := d.diffLiquidations(ctx, start, end, &result); err != nil {
+			return result, err
+		}
+		d.logger.Info("Compared batch",
+			zap.Time("from", start),
+			zap.Time("to", end),
+			zap.Int("ticks", result.Ticks),
+			zap.Int("liquidations", result.Liquidations),
+		)
+	}
+	return result, nil
+}
+
+// report lists the difference in the result while the report isn't full, the caller counts it
+func (d *DataDiffer) report(result *DataDiffResult, diff DataDifference) {
+	if len(result.Differences) < d.maxReported {
+		result.Differences = append(result.Differences, diff)
+	}
+}
+
+// diffTicks compares the ticks created within [start, end), matched by their StorageKey
+func (d *DataDiffer) diffTicks(ctx context.Context, start, end time.Time, result *DataDiffResult) error {
+	source, err := d.ticks.GetRange(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("loading ticks %s - %s: %w", start, end, err)
+	}
+	against, err := d.againstTicks.GetRange(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("loading compared ticks %s - %s: %w", start, end, err)
+	}
+	result.Ticks += len(source)
+	result.AgainstTicks += len(against)
+
+	againstByKey := make(map[int64]domain.Tick, len(against))
+	for _, tick := range against {
+		againstByKey[tick.StorageKey().Unix()] = tick
+	}
+
+	for _, tick := range source {
+		key := tick.StorageKey()
+		other, ok := againstByKey[key.Unix()]
+		if !ok {
+			result.MissingTicks++
+			d.report(result, DataDifference{Record: "tick", At: key, Problem: "missing in the compared repository"})
+			continue
+		}
+		delete(againstByKey, key.Unix())
+
+		diffs := diffTick(tick, other)
+		if len(diffs) > 0 {
+			result.DifferingTicks++
+		}
+		for _, diff := range diffs {
+			d.report(result, diff)
+		}
+	}
+
+	extra := make([]domain.Tick, 0, len(againstByKey))
+	for _, tick := range againstByKey {
+		extra = append(extra, tick)
+	}
+	slices.SortFunc(extra, func(a, b domain.Tick) int { return a.StartAt.Compare(b.StartAt) })
+	for _, tick := range extra {
+		result.ExtraTicks++
+		d.report(result, DataDifference{Record: "tick", At: tick.StorageKey(), Problem: "only in the compared repository"})
+	}
+	return nil
+}
+
+// diffTick lists the differences of the same tick stored in both repositories. Times are compared
+// to the millisecond, the precision every repository keeps
+func diffTick(a, b domain.Tick) []DataDifference {
+	at := a.StorageKey()
+	var diffs []DataDifference
+
+	fields := []struct {
+		name string
+		a, b any
+	}{
+		{"fetched_at", a.FetchedAt.UnixMilli(), b.FetchedAt.UnixMilli()},
+		{"created_at", a.CreatedAt.UnixMilli(), b.CreatedAt.UnixMilli()},
+		{"tick_avg_buy_open", a.AvgBuy10, b.AvgBuy10},
+		{"ll_1", a.LL1, b.LL1},
+		{"ll_2", a.LL2, b.LL2},
+		{"ll_5", a.LL5, b.LL5},
+		{"ll_60", a.LL60, b.LL60},
+		{"sl_1", a.SL1, b.SL1},
+		{"sl_2", a.SL2, b.SL2},
+		{"sl_10", a.SL10, b.SL10},
+		{"bv_1", a.BuyVolume1, b.BuyVolume1},
+		{"sv_1", a.SellVolume1, b.SellVolume1},
+		{"indicators_version", a.IndicatorsVersion, b.IndicatorsVersion},
+		{"quality", a.Quality, b.Quality},
+		{"avg", a.Avg, b.Avg},
+		{"avg_w", a.AvgWeighted, b.AvgWeighted},
+	}
+	for _, f := range fields {
+		if f.a != f.b {
+			diffs = append(diffs, DataDifference{Record: "tick", At: at, Problem: fmt.Sprintf("%s: %+v != %+v", f.name, f.a, f.b)})
+		}
+	}
+
+	symbols := make([]domain.TickerName, 0, len(a.Data)+len(b.Data))
+	for symbol := range a.Data {
+		symbols = append(symbols, symbol)
+	}
+	for symbol := range b.Data {
+		if _, ok := a.Data[symbol]; !ok {
+			symbols = append(symbols, symbol)
+		}
+	}
+	slices.Sort(symbols)
+
+	for _, symbol := range symbols {
+		ta, okA := a.Data[symbol]
+		tb, okB := b.Data[symbol]
+		switch {
+		case !okB:
+			diffs = append(diffs, DataDifference{Record: "tick", At: at, Symbol: string(symbol), Problem: "ticker missing in the compared repository"})
+		case !okA:
+			diffs = append(diffs, DataDifference{Record: "tick", At: at, Symbol: string(symbol), Problem: "ticker only in the compared repository"})
+		default:
+			for _, problem := range diffTicker(ta, tb) {
+				diffs = append(diffs, DataDifference{Record: "tick", At: at, Symbol: string(symbol), Problem: problem})
+			}
+		}
+	}
+	return diffs
+}
+
+// diffTicker lists the differing prices and times of the same ticker, indicators follow from them
+func diffTicker(a, b *domain.Ticker) []string {
+	var problems []string
+	if a.Ask != b.Ask {
+		problems = append(problems, fmt.Sprintf("ask: %v != %v", a.Ask, b.Ask))
+	}
+	if a.Bid != b.Bid {
+		problems = append(problems, fmt.Sprintf("bid: %v != %v", a.Bid, b.Bid))
+	}
+	if a.EventAt.UnixMilli() != b.EventAt.UnixMilli() {
+		problems = append(problems, fmt.Sprintf("et: %s != %s", a.EventAt.UTC().Format(time.RFC3339Nano), b.EventAt.UTC().Format(time.RFC3339Nano)))
+	}
+	return problems
+}
+
+// liquidationKey identifies a liquidation for the comparison, stored twice it keeps the symbol,
+// side and event time while its amounts may differ
+type liquidationKey struct {
+	symbol  domain.TickerName
+	side    domain.OrderSide
+	eventAt int64
+}
+
+// diffLiquidations compares the liquidations that happened within [start, end)
+func (d *DataDiffer) diffLiquidations(ctx context.Context, start, end time.Time, result *DataDiffResult) error {
+	source, err := d.liquidations.GetRange(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("loading liquidations %s - %s: %w", start, end, err)
+	}
+	against, err := d.againstLiquidations.GetRange(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("loading compared liquidations %s - %s: %w", start, end, err)
+	}
+	result.Liquidations += len(source)
+	result.AgainstLiquidations += len(against)
+
+	// several liquidations of a symbol may happen at the same millisecond, they are matched in order
+	againstByKey := make(map[liquidationKey][]domain.Liquidation, len(against))
+	for _, l := range against {
+		key := newLiquidationKey(l)
+		againstByKey[key] = append(againstByKey[key], l)
+	}
+
+	for _, l := range source {
+		key := newLiquidationKey(l)
+		candidates := againstByKey[key]
+		if len(candidates) == 0 {
+			result.MissingLiquidations++
+			d.report(result, DataDifference{Record: "liquidation", At: l.EventAt, Symbol: string(l.Order.Symbol), Problem: "missing in the compared repository"})
+			continue
+		}
+
+		i := max(slices.IndexFunc(candidates, func(c domain.Liquidation) bool { return len(diffOrder(l.Order, c.Order)) == 0 }), 0)
+		problems := diffOrder(l.Order, candidates[i].Order)
+		againstByKey[key] = slices.Delete(candidates, i, i+1)
+		if len(problems) > 0 {
+			result.DifferingLiquidations++
+		}
+		for _, problem := range problems {
+			d.report(result, DataDifference{Record: "liquidation", At: l.EventAt, Symbol: string(l.Order.Symbol), Problem: problem})
+		}
+	}
+
+	var extra []domain.Liquidation
+	for _, candidates := range againstByKey {
+		extra = append(extra, candidates...)
+	}
+	slices.SortFunc(extra, func(a, b domain.Liquidation) int {
+		return cmp.Or(a.EventAt.Compare(b.EventAt), cmp.Compare(a.Order.Symbol, b.Order.Symbol))
+	})
+	for _, l := range extra {
+		result.ExtraLiquidations++
+		d.report(result, DataDifference{Record: "liquidation", At: l.EventAt, Symbol: string(l.Order.Symbol), Problem: "only in the compared repository"})
+	}
+	return nil
+}
+
+// diffOrder lists the differing amounts of the same liquidation order
+func diffOrder(a, b domain.Order) []string {
+	var problems []string
+	fields := []struct {
+		name string
+		a, b float64
+	}{
+		{"p", a.Price, b.Price},
+		{"q", a.Quantity, b.Quantity},
+		{"tp", a.TotalPrice, b.TotalPrice},
+		{"usd", a.USDValue, b.USDValue},
+	}
+	for _, f := range fields {
+		if f.a != f.b {
+			problems = append(problems, fmt.Sprintf("%s: %v != %v", f.name, f.a, f.b))
+		}
+	}
+	if a.EventAt.UnixMilli() != b.EventAt.UnixMilli() {
+		problems = append(problems, fmt.Sprintf("order et: %s != %s", a.EventAt.UTC().Format(time.RFC3339Nano), b.EventAt.UTC().Format(time.RFC3339Nano)))
+	}
+	return problems
+}
+
+func newLiquidationKey(l domain.Liquidation) liquidationKey {
+	return liquidationKey{symbol: l.Order.Symbol, side: l.Order.Side, eventAt: l.EventAt.UnixMilli()}
+}
@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rangeLiquidationRepository serves GetRange from the given liquidations
+func rangeLiquidationRepository(liquidations []domain.Liquidation) *domainMocks.LiquidationRangeRepositoryMock {
+	return &domainMocks.LiquidationRangeRepositoryMock{
+		GetRangeFunc: func(ctx context.Context, from, to time.Time) ([]domain.Liquidation, error) {
+			var result []domain.Liquidation
+			for _, l := range liquidations {
+				if !l.EventAt.Before(from) && l.EventAt.Before(to) {
+					result = append(result, l)
+				}
+			}
+			return result, nil
+		},
+	}
+}
+
+func diffLiquidation(at time.Time, symbol string, price float64) domain.Liquidation {
+	return domain.Liquidation{
+		EventAt: at,
+		Order: domain.Order{
+			EventAt:  at,
+			Symbol:   domain.TickerName(symbol),
+			Side:     domain.OrderSideSell,
+			Price:    price,
+			Quantity: 1,
+		},
+	}
+}
+
+func TestDataDiffer_Run(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("same data", func(t *testing.T) {
+		ticks := storedTicks(start, 90)
+		liquidations := []domain.Liquidation{diffLiquidation(start.Add(time.Minute), "BTCUSDT", 100)}
+
+		differ := NewDataDiffer(DataDiffConfig{
+			Ticks:               rangeTickRepository(ticks),
+			AgainstTicks:        rangeTickRepository(storedTicks(start, 90)),
+			Liquidations:        rangeLiquidationRepository(liquidations),
+			AgainstLiquidations: rangeLiquidationRepository(liquidations),
+			Window:              30 * time.Minute,
+		})
+		result, err := differ.Run(context.Background(), start, start.Add(2*time.Hour))
+		require.NoError(t, err)
+
+		assert.True(t, result.Equal())
+		assert.Equal(t, 90, result.Ticks)
+		assert.Equal(t, 90, result.AgainstTicks)
+		assert.Equal(t, 1, result.Liquidations)
+		assert.Empty(t, result.Differences)
+	})
+
+	t.Run("missing and differing records", func(t *testing.T) {
+		source := storedTicks(start, 5)
+		against := storedTicks(start, 5)
+		against = append(against[:1], against[2:]...) // the second minute is missing
+		against = append(against, storedTicks(start.Add(10*time.Minute), 1)...)
+		against[2].LL1 = 42
+		against[2].Data = map[domain.TickerName]*domain.Ticker{
+			"BTCUSDT": {Symbol: "BTCUSDT", Ask: 1, Bid: source[3].Data["BTCUSDT"].Bid, EventAt: source[3].Data["BTCUSDT"].EventAt},
+			"ETHUSDT": {Symbol: "ETHUSDT", Ask: 2, Bid: 1, EventAt: start},
+		}
+
+		differ := NewDataDiffer(DataDiffConfig{
+			Ticks:        rangeTickRepository(source),
+			AgainstTicks: rangeTickRepository(against),
+			Liquidations: rangeLiquidationRepository([]domain.Liquidation{
+				diffLiquidation(start, "BTCUSDT", 100),
+				diffLiquidation(start.Add(time.Second), "ETHUSDT", 10),
+			}),
+			AgainstLiquidations: rangeLiquidationRepository([]domain.Liquidation{
+				diffLiquidation(start, "BTCUSDT", 101),
+				diffLiquidation(start.Add(2*time.Second), "SOLUSDT", 1),
+			}),
+		})
+		result, err := differ.Run(context.Background(), start, start.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.False(t, result.Equal())
+		assert.Equal(t, 1, result.MissingTicks)
+		assert.Equal(t, 1, result.ExtraTicks)
+		assert.Equal(t, 1, result.DifferingTicks)
+		assert.Equal(t, 1, result.MissingLiquidations)
+		assert.Equal(t, 1, result.ExtraLiquidations)
+		assert.Equal(t, 1, result.DifferingLiquidations)
+
+		var report []string
+		for _, diff := range result.Differences {
+			report = append(report, diff.String())
+		}
+		assert.Equal(t, []string{
+			"tick 2025-01-01T00:01:00Z: missing in the compared repository",
+			"tick 2025-01-01T00:03:00Z: ll_1: 3 != 42",
+			"tick 2025-01-01T00:03:00Z BTCUSDT: ask: 103.5 != 1",
+			"tick 2025-01-01T00:03:00Z ETHUSDT: ticker only in the compared repository",
+			"tick 2025-01-01T00:10:00Z: only in the compared repository",
+			"liquidation 2025-01-01T00:00:00Z BTCUSDT: p: 100 != 101",
+			"liquidation 2025-01-01T00:00:01Z ETHUSDT: missing in the compared repository",
+			"liquidation 2025-01-01T00:00:02Z SOLUSDT: only in the compared repository",
+		}, report)
+	})
+
+	t.Run("report is capped", func(t *testing.T) {
+		differ := NewDataDiffer(DataDiffConfig{
+			Ticks:               rangeTickRepository(storedTicks(start, 10)),
+			AgainstTicks:        rangeTickRepository(nil),
+			Liquidations:        rangeLiquidationRepository(nil),
+			AgainstLiquidations: rangeLiquidationRepository(nil),
+			MaxReported:         3,
+		})
+		result, err := differ.Run(context.Background(), start, start.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.Equal(t, 10, result.MissingTicks)
+		assert.Len(t, result.Differences, 3)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		differ := NewDataDiffer(DataDiffConfig{
+			Ticks: rangeTickRepository(nil),
+			AgainstTicks: &domainMocks.TickRepositoryMock{
+				GetRangeFunc: func(ctx context.Context, from, to time.Time) ([]domain.Tick, error) {
+					return nil, errors.New("connection refused")
+				},
+			},
+		})
+		_, err := differ.Run(context.Background(), start, start.Add(time.Hour))
+		assert.ErrorContains(t, err, "loading compared ticks")
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := NewDataDiffer(DataDiffConfig{}).Run(context.Background(), start, start)
+		assert.Error(t, err)
+	})
+}
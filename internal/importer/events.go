@@ -0,0 +1,27 @@
+package importer
+
+import (
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+)
+
+// publishTickBuilt announces a freshly built tick to every subscriber
+func (i *Importer) publishTickBuilt(tick *domain.Tick) {
+	i.events.Publish(eventbus.TickBuilt, tick)
+}
+
+// publishLiquidation announces a valid liquidation received from the exchange.
+// The archived exchange frame is for reprocessing the stored liquidations, subscribers don't get it
+func (i *Importer) publishLiquidation(liq domain.Liquidation) {
+	liq.Raw = nil
+	i.events.Publish(eventbus.LiquidationReceived, liq)
+}
+
+// publishDegraded reports a failed import step
+func (i *Importer) publishDegraded(stage string, err error) {
+	i.events.Publish(eventbus.ImportDegraded, eventbus.Degradation{
+		Exchange: i.exchange.GetName(),
+		Stage:    stage,
+		Err:      err,
+	})
+}
@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"go.uber.org/zap"
+)
+
+// MinFundingInterval is the shortest allowed interval between funding rate snapshots,
+// exchanges without a bulk endpoint (OKX) send a request per symbol for every snapshot
+const MinFundingInterval = 10 * time.Second
+
+// startFundingRatesImport starts storing funding rate snapshots if configured and supported by the exchange
+func (i *Importer) startFundingRatesImport(ctx context.Context) {
+	if i.fundingRateRepository == nil {
+		return
+	}
+	fetcher, ok := i.exchange.(exchanges.FundingRateFetcher)
+	if !ok {
+		i.logger.Warn("Funding rates are not served by the exchange, no funding snapshot is stored",
+			zap.String("exchange", i.exchange.GetName()))
+		return
+	}
+
+	i.logger.Info("Funding rates import started", zap.Duration("interval", i.fundingInterval))
+	i.supervisor.Go(ctx, "funding_rates", func(ctx context.Context) error {
+		i.pollFundingRates(ctx, fetcher)
+		return nil
+	})
+}
+
+// pollFundingRates stores a funding rate snapshot right away and then every interval until ctx is canceled
+func (i *Importer) pollFundingRates(ctx context.Context, fetcher exchanges.FundingRateFetcher) {
+	timeTicker := time.NewTicker(i.fundingInterval)
+	defer timeTicker.Stop()
+
+	for {
+		i.importFundingRates(ctx, fetcher, domain.Now())
+		select {
+		case <-ctx.Done():
+			i.logger.Info("Funding rates import stopped (context canceled).")
+			return
+		case <-timeTicker.C:
+		}
+	}
+}
+
+// importFundingRates fetches, validates and stores a funding rate snapshot taken at the given time.
+// Rates failing conversion or validation are left out, the snapshot is skipped when none is left
+func (i *Importer) importFundingRates(ctx context.Context, fetcher exchanges.FundingRateFetcher, at time.Time) {
+	fetched, err := fetcher.FetchFundingRates(ctx)
+	var convErr *exchanges.ConversionError
+	if errors.As(err, &convErr) && len(fetched) > 0 {
+		i.logger.Warn("Some funding rates failed conversion",
+			zap.Int("failed", convErr.Failed),
+			zap.Int("total", convErr.Total),
+			zap.Error(convErr),
+		)
+		err = nil
+	}
+	if err != nil {
+		i.telemetry.IncrementCounter(telemetryFundingRatesErrors, 1)
+		i.publishDegraded(eventbus.StageFetchFundingRates, err)
+		i.logger.Error("Failed to fetch funding rates", zap.Error(err))
+		return
+	}
+
+	rates := make([]domain.FundingRate, 0, len(fetched))
+	for _, f := range fetched {
+		rate := domain.FundingRate{
+			Symbol:        domain.TickerName(f.Symbol),
+			Rate:          f.Rate,
+			MarkPrice:     f.MarkPrice,
+			NextFundingAt: f.NextFundingAt,
+			EventAt:       f.EventAt,
+			CreatedAt:     at,
+		}
+		if err := rate.Validate(); err != nil {
+			i.logger.Debug("Funding rate validation failed", zap.String("symbol", f.Symbol), zap.Error(err))
+			continue
+		}
+		rates = append(rates, rate)
+	}
+	if len(rates) == 0 {
+		return
+	}
+
+	if err := i.fundingRateRepository.CreateMany(ctx, rates); err != nil {
+		i.publishDegraded(eventbus.StageStoreFundingRates, err)
+		i.logger.Error("Failed to store funding rates", zap.Error(err))
+		return
+	}
+	i.telemetry.IncrementCounter(telemetryFundingRatesStored, int64(len(rates)))
+}
@@ -0,0 +1,92 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fundingRatesFunc is an exchanges.FundingRateFetcher calling the function
+type fundingRatesFunc func(ctx context.Context) ([]exchanges.FundingRate, error)
+
+func (f fundingRatesFunc) FetchFundingRates(ctx context.Context) ([]exchanges.FundingRate, error) {
+	return f(ctx)
+}
+
+func TestImportFundingRates(t *testing.T) {
+	ts := setupTest()
+	repo := &domainMocks.FundingRateRepositoryMock{
+		CreateManyFunc: func(ctx context.Context, rates []domain.FundingRate) error {
+			return nil
+		},
+	}
+	ts.repoFactory.GetFundingRateRepositoryFunc = func(name string) (domain.FundingRateRepository, error) {
+		return repo, nil
+	}
+	importer := New(&Config{
+		Exchange:          ts.exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		FundingInterval:   time.Minute,
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+	assert.Equal(t, time.Minute, importer.fundingInterval)
+
+	var mu sync.Mutex
+	var stages []string
+	ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		stages = append(stages, event.Payload.(eventbus.Degradation).Stage)
+	}, eventbus.ImportDegraded)
+
+	at := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	nextFundingAt := at.Add(8 * time.Hour)
+	fetched := []exchanges.FundingRate{
+		{Symbol: "BTCUSDT", Rate: 0.0001, MarkPrice: 95000, NextFundingAt: nextFundingAt, EventAt: at.Add(-time.Second)},
+		{Symbol: "ETHUSDT", Rate: math.NaN(), NextFundingAt: nextFundingAt, EventAt: at.Add(-time.Second)},
+	}
+	partial := fundingRatesFunc(func(ctx context.Context) ([]exchanges.FundingRate, error) {
+		return fetched, exchanges.NewConversionError(3, []error{errors.New("SOLUSDT: invalid rate")})
+	})
+	importer.importFundingRates(context.Background(), partial, at)
+
+	calls := repo.CreateManyCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, []domain.FundingRate{
+		{Symbol: "BTCUSDT", Rate: 0.0001, MarkPrice: 95000, NextFundingAt: nextFundingAt, EventAt: at.Add(-time.Second), CreatedAt: at},
+	}, calls[0].Rates, "the rates failing conversion or validation are left out")
+
+	failing := fundingRatesFunc(func(ctx context.Context) ([]exchanges.FundingRate, error) {
+		return nil, errors.New("connection refused")
+	})
+	importer.importFundingRates(context.Background(), failing, at)
+	repo.CreateManyFunc = func(ctx context.Context, rates []domain.FundingRate) error {
+		return errors.New("db is down")
+	}
+	importer.importFundingRates(context.Background(), partial, at)
+	ts.events.Flush()
+
+	assert.Len(t, repo.CreateManyCalls(), 2)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{eventbus.StageFetchFundingRates, eventbus.StageStoreFundingRates}, stages)
+}
+
+func TestNewSkipsFundingRateRepositoryWhenDisabled(t *testing.T) {
+	ts := setupTest()
+	assert.Nil(t, ts.importer.fundingRateRepository)
+	assert.Empty(t, ts.repoFactory.GetFundingRateRepositoryCalls())
+}
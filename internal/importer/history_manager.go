@@ -2,41 +2,137 @@ package importer
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"go.uber.org/zap"
 )
 
-// initHistory loads old data from repositories and populates ring buffers
+// DefaultHistoryTimeout bounds the loading and replay of the history on start
+const DefaultHistoryTimeout = 30 * time.Second
+
+// historyProgressInterval is how often the replay of the history logs its progress
+const historyProgressInterval = 2 * time.Second
+
+// initHistory loads old data from repositories and populates ring buffers. The ticker histories are replayed
+// in parallel, one symbol per worker at a time. Once historyTimeout passed the remaining symbols are left without
+// history, like newly listed ones, so a slow start doesn't delay the first tick any further
 func (i *Importer) initHistory(ctx context.Context) error {
-	history, err := i.tickRepository.GetHistorySince(ctx, time.Now().Add(-domain.MaxTickHistory*time.Minute))
+	start := time.Now()
+	timeoutCtx, cancel := context.WithTimeout(ctx, i.historyTimeout)
+	defer cancel()
+
+	history, err := i.tickRepository.GetHistorySince(timeoutCtx, time.Now().Add(-domain.MaxTickHistory*time.Minute))
+	if err != nil && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		i.logger.Warn("History load timed out, starting without history", zap.Duration("timeout", i.historyTimeout))
+		history, err = nil, nil
+	}
 	if err != nil {
 		return fmt.Errorf("GetHistorySince failed: %w", err)
 	}
 
+	bySymbol := make(map[domain.TickerName][]*domain.Ticker)
 	for _, tick := range history {
 		i.addTickHistory(&tick)
-		for _, ticker := range tick.Data {
-			i.addTickerHistory(ticker)
+		for symbol, ticker := range tick.Data {
+			bySymbol[symbol] = append(bySymbol[symbol], ticker)
 		}
 	}
 
+	replayed := i.replayTickerHistory(timeoutCtx, bySymbol)
+	duration := time.Since(start)
+	i.telemetry.Timing(telemetryHistoryInitDuration, duration)
+	if replayed < len(bySymbol) {
+		i.logger.Warn("History replay timed out, the remaining symbols start without history",
+			zap.Int("replayed", replayed),
+			zap.Int("symbols", len(bySymbol)),
+			zap.Duration("timeout", i.historyTimeout),
+		)
+	}
+	i.logger.Info("History loaded",
+		zap.Int("ticks", len(history)),
+		zap.Int("symbols", replayed),
+		zap.Duration("duration", duration),
+	)
+
 	return nil
 }
 
+// replayTickerHistory adds the tickers of every symbol, oldest first, to the ticker history until ctx is done
+// and returns the number of symbols replayed
+func (i *Importer) replayTickerHistory(ctx context.Context, bySymbol map[domain.TickerName][]*domain.Ticker) int {
+	symbols := make(chan domain.TickerName, len(bySymbol))
+	for symbol := range bySymbol {
+		symbols <- symbol
+	}
+	close(symbols)
+
+	var replayed atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		progress := time.NewTicker(historyProgressInterval)
+		defer progress.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-progress.C:
+				i.logger.Info("Replaying history", zap.Int64("replayed", replayed.Load()), zap.Int("symbols", len(bySymbol)))
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.NumCPU(), len(bySymbol)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range symbols {
+				if ctx.Err() != nil {
+					return
+				}
+				for _, ticker := range bySymbol[symbol] {
+					i.addTickerHistory(ticker)
+				}
+				replayed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+
+	return int(replayed.Load())
+}
+
 func (i *Importer) addTickHistory(tick *domain.Tick) {
 	lastTick, exists := i.tickHistory.Last()
 	if exists && lastTick.StartAt.After(tick.StartAt) {
 		return
 	}
 
+	if i.historySince.IsZero() {
+		i.historySince = tick.StartAt
+	}
 	i.tickHistory.Push(tick)
 }
 
-// addTickerHistory updates the ring buffer for a particular ticker - 1 item per 1 minute
-func (i *Importer) addTickerHistory(ticker *domain.Ticker) {
-	i.tickerHistory.UpdateTicker(ticker)
+// warmingUp reports whether a tick started at startAt is built before the history covers the warm-up
+func (i *Importer) warmingUp(startAt time.Time) bool {
+	if i.warmUp <= 0 {
+		return false
+	}
+	return i.historySince.IsZero() || startAt.Sub(i.historySince) < i.warmUp
+}
+
+// addTickerHistory updates the ring buffer for a particular ticker - 1 item per 1 minute.
+// It returns the bar of the previous minute once the ticker starts a new one
+func (i *Importer) addTickerHistory(ticker *domain.Ticker) *domain.Bar {
+	return i.tickerHistory.UpdateTicker(ticker)
 }
 
 func (i *Importer) getLastTick() (*domain.Tick, error) {
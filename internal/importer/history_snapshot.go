@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// HistorySnapshot is the in-memory history the indicators are calculated from, exported to debug them
+type HistorySnapshot struct {
+	Exchange   string                                `json:"exchange"`
+	ExportedAt time.Time                             `json:"exported_at"`
+	Ticks      []domain.Tick                         `json:"ticks"`   // oldest first
+	Tickers    map[domain.TickerName][]domain.Ticker `json:"tickers"` // one entry per minute, oldest first, the last one is the live minute
+}
+
+// Snapshot copies the ticker histories, the live minutes keep changing once the shard is unlocked
+func (thm *tickerHistoryMap) Snapshot() map[domain.TickerName][]domain.Ticker {
+	snapshot := make(map[domain.TickerName][]domain.Ticker)
+	for i := range thm.shards {
+		shard := &thm.shards[i]
+		shard.mu.RLock()
+		for name, history := range shard.data {
+			tickers := make([]domain.Ticker, 0, history.Len())
+			for _, ticker := range history.Values() {
+				tickers = append(tickers, *ticker)
+			}
+			snapshot[name] = tickers
+		}
+		shard.mu.RUnlock()
+	}
+	return snapshot
+}
+
+// Lookup returns a copy of the history of the symbol, false when it has none. Unlike Get, it creates no history
+func (thm *tickerHistoryMap) Lookup(name domain.TickerName) ([]domain.Ticker, bool) {
+	shard := thm.shard(name)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	history, ok := shard.data[name]
+	if !ok || history.Len() == 0 {
+		return nil, false
+	}
+	tickers := make([]domain.Ticker, 0, history.Len())
+	for _, ticker := range history.Values() {
+		tickers = append(tickers, *ticker)
+	}
+	return tickers, true
+}
+
+// CopyTick copies the tick with its tickers. A ticker opening a minute is shared with the ticker history,
+// which keeps updating it for the rest of the minute, so it's copied under the lock of its shard
+func (thm *tickerHistoryMap) CopyTick(tick *domain.Tick) domain.Tick {
+	copied := *tick
+	copied.Data = make(map[domain.TickerName]*domain.Ticker, len(tick.Data))
+	for name, ticker := range tick.Data {
+		shard := thm.shard(name)
+		shard.mu.RLock()
+		t := *ticker
+		shard.mu.RUnlock()
+		copied.Data[name] = &t
+	}
+	return copied
+}
+
+// HistorySnapshot returns a copy of the tick and ticker histories, the tick in flight left out as it's still being built
+func (i *Importer) HistorySnapshot() HistorySnapshot {
+	ticks := i.publishedTicks(time.Time{}, 0)
+	if ticks == nil {
+		ticks = []domain.Tick{}
+	}
+	return HistorySnapshot{
+		Exchange:   i.exchange.GetName(),
+		ExportedAt: time.Now().UTC(),
+		Ticks:      ticks,
+		Tickers:    i.tickerHistory.Snapshot(),
+	}
+}
+
+// ExportHistory writes the HistorySnapshot as JSON
+func (i *Importer) ExportHistory(w io.Writer) error {
+	snapshot := i.HistorySnapshot()
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return fmt.Errorf("encoding history of %d ticks and %d symbols: %w", len(snapshot.Ticks), len(snapshot.Tickers), err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportHistory(t *testing.T) {
+	ts := setupSnapshotTest()
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	var buf bytes.Buffer
+	require.NoError(t, ts.importer.ExportHistory(&buf))
+
+	var snapshot HistorySnapshot
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &snapshot))
+	assert.Equal(t, "mockExchange", snapshot.Exchange)
+	assert.False(t, snapshot.ExportedAt.IsZero())
+	require.Len(t, snapshot.Ticks, 2)
+	assert.Len(t, snapshot.Ticks[1].Data, 2)
+	assert.ElementsMatch(t, []domain.TickerName{"BTCUSDT", "ETHUSDT"}, keys(snapshot.Tickers))
+	require.NotEmpty(t, snapshot.Tickers["BTCUSDT"])
+	assert.Equal(t, 50000.0, snapshot.Tickers["BTCUSDT"][0].Ask)
+}
+
+func TestHistorySnapshot_CopiesLiveMinute(t *testing.T) {
+	ts := setupSnapshotTest()
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	snapshot := ts.importer.HistorySnapshot()
+	live, ok := ts.importer.tickerHistory.Get("BTCUSDT").Last()
+	require.True(t, ok)
+	live.Ask = 1
+
+	assert.Equal(t, 50000.0, snapshot.Tickers["BTCUSDT"][0].Ask)
+	assert.Equal(t, 50000.0, snapshot.Ticks[0].Data["BTCUSDT"].Ask)
+}
+
+func TestHistorySnapshot_LeavesTickInFlightOut(t *testing.T) {
+	ts := setupSnapshotTest()
+	assert.NotNil(t, ts.importer.HistorySnapshot().Ticks)
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	published := ts.importer.tickHistory.At(0)
+	ts.importer.addTickHistory(&domain.Tick{StartAt: published.StartAt.Add(time.Second)})
+	snapshot := ts.importer.HistorySnapshot()
+	require.Len(t, snapshot.Ticks, 1)
+	assert.True(t, published.StartAt.Equal(snapshot.Ticks[0].StartAt))
+}
+
+// setupSnapshotTest returns a suite whose tickers pass validation, so they are added to the history
+func setupSnapshotTest() *testSuite {
+	ts := setupTest()
+	ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+		return []exchanges.Ticker{
+			{Symbol: "BTCUSDT", AskPrice: 50000, BidPrice: 49900, EventAt: time.Now()},
+			{Symbol: "ETHUSDT", AskPrice: 3000, BidPrice: 2990, EventAt: time.Now()},
+		}, nil
+	}
+	return ts
+}
+
+func keys[K comparable, V any](m map[K]V) []K {
+	out := make([]K, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"reflect"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Sizes used to estimate the memory held by the history. They leave out the strings and slices a ticker references,
+// which are small next to the ticker itself
+var (
+	tickSize        = int(reflect.TypeFor[domain.Tick]().Size())
+	tickerSize      = int(reflect.TypeFor[domain.Ticker]().Size())
+	statsBucketSize = int(reflect.TypeFor[statsBucket]().Size())
+	symbolStatsSize = int(reflect.TypeFor[symbolStats]().Size())
+)
+
+// mapEntrySize approximates the overhead of a map entry keyed by a symbol and holding a pointer
+const mapEntrySize = 48
+
+// historyStats describes what the in-memory history holds
+type historyStats struct {
+	Symbols       int // symbols with a ticker history
+	TickEntries   int // ticks in the tick history
+	TickerEntries int // minutes in the ticker histories of all symbols
+	TickBytes     int // estimated memory of the tick history, including the tickers of its ticks
+	TickerBytes   int // estimated memory of the ticker histories
+}
+
+// Stats returns the number of symbols and minutes held by the ticker histories and an estimate of their memory
+func (thm *tickerHistoryMap) Stats() historyStats {
+	var stats historyStats
+	for i := range thm.shards {
+		thm.shards[i].mu.RLock()
+		stats.Symbols += len(thm.shards[i].data)
+		for _, history := range thm.shards[i].data {
+			stats.TickerEntries += history.Len()
+		}
+		thm.shards[i].mu.RUnlock()
+	}
+	stats.TickerBytes = stats.Symbols*mapEntrySize + stats.TickerEntries*tickerSize
+	return stats
+}
+
+// Stats returns the number of ticks held by the tick history and an estimate of their memory
+func (th *tickHistory) Stats() historyStats {
+	stats := historyStats{TickEntries: th.buffer.Len()}
+	th.buffer.Range(func(_ int, tick *domain.Tick) bool {
+		stats.TickBytes += tickSize + len(tick.Data)*(mapEntrySize+tickerSize)
+		return true
+	})
+	return stats
+}
+
+// trimHistory evicts the least recently updated symbols past maxSymbols from the ticker history and the other
+// per-symbol state, and reports the size of the history
+func (i *Importer) trimHistory() {
+	if evicted := i.tickerHistory.Evict(i.maxSymbols); len(evicted) > 0 {
+		i.evictSymbols(evicted)
+		i.telemetry.IncrementCounter(telemetryHistorySymbolsEvicted, int64(len(evicted)))
+		i.logger.Info("Evicted the least recently updated symbols from the history",
+			zap.Int("evicted", len(evicted)),
+			zap.Int("max_symbols", i.maxSymbols),
+			zap.String("oldest", string(evicted[0])),
+		)
+	}
+
+	tickers, ticks := i.tickerHistory.Stats(), i.tickHistory.Stats()
+	i.telemetry.Gauge(telemetryHistorySymbols, float64(tickers.Symbols))
+	i.telemetry.Gauge(telemetryHistoryEntries, float64(ticks.TickEntries), "history:tick")
+	i.telemetry.Gauge(telemetryHistoryEntries, float64(tickers.TickerEntries), "history:ticker")
+	i.telemetry.Gauge(telemetryHistoryBytes, float64(ticks.TickBytes), "history:tick")
+	i.telemetry.Gauge(telemetryHistoryBytes, float64(tickers.TickerBytes), "history:ticker")
+
+	statsSymbols, statsBuckets := i.rollingStats.size()
+	i.telemetry.Gauge(telemetryHistoryEntries, float64(statsBuckets), "history:rolling_stats")
+	i.telemetry.Gauge(telemetryHistoryBytes, float64(statsSymbols*(mapEntrySize+symbolStatsSize)+statsBuckets*statsBucketSize), "history:rolling_stats")
+	i.telemetry.Gauge(telemetrySymbolsTracked, float64(statsSymbols), "state:rolling_stats")
+	i.telemetry.Gauge(telemetrySymbolsTracked, float64(i.latency.len()), "state:latency")
+	i.telemetry.Gauge(telemetrySymbolsTracked, float64(i.lastLiquidations.len()), "state:last_liquidations")
+	i.telemetry.Gauge(telemetrySymbolsTracked, float64(i.books.len()), "state:books")
+	if i.seenLiquidations != nil {
+		i.telemetry.Gauge(telemetrySymbolsTracked, float64(i.seenLiquidations.len()), "state:seen_liquidations")
+	}
+	if i.openInterest != nil {
+		i.telemetry.Gauge(telemetrySymbolsTracked, float64(i.openInterest.len()), "state:open_interest")
+	}
+	if i.symbols != nil {
+		i.telemetry.Gauge(telemetrySymbolsTracked, float64(i.symbols.len()), "state:registry")
+	}
+}
+
+// evictSymbols drops the symbols evicted from the ticker history from the other per-symbol state, so the state of
+// delisted or renamed symbols doesn't grow without bound
+func (i *Importer) evictSymbols(symbols []domain.TickerName) {
+	i.rollingStats.evict(symbols)
+	i.latency.evict(symbols)
+	i.lastLiquidations.evict(symbols)
+	i.books.evict(symbols)
+	if i.seenLiquidations != nil {
+		i.seenLiquidations.evict(symbols)
+	}
+	if i.openInterest != nil {
+		i.openInterest.evict(symbols)
+	}
+	if i.symbols != nil {
+		i.symbols.evict(symbols)
+	}
+}
@@ -2,6 +2,8 @@ package importer
 
 import (
 	"math"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -36,46 +38,85 @@ func (th *tickHistory) At(index int) *domain.Tick {
 	return th.buffer.At(index)
 }
 
-// tickerHistoryMap represents a thread-safe map of ticker histories
+// tickerHistoryShards is the number of independently locked partitions of tickerHistoryMap.
+// Workers building tickers for different symbols rarely hit the same shard.
+const tickerHistoryShards = 64
+
+// tickerHistoryMap represents a thread-safe map of ticker histories sharded by symbol hash
 type tickerHistoryMap struct {
-	data map[domain.TickerName]*utils.RingBuffer[*domain.Ticker]
+	shards []tickerHistoryShard
+}
+
+// tickerHistoryShard is a single partition of tickerHistoryMap guarded by its own lock
+type tickerHistoryShard struct {
+	data map[domain.TickerName]*domain.TickerHistory
+	bars map[domain.TickerName]*domain.Bar // bar of the live minute
 	mu   sync.RWMutex
 }
 
 func newTickerHistoryMap() *tickerHistoryMap {
-	return &tickerHistoryMap{
-		data: make(map[domain.TickerName]*utils.RingBuffer[*domain.Ticker]),
+	return newShardedTickerHistoryMap(tickerHistoryShards)
+}
+
+func newShardedTickerHistoryMap(shards int) *tickerHistoryMap {
+	thm := &tickerHistoryMap{
+		shards: make([]tickerHistoryShard, max(shards, 1)),
+	}
+	for i := range thm.shards {
+		thm.shards[i].data = make(map[domain.TickerName]*domain.TickerHistory)
+		thm.shards[i].bars = make(map[domain.TickerName]*domain.Bar)
 	}
+	return thm
 }
 
-func (thm *tickerHistoryMap) Get(name domain.TickerName) *utils.RingBuffer[*domain.Ticker] {
-	thm.mu.RLock()
-	history, ok := thm.data[name]
-	thm.mu.RUnlock()
+// shard returns the partition responsible for the symbol (FNV-1a hash)
+func (thm *tickerHistoryMap) shard(name domain.TickerName) *tickerHistoryShard {
+	hash := uint32(2166136261)
+	for i := 0; i < len(name); i++ {
+		hash ^= uint32(name[i])
+		hash *= 16777619
+	}
+	return &thm.shards[hash%uint32(len(thm.shards))]
+}
+
+func (thm *tickerHistoryMap) Get(name domain.TickerName) *domain.TickerHistory {
+	shard := thm.shard(name)
+
+	shard.mu.RLock()
+	history, ok := shard.data[name]
+	shard.mu.RUnlock()
 
 	if !ok {
-		thm.mu.Lock()
-		// Double-check after acquiring write lock
-		history, ok = thm.data[name]
-		if !ok {
-			history = utils.NewRingBuffer[*domain.Ticker](domain.MaxTickHistory)
-			thm.data[name] = history
-		}
-		thm.mu.Unlock()
+		shard.mu.Lock()
+		history = shard.getOrCreate(name)
+		shard.mu.Unlock()
 	}
 	return history
 }
 
-// UpdateTicker atomically updates or adds a new ticker to the history
-func (thm *tickerHistoryMap) UpdateTicker(ticker *domain.Ticker) {
-	thm.mu.Lock()
-	defer thm.mu.Unlock()
+// Len returns the number of symbols with history
+func (thm *tickerHistoryMap) Len() int {
+	total := 0
+	for i := range thm.shards {
+		thm.shards[i].mu.RLock()
+		total += len(thm.shards[i].data)
+		thm.shards[i].mu.RUnlock()
+	}
+	return total
+}
+
+// UpdateTicker atomically updates or adds a new ticker to the history.
+// When the ticker starts a new minute the bar of the previous one is finalized and returned
+func (thm *tickerHistoryMap) UpdateTicker(ticker *domain.Ticker) *domain.Bar {
+	shard := thm.shard(ticker.Symbol)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
 
-	history := thm.getOrCreateBuffer(ticker.Symbol)
+	history := shard.getOrCreate(ticker.Symbol)
 	lastTickerData, exists := history.Last()
 	if exists && lastTickerData.CreatedAt.After(ticker.CreatedAt) {
 		// Skip older data
-		return
+		return nil
 	}
 
 	if !exists || !lastTickerData.CreatedAt.Truncate(time.Minute).Equal(ticker.CreatedAt.Truncate(time.Minute)) {
@@ -83,24 +124,77 @@ func (thm *tickerHistoryMap) UpdateTicker(ticker *domain.Ticker) {
 		ticker.Max = ticker.Ask
 		ticker.Min = ticker.Ask
 		history.Push(ticker)
-		return
-	}
 
-	if lastTickerData.CreatedAt.After(ticker.CreatedAt) {
-		// Skip older data
-		return
+		closed := shard.bars[ticker.Symbol]
+		shard.bars[ticker.Symbol] = domain.NewBar(ticker)
+		return closed
 	}
 
 	// Update existing minute data
 	updateMinuteData(lastTickerData, ticker)
+	if bar, ok := shard.bars[ticker.Symbol]; ok {
+		bar.Update(ticker)
+	} else {
+		shard.bars[ticker.Symbol] = domain.NewBar(ticker)
+	}
+	return nil
+}
+
+// Evict removes the histories and live bars of the least recently updated symbols past maxSymbols
+// and returns the removed symbols, oldest first. Symbols without history go first
+func (thm *tickerHistoryMap) Evict(maxSymbols int) []domain.TickerName {
+	if maxSymbols <= 0 {
+		return nil
+	}
+	type symbolUpdate struct {
+		name      domain.TickerName
+		updatedAt time.Time
+	}
+	var updates []symbolUpdate
+	for i := range thm.shards {
+		thm.shards[i].mu.RLock()
+		for name, history := range thm.shards[i].data {
+			update := symbolUpdate{name: name}
+			if last, ok := history.Last(); ok {
+				update.updatedAt = last.CreatedAt
+			}
+			updates = append(updates, update)
+		}
+		thm.shards[i].mu.RUnlock()
+	}
+	if len(updates) <= maxSymbols {
+		return nil
+	}
+
+	slices.SortFunc(updates, func(a, b symbolUpdate) int {
+		if c := a.updatedAt.Compare(b.updatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(string(a.name), string(b.name))
+	})
+	evicted := make([]domain.TickerName, 0, len(updates)-maxSymbols)
+	for _, update := range updates[:len(updates)-maxSymbols] {
+		shard := thm.shard(update.name)
+		shard.mu.Lock()
+		// A symbol updated since it was picked isn't the least recently updated anymore
+		if history, ok := shard.data[update.name]; ok {
+			if last, ok := history.Last(); !ok || !last.CreatedAt.After(update.updatedAt) {
+				delete(shard.data, update.name)
+				delete(shard.bars, update.name)
+				evicted = append(evicted, update.name)
+			}
+		}
+		shard.mu.Unlock()
+	}
+	return evicted
 }
 
-// getOrCreateBuffer returns existing buffer or creates a new one (must be called under lock)
-func (thm *tickerHistoryMap) getOrCreateBuffer(name domain.TickerName) *utils.RingBuffer[*domain.Ticker] {
-	history, ok := thm.data[name]
+// getOrCreate returns existing history or creates a new one (must be called under lock)
+func (s *tickerHistoryShard) getOrCreate(name domain.TickerName) *domain.TickerHistory {
+	history, ok := s.data[name]
 	if !ok {
-		history = utils.NewRingBuffer[*domain.Ticker](domain.MaxTickHistory)
-		thm.data[name] = history
+		history = domain.NewTickerHistory(domain.MaxTickHistory)
+		s.data[name] = history
 	}
 	return history
 }
@@ -112,6 +206,9 @@ func updateMinuteData(existingTicker, newTicker *domain.Ticker) {
 	existingTicker.Ask = newTicker.Ask
 	existingTicker.Bid = newTicker.Bid
 	existingTicker.CreatedAt = newTicker.CreatedAt
+	if newTicker.OpenInterest > 0 {
+		existingTicker.OpenInterest = newTicker.OpenInterest
+	}
 
 	// Mirror changes to the newTicker ticker
 	newTicker.Max = existingTicker.Max
@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickerHistoryMap_ShardsKeepSymbolsApart(t *testing.T) {
+	thm := newTickerHistoryMap()
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 500; i++ {
+		thm.UpdateTicker(&domain.Ticker{
+			Symbol:    domain.TickerName(fmt.Sprintf("SYM%dUSDT", i)),
+			Ask:       float64(i + 1),
+			CreatedAt: startAt,
+		})
+	}
+
+	assert.Equal(t, 500, thm.Len())
+	for i := 0; i < 500; i++ {
+		last, ok := thm.Get(domain.TickerName(fmt.Sprintf("SYM%dUSDT", i))).Last()
+		assert.True(t, ok)
+		assert.Equal(t, float64(i+1), last.Ask)
+	}
+}
+
+func TestTickerHistoryMap_UpdateTickerClosesBars(t *testing.T) {
+	thm := newTickerHistoryMap()
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	update := func(at time.Time, ask float64) *domain.Bar {
+		return thm.UpdateTicker(&domain.Ticker{Symbol: "BTCUSDT", Ask: ask, Bid: ask, CreatedAt: at})
+	}
+
+	assert.Nil(t, update(startAt, 100))
+	assert.Nil(t, update(startAt.Add(30*time.Second), 110))
+	assert.Nil(t, update(startAt.Add(20*time.Second), 120), "older data is skipped")
+
+	bar := update(startAt.Add(time.Minute), 90)
+	if assert.NotNil(t, bar) {
+		assert.Equal(t, domain.Bar{Symbol: "BTCUSDT", StartAt: startAt, Open: 100, High: 110, Low: 100, Close: 110, Samples: 2}, *bar)
+	}
+	assert.Nil(t, update(startAt.Add(90*time.Second), 95))
+}
+
+func TestTickerHistoryMap_Evict(t *testing.T) {
+	thm := newTickerHistoryMap()
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	thm.UpdateTicker(&domain.Ticker{Symbol: "BTCUSDT", Ask: 1, CreatedAt: startAt.Add(2 * time.Minute)})
+	thm.UpdateTicker(&domain.Ticker{Symbol: "TESTUSDT", Ask: 1, CreatedAt: startAt})
+	thm.UpdateTicker(&domain.Ticker{Symbol: "ETHUSDT", Ask: 1, CreatedAt: startAt.Add(time.Minute)})
+	thm.Get("NEWUSDT")
+
+	assert.Nil(t, thm.Evict(0), "0 is unlimited")
+	assert.Nil(t, thm.Evict(4))
+	assert.Equal(t, []domain.TickerName{"NEWUSDT", "TESTUSDT"}, thm.Evict(2), "symbols without history go first")
+	assert.Equal(t, 2, thm.Len())
+	assert.Zero(t, thm.Get("TESTUSDT").Len(), "an evicted symbol loses its history")
+
+	// an evicted symbol starts anew
+	assert.Nil(t, thm.UpdateTicker(&domain.Ticker{Symbol: "TESTUSDT", Ask: 2, CreatedAt: startAt.Add(3 * time.Minute)}))
+	assert.Equal(t, []domain.TickerName{"ETHUSDT"}, thm.Evict(2))
+	assert.Equal(t, 1, thm.Get("TESTUSDT").Len())
+}
+
+func TestTrimHistory_EvictsSymbolState(t *testing.T) {
+	ts := setupTest()
+	i := ts.importer
+	i.maxSymbols = 1
+	i.seenLiquidations = newSeenLiquidations()
+	i.openInterest = newOpenInterest(time.Minute)
+	i.symbols = newSymbolRegistry(time.Hour)
+
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for n, symbol := range []domain.TickerName{"LUNAUSDT", "BTCUSDT"} {
+		at := startAt.Add(time.Duration(n) * time.Minute)
+		i.tickerHistory.UpdateTicker(&domain.Ticker{Symbol: symbol, Ask: 1, Bid: 1, CreatedAt: at})
+		i.rollingStats.addTicker(&domain.Ticker{Symbol: symbol, Ask: 1, Bid: 1, CreatedAt: at})
+		i.latency.record([]symbolDuration{{symbol: symbol, duration: time.Millisecond}})
+		liq := domain.Liquidation{Order: domain.Order{Symbol: symbol}, EventAt: at}
+		i.lastLiquidations.add(liq)
+		i.seenLiquidations.add(liq, at)
+		i.books.update(symbol, 1, 1, at)
+		i.symbols.observe([]exchanges.Ticker{{Symbol: string(symbol)}}, at)
+	}
+	i.openInterest.set([]exchanges.OpenInterest{{Symbol: "LUNAUSDT", Value: 1}, {Symbol: "BTCUSDT", Value: 1}}, startAt)
+
+	i.trimHistory()
+
+	_, ok := i.rollingStats.get("LUNAUSDT")
+	assert.False(t, ok)
+	assert.Equal(t, 1, i.latency.len())
+	assert.Nil(t, i.lastLiquidations.at("LUNAUSDT", startAt))
+	assert.Equal(t, 1, i.lastLiquidations.len())
+	assert.Equal(t, 1, i.books.len())
+	assert.Equal(t, 1, i.seenLiquidations.len())
+	assert.Zero(t, i.openInterest.at("LUNAUSDT", startAt))
+	assert.Equal(t, 1, i.openInterest.len())
+	assert.Equal(t, []domain.TickerName{"BTCUSDT"}, []domain.TickerName{i.symbols.all()[0].Symbol})
+	assert.Len(t, i.symbols.take(), 2, "the changes of an evicted symbol are still stored")
+}
+
+func TestHistory_Stats(t *testing.T) {
+	thm := newTickerHistoryMap()
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for minute := 0; minute < 3; minute++ {
+		thm.UpdateTicker(&domain.Ticker{Symbol: "BTCUSDT", Ask: 1, CreatedAt: startAt.Add(time.Duration(minute) * time.Minute)})
+	}
+	thm.UpdateTicker(&domain.Ticker{Symbol: "ETHUSDT", Ask: 1, CreatedAt: startAt})
+
+	stats := thm.Stats()
+	assert.Equal(t, 2, stats.Symbols)
+	assert.Equal(t, 4, stats.TickerEntries)
+	assert.Equal(t, 2*mapEntrySize+4*tickerSize, stats.TickerBytes)
+
+	th := newTickHistory(domain.MaxTickHistory)
+	th.Push(&domain.Tick{Data: map[domain.TickerName]*domain.Ticker{"BTCUSDT": {}, "ETHUSDT": {}}})
+	th.Push(&domain.Tick{})
+	stats = th.Stats()
+	assert.Equal(t, 2, stats.TickEntries)
+	assert.Equal(t, 2*tickSize+2*(mapEntrySize+tickerSize), stats.TickBytes)
+}
+
+// BenchmarkTickerHistoryMap_UpdateTicker compares a single lock with the sharded map
+// under the access pattern of buildTick: many workers updating different symbols.
+func BenchmarkTickerHistoryMap_UpdateTicker(b *testing.B) {
+	const symbolsCount = 2000
+	symbols := make([]domain.TickerName, symbolsCount)
+	for i := range symbols {
+		symbols[i] = domain.TickerName(fmt.Sprintf("SYM%dUSDT", i))
+	}
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, shards := range []int{1, tickerHistoryShards} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			thm := newShardedTickerHistoryMap(shards)
+			var workers atomic.Int64
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				// Each worker starts at its own offset so workers touch different symbols
+				n := workers.Add(1) * 7919
+				for pb.Next() {
+					n++
+					thm.UpdateTicker(&domain.Ticker{
+						Symbol:    symbols[n%symbolsCount],
+						Ask:       100,
+						Bid:       99,
+						CreatedAt: startAt.Add(time.Duration(n/symbolsCount) * time.Second),
+					})
+				}
+			})
+		})
+	}
+}
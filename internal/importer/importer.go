@@ -3,53 +3,147 @@ package importer
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
-	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/maintenance"
+	"github.com/ayankousky/exchange-data-importer/internal/supervisor"
+	"github.com/ayankousky/exchange-data-importer/internal/symbolmeta"
+	"github.com/ayankousky/exchange-data-importer/internal/usd"
 	"go.uber.org/zap"
 )
 
 //go:generate moq --out mocks/repository_factory.go --pkg mocks --with-resets --skip-ensure . RepositoryFactory
-//go:generate moq --out mocks/notifier.go --pkg mocks --with-resets --skip-ensure . NotifierService
 
-const defaultTickInterval = time.Second // defines the default time interval between each tick operation in the import loop.
+// TickInterval is the time interval between each tick operation in the import loop
+const TickInterval = time.Second
+
+// storeTickAttempts is the number of times storing a tick is attempted before the tick is reported as not stored
+const storeTickAttempts = 3
+
+// storeTickBackoff is the delay before the first retry of storing a tick, doubled on every following retry
+var storeTickBackoff = 50 * time.Millisecond
+
+// DefaultMaxConversionFailureRatio is the share of tickers failing conversion above which a tick is skipped
+const DefaultMaxConversionFailureRatio = 0.2
 
 // RepositoryFactory is a contract for creating repositories
 type RepositoryFactory interface {
 	GetTickRepository(name string) (domain.TickRepository, error)
 	GetLiquidationRepository(name string) (domain.LiquidationRepository, error)
-}
-
-// NotifierService represents the notifier service contract
-type NotifierService interface {
-	Subscribe(topic string, client notify.Client, strategy notify.Strategy)
-	Notify(ctx context.Context, data any)
+	GetSubTickRepository(name string) (domain.SubTickRepository, error)
+	GetBarRepository(name string) (domain.BarRepository, error)
+	GetFundingRateRepository(name string) (domain.FundingRateRepository, error)
+	GetTradeRepository(name string) (domain.TradeRepository, error)
+	GetSymbolRepository(name string) (domain.SymbolRepository, error)
 }
 
 // Importer is responsible for importing data from an exchange and storing it in the database
 type Importer struct {
 	exchange              exchanges.Exchange
+	liquidationSources    []exchanges.LiquidationSource
 	tickRepository        domain.TickRepository
 	liquidationRepository domain.LiquidationRepository
+	subTickRepository     domain.SubTickRepository     // only set in high-resolution mode
+	barRepository         domain.BarRepository         // only set when minute bars are enabled
+	fundingRateRepository domain.FundingRateRepository // only set when funding rates are enabled
+	tradeRepository       domain.TradeRepository       // only set when trades are imported
+	symbolRepository      domain.SymbolRepository      // only set when the symbol registry is enabled
 
-	tickHistory   *tickHistory
-	tickerHistory *tickerHistoryMap
+	tickHistory        *tickHistory
+	tickerHistory      *tickerHistoryMap
+	latency            *latencyTracker
+	lastLiquidations   *lastLiquidations
+	recentLiquidations *recentLiquidations
+	books              *latestBooks
+	seenLiquidations   *seenLiquidations // only set with liquidation sources
+	watermark          *liquidationWatermark
+	streamRates        *streamRates
+	rollingStats       *rollingStats
+	openInterest       *openInterest   // nil when the open interest isn't polled
+	tradeVolumes       *tradeVolumes   // nil when trades aren't imported
+	symbols            *symbolRegistry // nil when the symbol registry is disabled
+	liquidationStorage *liquidationStorage
 
-	notifier  NotifierService
-	telemetry telemetry.Provider
-	logger    *zap.Logger
+	// liquidationsFreshness is the latest message of the liquidation stream, unset when liquidations aren't streamed
+	liquidationsFreshness streamFreshness
+
+	mode                      Mode
+	publishOrder              PublishOrder
+	liquidationsBackfill      time.Duration
+	archiveRawLiquidations    bool
+	maxLiquidationDeviation   float64
+	logTickSummary            bool
+	maxConversionFailureRatio float64
+	liquidity                 LiquidityFilter
+	symbolMapper              exchanges.SymbolMapper
+	highRes                   HighResConfig
+	tradeSymbols              []string
+	priority                  *priorityList // nil when no priority symbols are configured
+	storageSampler            *storageSampler
+	normalizeUSD              bool
+	maxSymbols                int
+	maintenance               maintenance.Calendar
+	symbolMeta                *symbolmeta.Catalog // nil when the tickers aren't annotated
+	warmUp                    time.Duration
+	historyTimeout            time.Duration
+	tickOffset                time.Duration
+	fundingInterval           time.Duration
+	openInterestInterval      time.Duration
+	historySince              time.Time // start of the oldest tick of the history, loaded or built
+	precision                 domain.Precision
+	usdRates                  atomic.Pointer[usd.Rates]   // rates of the latest fetch, used for liquidations between ticks
+	bars                      *barCollector               // nil when minute bars are disabled
+	latestTick                atomic.Pointer[domain.Tick] // copy of the latest published tick, never modified once stored
+	tickValidator             *domain.TickValidator
+	quality                   QualityConfig
+
+	events     *eventbus.Bus
+	supervisor *supervisor.Supervisor
+	telemetry  telemetry.Provider
+	logger     *zap.Logger
 }
 
 // Config represents the configuration for initializing the importer
 type Config struct {
-	Exchange          exchanges.Exchange
-	RepositoryFactory RepositoryFactory
-	NotifierService   NotifierService
-	Telemetry         telemetry.Provider
-	Logger            *zap.Logger
+	Exchange                  exchanges.Exchange
+	LiquidationSources        []exchanges.LiquidationSource // external providers of the liquidations of the exchange
+	RepositoryFactory         RepositoryFactory
+	EventBus                  *eventbus.Bus
+	Mode                      Mode          // pipelines to run, empty runs all of them
+	PublishOrder              PublishOrder  // when ticks are published relative to storing them, empty is PublishAfterStore
+	LiquidationsBackfill      time.Duration // on start, store the liquidations missed within this window, 0 disables the backfill
+	ArchiveRawLiquidations    bool          // store the compressed exchange frame with every liquidation
+	MaxLiquidationDeviation   float64       // flag liquidations priced further than this % from the book of the symbol, 0 disables
+	LogTickSummary            bool          // log one structured line per imported tick
+	MaxConversionFailureRatio float64       // skip ticks with a larger share of tickers failing conversion, 0 uses the default
+	Liquidity                 LiquidityFilter
+	SymbolMapper              exchanges.SymbolMapper // canonical symbols of the tickers and the imported quote assets, the zero value imports all
+	HighRes                   HighResConfig
+	TradeSymbols              []string // symbols whose aggregated trades are stored and summed into the tick volumes, empty disables
+	SymbolRegistry            SymbolRegistryConfig
+	Quality                   QualityConfig
+	Priority                  PriorityConfig
+	LiquidationStorage        LiquidationStorageConfig
+	StorageSampling           StorageSamplingConfig
+	NormalizeUSD              bool                 // convert prices quoted in other assets than USDT/USD to USD before calculating indicators
+	Bars                      bool                 // publish and store a finalized 1-minute bar per symbol
+	FundingInterval           time.Duration        // store the funding rates of the perpetual symbols this often, 0 disables
+	OpenInterestInterval      time.Duration        // poll the open interest this often where the tickers don't carry it, 0 disables
+	TickChecks                []domain.TickCheck   // cross-field checks run on every built tick after Tick.Validate
+	Precision                 *domain.Precision    // decimals of the stored indicators, nil uses domain.DefaultPrecision
+	Maintenance               maintenance.Calendar // scheduled maintenance windows of the exchange, their ticks are flagged
+	SymbolMeta                *symbolmeta.Catalog  // categories and tier the tickers are annotated with, nil disables
+	WarmUp                    time.Duration        // flag the ticks built before the history covers this long, 0 disables
+	MaxSymbols                int                  // keep the history of this many symbols, evicting the least recently updated, 0 is unlimited
+	HistoryTimeout            time.Duration        // give up loading the history on start after this long, 0 uses DefaultHistoryTimeout
+	TickOffset                time.Duration        // start the ticks this long after the second, below TickInterval
+	Telemetry                 telemetry.Provider
+	Logger                    *zap.Logger
 }
 
 // New creates a new Importer
@@ -62,25 +156,170 @@ func New(cfg *Config) *Importer {
 	if err != nil {
 		return nil
 	}
+	var subTickRepository domain.SubTickRepository
+	if len(cfg.HighRes.Symbols) > 0 {
+		subTickRepository, err = cfg.RepositoryFactory.GetSubTickRepository(cfg.Exchange.GetName())
+		if err != nil {
+			return nil
+		}
+	}
+	precision := domain.DefaultPrecision()
+	if cfg.Precision != nil {
+		precision = *cfg.Precision
+	}
+	var barRepository domain.BarRepository
+	var bars *barCollector
+	if cfg.Bars {
+		barRepository, err = cfg.RepositoryFactory.GetBarRepository(cfg.Exchange.GetName())
+		if err != nil {
+			return nil
+		}
+		bars = newBarCollector(precision.Notional)
+	}
+	var fundingRateRepository domain.FundingRateRepository
+	if cfg.FundingInterval > 0 {
+		fundingRateRepository, err = cfg.RepositoryFactory.GetFundingRateRepository(cfg.Exchange.GetName())
+		if err != nil {
+			return nil
+		}
+	}
+	var tradeRepository domain.TradeRepository
+	var volumes *tradeVolumes
+	if len(cfg.TradeSymbols) > 0 {
+		tradeRepository, err = cfg.RepositoryFactory.GetTradeRepository(cfg.Exchange.GetName())
+		if err != nil {
+			return nil
+		}
+		volumes = newTradeVolumes()
+	}
+	var symbolRepository domain.SymbolRepository
+	var symbols *symbolRegistry
+	if cfg.SymbolRegistry.Enabled {
+		symbolRepository, err = cfg.RepositoryFactory.GetSymbolRepository(cfg.Exchange.GetName())
+		if err != nil {
+			return nil
+		}
+		symbols = newSymbolRegistry(cfg.SymbolRegistry.DelistAfter)
+	}
+	events := cfg.EventBus
+	if events == nil {
+		events = eventbus.New(cfg.Logger)
+	}
+	maxConversionFailureRatio := cfg.MaxConversionFailureRatio
+	if maxConversionFailureRatio <= 0 {
+		maxConversionFailureRatio = DefaultMaxConversionFailureRatio
+	}
+	publishOrder := cfg.PublishOrder
+	if publishOrder == "" {
+		publishOrder = PublishAfterStore
+	}
+	openInterestInterval := max(cfg.OpenInterestInterval, MinOpenInterestInterval)
+	var oi *openInterest
+	if cfg.OpenInterestInterval > 0 {
+		oi = newOpenInterest(openInterestInterval)
+	}
+	historyTimeout := cfg.HistoryTimeout
+	if historyTimeout <= 0 {
+		historyTimeout = DefaultHistoryTimeout
+	}
+	var seen *seenLiquidations
+	if len(cfg.LiquidationSources) > 0 {
+		seen = newSeenLiquidations()
+	}
 	return &Importer{
 		exchange:              cfg.Exchange,
+		liquidationSources:    cfg.LiquidationSources,
 		tickRepository:        tickRepository,
 		liquidationRepository: liquidationRepository,
+		subTickRepository:     subTickRepository,
+		barRepository:         barRepository,
+		fundingRateRepository: fundingRateRepository,
+		tradeRepository:       tradeRepository,
+		symbolRepository:      symbolRepository,
+
+		tickHistory:        newTickHistory(domain.MaxTickHistory),
+		tickerHistory:      newTickerHistoryMap(),
+		latency:            newLatencyTracker(),
+		lastLiquidations:   newLastLiquidations(),
+		recentLiquidations: newRecentLiquidations(recentLiquidationsSize),
+		books:              newLatestBooks(),
+		seenLiquidations:   seen,
+		watermark:          newLiquidationWatermark(),
+		streamRates:        newStreamRates(),
+		rollingStats:       newRollingStats(precision),
+		openInterest:       oi,
+		tradeVolumes:       volumes,
+		symbols:            symbols,
+		liquidationStorage: newLiquidationStorage(cfg.LiquidationStorage),
 
-		tickHistory:   newTickHistory(domain.MaxTickHistory),
-		tickerHistory: newTickerHistoryMap(),
+		mode:                      cfg.Mode,
+		publishOrder:              publishOrder,
+		liquidationsBackfill:      cfg.LiquidationsBackfill,
+		archiveRawLiquidations:    cfg.ArchiveRawLiquidations,
+		maxLiquidationDeviation:   cfg.MaxLiquidationDeviation,
+		logTickSummary:            cfg.LogTickSummary,
+		maxConversionFailureRatio: maxConversionFailureRatio,
+		liquidity:                 cfg.Liquidity,
+		symbolMapper:              cfg.SymbolMapper,
+		highRes:                   cfg.HighRes,
+		tradeSymbols:              cfg.TradeSymbols,
+		priority:                  newPriorityList(cfg.Priority),
+		storageSampler:            newStorageSampler(cfg.StorageSampling),
+		normalizeUSD:              cfg.NormalizeUSD,
+		maintenance:               cfg.Maintenance,
+		symbolMeta:                cfg.SymbolMeta,
+		warmUp:                    cfg.WarmUp,
+		historyTimeout:            historyTimeout,
+		tickOffset:                cfg.TickOffset,
+		fundingInterval:           max(cfg.FundingInterval, MinFundingInterval),
+		openInterestInterval:      openInterestInterval,
+		precision:                 precision,
+		maxSymbols:                cfg.MaxSymbols,
+		bars:                      bars,
+		tickValidator:             domain.NewTickValidator(cfg.TickChecks...),
+		quality:                   cfg.Quality.withDefaults(),
 
-		notifier:  cfg.NotifierService,
-		telemetry: cfg.Telemetry,
-		logger:    cfg.Logger,
+		events:     events,
+		supervisor: supervisor.New(cfg.Logger).WithTelemetry(cfg.Telemetry),
+		telemetry:  cfg.Telemetry,
+		logger:     cfg.Logger,
 	}
 }
 
-// Start starts a loop that imports data from the exchange periodically.
+// Start starts the pipelines of the configured mode and blocks until ctx is canceled.
+// It returns once the background pipelines returned too, so the repositories and notifiers can be closed after it
 func (i *Importer) Start(ctx context.Context) error {
-	if err := i.startLiquidationsImport(ctx); err != nil {
-		return fmt.Errorf("failed to start liquidations import: %w", err)
+	ctx, cancel := context.WithCancel(ctx)
+	defer func() {
+		cancel()
+		i.supervisor.Wait()
+	}()
+
+	i.supervisor.Go(ctx, "stream_rates", i.reportStreamRates)
+
+	// the registry is fed by the ticker fetches, liquidations are checked against it from the start
+	if i.mode.RunsTickers() {
+		if err := i.startSymbolRegistry(ctx); err != nil {
+			return fmt.Errorf("failed to start symbol registry: %w", err)
+		}
+	}
+
+	if i.mode.RunsLiquidations() {
+		if err := i.startLiquidationsImport(ctx); err != nil {
+			return fmt.Errorf("failed to start liquidations import: %w", err)
+		}
+	}
+
+	if !i.mode.RunsTickers() {
+		i.logger.Info("Tickers import disabled, only recording liquidations", zap.String("exchange", i.exchange.GetName()))
+		<-ctx.Done()
+		return ctx.Err()
 	}
+
+	i.startSubTicksImport(ctx)
+	i.startFundingRatesImport(ctx)
+	i.startOpenInterestImport(ctx)
+	i.startTradesImport(ctx)
 	if err := i.startTickersImport(ctx); err != nil {
 		return fmt.Errorf("failed to start tickers import: %w", err)
 	}
@@ -99,33 +338,76 @@ func (i *Importer) startLiquidationsImport(ctx context.Context) error {
 		return fmt.Errorf("failed to subscribe to liquidations")
 	}
 
-	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				i.logger.Info("Liquidation import stopped (context canceled).")
-				return
-			case liq := <-liqChan:
-				// Convert the `exchanges.Liquidation` to your domain model
-				domainLiq := i.convertLiquidationToDomain(liq)
-
-				if err := domainLiq.Validate(); err != nil {
-					i.logger.Error("Liquidation validation failed", zap.Error(err))
-					continue
-				}
-
-				// Store it
-				err := i.liquidationRepository.Create(ctx, domainLiq)
-				if err != nil {
-					i.logger.Error("Failed to store liquidation", zap.Error(err))
-				}
-			case err := <-errChan:
-				i.telemetry.IncrementCounter(telemetryLiquidationsErrors, 1, fmt.Sprintf("exchange:%s", i.exchange.GetName()))
-				i.logger.Error("Error on liquidation stream", zap.Error(err))
+	i.streamRates.track(eventbus.StageLiquidations)
+	i.supervisor.Go(ctx, "liquidation_storage", i.guardLiquidationStorage)
+	i.liquidationsFreshness.touch(time.Now())
+	i.supervisor.Go(ctx, "liquidations", func(ctx context.Context) error {
+		i.consumeLiquidations(ctx, liqChan, errChan)
+		return nil
+	})
+	i.startLiquidationSources(ctx)
+	// liquidations from now on come from the stream
+	i.startLiquidationsBackfill(ctx, time.Now())
+	return nil
+}
+
+// consumeLiquidations validates, publishes and stores liquidations until ctx is canceled
+func (i *Importer) consumeLiquidations(ctx context.Context, liqChan <-chan exchanges.Liquidation, errChan <-chan error) {
+	for {
+		select {
+		case <-ctx.Done():
+			i.logger.Info("Liquidation import stopped (context canceled).")
+			return
+		case liq := <-liqChan:
+			i.streamRates.observe(eventbus.StageLiquidations)
+			i.liquidationsFreshness.touch(time.Now())
+
+			// Convert the `exchanges.Liquidation` to your domain model
+			domainLiq := i.convertLiquidationToDomain(liq)
+
+			if err := domainLiq.Validate(); err != nil {
+				i.logger.Error("Liquidation validation failed", zap.Error(err))
+				continue
 			}
+			receivedAt := domain.Now()
+			domainLiq.ReceivedAt = receivedAt
+			if i.seenLiquidations != nil && !i.seenLiquidations.add(domainLiq, receivedAt) {
+				i.telemetry.IncrementCounter(telemetryLiquidationsDuplicates, 1, "source:"+sourceTag(liq.Source))
+				continue
+			}
+
+			// A liquidation arriving after its window was counted is counted in the next window instead
+			var late bool
+			domainLiq.WindowAt, late = i.watermark.assign(domainLiq.EventAt, receivedAt)
+			i.telemetry.Timing(telemetryLiquidationsLateness, receivedAt.Sub(domainLiq.EventAt), "source:"+sourceTag(liq.Source))
+			if late {
+				i.telemetry.IncrementCounter(telemetryLiquidationsLate, 1, "source:"+sourceTag(liq.Source))
+			}
+			i.checkLiquidationPrice(&domainLiq, receivedAt)
+			i.checkLiquidationSymbol(&domainLiq)
+			i.publishLiquidation(domainLiq)
+			i.lastLiquidations.add(domainLiq)
+			i.recentLiquidations.add(domainLiq)
+			i.rollingStats.addLiquidation(domainLiq)
+			if i.bars != nil {
+				i.bars.addLiquidation(domainLiq)
+			}
+
+			// Store it
+			i.storeLiquidation(ctx, domainLiq)
+		case err := <-errChan:
+			i.telemetry.IncrementCounter(telemetryLiquidationsErrors, 1)
+			i.publishDegraded(eventbus.StageLiquidations, err)
+			i.logger.Error("Error on liquidation stream", zap.Error(err))
 		}
-	}()
-	return nil
+	}
+}
+
+// liquidationOrderSides maps the normalized liquidation side to the side of the forced order.
+// Unknown sides map to an empty side, which fails validation
+var liquidationOrderSides = map[exchanges.LiquidationSide]domain.OrderSide{
+	exchanges.LongLiquidated:  domain.OrderSide(domain.LongLiquidation),
+	exchanges.ShortLiquidated: domain.OrderSide(domain.ShortLiquidation),
 }
 
 // convertLiquidationToDomain converts the exchange Liquidation to a domain Liquidation
@@ -134,14 +416,31 @@ func (i *Importer) convertLiquidationToDomain(liq exchanges.Liquidation) domain.
 		Order: domain.Order{
 			Symbol:     domain.TickerName(liq.Symbol),
 			EventAt:    liq.EventAt,
-			Side:       domain.OrderSide(liq.Side),
+			Side:       liquidationOrderSides[liq.Side],
 			Price:      liq.Price,
 			Quantity:   liq.Quantity,
 			TotalPrice: liq.TotalPrice,
+			Quote:      liq.Quote,
+			USDValue:   liquidationUSDValue(i.usdRates.Load(), liq, i.precision.Notional),
 		},
 		EventAt:  liq.EventAt,
-		StoredAt: time.Now(),
+		StoredAt: domain.Now(),
+		Raw:      i.archiveRaw(liq),
+		Source:   liq.Source,
+	}
+}
+
+// archiveRaw returns the compressed frame of the liquidation if raw liquidations are archived
+func (i *Importer) archiveRaw(liq exchanges.Liquidation) []byte {
+	if !i.archiveRawLiquidations || len(liq.Raw) == 0 {
+		return nil
+	}
+	raw, err := domain.CompressRaw(liq.Raw)
+	if err != nil {
+		i.logger.Warn("Failed to archive raw liquidation", zap.String("symbol", liq.Symbol), zap.Error(err))
+		return nil
 	}
+	return raw
 }
 
 // StartTickersImport starts a loop that imports data from the exchange periodically.
@@ -151,16 +450,21 @@ func (i *Importer) startTickersImport(ctx context.Context) error {
 		return fmt.Errorf("failed to init history: %w", err)
 	}
 
-	// Import should be started exactly at the beginning of the next second
-	now := time.Now()
-	nextSecond := now.Truncate(time.Second).Add(time.Second)
-	time.Sleep(time.Until(nextSecond))
+	i.logger.Info(i.generateImporterInfo())
+
+	// A panic while importing a tick restarts the loop instead of killing the process
+	return i.supervisor.Run(ctx, "tickers", i.runTickersLoop)
+}
+
+// runTickersLoop imports a tick every TickInterval until ctx is canceled
+func (i *Importer) runTickersLoop(ctx context.Context) error {
+	// Import should be started exactly at the beginning of the next second, shifted by the tick offset
+	time.Sleep(time.Until(nextTickStart(time.Now(), i.tickOffset)))
 
 	// Start the import loop with the specified interval
-	timeTicker := time.NewTicker(defaultTickInterval)
+	timeTicker := time.NewTicker(TickInterval)
 	defer timeTicker.Stop()
 
-	i.logger.Info(i.generateImporterInfo())
 	for {
 		select {
 		case <-ctx.Done():
@@ -175,13 +479,22 @@ func (i *Importer) startTickersImport(ctx context.Context) error {
 	}
 }
 
+// nextTickStart returns the first tick start after now: the beginning of a second shifted by offset
+func nextTickStart(now time.Time, offset time.Duration) time.Time {
+	start := now.Truncate(time.Second).Add(offset)
+	if !start.After(now) {
+		start = start.Add(time.Second)
+	}
+	return start
+}
+
 // GetInfo returns a string with the current state of the Importer
 func (i *Importer) generateImporterInfo() string {
 	var info string
 	info += "\n________________________________________________________________________________\n"
 	info += fmt.Sprintf("exchange: %s\n", i.exchange.GetName())
 	info += fmt.Sprintf("Tick history length: %d\n", i.tickHistory.Len())
-	info += fmt.Sprintf("Ticker history length: %d\n", len(i.tickerHistory.data))
+	info += fmt.Sprintf("Ticker history length: %d\n", i.tickerHistory.Len())
 
 	info += "________________________________________________________________________________\n"
 
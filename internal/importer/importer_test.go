@@ -1,24 +1,36 @@
 package importer
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
 	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
 	importerMocks "github.com/ayankousky/exchange-data-importer/internal/importer/mocks"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
 	exchangeMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/mocks"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
 	notifyMock "github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify/mocks"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/maintenance"
 	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/supervisor"
+	"github.com/ayankousky/exchange-data-importer/internal/symbolmeta"
+	"github.com/ayankousky/exchange-data-importer/internal/usd"
 	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type testSuite struct {
@@ -26,6 +38,8 @@ type testSuite struct {
 	repoFactory *importerMocks.RepositoryFactoryMock
 	tickRepo    *domainMocks.TickRepositoryMock
 	liqRepo     *domainMocks.LiquidationRepositoryMock
+	events      *eventbus.Bus
+	notifier    *notifier.Notifier
 	importer    *Importer
 }
 
@@ -76,10 +90,16 @@ func setupTest() *testSuite {
 
 	telemetryProvider := &telemetry.NoopProvider{}
 
+	events := eventbus.New(zap.NewNop())
+	tickNotifier := notifier.New(zap.NewNop())
+	events.Subscribe("notifier", func(ctx context.Context, event eventbus.Event) {
+		tickNotifier.Notify(ctx, event.Payload)
+	}, eventbus.TickBuilt)
+
 	cfg := &Config{
 		Exchange:          exchange,
 		RepositoryFactory: repoFactory,
-		NotifierService:   notifier.New(zap.NewNop()),
+		EventBus:          events,
 		Telemetry:         telemetryProvider,
 		Logger:            zap.NewNop(),
 	}
@@ -89,6 +109,8 @@ func setupTest() *testSuite {
 		repoFactory: repoFactory,
 		tickRepo:    tickRepo,
 		liqRepo:     liqRepo,
+		events:      events,
+		notifier:    tickNotifier,
 		importer:    New(cfg),
 	}
 }
@@ -105,6 +127,470 @@ func TestStartImport(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestStartModes(t *testing.T) {
+	tests := []struct {
+		name             string
+		mode             Mode
+		wantTickers      bool
+		wantLiquidations bool
+	}{
+		{name: "empty mode runs everything", wantTickers: true, wantLiquidations: true},
+		{name: "full", mode: ModeFull, wantTickers: true, wantLiquidations: true},
+		{name: "tickers only", mode: ModeTickers, wantTickers: true},
+		{name: "liquidations only", mode: ModeLiquidations, wantLiquidations: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupTest()
+			ts.importer.mode = tt.mode
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				done <- ts.importer.Start(ctx)
+			}()
+
+			// the pipelines are started before the tickers loop waits for the next second
+			time.Sleep(50 * time.Millisecond)
+			cancel()
+			select {
+			case err := <-done:
+				assert.ErrorIs(t, err, context.Canceled)
+			case <-time.After(3 * time.Second):
+				t.Fatal("Start didn't return after the context was canceled")
+			}
+
+			assert.Equal(t, tt.wantTickers, len(ts.tickRepo.GetHistorySinceCalls()) > 0, "tick history loaded")
+			assert.Equal(t, tt.wantLiquidations, len(ts.exchange.SubscribeLiquidationsCalls()) > 0, "liquidations subscribed")
+		})
+	}
+}
+
+func TestImportTickTagsSymbolCount(t *testing.T) {
+	ts := setupTest()
+	tagged := telemetry.NewTaggedProvider(&telemetry.NoopProvider{}, map[string]string{telemetry.TagExchange: "binance"})
+	ts.importer.telemetry = tagged
+
+	_, _, err := ts.importer.fetchTickers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"exchange:binance", "symbols:lt_100"}, tagged.Tags())
+}
+
+func TestImportTickPublishesEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(ts *testSuite)
+		wantEvent eventbus.EventType
+		wantStage string
+	}{
+		{
+			name:      "should publish built tick",
+			setup:     func(ts *testSuite) {},
+			wantEvent: eventbus.TickBuilt,
+		},
+		{
+			name: "should publish degradation when fetching fails",
+			setup: func(ts *testSuite) {
+				ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+					return nil, fmt.Errorf("exchange unavailable")
+				}
+			},
+			wantEvent: eventbus.ImportDegraded,
+			wantStage: eventbus.StageFetchTickers,
+		},
+		{
+			name: "should publish degradation when storing fails",
+			setup: func(ts *testSuite) {
+				ts.tickRepo.CreateFunc = func(ctx context.Context, tick domain.Tick) error {
+					return fmt.Errorf("database error")
+				}
+			},
+			wantEvent: eventbus.ImportDegraded,
+			wantStage: eventbus.StageStoreTick,
+		},
+		{
+			name: "should publish degradation and keep the tick when few tickers fail conversion",
+			setup: func(ts *testSuite) {
+				fetch := ts.exchange.FetchTickersFunc
+				ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+					tickers, _ := fetch(ctx)
+					return tickers, exchanges.NewConversionError(20, []error{fmt.Errorf("invalid bidPrice")})
+				}
+			},
+			wantEvent: eventbus.ImportDegraded,
+			wantStage: eventbus.StageConvertTickers,
+		},
+		{
+			name: "should skip the tick when too many tickers fail conversion",
+			setup: func(ts *testSuite) {
+				fetch := ts.exchange.FetchTickersFunc
+				ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+					tickers, _ := fetch(ctx)
+					return tickers, exchanges.NewConversionError(4, []error{fmt.Errorf("a"), fmt.Errorf("b")})
+				}
+			},
+			wantEvent: eventbus.ImportDegraded,
+			wantStage: eventbus.StageFetchTickers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupTest()
+			tt.setup(ts)
+
+			var mu sync.Mutex
+			var received []eventbus.Event
+			ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				received = append(received, event)
+			}, tt.wantEvent)
+
+			_ = ts.importer.importTick(context.Background())
+			ts.events.Flush()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !assert.Len(t, received, 1) {
+				return
+			}
+			if tt.wantStage != "" {
+				degradation, ok := received[0].Payload.(eventbus.Degradation)
+				assert.True(t, ok)
+				assert.Equal(t, tt.wantStage, degradation.Stage)
+				assert.Error(t, degradation.Err)
+			}
+		})
+	}
+}
+
+func TestLiquidationsImportSurvivesPanic(t *testing.T) {
+	ts := setupTest()
+	ts.importer.supervisor = supervisor.New(zap.NewNop()).WithBackoff(time.Millisecond, time.Millisecond)
+
+	liqChan := make(chan exchanges.Liquidation)
+	ts.exchange.SubscribeLiquidationsFunc = func(ctx context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+		return liqChan, make(chan error)
+	}
+
+	var mu sync.Mutex
+	var stored []domain.Liquidation
+	ts.liqRepo.CreateFunc = func(ctx context.Context, l domain.Liquidation) error {
+		if l.Order.Symbol == "PANICUSDT" {
+			panic("repository exploded")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		stored = append(stored, l)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, ts.importer.startLiquidationsImport(ctx))
+
+	now := time.Now()
+	liqChan <- exchanges.Liquidation{Symbol: "PANICUSDT", Side: exchanges.LongLiquidated, Price: 1, Quantity: 1, TotalPrice: 1, EventAt: now}
+	liqChan <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 1, Quantity: 1, TotalPrice: 1, EventAt: now}
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(stored) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestImportTickRetriesStoring(t *testing.T) {
+	ts := setupTest()
+	failures := 1
+	ts.tickRepo.CreateFunc = func(ctx context.Context, tick domain.Tick) error {
+		if failures > 0 {
+			failures--
+			return fmt.Errorf("connection reset")
+		}
+		return nil
+	}
+
+	var mu sync.Mutex
+	var degraded []eventbus.Event
+	ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		degraded = append(degraded, event)
+	}, eventbus.ImportDegraded)
+
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	ts.events.Flush()
+
+	calls := ts.tickRepo.CreateCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, calls[0].Ts.StorageKey(), calls[1].Ts.StorageKey(), "the retry stores the same tick")
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Empty(t, degraded)
+
+	ts = setupTest()
+	ts.tickRepo.CreateFunc = func(ctx context.Context, tick domain.Tick) error {
+		return fmt.Errorf("database error")
+	}
+	assert.Error(t, ts.importer.importTick(context.Background()))
+	assert.Len(t, ts.tickRepo.CreateCalls(), storeTickAttempts)
+}
+
+func TestImportTickLatestSnapshot(t *testing.T) {
+	ts := setupTest()
+	_, ok := ts.importer.LatestTick()
+	assert.False(t, ok)
+
+	ts.tickRepo.CreateFunc = func(ctx context.Context, tick domain.Tick) error {
+		return fmt.Errorf("database error")
+	}
+	assert.Error(t, ts.importer.importTick(context.Background()))
+	_, ok = ts.importer.LatestTick()
+	assert.False(t, ok, "a tick failing to store is not published")
+
+	ts = setupTest()
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if tick, ok := ts.importer.LatestTick(); ok {
+					for _, ticker := range tick.Data {
+						_ = ticker.Ask
+					}
+				}
+			}
+		}()
+	}
+	for range 3 {
+		require.NoError(t, ts.importer.importTick(context.Background()))
+	}
+	close(done)
+	readers.Wait()
+
+	latest, ok := ts.importer.LatestTick()
+	require.True(t, ok)
+	last, _ := ts.importer.getLastTick()
+	assert.Equal(t, last, latest)
+	assert.NotSame(t, last, latest, "the snapshot is a copy of the tick kept in the history")
+	for symbol, ticker := range last.Data {
+		assert.NotSame(t, ticker, latest.Data[symbol])
+	}
+}
+
+func TestImportTickChecks(t *testing.T) {
+	tickers := func(symbols ...string) func(ctx context.Context) ([]exchanges.Ticker, error) {
+		return func(ctx context.Context) ([]exchanges.Ticker, error) {
+			result := make([]exchanges.Ticker, 0, len(symbols))
+			for _, symbol := range symbols {
+				result = append(result, exchanges.Ticker{Symbol: symbol, AskPrice: 100, BidPrice: 99, EventAt: time.Now()})
+			}
+			return result, nil
+		}
+	}
+
+	ts := setupTest()
+	ts.importer.tickValidator.Register(domain.TickersCountCheck(0.4, domain.SeveritySuspect))
+	ts.exchange.FetchTickersFunc = tickers("BTCUSDT", "ETHUSDT")
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	ts.exchange.FetchTickersFunc = tickers("BTCUSDT")
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	calls := ts.tickRepo.CreateCalls()
+	require.Len(t, calls, 2)
+	assert.Empty(t, calls[0].Ts.Suspect)
+	assert.Equal(t, []string{"tickers_count: 1 tickers, 2 in the previous tick"}, calls[1].Ts.Suspect,
+		"the tick is compared to the previous one, not to itself")
+
+	ts.importer.tickValidator.Register(domain.TickersCountCheck(0.4, domain.SeverityReject))
+	ts.exchange.FetchTickersFunc = tickers("BTCUSDT", "ETHUSDT", "SOLUSDT", "XRPUSDT")
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	ts.exchange.FetchTickersFunc = tickers("BTCUSDT")
+	assert.ErrorContains(t, ts.importer.importTick(context.Background()), "tickers_count")
+	assert.Len(t, ts.tickRepo.CreateCalls(), 3, "a rejected tick is not stored")
+}
+
+func TestImportTickMaintenance(t *testing.T) {
+	ts := setupTest()
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	windows, err := maintenance.Parse([]string{"* 00:00-23:59", "* 23:59-00:00"})
+	require.NoError(t, err)
+	ts.importer.maintenance = windows
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	calls := ts.tickRepo.CreateCalls()
+	require.Len(t, calls, 2)
+	assert.False(t, calls[0].Ts.Maintenance)
+	assert.True(t, calls[1].Ts.Maintenance)
+}
+
+func TestImportTickWarmingUp(t *testing.T) {
+	ts := setupTest()
+	ts.importer.warmUp = time.Hour
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	// a history loaded on start counts towards the warm-up
+	ts.importer.historySince = time.Now().Add(-time.Hour)
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	calls := ts.tickRepo.CreateCalls()
+	require.Len(t, calls, 3)
+	assert.True(t, calls[0].Ts.WarmingUp)
+	assert.True(t, calls[1].Ts.WarmingUp)
+	assert.False(t, calls[2].Ts.WarmingUp)
+}
+
+func TestImportTickAnnotatesErrors(t *testing.T) {
+	ts := setupTest()
+	ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+		tickers := []exchanges.Ticker{{Symbol: "NOTIMEUSDT", AskPrice: 101, BidPrice: 100}}
+		for n := range 20 {
+			tickers = append(tickers, exchanges.Ticker{Symbol: fmt.Sprintf("S%dUSDT", n), AskPrice: 101, BidPrice: 100, EventAt: time.Now()})
+		}
+		return tickers, exchanges.NewConversionError(22, []error{errors.New("XUSDT: invalid ask")})
+	}
+	ts.liqRepo.GetLiquidationsHistoryFunc = func(ctx context.Context, timeAt time.Time) (domain.LiquidationsHistory, error) {
+		return domain.LiquidationsHistory{}, errors.New("connection refused")
+	}
+	require.NoError(t, ts.importer.importTick(context.Background()))
+
+	calls := ts.tickRepo.CreateCalls()
+	require.Len(t, calls, 1, "the tick is stored with its errors")
+	assert.Equal(t, []domain.TickError{
+		{Stage: domain.TickStageConvertTickers, Error: "failed to convert 1 of 22 tickers: XUSDT: invalid ask"},
+		{Stage: domain.TickStageLiquidations, Fields: []string{"ll_1", "ll_2", "ll_5", "ll_60", "sl_1", "sl_2", "sl_10"}, Error: "connection refused"},
+		{Stage: domain.TickStageBuildTickers, Error: "invalid ticker data: validation failed for field EventAt: event time cannot be zero"},
+	}, calls[0].Ts.Errors)
+	assert.Len(t, calls[0].Ts.Data, 20)
+}
+
+func TestStageFailures(t *testing.T) {
+	var failures stageFailures
+	assert.NoError(t, failures.err())
+
+	first := errors.New("BTCUSDT: invalid price")
+	failures.add(first)
+	assert.Equal(t, first, failures.err())
+
+	failures.add(errors.New("ETHUSDT: invalid price"))
+	assert.EqualError(t, failures.err(), "2 tickers failed, first: BTCUSDT: invalid price")
+	assert.ErrorIs(t, failures.err(), first)
+}
+
+func TestNextTickStart(t *testing.T) {
+	second := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		now    time.Time
+		offset time.Duration
+		want   time.Time
+	}{
+		{"next second", second.Add(100 * time.Millisecond), 0, second.Add(time.Second)},
+		{"at the start of a second", second, 0, second.Add(time.Second)},
+		{"offset later in the same second", second.Add(100 * time.Millisecond), 300 * time.Millisecond, second.Add(300 * time.Millisecond)},
+		{"offset passed", second.Add(500 * time.Millisecond), 300 * time.Millisecond, second.Add(1300 * time.Millisecond)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextTickStart(tt.now, tt.offset))
+		})
+	}
+}
+
+func TestImportTickPublishOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		order         PublishOrder
+		storeErr      error
+		wantPublished bool
+	}{
+		{name: "store first publishes stored ticks", wantPublished: true},
+		{name: "store first drops ticks failing to persist", storeErr: fmt.Errorf("database error")},
+		{name: "notify first publishes stored ticks", order: PublishBeforeStore, wantPublished: true},
+		{name: "notify first publishes ticks failing to persist", order: PublishBeforeStore, storeErr: fmt.Errorf("database error"), wantPublished: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupTest()
+			if tt.order != "" {
+				ts.importer.publishOrder = tt.order
+			}
+			ts.tickRepo.CreateFunc = func(ctx context.Context, tick domain.Tick) error {
+				return tt.storeErr
+			}
+
+			var mu sync.Mutex
+			var published, storedBefore []bool
+			ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				published = append(published, true)
+				storedBefore = append(storedBefore, len(ts.tickRepo.CreateCalls()) > 0)
+			}, eventbus.TickBuilt)
+
+			err := ts.importer.importTick(context.Background())
+			assert.Equal(t, tt.storeErr != nil, err != nil)
+			ts.events.Flush()
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.wantPublished, len(published) == 1)
+			if tt.order == "" && tt.wantPublished {
+				assert.Equal(t, []bool{true}, storedBefore, "the tick is stored before it's published")
+			}
+		})
+	}
+}
+
+func TestImportTickLogsSummary(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			ts := setupTest()
+			var buf bytes.Buffer
+			ts.importer.logger = zap.New(zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.AddSync(&buf),
+				zapcore.InfoLevel,
+			))
+			ts.importer.logTickSummary = enabled
+
+			assert.NoError(t, ts.importer.importTick(context.Background()))
+
+			if !enabled {
+				assert.NotContains(t, buf.String(), "Tick imported")
+				return
+			}
+			var entry map[string]any
+			for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+				if bytes.Contains(line, []byte("Tick imported")) {
+					assert.NoError(t, json.Unmarshal(line, &entry))
+				}
+			}
+			assert.Equal(t, "Tick imported", entry["msg"])
+			assert.Equal(t, "mockExchange", entry["exchange"])
+			assert.Equal(t, float64(2), entry["tickers_fetched"])
+			// Mocked tickers have no EventAt, so both fail to build
+			assert.Equal(t, float64(2), entry["tickers_dropped"])
+			assert.NotContains(t, entry, "validation_failures")
+			assert.Contains(t, entry, "fetch_ms")
+			assert.Contains(t, entry, "ll_60")
+		})
+	}
+}
+
 func TestTickerHistory(t *testing.T) {
 	ts := setupTest()
 	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -260,6 +746,257 @@ func TestBuildTick(t *testing.T) {
 	}
 }
 
+func TestBuildTickLastLiquidation(t *testing.T) {
+	ts := setupTest()
+	startAt := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+
+	liqChan := make(chan exchanges.Liquidation)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ts.importer.consumeLiquidations(ctx, liqChan, make(chan error))
+	}()
+	liqChan <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 50100, Quantity: 2, TotalPrice: 100200, EventAt: startAt.Add(-3 * time.Second)}
+	liqChan <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.ShortLiquidated, Price: 49800, Quantity: 1, TotalPrice: 49800, EventAt: startAt.Add(-1500 * time.Millisecond)}
+	liqChan <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 50500, Quantity: 1, TotalPrice: 50500, EventAt: startAt.Add(-5 * time.Second)}
+	cancel()
+	<-done
+
+	tick := &domain.Tick{StartAt: startAt, Data: make(map[domain.TickerName]*domain.Ticker)}
+	ts.importer.buildTick(context.Background(), tick, []exchanges.Ticker{
+		{Symbol: "BTCUSDT", AskPrice: 50000, BidPrice: 49900, EventAt: startAt},
+		{Symbol: "ETHUSDT", AskPrice: 3000, BidPrice: 2990, EventAt: startAt},
+	})
+
+	require.Len(t, tick.Data, 2)
+	assert.Equal(t, &domain.LastLiquidation{Price: 49800, Side: domain.OrderSideBuy, Age: 1500}, tick.Data["BTCUSDT"].LastLiquidation,
+		"the latest liquidation is kept even if an older one arrives later")
+	assert.Nil(t, tick.Data["ETHUSDT"].LastLiquidation)
+
+	require.NotNil(t, tick.Data["BTCUSDT"].Stats24h)
+	assert.Equal(t, 49950.0, tick.Data["BTCUSDT"].Stats24h.High, "the 24h stats include the ticker")
+	assert.Equal(t, startAt.Truncate(domain.RollingStatsBucket), tick.Data["BTCUSDT"].Stats24h.Since, "the streamed liquidations start the stats")
+}
+
+func TestBuildTickLiquidityFilter(t *testing.T) {
+	defaultDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tickers := []exchanges.Ticker{
+		{Symbol: "BTCUSDT", AskPrice: 50000, BidPrice: 49900, AskQuantity: 1, BidQuantity: 2, EventAt: defaultDate},
+		{Symbol: "DUSTUSDT", AskPrice: 0.01, BidPrice: 0.009, AskQuantity: 100, BidQuantity: 100000, EventAt: defaultDate},
+	}
+
+	tests := []struct {
+		name         string
+		filter       LiquidityFilter
+		wantSymbols  []domain.TickerName
+		wantIlliquid []domain.TickerName
+	}{
+		{
+			name:        "disabled filter keeps everything",
+			wantSymbols: []domain.TickerName{"BTCUSDT", "DUSTUSDT"},
+		},
+		{
+			name:         "illiquid symbols are stored and marked",
+			filter:       LiquidityFilter{MinNotional: 100},
+			wantSymbols:  []domain.TickerName{"BTCUSDT", "DUSTUSDT"},
+			wantIlliquid: []domain.TickerName{"DUSTUSDT"},
+		},
+		{
+			name:        "illiquid symbols are dropped",
+			filter:      LiquidityFilter{MinNotional: 100, DropIlliquid: true},
+			wantSymbols: []domain.TickerName{"BTCUSDT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupTest()
+			ts.importer.liquidity = tt.filter
+			ts.liqRepo.GetLiquidationsHistoryFunc = func(ctx context.Context, timeAt time.Time) (domain.LiquidationsHistory, error) {
+				return domain.LiquidationsHistory{}, nil
+			}
+
+			tick := &domain.Tick{
+				StartAt: time.Now(),
+				Data:    make(map[domain.TickerName]*domain.Ticker),
+			}
+			ts.importer.buildTick(context.Background(), tick, tickers)
+
+			assert.ElementsMatch(t, tt.wantSymbols, slices.Collect(maps.Keys(tick.Data)))
+			var illiquid []domain.TickerName
+			for symbol, ticker := range tick.Data {
+				if ticker.Illiquid {
+					illiquid = append(illiquid, symbol)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantIlliquid, illiquid)
+		})
+	}
+}
+
+func TestBuildTickSymbolMapper(t *testing.T) {
+	defaultDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tickers := []exchanges.Ticker{
+		{Symbol: "BTC-USDT-SWAP", Base: "BTC", Quote: "USDT", AskPrice: 50000, BidPrice: 49900, EventAt: defaultDate},
+		{Symbol: "ETH-USDC-SWAP", Base: "ETH", Quote: "USDC", AskPrice: 3000, BidPrice: 2990, EventAt: defaultDate},
+		{Symbol: "ETH-BTC-SWAP", Base: "ETH", Quote: "BTC", AskPrice: 0.06, BidPrice: 0.059, EventAt: defaultDate},
+		{Symbol: "BTC-USD-250328", Base: "BTC", Quote: "USD", AskPrice: 51000, BidPrice: 50900, EventAt: defaultDate},
+	}
+
+	ts := setupTest()
+	ts.importer.symbolMapper = exchanges.SymbolMapper{
+		QuoteAliases: map[string]string{"USDT": "USD", "USDC": "USD"},
+		Quotes:       []string{"USD"},
+	}
+	ts.liqRepo.GetLiquidationsHistoryFunc = func(ctx context.Context, timeAt time.Time) (domain.LiquidationsHistory, error) {
+		return domain.LiquidationsHistory{}, nil
+	}
+	tick := &domain.Tick{StartAt: time.Now(), Data: make(map[domain.TickerName]*domain.Ticker)}
+	ts.importer.buildTick(context.Background(), tick, tickers)
+
+	canonical := make(map[domain.TickerName]domain.TickerName)
+	for symbol, ticker := range tick.Data {
+		canonical[symbol] = ticker.Canonical
+	}
+	assert.Equal(t, map[domain.TickerName]domain.TickerName{
+		"BTC-USDT-SWAP":  "BTCUSD",
+		"ETH-USDC-SWAP":  "ETHUSD",
+		"BTC-USD-250328": "",
+	}, canonical, "tickers quoted in BTC are dropped, dated futures have no canonical symbol")
+}
+
+func TestBuildTickNormalizeUSD(t *testing.T) {
+	defaultDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tickers := []exchanges.Ticker{
+		{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", AskPrice: 50010, BidPrice: 49990, AskQuantity: 1, BidQuantity: 1, EventAt: defaultDate},
+		{Symbol: "ETHBTC", Base: "ETH", Quote: "BTC", AskPrice: 0.051, BidPrice: 0.05, AskQuantity: 10, BidQuantity: 10, EventAt: defaultDate},
+		{Symbol: "BTCUSDC", Base: "BTC", Quote: "USDC", AskPrice: 50020, BidPrice: 50000, AskQuantity: 1, BidQuantity: 1, EventAt: defaultDate},
+		{Symbol: "XYZBNB", Base: "XYZ", Quote: "BNB", AskPrice: 2, BidPrice: 1, AskQuantity: 10, BidQuantity: 10, EventAt: defaultDate},
+	}
+
+	tests := []struct {
+		name         string
+		normalize    bool
+		wantSymbols  []domain.TickerName
+		wantETHBTC   domain.Ticker
+		wantBTCUSDC  float64
+		wantIlliquid []domain.TickerName
+	}{
+		{
+			name:         "disabled keeps the quoted prices",
+			wantSymbols:  []domain.TickerName{"BTCUSDT", "ETHBTC", "BTCUSDC", "XYZBNB"},
+			wantETHBTC:   domain.Ticker{Ask: 0.051, Bid: 0.05},
+			wantIlliquid: []domain.TickerName{"XYZBNB"},
+		},
+		{
+			name:        "enabled converts to USD and drops unknown rates",
+			normalize:   true,
+			wantSymbols: []domain.TickerName{"BTCUSDT", "ETHBTC", "BTCUSDC"},
+			wantETHBTC:  domain.Ticker{Ask: 2550, Bid: 2500, QuoteRate: 50000},
+			wantBTCUSDC: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupTest()
+			ts.importer.normalizeUSD = tt.normalize
+			ts.importer.liquidity = LiquidityFilter{MinNotional: 100}
+			ts.liqRepo.GetLiquidationsHistoryFunc = func(ctx context.Context, timeAt time.Time) (domain.LiquidationsHistory, error) {
+				return domain.LiquidationsHistory{}, nil
+			}
+
+			tick := &domain.Tick{
+				StartAt: time.Now(),
+				Data:    make(map[domain.TickerName]*domain.Ticker),
+			}
+			ts.importer.buildTick(context.Background(), tick, tickers)
+
+			assert.ElementsMatch(t, tt.wantSymbols, slices.Collect(maps.Keys(tick.Data)))
+			ethBTC := tick.Data["ETHBTC"]
+			require.NotNil(t, ethBTC)
+			assert.InDelta(t, tt.wantETHBTC.Ask, ethBTC.Ask, 1e-9)
+			assert.InDelta(t, tt.wantETHBTC.Bid, ethBTC.Bid, 1e-9)
+			assert.Equal(t, tt.wantETHBTC.QuoteRate, ethBTC.QuoteRate)
+			assert.False(t, ethBTC.Illiquid, "the liquidity filter compares USD notionals")
+			assert.Equal(t, tt.wantBTCUSDC, tick.Data["BTCUSDC"].QuoteRate)
+
+			var illiquid []domain.TickerName
+			for symbol, ticker := range tick.Data {
+				if ticker.Illiquid {
+					illiquid = append(illiquid, symbol)
+				}
+			}
+			assert.ElementsMatch(t, tt.wantIlliquid, illiquid)
+		})
+	}
+}
+
+func TestBuildTickPriority(t *testing.T) {
+	defaultDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tickers := []exchanges.Ticker{
+		{Symbol: "SOLUSDT", AskPrice: 100, BidPrice: 99, EventAt: defaultDate},
+		{Symbol: "ETHUSDT", AskPrice: 3000, BidPrice: 2990, EventAt: defaultDate},
+		{Symbol: "XRPUSDT", AskPrice: 2, BidPrice: 1.9, EventAt: defaultDate},
+		{Symbol: "BTCUSDT", AskPrice: 50000, BidPrice: 49900, EventAt: defaultDate},
+	}
+
+	tests := []struct {
+		name        string
+		priority    PriorityConfig
+		elapsed     time.Duration
+		wantSymbols []domain.TickerName
+		wantSkipped []domain.TickerName
+	}{
+		{
+			name:        "disabled never skips",
+			elapsed:     time.Minute,
+			wantSymbols: []domain.TickerName{"SOLUSDT", "ETHUSDT", "XRPUSDT", "BTCUSDT"},
+		},
+		{
+			name:        "in time builds everything",
+			priority:    PriorityConfig{Symbols: []string{"BTCUSDT", "ETHUSDT"}, Deadline: time.Minute},
+			wantSymbols: []domain.TickerName{"SOLUSDT", "ETHUSDT", "XRPUSDT", "BTCUSDT"},
+		},
+		{
+			name:        "late tick skips low-priority symbols",
+			priority:    PriorityConfig{Symbols: []string{"BTCUSDT", "ETHUSDT"}, Deadline: time.Millisecond},
+			elapsed:     time.Second,
+			wantSymbols: []domain.TickerName{"ETHUSDT", "BTCUSDT"},
+			wantSkipped: []domain.TickerName{"SOLUSDT", "XRPUSDT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupTest()
+			ts.importer.priority = newPriorityList(tt.priority)
+
+			tick := &domain.Tick{
+				StartAt: time.Now().Add(-tt.elapsed),
+				Data:    make(map[domain.TickerName]*domain.Ticker),
+			}
+			ts.importer.buildTick(context.Background(), tick, tickers)
+
+			assert.ElementsMatch(t, tt.wantSymbols, slices.Collect(maps.Keys(tick.Data)))
+			assert.Equal(t, tt.wantSkipped, tick.Skipped)
+		})
+	}
+}
+
+func TestPriorityList_Order(t *testing.T) {
+	tickers := []exchanges.Ticker{{Symbol: "SOLUSDT"}, {Symbol: "ETHUSDT"}, {Symbol: "XRPUSDT"}, {Symbol: "BTCUSDT"}}
+
+	ordered, count := newPriorityList(PriorityConfig{Symbols: []string{"BTCUSDT", "ETHUSDT"}}).order(tickers)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []exchanges.Ticker{{Symbol: "ETHUSDT"}, {Symbol: "BTCUSDT"}, {Symbol: "SOLUSDT"}, {Symbol: "XRPUSDT"}}, ordered)
+
+	ordered, count = newPriorityList(PriorityConfig{}).order(tickers)
+	assert.Equal(t, len(tickers), count, "without priority symbols nothing is skipped")
+	assert.Equal(t, tickers, ordered)
+}
+
 func TestNotifyNewTick(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -357,11 +1094,12 @@ func TestNotifyNewTick(t *testing.T) {
 					},
 				}
 
-				ts.importer.WithNotifier(n, string(notifier.MarketDataTopic), strategy)
+				ts.notifier.Subscribe(string(notifier.MarketDataTopic), n, strategy)
 			}
 
-			// Execute the notification
-			ts.importer.notifyNewTick(tt.tick)
+			// Publish the tick and wait for the notifier to handle it
+			ts.importer.publishTickBuilt(tt.tick)
+			ts.events.Flush()
 
 			// Verify the notifier
 			totalCalls := 0
@@ -435,7 +1173,7 @@ func TestBuildTickerWithInvalidData(t *testing.T) {
 				Data:    make(map[domain.TickerName]*domain.Ticker),
 			}
 
-			_, err := ts.importer.buildTicker(tick, nil, tt.ticker)
+			_, err := ts.importer.buildTicker(tick, nil, tt.ticker, nil)
 
 			if tt.wantError {
 				assert.Error(t, err)
@@ -446,6 +1184,82 @@ func TestBuildTickerWithInvalidData(t *testing.T) {
 	}
 }
 
+func TestBuildTickerSymbolMeta(t *testing.T) {
+	ts := setupTest()
+	catalog, err := symbolmeta.Parse([]byte(`{"UNI*": {"categories": ["DeFi"], "tier": "mid"}}`))
+	require.NoError(t, err)
+	ts.importer.symbolMeta = catalog
+
+	startAt := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	tick := domain.Tick{StartAt: startAt, Data: make(map[domain.TickerName]*domain.Ticker)}
+
+	ticker, err := ts.importer.buildTicker(tick, nil, exchanges.Ticker{Symbol: "UNIUSDT", AskPrice: 10, BidPrice: 9.9, EventAt: startAt}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DeFi"}, ticker.Categories)
+	assert.Equal(t, "mid", ticker.Tier)
+
+	ticker, err = ts.importer.buildTicker(tick, nil, exchanges.Ticker{Symbol: "BTCUSDT", AskPrice: 100, BidPrice: 99, EventAt: startAt}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, ticker.Categories, "no metadata for the symbol")
+	assert.Empty(t, ticker.Tier)
+}
+
+func TestInitHistoryParallelReplay(t *testing.T) {
+	ts := setupTest()
+	defaultDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	symbols := []domain.TickerName{"BTCUSDT", "ETHUSDT", "SOLUSDT", "XRPUSDT", "DOGEUSDT", "ADAUSDT", "BNBUSDT", "TRXUSDT"}
+	ts.tickRepo.GetHistorySinceFunc = func(ctx context.Context, since time.Time) ([]domain.Tick, error) {
+		ticks := make([]domain.Tick, 300)
+		for i := range ticks {
+			createdAt := defaultDate.Add(time.Duration(i) * 10 * time.Second)
+			ticks[i] = domain.Tick{StartAt: createdAt, Data: make(map[domain.TickerName]*domain.Ticker)}
+			for _, symbol := range symbols {
+				ticks[i].Data[symbol] = &domain.Ticker{Symbol: symbol, Ask: float64(i + 1), Bid: float64(i), CreatedAt: createdAt}
+			}
+		}
+		return ticks, nil
+	}
+
+	require.NoError(t, ts.importer.initHistory(context.Background()))
+
+	assert.Equal(t, len(symbols), ts.importer.tickerHistory.Len())
+	for _, symbol := range symbols {
+		history := ts.importer.tickerHistory.Get(symbol)
+		require.Equal(t, domain.MaxTickHistory, history.Len(), symbol)
+		last := history.At(history.Len() - 1)
+		assert.Equal(t, 300.0, last.Ask, "%s is replayed oldest first", symbol)
+		assert.Equal(t, 295.0, last.Min, symbol)
+	}
+}
+
+func TestInitHistoryTimeout(t *testing.T) {
+	ts := setupTest()
+	ts.importer.historyTimeout = 10 * time.Millisecond
+	ts.tickRepo.GetHistorySinceFunc = func(ctx context.Context, since time.Time) ([]domain.Tick, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	require.NoError(t, ts.importer.initHistory(context.Background()), "a slow repository doesn't fail the start")
+	assert.Zero(t, ts.importer.tickHistory.Len())
+
+	// the history is loaded once the timeout already passed, no symbol is replayed
+	ts.importer.historyTimeout = time.Nanosecond
+	ts.tickRepo.GetHistorySinceFunc = func(ctx context.Context, since time.Time) ([]domain.Tick, error) {
+		return []domain.Tick{{Data: map[domain.TickerName]*domain.Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Ask: 1, Bid: 1}}}}, nil
+	}
+	require.NoError(t, ts.importer.initHistory(context.Background()))
+	assert.Equal(t, 1, ts.importer.tickHistory.Len())
+	assert.Zero(t, ts.importer.tickerHistory.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ts.tickRepo.GetHistorySinceFunc = func(ctx context.Context, since time.Time) ([]domain.Tick, error) {
+		return nil, ctx.Err()
+	}
+	assert.ErrorIs(t, ts.importer.initHistory(ctx), context.Canceled, "a canceled start still fails")
+}
+
 func TestInitHistoryWithErrors(t *testing.T) {
 	ts := setupTest()
 	ctx := context.Background()
@@ -549,7 +1363,7 @@ func TestConvertLiquidationToDomain(t *testing.T) {
 			name: "should convert long liquidation correctly",
 			input: exchanges.Liquidation{
 				Symbol:     "BTCUSDT",
-				Side:       "SELL",
+				Side:       exchanges.LongLiquidated,
 				Price:      50000.0,
 				Quantity:   1.5,
 				TotalPrice: 75000.0,
@@ -575,7 +1389,7 @@ func TestConvertLiquidationToDomain(t *testing.T) {
 			name: "should convert short liquidation correctly",
 			input: exchanges.Liquidation{
 				Symbol:     "ETHUSDT",
-				Side:       "BUY",
+				Side:       exchanges.ShortLiquidated,
 				Price:      3000.0,
 				Quantity:   10.0,
 				TotalPrice: 30000.0,
@@ -601,7 +1415,7 @@ func TestConvertLiquidationToDomain(t *testing.T) {
 			name: "should handle zero values correctly",
 			input: exchanges.Liquidation{
 				Symbol:     "SOLUSDT",
-				Side:       "SELL",
+				Side:       exchanges.LongLiquidated,
 				Price:      0.0,
 				Quantity:   0.0,
 				TotalPrice: 0.0,
@@ -644,6 +1458,95 @@ func TestConvertLiquidationToDomain(t *testing.T) {
 	}
 }
 
+func TestConvertLiquidationUSDValue(t *testing.T) {
+	rates := usd.NewRates([]exchanges.Ticker{
+		{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", AskPrice: 50000, BidPrice: 50000},
+	})
+
+	tests := []struct {
+		name  string
+		rates *usd.Rates
+		input exchanges.Liquidation
+		want  float64
+	}{
+		{
+			name:  "usdt quoted",
+			rates: rates,
+			input: exchanges.Liquidation{Symbol: "ETHUSDT", Quote: "USDT", Price: 3000, Quantity: 2.5},
+			want:  7500,
+		},
+		{
+			name:  "btc quoted",
+			rates: rates,
+			input: exchanges.Liquidation{Symbol: "ETHBTC", Quote: "BTC", Price: 0.05, Quantity: 10},
+			want:  25000,
+		},
+		{
+			name:  "coin-margined contracts",
+			rates: rates,
+			input: exchanges.Liquidation{Symbol: "BTC-USD-SWAP", Quote: "USD", Price: 50000, Quantity: 0.04},
+			want:  2000,
+		},
+		{
+			name:  "btc quoted before the first tick",
+			input: exchanges.Liquidation{Symbol: "ETHBTC", Quote: "BTC", Price: 0.05, Quantity: 10},
+		},
+		{
+			name:  "unknown quote",
+			rates: rates,
+			input: exchanges.Liquidation{Symbol: "BTCUSDH25", Price: 50000, Quantity: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := setupTest()
+			if tt.rates != nil {
+				ts.importer.usdRates.Store(tt.rates)
+			}
+			tt.input.Side = exchanges.LongLiquidated
+
+			result := ts.importer.convertLiquidationToDomain(tt.input)
+			assert.InDelta(t, tt.want, result.Order.USDValue, 1e-9)
+		})
+	}
+}
+
+func TestConvertLiquidationArchivesRaw(t *testing.T) {
+	frame := []byte(`{"e":"forceOrder","E":1568014460893,"o":{"s":"BTCUSDT","S":"SELL","q":"0.014","p":"9910"}}`)
+	liq := exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 9910, Quantity: 0.014, Raw: frame}
+
+	ts := setupTest()
+	assert.Nil(t, ts.importer.convertLiquidationToDomain(liq).Raw, "raw frames are only archived when enabled")
+
+	ts.importer.archiveRawLiquidations = true
+	result := ts.importer.convertLiquidationToDomain(liq)
+	require.NotEmpty(t, result.Raw)
+	archived, err := result.RawFrame()
+	require.NoError(t, err)
+	assert.Equal(t, frame, archived)
+}
+
+func TestPublishLiquidationDropsRaw(t *testing.T) {
+	ts := setupTest()
+	received := make(chan domain.Liquidation, 1)
+	ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+		received <- event.Payload.(domain.Liquidation)
+	}, eventbus.LiquidationReceived)
+
+	liq := domain.Liquidation{Order: domain.Order{Symbol: "BTCUSDT"}, Raw: []byte("archived frame")}
+	ts.importer.publishLiquidation(liq)
+
+	select {
+	case published := <-received:
+		assert.Equal(t, domain.TickerName("BTCUSDT"), published.Order.Symbol)
+		assert.Nil(t, published.Raw, "subscribers such as the notifiers don't get the archived frame")
+	case <-time.After(time.Second):
+		t.Fatal("the liquidation wasn't published")
+	}
+	assert.Equal(t, []byte("archived frame"), liq.Raw, "the stored liquidation keeps it")
+}
+
 func TestConvertLiquidationToDomainValidation(t *testing.T) {
 	ts := setupTest()
 
@@ -659,7 +1562,7 @@ func TestConvertLiquidationToDomainValidation(t *testing.T) {
 			name: "valid long liquidation should pass validation",
 			input: exchanges.Liquidation{
 				Symbol:     "BTCUSDT",
-				Side:       "SELL",
+				Side:       exchanges.LongLiquidated,
 				Price:      50000.0,
 				Quantity:   1.5,
 				TotalPrice: 75000.0,
@@ -671,7 +1574,7 @@ func TestConvertLiquidationToDomainValidation(t *testing.T) {
 			name: "valid short liquidation should pass validation",
 			input: exchanges.Liquidation{
 				Symbol:     "ETHUSDT",
-				Side:       "BUY",
+				Side:       exchanges.ShortLiquidated,
 				Price:      3000.0,
 				Quantity:   10.0,
 				TotalPrice: 30000.0,
@@ -683,7 +1586,7 @@ func TestConvertLiquidationToDomainValidation(t *testing.T) {
 			name: "zero price should not pass validation",
 			input: exchanges.Liquidation{
 				Symbol:     "SOLUSDT",
-				Side:       "SELL",
+				Side:       exchanges.LongLiquidated,
 				Price:      0.0,
 				Quantity:   5.0,
 				TotalPrice: 0.0,
@@ -691,6 +1594,18 @@ func TestConvertLiquidationToDomainValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "raw order side should not pass validation",
+			input: exchanges.Liquidation{
+				Symbol:     "BTCUSDT",
+				Side:       "SELL",
+				Price:      50000.0,
+				Quantity:   1.5,
+				TotalPrice: 75000.0,
+				EventAt:    testTime,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
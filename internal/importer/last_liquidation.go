@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// lastLiquidations keeps the latest liquidation of every symbol seen by the live stream,
+// so a tick can tell which tickers were just liquidated without querying the repository
+type lastLiquidations struct {
+	mu       sync.RWMutex
+	bySymbol map[domain.TickerName]domain.Liquidation
+}
+
+func newLastLiquidations() *lastLiquidations {
+	return &lastLiquidations{bySymbol: make(map[domain.TickerName]domain.Liquidation)}
+}
+
+// add records the liquidation unless a later one of the same symbol was already seen
+func (l *lastLiquidations) add(liq domain.Liquidation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.bySymbol[liq.Order.Symbol]; ok && last.EventAt.After(liq.EventAt) {
+		return
+	}
+	l.bySymbol[liq.Order.Symbol] = liq
+}
+
+// at returns the latest liquidation of the symbol as seen by a tick started at startAt, nil if none was seen
+func (l *lastLiquidations) at(symbol domain.TickerName, startAt time.Time) *domain.LastLiquidation {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	liq, ok := l.bySymbol[symbol]
+	if !ok {
+		return nil
+	}
+	return domain.NewLastLiquidation(liq, startAt)
+}
+
+// evict drops the latest liquidations of the symbols
+func (l *lastLiquidations) evict(symbols []domain.TickerName) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(l.bySymbol, symbol)
+	}
+}
+
+// len returns the number of symbols with a latest liquidation
+func (l *lastLiquidations) len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.bySymbol)
+}
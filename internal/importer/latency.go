@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// latencySmoothing is the weight of the newest sample in SymbolLatency.Avg
+const latencySmoothing = 0.1
+
+// latencyBuckets are the upper bounds of the buildTicker latency histogram, the last bucket is unbounded
+var latencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+}
+
+// SymbolLatency summarizes how long building a ticker takes for a symbol
+type SymbolLatency struct {
+	Symbol domain.TickerName `json:"symbol"`
+	Count  int64             `json:"count"`
+	Last   time.Duration     `json:"last"`
+	Avg    time.Duration     `json:"avg"` // exponentially weighted, recent samples dominate
+	Max    time.Duration     `json:"max"`
+}
+
+// LatencyBucket is a single histogram bucket. UpperBound is zero for the unbounded bucket
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upper_bound"`
+	Count      int64         `json:"count"`
+}
+
+// symbolDuration is a single buildTicker measurement
+type symbolDuration struct {
+	symbol   domain.TickerName
+	duration time.Duration
+}
+
+// latencyTracker aggregates per-symbol buildTicker latency.
+// Workers collect measurements locally and merge them once per tick, so the lock is not contended.
+type latencyTracker struct {
+	mu      sync.RWMutex
+	symbols map[domain.TickerName]*SymbolLatency
+	buckets []int64
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{
+		symbols: make(map[domain.TickerName]*SymbolLatency),
+		buckets: make([]int64, len(latencyBuckets)+1),
+	}
+}
+
+// record merges a batch of measurements
+func (lt *latencyTracker) record(samples []symbolDuration) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	for _, s := range samples {
+		stats, ok := lt.symbols[s.symbol]
+		if !ok {
+			stats = &SymbolLatency{Symbol: s.symbol, Avg: s.duration}
+			lt.symbols[s.symbol] = stats
+		}
+		stats.Count++
+		stats.Last = s.duration
+		stats.Max = max(stats.Max, s.duration)
+		stats.Avg = time.Duration(latencySmoothing*float64(s.duration) + (1-latencySmoothing)*float64(stats.Avg))
+
+		bucket, _ := slices.BinarySearch(latencyBuckets, s.duration)
+		lt.buckets[bucket]++
+	}
+}
+
+// evict drops the latencies of the symbols
+func (lt *latencyTracker) evict(symbols []domain.TickerName) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(lt.symbols, symbol)
+	}
+}
+
+// len returns the number of symbols with a latency
+func (lt *latencyTracker) len() int {
+	lt.mu.RLock()
+	defer lt.mu.RUnlock()
+	return len(lt.symbols)
+}
+
+// slowest returns up to k symbols with the highest average latency
+func (lt *latencyTracker) slowest(k int) []SymbolLatency {
+	lt.mu.RLock()
+	all := make([]SymbolLatency, 0, len(lt.symbols))
+	for _, stats := range lt.symbols {
+		all = append(all, *stats)
+	}
+	lt.mu.RUnlock()
+
+	slices.SortFunc(all, func(a, b SymbolLatency) int {
+		if c := cmp.Compare(b.Avg, a.Avg); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Symbol, b.Symbol)
+	})
+	return all[:min(k, len(all))]
+}
+
+// histogram returns the latency distribution of all measurements so far
+func (lt *latencyTracker) histogram() []LatencyBucket {
+	lt.mu.RLock()
+	defer lt.mu.RUnlock()
+
+	out := make([]LatencyBucket, len(lt.buckets))
+	for i, count := range lt.buckets {
+		if i < len(latencyBuckets) {
+			out[i].UpperBound = latencyBuckets[i]
+		}
+		out[i].Count = count
+	}
+	return out
+}
+
+// SlowestSymbols returns up to k symbols whose tickers take the longest to build
+func (i *Importer) SlowestSymbols(k int) []SymbolLatency {
+	return i.latency.slowest(k)
+}
+
+// SymbolLatencyHistogram returns the distribution of per-symbol ticker build latency
+func (i *Importer) SymbolLatencyHistogram() []LatencyBucket {
+	return i.latency.histogram()
+}
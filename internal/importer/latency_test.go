@@ -0,0 +1,89 @@
+package importer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	lt := newLatencyTracker()
+	lt.record([]symbolDuration{
+		{symbol: "BTCUSDT", duration: 20 * time.Microsecond},
+		{symbol: "ETHUSDT", duration: 2 * time.Millisecond},
+		{symbol: "SOLUSDT", duration: 5 * time.Microsecond},
+	})
+	lt.record([]symbolDuration{
+		{symbol: "BTCUSDT", duration: 40 * time.Microsecond},
+		{symbol: "ETHUSDT", duration: 20 * time.Millisecond},
+	})
+
+	slowest := lt.slowest(2)
+	assert.Len(t, slowest, 2)
+	assert.Equal(t, domain.TickerName("ETHUSDT"), slowest[0].Symbol)
+	assert.Equal(t, int64(2), slowest[0].Count)
+	assert.Equal(t, 20*time.Millisecond, slowest[0].Last)
+	assert.Equal(t, 20*time.Millisecond, slowest[0].Max)
+	assert.Equal(t, 3800*time.Microsecond, slowest[0].Avg)
+	assert.Equal(t, domain.TickerName("BTCUSDT"), slowest[1].Symbol)
+	assert.Len(t, lt.slowest(10), 3)
+
+	histogram := lt.histogram()
+	assert.Len(t, histogram, len(latencyBuckets)+1)
+	counts := map[time.Duration]int64{}
+	for _, b := range histogram {
+		counts[b.UpperBound] = b.Count
+	}
+	assert.Equal(t, int64(1), counts[10*time.Microsecond])
+	assert.Equal(t, int64(2), counts[50*time.Microsecond])
+	assert.Equal(t, int64(1), counts[5*time.Millisecond])
+	assert.Equal(t, int64(1), counts[0], "values above the last bound go to the unbounded bucket")
+}
+
+func TestImportTickRecordsSymbolLatency(t *testing.T) {
+	ts := setupTest()
+
+	assert.NoError(t, ts.importer.importTick(context.Background()))
+
+	slowest := ts.importer.SlowestSymbols(10)
+	assert.Len(t, slowest, 2)
+	for _, s := range slowest {
+		assert.Equal(t, int64(1), s.Count)
+	}
+}
+
+// timingRecorder records the timings reported to it
+type timingRecorder struct {
+	telemetry.NoopProvider
+	mu      sync.Mutex
+	timings map[string][]time.Duration
+	tags    []string
+}
+
+func (r *timingRecorder) Timing(name string, value time.Duration, tags ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings[name] = append(r.timings[name], value)
+	r.tags = append(r.tags, tags...)
+}
+
+func TestRecordTickerLatencies(t *testing.T) {
+	ts := setupTest()
+	recorder := &timingRecorder{timings: map[string][]time.Duration{}}
+	ts.importer.telemetry = recorder
+
+	ts.importer.recordTickerLatencies([][]symbolDuration{
+		{{symbol: "BTCUSDT", duration: 20 * time.Microsecond}, {symbol: "ETHUSDT", duration: 2 * time.Millisecond}},
+		{{symbol: "SOLUSDT", duration: 5 * time.Microsecond}},
+	})
+
+	assert.Equal(t, []time.Duration{20 * time.Microsecond, 2 * time.Millisecond, 5 * time.Microsecond}, recorder.timings[telemetryTickerBuildLatency])
+	assert.Equal(t, []time.Duration{2 * time.Millisecond}, recorder.timings[telemetryTickerBuildSlowest])
+	assert.Empty(t, recorder.tags, "the symbols are left out of the metrics")
+	assert.Len(t, ts.importer.SlowestSymbols(10), 3)
+}
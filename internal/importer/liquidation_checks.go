@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"go.uber.org/zap"
+)
+
+// liquidationBookMaxAge is the age past which the book of a symbol is too old to check liquidation prices against,
+// e.g. while the ticker fetches fail
+const liquidationBookMaxAge = 5 * time.Second
+
+// book is the top of the book of a symbol as quoted by the exchange, before any USD normalization
+type book struct {
+	bid, ask float64
+	at       time.Time
+}
+
+// latestBooks keeps the latest top of the book of every symbol from the ticker fetches,
+// so streamed liquidations can be checked against it between ticks
+type latestBooks struct {
+	mu       sync.RWMutex
+	bySymbol map[domain.TickerName]book
+}
+
+func newLatestBooks() *latestBooks {
+	return &latestBooks{bySymbol: make(map[domain.TickerName]book)}
+}
+
+// update records the top of the book of the symbol fetched at the given time
+func (b *latestBooks) update(symbol domain.TickerName, bid, ask float64, at time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bySymbol[symbol] = book{bid: bid, ask: ask, at: at}
+}
+
+// get returns the top of the book of the symbol, false when none was fetched within liquidationBookMaxAge before now
+func (b *latestBooks) get(symbol domain.TickerName, now time.Time) (book, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	latest, ok := b.bySymbol[symbol]
+	if !ok || now.Sub(latest.at) > liquidationBookMaxAge {
+		return book{}, false
+	}
+	return latest, true
+}
+
+// evict drops the books of the symbols
+func (b *latestBooks) evict(symbols []domain.TickerName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(b.bySymbol, symbol)
+	}
+}
+
+// len returns the number of symbols with a book
+func (b *latestBooks) len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.bySymbol)
+}
+
+// checkLiquidationPrice flags a liquidation priced too far from the latest book of its symbol as suspect,
+// it's stored anyway since a flash event looks the same as bad data
+func (i *Importer) checkLiquidationPrice(liq *domain.Liquidation, now time.Time) {
+	if i.maxLiquidationDeviation <= 0 {
+		return
+	}
+	latest, ok := i.books.get(liq.Order.Symbol, now)
+	if !ok {
+		return
+	}
+	if err := liq.CheckPrice(latest.bid, latest.ask, i.maxLiquidationDeviation); err != nil {
+		liq.Suspect = append(liq.Suspect, domain.LiquidationCheckPrice+": "+err.Error())
+		i.telemetry.IncrementCounter(telemetryLiquidationsSuspect, 1, "check:"+domain.LiquidationCheckPrice)
+		i.logger.Warn("Suspect liquidation",
+			zap.String("symbol", string(liq.Order.Symbol)),
+			zap.String("source", sourceTag(liq.Source)),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,50 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestBooks_Get(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	books := newLatestBooks()
+	books.update("BTCUSDT", 49900, 50000, now)
+
+	latest, ok := books.get("BTCUSDT", now.Add(time.Second))
+	require.True(t, ok)
+	assert.Equal(t, book{bid: 49900, ask: 50000, at: now}, latest)
+
+	_, ok = books.get("BTCUSDT", now.Add(liquidationBookMaxAge+time.Second))
+	assert.False(t, ok, "stale book")
+	_, ok = books.get("ETHUSDT", now)
+	assert.False(t, ok, "unknown symbol")
+}
+
+func TestCheckLiquidationPrice(t *testing.T) {
+	ts := setupTest()
+	ts.importer.maxLiquidationDeviation = 10
+	liquidations := make(chan exchanges.Liquidation)
+	ts.exchange.SubscribeLiquidationsFunc = func(context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+		return liquidations, make(chan error)
+	}
+	ts.importer.books.update("BTCUSDT", 49900, 50000, time.Now())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, ts.importer.startLiquidationsImport(ctx))
+
+	liquidations <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 49000, Quantity: 1, TotalPrice: 49000, EventAt: time.Now()}
+	liquidations <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 4900, Quantity: 1, TotalPrice: 4900, EventAt: time.Now()}
+	liquidations <- exchanges.Liquidation{Symbol: "ETHUSDT", Side: exchanges.ShortLiquidated, Price: 1, Quantity: 1, TotalPrice: 1, EventAt: time.Now()}
+	require.Eventually(t, func() bool { return len(ts.liqRepo.CreateCalls()) == 3 }, time.Second, time.Millisecond)
+
+	calls := ts.liqRepo.CreateCalls()
+	assert.Empty(t, calls[0].L.Suspect)
+	assert.Equal(t, []string{"price: sell at 4900 is -90.18% away from the bid 49900 (max 10%)"}, calls[1].L.Suspect)
+	assert.Empty(t, calls[2].L.Suspect, "no book to check against")
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"go.uber.org/zap"
+)
+
+// liquidationSourceRetention is how long a liquidation is kept to drop the copies reported later by another source,
+// it covers the polling interval and the delay of the aggregators
+const liquidationSourceRetention = time.Minute
+
+// liquidationSourceTolerance is the largest relative difference between the notionals of the same liquidation
+// reported by two sources, aggregators may derive the quantity from a rounded notional
+const liquidationSourceTolerance = 0.01
+
+// startLiquidationSources streams the liquidations of the external sources along with the ones of the exchange
+func (i *Importer) startLiquidationSources(ctx context.Context) {
+	for _, source := range i.liquidationSources {
+		liqChan, errChan := source.SubscribeLiquidations(ctx)
+		if liqChan == nil || errChan == nil {
+			i.logger.Error("Failed to subscribe to liquidation source", zap.String("source", source.GetName()))
+			continue
+		}
+		i.logger.Info("Importing liquidations from an external source", zap.String("source", source.GetName()))
+		i.supervisor.Go(ctx, "liquidations-"+source.GetName(), func(ctx context.Context) error {
+			i.consumeLiquidations(ctx, liqChan, errChan)
+			return nil
+		})
+	}
+}
+
+// seenLiquidations keeps the recent liquidations of all sources, so a liquidation reported by the exchange
+// and an aggregator is only imported once, from the source reporting it first
+type seenLiquidations struct {
+	mu       sync.Mutex
+	bySymbol map[domain.TickerName][]seenLiquidation
+}
+
+type seenLiquidation struct {
+	liquidation domain.Liquidation
+	seenAt      time.Time
+}
+
+func newSeenLiquidations() *seenLiquidations {
+	return &seenLiquidations{bySymbol: make(map[domain.TickerName][]seenLiquidation)}
+}
+
+// add records the liquidation and reports whether it's new, false if another source already reported it
+func (s *seenLiquidations) add(liq domain.Liquidation, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := s.bySymbol[liq.Order.Symbol]
+	kept := seen[:0]
+	for _, entry := range seen {
+		if now.Sub(entry.seenAt) < liquidationSourceRetention {
+			kept = append(kept, entry)
+		}
+	}
+
+	for _, entry := range kept {
+		if sameReportedLiquidation(entry.liquidation, liq) {
+			s.bySymbol[liq.Order.Symbol] = kept
+			return false
+		}
+	}
+	s.bySymbol[liq.Order.Symbol] = append(kept, seenLiquidation{liquidation: liq, seenAt: now})
+	return true
+}
+
+// evict drops the liquidations seen of the symbols
+func (s *seenLiquidations) evict(symbols []domain.TickerName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(s.bySymbol, symbol)
+	}
+}
+
+// len returns the number of symbols with liquidations seen
+func (s *seenLiquidations) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.bySymbol)
+}
+
+// sameReportedLiquidation reports whether two sources describe the same forced order,
+// it's domain.Liquidation.SameAs with a tolerance on the notional
+func sameReportedLiquidation(a, b domain.Liquidation) bool {
+	diff := a.EventAt.Sub(b.EventAt)
+	if a.Order.Symbol != b.Order.Symbol || a.Order.Side != b.Order.Side || a.Order.Price != b.Order.Price ||
+		diff > domain.LiquidationMatchWindow || diff < -domain.LiquidationMatchWindow {
+		return false
+	}
+	notionalA, notionalB := a.Order.Price*a.Order.Quantity, b.Order.Price*b.Order.Quantity
+	return math.Abs(notionalA-notionalB) <= liquidationSourceTolerance*math.Max(notionalA, notionalB)
+}
+
+// sourceTag returns the telemetry tag value of a liquidation source, exchange for the exchange itself
+func sourceTag(source string) string {
+	if source == "" {
+		return "exchange"
+	}
+	return source
+}
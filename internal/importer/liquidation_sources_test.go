@@ -0,0 +1,74 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// channelSource is a liquidation source streaming what is sent on its channel
+type channelSource struct {
+	name         string
+	liquidations chan exchanges.Liquidation
+}
+
+func (s *channelSource) GetName() string { return s.name }
+
+func (s *channelSource) SubscribeLiquidations(context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+	return s.liquidations, make(chan error)
+}
+
+func TestSeenLiquidations_Add(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	liquidation := func(price, quantity float64, at time.Time) domain.Liquidation {
+		return domain.Liquidation{
+			EventAt: at,
+			Order:   domain.Order{Symbol: "BTCUSDT", Side: domain.OrderSideSell, Price: price, Quantity: quantity, EventAt: at},
+		}
+	}
+
+	seen := newSeenLiquidations()
+	assert.True(t, seen.add(liquidation(50000, 0.123, now), now))
+	assert.False(t, seen.add(liquidation(50000, 0.1231, now.Add(300*time.Millisecond)), now), "same order with a rounded notional")
+	assert.True(t, seen.add(liquidation(50000, 0.2, now), now), "different notional")
+	assert.True(t, seen.add(liquidation(50001, 0.123, now), now), "different price")
+	assert.True(t, seen.add(liquidation(50000, 0.123, now.Add(2*time.Second)), now), "outside the match window")
+
+	later := now.Add(liquidationSourceRetention)
+	assert.True(t, seen.add(liquidation(50000, 0.123, now), later), "forgotten after the retention")
+}
+
+func TestLiquidationSources(t *testing.T) {
+	ts := setupTest()
+	native := make(chan exchanges.Liquidation)
+	ts.exchange.SubscribeLiquidationsFunc = func(context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+		return native, make(chan error)
+	}
+	source := &channelSource{name: "coinglass", liquidations: make(chan exchanges.Liquidation)}
+	ts.importer.liquidationSources = []exchanges.LiquidationSource{source}
+	ts.importer.seenLiquidations = newSeenLiquidations()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, ts.importer.startLiquidationsImport(ctx))
+
+	at := time.Now()
+	native <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 50000, Quantity: 0.5, TotalPrice: 25000, EventAt: at}
+	require.Eventually(t, func() bool { return len(ts.liqRepo.CreateCalls()) == 1 }, time.Second, time.Millisecond)
+
+	// the aggregator reports the streamed liquidation again along with one the stream missed
+	source.liquidations <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 50000, Quantity: 0.5, TotalPrice: 25000, EventAt: at, Source: "coinglass"}
+	source.liquidations <- exchanges.Liquidation{Symbol: "ETHUSDT", Side: exchanges.ShortLiquidated, Price: 2000, Quantity: 1, TotalPrice: 2000, EventAt: at, Source: "coinglass"}
+	require.Eventually(t, func() bool { return len(ts.liqRepo.CreateCalls()) == 2 }, time.Second, time.Millisecond)
+
+	calls := ts.liqRepo.CreateCalls()
+	assert.Equal(t, domain.TickerName("BTCUSDT"), calls[0].L.Order.Symbol)
+	assert.Empty(t, calls[0].L.Source)
+	assert.Equal(t, domain.TickerName("ETHUSDT"), calls[1].L.Order.Symbol)
+	assert.Equal(t, "coinglass", calls[1].L.Source)
+}
@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"go.uber.org/zap"
+)
+
+// liquidationStorageInterval is how often the storage rate of the liquidations is measured and the batch is flushed
+const liquidationStorageInterval = time.Second
+
+// liquidationStorageStopTimeout bounds storing the pending batch on stop
+const liquidationStorageStopTimeout = 5 * time.Second
+
+// DefaultLiquidationStorageBaseline is the window of the moving average the liquidation storage rate is compared to
+const DefaultLiquidationStorageBaseline = 10 * time.Minute
+
+// minLiquidationStorageBaseline is the lowest baseline rate a spike is compared to, so a few liquidations after a
+// quiet hour don't count as a spike
+const minLiquidationStorageBaseline = 1.0
+
+// LiquidationStorageConfig configures the guard of the liquidation storage rate. It flags the rate of stored
+// liquidations once it exceeds its trailing baseline, whatever the market alert thresholds, and batches the writes
+// meanwhile, so a liquidation cascade doesn't overwhelm the repository with single inserts
+type LiquidationStorageConfig struct {
+	SpikeFactor float64       // flag the rate once it exceeds the baseline this many times, 0 disables the guard
+	Baseline    time.Duration // window of the moving average, also the warm-up; 0 uses DefaultLiquidationStorageBaseline
+	BatchSize   int           // while flagged, store up to this many liquidations per write; 0 or 1 keeps single writes
+}
+
+// liquidationStorage measures the rate of the stored liquidations against its exponential moving average and
+// holds the liquidations batched while the rate is flagged
+type liquidationStorage struct {
+	config LiquidationStorageConfig
+
+	mu              sync.Mutex
+	stored          int64 // liquidations stored since the latest measurement
+	firstMeasuredAt time.Time
+	baseline        float64
+	spiking         bool
+	pending         []domain.Liquidation
+}
+
+func newLiquidationStorage(config LiquidationStorageConfig) *liquidationStorage {
+	if config.Baseline <= 0 {
+		config.Baseline = DefaultLiquidationStorageBaseline
+	}
+	return &liquidationStorage{config: config}
+}
+
+// batch adds the liquidation to the pending batch while the rate is flagged and returns the batch once full.
+// It reports false when the liquidation isn't batched and has to be stored on its own
+func (s *liquidationStorage) batch(liq domain.Liquidation) ([]domain.Liquidation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.spiking || s.config.BatchSize <= 1 {
+		return nil, false
+	}
+	s.pending = append(s.pending, liq)
+	if len(s.pending) < s.config.BatchSize {
+		return nil, true
+	}
+	full := s.pending
+	s.pending = nil
+	return full, true
+}
+
+// takePending returns the pending batch and empties it
+func (s *liquidationStorage) takePending() []domain.Liquidation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending := s.pending
+	s.pending = nil
+	return pending
+}
+
+// observe counts stored liquidations
+func (s *liquidationStorage) observe(stored int) {
+	s.mu.Lock()
+	s.stored += int64(stored)
+	s.mu.Unlock()
+}
+
+// measure returns the rate of the liquidations stored since the previous call, compared to the baseline before
+// adding it to the baseline, and whether it got flagged or recovered. The rate is flagged past the spike factor
+// once the baseline warmed up
+func (s *liquidationStorage) measure(exchange string, at time.Time, interval time.Duration) (eventbus.StorageRate, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.firstMeasuredAt.IsZero() {
+		s.firstMeasuredAt = at
+	}
+
+	rate := eventbus.StorageRate{
+		Exchange: exchange,
+		Stored:   s.stored,
+		Interval: interval,
+		Baseline: s.baseline,
+	}
+	warmedUp := at.Sub(s.firstMeasuredAt) >= s.config.Baseline
+	wasSpiking := s.spiking
+	s.spiking = s.config.SpikeFactor > 0 && warmedUp &&
+		rate.PerSecond() > s.config.SpikeFactor*max(s.baseline, minLiquidationStorageBaseline)
+	rate.Spiking = s.spiking
+
+	weight := min(interval.Seconds()/s.config.Baseline.Seconds(), 1)
+	s.baseline += weight * (rate.PerSecond() - s.baseline)
+	s.stored = 0
+	return rate, s.spiking != wasSpiking
+}
+
+// storeLiquidation stores a liquidation, in a batch while the storage rate is flagged and the repository stores batches
+func (i *Importer) storeLiquidation(ctx context.Context, liq domain.Liquidation) {
+	if _, ok := i.liquidationRepository.(domain.LiquidationBatchRepository); ok {
+		if full, batched := i.liquidationStorage.batch(liq); batched {
+			i.storeLiquidationBatch(ctx, full)
+			return
+		}
+	}
+
+	if err := i.liquidationRepository.Create(ctx, liq); err != nil {
+		i.publishDegraded(eventbus.StageStoreLiquidation, err)
+		i.logger.Error("Failed to store liquidation", zap.Error(err))
+		return
+	}
+	i.liquidationStorage.observe(1)
+}
+
+// storeLiquidationBatch stores the liquidations in a single write
+func (i *Importer) storeLiquidationBatch(ctx context.Context, liquidations []domain.Liquidation) {
+	repository, ok := i.liquidationRepository.(domain.LiquidationBatchRepository)
+	if !ok || len(liquidations) == 0 {
+		return
+	}
+	if err := repository.CreateMany(ctx, liquidations); err != nil {
+		i.publishDegraded(eventbus.StageStoreLiquidation, err)
+		i.logger.Error("Failed to store liquidations", zap.Int("count", len(liquidations)), zap.Error(err))
+		return
+	}
+	i.telemetry.Gauge(telemetryLiquidationsStoreBatchSize, float64(len(liquidations)))
+	i.liquidationStorage.observe(len(liquidations))
+}
+
+// guardLiquidationStorage stores the pending batch and measures the storage rate every liquidationStorageInterval,
+// the pending batch is stored on stop too
+func (i *Importer) guardLiquidationStorage(ctx context.Context) error {
+	ticker := time.NewTicker(liquidationStorageInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), liquidationStorageStopTimeout)
+			defer cancel()
+			i.storeLiquidationBatch(stopCtx, i.liquidationStorage.takePending())
+			return nil
+		case now := <-ticker.C:
+			i.storeLiquidationBatch(ctx, i.liquidationStorage.takePending())
+			i.reportLiquidationStorage(i.liquidationStorage.measure(i.exchange.GetName(), now, liquidationStorageInterval))
+		}
+	}
+}
+
+// reportLiquidationStorage reports a measurement of the storage rate, logging when the rate got flagged or recovered
+func (i *Importer) reportLiquidationStorage(rate eventbus.StorageRate, changed bool) {
+	i.telemetry.Gauge(telemetryLiquidationsStoreRate, rate.PerSecond())
+	i.telemetry.Gauge(telemetryLiquidationsStoreBaseline, rate.Baseline)
+	spiking := 0.0
+	if rate.Spiking {
+		spiking = 1
+	}
+	i.telemetry.Gauge(telemetryLiquidationsStoreSpiking, spiking)
+	i.events.Publish(eventbus.LiquidationStorageMeasured, rate)
+
+	if !changed {
+		return
+	}
+	fields := []zap.Field{
+		zap.Float64("rate", rate.PerSecond()),
+		zap.Float64("baseline", rate.Baseline),
+		zap.Float64("spike_factor", i.liquidationStorage.config.SpikeFactor),
+		zap.Int("batch_size", i.liquidationStorage.config.BatchSize),
+	}
+	if rate.Spiking {
+		i.logger.Warn("Liquidation storage rate spiked above its baseline", fields...)
+	} else {
+		i.logger.Info("Liquidation storage rate back to its baseline", fields...)
+	}
+}
@@ -0,0 +1,100 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchLiquidationRepository records the single and batched writes
+type batchLiquidationRepository struct {
+	*domainMocks.LiquidationRepositoryMock
+	batches [][]domain.Liquidation
+}
+
+func (r *batchLiquidationRepository) CreateMany(_ context.Context, liquidations []domain.Liquidation) error {
+	r.batches = append(r.batches, liquidations)
+	return nil
+}
+
+func TestLiquidationStorage_Measure(t *testing.T) {
+	storage := newLiquidationStorage(LiquidationStorageConfig{SpikeFactor: 5, Baseline: 10 * time.Second})
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	storage.observe(100)
+	rate, changed := storage.measure("binance", at, time.Second)
+	assert.False(t, rate.Spiking, "spikes are not flagged before the baseline is warmed up")
+	assert.False(t, changed)
+
+	for range 20 {
+		at = at.Add(time.Second)
+		storage.observe(2)
+		storage.measure("binance", at, time.Second)
+	}
+
+	at = at.Add(time.Second)
+	storage.observe(30)
+	rate, changed = storage.measure("binance", at, time.Second)
+	assert.True(t, rate.Spiking)
+	assert.True(t, changed)
+	assert.Equal(t, int64(30), rate.Stored)
+	assert.InDelta(t, 3, rate.Baseline, 0.1)
+
+	at = at.Add(time.Second)
+	storage.observe(2)
+	rate, changed = storage.measure("binance", at, time.Second)
+	assert.False(t, rate.Spiking)
+	assert.True(t, changed, "the rate recovered")
+}
+
+func TestLiquidationStorage_Disabled(t *testing.T) {
+	storage := newLiquidationStorage(LiquidationStorageConfig{})
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	storage.measure("binance", at, time.Second)
+
+	storage.observe(1000)
+	rate, _ := storage.measure("binance", at.Add(time.Hour), time.Second)
+	assert.False(t, rate.Spiking, "a zero spike factor disables the guard")
+	assert.Equal(t, DefaultLiquidationStorageBaseline, storage.config.Baseline)
+}
+
+func TestImporter_StoreLiquidationBatches(t *testing.T) {
+	ts := setupTest()
+	repository := &batchLiquidationRepository{LiquidationRepositoryMock: ts.liqRepo}
+	ts.importer.liquidationRepository = repository
+	ts.importer.liquidationStorage = newLiquidationStorage(LiquidationStorageConfig{SpikeFactor: 2, BatchSize: 3})
+	var measured []eventbus.StorageRate
+	ts.events.Subscribe("test", func(_ context.Context, event eventbus.Event) {
+		measured = append(measured, event.Payload.(eventbus.StorageRate))
+	}, eventbus.LiquidationStorageMeasured)
+	ctx := context.Background()
+	liq := domain.Liquidation{Order: domain.Order{Symbol: "BTCUSDT"}}
+
+	ts.importer.storeLiquidation(ctx, liq)
+	assert.Len(t, ts.liqRepo.CreateCalls(), 1, "liquidations are stored one by one while the rate is normal")
+
+	ts.importer.liquidationStorage.spiking = true
+	for range 4 {
+		ts.importer.storeLiquidation(ctx, liq)
+	}
+	assert.Len(t, ts.liqRepo.CreateCalls(), 1)
+	require.Len(t, repository.batches, 1, "a full batch is stored right away")
+	assert.Len(t, repository.batches[0], 3)
+
+	ts.importer.storeLiquidationBatch(ctx, ts.importer.liquidationStorage.takePending())
+	require.Len(t, repository.batches, 2, "the partial batch is stored by the guard")
+	assert.Len(t, repository.batches[1], 1)
+
+	rate, _ := ts.importer.liquidationStorage.measure("binance", time.Now(), time.Second)
+	assert.Equal(t, int64(5), rate.Stored)
+	ts.importer.reportLiquidationStorage(rate, false)
+	ts.events.Flush()
+	require.Len(t, measured, 1)
+	assert.Equal(t, int64(5), measured[0].Stored)
+}
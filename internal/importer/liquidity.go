@@ -0,0 +1,63 @@
+package importer
+
+import (
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/usd"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+)
+
+// LiquidityFilter configures how dust pairs are kept out of market averages and alerts
+type LiquidityFilter struct {
+	MinNotional  float64 // minimum of bid and ask notional in USD, 0 disables the filter
+	DropIlliquid bool    // drop illiquid pairs from the tick instead of storing them marked as illiquid
+}
+
+// enabled reports whether the filter is configured
+func (f LiquidityFilter) enabled() bool {
+	return f.MinNotional > 0
+}
+
+// isIlliquid reports whether the top of the book of the ticker is below the minimum notional.
+// Tickers without a known USD rate are compared as quoted
+func (f LiquidityFilter) isIlliquid(t exchanges.Ticker, rates *usd.Rates) bool {
+	if !f.enabled() {
+		return false
+	}
+	notional, ok := rates.TickerNotional(t)
+	if !ok {
+		notional = min(t.BidPrice*t.BidQuantity, t.AskPrice*t.AskQuantity)
+	}
+	return notional < f.MinNotional
+}
+
+// apply returns the tickers to build and the number of illiquid ones; illiquid tickers are removed if DropIlliquid is set
+func (f LiquidityFilter) apply(tickers []exchanges.Ticker, rates *usd.Rates) ([]exchanges.Ticker, int) {
+	if !f.enabled() {
+		return tickers, 0
+	}
+
+	kept := tickers
+	if f.DropIlliquid {
+		kept = make([]exchanges.Ticker, 0, len(tickers))
+	}
+	illiquid := 0
+	for _, t := range tickers {
+		if f.isIlliquid(t, rates) {
+			illiquid++
+			continue
+		}
+		if f.DropIlliquid {
+			kept = append(kept, t)
+		}
+	}
+	return kept, illiquid
+}
+
+// liquidationUSDValue returns the USD notional of a liquidation rounded to decimals, 0 if its quote asset has no known rate
+func liquidationUSDValue(rates *usd.Rates, liq exchanges.Liquidation, decimals int) float64 {
+	notional, ok := rates.Notional(liq.Price, liq.Quantity, liq.Quote, 0)
+	if !ok {
+		return 0
+	}
+	return mathutils.RoundTo(notional, decimals)
+}
@@ -14,12 +14,27 @@ import (
 //
 //		// make and configure a mocked importer.RepositoryFactory
 //		mockedRepositoryFactory := &RepositoryFactoryMock{
+//			GetBarRepositoryFunc: func(name string) (domain.BarRepository, error) {
+//				panic("mock out the GetBarRepository method")
+//			},
+//			GetFundingRateRepositoryFunc: func(name string) (domain.FundingRateRepository, error) {
+//				panic("mock out the GetFundingRateRepository method")
+//			},
 //			GetLiquidationRepositoryFunc: func(name string) (domain.LiquidationRepository, error) {
 //				panic("mock out the GetLiquidationRepository method")
 //			},
+//			GetSymbolRepositoryFunc: func(name string) (domain.SymbolRepository, error) {
+//				panic("mock out the GetSymbolRepository method")
+//			},
+//			GetSubTickRepositoryFunc: func(name string) (domain.SubTickRepository, error) {
+//				panic("mock out the GetSubTickRepository method")
+//			},
 //			GetTickRepositoryFunc: func(name string) (domain.TickRepository, error) {
 //				panic("mock out the GetTickRepository method")
 //			},
+//			GetTradeRepositoryFunc: func(name string) (domain.TradeRepository, error) {
+//				panic("mock out the GetTradeRepository method")
+//			},
 //		}
 //
 //		// use mockedRepositoryFactory in code that requires importer.RepositoryFactory
@@ -27,27 +42,150 @@ import (
 //
 //	}
 type RepositoryFactoryMock struct {
+	// GetBarRepositoryFunc mocks the GetBarRepository method.
+	GetBarRepositoryFunc func(name string) (domain.BarRepository, error)
+
+	// GetFundingRateRepositoryFunc mocks the GetFundingRateRepository method.
+	GetFundingRateRepositoryFunc func(name string) (domain.FundingRateRepository, error)
+
 	// GetLiquidationRepositoryFunc mocks the GetLiquidationRepository method.
 	GetLiquidationRepositoryFunc func(name string) (domain.LiquidationRepository, error)
 
+	// GetSymbolRepositoryFunc mocks the GetSymbolRepository method.
+	GetSymbolRepositoryFunc func(name string) (domain.SymbolRepository, error)
+
+	// GetSubTickRepositoryFunc mocks the GetSubTickRepository method.
+	GetSubTickRepositoryFunc func(name string) (domain.SubTickRepository, error)
+
 	// GetTickRepositoryFunc mocks the GetTickRepository method.
 	GetTickRepositoryFunc func(name string) (domain.TickRepository, error)
 
+	// GetTradeRepositoryFunc mocks the GetTradeRepository method.
+	GetTradeRepositoryFunc func(name string) (domain.TradeRepository, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// GetBarRepository holds details about calls to the GetBarRepository method.
+		GetBarRepository []struct {
+			// Name is the name argument value.
+			Name string
+		}
+		// GetFundingRateRepository holds details about calls to the GetFundingRateRepository method.
+		GetFundingRateRepository []struct {
+			// Name is the name argument value.
+			Name string
+		}
 		// GetLiquidationRepository holds details about calls to the GetLiquidationRepository method.
 		GetLiquidationRepository []struct {
 			// Name is the name argument value.
 			Name string
 		}
+		// GetSymbolRepository holds details about calls to the GetSymbolRepository method.
+		GetSymbolRepository []struct {
+			// Name is the name argument value.
+			Name string
+		}
+		// GetSubTickRepository holds details about calls to the GetSubTickRepository method.
+		GetSubTickRepository []struct {
+			// Name is the name argument value.
+			Name string
+		}
 		// GetTickRepository holds details about calls to the GetTickRepository method.
 		GetTickRepository []struct {
 			// Name is the name argument value.
 			Name string
 		}
+		// GetTradeRepository holds details about calls to the GetTradeRepository method.
+		GetTradeRepository []struct {
+			// Name is the name argument value.
+			Name string
+		}
 	}
+	lockGetBarRepository         sync.RWMutex
+	lockGetFundingRateRepository sync.RWMutex
 	lockGetLiquidationRepository sync.RWMutex
+	lockGetSymbolRepository      sync.RWMutex
+	lockGetSubTickRepository     sync.RWMutex
 	lockGetTickRepository        sync.RWMutex
+	lockGetTradeRepository       sync.RWMutex
+}
+
+// GetBarRepository calls GetBarRepositoryFunc.
+func (mock *RepositoryFactoryMock) GetBarRepository(name string) (domain.BarRepository, error) {
+	if mock.GetBarRepositoryFunc == nil {
+		panic("RepositoryFactoryMock.GetBarRepositoryFunc: method is nil but RepositoryFactory.GetBarRepository was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockGetBarRepository.Lock()
+	mock.calls.GetBarRepository = append(mock.calls.GetBarRepository, callInfo)
+	mock.lockGetBarRepository.Unlock()
+	return mock.GetBarRepositoryFunc(name)
+}
+
+// GetBarRepositoryCalls gets all the calls that were made to GetBarRepository.
+// Check the length with:
+//
+//	len(mockedRepositoryFactory.GetBarRepositoryCalls())
+func (mock *RepositoryFactoryMock) GetBarRepositoryCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockGetBarRepository.RLock()
+	calls = mock.calls.GetBarRepository
+	mock.lockGetBarRepository.RUnlock()
+	return calls
+}
+
+// ResetGetBarRepositoryCalls reset all the calls that were made to GetBarRepository.
+func (mock *RepositoryFactoryMock) ResetGetBarRepositoryCalls() {
+	mock.lockGetBarRepository.Lock()
+	mock.calls.GetBarRepository = nil
+	mock.lockGetBarRepository.Unlock()
+}
+
+// GetFundingRateRepository calls GetFundingRateRepositoryFunc.
+func (mock *RepositoryFactoryMock) GetFundingRateRepository(name string) (domain.FundingRateRepository, error) {
+	if mock.GetFundingRateRepositoryFunc == nil {
+		panic("RepositoryFactoryMock.GetFundingRateRepositoryFunc: method is nil but RepositoryFactory.GetFundingRateRepository was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockGetFundingRateRepository.Lock()
+	mock.calls.GetFundingRateRepository = append(mock.calls.GetFundingRateRepository, callInfo)
+	mock.lockGetFundingRateRepository.Unlock()
+	return mock.GetFundingRateRepositoryFunc(name)
+}
+
+// GetFundingRateRepositoryCalls gets all the calls that were made to GetFundingRateRepository.
+// Check the length with:
+//
+//	len(mockedRepositoryFactory.GetFundingRateRepositoryCalls())
+func (mock *RepositoryFactoryMock) GetFundingRateRepositoryCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockGetFundingRateRepository.RLock()
+	calls = mock.calls.GetFundingRateRepository
+	mock.lockGetFundingRateRepository.RUnlock()
+	return calls
+}
+
+// ResetGetFundingRateRepositoryCalls reset all the calls that were made to GetFundingRateRepository.
+func (mock *RepositoryFactoryMock) ResetGetFundingRateRepositoryCalls() {
+	mock.lockGetFundingRateRepository.Lock()
+	mock.calls.GetFundingRateRepository = nil
+	mock.lockGetFundingRateRepository.Unlock()
 }
 
 // GetLiquidationRepository calls GetLiquidationRepositoryFunc.
@@ -89,6 +227,84 @@ func (mock *RepositoryFactoryMock) ResetGetLiquidationRepositoryCalls() {
 	mock.lockGetLiquidationRepository.Unlock()
 }
 
+// GetSymbolRepository calls GetSymbolRepositoryFunc.
+func (mock *RepositoryFactoryMock) GetSymbolRepository(name string) (domain.SymbolRepository, error) {
+	if mock.GetSymbolRepositoryFunc == nil {
+		panic("RepositoryFactoryMock.GetSymbolRepositoryFunc: method is nil but RepositoryFactory.GetSymbolRepository was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockGetSymbolRepository.Lock()
+	mock.calls.GetSymbolRepository = append(mock.calls.GetSymbolRepository, callInfo)
+	mock.lockGetSymbolRepository.Unlock()
+	return mock.GetSymbolRepositoryFunc(name)
+}
+
+// GetSymbolRepositoryCalls gets all the calls that were made to GetSymbolRepository.
+// Check the length with:
+//
+//	len(mockedRepositoryFactory.GetSymbolRepositoryCalls())
+func (mock *RepositoryFactoryMock) GetSymbolRepositoryCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockGetSymbolRepository.RLock()
+	calls = mock.calls.GetSymbolRepository
+	mock.lockGetSymbolRepository.RUnlock()
+	return calls
+}
+
+// ResetGetSymbolRepositoryCalls reset all the calls that were made to GetSymbolRepository.
+func (mock *RepositoryFactoryMock) ResetGetSymbolRepositoryCalls() {
+	mock.lockGetSymbolRepository.Lock()
+	mock.calls.GetSymbolRepository = nil
+	mock.lockGetSymbolRepository.Unlock()
+}
+
+// GetSubTickRepository calls GetSubTickRepositoryFunc.
+func (mock *RepositoryFactoryMock) GetSubTickRepository(name string) (domain.SubTickRepository, error) {
+	if mock.GetSubTickRepositoryFunc == nil {
+		panic("RepositoryFactoryMock.GetSubTickRepositoryFunc: method is nil but RepositoryFactory.GetSubTickRepository was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockGetSubTickRepository.Lock()
+	mock.calls.GetSubTickRepository = append(mock.calls.GetSubTickRepository, callInfo)
+	mock.lockGetSubTickRepository.Unlock()
+	return mock.GetSubTickRepositoryFunc(name)
+}
+
+// GetSubTickRepositoryCalls gets all the calls that were made to GetSubTickRepository.
+// Check the length with:
+//
+//	len(mockedRepositoryFactory.GetSubTickRepositoryCalls())
+func (mock *RepositoryFactoryMock) GetSubTickRepositoryCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockGetSubTickRepository.RLock()
+	calls = mock.calls.GetSubTickRepository
+	mock.lockGetSubTickRepository.RUnlock()
+	return calls
+}
+
+// ResetGetSubTickRepositoryCalls reset all the calls that were made to GetSubTickRepository.
+func (mock *RepositoryFactoryMock) ResetGetSubTickRepositoryCalls() {
+	mock.lockGetSubTickRepository.Lock()
+	mock.calls.GetSubTickRepository = nil
+	mock.lockGetSubTickRepository.Unlock()
+}
+
 // GetTickRepository calls GetTickRepositoryFunc.
 func (mock *RepositoryFactoryMock) GetTickRepository(name string) (domain.TickRepository, error) {
 	if mock.GetTickRepositoryFunc == nil {
@@ -128,13 +344,72 @@ func (mock *RepositoryFactoryMock) ResetGetTickRepositoryCalls() {
 	mock.lockGetTickRepository.Unlock()
 }
 
+// GetTradeRepository calls GetTradeRepositoryFunc.
+func (mock *RepositoryFactoryMock) GetTradeRepository(name string) (domain.TradeRepository, error) {
+	if mock.GetTradeRepositoryFunc == nil {
+		panic("RepositoryFactoryMock.GetTradeRepositoryFunc: method is nil but RepositoryFactory.GetTradeRepository was just called")
+	}
+	callInfo := struct {
+		Name string
+	}{
+		Name: name,
+	}
+	mock.lockGetTradeRepository.Lock()
+	mock.calls.GetTradeRepository = append(mock.calls.GetTradeRepository, callInfo)
+	mock.lockGetTradeRepository.Unlock()
+	return mock.GetTradeRepositoryFunc(name)
+}
+
+// GetTradeRepositoryCalls gets all the calls that were made to GetTradeRepository.
+// Check the length with:
+//
+//	len(mockedRepositoryFactory.GetTradeRepositoryCalls())
+func (mock *RepositoryFactoryMock) GetTradeRepositoryCalls() []struct {
+	Name string
+} {
+	var calls []struct {
+		Name string
+	}
+	mock.lockGetTradeRepository.RLock()
+	calls = mock.calls.GetTradeRepository
+	mock.lockGetTradeRepository.RUnlock()
+	return calls
+}
+
+// ResetGetTradeRepositoryCalls reset all the calls that were made to GetTradeRepository.
+func (mock *RepositoryFactoryMock) ResetGetTradeRepositoryCalls() {
+	mock.lockGetTradeRepository.Lock()
+	mock.calls.GetTradeRepository = nil
+	mock.lockGetTradeRepository.Unlock()
+}
+
 // ResetCalls reset all the calls that were made to all mocked methods.
 func (mock *RepositoryFactoryMock) ResetCalls() {
+	mock.lockGetBarRepository.Lock()
+	mock.calls.GetBarRepository = nil
+	mock.lockGetBarRepository.Unlock()
+
+	mock.lockGetFundingRateRepository.Lock()
+	mock.calls.GetFundingRateRepository = nil
+	mock.lockGetFundingRateRepository.Unlock()
+
 	mock.lockGetLiquidationRepository.Lock()
 	mock.calls.GetLiquidationRepository = nil
 	mock.lockGetLiquidationRepository.Unlock()
 
+	mock.lockGetSymbolRepository.Lock()
+	mock.calls.GetSymbolRepository = nil
+	mock.lockGetSymbolRepository.Unlock()
+
+	mock.lockGetSubTickRepository.Lock()
+	mock.calls.GetSubTickRepository = nil
+	mock.lockGetSubTickRepository.Unlock()
+
 	mock.lockGetTickRepository.Lock()
 	mock.calls.GetTickRepository = nil
 	mock.lockGetTickRepository.Unlock()
+
+	mock.lockGetTradeRepository.Lock()
+	mock.calls.GetTradeRepository = nil
+	mock.lockGetTradeRepository.Unlock()
 }
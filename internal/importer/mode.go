@@ -0,0 +1,28 @@
+package importer
+
+// Mode selects the pipelines run by the importer
+type Mode string
+
+const (
+	// ModeFull runs both the per-second tick build and the liquidation stream
+	ModeFull Mode = "full"
+
+	// ModeTickers only builds ticks, their liquidation counters are still read from the repository
+	ModeTickers Mode = "tickers"
+
+	// ModeLiquidations only records the liquidation stream, no tick is built
+	ModeLiquidations Mode = "liquidations"
+)
+
+// Modes lists the supported modes
+var Modes = []Mode{ModeFull, ModeTickers, ModeLiquidations}
+
+// RunsTickers reports whether ticks are built in this mode, an empty mode is ModeFull
+func (m Mode) RunsTickers() bool {
+	return m != ModeLiquidations
+}
+
+// RunsLiquidations reports whether the liquidation stream is consumed in this mode, an empty mode is ModeFull
+func (m Mode) RunsLiquidations() bool {
+	return m != ModeTickers
+}
@@ -0,0 +1,139 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"go.uber.org/zap"
+)
+
+// MinOpenInterestInterval is the shortest allowed interval between open interest polls,
+// exchanges without a bulk endpoint (Binance) send a request per symbol for every poll
+const MinOpenInterestInterval = 15 * time.Second
+
+// openInterestMaxAge is the number of poll intervals a polled open interest is used for, so the tickers of a stalled
+// poller report no open interest instead of a frozen one
+const openInterestMaxAge = 3
+
+// openInterest keeps the latest polled open interest of every symbol, for the exchanges that don't report it
+// with the tickers
+type openInterest struct {
+	mu       sync.RWMutex
+	bySymbol map[domain.TickerName]float64
+	polledAt time.Time
+	maxAge   time.Duration
+}
+
+func newOpenInterest(interval time.Duration) *openInterest {
+	return &openInterest{bySymbol: make(map[domain.TickerName]float64), maxAge: openInterestMaxAge * interval}
+}
+
+// set replaces the open interest with the values polled at the given time
+func (o *openInterest) set(values []exchanges.OpenInterest, polledAt time.Time) {
+	bySymbol := make(map[domain.TickerName]float64, len(values))
+	for _, v := range values {
+		if v.Value > 0 {
+			bySymbol[domain.TickerName(v.Symbol)] = v.Value
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.bySymbol = bySymbol
+	o.polledAt = polledAt
+}
+
+// at returns the open interest of the symbol as seen by a tick started at startAt, 0 when unknown or outdated
+func (o *openInterest) at(symbol domain.TickerName, startAt time.Time) float64 {
+	if o == nil {
+		return 0
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	if startAt.Sub(o.polledAt) > o.maxAge {
+		return 0
+	}
+	return o.bySymbol[symbol]
+}
+
+// evict drops the open interest of the symbols until the next poll
+func (o *openInterest) evict(symbols []domain.TickerName) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(o.bySymbol, symbol)
+	}
+}
+
+// len returns the number of symbols with an open interest
+func (o *openInterest) len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.bySymbol)
+}
+
+// startOpenInterestImport starts polling the open interest if configured and served by the exchange apart from
+// the tickers
+func (i *Importer) startOpenInterestImport(ctx context.Context) {
+	if i.openInterest == nil {
+		return
+	}
+	fetcher, ok := i.exchange.(exchanges.OpenInterestFetcher)
+	if !ok {
+		i.logger.Warn("Open interest is not polled from the exchange, only the tickers reporting it carry it",
+			zap.String("exchange", i.exchange.GetName()))
+		return
+	}
+
+	i.logger.Info("Open interest import started", zap.Duration("interval", i.openInterestInterval))
+	i.supervisor.Go(ctx, "open_interest", func(ctx context.Context) error {
+		i.pollOpenInterest(ctx, fetcher)
+		return nil
+	})
+}
+
+// pollOpenInterest polls the open interest right away and then every interval until ctx is canceled
+func (i *Importer) pollOpenInterest(ctx context.Context, fetcher exchanges.OpenInterestFetcher) {
+	timeTicker := time.NewTicker(i.openInterestInterval)
+	defer timeTicker.Stop()
+
+	for {
+		i.importOpenInterest(ctx, fetcher, domain.Now())
+		select {
+		case <-ctx.Done():
+			i.logger.Info("Open interest import stopped (context canceled).")
+			return
+		case <-timeTicker.C:
+		}
+	}
+}
+
+// importOpenInterest fetches the open interest and keeps it for the next tickers, the values failing conversion
+// are left out
+func (i *Importer) importOpenInterest(ctx context.Context, fetcher exchanges.OpenInterestFetcher, at time.Time) {
+	fetched, err := fetcher.FetchOpenInterest(ctx)
+	var convErr *exchanges.ConversionError
+	if errors.As(err, &convErr) && len(fetched) > 0 {
+		i.logger.Warn("Some open interest values failed conversion",
+			zap.Int("failed", convErr.Failed),
+			zap.Int("total", convErr.Total),
+			zap.Error(convErr),
+		)
+		err = nil
+	}
+	if err != nil {
+		i.telemetry.IncrementCounter(telemetryOpenInterestErrors, 1)
+		i.publishDegraded(eventbus.StageFetchOpenInterest, err)
+		i.logger.Error("Failed to fetch open interest", zap.Error(err))
+		return
+	}
+
+	i.openInterest.set(fetched, at)
+	i.telemetry.IncrementCounter(telemetryOpenInterestPolled, int64(len(fetched)))
+}
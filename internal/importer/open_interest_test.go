@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openInterestFunc is an exchanges.OpenInterestFetcher calling the function
+type openInterestFunc func(ctx context.Context) ([]exchanges.OpenInterest, error)
+
+func (f openInterestFunc) FetchOpenInterest(ctx context.Context) ([]exchanges.OpenInterest, error) {
+	return f(ctx)
+}
+
+func TestImportOpenInterest(t *testing.T) {
+	ts := setupTest()
+	importer := New(&Config{
+		Exchange:             ts.exchange,
+		RepositoryFactory:    ts.repoFactory,
+		EventBus:             ts.events,
+		OpenInterestInterval: time.Minute,
+		Telemetry:            ts.importer.telemetry,
+		Logger:               ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+	require.NotNil(t, importer.openInterest)
+	assert.Equal(t, time.Minute, importer.openInterestInterval)
+
+	var mu sync.Mutex
+	var stages []string
+	ts.events.Subscribe("test", func(ctx context.Context, event eventbus.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		stages = append(stages, event.Payload.(eventbus.Degradation).Stage)
+	}, eventbus.ImportDegraded)
+
+	at := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	partial := openInterestFunc(func(ctx context.Context) ([]exchanges.OpenInterest, error) {
+		return []exchanges.OpenInterest{{Symbol: "BTCUSDT", Value: 2500, EventAt: at}},
+			exchanges.NewConversionError(2, []error{errors.New("ETHUSDT: invalid openInterest")})
+	})
+	importer.importOpenInterest(context.Background(), partial, at)
+	assert.Equal(t, 2500.0, importer.openInterest.at("BTCUSDT", at.Add(time.Second)))
+	assert.Zero(t, importer.openInterest.at("ETHUSDT", at.Add(time.Second)), "failed conversion")
+
+	failing := openInterestFunc(func(ctx context.Context) ([]exchanges.OpenInterest, error) {
+		return nil, errors.New("connection refused")
+	})
+	importer.importOpenInterest(context.Background(), failing, at.Add(time.Minute))
+	ts.events.Flush()
+	assert.Equal(t, 2500.0, importer.openInterest.at("BTCUSDT", at.Add(time.Minute)), "a failed poll keeps the last values")
+	assert.Zero(t, importer.openInterest.at("BTCUSDT", at.Add(4*time.Minute)), "outdated after 3 intervals")
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{eventbus.StageFetchOpenInterest}, stages)
+}
+
+func TestBuildTickerOpenInterest(t *testing.T) {
+	ts := setupTest()
+	startAt := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	tick := domain.Tick{StartAt: startAt, Data: make(map[domain.TickerName]*domain.Ticker)}
+	eTicker := exchanges.Ticker{Symbol: "BTCUSDT", AskPrice: 50000, BidPrice: 49900, EventAt: startAt}
+
+	ticker, err := ts.importer.buildTicker(tick, nil, eTicker, nil)
+	require.NoError(t, err)
+	assert.Zero(t, ticker.OpenInterest, "not polled and not reported with the ticker")
+
+	ts.importer.openInterest = newOpenInterest(time.Minute)
+	ts.importer.openInterest.set([]exchanges.OpenInterest{{Symbol: "BTCUSDT", Value: 2500}}, startAt)
+	tick.StartAt = startAt.Add(time.Second)
+	ticker, err = ts.importer.buildTicker(tick, nil, eTicker, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2500.0, ticker.OpenInterest, "polled")
+
+	eTicker.OpenInterest = 2600
+	tick.StartAt = startAt.Add(2 * time.Second)
+	ticker, err = ts.importer.buildTicker(tick, nil, eTicker, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2600.0, ticker.OpenInterest, "reported with the ticker")
+}
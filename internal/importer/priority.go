@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+// PriorityConfig configures the core symbols of an exchange, built before the others so that an overloaded tick
+// still carries them
+type PriorityConfig struct {
+	Symbols  []string      // symbols always built, before the other ones
+	Deadline time.Duration // once this long passed since the tick started, the remaining low-priority symbols are skipped
+}
+
+// priorityList orders the tickers of a tick, nil when no priority symbols are configured
+type priorityList struct {
+	symbols  map[string]struct{}
+	deadline time.Duration
+}
+
+func newPriorityList(cfg PriorityConfig) *priorityList {
+	if len(cfg.Symbols) == 0 {
+		return nil
+	}
+	p := &priorityList{
+		symbols:  make(map[string]struct{}, len(cfg.Symbols)),
+		deadline: cfg.Deadline,
+	}
+	for _, symbol := range cfg.Symbols {
+		p.symbols[symbol] = struct{}{}
+	}
+	return p
+}
+
+// order returns the tickers with the priority symbols first, each group keeping its order, and the number of
+// priority tickers. Without a priority list every ticker is considered a priority one and is never skipped
+func (p *priorityList) order(tickers []exchanges.Ticker) ([]exchanges.Ticker, int) {
+	if p == nil {
+		return tickers, len(tickers)
+	}
+
+	ordered := make([]exchanges.Ticker, 0, len(tickers))
+	for _, t := range tickers {
+		if p.isPriority(t.Symbol) {
+			ordered = append(ordered, t)
+		}
+	}
+	count := len(ordered)
+	for _, t := range tickers {
+		if !p.isPriority(t.Symbol) {
+			ordered = append(ordered, t)
+		}
+	}
+	return ordered, count
+}
+
+// expired reports whether the low-priority symbols of a tick started at startAt must be skipped
+func (p *priorityList) expired(startAt time.Time) bool {
+	return p != nil && p.deadline > 0 && time.Since(startAt) > p.deadline
+}
+
+func (p *priorityList) isPriority(symbol string) bool {
+	_, ok := p.symbols[symbol]
+	return ok
+}
+
+// skippedSymbols returns the sorted symbols of the tickers left out of a tick
+func skippedSymbols(tickers []exchanges.Ticker) []domain.TickerName {
+	symbols := make([]domain.TickerName, 0, len(tickers))
+	for _, t := range tickers {
+		symbols = append(symbols, domain.TickerName(t.Symbol))
+	}
+	slices.Sort(symbols)
+	return symbols
+}
@@ -2,13 +2,18 @@ package importer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/usd"
 	"go.uber.org/zap"
 )
 
@@ -19,42 +24,147 @@ func (i *Importer) importTick(ctx context.Context) error {
 	startAt := time.Now()
 
 	// Fetch tickers from the exchange
-	fetchedTickers, err := i.fetchTickers(ctx)
+	fetchedTickers, convErr, err := i.fetchTickers(ctx)
 	if err != nil {
+		i.publishDegraded(eventbus.StageFetchTickers, err)
 		return fmt.Errorf("fetchTickers failed: %w", err)
 	}
 	fetchedAt := time.Now()
 
 	// Create a new tick
 	newTick := &domain.Tick{
-		StartAt:       startAt,
-		FetchedAt:     fetchedAt,
-		FetchDuration: fetchedAt.Sub(startAt).Milliseconds(),
-		Avg:           domain.TickAvg{},
-		Data:          make(map[domain.TickerName]*domain.Ticker),
+		StartAt:           startAt.UTC(),
+		FetchedAt:         fetchedAt.UTC(),
+		FetchDuration:     fetchedAt.Sub(startAt).Milliseconds(),
+		IndicatorsVersion: domain.IndicatorsVersion,
+		Maintenance:       i.inMaintenance(startAt),
+		WarmingUp:         i.warmingUp(startAt),
+		Avg:               domain.TickAvg{},
+		Data:              make(map[domain.TickerName]*domain.Ticker),
+	}
+	if convErr != nil {
+		newTick.AddError(domain.TickStageConvertTickers, convErr)
 	}
 
 	// Build the tick using the fetched data
+	previous, _ := i.getLastTick()
 	i.buildTick(ctx, newTick, fetchedTickers)
-	newTick.CreatedAt = time.Now()
-	newTick.HandlingDuration = time.Since(newTick.FetchedAt).Milliseconds()
+	i.scoreTick(newTick, previous, convErr)
+	if skew, ok := newTick.MedianEventSkew(); ok {
+		newTick.EventSkew = skew.Milliseconds()
+		i.telemetry.Gauge(telemetryTickEventSkew, float64(newTick.EventSkew))
+	}
+	for _, tickErr := range newTick.Errors {
+		i.telemetry.IncrementCounter(telemetryTickBuildErrors, 1, fmt.Sprintf("stage:%s", tickErr.Stage))
+	}
+	newTick.CreatedAt = domain.Now()
+	newTick.HandlingDuration = time.Since(fetchedAt).Milliseconds()
 
-	if err := newTick.Validate(); err != nil {
+	if err := i.validateTick(newTick); err != nil {
+		i.publishDegraded(eventbus.StageValidateTick, err)
 		return fmt.Errorf("tick validation failed: %w", err)
 	}
 
-	i.notifyNewTick(newTick)
-
-	// Store the tick in the database
-	if err := i.tickRepository.Create(ctx, *newTick); err != nil {
+	// By default the tick is stored, then published: notifiers send its market data, then evaluate
+	// its alerts. PublishBeforeStore publishes first, a tick failing to persist is published anyway
+	if i.publishOrder == PublishBeforeStore {
+		i.publishTick(ctx, newTick)
+	}
+	if err := i.storeTick(ctx, newTick); err != nil {
+		i.publishDegraded(eventbus.StageStoreTick, err)
 		return fmt.Errorf("failed to store tick in DB: %w", err)
 	}
+	if i.publishOrder == PublishAfterStore {
+		i.publishTick(ctx, newTick)
+	}
+
+	if i.logTickSummary {
+		i.logTick(newTick, len(fetchedTickers))
+	}
 
 	return nil
 }
 
-// fetchTickers is a simple wrapper that calls exchange.FetchTickers
-func (i *Importer) fetchTickers(ctx context.Context) ([]exchanges.Ticker, error) {
+// publishTick swaps in the snapshot of the tick, announces it and flushes the minute bars it closed
+func (i *Importer) publishTick(ctx context.Context, tick *domain.Tick) {
+	i.latestTick.Store(tick.Clone())
+	i.publishTickBuilt(tick)
+	if i.bars != nil {
+		i.flushBars(ctx, tick.StartAt)
+	}
+}
+
+// LatestTick returns a snapshot of the latest published tick, false before the first one. The snapshot is replaced,
+// never modified, when the next tick is published, so it can be served to external readers without locking;
+// callers must not modify it
+func (i *Importer) LatestTick() (*domain.Tick, bool) {
+	tick := i.latestTick.Load()
+	return tick, tick != nil
+}
+
+// validateTick runs the tick checks against the ticks preceding it in the history
+func (i *Importer) validateTick(tick *domain.Tick) error {
+	previous := i.tickHistory.buffer.Values()
+	if n := len(previous); n > 0 && previous[n-1] == tick {
+		previous = previous[:n-1]
+	}
+	err := i.tickValidator.Validate(tick, previous)
+	if len(tick.Suspect) > 0 {
+		i.telemetry.IncrementCounter(telemetryTickValidateSuspect, 1)
+		i.logger.Warn("Tick marked suspect", zap.Time("start_at", tick.StartAt), zap.Strings("checks", tick.Suspect))
+	}
+	return err
+}
+
+// storeTick stores the tick, retrying transient repository failures. Repositories upsert ticks by their
+// StorageKey, so a retry after a write reported as failed but applied doesn't create a duplicate.
+// With storage sampling the long-tail tickers not due this second are left out of the stored copy
+func (i *Importer) storeTick(ctx context.Context, tick *domain.Tick) error {
+	if i.storageSampler != nil {
+		var sampledOut int
+		tick, sampledOut = i.storageSampler.sample(tick, i.priority)
+		i.telemetry.Gauge(telemetryTickStoreSampledOut, float64(sampledOut))
+	}
+	backoff := storeTickBackoff
+	for attempt := 1; ; attempt++ {
+		err := i.tickRepository.Create(ctx, *tick)
+		if err == nil || attempt == storeTickAttempts {
+			return err
+		}
+
+		i.telemetry.IncrementCounter(telemetryTickStoreRetries, 1)
+		i.logger.Warn("Failed to store tick, retrying", zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// logTick writes a single structured line describing the imported tick. tickers_dropped counts the fetched tickers
+// left out of the tick for any reason: other quote assets, dust, unconvertible, skipped or failing to build
+func (i *Importer) logTick(tick *domain.Tick, fetched int) {
+	i.logger.Info("Tick imported",
+		zap.String("exchange", i.exchange.GetName()),
+		zap.Time("start_at", tick.StartAt),
+		zap.Int("tickers_fetched", fetched),
+		zap.Int("tickers_processed", len(tick.Data)),
+		zap.Int("tickers_dropped", fetched-len(tick.Data)),
+		zap.Int64("fetch_ms", tick.FetchDuration),
+		zap.Int64("handling_ms", tick.HandlingDuration),
+		zap.Int64("ll_1", tick.LL1),
+		zap.Int64("ll_60", tick.LL60),
+		zap.Int64("sl_1", tick.SL1),
+		zap.Int64("sl_10", tick.SL10),
+		zap.Float64("avg_change_1m", tick.Avg.Change1m),
+	)
+}
+
+// fetchTickers is a simple wrapper that calls exchange.FetchTickers. It returns the conversion error of a fetch
+// whose share of tickers failing conversion is small enough to build the tick from the remaining ones
+func (i *Importer) fetchTickers(ctx context.Context) ([]exchanges.Ticker, *exchanges.ConversionError, error) {
 	span, ctx := i.telemetry.StartSpan(ctx, telemetrySpanFetchTickers)
 	defer span.Finish()
 
@@ -62,6 +172,21 @@ func (i *Importer) fetchTickers(ctx context.Context) ([]exchanges.Ticker, error)
 	tickers, err := i.exchange.FetchTickers(ctx)
 	i.telemetry.Timing(telemetryTickFetchDuration, time.Since(startTime))
 	i.telemetry.Gauge(telemetryTickFetchTickersCount, float64(len(tickers)))
+	if reporter, ok := i.exchange.(exchanges.APIUsageReporter); ok {
+		usage := reporter.APIUsage()
+		i.telemetry.Gauge(telemetryExchangeAPIUsed, usage.Used)
+		i.telemetry.Gauge(telemetryExchangeAPIRemaining, usage.Remaining())
+	}
+	if errors.Is(err, exchanges.ErrThrottled) {
+		i.telemetry.IncrementCounter(telemetryTickFetchThrottled, 1)
+	}
+
+	var convErr *exchanges.ConversionError
+	if errors.As(err, &convErr) {
+		if err = i.handleConversionError(convErr); err != nil {
+			convErr = nil
+		}
+	}
 
 	if err != nil {
 		span.SetTag("error", true)
@@ -69,9 +194,31 @@ func (i *Importer) fetchTickers(ctx context.Context) ([]exchanges.Ticker, error)
 		i.telemetry.IncrementCounter(telemetryTickFetchErrors, 1)
 	} else {
 		span.SetTag("tickers.count", len(tickers))
+		if tagger, ok := i.telemetry.(telemetry.Tagger); ok {
+			tagger.SetTag(telemetry.TagSymbols, telemetry.SymbolCountBucket(len(tickers)))
+		}
+	}
+
+	return tickers, convErr, err
+}
+
+// handleConversionError decides whether a partially converted fetch is trustworthy.
+// A small share of failures is reported and the tick is built from the remaining tickers,
+// otherwise the error is returned and the tick is skipped
+func (i *Importer) handleConversionError(convErr *exchanges.ConversionError) error {
+	i.telemetry.Gauge(telemetryTickFetchConversionFailures, float64(convErr.Failed))
+
+	if ratio := convErr.FailureRatio(); ratio > i.maxConversionFailureRatio {
+		return fmt.Errorf("%.1f%% of tickers failed conversion: %w", ratio*100, convErr)
 	}
 
-	return tickers, err
+	i.publishDegraded(eventbus.StageConvertTickers, convErr)
+	i.logger.Warn("Some tickers failed conversion",
+		zap.Int("failed", convErr.Failed),
+		zap.Int("total", convErr.Total),
+		zap.Error(convErr),
+	)
+	return nil
 }
 
 // buildTick calculates indicators and populates domain.Tick.
@@ -89,7 +236,12 @@ func (i *Importer) buildTick(ctx context.Context, tick *domain.Tick, eTickers []
 	liquidationsHistory, err := i.liquidationRepository.GetLiquidationsHistory(ctx, tick.StartAt)
 	if err != nil {
 		i.logger.Error("Error getting liquidations history", zap.Error(err))
+		tick.AddError(domain.TickStageLiquidations, err)
+	} else {
+		// liquidations arriving from now on are too late for the windows of this tick
+		i.watermark.advance(tick.StartAt)
 	}
+	i.telemetry.Gauge(telemetryLiquidationsWatermarkSkew, float64(i.watermark.maxSkew(time.Now()).Milliseconds()))
 	tick.LL1 = liquidationsHistory.LongLiquidations1s
 	tick.LL2 = liquidationsHistory.LongLiquidations2s
 	tick.LL5 = liquidationsHistory.LongLiquidations5s
@@ -100,22 +252,58 @@ func (i *Importer) buildTick(ctx context.Context, tick *domain.Tick, eTickers []
 
 	i.telemetry.Timing(telemetryTickBuildSetLiquidations, time.Since(liqStart))
 
+	i.setTradeVolumes(tick)
+
+	// USD rates of the quote assets are derived from the reference tickers of the same fetch
+	rates := usd.NewRates(eTickers)
+	i.usdRates.Store(rates)
+	i.observeSymbols(eTickers, tick.StartAt)
+
+	// Tickers quoted in other assets than the imported ones are dropped once they priced the quote assets
+	kept := i.symbolMapper.Filter(eTickers)
+	if len(i.symbolMapper.Quotes) > 0 {
+		i.telemetry.Gauge(telemetryTickBuildTickersOtherQuote, float64(len(eTickers)-len(kept)))
+	}
+	eTickers = kept
+
+	// Dust pairs are either dropped here or marked illiquid by buildTicker
+	eTickers, illiquid := i.liquidity.apply(eTickers, rates)
+	if i.liquidity.enabled() {
+		i.telemetry.Gauge(telemetryTickBuildTickersIlliquid, float64(illiquid))
+	}
+
 	// Handle tickers data in parallel
 	wg := sync.WaitGroup{}
 	numWorkers := runtime.NumCPU()
 	taskChannel := make(chan exchanges.Ticker, numWorkers)
 	resultChannel := make(chan *domain.Ticker, len(eTickers))
-	worker := func(tasks <-chan exchanges.Ticker, results chan<- *domain.Ticker) {
+	latencies := make([][]symbolDuration, numWorkers)
+	var unconvertible atomic.Int64
+	var failures stageFailures
+	// The workers get a copy of the tick, the tick itself is updated with the skipped symbols while they run
+	current := *tick
+	worker := func(id int, tasks <-chan exchanges.Ticker, results chan<- *domain.Ticker) {
 		defer func() {
 			if r := recover(); r != nil {
 				i.logger.Error("Worker panic", zap.Any("panic", r))
+				failures.add(fmt.Errorf("worker panic: %v", r))
 			}
 		}()
 
 		for exchangeTicker := range tasks {
-			ticker, err := i.buildTicker(*tick, lastTick, exchangeTicker)
+			buildStart := time.Now()
+			ticker, err := i.buildTicker(current, lastTick, exchangeTicker, rates)
+			latencies[id] = append(latencies[id], symbolDuration{
+				symbol:   domain.TickerName(exchangeTicker.Symbol),
+				duration: time.Since(buildStart),
+			})
+			if errors.Is(err, errNoUSDRate) {
+				unconvertible.Add(1)
+				continue
+			}
 			if err != nil {
 				i.logger.Error("Error building ticker", zap.Error(err))
+				failures.add(err)
 				continue
 			}
 			results <- ticker
@@ -124,13 +312,19 @@ func (i *Importer) buildTick(ctx context.Context, tick *domain.Tick, eTickers []
 
 	for w := 0; w < numWorkers; w++ {
 		wg.Add(1)
-		go func() {
+		go func(id int) {
 			defer wg.Done()
-			worker(taskChannel, resultChannel)
-		}()
+			worker(id, taskChannel, resultChannel)
+		}(w)
 	}
 
-	for _, eTicker := range eTickers {
+	// Priority symbols are built first, the others are skipped once the tick runs past its deadline
+	eTickers, priorityCount := i.priority.order(eTickers)
+	for idx, eTicker := range eTickers {
+		if idx >= priorityCount && i.priority.expired(tick.StartAt) {
+			tick.Skipped = skippedSymbols(eTickers[idx:])
+			break
+		}
 		taskChannel <- eTicker
 	}
 	close(taskChannel)
@@ -144,12 +338,51 @@ func (i *Importer) buildTick(ctx context.Context, tick *domain.Tick, eTickers []
 		tick.SetTicker(processedTicker)
 		tickersProcessed++
 	}
+	if err := failures.err(); err != nil {
+		tick.AddError(domain.TickStageBuildTickers, err)
+	}
 
 	i.telemetry.Gauge(telemetryTickBuildTickersProcessed, float64(tickersProcessed))
+	if i.priority != nil {
+		i.telemetry.Gauge(telemetryTickBuildTickersSkipped, float64(len(tick.Skipped)))
+	}
+	if len(tick.Skipped) > 0 {
+		i.logger.Warn("Tick deadline reached, low-priority symbols skipped",
+			zap.Int("skipped", len(tick.Skipped)),
+			zap.Duration("deadline", i.priority.deadline),
+		)
+	}
+	if i.normalizeUSD {
+		i.telemetry.Gauge(telemetryTickBuildTickersUnconvertible, float64(unconvertible.Load()))
+	}
+	i.recordTickerLatencies(latencies)
 
 	// Calculate tick indicators
 	indicatorsStart := time.Now()
 	i.addTickHistory(tick)
-	tick.CalculateIndicators(i.tickHistory.buffer)
+	tick.CalculateIndicators(i.tickHistory.buffer, i.precision)
 	i.telemetry.Timing(telemetryTickCalculateIndicators, time.Since(indicatorsStart))
+	i.trimHistory()
+}
+
+// recordTickerLatencies merges per-worker buildTicker measurements, reports each of them as a timing
+// and the slowest of the tick. The symbols are left out of the metrics, SlowestSymbols serves them
+func (i *Importer) recordTickerLatencies(perWorker [][]symbolDuration) {
+	var slowest time.Duration
+	for _, samples := range perWorker {
+		i.latency.record(samples)
+		for _, s := range samples {
+			i.telemetry.Timing(telemetryTickerBuildLatency, s.duration)
+			slowest = max(slowest, s.duration)
+		}
+	}
+	if slowest > 0 {
+		i.telemetry.Timing(telemetryTickerBuildSlowest, slowest)
+	}
+}
+
+// inMaintenance reports whether a tick started at startAt falls within a maintenance window of the exchange
+func (i *Importer) inMaintenance(startAt time.Time) bool {
+	_, ok := i.maintenance.Active(startAt)
+	return ok
 }
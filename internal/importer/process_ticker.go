@@ -1,26 +1,68 @@
 package importer
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/usd"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
 )
 
-func (i *Importer) buildTicker(currTick domain.Tick, lastTick *domain.Tick, eTicker exchanges.Ticker) (*domain.Ticker, error) {
+// errNoUSDRate is returned for tickers whose quote asset can't be normalized to USD
+var errNoUSDRate = errors.New("no USD rate for the quote asset")
+
+func (i *Importer) buildTicker(currTick domain.Tick, lastTick *domain.Tick, eTicker exchanges.Ticker, rates *usd.Rates) (*domain.Ticker, error) {
 	ticker := &domain.Ticker{
 		Symbol:    domain.TickerName(eTicker.Symbol),
 		Ask:       eTicker.AskPrice,
 		Bid:       eTicker.BidPrice,
 		EventAt:   eTicker.EventAt,
 		CreatedAt: currTick.StartAt,
+		Illiquid:  i.liquidity.isIlliquid(eTicker, rates),
+
+		EventAtSource: string(eTicker.EventAtSource),
+
+		LastLiquidation: i.lastLiquidations.at(domain.TickerName(eTicker.Symbol), currTick.StartAt),
+
+		OpenInterest: eTicker.OpenInterest,
+	}
+	if canonical, ok := i.symbolMapper.Map(eTicker); ok {
+		ticker.Canonical = domain.TickerName(canonical)
+	}
+	if ticker.OpenInterest == 0 {
+		ticker.OpenInterest = i.openInterest.at(ticker.Symbol, currTick.StartAt)
+	}
+	if meta, ok := i.symbolMeta.Lookup(eTicker.Symbol); ok {
+		ticker.Categories = meta.Categories
+		ticker.Tier = meta.Tier
+	}
+	i.books.update(ticker.Symbol, eTicker.BidPrice, eTicker.AskPrice, currTick.StartAt)
+	if notional, ok := rates.TickerNotional(eTicker); ok {
+		ticker.Notional = mathutils.RoundTo(notional, i.precision.Notional)
+	}
+
+	// Prices quoted in other assets are converted so their changes are comparable in market averages
+	if i.normalizeUSD && eTicker.Quote != "" && !usd.IsBasis(eTicker.Quote) {
+		rate, ok := rates.Rate(eTicker.Quote)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w %s", eTicker.Symbol, errNoUSDRate, eTicker.Quote)
+		}
+		ticker.Ask *= rate
+		ticker.Bid *= rate
+		ticker.QuoteRate = rate
 	}
 
 	if err := ticker.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid ticker data: %v", err)
 	}
 
-	i.addTickerHistory(ticker)
-	ticker.CalculateIndicators(i.tickerHistory.Get(ticker.Symbol), lastTick)
+	ticker.Stats24h = i.rollingStats.addTicker(ticker)
+
+	if bar := i.addTickerHistory(ticker); bar != nil && i.bars != nil {
+		i.bars.close(bar)
+	}
+	ticker.CalculateIndicators(i.tickerHistory.Get(ticker.Symbol), lastTick, i.precision)
 	return ticker, nil
 }
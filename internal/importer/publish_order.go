@@ -0,0 +1,17 @@
+package importer
+
+// PublishOrder selects when a tick is announced to the event bus relative to storing it
+type PublishOrder string
+
+const (
+	// PublishAfterStore stores the tick first and publishes it once stored,
+	// so consumers never see a tick that failed to persist
+	PublishAfterStore PublishOrder = "store-first"
+
+	// PublishBeforeStore publishes the tick as soon as it's validated and stores it afterwards,
+	// for latency-sensitive consumers accepting ticks that may fail to persist
+	PublishBeforeStore PublishOrder = "notify-first"
+)
+
+// PublishOrders lists the supported publish orders
+var PublishOrders = []PublishOrder{PublishAfterStore, PublishBeforeStore}
@@ -0,0 +1,76 @@
+package importer
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+const (
+	// DefaultQualityFetchBudget is the fetch duration past which the fetch lowers the tick quality
+	DefaultQualityFetchBudget = 500 * time.Millisecond
+
+	// DefaultQualityLiquidationsStaleAfter is the silence of the liquidation stream past which it lowers the tick quality
+	DefaultQualityLiquidationsStaleAfter = 2 * time.Minute
+)
+
+// QualityConfig configures the quality score of the ticks, see domain.TickQuality
+type QualityConfig struct {
+	FetchBudget            time.Duration // 0 uses DefaultQualityFetchBudget
+	LiquidationsStaleAfter time.Duration // 0 uses DefaultQualityLiquidationsStaleAfter
+}
+
+// withDefaults returns the config with the defaults of the unset fields
+func (c QualityConfig) withDefaults() QualityConfig {
+	if c.FetchBudget <= 0 {
+		c.FetchBudget = DefaultQualityFetchBudget
+	}
+	if c.LiquidationsStaleAfter <= 0 {
+		c.LiquidationsStaleAfter = DefaultQualityLiquidationsStaleAfter
+	}
+	return c
+}
+
+// streamFreshness keeps the time of the latest message of a stream
+type streamFreshness struct {
+	at atomic.Int64 // unix nanoseconds, 0 before the stream started
+}
+
+// touch records a message, or the start of the stream, received at the given time
+func (f *streamFreshness) touch(at time.Time) {
+	f.at.Store(at.UnixNano())
+}
+
+// age returns the time since the latest message at now, false before the stream started
+func (f *streamFreshness) age(now time.Time) (time.Duration, bool) {
+	at := f.at.Load()
+	if at == 0 {
+		return 0, false
+	}
+	return max(now.Sub(time.Unix(0, at)), 0), true
+}
+
+// scoreTick sets the quality of a built tick and reports its score and components
+func (i *Importer) scoreTick(tick, previous *domain.Tick, convErr *exchanges.ConversionError) {
+	quality := domain.TickQuality{
+		Updated:         tick.UpdatedShare(previous),
+		FetchBudget:     float64(tick.FetchDuration) / float64(i.quality.FetchBudget.Milliseconds()),
+		LiquidationsAge: -1,
+	}
+	if convErr != nil {
+		quality.ConversionFailures = convErr.FailureRatio()
+	}
+	if age, ok := i.liquidationsFreshness.age(tick.StartAt); ok {
+		quality.LiquidationsAge = age.Milliseconds()
+	}
+	quality.CalculateScore(i.quality.LiquidationsStaleAfter)
+	tick.Quality = quality
+
+	i.telemetry.Gauge(telemetryTickQualityScore, quality.Score)
+	for component, score := range quality.Components(i.quality.LiquidationsStaleAfter) {
+		i.telemetry.Gauge(telemetryTickQualityComponent, score, fmt.Sprintf("component:%s", component))
+	}
+}
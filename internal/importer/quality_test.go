@@ -0,0 +1,59 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamFreshness(t *testing.T) {
+	var freshness streamFreshness
+	_, ok := freshness.age(time.Now())
+	assert.False(t, ok, "not started")
+
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	freshness.touch(at)
+	age, ok := freshness.age(at.Add(3 * time.Second))
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, age)
+	age, _ = freshness.age(at.Add(-time.Second))
+	assert.Zero(t, age, "never negative")
+}
+
+func TestScoreTick(t *testing.T) {
+	ts := setupTest()
+	require.Equal(t, DefaultQualityFetchBudget, ts.importer.quality.FetchBudget)
+	ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+		return []exchanges.Ticker{
+			{Symbol: "BTCUSDT", AskPrice: 50000, BidPrice: 49900, EventAt: time.Now()},
+			{Symbol: "ETHUSDT", AskPrice: 3000, BidPrice: 2990, EventAt: time.Now()},
+		}, nil
+	}
+
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	tick, ok := ts.importer.LatestTick()
+	require.True(t, ok)
+	require.Len(t, tick.Data, 2)
+	assert.Equal(t, 1.0, tick.Quality.Updated)
+	assert.Equal(t, int64(-1), tick.Quality.LiquidationsAge, "liquidations aren't streamed")
+	assert.Equal(t, 1.0, tick.Quality.Score)
+
+	// ETHUSDT fails conversion
+	ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+		return []exchanges.Ticker{{Symbol: "BTCUSDT", AskPrice: 50000, BidPrice: 49900, EventAt: time.Now()}},
+			&exchanges.ConversionError{Failed: 1, Total: 2}
+	}
+	ts.importer.maxConversionFailureRatio = 0.5
+	ts.importer.liquidationsFreshness.touch(time.Now().Add(-3 * time.Minute))
+
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	tick, _ = ts.importer.LatestTick()
+	assert.Equal(t, 0.5, tick.Quality.Updated)
+	assert.Equal(t, 0.5, tick.Quality.ConversionFailures)
+	assert.GreaterOrEqual(t, tick.Quality.LiquidationsAge, (3 * time.Minute).Milliseconds())
+	assert.Less(t, tick.Quality.Score, 0.75)
+}
@@ -0,0 +1,129 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils"
+)
+
+// recentLiquidationsSize is the number of the latest liquidations kept in memory to be queried
+const recentLiquidationsSize = 10_000
+
+// recentLiquidations keeps the latest liquidations of the live stream, oldest first
+type recentLiquidations struct {
+	mu   sync.RWMutex
+	ring *utils.Ring[domain.Liquidation]
+
+	// from is the time since which every liquidation is kept: the creation of the buffer, then the event time
+	// of the latest evicted liquidation
+	from time.Time
+}
+
+func newRecentLiquidations(size int) *recentLiquidations {
+	return &recentLiquidations{ring: utils.NewRing[domain.Liquidation](size), from: time.Now()}
+}
+
+// add keeps the liquidation, evicting the oldest one when full
+func (r *recentLiquidations) add(liq domain.Liquidation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ring.Len() == r.ring.Cap() {
+		if evicted := r.ring.At(0); evicted.EventAt.After(r.from) {
+			r.from = evicted.EventAt
+		}
+	}
+	r.ring.Push(liq)
+}
+
+// since returns the kept liquidations that happened since the given time, oldest first, and whether they're
+// all the liquidations streamed since then
+func (r *recentLiquidations) since(since time.Time) ([]domain.Liquidation, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var liquidations []domain.Liquidation
+	r.ring.Range(func(_ int, liq domain.Liquidation) bool {
+		if !liq.EventAt.Before(since) {
+			liquidations = append(liquidations, liq)
+		}
+		return true
+	})
+	return liquidations, !since.Before(r.from)
+}
+
+// Ticks returns at most limit ticks started since the given time, oldest first. They're read from the in-memory
+// history when it goes back that far, see publishedTicks, from the tick repository otherwise: a page of limit ticks
+// when it can read one, else the range of limit ticks at most, one per TickInterval
+func (i *Importer) Ticks(ctx context.Context, since time.Time, limit int) ([]domain.Tick, error) {
+	if i.tickHistory.Len() > 0 && !i.tickHistory.At(0).StartAt.After(since) {
+		return i.publishedTicks(since, limit), nil
+	}
+
+	var ticks []domain.Tick
+	var err error
+	if repo, ok := i.tickRepository.(domain.TickPageRepository); ok {
+		ticks, err = repo.GetPage(ctx, since, limit)
+	} else {
+		ticks, err = i.tickRepository.GetRange(ctx, since, since.Add(time.Duration(limit)*TickInterval))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ticks since %s: %w", since.Format(time.RFC3339), err)
+	}
+	if len(ticks) > limit {
+		ticks = ticks[:limit]
+	}
+	return ticks, nil
+}
+
+// publishedTicks returns copies of at most limit (all when not positive) ticks of the in-memory history started
+// since the given time and published already, oldest first, the latest one being its LatestTick snapshot. The ticks
+// started after it are left out: the one in flight is still being built, validated and stored
+func (i *Importer) publishedTicks(since time.Time, limit int) []domain.Tick {
+	latest, published := i.LatestTick()
+	if !published {
+		return nil
+	}
+	var ticks []domain.Tick
+	for _, tick := range i.tickHistory.buffer.Values() {
+		if limit > 0 && len(ticks) == limit {
+			break
+		}
+		switch {
+		case tick.StartAt.Before(since):
+		case tick.StartAt.Equal(latest.StartAt):
+			ticks = append(ticks, *latest)
+		case tick.StartAt.Before(latest.StartAt):
+			ticks = append(ticks, i.tickerHistory.CopyTick(tick))
+		}
+	}
+	return ticks
+}
+
+// TickerHistory returns a copy of the in-memory history of the symbol, one ticker per minute, oldest first,
+// the last one is the live minute. It's false for a symbol without history
+func (i *Importer) TickerHistory(symbol domain.TickerName) ([]domain.Ticker, bool) {
+	return i.tickerHistory.Lookup(symbol)
+}
+
+// Liquidations returns the liquidations that happened since the given time, oldest first. They're read from memory
+// when it holds them all, from the liquidation repository when it can read them back, otherwise the ones in memory
+// are returned and complete is false
+func (i *Importer) Liquidations(ctx context.Context, since time.Time) (liquidations []domain.Liquidation, complete bool, err error) {
+	liquidations, complete = i.recentLiquidations.since(since)
+	if complete {
+		return liquidations, true, nil
+	}
+
+	repo, ok := i.liquidationRepository.(domain.LiquidationRangeRepository)
+	if !ok {
+		return liquidations, false, nil
+	}
+	stored, err := repo.GetRange(ctx, since, time.Now())
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get liquidations since %s: %w", since.Format(time.RFC3339), err)
+	}
+	return stored, true, nil
+}
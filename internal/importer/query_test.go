@@ -0,0 +1,161 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentLiquidations(t *testing.T) {
+	at := time.Now().Add(time.Minute)
+	recent := newRecentLiquidations(2)
+	for n := range 3 {
+		recent.add(domain.Liquidation{EventAt: at.Add(time.Duration(n) * time.Second)})
+	}
+
+	liquidations, complete := recent.since(at.Add(time.Second))
+	assert.True(t, complete)
+	assert.Len(t, liquidations, 2)
+
+	liquidations, complete = recent.since(at.Add(-time.Second))
+	assert.False(t, complete, "the oldest liquidation was evicted")
+	assert.Len(t, liquidations, 2)
+}
+
+func TestImporterTicks(t *testing.T) {
+	ts := setupSnapshotTest()
+	for range 3 {
+		require.NoError(t, ts.importer.importTick(context.Background()))
+	}
+	require.Equal(t, 3, ts.importer.tickHistory.Len())
+	since := ts.importer.tickHistory.At(0).StartAt
+
+	ticks, err := ts.importer.Ticks(context.Background(), since, 2)
+	require.NoError(t, err)
+	assert.Len(t, ticks, 2, "the in-memory history goes back far enough")
+	assert.Empty(t, ts.tickRepo.GetRangeCalls())
+
+	latest, ok := ts.importer.LatestTick()
+	require.True(t, ok)
+	ticks, err = ts.importer.Ticks(context.Background(), latest.StartAt, 10)
+	require.NoError(t, err)
+	require.Len(t, ticks, 1)
+	assert.Equal(t, *latest, ticks[0], "the published tick is served from its snapshot")
+
+	stored := []domain.Tick{{StartAt: since.Add(-time.Hour)}, {StartAt: since.Add(-time.Minute)}}
+	ts.tickRepo.GetRangeFunc = func(ctx context.Context, from, to time.Time) ([]domain.Tick, error) {
+		return stored, nil
+	}
+	ticks, err = ts.importer.Ticks(context.Background(), since.Add(-2*time.Hour), 1)
+	require.NoError(t, err)
+	assert.Equal(t, stored[:1], ticks, "older ticks are read from the repository")
+	require.Len(t, ts.tickRepo.GetRangeCalls(), 1)
+	assert.Equal(t, since.Add(-2*time.Hour+TickInterval), ts.tickRepo.GetRangeCalls()[0].To, "the range of limit ticks is read")
+	assert.Empty(t, ts.tickRepo.GetHistorySinceCalls())
+
+	pages := &domainMocks.TickPageRepositoryMock{
+		GetPageFunc: func(ctx context.Context, since time.Time, limit int) ([]domain.Tick, error) {
+			return stored[:limit], nil
+		},
+	}
+	ts.importer.tickRepository = struct {
+		*domainMocks.TickRepositoryMock
+		*domainMocks.TickPageRepositoryMock
+	}{ts.tickRepo, pages}
+	ticks, err = ts.importer.Ticks(context.Background(), since.Add(-2*time.Hour), 2)
+	require.NoError(t, err)
+	assert.Equal(t, stored, ticks, "a page is read when the repository can")
+	require.Len(t, pages.GetPageCalls(), 1)
+	assert.Equal(t, 2, pages.GetPageCalls()[0].Limit)
+	assert.Len(t, ts.tickRepo.GetRangeCalls(), 1)
+
+	history, ok := ts.importer.TickerHistory("BTCUSDT")
+	require.True(t, ok)
+	assert.Len(t, history, 1)
+	_, ok = ts.importer.TickerHistory("DOGEUSDT")
+	assert.False(t, ok)
+	assert.Equal(t, 2, ts.importer.tickerHistory.Len(), "looking up a symbol creates no history")
+}
+
+func TestImporterTicks_Published(t *testing.T) {
+	ts := setupSnapshotTest()
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	since := ts.importer.tickHistory.At(0).StartAt
+
+	inFlight := &domain.Tick{StartAt: since.Add(time.Second)}
+	ts.importer.addTickHistory(inFlight)
+	ticks, err := ts.importer.Ticks(context.Background(), since, 10)
+	require.NoError(t, err)
+	assert.Len(t, ticks, 1, "the tick in flight isn't served before it's published")
+}
+
+// TestImporterTicks_ConcurrentImport reads the ticks while they're imported, run it with -race
+func TestImporterTicks_ConcurrentImport(t *testing.T) {
+	ts := setupSnapshotTest()
+	// enough tickers for the indicators of the tick in flight to take a while
+	tickers := make([]exchanges.Ticker, 1000)
+	for n := range tickers {
+		tickers[n] = exchanges.Ticker{Symbol: fmt.Sprintf("S%dUSDT", n), AskPrice: 100, BidPrice: 99, EventAt: time.Now()}
+	}
+	ts.exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+		return tickers, nil
+	}
+	require.NoError(t, ts.importer.importTick(context.Background()))
+	since := ts.importer.tickHistory.At(0).StartAt
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 20 {
+			assert.NoError(t, ts.importer.importTick(context.Background()))
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			ticks, err := ts.importer.Ticks(context.Background(), since, 100)
+			require.NoError(t, err)
+			assert.NotEmpty(t, ticks)
+			return
+		default:
+			_, err := ts.importer.Ticks(context.Background(), since, 100)
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestImporterLiquidations(t *testing.T) {
+	ts := setupTest()
+	at := time.Now()
+	ts.importer.recentLiquidations = newRecentLiquidations(1)
+	ts.importer.recentLiquidations.from = at.Add(-time.Hour)
+	ts.importer.recentLiquidations.add(domain.Liquidation{EventAt: at.Add(-2 * time.Second)})
+	ts.importer.recentLiquidations.add(domain.Liquidation{EventAt: at.Add(-time.Second)})
+
+	liquidations, complete, err := ts.importer.Liquidations(context.Background(), at.Add(-time.Second))
+	require.NoError(t, err)
+	assert.True(t, complete)
+	assert.Len(t, liquidations, 1)
+
+	liquidations, complete, err = ts.importer.Liquidations(context.Background(), at.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.False(t, complete, "the repository can't read liquidations back")
+	assert.Len(t, liquidations, 1)
+
+	stored := []domain.Liquidation{{EventAt: at.Add(-30 * time.Second)}, {EventAt: at.Add(-time.Second)}}
+	ts.importer.liquidationRepository = struct {
+		*domainMocks.LiquidationRepositoryMock
+		*domainMocks.LiquidationRangeRepositoryMock
+	}{ts.liqRepo, rangeLiquidationRepository(stored)}
+	liquidations, complete, err = ts.importer.Liquidations(context.Background(), at.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, stored, liquidations)
+}
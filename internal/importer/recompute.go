@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"go.uber.org/zap"
+)
+
+// DefaultRecomputeWindow is the time range of stored ticks loaded and written per batch
+const DefaultRecomputeWindow = time.Hour
+
+// RecomputeConfig holds the configuration of the indicator recomputation job
+type RecomputeConfig struct {
+	Ticks     domain.TickRepository           // source of the stored ticks
+	Output    domain.RecomputedTickRepository // destination of the versioned results
+	Window    time.Duration                   // batch size, 0 uses DefaultRecomputeWindow
+	Precision *domain.Precision               // decimals of the recomputed indicators, nil uses domain.DefaultPrecision
+	Telemetry telemetry.Provider
+	Logger    *zap.Logger
+}
+
+// RecomputeResult summarizes a recomputation run
+type RecomputeResult struct {
+	Version         int // IndicatorsVersion the results were written with
+	Ticks           int // ticks written
+	TickersSkipped  int // stored tickers failing validation
+	WarmupTicks     int // ticks preceding the range replayed to fill the history
+	IndicatorsDrift int // written ticks whose stored indicators were calculated with another version
+}
+
+// Recomputer recalculates the indicators of stored ticks by replaying them through the same history
+// structures the live import uses, so backfilled ranges and indicator changes can be applied to old data
+type Recomputer struct {
+	ticks     domain.TickRepository
+	output    domain.RecomputedTickRepository
+	window    time.Duration
+	precision domain.Precision
+	telemetry telemetry.Provider
+	logger    *zap.Logger
+
+	tickHistory   *tickHistory
+	tickerHistory *tickerHistoryMap
+}
+
+// NewRecomputer creates a new Recomputer
+func NewRecomputer(cfg RecomputeConfig) *Recomputer {
+	r := &Recomputer{
+		ticks:     cfg.Ticks,
+		output:    cfg.Output,
+		window:    cfg.Window,
+		precision: domain.DefaultPrecision(),
+		telemetry: cfg.Telemetry,
+		logger:    cfg.Logger,
+	}
+	if r.window <= 0 {
+		r.window = DefaultRecomputeWindow
+	}
+	if cfg.Precision != nil {
+		r.precision = *cfg.Precision
+	}
+	if r.telemetry == nil {
+		r.telemetry = &telemetry.NoopProvider{}
+	}
+	if r.logger == nil {
+		r.logger = zap.NewNop()
+	}
+	return r
+}
+
+// Run recomputes the indicators of the ticks created within [from, to) and writes them with the current
+// domain.IndicatorsVersion. The ticks of the preceding MaxTickHistory minutes are replayed first so the
+// first results of the range see the same history the live import would have seen
+func (r *Recomputer) Run(ctx context.Context, from, to time.Time) (RecomputeResult, error) {
+	result := RecomputeResult{Version: domain.IndicatorsVersion}
+	if !from.Before(to) {
+		return result, fmt.Errorf("invalid range: from %s must be before to %s", from, to)
+	}
+
+	r.tickHistory = newTickHistory(domain.MaxTickHistory)
+	r.tickerHistory = newTickerHistoryMap()
+
+	warmup, err := r.ticks.GetRange(ctx, from.Add(-domain.MaxTickHistory*time.Minute), from)
+	if err != nil {
+		return result, fmt.Errorf("loading warmup ticks: %w", err)
+	}
+	for _, stored := range warmup {
+		r.replay(stored)
+	}
+	result.WarmupTicks = len(warmup)
+
+	for start := from; start.Before(to); start = start.Add(r.window) {
+		end := start.Add(r.window)
+		if end.After(to) {
+			end = to
+		}
+		if err := r.recomputeBatch(ctx, start, end, &result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// recomputeBatch replays and writes the ticks created within [start, end)
+func (r *Recomputer) recomputeBatch(ctx context.Context, start, end time.Time, result *RecomputeResult) error {
+	batchStart := time.Now()
+
+	stored, err := r.ticks.GetRange(ctx, start, end)
+	if err != nil {
+		return fmt.Errorf("loading ticks %s - %s: %w", start, end, err)
+	}
+
+	recomputed := make([]domain.Tick, 0, len(stored))
+	for _, s := range stored {
+		tick, skipped := r.replay(s)
+		recomputed = append(recomputed, tick)
+		result.TickersSkipped += skipped
+		if s.IndicatorsVersion != domain.IndicatorsVersion {
+			result.IndicatorsDrift++
+		}
+	}
+
+	if err := r.output.SaveMany(ctx, recomputed); err != nil {
+		return fmt.Errorf("saving ticks %s - %s: %w", start, end, err)
+	}
+	result.Ticks += len(recomputed)
+
+	r.telemetry.IncrementCounter(telemetryRecomputeTicks, int64(len(recomputed)))
+	r.telemetry.Timing(telemetryRecomputeBatchDuration, time.Since(batchStart))
+	r.logger.Info("Recomputed ticks",
+		zap.Time("from", start),
+		zap.Time("to", end),
+		zap.Int("ticks", len(recomputed)),
+		zap.Int("version", domain.IndicatorsVersion),
+	)
+	return nil
+}
+
+// replay rebuilds a stored tick from its raw prices the way buildTick does and returns a detached copy,
+// the history keeps mutating its tickers while the current minute is in progress
+func (r *Recomputer) replay(stored domain.Tick) (domain.Tick, int) {
+	tick := &domain.Tick{
+		StartAt:           stored.StartAt,
+		FetchedAt:         stored.FetchedAt,
+		CreatedAt:         stored.CreatedAt,
+		FetchDuration:     stored.FetchDuration,
+		HandlingDuration:  stored.HandlingDuration,
+		EventSkew:         stored.EventSkew,
+		LL1:               stored.LL1,
+		LL2:               stored.LL2,
+		LL5:               stored.LL5,
+		LL60:              stored.LL60,
+		SL1:               stored.SL1,
+		SL2:               stored.SL2,
+		SL10:              stored.SL10,
+		BuyVolume1:        stored.BuyVolume1,
+		SellVolume1:       stored.SellVolume1,
+		IndicatorsVersion: domain.IndicatorsVersion,
+		Maintenance:       stored.Maintenance,
+		Quality:           stored.Quality,
+		Errors:            stored.Errors,
+		Data:              make(map[domain.TickerName]*domain.Ticker, len(stored.Data)),
+	}
+
+	lastTick, _ := r.tickHistory.Last()
+	skipped := 0
+	for _, s := range stored.Data {
+		ticker := &domain.Ticker{
+			Symbol:    s.Symbol,
+			Ask:       s.Ask,
+			Bid:       s.Bid,
+			EventAt:   s.EventAt,
+			CreatedAt: tick.StartAt,
+			Illiquid:  s.Illiquid,
+			QuoteRate: s.QuoteRate,
+			Notional:  s.Notional,
+
+			EventAtSource: s.EventAtSource,
+			OpenInterest:  s.OpenInterest,
+			Categories:    s.Categories,
+			Tier:          s.Tier,
+		}
+		if err := ticker.Validate(); err != nil {
+			skipped++
+			continue
+		}
+
+		r.tickerHistory.UpdateTicker(ticker)
+		ticker.CalculateIndicators(r.tickerHistory.Get(ticker.Symbol), lastTick, r.precision)
+		tick.SetTicker(ticker)
+	}
+
+	if last, exists := r.tickHistory.Last(); !exists || !last.StartAt.After(tick.StartAt) {
+		r.tickHistory.Push(tick)
+	}
+	tick.CalculateIndicators(r.tickHistory.buffer, r.precision)
+
+	snapshot := *tick
+	snapshot.Data = make(map[domain.TickerName]*domain.Ticker, len(tick.Data))
+	for symbol, ticker := range tick.Data {
+		t := *ticker
+		snapshot.Data[symbol] = &t
+	}
+	return snapshot, skipped
+}
@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedTicks returns one stored tick per minute with a rising BTCUSDT price and stale indicators
+func storedTicks(start time.Time, minutes int) []domain.Tick {
+	ticks := make([]domain.Tick, 0, minutes)
+	for m := 0; m < minutes; m++ {
+		at := start.Add(time.Duration(m) * time.Minute)
+		price := 100 + float64(m)
+		ticks = append(ticks, domain.Tick{
+			StartAt:   at,
+			FetchedAt: at,
+			CreatedAt: at.Add(time.Second),
+			LL1:       int64(m),
+			Data: map[domain.TickerName]*domain.Ticker{
+				"BTCUSDT": {Symbol: "BTCUSDT", Ask: price + 0.5, Bid: price, EventAt: at, CreatedAt: at, RSI20: 999},
+			},
+		})
+	}
+	return ticks
+}
+
+// rangeTickRepository serves GetRange from the given ticks
+func rangeTickRepository(ticks []domain.Tick) *domainMocks.TickRepositoryMock {
+	return &domainMocks.TickRepositoryMock{
+		GetRangeFunc: func(ctx context.Context, from, to time.Time) ([]domain.Tick, error) {
+			var result []domain.Tick
+			for _, tick := range ticks {
+				if !tick.CreatedAt.Before(from) && tick.CreatedAt.Before(to) {
+					result = append(result, tick)
+				}
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestRecomputer_Run(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := storedTicks(start, 30)
+
+	var saved [][]domain.Tick
+	output := &domainMocks.RecomputedTickRepositoryMock{
+		SaveManyFunc: func(ctx context.Context, ticks []domain.Tick) error {
+			saved = append(saved, ticks)
+			return nil
+		},
+	}
+	recomputer := NewRecomputer(RecomputeConfig{
+		Ticks:  rangeTickRepository(stored),
+		Output: output,
+		Window: 2 * time.Minute,
+	})
+
+	from := start.Add(25 * time.Minute)
+	result, err := recomputer.Run(context.Background(), from, start.Add(30*time.Minute))
+	require.NoError(t, err)
+
+	assert.Equal(t, RecomputeResult{
+		Version:         domain.IndicatorsVersion,
+		Ticks:           5,
+		WarmupTicks:     25,
+		IndicatorsDrift: 5,
+	}, result)
+	require.Len(t, saved, 3, "the range is written in windows of 2 minutes")
+	assert.Len(t, saved[0], 2)
+	assert.Len(t, saved[2], 1)
+
+	first := saved[0][0]
+	assert.Equal(t, from, first.StartAt)
+	assert.Equal(t, domain.IndicatorsVersion, first.IndicatorsVersion)
+	assert.Equal(t, int64(25), first.LL1, "liquidations are kept as stored")
+
+	ticker := first.Data["BTCUSDT"]
+	require.NotNil(t, ticker)
+	assert.Equal(t, mathutils.PercDiff(125, 124, 2), ticker.Change1m)
+	assert.Equal(t, mathutils.PercDiff(125, 105, 2), ticker.Change20m, "the warmup provides 20 minutes of history")
+	assert.Equal(t, float64(100), ticker.RSI20, "stale stored indicators are replaced")
+	assert.Equal(t, ticker.Change1m, first.Avg.Change1m)
+	assert.Equal(t, int16(1), first.Avg.TickersCount)
+}
+
+func TestRecomputer_RunSkipsInvalidTickers(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := storedTicks(start, 2)
+	stored[1].Data["ETHUSDT"] = &domain.Ticker{Symbol: "ETHUSDT", Ask: 10, Bid: 11, EventAt: start, CreatedAt: start}
+
+	var saved []domain.Tick
+	recomputer := NewRecomputer(RecomputeConfig{
+		Ticks: rangeTickRepository(stored),
+		Output: &domainMocks.RecomputedTickRepositoryMock{
+			SaveManyFunc: func(ctx context.Context, ticks []domain.Tick) error {
+				saved = append(saved, ticks...)
+				return nil
+			},
+		},
+	})
+
+	result, err := recomputer.Run(context.Background(), start, start.Add(time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Ticks)
+	assert.Equal(t, 1, result.TickersSkipped)
+	require.Len(t, saved, 2)
+	assert.NotContains(t, saved[1].Data, domain.TickerName("ETHUSDT"))
+}
+
+func TestRecomputer_RunErrors(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repoErr := errors.New("db is down")
+
+	tests := []struct {
+		name    string
+		from    time.Time
+		ticks   func(ctx context.Context, from, to time.Time) ([]domain.Tick, error)
+		save    func(ctx context.Context, ticks []domain.Tick) error
+		wantErr string
+	}{
+		{
+			name:    "empty range",
+			from:    start.Add(time.Hour),
+			wantErr: "invalid range",
+		},
+		{
+			name: "loading warmup fails",
+			from: start,
+			ticks: func(ctx context.Context, from, to time.Time) ([]domain.Tick, error) {
+				return nil, repoErr
+			},
+			wantErr: "loading warmup ticks",
+		},
+		{
+			name: "saving fails",
+			from: start,
+			ticks: func(ctx context.Context, from, to time.Time) ([]domain.Tick, error) {
+				return storedTicks(from, 1), nil
+			},
+			save: func(ctx context.Context, ticks []domain.Tick) error {
+				return repoErr
+			},
+			wantErr: "saving ticks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recomputer := NewRecomputer(RecomputeConfig{
+				Ticks:  &domainMocks.TickRepositoryMock{GetRangeFunc: tt.ticks},
+				Output: &domainMocks.RecomputedTickRepositoryMock{SaveManyFunc: tt.save},
+			})
+
+			_, err := recomputer.Run(context.Background(), tt.from, start.Add(time.Hour))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
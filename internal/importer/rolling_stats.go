@@ -0,0 +1,233 @@
+package importer
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+)
+
+// statsBucket aggregates the tickers and liquidations of a symbol within a domain.RollingStatsBucket
+type statsBucket struct {
+	start        time.Time
+	open         float64 // mid price of the first ticker, 0 while the bucket only holds liquidations
+	high         float64
+	low          float64
+	close        float64
+	liquidations float64 // USD notional
+}
+
+// addPrice extends the bucket with a mid price
+func (b *statsBucket) addPrice(mid float64) {
+	b.close = mid
+	if b.open == 0 {
+		b.open, b.high, b.low = mid, mid, mid
+		return
+	}
+	b.high = max(b.high, mid)
+	b.low = min(b.low, mid)
+}
+
+// symbolStats keeps the buckets of a symbol within the rolling window, oldest first.
+// The extremes and the liquidations of all but the latest bucket are cached, so an update only touches the latest
+// bucket and the cache is only rebuilt when the window moves
+type symbolStats struct {
+	mu      sync.Mutex
+	buckets []statsBucket
+
+	closedHigh         float64
+	closedLow          float64
+	closedLiquidations float64
+}
+
+// bucket returns the bucket holding the given time, nil if it's older than the window of the latest bucket
+func (s *symbolStats) bucket(at time.Time) *statsBucket {
+	start := at.Truncate(domain.RollingStatsBucket)
+	n := len(s.buckets)
+	if n > 0 && s.buckets[n-1].start.Equal(start) {
+		return &s.buckets[n-1]
+	}
+
+	if n == 0 || start.After(s.buckets[n-1].start) {
+		s.buckets = append(s.buckets, statsBucket{start: start})
+		s.evict(start)
+		s.rebuild()
+		return &s.buckets[len(s.buckets)-1]
+	}
+
+	// a late liquidation, e.g. a backfilled one
+	if !start.After(s.buckets[n-1].start.Add(-domain.RollingStatsWindow)) {
+		return nil
+	}
+	i, found := slices.BinarySearchFunc(s.buckets, start, func(b statsBucket, t time.Time) int { return b.start.Compare(t) })
+	if !found {
+		s.buckets = slices.Insert(s.buckets, i, statsBucket{start: start})
+	}
+	return &s.buckets[i]
+}
+
+// evict drops the buckets that left the window ending with the bucket started at latest
+func (s *symbolStats) evict(latest time.Time) {
+	windowStart := latest.Add(-domain.RollingStatsWindow)
+	s.buckets = slices.DeleteFunc(s.buckets, func(b statsBucket) bool { return !b.start.After(windowStart) })
+}
+
+// rebuild recomputes the cache of all but the latest bucket
+func (s *symbolStats) rebuild() {
+	s.closedHigh, s.closedLow, s.closedLiquidations = 0, 0, 0
+	for _, b := range s.buckets[:len(s.buckets)-1] {
+		s.closedLiquidations += b.liquidations
+		if b.open == 0 {
+			continue
+		}
+		if s.closedHigh == 0 {
+			s.closedHigh, s.closedLow = b.high, b.low
+			continue
+		}
+		s.closedHigh = max(s.closedHigh, b.high)
+		s.closedLow = min(s.closedLow, b.low)
+	}
+}
+
+// stats returns the statistics of the window, the change being measured up to the given mid price
+func (s *symbolStats) stats(mid float64, precision domain.Precision) domain.RollingStats {
+	latest := s.buckets[len(s.buckets)-1]
+	stats := domain.RollingStats{
+		High:                s.closedHigh,
+		Low:                 s.closedLow,
+		LiquidationNotional: mathutils.RoundTo(s.closedLiquidations+latest.liquidations, precision.Notional),
+		Since:               s.buckets[0].start,
+	}
+	if latest.open != 0 {
+		if stats.High == 0 {
+			stats.High, stats.Low = latest.high, latest.low
+		}
+		stats.High = max(stats.High, latest.high)
+		stats.Low = min(stats.Low, latest.low)
+	}
+	for _, b := range s.buckets {
+		if b.open != 0 {
+			stats.Change = mathutils.PercDiff(mid, b.open, precision.Change)
+			break
+		}
+	}
+	return stats
+}
+
+// rollingStats maintains the domain.RollingStats of every symbol, updated incrementally from the built tickers and
+// the streamed liquidations
+type rollingStats struct {
+	mu        sync.RWMutex
+	bySymbol  map[domain.TickerName]*symbolStats
+	precision domain.Precision
+}
+
+func newRollingStats(precision domain.Precision) *rollingStats {
+	return &rollingStats{bySymbol: make(map[domain.TickerName]*symbolStats), precision: precision}
+}
+
+// symbol returns the stats of the symbol, created on first use
+func (r *rollingStats) symbol(symbol domain.TickerName) *symbolStats {
+	r.mu.RLock()
+	s, ok := r.bySymbol[symbol]
+	r.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok = r.bySymbol[symbol]; !ok {
+		s = &symbolStats{}
+		r.bySymbol[symbol] = s
+	}
+	return s
+}
+
+// addTicker adds the mid price of the ticker and returns the stats including it
+func (r *rollingStats) addTicker(ticker *domain.Ticker) *domain.RollingStats {
+	s := r.symbol(ticker.Symbol)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mid := ticker.Mid()
+	b := s.bucket(ticker.CreatedAt)
+	if b == nil {
+		return nil
+	}
+	b.addPrice(mid)
+	stats := s.stats(mid, r.precision)
+	return &stats
+}
+
+// addLiquidation adds the USD notional of the liquidation to the bucket of its event time
+func (r *rollingStats) addLiquidation(liq domain.Liquidation) {
+	s := r.symbol(liq.Order.Symbol)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.bucket(liq.EventAt)
+	if b == nil {
+		return
+	}
+	b.liquidations += liq.Order.USDValue
+	if b != &s.buckets[len(s.buckets)-1] {
+		s.closedLiquidations += liq.Order.USDValue
+	}
+}
+
+// get returns the stats of the symbol as of its latest ticker, false if the symbol wasn't seen
+func (r *rollingStats) get(symbol domain.TickerName) (domain.RollingStats, bool) {
+	r.mu.RLock()
+	s, ok := r.bySymbol[symbol]
+	r.mu.RUnlock()
+	if !ok {
+		return domain.RollingStats{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.buckets) == 0 {
+		return domain.RollingStats{}, false
+	}
+	mid := 0.0
+	for _, b := range slices.Backward(s.buckets) {
+		if b.open != 0 {
+			mid = b.close
+			break
+		}
+	}
+	return s.stats(mid, r.precision), true
+}
+
+// evict drops the stats of the symbols
+func (r *rollingStats) evict(symbols []domain.TickerName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(r.bySymbol, symbol)
+	}
+}
+
+// size returns the number of symbols with stats and of their buckets
+func (r *rollingStats) size() (symbols, buckets int) {
+	r.mu.RLock()
+	all := make([]*symbolStats, 0, len(r.bySymbol))
+	for _, s := range r.bySymbol {
+		all = append(all, s)
+	}
+	r.mu.RUnlock()
+	for _, s := range all {
+		s.mu.Lock()
+		buckets += len(s.buckets)
+		s.mu.Unlock()
+	}
+	return len(all), buckets
+}
+
+// RollingStats returns the statistics of the symbol over the last 24 hours, false if the symbol wasn't seen
+func (i *Importer) RollingStats(symbol domain.TickerName) (domain.RollingStats, bool) {
+	return i.rollingStats.get(symbol)
+}
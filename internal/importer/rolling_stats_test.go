@@ -0,0 +1,44 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollingStats(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stats := newRollingStats(domain.DefaultPrecision())
+	addTicker := func(at time.Time, mid float64) *domain.RollingStats {
+		return stats.addTicker(&domain.Ticker{Symbol: "BTCUSDT", Ask: mid + 1, Bid: mid - 1, CreatedAt: at})
+	}
+	liquidation := func(at time.Time, notional float64) domain.Liquidation {
+		return domain.Liquidation{Order: domain.Order{Symbol: "BTCUSDT", USDValue: notional}, EventAt: at}
+	}
+
+	_, ok := stats.get("BTCUSDT")
+	assert.False(t, ok)
+
+	assert.Equal(t, &domain.RollingStats{High: 100, Low: 100, Since: start}, addTicker(start, 100))
+	stats.addLiquidation(liquidation(start.Add(time.Minute), 5000))
+	addTicker(start.Add(time.Hour), 120)
+	stats.addLiquidation(liquidation(start.Add(time.Hour), 1000))
+	stats.addLiquidation(liquidation(start.Add(2*time.Minute), 500)) // late, e.g. backfilled
+	assert.Equal(t, &domain.RollingStats{High: 120, Low: 90, Change: -10, LiquidationNotional: 6500, Since: start},
+		addTicker(start.Add(2*time.Hour), 90))
+
+	got, ok := stats.get("BTCUSDT")
+	require.True(t, ok)
+	assert.Equal(t, domain.RollingStats{High: 120, Low: 90, Change: -10, LiquidationNotional: 6500, Since: start}, got)
+
+	// the first bucket leaves the window
+	assert.Equal(t, &domain.RollingStats{High: 120, Low: 90, Change: -12.5, LiquidationNotional: 1000, Since: start.Add(time.Hour)},
+		addTicker(start.Add(24*time.Hour), 105))
+
+	stats.addLiquidation(liquidation(start, 700))
+	got, _ = stats.get("BTCUSDT")
+	assert.Equal(t, 1000.0, got.LiquidationNotional, "liquidations older than the window are ignored")
+}
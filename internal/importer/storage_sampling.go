@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// StorageSamplingConfig thins out the stored tickers of the long-tail symbols. The symbols of a tick are ranked by
+// their notional, the tickers of a sampled symbol are stored once every few seconds while the published ticks keep
+// every ticker, so the notifiers get the full stream
+type StorageSamplingConfig struct {
+	Tiers map[int]int // interval in seconds of the symbols ranked within the top N by notional, e.g. 50:1,200:5
+	Every int         // interval in seconds of the symbols outside every tier, 0 or 1 stores every tick
+}
+
+// samplingTier is the interval of the symbols ranked above a rank
+type samplingTier struct {
+	top   int
+	every int
+}
+
+// storageSampler picks the tickers of a tick to store, nil when sampling is disabled
+type storageSampler struct {
+	tiers []samplingTier // sorted by rank
+	every int
+}
+
+func newStorageSampler(cfg StorageSamplingConfig) *storageSampler {
+	s := &storageSampler{every: cfg.Every}
+	sampled := cfg.Every > 1
+	for _, top := range slices.Sorted(maps.Keys(cfg.Tiers)) {
+		s.tiers = append(s.tiers, samplingTier{top: top, every: cfg.Tiers[top]})
+		sampled = sampled || cfg.Tiers[top] > 1
+	}
+	if !sampled {
+		return nil
+	}
+	return s
+}
+
+// interval returns how many seconds apart the tickers of the symbol at a rank, 0 for the largest notional, are stored
+func (s *storageSampler) interval(rank int) int {
+	for _, tier := range s.tiers {
+		if rank < tier.top {
+			return tier.every
+		}
+	}
+	return s.every
+}
+
+// sample returns the tick to store: the tick itself when every ticker is due, otherwise a shallow copy holding the
+// due tickers only, and the number of tickers left out. The tickers of the priority symbols and of the symbols whose
+// interval divides the second the tick started at are due
+func (s *storageSampler) sample(tick *domain.Tick, priority *priorityList) (*domain.Tick, int) {
+	if s == nil {
+		return tick, 0
+	}
+
+	second := tick.StartAt.Unix()
+	data := make(map[domain.TickerName]*domain.Ticker, len(tick.Data))
+	for rank, name := range rankByNotional(tick) {
+		every := s.interval(rank)
+		if every <= 1 || second%int64(every) == 0 || (priority != nil && priority.isPriority(string(name))) {
+			data[name] = tick.Data[name]
+		}
+	}
+	sampledOut := len(tick.Data) - len(data)
+	if sampledOut == 0 {
+		return tick, 0
+	}
+	stored := *tick
+	stored.Data = data
+	return &stored, sampledOut
+}
+
+// rankByNotional returns the symbols of the tick by decreasing notional, ties broken by name
+func rankByNotional(tick *domain.Tick) []domain.TickerName {
+	names := slices.Collect(maps.Keys(tick.Data))
+	slices.SortFunc(names, func(a, b domain.TickerName) int {
+		return cmp.Or(cmp.Compare(tick.Data[b].Notional, tick.Data[a].Notional), cmp.Compare(a, b))
+	})
+	return names
+}
@@ -0,0 +1,72 @@
+package importer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampledTick returns a tick started at the given second holding tickers of decreasing notional
+func sampledTick(second int64) *domain.Tick {
+	return &domain.Tick{
+		StartAt: time.Unix(second, 0).UTC(),
+		Data: map[domain.TickerName]*domain.Ticker{
+			"BTCUSDT":  {Symbol: "BTCUSDT", Notional: 5_000_000},
+			"ETHUSDT":  {Symbol: "ETHUSDT", Notional: 2_000_000},
+			"SOLUSDT":  {Symbol: "SOLUSDT", Notional: 900_000},
+			"PEPEUSDT": {Symbol: "PEPEUSDT", Notional: 50_000},
+			"XYZUSDT":  {Symbol: "XYZUSDT", Notional: 1_000},
+		},
+	}
+}
+
+func TestStorageSampler_Sample(t *testing.T) {
+	assert.Nil(t, newStorageSampler(StorageSamplingConfig{Tiers: map[int]int{10: 1}, Every: 1}),
+		"sampling is disabled without an interval above a second")
+
+	sampler := newStorageSampler(StorageSamplingConfig{Tiers: map[int]int{2: 1, 4: 5}, Every: 10})
+	priority := newPriorityList(PriorityConfig{Symbols: []string{"XYZUSDT"}})
+
+	tick := sampledTick(1_700_000_010)
+	stored, sampledOut := sampler.sample(tick, priority)
+	assert.Same(t, tick, stored, "every ticker is due in a second divisible by every interval")
+	assert.Zero(t, sampledOut)
+
+	tick = sampledTick(1_700_000_005)
+	stored, sampledOut = sampler.sample(tick, nil)
+	assert.ElementsMatch(t, []domain.TickerName{"BTCUSDT", "ETHUSDT", "SOLUSDT", "PEPEUSDT"}, keys(stored.Data))
+	assert.Equal(t, 1, sampledOut)
+	assert.Len(t, tick.Data, 5, "the tick itself keeps every ticker")
+
+	stored, sampledOut = sampler.sample(sampledTick(1_700_000_001), priority)
+	assert.ElementsMatch(t, []domain.TickerName{"BTCUSDT", "ETHUSDT", "XYZUSDT"}, keys(stored.Data),
+		"the first tier and the priority symbols are stored every second")
+	assert.Equal(t, 2, sampledOut)
+}
+
+func TestImporter_StoreSampledTick(t *testing.T) {
+	var stored []domain.Tick
+	i := &Importer{
+		tickRepository: &domainMocks.TickRepositoryMock{
+			CreateFunc: func(_ context.Context, tick domain.Tick) error {
+				stored = append(stored, tick)
+				return nil
+			},
+		},
+		storageSampler: newStorageSampler(StorageSamplingConfig{Every: 5}),
+		telemetry:      &telemetry.NoopProvider{},
+	}
+
+	tick := sampledTick(1_700_000_001)
+	require.NoError(t, i.storeTick(context.Background(), tick))
+	require.Len(t, stored, 1)
+	assert.Empty(t, stored[0].Data)
+	assert.Equal(t, tick.StartAt, stored[0].StartAt)
+	assert.Len(t, tick.Data, 5, "the published tick keeps every ticker")
+}
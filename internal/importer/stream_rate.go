@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+)
+
+// streamRateInterval is how often the message rate of the exchange streams is reported
+const streamRateInterval = time.Second
+
+// streamRates counts the messages received on every running exchange stream.
+// A stream is keyed by the stage it reports its failures with, so its rate and failures can be matched
+type streamRates struct {
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func newStreamRates() *streamRates {
+	return &streamRates{counts: make(map[string]int64)}
+}
+
+// track starts reporting a stream, so a silent stream is reported with zero messages
+func (r *streamRates) track(stream string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.counts[stream]; !ok {
+		r.counts[stream] = 0
+	}
+}
+
+// observe counts a message received on a stream
+func (r *streamRates) observe(stream string) {
+	r.mu.Lock()
+	r.counts[stream]++
+	r.mu.Unlock()
+}
+
+// take returns the message counts since the previous call, sorted by stream, and resets them
+func (r *streamRates) take(exchange string, interval time.Duration) []eventbus.StreamRate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rates := make([]eventbus.StreamRate, 0, len(r.counts))
+	for _, stream := range slices.Sorted(maps.Keys(r.counts)) {
+		rates = append(rates, eventbus.StreamRate{
+			Exchange: exchange,
+			Stream:   stream,
+			Messages: r.counts[stream],
+			Interval: interval,
+		})
+		r.counts[stream] = 0
+	}
+	return rates
+}
+
+// reportStreamRates publishes the message rate of every tracked stream each streamRateInterval until ctx is canceled
+func (i *Importer) reportStreamRates(ctx context.Context) error {
+	ticker := time.NewTicker(streamRateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, rate := range i.streamRates.take(i.exchange.GetName(), streamRateInterval) {
+				i.telemetry.Gauge(telemetryStreamMessagesPerSecond, rate.PerSecond(), fmt.Sprintf("stream:%s", rate.Stream))
+				i.events.Publish(eventbus.StreamRateMeasured, rate)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamRates_Take(t *testing.T) {
+	rates := newStreamRates()
+	assert.Empty(t, rates.take("binance", time.Second), "streams are only reported once tracked")
+
+	rates.track(eventbus.StageSubTicksStream)
+	rates.track(eventbus.StageLiquidations)
+	rates.observe(eventbus.StageLiquidations)
+	rates.observe(eventbus.StageLiquidations)
+
+	assert.Equal(t, []eventbus.StreamRate{
+		{Exchange: "binance", Stream: eventbus.StageLiquidations, Messages: 2, Interval: 2 * time.Second},
+		{Exchange: "binance", Stream: eventbus.StageSubTicksStream, Messages: 0, Interval: 2 * time.Second},
+	}, rates.take("binance", 2*time.Second))
+
+	rates.track(eventbus.StageLiquidations)
+	for _, rate := range rates.take("binance", time.Second) {
+		assert.Zero(t, rate.Messages, "counts are reset by take")
+	}
+}
@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSubTickInterval is the default sampling interval of high-resolution symbols
+	DefaultSubTickInterval = 250 * time.Millisecond
+
+	// MinSubTickInterval is the shortest allowed sampling interval
+	MinSubTickInterval = 100 * time.Millisecond
+)
+
+// HighResConfig configures high-resolution sampling of selected symbols between regular ticks
+type HighResConfig struct {
+	Symbols  []string      // symbols sampled via book tickers, empty disables the mode
+	Interval time.Duration // sampling interval, 0 uses DefaultSubTickInterval, shorter than MinSubTickInterval is raised to it
+}
+
+// interval returns the effective sampling interval
+func (c HighResConfig) interval() time.Duration {
+	if c.Interval <= 0 {
+		return DefaultSubTickInterval
+	}
+	return max(c.Interval, MinSubTickInterval)
+}
+
+// subTickSampler keeps the latest book ticker of every symbol until the next sample
+type subTickSampler struct {
+	mu     sync.Mutex
+	latest map[string]exchanges.Ticker
+}
+
+func newSubTickSampler() *subTickSampler {
+	return &subTickSampler{latest: make(map[string]exchanges.Ticker)}
+}
+
+// update records the latest book ticker of a symbol
+func (s *subTickSampler) update(t exchanges.Ticker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latest[t.Symbol] = t
+}
+
+// take returns a sub-tick for every symbol updated since the previous call, unchanged symbols are not repeated
+func (s *subTickSampler) take(at time.Time) []domain.SubTick {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subTicks := make([]domain.SubTick, 0, len(s.latest))
+	for symbol, t := range s.latest {
+		subTicks = append(subTicks, domain.SubTick{
+			Symbol:    domain.TickerName(symbol),
+			EventAt:   t.EventAt,
+			CreatedAt: at,
+			Ask:       t.AskPrice,
+			Bid:       t.BidPrice,
+			AskQty:    t.AskQuantity,
+			BidQty:    t.BidQuantity,
+		})
+		delete(s.latest, symbol)
+	}
+	return subTicks
+}
+
+// startSubTicksImport starts sampling the high-resolution symbols if configured and supported by the exchange
+func (i *Importer) startSubTicksImport(ctx context.Context) {
+	if len(i.highRes.Symbols) == 0 || i.subTickRepository == nil {
+		return
+	}
+	subscriber, ok := i.exchange.(exchanges.BookTickerSubscriber)
+	if !ok {
+		i.logger.Warn("High-resolution sampling is not supported by the exchange, only regular ticks are imported",
+			zap.String("exchange", i.exchange.GetName()))
+		return
+	}
+
+	tickers, errs := subscriber.SubscribeBookTickers(ctx, i.highRes.Symbols)
+	i.streamRates.track(eventbus.StageSubTicksStream)
+	i.logger.Info("High-resolution sampling started",
+		zap.Strings("symbols", i.highRes.Symbols),
+		zap.Duration("interval", i.highRes.interval()))
+
+	i.supervisor.Go(ctx, "sub_ticks", func(ctx context.Context) error {
+		i.consumeSubTicks(ctx, tickers, errs)
+		return nil
+	})
+}
+
+// consumeSubTicks collects book tickers and stores a sample of them every interval until ctx is canceled
+func (i *Importer) consumeSubTicks(ctx context.Context, tickers <-chan exchanges.Ticker, errs <-chan error) {
+	sampler := newSubTickSampler()
+	timeTicker := time.NewTicker(i.highRes.interval())
+	defer timeTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			i.logger.Info("Sub-tick import stopped (context canceled).")
+			return
+		case t, ok := <-tickers:
+			if !ok {
+				return
+			}
+			i.streamRates.observe(eventbus.StageSubTicksStream)
+			sampler.update(t)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			i.telemetry.IncrementCounter(telemetrySubTicksErrors, 1)
+			i.publishDegraded(eventbus.StageSubTicksStream, err)
+			i.logger.Error("Error on book ticker stream", zap.Error(err))
+		case now := <-timeTicker.C:
+			i.storeSubTicks(ctx, sampler.take(now.UTC()))
+		}
+	}
+}
+
+// storeSubTicks validates and stores a sample, invalid sub-ticks are dropped
+func (i *Importer) storeSubTicks(ctx context.Context, subTicks []domain.SubTick) {
+	valid := subTicks[:0]
+	for _, s := range subTicks {
+		if err := s.Validate(); err != nil {
+			i.logger.Debug("Sub-tick validation failed", zap.String("symbol", string(s.Symbol)), zap.Error(err))
+			continue
+		}
+		valid = append(valid, s)
+	}
+	if len(valid) == 0 {
+		return
+	}
+
+	start := time.Now()
+	if err := i.subTickRepository.CreateMany(ctx, valid); err != nil {
+		i.publishDegraded(eventbus.StageStoreSubTicks, err)
+		i.logger.Error("Failed to store sub-ticks", zap.Error(err))
+		return
+	}
+	i.telemetry.Timing(telemetrySubTicksStoreDuration, time.Since(start))
+	i.telemetry.IncrementCounter(telemetrySubTicksStored, int64(len(valid)))
+}
@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	exchangeMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bookTickerExchange is an exchange mock supporting high-resolution sampling
+type bookTickerExchange struct {
+	*exchangeMocks.ExchangeMock
+	subscribe func(ctx context.Context, symbols []string) (<-chan exchanges.Ticker, <-chan error)
+}
+
+func (e *bookTickerExchange) SubscribeBookTickers(ctx context.Context, symbols []string) (<-chan exchanges.Ticker, <-chan error) {
+	return e.subscribe(ctx, symbols)
+}
+
+func TestHighResConfigInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     time.Duration
+	}{
+		{name: "default", want: DefaultSubTickInterval},
+		{name: "configured", interval: 150 * time.Millisecond, want: 150 * time.Millisecond},
+		{name: "raised to minimum", interval: 10 * time.Millisecond, want: MinSubTickInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HighResConfig{Interval: tt.interval}.interval())
+		})
+	}
+}
+
+func TestSubTickSampler(t *testing.T) {
+	eventAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := newSubTickSampler()
+
+	sampler.update(exchanges.Ticker{Symbol: "BTCUSDT", AskPrice: 100, BidPrice: 99, EventAt: eventAt})
+	sampler.update(exchanges.Ticker{Symbol: "BTCUSDT", AskPrice: 101, BidPrice: 100, AskQuantity: 2, BidQuantity: 3, EventAt: eventAt.Add(time.Millisecond)})
+
+	at := eventAt.Add(time.Second)
+	assert.Equal(t, []domain.SubTick{{
+		Symbol:    "BTCUSDT",
+		EventAt:   eventAt.Add(time.Millisecond),
+		CreatedAt: at,
+		Ask:       101,
+		Bid:       100,
+		AskQty:    2,
+		BidQty:    3,
+	}}, sampler.take(at), "only the latest update of a symbol is sampled")
+
+	assert.Empty(t, sampler.take(at.Add(time.Second)), "unchanged symbols are not sampled again")
+}
+
+func TestSubTicksImport(t *testing.T) {
+	ts := setupTest()
+
+	var mu sync.Mutex
+	var stored []domain.SubTick
+	subTickRepo := &domainMocks.SubTickRepositoryMock{
+		CreateManyFunc: func(ctx context.Context, subTicks []domain.SubTick) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stored = append(stored, subTicks...)
+			return nil
+		},
+	}
+	ts.repoFactory.GetSubTickRepositoryFunc = func(name string) (domain.SubTickRepository, error) {
+		return subTickRepo, nil
+	}
+
+	tickers := make(chan exchanges.Ticker, 2)
+	var subscribed []string
+	exchange := &bookTickerExchange{
+		ExchangeMock: ts.exchange,
+		subscribe: func(ctx context.Context, symbols []string) (<-chan exchanges.Ticker, <-chan error) {
+			subscribed = symbols
+			return tickers, make(chan error)
+		},
+	}
+	importer := New(&Config{
+		Exchange:          exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		HighRes:           HighResConfig{Symbols: []string{"BTCUSDT"}, Interval: MinSubTickInterval},
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	importer.startSubTicksImport(ctx)
+	assert.Equal(t, []string{"BTCUSDT"}, subscribed)
+
+	now := time.Now()
+	tickers <- exchanges.Ticker{Symbol: "BTCUSDT", AskPrice: 101, BidPrice: 100, EventAt: now}
+	tickers <- exchanges.Ticker{Symbol: "ETHUSDT", AskPrice: 100, BidPrice: 101, EventAt: now} // crossed book, dropped
+
+	assert.Eventually(t, func() bool {
+		return len(subTickRepo.CreateManyCalls()) > 0
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, stored, 1)
+	assert.Equal(t, domain.TickerName("BTCUSDT"), stored[0].Symbol)
+	assert.Equal(t, 101.0, stored[0].Ask)
+}
+
+func TestSubTicksImportUnsupportedExchange(t *testing.T) {
+	ts := setupTest()
+	ts.repoFactory.GetSubTickRepositoryFunc = func(name string) (domain.SubTickRepository, error) {
+		return &domainMocks.SubTickRepositoryMock{}, nil
+	}
+	importer := New(&Config{
+		Exchange:          ts.exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		HighRes:           HighResConfig{Symbols: []string{"BTCUSDT"}},
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+
+	assert.NotPanics(t, func() { importer.startSubTicksImport(context.Background()) })
+}
+
+func TestNewSkipsSubTickRepositoryWithoutSymbols(t *testing.T) {
+	ts := setupTest()
+	assert.NotNil(t, ts.importer)
+	assert.Empty(t, ts.repoFactory.GetSubTickRepositoryCalls())
+}
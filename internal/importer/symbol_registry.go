@@ -0,0 +1,259 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"go.uber.org/zap"
+)
+
+// DefaultSymbolDelistAfter is how long a symbol may be missing from the ticker fetches before it's marked delisted
+const DefaultSymbolDelistAfter = time.Hour
+
+const (
+	// symbolRegistryFlushInterval is how often the changed symbols are stored
+	symbolRegistryFlushInterval = time.Minute
+
+	// symbolRegistryStopTimeout bounds the last flush of the changed symbols on stop
+	symbolRegistryStopTimeout = 5 * time.Second
+
+	// symbolSeenRefreshInterval is how often the fetched symbols are stored for their last sighting alone, so the
+	// stored sightings a restart delists from lag behind by this long at most
+	symbolSeenRefreshInterval = 10 * time.Minute
+)
+
+// SymbolRegistryConfig configures the registry of the symbols of the exchange
+type SymbolRegistryConfig struct {
+	Enabled     bool
+	DelistAfter time.Duration // mark symbols missing from the fetches this long delisted, 0 uses DefaultSymbolDelistAfter
+}
+
+// symbolRegistry keeps a record of every symbol fetched from the exchange, the changed records are stored periodically.
+// A record changes when its status or metadata does, a new sighting alone changes it every symbolSeenRefreshInterval
+type symbolRegistry struct {
+	delistAfter time.Duration
+
+	mu          sync.RWMutex
+	bySymbol    map[domain.TickerName]*domain.Symbol
+	dirty       map[domain.TickerName]*domain.Symbol // records changed since the last take, kept once evicted
+	refreshedAt time.Time                            // when the sightings of the fetched symbols were last marked changed
+}
+
+func newSymbolRegistry(delistAfter time.Duration) *symbolRegistry {
+	if delistAfter <= 0 {
+		delistAfter = DefaultSymbolDelistAfter
+	}
+	return &symbolRegistry{
+		delistAfter: delistAfter,
+		bySymbol:    make(map[domain.TickerName]*domain.Symbol),
+		dirty:       make(map[domain.TickerName]*domain.Symbol),
+	}
+}
+
+// load adds the stored symbols to the registry
+func (r *symbolRegistry) load(symbols []domain.Symbol) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range symbols {
+		r.bySymbol[s.Symbol] = &s
+	}
+}
+
+// observe records the tickers fetched for the tick starting at the given time and marks the symbols missing since
+// delistAfter delisted. It returns the symbols listed for the first time or again and the ones just delisted
+func (r *symbolRegistry) observe(eTickers []exchanges.Ticker, at time.Time) (listed, delisted []domain.TickerName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	refresh := at.Sub(r.refreshedAt) >= symbolSeenRefreshInterval
+	if refresh {
+		r.refreshedAt = at
+	}
+	for _, t := range eTickers {
+		name := domain.TickerName(t.Symbol)
+		s, ok := r.bySymbol[name]
+		if !ok {
+			s = &domain.Symbol{Symbol: name, FirstSeenAt: at}
+			r.bySymbol[name] = s
+		}
+		changed := refresh || s.Status != domain.SymbolActive ||
+			s.InstType != t.InstType || s.Base != t.Base || s.Quote != t.Quote || s.ContractValue != t.ContractValue
+		if s.Status != domain.SymbolActive {
+			listed = append(listed, name)
+		}
+		s.InstType, s.Base, s.Quote, s.ContractValue = t.InstType, t.Base, t.Quote, t.ContractValue
+		s.Status = domain.SymbolActive
+		s.LastSeenAt = at
+		s.DelistedAt = time.Time{}
+		if changed {
+			r.dirty[name] = s
+		}
+	}
+
+	for name, s := range r.bySymbol {
+		if s.Status == domain.SymbolActive && at.Sub(s.LastSeenAt) >= r.delistAfter {
+			s.Status = domain.SymbolDelisted
+			s.DelistedAt = at
+			r.dirty[name] = s
+			delisted = append(delisted, name)
+		}
+	}
+	return listed, delisted
+}
+
+// known tells whether the symbol is in the registry, every symbol is known while the registry is empty
+func (r *symbolRegistry) known(symbol domain.TickerName) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.bySymbol) == 0 {
+		return true
+	}
+	_, ok := r.bySymbol[symbol]
+	return ok
+}
+
+// take returns the symbols changed since the previous take
+func (r *symbolRegistry) take() []domain.Symbol {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := make([]domain.Symbol, 0, len(r.dirty))
+	for _, s := range r.dirty {
+		changed = append(changed, *s)
+	}
+	clear(r.dirty)
+	return changed
+}
+
+// restore marks the symbols changed again after they failed to be stored
+func (r *symbolRegistry) restore(symbols []domain.Symbol) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range symbols {
+		if _, changed := r.dirty[s.Symbol]; changed {
+			continue
+		}
+		if current, ok := r.bySymbol[s.Symbol]; ok {
+			r.dirty[s.Symbol] = current
+		} else {
+			r.dirty[s.Symbol] = &s
+		}
+	}
+}
+
+// evict drops the records of the symbols from memory, their stored records and pending changes are kept.
+// A symbol fetched again after its eviction is recorded anew
+func (r *symbolRegistry) evict(symbols []domain.TickerName) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, symbol := range symbols {
+		delete(r.bySymbol, symbol)
+	}
+}
+
+// len returns the number of symbols in the registry
+func (r *symbolRegistry) len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.bySymbol)
+}
+
+// all returns a copy of the registry sorted by symbol
+func (r *symbolRegistry) all() []domain.Symbol {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	symbols := make([]domain.Symbol, 0, len(r.bySymbol))
+	for _, s := range r.bySymbol {
+		symbols = append(symbols, *s)
+	}
+	slices.SortFunc(symbols, func(a, b domain.Symbol) int { return strings.Compare(string(a.Symbol), string(b.Symbol)) })
+	return symbols
+}
+
+// Symbols returns the symbol registry of the exchange sorted by symbol, nil when the registry is disabled
+func (i *Importer) Symbols() []domain.Symbol {
+	if i.symbols == nil {
+		return nil
+	}
+	return i.symbols.all()
+}
+
+// startSymbolRegistry loads the stored symbols and stores the changed ones every symbolRegistryFlushInterval
+// and once more on stop
+func (i *Importer) startSymbolRegistry(ctx context.Context) error {
+	if i.symbols == nil {
+		return nil
+	}
+	stored, err := i.symbolRepository.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load symbols: %w", err)
+	}
+	i.symbols.load(stored)
+	i.logger.Info("Symbol registry loaded", zap.Int("symbols", len(stored)))
+
+	i.supervisor.Go(ctx, "symbol_registry", func(ctx context.Context) error {
+		timeTicker := time.NewTicker(symbolRegistryFlushInterval)
+		defer timeTicker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), symbolRegistryStopTimeout)
+				defer cancel()
+				i.flushSymbols(stopCtx)
+				return nil
+			case <-timeTicker.C:
+				i.flushSymbols(ctx)
+			}
+		}
+	})
+	return nil
+}
+
+// observeSymbols records the fetched tickers in the symbol registry if enabled
+func (i *Importer) observeSymbols(eTickers []exchanges.Ticker, at time.Time) {
+	if i.symbols == nil {
+		return
+	}
+	listed, delisted := i.symbols.observe(eTickers, at)
+	if len(listed) > 0 {
+		i.telemetry.IncrementCounter(telemetrySymbolsListed, int64(len(listed)))
+		i.logger.Info("Symbols listed", zap.Int("count", len(listed)), zap.Any("symbols", listed))
+	}
+	if len(delisted) > 0 {
+		i.telemetry.IncrementCounter(telemetrySymbolsDelisted, int64(len(delisted)))
+		i.logger.Info("Symbols delisted", zap.Int("count", len(delisted)), zap.Any("symbols", delisted))
+	}
+}
+
+// flushSymbols stores the symbols changed since the previous flush, they're retried on the next one on failure
+func (i *Importer) flushSymbols(ctx context.Context) {
+	changed := i.symbols.take()
+	if len(changed) == 0 {
+		return
+	}
+	if err := i.symbolRepository.SaveMany(ctx, changed); err != nil {
+		i.symbols.restore(changed)
+		i.publishDegraded(eventbus.StageStoreSymbols, err)
+		i.logger.Error("Failed to store symbols", zap.Int("count", len(changed)), zap.Error(err))
+	}
+}
+
+// checkLiquidationSymbol flags a liquidation of a symbol missing from the symbol registry as suspect,
+// e.g. parsed from a malformed frame
+func (i *Importer) checkLiquidationSymbol(liq *domain.Liquidation) {
+	if i.symbols == nil || i.symbols.known(liq.Order.Symbol) {
+		return
+	}
+	liq.Suspect = append(liq.Suspect, domain.LiquidationCheckSymbol+": unknown symbol "+string(liq.Order.Symbol))
+	i.telemetry.IncrementCounter(telemetryLiquidationsSuspect, 1, "check:"+domain.LiquidationCheckSymbol)
+	i.logger.Warn("Suspect liquidation",
+		zap.String("symbol", string(liq.Order.Symbol)),
+		zap.String("source", sourceTag(liq.Source)),
+		zap.String("check", domain.LiquidationCheckSymbol))
+}
@@ -0,0 +1,127 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolRegistry_Observe(t *testing.T) {
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry := newSymbolRegistry(time.Hour)
+	registry.load([]domain.Symbol{
+		{Symbol: "LUNAUSDT", Status: domain.SymbolActive, FirstSeenAt: at.Add(-48 * time.Hour), LastSeenAt: at.Add(-2 * time.Hour)},
+		{Symbol: "ETHUSDT", Status: domain.SymbolDelisted, FirstSeenAt: at.Add(-48 * time.Hour), LastSeenAt: at.Add(-2 * time.Hour)},
+	})
+
+	listed, delisted := registry.observe([]exchanges.Ticker{
+		{Symbol: "BTCUSDT", InstType: "SWAP", Base: "BTC", Quote: "USDT"},
+		{Symbol: "ETHUSDT", Base: "ETH", Quote: "USDT"},
+	}, at)
+	assert.ElementsMatch(t, []domain.TickerName{"BTCUSDT", "ETHUSDT"}, listed, "new and relisted symbols")
+	assert.Equal(t, []domain.TickerName{"LUNAUSDT"}, delisted)
+
+	symbols := registry.all()
+	require.Len(t, symbols, 3)
+	assert.Equal(t, domain.Symbol{
+		Symbol: "BTCUSDT", InstType: "SWAP", Base: "BTC", Quote: "USDT",
+		Status: domain.SymbolActive, FirstSeenAt: at, LastSeenAt: at,
+	}, symbols[0])
+	assert.Equal(t, domain.SymbolActive, symbols[1].Status)
+	assert.Equal(t, at.Add(-48*time.Hour), symbols[1].FirstSeenAt, "the first sighting is kept on relisting")
+	assert.Equal(t, domain.SymbolDelisted, symbols[2].Status)
+	assert.Equal(t, at, symbols[2].DelistedAt)
+
+	assert.Len(t, registry.take(), 3)
+	assert.Empty(t, registry.take(), "changes are taken once")
+
+	listed, delisted = registry.observe([]exchanges.Ticker{{Symbol: "BTCUSDT", InstType: "SWAP", Base: "BTC", Quote: "USDT"}}, at.Add(time.Second))
+	assert.Empty(t, listed)
+	assert.Empty(t, delisted, "delisted symbols aren't delisted again")
+	assert.Empty(t, registry.take(), "a sighting alone isn't a change")
+	assert.Equal(t, at.Add(time.Second), registry.all()[0].LastSeenAt)
+
+	registry.observe([]exchanges.Ticker{{Symbol: "BTCUSDT", InstType: "SWAP", Base: "BTC", Quote: "USD"}}, at.Add(2*time.Second))
+	assert.Len(t, registry.take(), 1, "metadata changes are stored")
+
+	registry.observe([]exchanges.Ticker{{Symbol: "BTCUSDT", InstType: "SWAP", Base: "BTC", Quote: "USD"}}, at.Add(symbolSeenRefreshInterval))
+	changed := registry.take()
+	require.Len(t, changed, 1, "the sightings are refreshed periodically")
+	assert.Equal(t, at.Add(symbolSeenRefreshInterval), changed[0].LastSeenAt)
+}
+
+func TestSymbolRegistry_Known(t *testing.T) {
+	registry := newSymbolRegistry(0)
+	assert.Equal(t, DefaultSymbolDelistAfter, registry.delistAfter)
+	assert.True(t, registry.known("ANYUSDT"), "every symbol is known while the registry is empty")
+
+	registry.observe([]exchanges.Ticker{{Symbol: "BTCUSDT"}}, time.Now())
+	assert.True(t, registry.known("BTCUSDT"))
+	assert.False(t, registry.known("BTC\x00USDT"))
+}
+
+func TestSymbolRegistryImport(t *testing.T) {
+	ts := setupTest()
+	saveErr := errors.New("db down")
+	symbolRepo := &domainMocks.SymbolRepositoryMock{
+		GetAllFunc: func(ctx context.Context) ([]domain.Symbol, error) {
+			return []domain.Symbol{{Symbol: "BTCUSDT", Status: domain.SymbolActive, LastSeenAt: time.Now()}}, nil
+		},
+		SaveManyFunc: func(ctx context.Context, symbols []domain.Symbol) error {
+			return saveErr
+		},
+	}
+	ts.repoFactory.GetSymbolRepositoryFunc = func(name string) (domain.SymbolRepository, error) {
+		return symbolRepo, nil
+	}
+	liquidations := make(chan exchanges.Liquidation)
+	ts.exchange.SubscribeLiquidationsFunc = func(context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+		return liquidations, make(chan error)
+	}
+	importer := New(&Config{
+		Exchange:          ts.exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		SymbolRegistry:    SymbolRegistryConfig{Enabled: true},
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, importer.startSymbolRegistry(ctx))
+	require.NoError(t, importer.startLiquidationsImport(ctx))
+	assert.Len(t, importer.Symbols(), 1)
+
+	liquidations <- exchanges.Liquidation{Symbol: "BTCUSDT", Side: exchanges.LongLiquidated, Price: 1, Quantity: 1, TotalPrice: 1, EventAt: time.Now()}
+	liquidations <- exchanges.Liquidation{Symbol: "JUNK", Side: exchanges.LongLiquidated, Price: 1, Quantity: 1, TotalPrice: 1, EventAt: time.Now()}
+	require.Eventually(t, func() bool { return len(ts.liqRepo.CreateCalls()) == 2 }, time.Second, time.Millisecond)
+
+	calls := ts.liqRepo.CreateCalls()
+	assert.Empty(t, calls[0].L.Suspect)
+	assert.Equal(t, []string{"symbol: unknown symbol JUNK"}, calls[1].L.Suspect)
+
+	importer.observeSymbols([]exchanges.Ticker{{Symbol: "ETHUSDT"}}, time.Now())
+	importer.flushSymbols(ctx)
+	require.Len(t, symbolRepo.SaveManyCalls(), 1)
+	assert.Len(t, symbolRepo.SaveManyCalls()[0].Symbols, 1)
+	assert.Len(t, importer.symbols.take(), 1, "symbols failing to be stored are retried")
+}
+
+func TestSymbolsDisabled(t *testing.T) {
+	ts := setupTest()
+	assert.Nil(t, ts.importer.Symbols())
+	assert.NoError(t, ts.importer.startSymbolRegistry(context.Background()))
+
+	liq := domain.Liquidation{Order: domain.Order{Symbol: "JUNK"}}
+	ts.importer.checkLiquidationSymbol(&liq)
+	assert.Empty(t, liq.Suspect)
+}
@@ -1,5 +1,7 @@
 package importer
 
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
 // Telemetry constants for counters
 const (
 	// telemetryLiquidationsErrors tracks the number of errors encountered during liquidation stream processing
@@ -7,18 +9,110 @@ const (
 
 	// telemetryTickFetchErrors counts errors that occur when fetching tickers from the exchange
 	telemetryTickFetchErrors = "tick.fetch.errors"
+
+	// telemetrySubTicksErrors counts errors of the book ticker stream used for high-resolution sampling
+	telemetrySubTicksErrors = "sub_ticks.errors"
+
+	// telemetrySubTicksStored counts the stored high-resolution sub-ticks
+	telemetrySubTicksStored = "sub_ticks.stored"
+
+	// telemetryRecomputeTicks counts the ticks written by the indicator recomputation job
+	telemetryRecomputeTicks = "recompute.ticks"
+
+	// telemetryBarsStored counts the stored minute bars
+	telemetryBarsStored = "bars.stored"
+
+	// telemetryFundingRatesStored counts the stored funding rates
+	telemetryFundingRatesStored = "funding_rates.stored"
+
+	// telemetryFundingRatesErrors counts the failed funding rate fetches
+	telemetryFundingRatesErrors = "funding_rates.errors"
+
+	// telemetryOpenInterestPolled counts the polled open interest values
+	telemetryOpenInterestPolled = "open_interest.polled"
+
+	// telemetryOpenInterestErrors counts the failed open interest polls
+	telemetryOpenInterestErrors = "open_interest.errors"
+
+	// telemetryTradesStored counts the stored aggregated trades
+	telemetryTradesStored = "trades.stored"
+
+	// telemetryTradesErrors counts errors of the trade stream
+	telemetryTradesErrors = "trades.errors"
+
+	// telemetrySymbolsListed counts the symbols seen for the first time or again after their delisting
+	telemetrySymbolsListed = "symbols.listed"
+
+	// telemetrySymbolsDelisted counts the symbols marked delisted
+	telemetrySymbolsDelisted = "symbols.delisted"
+
+	// telemetryLiquidationsBackfilled counts the liquidations stored by the backfill on start
+	telemetryLiquidationsBackfilled = "liquidations.backfilled"
+
+	// telemetryLiquidationsDuplicates counts the liquidations dropped because another source reported them first
+	telemetryLiquidationsDuplicates = "liquidations.duplicates"
+
+	// telemetryLiquidationsLate counts the liquidations arrived after their window was counted, counted in the next window
+	telemetryLiquidationsLate = "liquidations.late"
+
+	// telemetryLiquidationsSuspect counts the liquidations stored with failed sanity checks, tagged with the check
+	telemetryLiquidationsSuspect = "liquidations.suspect"
+
+	// telemetryTickStoreRetries counts the retries of storing a tick after a repository failure
+	telemetryTickStoreRetries = "tick.store.retries"
+	// telemetryTickStoreSampledOut reports the tickers left out of the latest stored tick by the storage sampling
+	telemetryTickStoreSampledOut = "tick.store.sampled_out"
+
+	// telemetryTickBuildErrors counts the ticks stored with a failed build stage, tagged with the stage
+	telemetryTickBuildErrors = "tick.build.errors"
+
+	// telemetryTickValidateSuspect counts the ticks stored with failed suspect-severity checks
+	telemetryTickValidateSuspect = "tick.validate.suspect"
+
+	// telemetryTickFetchThrottled counts the ticker fetches skipped to stay within the rate limit of the exchange
+	telemetryTickFetchThrottled = "tick.fetch.throttled"
+
+	// telemetryHistorySymbolsEvicted counts the symbols evicted from the ticker history past the symbol cap
+	telemetryHistorySymbolsEvicted = "history.symbols.evicted"
 )
 
 // Telemetry constants for timings
 const (
+	// telemetryHistoryInitDuration measures the time taken to load and replay the history on start
+	telemetryHistoryInitDuration = "history.init.duration"
+
+	// telemetryTickQualityScore reports the quality score of the latest tick
+	telemetryTickQualityScore = "tick.quality.score"
+
+	// telemetryTickEventSkew reports the median skew between the exchange timestamps and the fetch of the latest tick
+	telemetryTickEventSkew = "tick.event_skew"
+
+	// telemetryTickQualityComponent reports the scores of the quality components of the latest tick, tagged by component
+	telemetryTickQualityComponent = "tick.quality.component"
+
 	// telemetryTickFetchDuration measures the time taken to fetch tickers from the exchange
 	telemetryTickFetchDuration = "tick.fetch.duration"
 
 	// telemetryTickBuildSetLiquidations tracks time spent populating liquidation data in a tick
 	telemetryTickBuildSetLiquidations = "tick.build.set_tick_liquidations"
 
+	// telemetryLiquidationsLateness measures the delay between the event time of a liquidation and its arrival
+	telemetryLiquidationsLateness = "liquidations.lateness"
+
 	// telemetryTickCalculateIndicators measures time spent calculating tick indicators from history
 	telemetryTickCalculateIndicators = "tick.calculate_indicators.duration"
+
+	// telemetryTickerBuildSlowest reports the slowest buildTicker call of a tick
+	telemetryTickerBuildSlowest = "tick.build.ticker.slowest_duration"
+
+	// telemetryTickerBuildLatency measures every buildTicker call
+	telemetryTickerBuildLatency = "tick.build.ticker.latency"
+
+	// telemetrySubTicksStoreDuration measures the time taken to store a sample of sub-ticks
+	telemetrySubTicksStoreDuration = "sub_ticks.store.duration"
+
+	// telemetryRecomputeBatchDuration measures the time taken to recompute and write a batch of stored ticks
+	telemetryRecomputeBatchDuration = "recompute.batch.duration"
 )
 
 // Telemetry constants for gauges
@@ -26,8 +120,62 @@ const (
 	// telemetryTickFetchTickersCount tracks the number of tickers fetched from the exchange
 	telemetryTickFetchTickersCount = "tick.fetch.tickers_count"
 
+	// telemetryLiquidationsWatermarkSkew tracks the largest liquidation lateness of the last minute, in milliseconds
+	telemetryLiquidationsWatermarkSkew = "liquidations.watermark.skew"
+
 	// telemetryTickBuildTickersProcessed measures the number of tickers successfully processed in a tick
 	telemetryTickBuildTickersProcessed = "tick.build.tickers_processed"
+
+	// telemetryTickBuildTickersIlliquid tracks the number of tickers below the minimum liquidity of a tick
+	telemetryTickBuildTickersIlliquid = "tick.build.tickers_illiquid"
+
+	// telemetryTickBuildTickersOtherQuote tracks the number of tickers dropped because of their quote asset
+	telemetryTickBuildTickersOtherQuote = "tick.build.tickers_other_quote"
+
+	// telemetryTickBuildTickersUnconvertible tracks the number of tickers dropped because their quote asset has no USD rate
+	telemetryTickBuildTickersUnconvertible = "tick.build.tickers_unconvertible"
+
+	// telemetryTickBuildTickersSkipped tracks the number of low-priority tickers skipped because a tick ran past its deadline
+	telemetryTickBuildTickersSkipped = "tick.build.tickers_skipped"
+
+	// telemetryTickFetchConversionFailures tracks the number of fetched tickers dropped because of conversion errors
+	telemetryTickFetchConversionFailures = "tick.fetch.conversion_failures"
+
+	// telemetryStreamMessagesPerSecond tracks the messages per second received on every exchange stream, tagged with the stream
+	telemetryStreamMessagesPerSecond = "stream.messages_per_second"
+
+	// telemetryExchangeAPIUsed tracks the weight or requests of the REST rate limit used within the current window
+	telemetryExchangeAPIUsed = "exchange.api.used"
+
+	// telemetryExchangeAPIRemaining tracks the weight or requests of the REST rate limit left within the current window
+	telemetryExchangeAPIRemaining = "exchange.api.remaining"
+
+	// telemetryLiquidationsStoreRate tracks the liquidations stored per second
+	telemetryLiquidationsStoreRate = "liquidations.store.rate"
+
+	// telemetryLiquidationsStoreBaseline tracks the trailing baseline the liquidation storage rate is compared to
+	telemetryLiquidationsStoreBaseline = "liquidations.store.baseline"
+
+	// telemetryLiquidationsStoreSpiking is 1 while the liquidation storage rate exceeds its baseline by the spike factor
+	telemetryLiquidationsStoreSpiking = "liquidations.store.spiking"
+
+	// telemetryLiquidationsStoreBatchSize tracks the liquidations stored per write while the storage rate spikes
+	telemetryLiquidationsStoreBatchSize = "liquidations.store.batch_size"
+
+	// telemetryHistorySymbols tracks the number of symbols with a ticker history
+	telemetryHistorySymbols = "history.symbols"
+
+	// telemetryHistoryEntries tracks the entries of the tick and ticker ring buffers and the buckets of the rolling
+	// stats, tagged with the history
+	telemetryHistoryEntries = "history.entries"
+
+	// telemetryHistoryBytes tracks the estimated memory of the tick and ticker histories and of the rolling stats,
+	// tagged with the history
+	telemetryHistoryBytes = "history.bytes"
+
+	// telemetrySymbolsTracked tracks the symbols held by the per-symbol state besides the ticker history, tagged with
+	// the state
+	telemetrySymbolsTracked = "symbols.tracked"
 )
 
 // Telemetry constants for spans
@@ -41,3 +189,67 @@ const (
 	// telemetrySpanBuildTick represents the process of building a tick from fetched data
 	telemetrySpanBuildTick = "buildTick"
 )
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryLiquidationsErrors, Kind: telemetry.KindCounter, Description: "Errors of the liquidation stream"},
+		{Name: telemetryTickFetchErrors, Kind: telemetry.KindCounter, Description: "Errors fetching tickers from the exchange"},
+		{Name: telemetrySubTicksErrors, Kind: telemetry.KindCounter, Description: "Errors of the book ticker stream used for high-resolution sampling"},
+		{Name: telemetrySubTicksStored, Kind: telemetry.KindCounter, Description: "High-resolution sub-ticks stored"},
+		{Name: telemetryRecomputeTicks, Kind: telemetry.KindCounter, Description: "Ticks written by the indicator recomputation job"},
+		{Name: telemetryBarsStored, Kind: telemetry.KindCounter, Description: "Minute bars stored"},
+		{Name: telemetryFundingRatesStored, Kind: telemetry.KindCounter, Description: "Funding rates stored"},
+		{Name: telemetryFundingRatesErrors, Kind: telemetry.KindCounter, Description: "Failed funding rate fetches"},
+		{Name: telemetryOpenInterestPolled, Kind: telemetry.KindCounter, Description: "Open interest values polled"},
+		{Name: telemetryOpenInterestErrors, Kind: telemetry.KindCounter, Description: "Failed open interest polls"},
+		{Name: telemetryTradesStored, Kind: telemetry.KindCounter, Description: "Aggregated trades stored"},
+		{Name: telemetryTradesErrors, Kind: telemetry.KindCounter, Description: "Errors of the trade stream"},
+		{Name: telemetrySymbolsListed, Kind: telemetry.KindCounter, Description: "Symbols listed or relisted"},
+		{Name: telemetrySymbolsDelisted, Kind: telemetry.KindCounter, Description: "Symbols marked delisted"},
+		{Name: telemetryLiquidationsBackfilled, Kind: telemetry.KindCounter, Description: "Liquidations stored by the backfill on start"},
+		{Name: telemetryLiquidationsDuplicates, Kind: telemetry.KindCounter, Description: "Liquidations dropped because another source reported them first", Tags: []string{"source"}},
+		{Name: telemetryLiquidationsLate, Kind: telemetry.KindCounter, Description: "Liquidations arrived after their window was counted, counted in the next window", Tags: []string{"source"}},
+		{Name: telemetryLiquidationsSuspect, Kind: telemetry.KindCounter, Description: "Liquidations stored with failed sanity checks", Tags: []string{"check"}},
+		{Name: telemetryTickStoreRetries, Kind: telemetry.KindCounter, Description: "Retries of storing a tick after a repository failure"},
+		{Name: telemetryTickStoreSampledOut, Kind: telemetry.KindGauge, Description: "Tickers left out of the latest stored tick by the storage sampling"},
+		{Name: telemetryTickValidateSuspect, Kind: telemetry.KindCounter, Description: "Ticks stored with failed suspect-severity checks"},
+		{Name: telemetryTickBuildErrors, Kind: telemetry.KindCounter, Description: "Ticks stored with a failed build stage, see Tick.Errors", Tags: []string{"stage"}},
+		{Name: telemetryTickFetchThrottled, Kind: telemetry.KindCounter, Description: "Ticker fetches skipped to stay within the rate limit of the exchange"},
+		{Name: telemetryHistorySymbolsEvicted, Kind: telemetry.KindCounter, Description: "Least recently updated symbols evicted from the ticker history past the symbol cap"},
+		{Name: telemetryHistoryInitDuration, Kind: telemetry.KindTiming, Description: "Time taken to load and replay the history on start"},
+		{Name: telemetryTickQualityScore, Kind: telemetry.KindGauge, Description: "Quality score of the latest tick, from 0 to 1"},
+		{Name: telemetryTickEventSkew, Kind: telemetry.KindGauge, Description: "Median of the fetch time minus the exchange timestamps of the tickers of the latest tick, in milliseconds"},
+		{Name: telemetryTickQualityComponent, Kind: telemetry.KindGauge, Description: "Quality component scores of the latest tick", Tags: []string{"component"}},
+		{Name: telemetryTickFetchDuration, Kind: telemetry.KindTiming, Description: "Time taken to fetch tickers from the exchange"},
+		{Name: telemetryTickBuildSetLiquidations, Kind: telemetry.KindTiming, Description: "Time spent populating liquidation data in a tick"},
+		{Name: telemetryLiquidationsLateness, Kind: telemetry.KindTiming, Description: "Delay between the event time of a liquidation and its arrival", Tags: []string{"source"}},
+		{Name: telemetryTickCalculateIndicators, Kind: telemetry.KindTiming, Description: "Time spent calculating tick indicators"},
+		{Name: telemetryTickerBuildSlowest, Kind: telemetry.KindTiming, Description: "Slowest ticker build of a tick"},
+		{Name: telemetryTickerBuildLatency, Kind: telemetry.KindTiming, Description: "Time taken to build a ticker"},
+		{Name: telemetrySubTicksStoreDuration, Kind: telemetry.KindTiming, Description: "Time taken to store a sample of sub-ticks"},
+		{Name: telemetryRecomputeBatchDuration, Kind: telemetry.KindTiming, Description: "Time taken to recompute and write a batch of stored ticks"},
+		{Name: telemetryTickFetchTickersCount, Kind: telemetry.KindGauge, Description: "Number of tickers fetched from the exchange"},
+		{Name: telemetryLiquidationsWatermarkSkew, Kind: telemetry.KindGauge, Description: "Largest liquidation lateness of the last minute, in milliseconds"},
+		{Name: telemetryTickBuildTickersProcessed, Kind: telemetry.KindGauge, Description: "Number of tickers processed in a tick"},
+		{Name: telemetryTickBuildTickersIlliquid, Kind: telemetry.KindGauge, Description: "Number of tickers below the minimum liquidity in a tick"},
+		{Name: telemetryTickBuildTickersOtherQuote, Kind: telemetry.KindGauge, Description: "Number of tickers dropped because they aren't quoted in the imported quote assets"},
+		{Name: telemetryTickBuildTickersUnconvertible, Kind: telemetry.KindGauge, Description: "Number of tickers dropped because their quote asset has no USD rate"},
+		{Name: telemetryTickBuildTickersSkipped, Kind: telemetry.KindGauge, Description: "Number of low-priority tickers skipped because a tick ran past its deadline"},
+		{Name: telemetryTickFetchConversionFailures, Kind: telemetry.KindGauge, Description: "Number of fetched tickers dropped because of conversion errors"},
+		{Name: telemetryStreamMessagesPerSecond, Kind: telemetry.KindGauge, Description: "Messages per second received on an exchange stream", Tags: []string{"stream"}},
+		{Name: telemetryExchangeAPIUsed, Kind: telemetry.KindGauge, Description: "Weight or requests of the REST rate limit of the exchange used within the current window"},
+		{Name: telemetryExchangeAPIRemaining, Kind: telemetry.KindGauge, Description: "Weight or requests of the REST rate limit of the exchange left within the current window"},
+		{Name: telemetryLiquidationsStoreRate, Kind: telemetry.KindGauge, Description: "Liquidations stored per second"},
+		{Name: telemetryLiquidationsStoreBaseline, Kind: telemetry.KindGauge, Description: "Trailing baseline of the liquidations stored per second"},
+		{Name: telemetryLiquidationsStoreSpiking, Kind: telemetry.KindGauge, Description: "1 while the liquidation storage rate exceeds its baseline by the spike factor, 0 otherwise"},
+		{Name: telemetryLiquidationsStoreBatchSize, Kind: telemetry.KindGauge, Description: "Liquidations stored per write while the storage rate spikes"},
+		{Name: telemetryHistorySymbols, Kind: telemetry.KindGauge, Description: "Number of symbols with a ticker history"},
+		{Name: telemetryHistoryEntries, Kind: telemetry.KindGauge, Description: "Entries of the tick and ticker ring buffers and buckets of the rolling stats", Tags: []string{"history"}},
+		{Name: telemetryHistoryBytes, Kind: telemetry.KindGauge, Description: "Estimated memory of the tick and ticker histories and of the rolling stats, in bytes", Tags: []string{"history"}},
+		{Name: telemetrySymbolsTracked, Kind: telemetry.KindGauge, Description: "Symbols held by the per-symbol state besides the ticker history", Tags: []string{"state"}},
+		{Name: telemetrySpanImportTick, Kind: telemetry.KindSpan, Description: "Import of a single tick"},
+		{Name: telemetrySpanFetchTickers, Kind: telemetry.KindSpan, Description: "Fetching tickers from the exchange"},
+		{Name: telemetrySpanBuildTick, Kind: telemetry.KindSpan, Description: "Building a tick from fetched data"},
+	}
+}
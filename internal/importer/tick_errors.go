@@ -0,0 +1,37 @@
+package importer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// stageFailures collects the failures of a stage run concurrently for every ticker of a tick
+type stageFailures struct {
+	mu    sync.Mutex
+	count int
+	first error
+}
+
+func (f *stageFailures) add(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.count++
+	if f.first == nil {
+		f.first = err
+	}
+}
+
+// err summarizes the failures, nil when the stage didn't fail
+func (f *stageFailures) err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch f.count {
+	case 0:
+		return nil
+	case 1:
+		return f.first
+	}
+	return fmt.Errorf("%d tickers failed, first: %w", f.count, f.first)
+}
@@ -0,0 +1,200 @@
+package importer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+	"go.uber.org/zap"
+)
+
+// tradesFlushInterval is how often the received trades are stored
+const tradesFlushInterval = time.Second
+
+// tradesStopTimeout bounds storing the pending trades on stop
+const tradesStopTimeout = 5 * time.Second
+
+// tradeVolumesRetention is how long the trades are kept for the tick volumes, so a late tick still finds its window
+const tradeVolumesRetention = 10 * time.Second
+
+// tradeVolume is the USD notional of a trade summed into the tick volumes
+type tradeVolume struct {
+	eventAt time.Time
+	side    domain.OrderSide
+	usd     float64
+}
+
+// tradeVolumes keeps the USD notional of the recent trades, so a tick can sum the taker buy and sell volumes of the
+// second before it started without querying the repository. The trades arrive about in time order, so the expired
+// ones are dropped from the front
+type tradeVolumes struct {
+	mu     sync.Mutex
+	trades []tradeVolume
+	head   int // index of the oldest trade kept, the trades before it expired
+}
+
+func newTradeVolumes() *tradeVolumes {
+	return &tradeVolumes{}
+}
+
+// add records a trade, trades without a known USD value are left out of the volumes
+func (v *tradeVolumes) add(t domain.Trade) {
+	if t.USDValue <= 0 {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.trades = append(v.trades, tradeVolume{eventAt: t.EventAt, side: t.Side, usd: t.USDValue})
+	v.prune(t.EventAt.Add(-tradeVolumesRetention))
+}
+
+// window returns the taker buy and sell volumes of the trades that happened within the second before startAt
+func (v *tradeVolumes) window(startAt time.Time) (buy, sell float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	from := startAt.Add(-time.Second)
+	for _, t := range v.trades[v.head:] {
+		if t.eventAt.Before(from) || !t.eventAt.Before(startAt) {
+			continue
+		}
+		if t.side == domain.OrderSideBuy {
+			buy += t.usd
+		} else {
+			sell += t.usd
+		}
+	}
+	return buy, sell
+}
+
+// prune drops the trades from the front up to the first one that happened at or after the given time, must be
+// called with the lock held. The kept trades are moved back to the start once the dropped ones outnumber them
+func (v *tradeVolumes) prune(before time.Time) {
+	for v.head < len(v.trades) && v.trades[v.head].eventAt.Before(before) {
+		v.head++
+	}
+	if v.head <= len(v.trades)/2 {
+		return
+	}
+	kept := copy(v.trades, v.trades[v.head:])
+	clear(v.trades[kept:])
+	v.trades = v.trades[:kept]
+	v.head = 0
+}
+
+// takerSides maps the normalized taker side to the domain order side, unknown sides fail validation
+var takerSides = map[exchanges.TakerSide]domain.OrderSide{
+	exchanges.TakerBuy:  domain.OrderSideBuy,
+	exchanges.TakerSell: domain.OrderSideSell,
+}
+
+// startTradesImport starts storing the trades of the configured symbols if supported by the exchange
+func (i *Importer) startTradesImport(ctx context.Context) {
+	if len(i.tradeSymbols) == 0 || i.tradeRepository == nil {
+		return
+	}
+	subscriber, ok := i.exchange.(exchanges.TradeSubscriber)
+	if !ok {
+		i.logger.Warn("Trades are not streamed by the exchange, the ticks carry no buy/sell volumes",
+			zap.String("exchange", i.exchange.GetName()))
+		return
+	}
+
+	trades, errs := subscriber.SubscribeTrades(ctx, i.tradeSymbols)
+	i.streamRates.track(eventbus.StageTradesStream)
+	i.logger.Info("Trades import started", zap.Strings("symbols", i.tradeSymbols))
+
+	i.supervisor.Go(ctx, "trades", func(ctx context.Context) error {
+		i.consumeTrades(ctx, trades, errs)
+		return nil
+	})
+}
+
+// consumeTrades adds the trades to the tick volumes and stores them every second until ctx is canceled or the
+// stream is closed, the pending trades are stored on stop too
+func (i *Importer) consumeTrades(ctx context.Context, trades <-chan exchanges.Trade, errs <-chan error) {
+	var pending []domain.Trade
+	timeTicker := time.NewTicker(tradesFlushInterval)
+	defer timeTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tradesStopTimeout)
+			defer cancel()
+			i.storeTrades(stopCtx, pending)
+			i.logger.Info("Trades import stopped (context canceled).")
+			return
+		case t, ok := <-trades:
+			if !ok {
+				i.storeTrades(ctx, pending)
+				return
+			}
+			i.streamRates.observe(eventbus.StageTradesStream)
+			trade := i.convertTradeToDomain(t)
+			if err := trade.Validate(); err != nil {
+				i.logger.Debug("Trade validation failed", zap.String("symbol", t.Symbol), zap.Error(err))
+				continue
+			}
+			i.tradeVolumes.add(trade)
+			pending = append(pending, trade)
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			i.telemetry.IncrementCounter(telemetryTradesErrors, 1)
+			i.publishDegraded(eventbus.StageTradesStream, err)
+			i.logger.Error("Error on trade stream", zap.Error(err))
+		case <-timeTicker.C:
+			i.storeTrades(ctx, pending)
+			pending = nil
+		}
+	}
+}
+
+// convertTradeToDomain converts the exchange Trade to a domain Trade
+func (i *Importer) convertTradeToDomain(t exchanges.Trade) domain.Trade {
+	var usdValue float64
+	if notional, ok := i.usdRates.Load().Notional(t.Price, t.Quantity, t.Quote, t.ContractValue); ok {
+		usdValue = mathutils.RoundTo(notional, i.precision.Notional)
+	}
+	return domain.Trade{
+		Symbol:   domain.TickerName(t.Symbol),
+		ID:       t.ID,
+		Side:     takerSides[t.Side],
+		Price:    t.Price,
+		Quantity: t.Quantity,
+		USDValue: usdValue,
+		EventAt:  t.EventAt,
+		StoredAt: domain.Now(),
+	}
+}
+
+// storeTrades stores the trades received since the previous flush
+func (i *Importer) storeTrades(ctx context.Context, trades []domain.Trade) {
+	if len(trades) == 0 {
+		return
+	}
+	if err := i.tradeRepository.CreateMany(ctx, trades); err != nil {
+		i.publishDegraded(eventbus.StageStoreTrades, err)
+		i.logger.Error("Failed to store trades", zap.Int("count", len(trades)), zap.Error(err))
+		return
+	}
+	i.telemetry.IncrementCounter(telemetryTradesStored, int64(len(trades)))
+}
+
+// setTradeVolumes sets the taker buy and sell volumes of the second before the tick started
+func (i *Importer) setTradeVolumes(tick *domain.Tick) {
+	if i.tradeVolumes == nil {
+		return
+	}
+	buy, sell := i.tradeVolumes.window(tick.StartAt)
+	tick.BuyVolume1 = mathutils.RoundTo(buy, i.precision.Notional)
+	tick.SellVolume1 = mathutils.RoundTo(sell, i.precision.Notional)
+}
@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	exchangeMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tradeExchange is an exchange mock streaming trades
+type tradeExchange struct {
+	*exchangeMocks.ExchangeMock
+	subscribe func(ctx context.Context, symbols []string) (<-chan exchanges.Trade, <-chan error)
+}
+
+func (e *tradeExchange) SubscribeTrades(ctx context.Context, symbols []string) (<-chan exchanges.Trade, <-chan error) {
+	return e.subscribe(ctx, symbols)
+}
+
+func TestTradeVolumes(t *testing.T) {
+	startAt := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+	volumes := newTradeVolumes()
+
+	volumes.add(domain.Trade{Side: domain.OrderSideBuy, USDValue: 100, EventAt: startAt.Add(-1500 * time.Millisecond)}) // previous second
+	volumes.add(domain.Trade{Side: domain.OrderSideBuy, USDValue: 200, EventAt: startAt.Add(-time.Second)})
+	volumes.add(domain.Trade{Side: domain.OrderSideBuy, USDValue: 50, EventAt: startAt.Add(-time.Millisecond)})
+	volumes.add(domain.Trade{Side: domain.OrderSideSell, USDValue: 75, EventAt: startAt.Add(-500 * time.Millisecond)})
+	volumes.add(domain.Trade{Side: domain.OrderSideSell, EventAt: startAt.Add(-500 * time.Millisecond)}) // unknown USD value
+	volumes.add(domain.Trade{Side: domain.OrderSideSell, USDValue: 20, EventAt: startAt})                // next tick
+
+	buy, sell := volumes.window(startAt)
+	assert.Equal(t, 250.0, buy)
+	assert.Equal(t, 75.0, sell)
+
+	volumes.add(domain.Trade{Side: domain.OrderSideBuy, USDValue: 10, EventAt: startAt.Add(tradeVolumesRetention)})
+	buy, sell = volumes.window(startAt)
+	assert.Zero(t, buy, "trades older than the retention are dropped")
+	assert.Zero(t, sell)
+	assert.Len(t, volumes.trades[volumes.head:], 2, "the trades within the retention are kept")
+
+	for n := range 100 {
+		volumes.add(domain.Trade{Side: domain.OrderSideBuy, USDValue: 1, EventAt: startAt.Add(tradeVolumesRetention + time.Duration(n)*time.Second)})
+	}
+	assert.LessOrEqual(t, len(volumes.trades), 2*(len(volumes.trades)-volumes.head), "the expired trades are compacted")
+	buy, _ = volumes.window(startAt.Add(tradeVolumesRetention + 100*time.Second))
+	assert.Equal(t, 1.0, buy)
+}
+
+func TestTradesStoredOnStop(t *testing.T) {
+	ts := setupTest()
+	var storeErrs []error
+	tradeRepo := &domainMocks.TradeRepositoryMock{
+		CreateManyFunc: func(ctx context.Context, trades []domain.Trade) error {
+			storeErrs = append(storeErrs, ctx.Err())
+			return nil
+		},
+	}
+	ts.repoFactory.GetTradeRepositoryFunc = func(name string) (domain.TradeRepository, error) {
+		return tradeRepo, nil
+	}
+	importer := New(&Config{
+		Exchange:          ts.exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		TradeSymbols:      []string{"BTCUSDT"},
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+	trade := exchanges.Trade{Symbol: "BTCUSDT", Quote: "USDT", ID: 1, Side: exchanges.TakerBuy, Price: 100, Quantity: 1, EventAt: time.Now()}
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		trades := make(chan exchanges.Trade)
+		done := make(chan struct{})
+		go func() {
+			importer.consumeTrades(ctx, trades, nil)
+			close(done)
+		}()
+		trades <- trade
+		cancel()
+		<-done
+
+		calls := tradeRepo.CreateManyCalls()
+		require.Len(t, calls, 1)
+		assert.Len(t, calls[0].Trades, 1)
+		assert.NoError(t, storeErrs[0], "stored with a context outliving the canceled one")
+	})
+
+	t.Run("stream closed", func(t *testing.T) {
+		trades := make(chan exchanges.Trade, 1)
+		trades <- trade
+		close(trades)
+		importer.consumeTrades(context.Background(), trades, nil)
+
+		calls := tradeRepo.CreateManyCalls()
+		require.Len(t, calls, 2)
+		assert.Len(t, calls[1].Trades, 1)
+	})
+}
+
+func TestTradesImport(t *testing.T) {
+	ts := setupTest()
+
+	var mu sync.Mutex
+	var stored []domain.Trade
+	tradeRepo := &domainMocks.TradeRepositoryMock{
+		CreateManyFunc: func(ctx context.Context, trades []domain.Trade) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stored = append(stored, trades...)
+			return nil
+		},
+	}
+	ts.repoFactory.GetTradeRepositoryFunc = func(name string) (domain.TradeRepository, error) {
+		return tradeRepo, nil
+	}
+
+	trades := make(chan exchanges.Trade, 2)
+	var subscribed []string
+	exchange := &tradeExchange{
+		ExchangeMock: ts.exchange,
+		subscribe: func(ctx context.Context, symbols []string) (<-chan exchanges.Trade, <-chan error) {
+			subscribed = symbols
+			return trades, make(chan error)
+		},
+	}
+	importer := New(&Config{
+		Exchange:          exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		TradeSymbols:      []string{"BTCUSDT"},
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	importer.startTradesImport(ctx)
+	assert.Equal(t, []string{"BTCUSDT"}, subscribed)
+
+	eventAt := time.Now()
+	trades <- exchanges.Trade{Symbol: "BTCUSDT", Quote: "USDT", ID: 1, Side: exchanges.TakerSell, Price: 100, Quantity: 2, EventAt: eventAt}
+	trades <- exchanges.Trade{Symbol: "BTCUSDT", Quote: "USDT", ID: 2, Side: exchanges.TakerBuy, Quantity: 1, EventAt: eventAt} // no price, dropped
+
+	assert.Eventually(t, func() bool {
+		return len(tradeRepo.CreateManyCalls()) > 0
+	}, 2*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Len(t, stored, 1)
+	assert.Equal(t, domain.OrderSideSell, stored[0].Side)
+	assert.Equal(t, 200.0, stored[0].USDValue)
+	mu.Unlock()
+
+	tick := &domain.Tick{StartAt: eventAt.Add(time.Millisecond)}
+	importer.setTradeVolumes(tick)
+	assert.Zero(t, tick.BuyVolume1)
+	assert.Equal(t, 200.0, tick.SellVolume1)
+}
+
+func TestTradesImportUnsupportedExchange(t *testing.T) {
+	ts := setupTest()
+	ts.repoFactory.GetTradeRepositoryFunc = func(name string) (domain.TradeRepository, error) {
+		return &domainMocks.TradeRepositoryMock{}, nil
+	}
+	importer := New(&Config{
+		Exchange:          ts.exchange,
+		RepositoryFactory: ts.repoFactory,
+		EventBus:          ts.events,
+		TradeSymbols:      []string{"BTCUSDT"},
+		Telemetry:         ts.importer.telemetry,
+		Logger:            ts.importer.logger,
+	})
+	require.NotNil(t, importer)
+
+	assert.NotPanics(t, func() { importer.startTradesImport(context.Background()) })
+}
@@ -0,0 +1,77 @@
+package importer
+
+import (
+	"sync"
+	"time"
+)
+
+// lateWindowOffset places a late liquidation just after the latest counted tick start, in the first window
+// not counted yet, since the windows of a tick include its start
+const lateWindowOffset = time.Millisecond
+
+// skewBuckets is the number of one-second buckets the largest lateness is kept over
+const skewBuckets = 60
+
+// liquidationWatermark assigns streamed liquidations to the LL/SL window they are counted in.
+// Ticks count the liquidations of the windows ending at their start, the watermark is the start of the latest
+// counted tick: a liquidation arriving after the window of its event time was counted is counted in the first
+// window not counted yet instead of never. It also keeps the largest lateness of the last minute
+type liquidationWatermark struct {
+	mu        sync.Mutex
+	countedAt time.Time
+	skews     [skewBuckets]skewBucket
+}
+
+// skewBucket is the largest lateness of the liquidations arrived within a second
+type skewBucket struct {
+	second int64
+	max    time.Duration
+}
+
+func newLiquidationWatermark() *liquidationWatermark {
+	return &liquidationWatermark{}
+}
+
+// assign returns the window time of a liquidation arrived at receivedAt and whether it arrived after
+// the window of its event time was counted
+func (w *liquidationWatermark) assign(eventAt, receivedAt time.Time) (time.Time, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	second := receivedAt.Unix()
+	bucket := &w.skews[second%skewBuckets]
+	if bucket.second != second {
+		*bucket = skewBucket{second: second}
+	}
+	bucket.max = max(bucket.max, receivedAt.Sub(eventAt))
+
+	if eventAt.After(w.countedAt) {
+		return eventAt, false
+	}
+	return w.countedAt.Add(lateWindowOffset), true
+}
+
+// advance moves the watermark to the start of a tick whose liquidation windows were counted
+func (w *liquidationWatermark) advance(countedAt time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if countedAt.After(w.countedAt) {
+		w.countedAt = countedAt
+	}
+}
+
+// maxSkew returns the largest lateness of the liquidations arrived within the minute before now
+func (w *liquidationWatermark) maxSkew(now time.Time) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var skew time.Duration
+	since := now.Unix() - skewBuckets
+	for _, bucket := range w.skews {
+		if bucket.second > since {
+			skew = max(skew, bucket.max)
+		}
+	}
+	return skew
+}
@@ -0,0 +1,42 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiquidationWatermark_Assign(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC)
+	watermark := newLiquidationWatermark()
+
+	windowAt, late := watermark.assign(now.Add(-3*time.Second), now)
+	assert.Equal(t, now.Add(-3*time.Second), windowAt, "nothing counted yet")
+	assert.False(t, late)
+
+	watermark.advance(now)
+	watermark.advance(now.Add(-time.Second))
+
+	windowAt, late = watermark.assign(now.Add(-2*time.Second), now.Add(100*time.Millisecond))
+	assert.Equal(t, now.Add(lateWindowOffset), windowAt, "counted in the first open window")
+	assert.True(t, late)
+
+	windowAt, late = watermark.assign(now.Add(50*time.Millisecond), now.Add(100*time.Millisecond))
+	assert.Equal(t, now.Add(50*time.Millisecond), windowAt)
+	assert.False(t, late)
+}
+
+func TestLiquidationWatermark_MaxSkew(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	watermark := newLiquidationWatermark()
+	assert.Zero(t, watermark.maxSkew(now))
+
+	watermark.assign(now.Add(-4*time.Second), now)
+	watermark.assign(now.Add(10*time.Second), now.Add(11*time.Second))
+	assert.Equal(t, 4*time.Second, watermark.maxSkew(now.Add(11*time.Second)))
+
+	// the lateness of a minute ago is forgotten
+	assert.Equal(t, time.Second, watermark.maxSkew(now.Add(time.Minute)))
+	assert.Zero(t, watermark.maxSkew(now.Add(2*time.Minute)))
+}
@@ -2,7 +2,11 @@ package infrastructure
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,22 +15,129 @@ import (
 	"go.uber.org/zap"
 )
 
-// NewRedisClient creates a new Redis client to inject into the other services
-func NewRedisClient(ctx context.Context, url string, maxConns int) (*redis.Client, error) {
-	opt, err := redis.ParseURL(url)
+// Redis deployments NewRedisClient connects to
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeCluster    = "cluster"
+	RedisModeSentinel   = "sentinel"
+)
+
+// RedisConfig describes the Redis deployment NewRedisClient connects to
+type RedisConfig struct {
+	Mode       string   // RedisModeStandalone when empty
+	URL        string   // standalone: redis:// or rediss:// URL of the server
+	Addrs      []string // cluster: seed nodes, sentinel: sentinels, as host:port
+	MasterName string   // sentinel: name of the master monitored by the sentinels
+	DB         int      // sentinel: database of the master, the URL selects it in standalone mode
+
+	Username         string // ACL user of the Redis servers, overrides the user of the URL
+	Password         string // password of the Redis servers, overrides the password of the URL
+	SentinelUsername string // ACL user of the sentinels
+	SentinelPassword string // password of the sentinels
+
+	TLS           bool   // connect over TLS, implied by a rediss:// URL
+	TLSCAFile     string // PEM file of the CAs verifying the servers, the system roots when empty
+	TLSSkipVerify bool   // don't verify the certificates of the servers, e.g. self-signed ones of a test setup
+
+	PoolSize     int // maximum connections per server, 10 per CPU when 0
+	MinIdleConns int // connections per server kept open while idle
+}
+
+// NewRedisClient creates a new Redis client to inject into the other services: a client of a standalone server,
+// a cluster client routing the commands to the nodes or a client of the master the sentinels fail over to
+func NewRedisClient(ctx context.Context, cfg RedisConfig) (redis.UniversalClient, error) {
+	tlsConfig, err := cfg.tlsConfig()
 	if err != nil {
 		return nil, err
 	}
-	opt.PoolSize = maxConns
 
-	client := redis.NewClient(opt)
+	var client redis.UniversalClient
+	switch cfg.Mode {
+	case "", RedisModeStandalone:
+		opt, err := redis.ParseURL(cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Username != "" {
+			opt.Username = cfg.Username
+		}
+		if cfg.Password != "" {
+			opt.Password = cfg.Password
+		}
+		if tlsConfig != nil {
+			if opt.TLSConfig != nil {
+				tlsConfig.ServerName = opt.TLSConfig.ServerName
+			}
+			opt.TLSConfig = tlsConfig
+		}
+		if cfg.PoolSize > 0 {
+			opt.PoolSize = cfg.PoolSize
+		}
+		opt.MinIdleConns = cfg.MinIdleConns
+		client = redis.NewClient(opt)
+	case RedisModeCluster:
+		if len(cfg.Addrs) == 0 {
+			return nil, fmt.Errorf("redis cluster: no node addresses")
+		}
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			TLSConfig:    tlsConfig,
+			PoolSize:     cfg.PoolSize,
+			MinIdleConns: cfg.MinIdleConns,
+		})
+	case RedisModeSentinel:
+		if len(cfg.Addrs) == 0 || cfg.MasterName == "" {
+			return nil, fmt.Errorf("redis sentinel: sentinel addresses and master name are required")
+		}
+		client = redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelUsername: cfg.SentinelUsername,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			TLSConfig:        tlsConfig,
+			PoolSize:         cfg.PoolSize,
+			MinIdleConns:     cfg.MinIdleConns,
+		})
+	default:
+		return nil, fmt.Errorf("unknown redis mode: %q", cfg.Mode)
+	}
+
 	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
 		return nil, err
 	}
 
 	return client, nil
 }
 
+// tlsConfig returns the TLS configuration of the connections, nil without TLS.
+// A rediss:// URL enables TLS on its own, the CA file and verification still apply to it
+func (cfg RedisConfig) tlsConfig() (*tls.Config, error) {
+	if !cfg.TLS && !strings.HasPrefix(cfg.URL, "rediss://") {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.TLSSkipVerify,
+	}
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading redis CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in redis CA file %s", cfg.TLSCAFile)
+		}
+	}
+	return tlsConfig, nil
+}
+
 // NewMongoClient creates a new MongoDB client to inject into the other services
 func NewMongoClient(ctx context.Context, uri string) (*mongo.Client, error) {
 	clientOptions := options.Client().ApplyURI(uri)
@@ -48,16 +159,82 @@ func NewMongoClient(ctx context.Context, uri string) (*mongo.Client, error) {
 	return client, nil
 }
 
+// defaultLogSamplingThereafter is how many of the repeated entries past the initial ones are logged one of,
+// when sampling is set without it
+const defaultLogSamplingThereafter = 100
+
+// LoggerConfig describes how NewLoggerWithConfig builds the logger
+type LoggerConfig struct {
+	Env     string
+	Service string
+
+	// Level is a zap level name (debug, info, warn, error...). Defaults to debug in development, info otherwise
+	Level string
+	// Format is either "json" or "console". Defaults to console in development, json otherwise
+	Format string
+	// SamplingInitial and SamplingThereafter control sampling of repeated messages per second:
+	// the first SamplingInitial entries are logged, then every SamplingThereafter-th (every 100th when zero).
+	// Zero keeps the zap defaults (100/100 in production, none in development), a negative value disables sampling
+	SamplingInitial    int
+	SamplingThereafter int
+}
+
 // NewLogger creates a new logger to inject into the other services
 func NewLogger(env, service string) (*zap.Logger, error) {
-	logger, _ := zap.NewProduction(zap.Fields(
-		zap.String("env", env),
-		zap.String("service", service),
-	))
+	logger, _, err := NewLoggerWithConfig(LoggerConfig{Env: env, Service: service})
+	return logger, err
+}
 
-	if env == "" || env == "development" {
-		logger, _ = zap.NewDevelopment()
+// NewLoggerWithConfig creates a new logger together with its level, which can be changed at runtime
+func NewLoggerWithConfig(cfg LoggerConfig) (*zap.Logger, zap.AtomicLevel, error) {
+	development := cfg.Env == "" || cfg.Env == "development"
+
+	zapCfg := zap.NewProductionConfig()
+	if development {
+		zapCfg = zap.NewDevelopmentConfig()
+	}
+
+	if cfg.Level != "" {
+		level, err := zap.ParseAtomicLevel(cfg.Level)
+		if err != nil {
+			return nil, zap.AtomicLevel{}, fmt.Errorf("parsing log level: %w", err)
+		}
+		zapCfg.Level = level
 	}
 
-	return logger, nil
+	switch cfg.Format {
+	case "":
+	case "json", "console":
+		zapCfg.Encoding = cfg.Format
+	default:
+		return nil, zap.AtomicLevel{}, fmt.Errorf("unknown log format: %q", cfg.Format)
+	}
+
+	switch {
+	case cfg.SamplingInitial < 0:
+		zapCfg.Sampling = nil
+	case cfg.SamplingInitial > 0:
+		thereafter := cfg.SamplingThereafter
+		if thereafter <= 0 {
+			// zap drops every entry past the initial ones without it
+			thereafter = defaultLogSamplingThereafter
+		}
+		zapCfg.Sampling = &zap.SamplingConfig{
+			Initial:    cfg.SamplingInitial,
+			Thereafter: thereafter,
+		}
+	}
+
+	if !development {
+		zapCfg.InitialFields = map[string]any{
+			"env":     cfg.Env,
+			"service": cfg.Service,
+		}
+	}
+
+	logger, err := zapCfg.Build()
+	if err != nil {
+		return nil, zap.AtomicLevel{}, fmt.Errorf("building logger: %w", err)
+	}
+	return logger, zapCfg.Level, nil
 }
@@ -0,0 +1,160 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewLoggerWithConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       LoggerConfig
+		wantLevel zapcore.Level
+		wantErr   bool
+	}{
+		{
+			name:      "development defaults to debug",
+			cfg:       LoggerConfig{Env: "development"},
+			wantLevel: zapcore.DebugLevel,
+		},
+		{
+			name:      "production defaults to info",
+			cfg:       LoggerConfig{Env: "production", Service: "svc"},
+			wantLevel: zapcore.InfoLevel,
+		},
+		{
+			name:      "explicit level and format override env defaults",
+			cfg:       LoggerConfig{Env: "production", Level: "warn", Format: "console", SamplingInitial: 10, SamplingThereafter: 5},
+			wantLevel: zapcore.WarnLevel,
+		},
+		{
+			name:      "sampling can be disabled",
+			cfg:       LoggerConfig{Env: "production", SamplingInitial: -1},
+			wantLevel: zapcore.InfoLevel,
+		},
+		{
+			name:    "invalid level",
+			cfg:     LoggerConfig{Level: "loud"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			cfg:     LoggerConfig{Format: "xml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, level, err := NewLoggerWithConfig(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, logger)
+			assert.Equal(t, tt.wantLevel, level.Level())
+		})
+	}
+}
+
+func TestNewLoggerWithConfig_SamplingThereafterDefault(t *testing.T) {
+	stderr := os.Stderr
+	out, err := os.Create(filepath.Join(t.TempDir(), "stderr"))
+	require.NoError(t, err)
+	os.Stderr = out
+	defer func() { os.Stderr = stderr }()
+
+	logger, _, err := NewLoggerWithConfig(LoggerConfig{Env: "production", SamplingInitial: 10})
+	os.Stderr = stderr
+	require.NoError(t, err)
+
+	for range 150 {
+		logger.Info("repeated")
+	}
+	_ = logger.Sync()
+
+	logged, err := os.ReadFile(out.Name())
+	require.NoError(t, err)
+	assert.Equal(t, 11, strings.Count(string(logged), "repeated"), "the 10 initial entries and the 100th after them")
+}
+
+func TestNewLoggerWithConfig_DynamicLevel(t *testing.T) {
+	logger, level, err := NewLoggerWithConfig(LoggerConfig{Env: "production", Level: "error"})
+	assert.NoError(t, err)
+	assert.False(t, logger.Core().Enabled(zapcore.InfoLevel))
+
+	level.SetLevel(zapcore.DebugLevel)
+	assert.True(t, logger.Core().Enabled(zapcore.DebugLevel))
+}
+
+func TestNewRedisClient_Standalone(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireUserAuth("importer", "secret")
+
+	_, err := NewRedisClient(context.Background(), RedisConfig{URL: "redis://" + server.Addr()})
+	require.Error(t, err, "the server requires auth")
+
+	client, err := NewRedisClient(context.Background(), RedisConfig{
+		URL:          "redis://wrong:wrong@" + server.Addr(),
+		Username:     "importer",
+		Password:     "secret",
+		PoolSize:     4,
+		MinIdleConns: 1,
+	})
+	require.NoError(t, err, "the credentials override the URL")
+	defer client.Close()
+	require.IsType(t, &redis.Client{}, client)
+	assert.Equal(t, 4, client.(*redis.Client).Options().PoolSize)
+}
+
+func TestNewRedisClient_Cluster(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	client, err := NewRedisClient(context.Background(), RedisConfig{Mode: RedisModeCluster, Addrs: []string{server.Addr()}})
+	require.NoError(t, err)
+	defer client.Close()
+	require.IsType(t, &redis.ClusterClient{}, client)
+	assert.NoError(t, client.Publish(context.Background(), "importer:MARKET_DATA", "{}").Err())
+}
+
+func TestNewRedisClient_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewRedisClient(ctx, RedisConfig{Mode: RedisModeCluster})
+	assert.EqualError(t, err, "redis cluster: no node addresses")
+	_, err = NewRedisClient(ctx, RedisConfig{Mode: RedisModeSentinel, Addrs: []string{"localhost:26379"}})
+	assert.EqualError(t, err, "redis sentinel: sentinel addresses and master name are required")
+	_, err = NewRedisClient(ctx, RedisConfig{Mode: "ring"})
+	assert.EqualError(t, err, `unknown redis mode: "ring"`)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+	_, err = NewRedisClient(ctx, RedisConfig{URL: "redis://localhost:6379", TLS: true, TLSCAFile: caFile})
+	assert.EqualError(t, err, "no certificates in redis CA file "+caFile)
+}
+
+func TestRedisConfig_TLSConfig(t *testing.T) {
+	tlsConfig, err := RedisConfig{URL: "redis://localhost:6379"}.tlsConfig()
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig, "plain connections without TLS")
+
+	tlsConfig, err = RedisConfig{URL: "rediss://localhost:6380", TLSSkipVerify: true}.tlsConfig()
+	require.NoError(t, err)
+	require.NotNil(t, tlsConfig, "a rediss:// URL enables TLS")
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+
+	tlsConfig, err = RedisConfig{Mode: RedisModeCluster, TLS: true}.tlsConfig()
+	require.NoError(t, err)
+	assert.NotNil(t, tlsConfig)
+}
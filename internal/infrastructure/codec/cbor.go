@@ -0,0 +1,297 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// CBOR major types
+const (
+	cborUint   = 0
+	cborNegint = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+// cborCodec encodes the JSON document of a value as CBOR, so the field names and formats (e.g. RFC3339 times)
+// are those of the JSON tags and the JSON codec. Maps are written with sorted keys, floats as float32 when it's
+// lossless. Indefinite lengths aren't written nor read
+type cborCodec struct{}
+
+func (cborCodec) Name() string { return "cbor" }
+
+func (cborCodec) Marshal(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeCBOR(&buf, document); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (cborCodec) Unmarshal(data []byte, v any) error {
+	r := &cborReader{data: data}
+	document, err := r.read(0)
+	if err != nil {
+		return fmt.Errorf("cbor: %w", err)
+	}
+	if r.pos != len(data) {
+		return fmt.Errorf("cbor: %d trailing bytes", len(data)-r.pos)
+	}
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, v)
+}
+
+// writeCBOR writes a decoded JSON document
+func writeCBOR(buf *bytes.Buffer, value any) error {
+	switch value := value.(type) {
+	case nil:
+		buf.WriteByte(cborSimple<<5 | 22)
+	case bool:
+		if value {
+			buf.WriteByte(cborSimple<<5 | 21)
+		} else {
+			buf.WriteByte(cborSimple<<5 | 20)
+		}
+	case json.Number:
+		return writeCBORNumber(buf, value)
+	case string:
+		writeCBORHead(buf, cborText, uint64(len(value)))
+		buf.WriteString(value)
+	case []any:
+		writeCBORHead(buf, cborArray, uint64(len(value)))
+		for _, item := range value {
+			if err := writeCBOR(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		writeCBORHead(buf, cborMap, uint64(len(value)))
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			writeCBORHead(buf, cborText, uint64(len(key)))
+			buf.WriteString(key)
+			if err := writeCBOR(buf, value[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cbor: unexpected %T in the JSON document", value)
+	}
+	return nil
+}
+
+// writeCBORNumber writes a JSON number as an integer when it is one, as a float otherwise
+func writeCBORNumber(buf *bytes.Buffer, number json.Number) error {
+	if n, err := strconv.ParseInt(number.String(), 10, 64); err == nil {
+		if n < 0 {
+			writeCBORHead(buf, cborNegint, uint64(-1-n))
+		} else {
+			writeCBORHead(buf, cborUint, uint64(n))
+		}
+		return nil
+	}
+	if n, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+		writeCBORHead(buf, cborUint, n)
+		return nil
+	}
+
+	f, err := number.Float64()
+	if err != nil {
+		return fmt.Errorf("cbor: %w", err)
+	}
+	if f32 := float32(f); float64(f32) == f {
+		buf.WriteByte(cborSimple<<5 | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(f32)))
+		return nil
+	}
+	buf.WriteByte(cborSimple<<5 | 27)
+	buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+	return nil
+}
+
+// writeCBORHead writes the initial byte of an item and its argument in the fewest bytes
+func writeCBORHead(buf *bytes.Buffer, major byte, arg uint64) {
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= math.MaxUint8:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(arg)))
+	case arg <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(arg)))
+	default:
+		buf.WriteByte(major<<5 | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, arg))
+	}
+}
+
+// maxCBORDepth bounds the nesting of the items read, so a corrupted blob can't exhaust the stack
+const maxCBORDepth = 64
+
+// cborReader reads CBOR items as the values of a decoded JSON document
+type cborReader struct {
+	data []byte
+	pos  int
+}
+
+// read reads the next item
+func (r *cborReader) read(depth int) (any, error) {
+	if depth > maxCBORDepth {
+		return nil, fmt.Errorf("items nested deeper than %d", maxCBORDepth)
+	}
+	major, info, arg, err := r.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUint:
+		return arg, nil
+	case cborNegint:
+		if arg > math.MaxInt64 {
+			return nil, fmt.Errorf("negative integer overflows int64")
+		}
+		return -1 - int64(arg), nil
+	case cborBytes:
+		return r.next(arg)
+	case cborText:
+		text, err := r.next(arg)
+		return string(text), err
+	case cborArray:
+		items := make([]any, 0, min(arg, uint64(len(r.data)-r.pos)))
+		for range arg {
+			item, err := r.read(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case cborMap:
+		items := make(map[string]any, min(arg, uint64(len(r.data)-r.pos)))
+		for range arg {
+			key, err := r.read(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("map key is a %T, not a text", key)
+			}
+			if items[name], err = r.read(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	case cborTag:
+		return r.read(depth + 1)
+	default:
+		switch info {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22, 23:
+			return nil, nil
+		case 25:
+			return float16(uint16(arg)), nil
+		case 26:
+			return float64(math.Float32frombits(uint32(arg))), nil
+		case 27:
+			return math.Float64frombits(arg), nil
+		}
+		return nil, fmt.Errorf("unsupported simple value %d", info)
+	}
+}
+
+// head reads the initial byte of an item and its argument
+func (r *cborReader) head() (major, info byte, arg uint64, err error) {
+	if r.pos >= len(r.data) {
+		return 0, 0, 0, fmt.Errorf("unexpected end of data")
+	}
+	initial := r.data[r.pos]
+	r.pos++
+	major, info = initial>>5, initial&0x1f
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		argument, err := r.next(uint64(size))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for _, b := range argument {
+			arg = arg<<8 | uint64(b)
+		}
+		return major, info, arg, nil
+	case info == 31:
+		return 0, 0, 0, fmt.Errorf("indefinite lengths aren't supported")
+	default:
+		return 0, 0, 0, fmt.Errorf("reserved additional information %d", info)
+	}
+}
+
+// next returns the next n bytes
+func (r *cborReader) next(n uint64) ([]byte, error) {
+	if n > uint64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	data := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return data, nil
+}
+
+// float16 converts a half-precision float
+func float16(bits uint16) float64 {
+	exponent := int(bits>>10) & 0x1f
+	mantissa := float64(bits & 0x3ff)
+	var value float64
+	switch exponent {
+	case 0:
+		value = math.Ldexp(mantissa, -24)
+	case 0x1f:
+		value = math.Inf(1)
+		if mantissa != 0 {
+			value = math.NaN()
+		}
+	default:
+		value = math.Ldexp(mantissa+1024, exponent-25)
+	}
+	if bits&0x8000 != 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,108 @@
+// Package codec provides the formats the repositories and the file sink serialize domain objects with when they
+// store them as blobs
+package codec
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Codec serializes domain objects to the bytes stored by a repository
+type Codec interface {
+	// Name identifies the codec, it is stored with every blob so blobs of different codecs can be read back
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Sink is a backend storing domain objects, implemented by the persistent repository factories and the file sink,
+// so every backend takes the codec of the options the same way. A sink rejects the codecs it can't store
+type Sink interface {
+	SetCodec(c Codec) error
+}
+
+var (
+	// JSON stores readable text, the default
+	JSON Codec = jsonCodec{}
+
+	// BSON stores binary documents, the format of the mongo repository. Times keep millisecond precision only
+	BSON Codec = bsonCodec{}
+
+	// GzipJSON stores gzip-compressed JSON, the most compact for the large tick documents
+	GzipJSON Codec = gzipJSONCodec{}
+
+	// CBOR stores binary documents (RFC 8949) with the field names of JSON, more compact than JSON and BSON
+	CBOR Codec = cborCodec{}
+)
+
+// Codecs are the available codecs
+var Codecs = []Codec{JSON, BSON, GzipJSON, CBOR}
+
+// Names returns the names of the available codecs
+func Names() []string {
+	names := make([]string, len(Codecs))
+	for i, c := range Codecs {
+		names[i] = c.Name()
+	}
+	return names
+}
+
+// Lookup returns the codec of the given name, an empty name is JSON as blobs were stored before codecs existed
+func Lookup(name string) (Codec, error) {
+	if name == "" {
+		return JSON, nil
+	}
+	i := slices.IndexFunc(Codecs, func(c Codec) bool { return c.Name() == name })
+	if i < 0 {
+		return nil, fmt.Errorf("unknown codec %q", name)
+	}
+	return Codecs[i], nil
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                       { return "json" }
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type bsonCodec struct{}
+
+func (bsonCodec) Name() string                       { return "bson" }
+func (bsonCodec) Marshal(v any) ([]byte, error)      { return bson.Marshal(v) }
+func (bsonCodec) Unmarshal(data []byte, v any) error { return bson.Unmarshal(data, v) }
+
+type gzipJSONCodec struct{}
+
+func (gzipJSONCodec) Name() string { return "json-gzip" }
+
+func (gzipJSONCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gzipJSONCodec) Unmarshal(data []byte, v any) error {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	data, err = io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,116 @@
+package codec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := domain.Tick{
+		StartAt:   startAt,
+		CreatedAt: startAt.Add(150 * time.Millisecond),
+		LL5:       3,
+		Skipped:   []domain.TickerName{"DOGEUSDT"},
+		Avg:       domain.TickAvg{TickersCount: 1},
+		Data: map[domain.TickerName]*domain.Ticker{
+			"BTCUSDT": {Symbol: "BTCUSDT", CreatedAt: startAt, Ask: 50000, Bid: 49900, LastLiquidation: &domain.LastLiquidation{Price: 49800, Side: domain.OrderSideBuy, Age: 1500}},
+		},
+	}
+
+	for _, c := range Codecs {
+		t.Run(c.Name(), func(t *testing.T) {
+			data, err := c.Marshal(tick)
+			require.NoError(t, err)
+
+			var got domain.Tick
+			require.NoError(t, c.Unmarshal(data, &got))
+			assert.Equal(t, tick, got)
+		})
+	}
+}
+
+func TestGzipJSON_Compact(t *testing.T) {
+	tick := domain.Tick{Data: make(map[domain.TickerName]*domain.Ticker)}
+	for _, symbol := range []domain.TickerName{"BTCUSDT", "ETHUSDT", "SOLUSDT", "XRPUSDT", "DOGEUSDT"} {
+		tick.Data[symbol] = &domain.Ticker{Symbol: symbol, Ask: 1, Bid: 1}
+	}
+
+	text, err := JSON.Marshal(tick)
+	require.NoError(t, err)
+	compressed, err := GzipJSON.Marshal(tick)
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(text)/2)
+}
+
+func TestLookup(t *testing.T) {
+	for _, c := range Codecs {
+		got, err := Lookup(c.Name())
+		require.NoError(t, err)
+		assert.Equal(t, c, got)
+	}
+
+	got, err := Lookup("")
+	require.NoError(t, err)
+	assert.Equal(t, JSON, got, "rows stored before codecs existed are JSON")
+
+	_, err = Lookup("xml")
+	assert.EqualError(t, err, `unknown codec "xml"`)
+}
+
+func TestCBOR_Encoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  []byte
+	}{
+		{"small integer", 10, []byte{0x0a}},
+		{"negative integer", -500, []byte{0x39, 0x01, 0xf3}},
+		{"large integer", uint64(1) << 40, []byte{0x1b, 0, 0, 0x01, 0, 0, 0, 0, 0}},
+		{"float32", 1.5, []byte{0xfa, 0x3f, 0xc0, 0, 0}},
+		{"float64", 0.1, []byte{0xfb, 0x3f, 0xb9, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}},
+		{"sorted map", map[string]any{"b": true, "a": nil}, []byte{0xa2, 0x61, 'a', 0xf6, 0x61, 'b', 0xf5}},
+		{"array", []string{"x"}, []byte{0x81, 0x61, 'x'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CBOR.Marshal(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCBOR_Decoding(t *testing.T) {
+	var half struct {
+		A float64 `json:"a"`
+	}
+	require.NoError(t, CBOR.Unmarshal([]byte{0xa1, 0x61, 'a', 0xf9, 0x3e, 0x00}, &half), "half-precision floats of other encoders")
+	assert.Equal(t, 1.5, half.A)
+
+	var value any
+	assert.EqualError(t, CBOR.Unmarshal([]byte{0x9f, 0xff}, &value), "cbor: indefinite lengths aren't supported")
+	assert.EqualError(t, CBOR.Unmarshal([]byte{0x82, 0x01}, &value), "cbor: unexpected end of data")
+	assert.EqualError(t, CBOR.Unmarshal([]byte{0x01, 0x02}, &value), "cbor: 1 trailing bytes")
+	assert.EqualError(t, CBOR.Unmarshal([]byte{0xa1, 0x01, 0x02}, &value), "cbor: map key is a uint64, not a text")
+}
+
+func TestCBOR_Compact(t *testing.T) {
+	tick := domain.Tick{Data: make(map[domain.TickerName]*domain.Ticker)}
+	for _, symbol := range []domain.TickerName{"BTCUSDT", "ETHUSDT", "SOLUSDT", "XRPUSDT", "DOGEUSDT"} {
+		tick.Data[symbol] = &domain.Ticker{Symbol: symbol, Ask: 1, Bid: 1}
+	}
+
+	text, err := JSON.Marshal(tick)
+	require.NoError(t, err)
+	binary, err := BSON.Marshal(tick)
+	require.NoError(t, err)
+	compact, err := CBOR.Marshal(tick)
+	require.NoError(t, err)
+	assert.Less(t, len(compact), len(text))
+	assert.Less(t, len(compact), len(binary))
+}
@@ -3,10 +3,13 @@ package binance
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
@@ -35,16 +38,29 @@ type Config struct {
 	// WSUrl is the websocket endpoint URL
 	WSUrl string
 
+	// StreamWSUrl is the combined streams endpoint URL used for book tickers
+	StreamWSUrl string
+
 	// HTTPClient is a custom HTTP client for making requests
 	HTTPClient *http.Client
+
+	// EventAtFallback stamps the tickers Binance gave no time for, defaults to the exchange response time
+	EventAtFallback exchanges.EventAtFallback
+
+	// Websocket configures the dialer of the liquidation and book ticker streams
+	Websocket exchanges.WebsocketConfig
 }
 
 // Client implements a Binance exchange client
 type Client struct {
-	name       string
-	httpURL    string
-	wsURL      string
-	httpClient *http.Client
+	name        string
+	httpURL     string
+	wsURL       string
+	streamWSURL string
+	httpClient  *http.Client
+	wsConfig    exchanges.WebsocketConfig
+	budget      *exchanges.APIBudget
+	eventAt     exchanges.EventAtFallback
 }
 
 // NewBinance creates a new Binance client with the provided configuration
@@ -55,6 +71,9 @@ func NewBinance(cfg Config) *Client {
 	if cfg.WSUrl == "" {
 		cfg.WSUrl = FuturesWSUrl
 	}
+	if cfg.StreamWSUrl == "" {
+		cfg.StreamWSUrl = FuturesStreamWSUrl
+	}
 	if cfg.APIUrl == "" {
 		cfg.APIUrl = FuturesAPIURL
 	}
@@ -63,21 +82,28 @@ func NewBinance(cfg Config) *Client {
 	}
 
 	return &Client{
-		name:       cfg.Name,
-		httpURL:    cfg.APIUrl,
-		wsURL:      cfg.WSUrl,
-		httpClient: cfg.HTTPClient,
+		name:        cfg.Name,
+		httpURL:     cfg.APIUrl,
+		wsURL:       cfg.WSUrl,
+		streamWSURL: cfg.StreamWSUrl,
+		httpClient:  cfg.HTTPClient,
+		wsConfig:    cfg.Websocket,
+		budget:      exchanges.NewAPIBudget(WeightLimit, WeightWindow),
+		eventAt:     cfg.EventAtFallback,
 	}
 }
 
-//------------------------------------------------------------------------------
-// Fetch Tickers API Methods
-//------------------------------------------------------------------------------
+// APIUsage returns the request weight used within the current minute
+func (bc *Client) APIUsage() exchanges.APIUsage {
+	return bc.budget.Usage()
+}
 
-// FetchTickers retrieves current ticker information for all trading pairs
-// It returns a slice of normalized Ticker objects or an error if the request fails
-func (bc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error) {
-	url := bc.httpURL + FetchTickersData
+// get sends a GET request of the given weight unless it would exceed the weight limit, and accounts the weight
+// used as reported by Binance. Rate limited and banned responses block the requests until their Retry-After
+func (bc *Client) get(ctx context.Context, url string, weight float64) (*http.Response, error) {
+	if err := bc.budget.Acquire(weight); err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
@@ -88,14 +114,39 @@ func (bc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error)
 	if err != nil {
 		return nil, fmt.Errorf("executing request for %s: %w", url, err)
 	}
+
+	if used, err := strconv.ParseFloat(resp.Header.Get(UsedWeightHeader), 64); err == nil {
+		bc.budget.Observe(used, 0)
+	}
+	if bc.budget.BlockRetryAfter(resp) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: status %s from %s", exchanges.ErrThrottled, resp.Status, url)
+	}
+	return resp, nil
+}
+
+//------------------------------------------------------------------------------
+// Fetch Tickers API Methods
+//------------------------------------------------------------------------------
+
+// FetchTickers retrieves current ticker information for all trading pairs
+// It returns the normalized tickers, along with an *exchanges.ConversionError if some of them were dropped
+func (bc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error) {
+	url := bc.httpURL + FetchTickersData
+
+	resp, err := bc.get(ctx, url, FetchTickersWeight)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
 	}
+	receivedAt := time.Now().UTC()
 
 	var binanceTickers []TickerDTO
-	err = json.NewDecoder(resp.Body).Decode(&binanceTickers)
+	err = exchanges.JSON.NewDecoder(resp.Body).Decode(&binanceTickers)
 	if err != nil {
 		return nil, fmt.Errorf("decoding response from %s: %w", url, err)
 	}
@@ -106,23 +157,31 @@ func (bc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error)
 		return nil, fmt.Errorf("validating market data: %w", err)
 	}
 
-	return convertTickers(filteredTickers), nil
+	// the body carries no server time, the Date header is precise to the second
+	responseAt, _ := http.ParseTime(resp.Header.Get("Date"))
+	tickers, convErrs := convertTickers(filteredTickers, bc.eventAt, responseAt, receivedAt)
+	return tickers, exchanges.NewConversionError(len(filteredTickers), convErrs)
 }
 
-// convertTickers converts Binance-specific ticker DTOs to normalized tickers
-func convertTickers(binanceTickers []TickerDTO) []exchanges.Ticker {
+// convertTickers converts Binance-specific ticker DTOs to normalized tickers, skipping and reporting invalid ones
+func convertTickers(binanceTickers []TickerDTO, fallback exchanges.EventAtFallback, responseAt, receivedAt time.Time) ([]exchanges.Ticker, []error) {
 	tickers := make([]exchanges.Ticker, 0, len(binanceTickers))
+	var errs []error
 
 	for _, bt := range binanceTickers {
 		ticker, err := bt.toTicker()
 		if err != nil {
-			log.Printf("Warning: failed to convert ticker: %v", err)
+			errs = append(errs, fmt.Errorf("%s: %w", bt.Symbol, err))
+			continue
+		}
+		if err := fallback.Stamp(&ticker, responseAt, receivedAt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", bt.Symbol, err))
 			continue
 		}
 		tickers = append(tickers, ticker)
 	}
 
-	return tickers
+	return tickers, errs
 }
 
 //------------------------------------------------------------------------------
@@ -155,12 +214,17 @@ func (bc *Client) handleLiquidationSubscription(ctx context.Context, out chan<-
 			}
 		}
 
+		delay := bc.wsConfig.ReconnectDelayOr(DefaultReconnectDelay)
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			log.Printf("Reconnecting in %s...", DefaultReconnectDelay)
-			time.Sleep(DefaultReconnectDelay)
+			log.Printf("Reconnecting in %s...", delay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
 		}
 	}
 }
@@ -168,12 +232,16 @@ func (bc *Client) handleLiquidationSubscription(ctx context.Context, out chan<-
 // connectAndHandle establishes and manages a single websocket connection
 // It connects and reads messages from the websocket
 func (bc *Client) connectAndHandle(ctx context.Context, out chan<- exchanges.Liquidation, errCh chan<- error) error {
-	conn, _, err := websocket.DefaultDialer.Dial(bc.wsURL, nil)
+	conn, err := bc.wsConfig.Dial(ctx, bc.wsURL)
 	if err != nil {
 		return fmt.Errorf("websocket dial: %w", err)
 	}
 	defer conn.Close()
 
+	// unblock ReadMessage on shutdown
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
 	return bc.readMessages(ctx, conn, out, errCh)
 }
 
@@ -190,6 +258,9 @@ func (bc *Client) readMessages(ctx context.Context, conn *websocket.Conn, out ch
 
 			_, msg, err := conn.ReadMessage()
 			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
 				return fmt.Errorf("reading message: %w", err)
 			}
 
@@ -203,7 +274,7 @@ func (bc *Client) readMessages(ctx context.Context, conn *websocket.Conn, out ch
 // processMessage handles the deserialization and conversion of websocket messages
 func (bc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exchanges.Liquidation, errCh chan<- error) error {
 	var event LiquidationDTO
-	if err := json.Unmarshal(msg, &event); err != nil {
+	if err := exchanges.JSON.Unmarshal(msg, &event); err != nil {
 		select {
 		case errCh <- err:
 		default:
@@ -221,6 +292,7 @@ func (bc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exc
 		}
 		return err
 	}
+	liquidation.Raw = msg
 
 	select {
 	case out <- liquidation:
@@ -230,6 +302,305 @@ func (bc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exc
 	}
 }
 
+//------------------------------------------------------------------------------
+// Fetch Funding Rates API Methods
+//------------------------------------------------------------------------------
+
+// FetchFundingRates retrieves the current funding rate of all perpetual symbols, delivery contracts have none
+func (bc *Client) FetchFundingRates(ctx context.Context) ([]exchanges.FundingRate, error) {
+	url := bc.httpURL + FetchFundingRatesPath
+
+	resp, err := bc.get(ctx, url, FetchFundingRatesWeight)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	var indexes []PremiumIndexDTO
+	if err := exchanges.JSON.NewDecoder(resp.Body).Decode(&indexes); err != nil {
+		return nil, fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	indexes, err = FilterPremiumIndexes(indexes)
+	if err != nil {
+		return nil, fmt.Errorf("validating market data: %w", err)
+	}
+
+	rates := make([]exchanges.FundingRate, 0, len(indexes))
+	var convErrs []error
+	for _, index := range indexes {
+		if index.LastFundingRate == "" {
+			continue
+		}
+		rate, err := index.toFundingRate()
+		if err != nil {
+			convErrs = append(convErrs, fmt.Errorf("%s: %w", index.Symbol, err))
+			continue
+		}
+		rates = append(rates, rate)
+	}
+	return rates, exchanges.NewConversionError(len(rates)+len(convErrs), convErrs)
+}
+
+//------------------------------------------------------------------------------
+// Fetch Open Interest API Methods
+//------------------------------------------------------------------------------
+
+// FetchOpenInterest retrieves the open interest of the symbols of the market data, one request per symbol.
+// A failed request is reported as a conversion error of its symbol so the open interest of the others is kept
+func (bc *Client) FetchOpenInterest(ctx context.Context) ([]exchanges.OpenInterest, error) {
+	symbols, err := AllowedSymbols()
+	if err != nil {
+		return nil, fmt.Errorf("reading market data: %w", err)
+	}
+
+	var mu sync.Mutex
+	openInterest := make([]exchanges.OpenInterest, 0, len(symbols))
+	var errs []error
+	tasks := make(chan string)
+	var wg sync.WaitGroup
+	for range FetchOpenInterestWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range tasks {
+				oi, err := bc.fetchOpenInterest(ctx, symbol)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+				} else {
+					openInterest = append(openInterest, oi)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, symbol := range symbols {
+		if ctx.Err() != nil {
+			break
+		}
+		tasks <- symbol
+	}
+	close(tasks)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(openInterest, func(a, b exchanges.OpenInterest) int { return strings.Compare(a.Symbol, b.Symbol) })
+	return openInterest, exchanges.NewConversionError(len(symbols), errs)
+}
+
+// fetchOpenInterest retrieves the open interest of a single symbol
+func (bc *Client) fetchOpenInterest(ctx context.Context, symbol string) (exchanges.OpenInterest, error) {
+	url := bc.httpURL + FetchOpenInterestPath + "?symbol=" + symbol
+
+	resp, err := bc.get(ctx, url, FetchOpenInterestWeight)
+	if err != nil {
+		return exchanges.OpenInterest{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return exchanges.OpenInterest{}, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	var dto OpenInterestDTO
+	if err := exchanges.JSON.NewDecoder(resp.Body).Decode(&dto); err != nil {
+		return exchanges.OpenInterest{}, fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return dto.toOpenInterest()
+}
+
+//------------------------------------------------------------------------------
+// Book Tickers API Methods
+//------------------------------------------------------------------------------
+
+// SubscribeBookTickers initiates a websocket connection to receive best bid/ask updates of the given symbols
+// It returns two channels: one for receiving tickers and one for errors
+func (bc *Client) SubscribeBookTickers(ctx context.Context, symbols []string) (tickers <-chan exchanges.Ticker, errors <-chan error) {
+	out := make(chan exchanges.Ticker, DefaultChannelBuffer)
+	errCh := make(chan error, DefaultChannelBuffer)
+
+	go bc.handleBookTickerSubscription(ctx, bc.streamURL(symbols, "bookTicker"), out, errCh)
+
+	return out, errCh
+}
+
+// streamURL builds the combined stream URL for a stream of the given symbols, e.g. bookTicker
+func (bc *Client) streamURL(symbols []string, stream string) string {
+	streams := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		streams[i] = strings.ToLower(symbol) + "@" + stream
+	}
+	return bc.streamWSURL + "?streams=" + strings.Join(streams, "/")
+}
+
+// handleBookTickerSubscription keeps the book ticker connection alive until ctx is canceled
+func (bc *Client) handleBookTickerSubscription(ctx context.Context, url string, out chan<- exchanges.Ticker, errCh chan<- error) {
+	defer close(out)
+	defer close(errCh)
+
+	for {
+		if err := bc.readBookTickers(ctx, url, out, errCh); err != nil {
+			select {
+			case errCh <- fmt.Errorf("book ticker websocket error: %w", err):
+			default:
+				log.Printf("Error: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bc.wsConfig.ReconnectDelayOr(DefaultReconnectDelay)):
+		}
+	}
+}
+
+// readBookTickers establishes a single connection and forwards book tickers until it fails
+func (bc *Client) readBookTickers(ctx context.Context, url string, out chan<- exchanges.Ticker, errCh chan<- error) error {
+	conn, err := bc.wsConfig.Dial(ctx, url)
+	if err != nil {
+		return fmt.Errorf("websocket dial: %w", err)
+	}
+	defer conn.Close()
+
+	// unblock ReadMessage on shutdown
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(DefaultWebsocketTimeout)); err != nil {
+			return fmt.Errorf("setting read deadline: %w", err)
+		}
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("reading message: %w", err)
+		}
+
+		var event bookTickerStreamDTO
+		if err := exchanges.JSON.Unmarshal(msg, &event); err != nil {
+			select {
+			case errCh <- fmt.Errorf("unmarshaling book ticker: %w", err):
+			default:
+			}
+			continue
+		}
+		ticker, err := event.Data.toTicker()
+		if err == nil {
+			err = bc.eventAt.Stamp(&ticker, exchanges.UnixMilli(event.Data.EventTime), time.Now().UTC())
+		}
+		if err != nil {
+			select {
+			case errCh <- fmt.Errorf("converting book ticker: %w", err):
+			default:
+			}
+			continue
+		}
+
+		select {
+		case out <- ticker:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+// Trades API Methods
+//------------------------------------------------------------------------------
+
+// SubscribeTrades initiates a websocket connection to receive the aggregated trades of the given symbols
+// It returns two channels: one for receiving trades and one for errors
+func (bc *Client) SubscribeTrades(ctx context.Context, symbols []string) (trades <-chan exchanges.Trade, errors <-chan error) {
+	out := make(chan exchanges.Trade, DefaultChannelBuffer)
+	errCh := make(chan error, DefaultChannelBuffer)
+
+	go bc.handleTradeSubscription(ctx, bc.streamURL(symbols, "aggTrade"), out, errCh)
+
+	return out, errCh
+}
+
+// handleTradeSubscription keeps the trade connection alive until ctx is canceled
+func (bc *Client) handleTradeSubscription(ctx context.Context, url string, out chan<- exchanges.Trade, errCh chan<- error) {
+	defer close(out)
+	defer close(errCh)
+
+	for {
+		if err := bc.readTrades(ctx, url, out, errCh); err != nil {
+			select {
+			case errCh <- fmt.Errorf("trade websocket error: %w", err):
+			default:
+				log.Printf("Error: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(bc.wsConfig.ReconnectDelayOr(DefaultReconnectDelay)):
+		}
+	}
+}
+
+// readTrades establishes a single connection and forwards trades until it fails
+func (bc *Client) readTrades(ctx context.Context, url string, out chan<- exchanges.Trade, errCh chan<- error) error {
+	conn, err := bc.wsConfig.Dial(ctx, url)
+	if err != nil {
+		return fmt.Errorf("websocket dial: %w", err)
+	}
+	defer conn.Close()
+
+	// unblock ReadMessage on shutdown
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(DefaultWebsocketTimeout)); err != nil {
+			return fmt.Errorf("setting read deadline: %w", err)
+		}
+
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("reading message: %w", err)
+		}
+
+		var event aggTradeStreamDTO
+		if err := exchanges.JSON.Unmarshal(msg, &event); err != nil {
+			select {
+			case errCh <- fmt.Errorf("unmarshaling trade: %w", err):
+			default:
+			}
+			continue
+		}
+		trade, err := event.Data.toTrade()
+		if err != nil {
+			select {
+			case errCh <- fmt.Errorf("converting trade: %w", err):
+			default:
+			}
+			continue
+		}
+
+		select {
+		case out <- trade:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 //------------------------------------------------------------------------------
 // Other methods
 //------------------------------------------------------------------------------
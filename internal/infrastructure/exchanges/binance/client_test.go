@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/exchangestest"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,6 +55,7 @@ func TestClient_FetchTickers(t *testing.T) {
 		statusCode    int
 		expectError   bool
 		wantTickers   []exchanges.Ticker
+		wantFailed    int // tickers dropped because of conversion errors
 		contextCancel bool
 	}{
 		{
@@ -70,12 +74,15 @@ func TestClient_FetchTickers(t *testing.T) {
 			expectError: false,
 			wantTickers: []exchanges.Ticker{
 				{
-					Symbol:      "BTCUSDT",
-					BidPrice:    50000.50,
-					BidQuantity: 1.5,
-					AskPrice:    50000.75,
-					AskQuantity: 2.5,
-					EventAt:     time.UnixMilli(1635739200000),
+					Symbol:        "BTCUSDT",
+					Base:          "BTC",
+					Quote:         "USDT",
+					BidPrice:      50000.50,
+					BidQuantity:   1.5,
+					AskPrice:      50000.75,
+					AskQuantity:   2.5,
+					EventAt:       time.UnixMilli(1635739200000).UTC(),
+					EventAtSource: exchanges.EventAtSourceExchange,
 				},
 			},
 		},
@@ -111,6 +118,7 @@ func TestClient_FetchTickers(t *testing.T) {
 			statusCode:  http.StatusOK,
 			expectError: false,
 			wantTickers: []exchanges.Ticker{},
+			wantFailed:  1,
 		},
 	}
 
@@ -149,6 +157,14 @@ func TestClient_FetchTickers(t *testing.T) {
 				return
 			}
 
+			if tt.wantFailed > 0 {
+				var convErr *exchanges.ConversionError
+				require.ErrorAs(t, err, &convErr)
+				assert.Equal(t, tt.wantFailed, convErr.Failed)
+				assert.Equal(t, tt.wantTickers, got)
+				return
+			}
+
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantTickers, got)
 		})
@@ -288,6 +304,7 @@ func TestClient_SubscribeLiquidations(t *testing.T) {
 						require.NotEmpty(t, liq.Symbol)
 						require.NotZero(t, liq.Price)
 						require.NotZero(t, liq.Quantity)
+						require.True(t, json.Valid(liq.Raw), "the frame is kept")
 						count++
 					case err, ok := <-errors:
 						if !ok {
@@ -321,10 +338,79 @@ func TestClient_SubscribeLiquidations(t *testing.T) {
 	}
 }
 
+func TestClient_FetchFundingRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, FetchFundingRatesPath, r.URL.Path)
+		_ = json.NewEncoder(w).Encode([]PremiumIndexDTO{
+			{Symbol: "BTCUSDT", MarkPrice: "42000.5", LastFundingRate: "0.0001", NextFundingTime: 1703088000000, Time: 1703070685309},
+			{Symbol: "BTCUSDT_240329", MarkPrice: "43000", Time: 1703070685309}, // delivery contracts have no funding
+			{Symbol: "ETHUSDT", MarkPrice: "invalid", LastFundingRate: "0.0001", NextFundingTime: 1703088000000, Time: 1703070685309},
+			{Symbol: "UNLISTEDUSDT", MarkPrice: "1", LastFundingRate: "0.0001", NextFundingTime: 1703088000000, Time: 1703070685309},
+		})
+	}))
+	defer server.Close()
+
+	client := NewBinance(Config{APIUrl: server.URL})
+	got, err := client.FetchFundingRates(context.Background())
+
+	var convErr *exchanges.ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, 2, convErr.Total)
+	assert.Equal(t, 1, convErr.Failed)
+	assert.Equal(t, []exchanges.FundingRate{{
+		Symbol:        "BTCUSDT",
+		Base:          "BTC",
+		Quote:         "USDT",
+		Rate:          0.0001,
+		MarkPrice:     42000.5,
+		NextFundingAt: time.UnixMilli(1703088000000).UTC(),
+		EventAt:       time.UnixMilli(1703070685309).UTC(),
+	}}, got)
+
+	server.Close()
+	_, err = client.FetchFundingRates(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClient_FetchOpenInterest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, FetchOpenInterestPath, r.URL.Path)
+		symbol := r.URL.Query().Get("symbol")
+		switch symbol {
+		case "ETHUSDT":
+			w.WriteHeader(http.StatusBadRequest)
+		case "BTCUSDT":
+			_ = json.NewEncoder(w).Encode(OpenInterestDTO{Symbol: symbol, OpenInterest: "10659.509", Time: 1703070685309})
+		default:
+			_ = json.NewEncoder(w).Encode(OpenInterestDTO{Symbol: symbol, OpenInterest: "1", Time: 1703070685309})
+		}
+	}))
+	defer server.Close()
+
+	symbols, err := AllowedSymbols()
+	require.NoError(t, err)
+
+	client := NewBinance(Config{APIUrl: server.URL})
+	got, err := client.FetchOpenInterest(context.Background())
+
+	var convErr *exchanges.ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, len(symbols), convErr.Total)
+	assert.Equal(t, 1, convErr.Failed)
+	require.Len(t, got, len(symbols)-1)
+	assert.True(t, slices.IsSortedFunc(got, func(a, b exchanges.OpenInterest) int { return strings.Compare(a.Symbol, b.Symbol) }))
+	assert.Contains(t, got, exchanges.OpenInterest{Symbol: "BTCUSDT", Value: 10659.509, EventAt: time.UnixMilli(1703070685309).UTC()})
+	assert.Equal(t, float64(len(symbols)), client.APIUsage().Used, "a request of weight 1 per symbol")
+}
+
 func TestConvertTickers(t *testing.T) {
+	responseAt := time.UnixMilli(1635739201000).UTC()
+	receivedAt := time.UnixMilli(1635739201500).UTC()
+
 	tests := []struct {
 		name      string
 		input     []TickerDTO
+		fallback  exchanges.EventAtFallback
 		want      []exchanges.Ticker
 		wantCount int
 	}{
@@ -351,20 +437,26 @@ func TestConvertTickers(t *testing.T) {
 			wantCount: 2,
 			want: []exchanges.Ticker{
 				{
-					Symbol:      "BTCUSDT",
-					BidPrice:    50000.50,
-					BidQuantity: 1.5,
-					AskPrice:    50000.75,
-					AskQuantity: 2.5,
-					EventAt:     time.UnixMilli(1635739200000),
+					Symbol:        "BTCUSDT",
+					Base:          "BTC",
+					Quote:         "USDT",
+					BidPrice:      50000.50,
+					BidQuantity:   1.5,
+					AskPrice:      50000.75,
+					AskQuantity:   2.5,
+					EventAt:       time.UnixMilli(1635739200000).UTC(),
+					EventAtSource: exchanges.EventAtSourceExchange,
 				},
 				{
-					Symbol:      "ETHUSDT",
-					BidPrice:    3000.50,
-					BidQuantity: 10.5,
-					AskPrice:    3000.75,
-					AskQuantity: 12.5,
-					EventAt:     time.UnixMilli(1635739200000),
+					Symbol:        "ETHUSDT",
+					Base:          "ETH",
+					Quote:         "USDT",
+					BidPrice:      3000.50,
+					BidQuantity:   10.5,
+					AskPrice:      3000.75,
+					AskQuantity:   12.5,
+					EventAt:       time.UnixMilli(1635739200000).UTC(),
+					EventAtSource: exchanges.EventAtSourceExchange,
 				},
 			},
 		},
@@ -391,12 +483,57 @@ func TestConvertTickers(t *testing.T) {
 			wantCount: 1,
 			want: []exchanges.Ticker{
 				{
-					Symbol:      "BTCUSDT",
-					BidPrice:    50000.50,
-					BidQuantity: 1.5,
-					AskPrice:    50000.75,
-					AskQuantity: 2.5,
-					EventAt:     time.UnixMilli(1635739200000),
+					Symbol:        "BTCUSDT",
+					Base:          "BTC",
+					Quote:         "USDT",
+					BidPrice:      50000.50,
+					BidQuantity:   1.5,
+					AskPrice:      50000.75,
+					AskQuantity:   2.5,
+					EventAt:       time.UnixMilli(1635739200000).UTC(),
+					EventAtSource: exchanges.EventAtSourceExchange,
+				},
+			},
+		},
+		{
+			name: "tickers without time get the response time",
+			input: []TickerDTO{
+				{Symbol: "BTCUSDT", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1"},
+			},
+			wantCount: 1,
+			want: []exchanges.Ticker{
+				{
+					Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", BidPrice: 1, BidQuantity: 1, AskPrice: 2, AskQuantity: 1,
+					EventAt: responseAt, EventAtSource: exchanges.EventAtSourceResponse,
+				},
+			},
+		},
+		{
+			name: "tickers without time get the receive time",
+			input: []TickerDTO{
+				{Symbol: "BTCUSDT", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1"},
+			},
+			fallback:  exchanges.EventAtFallbackReceived,
+			wantCount: 1,
+			want: []exchanges.Ticker{
+				{
+					Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", BidPrice: 1, BidQuantity: 1, AskPrice: 2, AskQuantity: 1,
+					EventAt: receivedAt, EventAtSource: exchanges.EventAtSourceReceived,
+				},
+			},
+		},
+		{
+			name: "tickers without time are rejected",
+			input: []TickerDTO{
+				{Symbol: "BTCUSDT", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1"},
+				{Symbol: "ETHUSDT", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1", Time: 1635739200000},
+			},
+			fallback:  exchanges.EventAtFallbackReject,
+			wantCount: 1,
+			want: []exchanges.Ticker{
+				{
+					Symbol: "ETHUSDT", Base: "ETH", Quote: "USDT", BidPrice: 1, BidQuantity: 1, AskPrice: 2, AskQuantity: 1,
+					EventAt: time.UnixMilli(1635739200000).UTC(), EventAtSource: exchanges.EventAtSourceExchange,
 				},
 			},
 		},
@@ -431,9 +568,160 @@ func TestConvertTickers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := convertTickers(tt.input)
+			got, errs := convertTickers(tt.input, tt.fallback, responseAt, receivedAt)
 			assert.Equal(t, tt.wantCount, len(got))
+			assert.Len(t, errs, len(tt.input)-tt.wantCount)
 			assert.Equal(t, tt.want, got)
 		})
 	}
 }
+
+func TestClient_SubscribeBookTickers(t *testing.T) {
+	var requestedStreams string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedStreams = r.URL.Query().Get("streams")
+		upgrader := websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		}
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("upgrade error: %v", err)
+			return
+		}
+		defer ws.Close()
+
+		messages := []string{
+			`invalid json`,
+			`{"stream":"btcusdt@bookTicker","data":{"e":"bookTicker","u":400900217,"E":1568014460893,"T":1568014460891,"s":"BTCUSDT","b":"25.35190000","B":"31.21000000","a":"25.36520000","A":"40.66000000"}}`,
+		}
+		for _, msg := range messages {
+			if err := ws.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				t.Logf("write message error: %v", err)
+				return
+			}
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewBinance(Config{
+		Name:        "test",
+		StreamWSUrl: "ws" + server.URL[4:] + "/stream",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	tickers, errs := client.SubscribeBookTickers(ctx, []string{"BTCUSDT", "ETHUSDT"})
+
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "unmarshaling book ticker")
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for error")
+	}
+
+	select {
+	case ticker := <-tickers:
+		assert.Equal(t, exchanges.Ticker{
+			Symbol:        "BTCUSDT",
+			Base:          "BTC",
+			Quote:         "USDT",
+			AskPrice:      25.3652,
+			BidPrice:      25.3519,
+			AskQuantity:   40.66,
+			BidQuantity:   31.21,
+			EventAt:       time.Unix(0, 1568014460891*int64(time.Millisecond)).UTC(),
+			EventAtSource: exchanges.EventAtSourceExchange,
+		}, ticker)
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for book ticker")
+	}
+	assert.Equal(t, "btcusdt@bookTicker/ethusdt@bookTicker", requestedStreams)
+
+	// channels are closed once the context is canceled
+	cancel()
+	assert.Eventually(t, func() bool {
+		_, open := <-tickers
+		return !open
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestClient_SubscribeTrades(t *testing.T) {
+	var requestedStreams string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedStreams = r.URL.Query().Get("streams")
+		upgrader := websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		}
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("upgrade error: %v", err)
+			return
+		}
+		defer ws.Close()
+
+		messages := []string{
+			`{"stream":"btcusdt@aggTrade","data":{"e":"aggTrade","E":1568014460893,"a":5933014,"s":"BTCUSDT","p":"bad","q":"100","f":100,"l":105,"T":1568014460891,"m":true}}`,
+			`{"stream":"btcusdt@aggTrade","data":{"e":"aggTrade","E":1568014460893,"a":5933015,"s":"BTCUSDT","p":"25000.5","q":"0.2","f":106,"l":107,"T":1568014460891,"m":true}}`,
+			`{"stream":"ethusdt@aggTrade","data":{"e":"aggTrade","E":1568014460893,"a":7100,"s":"ETHUSDT","p":"1500","q":"2","f":1,"l":1,"T":1568014460892,"m":false}}`,
+		}
+		for _, msg := range messages {
+			if err := ws.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				t.Logf("write message error: %v", err)
+				return
+			}
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewBinance(Config{
+		Name:        "test",
+		StreamWSUrl: "ws" + server.URL[4:] + "/stream",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	trades, errs := client.SubscribeTrades(ctx, []string{"BTCUSDT", "ETHUSDT"})
+
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "converting trade: invalid price 'bad'")
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for error")
+	}
+
+	want := []exchanges.Trade{
+		{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", ID: 5933015, Side: exchanges.TakerSell, Price: 25000.5, Quantity: 0.2, EventAt: time.UnixMilli(1568014460891).UTC()},
+		{Symbol: "ETHUSDT", Base: "ETH", Quote: "USDT", ID: 7100, Side: exchanges.TakerBuy, Price: 1500, Quantity: 2, EventAt: time.UnixMilli(1568014460892).UTC()},
+	}
+	for _, wantTrade := range want {
+		select {
+		case trade := <-trades:
+			assert.Equal(t, wantTrade, trade)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for trade")
+		}
+	}
+	assert.Equal(t, "btcusdt@aggTrade/ethusdt@aggTrade", requestedStreams)
+
+	// channels are closed once the context is canceled
+	cancel()
+	assert.Eventually(t, func() bool {
+		_, open := <-trades
+		return !open
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestClient_Conformance(t *testing.T) {
+	exchangestest.Run(t, exchangestest.Fixture{
+		New: func(apiURL, wsURL string, ws exchanges.WebsocketConfig) exchanges.Exchange {
+			return NewBinance(Config{Name: "test", APIUrl: apiURL, WSUrl: wsURL, HTTPClient: http.DefaultClient, Websocket: ws})
+		},
+		Tickers: []TickerDTO{
+			{Symbol: "BTCUSDT", BidPrice: "50000.50", BidQuantity: "1.5", AskPrice: "50000.75", AskQuantity: "2.5", Time: 1635739200000},
+			{Symbol: "ETHUSDT", BidPrice: "3000.10", BidQuantity: "10", AskPrice: "3000.20", AskQuantity: "12", Time: 1635739200000},
+		},
+		Liquidation: `{"e":"forceOrder","E":1635739200000,"o":{"s":"BTCUSDT","S":"SELL","o":"LIMIT","f":"IOC","q":"0.001","p":"50000.50","ap":"0","X":"FILLED","l":"0.001","T":1635739200000}}`,
+	})
+}
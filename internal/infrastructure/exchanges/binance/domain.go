@@ -15,8 +15,38 @@ const (
 	// FuturesWSUrl is the base URL for the Binance Futures Websocket API
 	FuturesWSUrl = "wss://fstream.binance.com/ws/!forceOrder@arr"
 
+	// FuturesStreamWSUrl is the base URL for the Binance Futures combined streams
+	FuturesStreamWSUrl = "wss://fstream.binance.com/stream"
+
 	// FetchTickersData is the endpoint to fetch tickers data
 	FetchTickersData = "/ticker/bookTicker"
+
+	// WeightLimit is the request weight an IP may use per WeightWindow before being rate limited and then banned
+	WeightLimit = 2400
+
+	// WeightWindow is the window of WeightLimit
+	WeightWindow = time.Minute
+
+	// FetchTickersWeight is the weight of a FetchTickersData request of all symbols
+	FetchTickersWeight = 5
+
+	// FetchFundingRatesPath is the endpoint to fetch the mark price and funding rate of all symbols
+	FetchFundingRatesPath = "/premiumIndex"
+
+	// FetchFundingRatesWeight is the weight of a FetchFundingRatesPath request without a symbol
+	FetchFundingRatesWeight = 10
+
+	// FetchOpenInterestPath is the endpoint to fetch the open interest of a symbol, the symbol query parameter is required
+	FetchOpenInterestPath = "/openInterest"
+
+	// FetchOpenInterestWeight is the weight of a FetchOpenInterestPath request
+	FetchOpenInterestWeight = 1
+
+	// FetchOpenInterestWorkers is the number of FetchOpenInterestPath requests sent concurrently
+	FetchOpenInterestWorkers = 8
+
+	// UsedWeightHeader reports the weight used by the IP within the current WeightWindow
+	UsedWeightHeader = "X-Mbx-Used-Weight-1m"
 )
 
 // TickerDTO represents a ticker event from the Binance WebSocket API
@@ -53,15 +83,152 @@ func (bt TickerDTO) toTicker() (exchanges.Ticker, error) {
 	}
 
 	ticker.Symbol = bt.Symbol
+	ticker.Base, ticker.Quote = exchanges.SplitSymbol(bt.Symbol)
 	ticker.BidPrice = bidPrice
 	ticker.AskPrice = askPrice
 	ticker.BidQuantity = bidQuantity
 	ticker.AskQuantity = askQuantity
-	ticker.EventAt = time.Unix(0, bt.Time*int64(time.Millisecond))
+	// the REST book ticker often has no time, such tickers are left for the EventAt fallback
+	ticker.EventAt = exchanges.UnixMilli(bt.Time)
 
 	return ticker, nil
 }
 
+// PremiumIndexDTO represents the mark price and funding rate of a symbol from the Binance API
+type PremiumIndexDTO struct {
+	Symbol          string `json:"symbol"`
+	MarkPrice       string `json:"markPrice"`
+	LastFundingRate string `json:"lastFundingRate"` // empty for delivery contracts
+	NextFundingTime int64  `json:"nextFundingTime"`
+	Time            int64  `json:"time"`
+}
+
+// toFundingRate converts a PremiumIndexDTO to an exchanges.FundingRate
+func (pi PremiumIndexDTO) toFundingRate() (exchanges.FundingRate, error) {
+	rate, err := strconv.ParseFloat(pi.LastFundingRate, 64)
+	if err != nil {
+		return exchanges.FundingRate{}, fmt.Errorf("invalid lastFundingRate '%s': %w", pi.LastFundingRate, err)
+	}
+	markPrice, err := strconv.ParseFloat(pi.MarkPrice, 64)
+	if err != nil {
+		return exchanges.FundingRate{}, fmt.Errorf("invalid markPrice '%s': %w", pi.MarkPrice, err)
+	}
+
+	fundingRate := exchanges.FundingRate{
+		Symbol:        pi.Symbol,
+		Rate:          rate,
+		MarkPrice:     markPrice,
+		NextFundingAt: exchanges.UnixMilli(pi.NextFundingTime),
+		EventAt:       exchanges.UnixMilli(pi.Time),
+	}
+	fundingRate.Base, fundingRate.Quote = exchanges.SplitSymbol(pi.Symbol)
+	return fundingRate, nil
+}
+
+// OpenInterestDTO represents the open interest of a symbol from the Binance API
+type OpenInterestDTO struct {
+	Symbol       string `json:"symbol"`
+	OpenInterest string `json:"openInterest"` // in base asset units
+	Time         int64  `json:"time"`
+}
+
+// toOpenInterest converts an OpenInterestDTO to an exchanges.OpenInterest
+func (oi OpenInterestDTO) toOpenInterest() (exchanges.OpenInterest, error) {
+	value, err := strconv.ParseFloat(oi.OpenInterest, 64)
+	if err != nil {
+		return exchanges.OpenInterest{}, fmt.Errorf("invalid openInterest '%s': %w", oi.OpenInterest, err)
+	}
+	return exchanges.OpenInterest{
+		Symbol:  oi.Symbol,
+		Value:   value,
+		EventAt: exchanges.UnixMilli(oi.Time),
+	}, nil
+}
+
+// BookTickerDTO represents a book ticker event from the Binance WebSocket API
+type BookTickerDTO struct {
+	EventType       string `json:"e"`
+	UpdateID        int64  `json:"u"`
+	EventTime       int64  `json:"E"`
+	TransactionTime int64  `json:"T"`
+	Symbol          string `json:"s"`
+	BidPrice        string `json:"b"`
+	BidQuantity     string `json:"B"`
+	AskPrice        string `json:"a"`
+	AskQuantity     string `json:"A"`
+}
+
+// bookTickerStreamDTO wraps a BookTickerDTO in a combined stream message
+type bookTickerStreamDTO struct {
+	Stream string        `json:"stream"`
+	Data   BookTickerDTO `json:"data"`
+}
+
+// toTicker converts a BookTickerDTO to an exchanges.Ticker
+func (bt BookTickerDTO) toTicker() (exchanges.Ticker, error) {
+	return TickerDTO{
+		Symbol:      bt.Symbol,
+		BidPrice:    bt.BidPrice,
+		BidQuantity: bt.BidQuantity,
+		AskPrice:    bt.AskPrice,
+		AskQuantity: bt.AskQuantity,
+		Time:        bt.TransactionTime,
+	}.toTicker()
+}
+
+// AggTradeDTO represents an aggregated trade event from the Binance WebSocket API
+type AggTradeDTO struct {
+	EventType    string `json:"e"`
+	EventTime    int64  `json:"E"`
+	ID           int64  `json:"a"`
+	Symbol       string `json:"s"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	FirstTradeID int64  `json:"f"`
+	LastTradeID  int64  `json:"l"`
+	TradeTime    int64  `json:"T"`
+	BuyerMaker   bool   `json:"m"` // the buyer placed the resting order, so the seller took liquidity
+}
+
+// aggTradeStreamDTO wraps an AggTradeDTO in a combined stream message
+type aggTradeStreamDTO struct {
+	Stream string      `json:"stream"`
+	Data   AggTradeDTO `json:"data"`
+}
+
+// toTrade converts an AggTradeDTO to an exchanges.Trade
+func (at AggTradeDTO) toTrade() (exchanges.Trade, error) {
+	price, err := strconv.ParseFloat(at.Price, 64)
+	if err != nil {
+		return exchanges.Trade{}, fmt.Errorf("invalid price '%s': %w", at.Price, err)
+	}
+	quantity, err := strconv.ParseFloat(at.Quantity, 64)
+	if err != nil {
+		return exchanges.Trade{}, fmt.Errorf("invalid quantity '%s': %w", at.Quantity, err)
+	}
+
+	trade := exchanges.Trade{
+		Symbol:   at.Symbol,
+		ID:       at.ID,
+		Side:     exchanges.TakerBuy,
+		Price:    price,
+		Quantity: quantity,
+		EventAt:  exchanges.UnixMilli(at.TradeTime),
+	}
+	if at.BuyerMaker {
+		trade.Side = exchanges.TakerSell
+	}
+	trade.Base, trade.Quote = exchanges.SplitSymbol(at.Symbol)
+	return trade, nil
+}
+
+// liquidationSides maps the Binance side to the normalized one.
+// Binance reports the side of the forced order, so "SELL" means a long position was liquidated
+var liquidationSides = map[string]exchanges.LiquidationSide{
+	"SELL": exchanges.LongLiquidated,
+	"BUY":  exchanges.ShortLiquidated,
+}
+
 // LiquidationDTO represents a liquidation event from the Binance WebSocket API
 type LiquidationDTO struct {
 	EventType string `json:"e"`
@@ -96,9 +263,15 @@ func (bl LiquidationDTO) toLiquidation() (exchanges.Liquidation, error) {
 	liquidation.Price = priceF
 	liquidation.Quantity = quantityF
 	liquidation.Symbol = bl.OrderData.Symbol
-	liquidation.EventAt = time.Unix(0, bl.EventTime*int64(time.Millisecond))
-	liquidation.Side = bl.OrderData.Side
+	liquidation.Base, liquidation.Quote = exchanges.SplitSymbol(bl.OrderData.Symbol)
+	liquidation.EventAt = time.Unix(0, bl.EventTime*int64(time.Millisecond)).UTC()
 	liquidation.TotalPrice = priceF * quantityF
 
+	side, ok := liquidationSides[bl.OrderData.Side]
+	if !ok {
+		return liquidation, fmt.Errorf("invalid side '%s'", bl.OrderData.Side)
+	}
+	liquidation.Side = side
+
 	return liquidation, nil
 }
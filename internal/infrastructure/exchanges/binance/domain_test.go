@@ -1,6 +1,8 @@
 package binance
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -28,11 +30,13 @@ func TestTickerDTO_ToTicker(t *testing.T) {
 			},
 			want: exchanges.Ticker{
 				Symbol:      "BTCUSDT",
+				Base:        "BTC",
+				Quote:       "USDT",
 				BidPrice:    50000.50,
 				BidQuantity: 1.5,
 				AskPrice:    50000.75,
 				AskQuantity: 2.5,
-				EventAt:     time.UnixMilli(1635739200000),
+				EventAt:     time.UnixMilli(1635739200000).UTC(),
 			},
 			wantErr: false,
 		},
@@ -101,6 +105,18 @@ func TestTickerDTO_ToTicker(t *testing.T) {
 	}
 }
 
+// forceOrder returns a forced order of 1 BTCUSDT at 100 on the side, Binance reports the side of the order
+func forceOrder(side string) LiquidationDTO {
+	var dto LiquidationDTO
+	dto.EventType = "forceOrder"
+	dto.EventTime = 1635739200000
+	dto.OrderData.Symbol = "BTCUSDT"
+	dto.OrderData.Side = side
+	dto.OrderData.OrigQuantity = "1"
+	dto.OrderData.Price = "100"
+	return dto
+}
+
 func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -133,10 +149,12 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 			},
 			want: exchanges.Liquidation{
 				Symbol:     "BTCUSDT",
-				Side:       "SELL",
+				Base:       "BTC",
+				Quote:      "USDT",
+				Side:       exchanges.LongLiquidated,
 				Price:      50000.50,
 				Quantity:   0.001,
-				EventAt:    time.UnixMilli(1635739200000),
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
 				TotalPrice: 50.0005,
 			},
 			wantErr: false,
@@ -190,6 +208,23 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 			want:    exchanges.Liquidation{},
 			wantErr: true,
 		},
+		{
+			name: "short liquidated",
+			dto:  forceOrder("BUY"),
+			want: exchanges.Liquidation{
+				Symbol:     "BTCUSDT",
+				Base:       "BTC",
+				Quote:      "USDT",
+				Side:       exchanges.ShortLiquidated,
+				Price:      100,
+				Quantity:   1,
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
+				TotalPrice: 100,
+			},
+		},
+		{name: "lower case side", dto: forceOrder("sell"), wantErr: true},
+		{name: "mixed case side", dto: forceOrder("Buy"), wantErr: true},
+		{name: "missing side", dto: forceOrder(""), wantErr: true},
 	}
 
 	for _, tt := range tests {
@@ -204,3 +239,85 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 		})
 	}
 }
+
+// liquidationFrame is a force order frame as sent by the !forceOrder@arr stream
+var liquidationFrame = []byte(`{"e":"forceOrder","E":1568014460893,"o":{"s":"BTCUSDT","S":"SELL","o":"LIMIT","f":"IOC",` +
+	`"q":"0.014","p":"9910","ap":"9910","X":"FILLED","l":"0.014","z":"0.014","T":1568014460893}}`)
+
+// tickersPayload returns a /fapi/v1/ticker/bookTicker response with n symbols
+func tickersPayload(n int) []byte {
+	tickers := make([]TickerDTO, n)
+	for i := range tickers {
+		tickers[i] = TickerDTO{
+			Symbol:      fmt.Sprintf("SYM%dUSDT", i),
+			BidPrice:    "100.10",
+			BidQuantity: "12.5",
+			AskPrice:    "100.20",
+			AskQuantity: "7.25",
+			Time:        1635739200000,
+			LastUpdated: int64(i),
+		}
+	}
+	payload, _ := json.Marshal(tickers)
+	return payload
+}
+
+func TestJSONDecodesLikeEncodingJSON(t *testing.T) {
+	var want, got LiquidationDTO
+	require.NoError(t, json.Unmarshal(liquidationFrame, &want))
+	require.NoError(t, exchanges.JSON.Unmarshal(liquidationFrame, &got))
+	assert.Equal(t, want, got)
+	assert.Equal(t, "forceOrder", got.EventType, "e and E are told apart")
+	assert.Equal(t, int64(1568014460893), got.EventTime)
+
+	var wantTickers, gotTickers []TickerDTO
+	payload := tickersPayload(3)
+	require.NoError(t, json.Unmarshal(payload, &wantTickers))
+	require.NoError(t, exchanges.JSON.Unmarshal(payload, &gotTickers))
+	assert.Equal(t, wantTickers, gotTickers)
+
+	assert.Error(t, exchanges.JSON.Unmarshal([]byte(`{"e":`), &got))
+}
+
+// BenchmarkDecodeLiquidation compares encoding/json with the hot path decoder on a single liquidation frame
+func BenchmarkDecodeLiquidation(b *testing.B) {
+	decoders := map[string]func([]byte, any) error{
+		"encoding_json": json.Unmarshal,
+		"jsoniter":      exchanges.JSON.Unmarshal,
+	}
+	for _, name := range []string{"encoding_json", "jsoniter"} {
+		unmarshal := decoders[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(liquidationFrame)))
+			for range b.N {
+				var event LiquidationDTO
+				if err := unmarshal(liquidationFrame, &event); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeTickers compares encoding/json with the hot path decoder on the tickers of all symbols
+func BenchmarkDecodeTickers(b *testing.B) {
+	payload := tickersPayload(500)
+	decoders := map[string]func([]byte, any) error{
+		"encoding_json": json.Unmarshal,
+		"jsoniter":      exchanges.JSON.Unmarshal,
+	}
+	for _, name := range []string{"encoding_json", "jsoniter"} {
+		unmarshal := decoders[name]
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(payload)))
+			for range b.N {
+				var tickers []TickerDTO
+				if err := unmarshal(payload, &tickers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
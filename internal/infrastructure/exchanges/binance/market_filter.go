@@ -2,6 +2,8 @@ package binance
 
 import (
 	"encoding/json"
+	"maps"
+	"slices"
 )
 
 // SymbolInfo represents the structure of market data for a single ticker
@@ -17,18 +19,37 @@ type AllowedSymbolsMap map[string]SymbolInfo
 
 // FilterTickers filters tickers based on allowed symbols
 func FilterTickers(tickers []TickerDTO) ([]TickerDTO, error) {
+	return filterSymbols(tickers, func(ticker TickerDTO) string { return ticker.Symbol })
+}
+
+// FilterPremiumIndexes filters premium indexes based on allowed symbols, so funding rates cover the symbols of the ticks
+func FilterPremiumIndexes(indexes []PremiumIndexDTO) ([]PremiumIndexDTO, error) {
+	return filterSymbols(indexes, func(index PremiumIndexDTO) string { return index.Symbol })
+}
+
+// AllowedSymbols returns the symbols of the market data in alphabetical order
+func AllowedSymbols() ([]string, error) {
+	var allowedSymbolsMap AllowedSymbolsMap
+	if err := json.Unmarshal([]byte(marketDataJSON), &allowedSymbolsMap); err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(allowedSymbolsMap)), nil
+}
+
+// filterSymbols keeps the items whose symbol is in the market data
+func filterSymbols[T any](items []T, symbol func(T) string) ([]T, error) {
 	var allowedSymbolsMap AllowedSymbolsMap
 	if err := json.Unmarshal([]byte(marketDataJSON), &allowedSymbolsMap); err != nil {
 		return nil, err
 	}
-	validTickers := make([]TickerDTO, 0, len(allowedSymbolsMap))
+	valid := make([]T, 0, len(allowedSymbolsMap))
 
-	for _, ticker := range tickers {
-		if _, exists := allowedSymbolsMap[ticker.Symbol]; !exists {
+	for _, item := range items {
+		if _, exists := allowedSymbolsMap[symbol(item)]; !exists {
 			continue
 		}
-		validTickers = append(validTickers, ticker)
+		valid = append(valid, item)
 	}
 
-	return validTickers, nil
+	return valid, nil
 }
@@ -0,0 +1,141 @@
+package exchanges
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultAPIBudgetReserve is the share of a rate limit an APIBudget keeps unused, so bursts of other clients
+// sharing the IP and requests racing the accounting don't get the importer banned
+const DefaultAPIBudgetReserve = 0.1
+
+// ErrThrottled is returned instead of sending a request that would exceed the rate limit of the exchange,
+// or while the exchange asked to back off
+var ErrThrottled = errors.New("request throttled to stay within the exchange rate limit")
+
+// APIUsage is the consumption of the REST rate limit of an exchange within the current window
+type APIUsage struct {
+	Used      float64   // weight or requests consumed, as reported by the exchange when it does
+	Limit     float64   // weight or requests allowed per window
+	ResetAt   time.Time // end of the current window
+	Throttled int64     // requests refused so far to stay within the limit
+}
+
+// Remaining returns the weight or requests left within the window
+func (u APIUsage) Remaining() float64 {
+	return max(u.Limit-u.Used, 0)
+}
+
+// APIUsageReporter is implemented by exchanges accounting the usage of their REST rate limit
+type APIUsageReporter interface {
+	APIUsage() APIUsage
+}
+
+// APIBudget accounts the weight of the requests sent to an exchange within fixed windows and refuses the requests
+// that would exceed the limit minus the reserve. The local accounting is corrected with the usage reported by
+// the exchange, which includes other clients sharing the IP
+type APIBudget struct {
+	limit   float64
+	window  time.Duration
+	reserve float64
+	now     func() time.Time
+
+	mu           sync.Mutex
+	windowStart  time.Time
+	used         float64
+	blockedUntil time.Time
+	throttled    int64
+}
+
+// NewAPIBudget creates an APIBudget allowing limit weight per window
+func NewAPIBudget(limit float64, window time.Duration) *APIBudget {
+	return &APIBudget{
+		limit:   limit,
+		window:  window,
+		reserve: DefaultAPIBudgetReserve,
+		now:     time.Now,
+	}
+}
+
+// Acquire reserves the weight of a request, ErrThrottled if it must not be sent
+func (b *APIBudget) Acquire(weight float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.roll(now)
+	if now.Before(b.blockedUntil) {
+		b.throttled++
+		return fmt.Errorf("%w: backing off until %s", ErrThrottled, b.blockedUntil.Format(time.RFC3339))
+	}
+	if b.used+weight > b.limit*(1-b.reserve) {
+		b.throttled++
+		return fmt.Errorf("%w: %g of %g used until %s", ErrThrottled, b.used, b.limit, b.windowStart.Add(b.window).Format(time.RFC3339))
+	}
+	b.used += weight
+	return nil
+}
+
+// Observe replaces the local accounting with the usage reported by the exchange. A positive limit
+// replaces the configured one, for exchanges reporting it
+func (b *APIBudget) Observe(used, limit float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll(b.now())
+	b.used = used
+	if limit > 0 {
+		b.limit = limit
+	}
+}
+
+// Block refuses every request until the given time, e.g. when the exchange answered 429
+func (b *APIBudget) Block(until time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// BlockRetryAfter blocks the budget when the response is rate limited (429) or the IP is banned (418),
+// until the Retry-After of the response or the end of the window without it. It reports whether it blocked
+func (b *APIBudget) BlockRetryAfter(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusTeapot {
+		return false
+	}
+
+	now := b.now()
+	until := now.Truncate(b.window).Add(b.window)
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		until = now.Add(time.Duration(seconds) * time.Second)
+	}
+	b.Block(until)
+	return true
+}
+
+// Usage returns the consumption within the current window
+func (b *APIBudget) Usage() APIUsage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.roll(b.now())
+	return APIUsage{
+		Used:      b.used,
+		Limit:     b.limit,
+		ResetAt:   b.windowStart.Add(b.window),
+		Throttled: b.throttled,
+	}
+}
+
+// roll starts a new window once the current one elapsed (must be called under lock)
+func (b *APIBudget) roll(now time.Time) {
+	if start := now.Truncate(b.window); start.After(b.windowStart) {
+		b.windowStart = start
+		b.used = 0
+	}
+}
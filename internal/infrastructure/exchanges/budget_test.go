@@ -0,0 +1,71 @@
+package exchanges
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBudget(limit float64, window time.Duration, now *time.Time) *APIBudget {
+	b := NewAPIBudget(limit, window)
+	b.now = func() time.Time { return *now }
+	return b
+}
+
+func TestAPIBudget_Acquire(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 10, 0, time.UTC)
+	b := newTestBudget(100, time.Minute, &now)
+
+	assert.NoError(t, b.Acquire(50))
+	assert.NoError(t, b.Acquire(40))
+	err := b.Acquire(1)
+	assert.ErrorIs(t, err, ErrThrottled, "the reserve keeps the last 10% unused")
+
+	usage := b.Usage()
+	assert.Equal(t, 90.0, usage.Used)
+	assert.Equal(t, 10.0, usage.Remaining())
+	assert.Equal(t, int64(1), usage.Throttled)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC), usage.ResetAt)
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, b.Acquire(50), "a new window resets the usage")
+	assert.Equal(t, 50.0, b.Usage().Used)
+}
+
+func TestAPIBudget_Observe(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newTestBudget(100, time.Minute, &now)
+
+	assert.NoError(t, b.Acquire(5))
+	b.Observe(85, 0)
+	assert.ErrorIs(t, b.Acquire(10), ErrThrottled, "the reported usage includes other clients of the IP")
+
+	b.Observe(85, 200)
+	assert.NoError(t, b.Acquire(10), "the reported limit replaces the configured one")
+	assert.Equal(t, 200.0, b.Usage().Limit)
+}
+
+func TestAPIBudget_BlockRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC)
+	b := newTestBudget(100, time.Minute, &now)
+
+	assert.False(t, b.BlockRetryAfter(&http.Response{StatusCode: http.StatusOK}))
+	assert.NoError(t, b.Acquire(1))
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "120")
+	assert.True(t, b.BlockRetryAfter(resp))
+
+	now = now.Add(time.Minute)
+	assert.ErrorIs(t, b.Acquire(1), ErrThrottled, "the new window doesn't lift the Retry-After")
+	now = now.Add(time.Minute)
+	assert.NoError(t, b.Acquire(1))
+
+	// without Retry-After the budget is blocked until the end of the window
+	assert.True(t, b.BlockRetryAfter(&http.Response{StatusCode: http.StatusTeapot, Header: http.Header{}}))
+	assert.ErrorIs(t, b.Acquire(1), ErrThrottled)
+	now = now.Truncate(time.Minute).Add(time.Minute)
+	assert.NoError(t, b.Acquire(1))
+}
@@ -3,11 +3,12 @@ package bybit
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
@@ -32,8 +33,13 @@ const (
 type Config struct {
 	Name       string
 	APIUrl     string
-	WSUrl      string
+	WSUrl      string   // stream of the linear category, streams of other categories replace its last path segment
+	Categories []string // categories to import, defaults to linear only
 	HTTPClient *http.Client
+	Websocket  exchanges.WebsocketConfig // dialer of the liquidation streams
+
+	// EventAtFallback stamps the tickers without their own timestamp, defaults to the exchange response time
+	EventAtFallback exchanges.EventAtFallback
 }
 
 // Client implements a Bybit exchange client
@@ -42,10 +48,16 @@ type Client struct {
 	httpURL    string
 	wsURL      string
 	httpClient *http.Client
+	categories []string
+	wsConfig   exchanges.WebsocketConfig
+	budget     *exchanges.APIBudget
+	eventAt    exchanges.EventAtFallback
 
+	// symbol universes are kept per category
 	tickersInfo struct {
-		availableTickers []string
-		updatedAt        time.Time
+		mu               sync.Mutex
+		availableTickers map[string][]string
+		updatedAt        map[string]time.Time
 	}
 }
 
@@ -60,22 +72,36 @@ func NewBybit(cfg Config) *Client {
 	if cfg.APIUrl == "" {
 		cfg.APIUrl = FuturesAPIURL
 	}
+	if len(cfg.Categories) == 0 {
+		cfg.Categories = []string{CategoryLinear}
+	}
 
-	return &Client{
+	client := &Client{
 		name:       cfg.Name,
 		httpURL:    cfg.APIUrl,
 		wsURL:      cfg.WSUrl,
 		httpClient: cfg.HTTPClient,
+		categories: cfg.Categories,
+		wsConfig:   cfg.Websocket,
+		eventAt:    cfg.EventAtFallback,
+		budget:     exchanges.NewAPIBudget(RequestLimit, RequestWindow),
 	}
+	client.tickersInfo.availableTickers = make(map[string][]string)
+	client.tickersInfo.updatedAt = make(map[string]time.Time)
+	return client
 }
 
-//------------------------------------------------------------------------------
-// Fetch Tickers API Methods
-//------------------------------------------------------------------------------
+// APIUsage returns the requests sent within the current window
+func (bc *Client) APIUsage() exchanges.APIUsage {
+	return bc.budget.Usage()
+}
 
-// FetchTickers retrieves current ticker information for all trading pairs
-func (bc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error) {
-	url := bc.httpURL + FetchTickersData
+// get sends a GET request unless it would exceed the request limit, and accounts the requests remaining
+// as reported by Bybit when it does. Rate limited responses block the requests until their Retry-After
+func (bc *Client) get(ctx context.Context, url string) (*http.Response, error) {
+	if err := bc.budget.Acquire(1); err != nil {
+		return nil, err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
@@ -86,43 +112,147 @@ func (bc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error)
 	if err != nil {
 		return nil, fmt.Errorf("executing request for %s: %w", url, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+	limit, limitErr := strconv.ParseFloat(resp.Header.Get(LimitHeader), 64)
+	remaining, remainingErr := strconv.ParseFloat(resp.Header.Get(LimitStatusHeader), 64)
+	if limitErr == nil && remainingErr == nil {
+		bc.budget.Observe(limit-remaining, limit)
+	}
+	if bc.budget.BlockRetryAfter(resp) {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: status %s from %s", exchanges.ErrThrottled, resp.Status, url)
 	}
+	return resp, nil
+}
 
-	var response TickerResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response from %s: %w", url, err)
+//------------------------------------------------------------------------------
+// Fetch Tickers API Methods
+//------------------------------------------------------------------------------
+
+// FetchTickers retrieves current ticker information for all trading pairs of the configured categories
+func (bc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error) {
+	tickers := make([]exchanges.Ticker, 0)
+	var convErrs []error
+	for _, category := range bc.categories {
+		categoryTickers, categoryConvErrs, err := bc.fetchCategoryTickers(ctx, category)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s tickers: %w", category, err)
+		}
+		tickers = append(tickers, categoryTickers...)
+		convErrs = append(convErrs, categoryConvErrs...)
 	}
+	return tickers, exchanges.NewConversionError(len(tickers)+len(convErrs), convErrs)
+}
 
-	if len(bc.tickersInfo.availableTickers) == 0 || time.Since(bc.tickersInfo.updatedAt) > DefaultTickersUpdateInterval {
+// fetchCategoryTickers retrieves tickers of a single category along with the conversion errors
+func (bc *Client) fetchCategoryTickers(ctx context.Context, category string) ([]exchanges.Ticker, []error, error) {
+	response, receivedAt, err := bc.getTickers(ctx, category)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bc.shouldUpdateTickers(category) {
 		var availableTickers []string
 		for _, ticker := range response.Result.List {
 			availableTickers = append(availableTickers, ticker.Symbol)
 		}
-		bc.setAvailableTickers(availableTickers)
+		bc.setAvailableTickers(category, availableTickers)
 	}
 
-	return convertTickers(response.Result.List, time.Unix(0, response.Time*int64(time.Millisecond))), nil
+	// Bybit tickers carry no time of their own, they are all stamped by the fallback
+	tickers, convErrs := convertTickers(response.Result.List, category, bc.eventAt, exchanges.UnixMilli(response.Time), receivedAt)
+	return tickers, convErrs, nil
+}
+
+// getTickers requests the tickers of a single category and returns them with the time they were received
+func (bc *Client) getTickers(ctx context.Context, category string) (*TickerResponse, time.Time, error) {
+	url := bc.httpURL + FetchTickersPath + "?category=" + category
+
+	resp, err := bc.get(ctx, url)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+	}
+	receivedAt := time.Now().UTC()
+
+	var response TickerResponse
+	if err := exchanges.JSON.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, time.Time{}, fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return &response, receivedAt, nil
 }
 
-// convertTickers converts Bybit-specific ticker DTOs to normalized tickers
-func convertTickers(bybitTickers []TickerDTO, eventAt time.Time) []exchanges.Ticker {
+// convertTickers converts Bybit-specific ticker DTOs to normalized tickers, skipping and reporting invalid ones
+func convertTickers(bybitTickers []TickerDTO, category string, fallback exchanges.EventAtFallback, responseAt, receivedAt time.Time) ([]exchanges.Ticker, []error) {
 	tickers := make([]exchanges.Ticker, 0, len(bybitTickers))
+	var errs []error
 
 	for _, bt := range bybitTickers {
 		ticker, err := bt.toTicker()
-		ticker.EventAt = eventAt
 		if err != nil {
-			log.Printf("Warning: failed to convert ticker: %v", err)
+			errs = append(errs, fmt.Errorf("%s: %w", bt.Symbol, err))
+			continue
+		}
+		if ticker.OpenInterest, err = bt.openInterest(category); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", bt.Symbol, err))
+			continue
+		}
+		if err := fallback.Stamp(&ticker, responseAt, receivedAt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", bt.Symbol, err))
 			continue
 		}
+		ticker.InstType = category
+		if category == CategoryInverse {
+			setInverseAssets(&ticker)
+		}
 		tickers = append(tickers, ticker)
 	}
 
-	return tickers
+	return tickers, errs
+}
+
+//------------------------------------------------------------------------------
+// Fetch Funding Rates API Methods
+//------------------------------------------------------------------------------
+
+// FetchFundingRates retrieves the current funding rate of the perpetuals of the linear and inverse categories
+// from their tickers, expiring futures have none and options are skipped
+func (bc *Client) FetchFundingRates(ctx context.Context) ([]exchanges.FundingRate, error) {
+	rates := make([]exchanges.FundingRate, 0)
+	var convErrs []error
+	for _, category := range bc.categories {
+		if category == CategoryOption {
+			continue
+		}
+		response, receivedAt, err := bc.getTickers(ctx, category)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s funding rates: %w", category, err)
+		}
+
+		eventAt := exchanges.UnixMilli(response.Time)
+		if eventAt.IsZero() {
+			eventAt = receivedAt
+		}
+		for _, bt := range response.Result.List {
+			if bt.FundingRate == "" {
+				continue
+			}
+			rate, err := bt.toFundingRate(eventAt)
+			if err != nil {
+				convErrs = append(convErrs, fmt.Errorf("%s: %w", bt.Symbol, err))
+				continue
+			}
+			if base, ok := inverseBase(bt.Symbol); ok && category == CategoryInverse {
+				rate.Base, rate.Quote = base, "USD"
+			}
+			rates = append(rates, rate)
+		}
+	}
+	return rates, exchanges.NewConversionError(len(rates)+len(convErrs), convErrs)
 }
 
 //------------------------------------------------------------------------------
@@ -134,18 +264,28 @@ func (bc *Client) SubscribeLiquidations(ctx context.Context) (liquidations <-cha
 	out := make(chan exchanges.Liquidation, DefaultChannelBuffer)
 	errCh := make(chan error, DefaultChannelBuffer)
 
-	go bc.handleLiquidationSubscription(ctx, out, errCh)
+	// every category has its own stream, so each one gets its own connection
+	var wg sync.WaitGroup
+	for _, category := range bc.categories {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bc.handleLiquidationSubscription(ctx, category, out, errCh)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+		close(errCh)
+	}()
 
 	return out, errCh
 }
 
-// handleLiquidationSubscription manages the websocket connection lifecycle
-func (bc *Client) handleLiquidationSubscription(ctx context.Context, out chan<- exchanges.Liquidation, errCh chan<- error) {
-	defer close(out)
-	defer close(errCh)
-
+// handleLiquidationSubscription manages the websocket connection lifecycle of a category
+func (bc *Client) handleLiquidationSubscription(ctx context.Context, category string, out chan<- exchanges.Liquidation, errCh chan<- error) {
 	for {
-		if err := bc.connectAndHandle(ctx, out, errCh); err != nil {
+		if err := bc.connectAndHandle(ctx, category, out, errCh); err != nil {
 			select {
 			case errCh <- fmt.Errorf("websocket error: %w", err):
 			default:
@@ -153,25 +293,34 @@ func (bc *Client) handleLiquidationSubscription(ctx context.Context, out chan<-
 			}
 		}
 
+		delay := bc.wsConfig.ReconnectDelayOr(DefaultReconnectDelay)
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			log.Printf("Reconnecting in %s...", DefaultReconnectDelay)
-			time.Sleep(DefaultReconnectDelay)
+			log.Printf("Reconnecting in %s...", delay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
 		}
 	}
 }
 
 // connectAndHandle establishes and manages a single websocket connection
-func (bc *Client) connectAndHandle(ctx context.Context, out chan<- exchanges.Liquidation, errCh chan<- error) error {
-	conn, _, err := websocket.DefaultDialer.Dial(bc.wsURL, nil)
+func (bc *Client) connectAndHandle(ctx context.Context, category string, out chan<- exchanges.Liquidation, errCh chan<- error) error {
+	conn, err := bc.wsConfig.Dial(ctx, bc.categoryWSURL(category))
 	if err != nil {
 		return fmt.Errorf("websocket dial: %w", err)
 	}
 	defer conn.Close()
 
-	availableTickers := bc.getAvailableTickers()
+	// unblock ReadMessage on shutdown
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	availableTickers := bc.getAvailableTickers(category)
 	if len(availableTickers) == 0 {
 		return nil
 	}
@@ -206,6 +355,9 @@ func (bc *Client) readMessages(ctx context.Context, conn *websocket.Conn, out ch
 
 			_, msg, err := conn.ReadMessage()
 			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
 				return fmt.Errorf("reading message: %w", err)
 			}
 
@@ -219,7 +371,7 @@ func (bc *Client) readMessages(ctx context.Context, conn *websocket.Conn, out ch
 // processMessage handles the deserialization and conversion of websocket messages
 func (bc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exchanges.Liquidation, errCh chan<- error) error {
 	var event LiquidationEvent
-	if err := json.Unmarshal(msg, &event); err != nil {
+	if err := exchanges.JSON.Unmarshal(msg, &event); err != nil {
 		select {
 		case errCh <- err:
 		default:
@@ -242,6 +394,7 @@ func (bc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exc
 		}
 		return err
 	}
+	liquidation.Raw = msg
 
 	select {
 	case out <- liquidation:
@@ -260,13 +413,33 @@ func (bc *Client) GetName() string {
 	return bc.name
 }
 
-// setAvailableTickers updates the available tickers with proper locking
-func (bc *Client) setAvailableTickers(tickers []string) {
-	bc.tickersInfo.availableTickers = tickers
-	bc.tickersInfo.updatedAt = time.Now()
+// categoryWSURL returns the public stream of the category.
+// Only URLs ending with the linear category are rewritten, custom URLs are used as is
+func (bc *Client) categoryWSURL(category string) string {
+	if prefix, ok := strings.CutSuffix(bc.wsURL, "/"+CategoryLinear); ok {
+		return prefix + "/" + category
+	}
+	return bc.wsURL
+}
+
+// shouldUpdateTickers reports whether the symbol universe of the category is missing or outdated
+func (bc *Client) shouldUpdateTickers(category string) bool {
+	bc.tickersInfo.mu.Lock()
+	defer bc.tickersInfo.mu.Unlock()
+	return len(bc.tickersInfo.availableTickers[category]) == 0 || time.Since(bc.tickersInfo.updatedAt[category]) > DefaultTickersUpdateInterval
+}
+
+// setAvailableTickers updates the available tickers of a category with proper locking
+func (bc *Client) setAvailableTickers(category string, tickers []string) {
+	bc.tickersInfo.mu.Lock()
+	defer bc.tickersInfo.mu.Unlock()
+	bc.tickersInfo.availableTickers[category] = tickers
+	bc.tickersInfo.updatedAt[category] = time.Now()
 }
 
-// getAvailableTickers safely retrieves the available tickers
-func (bc *Client) getAvailableTickers() []string {
-	return append([]string{}, bc.tickersInfo.availableTickers...)
+// getAvailableTickers safely retrieves the available tickers of a category
+func (bc *Client) getAvailableTickers(category string) []string {
+	bc.tickersInfo.mu.Lock()
+	defer bc.tickersInfo.mu.Unlock()
+	return append([]string{}, bc.tickersInfo.availableTickers[category]...)
 }
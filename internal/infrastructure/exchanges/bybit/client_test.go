@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/exchangestest"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,6 +53,7 @@ func TestClient_FetchTickers(t *testing.T) {
 		statusCode    int
 		expectError   bool
 		wantTickers   []exchanges.Ticker
+		wantFailed    int // tickers dropped because of conversion errors
 		contextCancel bool
 	}{
 		{
@@ -73,6 +75,9 @@ func TestClient_FetchTickers(t *testing.T) {
 							AskPrice:    "50000.75",
 							AskQuantity: "2.5",
 							LastPrice:   "50000.60",
+
+							OpenInterest:      "1234.5",
+							OpenInterestValue: "61725625.93",
 						},
 					},
 				},
@@ -81,12 +86,17 @@ func TestClient_FetchTickers(t *testing.T) {
 			expectError: false,
 			wantTickers: []exchanges.Ticker{
 				{
-					Symbol:      "BTCUSDT",
-					BidPrice:    50000.50,
-					BidQuantity: 1.5,
-					AskPrice:    50000.75,
-					AskQuantity: 2.5,
-					EventAt:     time.Unix(0, 1738253085440*int64(time.Millisecond)),
+					Symbol:        "BTCUSDT",
+					Base:          "BTC",
+					Quote:         "USDT",
+					InstType:      CategoryLinear,
+					BidPrice:      50000.50,
+					BidQuantity:   1.5,
+					AskPrice:      50000.75,
+					AskQuantity:   2.5,
+					EventAt:       time.Unix(0, 1738253085440*int64(time.Millisecond)).UTC(),
+					EventAtSource: exchanges.EventAtSourceResponse,
+					OpenInterest:  1234.5,
 				},
 			},
 		},
@@ -130,6 +140,7 @@ func TestClient_FetchTickers(t *testing.T) {
 			statusCode:  http.StatusOK,
 			expectError: false,
 			wantTickers: []exchanges.Ticker{},
+			wantFailed:  1,
 		},
 	}
 
@@ -163,6 +174,14 @@ func TestClient_FetchTickers(t *testing.T) {
 				return
 			}
 
+			if tt.wantFailed > 0 {
+				var convErr *exchanges.ConversionError
+				require.ErrorAs(t, err, &convErr)
+				assert.Equal(t, tt.wantFailed, convErr.Failed)
+				assert.Equal(t, tt.wantTickers, got)
+				return
+			}
+
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantTickers, got)
 		})
@@ -268,7 +287,7 @@ func TestClient_SubscribeLiquidations(t *testing.T) {
 
 			// Set available tickers for test if not skipped
 			if !tt.skipTickerSetup {
-				client.setAvailableTickers(tt.availableTickers)
+				client.setAvailableTickers(CategoryLinear, tt.availableTickers)
 			}
 
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
@@ -333,3 +352,84 @@ func TestClient_SubscribeLiquidations(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_FetchTickersMultipleCategories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		symbol := map[string]string{CategoryLinear: "BTCUSDT", CategoryInverse: "BTCUSD"}[r.URL.Query().Get("category")]
+		var response TickerResponse
+		response.Result.List = []TickerDTO{{Symbol: symbol, BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1",
+			OpenInterest: "3000", OpenInterestValue: "2000"}}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewBybit(Config{APIUrl: server.URL, Categories: []string{CategoryLinear, CategoryInverse}})
+	got, err := client.FetchTickers(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "BTCUSDT", got[0].Symbol)
+	assert.Equal(t, CategoryLinear, got[0].InstType)
+	assert.Equal(t, "BTCUSD", got[1].Symbol)
+	assert.Equal(t, CategoryInverse, got[1].InstType)
+	assert.Equal(t, 3000.0, got[0].OpenInterest, "linear open interest is in base asset units")
+	assert.Equal(t, 2000.0, got[1].OpenInterest, "inverse open interest is in USD, its value in base asset units")
+	assert.Equal(t, []string{"BTCUSD"}, client.getAvailableTickers(CategoryInverse))
+}
+
+func TestClient_FetchFundingRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, FetchTickersPath, r.URL.Path)
+		response := TickerResponse{Time: 1703070685309}
+		switch r.URL.Query().Get("category") {
+		case CategoryLinear:
+			response.Result.List = []TickerDTO{
+				{Symbol: "BTCUSDT", MarkPrice: "42000.5", FundingRate: "0.0001", NextFundingTime: "1703088000000"},
+				{Symbol: "BTC-29MAR24", MarkPrice: "43000"}, // expiring futures have no funding
+				{Symbol: "ETHUSDT", MarkPrice: "2200", FundingRate: "invalid", NextFundingTime: "1703088000000"},
+			}
+		case CategoryInverse:
+			response.Result.List = []TickerDTO{
+				{Symbol: "BTCUSD", MarkPrice: "42001", FundingRate: "-0.0002", NextFundingTime: "1703088000000"},
+			}
+		default:
+			t.Errorf("unexpected category of %s", r.URL)
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := NewBybit(Config{APIUrl: server.URL, Categories: []string{CategoryLinear, CategoryInverse, CategoryOption}})
+	got, err := client.FetchFundingRates(context.Background())
+
+	var convErr *exchanges.ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, 3, convErr.Total)
+	assert.Equal(t, 1, convErr.Failed)
+	eventAt, nextFundingAt := time.UnixMilli(1703070685309).UTC(), time.UnixMilli(1703088000000).UTC()
+	assert.Equal(t, []exchanges.FundingRate{
+		{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", Rate: 0.0001, MarkPrice: 42000.5, NextFundingAt: nextFundingAt, EventAt: eventAt},
+		{Symbol: "BTCUSD", Base: "BTC", Quote: "USD", Rate: -0.0002, MarkPrice: 42001, NextFundingAt: nextFundingAt, EventAt: eventAt},
+	}, got)
+}
+
+func TestClient_CategoryWSURL(t *testing.T) {
+	client := NewBybit(Config{})
+	assert.Equal(t, "wss://stream.bybit.com/v5/public/linear", client.categoryWSURL(CategoryLinear))
+	assert.Equal(t, "wss://stream.bybit.com/v5/public/inverse", client.categoryWSURL(CategoryInverse))
+
+	custom := NewBybit(Config{WSUrl: "ws://proxy.local/stream"})
+	assert.Equal(t, "ws://proxy.local/stream", custom.categoryWSURL(CategoryInverse))
+}
+
+func TestClient_Conformance(t *testing.T) {
+	exchangestest.Run(t, exchangestest.Fixture{
+		New: func(apiURL, wsURL string, ws exchanges.WebsocketConfig) exchanges.Exchange {
+			return NewBybit(Config{Name: "test", APIUrl: apiURL, WSUrl: wsURL, HTTPClient: http.DefaultClient, Websocket: ws})
+		},
+		Tickers: json.RawMessage(`{"retCode":0,"retMsg":"OK","time":1738253085440,"result":{"category":"linear","list":[
+			{"symbol":"BTCUSDT","bid1Price":"50000.50","bid1Size":"1.5","ask1Price":"50000.75","ask1Size":"2.5","lastPrice":"50000.60"},
+			{"symbol":"ETHUSDT","bid1Price":"3000.10","bid1Size":"10","ask1Price":"3000.20","ask1Size":"12","lastPrice":"3000.15"}]}}`),
+		Liquidation: `{"topic":"liquidation.BTCUSDT","type":"snapshot","data":{"symbol":"BTCUSDT","side":"Sell","price":"50000.50","size":"0.001","updatedTime":1635739200000},"ts":1635739200000}`,
+	})
+}
@@ -3,6 +3,7 @@ package bybit
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
@@ -15,8 +16,30 @@ const (
 	// FuturesWSUrl is the base URL for the Bybit Futures Websocket API
 	FuturesWSUrl = "wss://stream.bybit.com/v5/public/linear"
 
-	// FetchTickersData is the endpoint to fetch tickers data
-	FetchTickersData = "/market/tickers?category=linear"
+	// FetchTickersPath is the endpoint to fetch tickers data, the category query parameter is required
+	FetchTickersPath = "/market/tickers"
+
+	// RequestLimit is the number of requests an IP may send per RequestWindow before being banned
+	RequestLimit = 600
+
+	// RequestWindow is the window of RequestLimit
+	RequestWindow = 5 * time.Second
+
+	// LimitHeader and LimitStatusHeader report the limit of the endpoint and the requests remaining within it
+	LimitHeader       = "X-Bapi-Limit"
+	LimitStatusHeader = "X-Bapi-Limit-Status"
+)
+
+// Categories supported by the tickers endpoint, each one has its own public websocket stream
+const (
+	// CategoryLinear is USDT/USDC perpetuals and futures
+	CategoryLinear = "linear"
+
+	// CategoryInverse is coin margined perpetuals and futures
+	CategoryInverse = "inverse"
+
+	// CategoryOption is USDC options
+	CategoryOption = "option"
 )
 
 // TickerResponse represents the API response for ticker data
@@ -38,6 +61,16 @@ type TickerDTO struct {
 	AskPrice    string `json:"ask1Price"`
 	AskQuantity string `json:"ask1Size"`
 	LastPrice   string `json:"lastPrice"`
+
+	// funding of perpetuals, empty for expiring futures and options
+	MarkPrice       string `json:"markPrice"`
+	FundingRate     string `json:"fundingRate"`
+	NextFundingTime string `json:"nextFundingTime"`
+
+	// open positions, in base asset units for linear instruments and in USD for inverse ones whose
+	// OpenInterestValue is in base asset units
+	OpenInterest      string `json:"openInterest"`
+	OpenInterestValue string `json:"openInterestValue"`
 }
 
 // toTicker converts a TickerDTO to an exchanges.Ticker
@@ -62,6 +95,7 @@ func (bt TickerDTO) toTicker() (exchanges.Ticker, error) {
 	}
 
 	ticker.Symbol = bt.Symbol
+	ticker.Base, ticker.Quote = exchanges.SplitSymbol(bt.Symbol)
 	ticker.BidPrice = bidPrice
 	ticker.AskPrice = askPrice
 	ticker.BidQuantity = bidQuantity
@@ -70,6 +104,86 @@ func (bt TickerDTO) toTicker() (exchanges.Ticker, error) {
 	return ticker, nil
 }
 
+// openInterest returns the open interest of a ticker of the category in base asset units, 0 when it's not reported
+func (bt TickerDTO) openInterest(category string) (float64, error) {
+	field, value := "openInterest", bt.OpenInterest
+	if category == CategoryInverse {
+		field, value = "openInterestValue", bt.OpenInterestValue
+	}
+	if value == "" {
+		return 0, nil
+	}
+	openInterest, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s': %w", field, value, err)
+	}
+	return openInterest, nil
+}
+
+// toFundingRate converts the funding of a perpetual TickerDTO to an exchanges.FundingRate observed at eventAt
+func (bt TickerDTO) toFundingRate(eventAt time.Time) (exchanges.FundingRate, error) {
+	rate, err := strconv.ParseFloat(bt.FundingRate, 64)
+	if err != nil {
+		return exchanges.FundingRate{}, fmt.Errorf("invalid fundingRate '%s': %w", bt.FundingRate, err)
+	}
+	var markPrice float64
+	if bt.MarkPrice != "" {
+		if markPrice, err = strconv.ParseFloat(bt.MarkPrice, 64); err != nil {
+			return exchanges.FundingRate{}, fmt.Errorf("invalid markPrice '%s': %w", bt.MarkPrice, err)
+		}
+	}
+	nextFundingTime, err := strconv.ParseInt(bt.NextFundingTime, 10, 64)
+	if err != nil {
+		return exchanges.FundingRate{}, fmt.Errorf("invalid nextFundingTime '%s': %w", bt.NextFundingTime, err)
+	}
+
+	fundingRate := exchanges.FundingRate{
+		Symbol:        bt.Symbol,
+		Rate:          rate,
+		MarkPrice:     markPrice,
+		NextFundingAt: exchanges.UnixMilli(nextFundingTime),
+		EventAt:       eventAt,
+	}
+	fundingRate.Base, fundingRate.Quote = exchanges.SplitSymbol(bt.Symbol)
+	return fundingRate, nil
+}
+
+// inverseContractValue is the USD face value of a contract of Bybit inverse instruments, their sizes are in USD
+const inverseContractValue = 1
+
+// setInverseAssets marks a ticker of the inverse category as coin-margined. Inverse futures carry the
+// expiry after the quote (BTCUSDH25), so the assets are cut at USD instead of split by suffix
+func setInverseAssets(ticker *exchanges.Ticker) {
+	base, ok := inverseBase(ticker.Symbol)
+	if !ok {
+		return
+	}
+	ticker.Base, ticker.Quote = base, "USD"
+	ticker.ContractValue = inverseContractValue
+}
+
+// inverseBase returns the base coin of an inverse instrument symbol, e.g. BTC of BTCUSD or BTCUSDH25:
+// the coin quoted in USD, followed by the month code and the year of the expiry for futures
+func inverseBase(symbol string) (string, bool) {
+	base, expiry, found := strings.Cut(symbol, "USD")
+	if !found || base == "" {
+		return "", false
+	}
+	if expiry == "" {
+		return base, true
+	}
+	if len(expiry) != 3 || !strings.ContainsRune(futuresMonthCodes, rune(expiry[0])) {
+		return "", false
+	}
+	if _, err := strconv.Atoi(expiry[1:]); err != nil {
+		return "", false
+	}
+	return base, true
+}
+
+// futuresMonthCodes are the letters of the expiry months of futures, January to December
+const futuresMonthCodes = "FGHJKMNQUVXZ"
+
 // LiquidationEvent represents a liquidation websocket event
 type LiquidationEvent struct {
 	Topic string         `json:"topic"`
@@ -78,6 +192,13 @@ type LiquidationEvent struct {
 	TS    int64          `json:"ts"`
 }
 
+// liquidationSides maps the Bybit side to the normalized one.
+// Bybit reports the side of the liquidated position, so "Buy" means a long position was liquidated
+var liquidationSides = map[string]exchanges.LiquidationSide{
+	"Buy":  exchanges.LongLiquidated,
+	"Sell": exchanges.ShortLiquidated,
+}
+
 // LiquidationDTO represents a liquidation order from Bybit
 type LiquidationDTO struct {
 	Symbol      string `json:"symbol"`
@@ -101,19 +222,21 @@ func (bl LiquidationDTO) toLiquidation() (exchanges.Liquidation, error) {
 	}
 
 	liquidation.Price = price
-	liquidation.Quantity = quantity
 	liquidation.Symbol = bl.Symbol
-	liquidation.EventAt = time.Unix(0, bl.UpdatedTime*int64(time.Millisecond))
+	liquidation.Base, liquidation.Quote = exchanges.SplitSymbol(bl.Symbol)
+	if base, ok := inverseBase(bl.Symbol); ok {
+		// the sizes of inverse perpetuals and futures are in USD
+		liquidation.Base, liquidation.Quote = base, "USD"
+		quantity = exchanges.ContractSpec{FaceValue: inverseContractValue}.BaseQuantity(quantity, price)
+	}
+	liquidation.Quantity = quantity
+	liquidation.EventAt = time.Unix(0, bl.UpdatedTime*int64(time.Millisecond)).UTC()
 	liquidation.TotalPrice = price * quantity
-	switch bl.Side {
-	case "Buy":
-		liquidation.Side = "SELL"
-	case "Sell":
-		liquidation.Side = "BUY"
-	default:
+	side, ok := liquidationSides[bl.Side]
+	if !ok {
 		return liquidation, fmt.Errorf("invalid side '%s'", bl.Side)
-
 	}
+	liquidation.Side = side
 
 	return liquidation, nil
 }
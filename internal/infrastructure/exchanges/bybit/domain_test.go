@@ -28,6 +28,8 @@ func TestTickerDTO_ToTicker(t *testing.T) {
 			},
 			want: exchanges.Ticker{
 				Symbol:      "BTCUSDT",
+				Base:        "BTC",
+				Quote:       "USDT",
 				BidPrice:    50000.50,
 				BidQuantity: 1.5,
 				AskPrice:    50000.75,
@@ -116,14 +118,56 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 			},
 			want: exchanges.Liquidation{
 				Symbol:     "BTCUSDT",
-				Side:       "BUY",
+				Base:       "BTC",
+				Quote:      "USDT",
+				Side:       exchanges.ShortLiquidated,
 				Price:      50000.50,
 				Quantity:   0.001,
-				EventAt:    time.UnixMilli(1635739200000),
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
 				TotalPrice: 50.0005,
 			},
 			wantErr: false,
 		},
+		{
+			name: "inverse sizes in USD",
+			dto: LiquidationDTO{
+				Symbol:      "BTCUSD",
+				Side:        "Buy",
+				Price:       "50000",
+				Quantity:    "2000",
+				UpdatedTime: 1635739200000,
+			},
+			want: exchanges.Liquidation{
+				Symbol:     "BTCUSD",
+				Base:       "BTC",
+				Quote:      "USD",
+				Side:       exchanges.LongLiquidated,
+				Price:      50000,
+				Quantity:   0.04,
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
+				TotalPrice: 2000,
+			},
+		},
+		{
+			name: "inverse future sizes in USD",
+			dto: LiquidationDTO{
+				Symbol:      "BTCUSDH25",
+				Side:        "Sell",
+				Price:       "80000",
+				Quantity:    "4000",
+				UpdatedTime: 1635739200000,
+			},
+			want: exchanges.Liquidation{
+				Symbol:     "BTCUSDH25",
+				Base:       "BTC",
+				Quote:      "USD",
+				Side:       exchanges.ShortLiquidated,
+				Price:      80000,
+				Quantity:   0.05,
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
+				TotalPrice: 4000,
+			},
+		},
 		{
 			name: "invalid price",
 			dto: LiquidationDTO{
@@ -147,6 +191,21 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 			want:    exchanges.Liquidation{},
 			wantErr: true,
 		},
+		{
+			name:    "upper case side",
+			dto:     LiquidationDTO{Symbol: "BTCUSDT", Side: "BUY", Price: "100", Quantity: "1", UpdatedTime: 1635739200000},
+			wantErr: true,
+		},
+		{
+			name:    "lower case side",
+			dto:     LiquidationDTO{Symbol: "BTCUSDT", Side: "sell", Price: "100", Quantity: "1", UpdatedTime: 1635739200000},
+			wantErr: true,
+		},
+		{
+			name:    "missing side",
+			dto:     LiquidationDTO{Symbol: "BTCUSDT", Price: "100", Quantity: "1", UpdatedTime: 1635739200000},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -161,3 +220,29 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 		})
 	}
 }
+
+func TestSetInverseAssets(t *testing.T) {
+	tests := []struct {
+		symbol            string
+		wantBase          string
+		wantQuote         string
+		wantContractValue float64
+	}{
+		{symbol: "BTCUSD", wantBase: "BTC", wantQuote: "USD", wantContractValue: 1},
+		{symbol: "ETHUSDH25", wantBase: "ETH", wantQuote: "USD", wantContractValue: 1},
+		{symbol: "USDT"},
+		{symbol: "BTCUSDT"},
+		{symbol: "BTCUSDC"},
+		{symbol: "BTCUSDA25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			ticker := exchanges.Ticker{Symbol: tt.symbol}
+			setInverseAssets(&ticker)
+			assert.Equal(t, tt.wantBase, ticker.Base)
+			assert.Equal(t, tt.wantQuote, ticker.Quote)
+			assert.Equal(t, tt.wantContractValue, ticker.ContractValue)
+		})
+	}
+}
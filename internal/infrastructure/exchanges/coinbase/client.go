@@ -0,0 +1,164 @@
+// Package coinbase provides a client for interacting with the Coinbase International Exchange perpetual futures API
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+const (
+	// FuturesAPIURL is the base URL for the Coinbase International Exchange API
+	FuturesAPIURL = "https://api.international.coinbase.com/api/v1"
+
+	// FetchInstrumentsPath is the endpoint listing the instruments along with their top of the book quote
+	FetchInstrumentsPath = "/instruments"
+
+	// FetchInstrumentsLimit is the number of public requests the importer sends per FetchInstrumentsWindow.
+	// Coinbase doesn't report the usage in its responses, so it's only accounted locally
+	FetchInstrumentsLimit = 10
+
+	// FetchInstrumentsWindow is the window of FetchInstrumentsLimit
+	FetchInstrumentsWindow = time.Second
+)
+
+// Config holds the configuration for the Coinbase client
+type Config struct {
+	Name       string
+	APIUrl     string
+	HTTPClient *http.Client
+
+	// EventAtFallback stamps the tickers without their own timestamp, defaults to the exchange response time
+	EventAtFallback exchanges.EventAtFallback
+}
+
+// Client implements a Coinbase International Exchange client importing its perpetual futures
+type Client struct {
+	name       string
+	httpURL    string
+	httpClient *http.Client
+	budget     *exchanges.APIBudget
+	eventAt    exchanges.EventAtFallback
+}
+
+// NewCoinbase creates a new Coinbase client with the provided configuration
+func NewCoinbase(cfg Config) *Client {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if cfg.APIUrl == "" {
+		cfg.APIUrl = FuturesAPIURL
+	}
+
+	return &Client{
+		name:       cfg.Name,
+		httpURL:    cfg.APIUrl,
+		httpClient: cfg.HTTPClient,
+		eventAt:    cfg.EventAtFallback,
+		budget:     exchanges.NewAPIBudget(FetchInstrumentsLimit, FetchInstrumentsWindow),
+	}
+}
+
+// APIUsage returns the instruments requests sent within the current window
+func (cc *Client) APIUsage() exchanges.APIUsage {
+	return cc.budget.Usage()
+}
+
+//------------------------------------------------------------------------------
+// Fetch Tickers API Methods
+//------------------------------------------------------------------------------
+
+// FetchTickers retrieves the top of the book of the perpetual futures open for trading
+func (cc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error) {
+	url := cc.httpURL + FetchInstrumentsPath
+
+	if err := cc.budget.Acquire(1); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for %s: %w", url, err)
+	}
+
+	resp, err := cc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request for %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if cc.budget.BlockRetryAfter(resp) {
+		return nil, fmt.Errorf("%w: status %s from %s", exchanges.ErrThrottled, resp.Status, url)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+	}
+	receivedAt := time.Now().UTC()
+
+	var instruments []InstrumentDTO
+	if err := exchanges.JSON.NewDecoder(resp.Body).Decode(&instruments); err != nil {
+		return nil, fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+
+	// quotes without a timestamp fall back to the Date header, precise to the second
+	responseAt, _ := http.ParseTime(resp.Header.Get("Date"))
+	tickers, convErrs := convertTickers(instruments, cc.eventAt, responseAt, receivedAt)
+	return tickers, exchanges.NewConversionError(len(tickers)+len(convErrs), convErrs)
+}
+
+// convertTickers converts the quotes of the tradable perpetuals to normalized tickers, skipping and reporting
+// invalid ones. Spot instruments and instruments not open for trading are left out
+func convertTickers(instruments []InstrumentDTO, fallback exchanges.EventAtFallback, responseAt, receivedAt time.Time) ([]exchanges.Ticker, []error) {
+	tickers := make([]exchanges.Ticker, 0, len(instruments))
+	var errs []error
+
+	for _, instrument := range instruments {
+		if instrument.Type != InstrumentTypePerp || instrument.TradingState != TradingStateTrading {
+			continue
+		}
+		ticker, err := instrument.toTicker()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", instrument.Symbol, err))
+			continue
+		}
+		if err := fallback.Stamp(&ticker, responseAt, receivedAt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", instrument.Symbol, err))
+			continue
+		}
+		tickers = append(tickers, ticker)
+	}
+
+	return tickers, errs
+}
+
+//------------------------------------------------------------------------------
+// Fetch Liquidations API Methods
+//------------------------------------------------------------------------------
+
+// SubscribeLiquidations returns channels closed once ctx is done. Coinbase International publishes no liquidation
+// feed, the liquidations of its symbols come from the external sources (LIQUIDATIONS_COINGLASS_*) when enabled
+func (cc *Client) SubscribeLiquidations(ctx context.Context) (liquidations <-chan exchanges.Liquidation, errors <-chan error) {
+	out := make(chan exchanges.Liquidation)
+	errCh := make(chan error)
+
+	go func() {
+		defer close(out)
+		defer close(errCh)
+		<-ctx.Done()
+	}()
+
+	return out, errCh
+}
+
+//------------------------------------------------------------------------------
+// Other methods
+//------------------------------------------------------------------------------
+
+// GetName returns the name of the client instance
+func (cc *Client) GetName() string {
+	return cc.name
+}
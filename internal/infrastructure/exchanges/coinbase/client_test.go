@@ -0,0 +1,128 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/exchangestest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCoinbase(t *testing.T) {
+	client := NewCoinbase(Config{Name: "test-coinbase"})
+	assert.Equal(t, "test-coinbase", client.GetName())
+	assert.Equal(t, FuturesAPIURL, client.httpURL)
+	assert.Equal(t, float64(FetchInstrumentsLimit), client.APIUsage().Limit)
+}
+
+func TestClient_FetchTickers(t *testing.T) {
+	btc := InstrumentDTO{
+		Symbol: "BTC-PERP", Type: InstrumentTypePerp, Base: "BTC", Quote: "USDC", TradingState: TradingStateTrading,
+		Book: QuoteDTO{BidPrice: "50000.25", BidQuantity: "1.5", AskPrice: "50000.75", AskQuantity: "2.5", Timestamp: "2021-11-01T04:00:00.123Z"},
+	}
+	spot := InstrumentDTO{
+		Symbol: "BTC-USDC", Type: "SPOT", Base: "BTC", Quote: "USDC", TradingState: TradingStateTrading,
+		Book: QuoteDTO{BidPrice: "50000", BidQuantity: "1", AskPrice: "50001", AskQuantity: "1"},
+	}
+	halted := InstrumentDTO{Symbol: "ETH-PERP", Type: InstrumentTypePerp, Base: "ETH", Quote: "USDC", TradingState: "HALT"}
+	noBook := InstrumentDTO{Symbol: "SOL-PERP", Type: InstrumentTypePerp, Base: "SOL", Quote: "USDC", TradingState: TradingStateTrading}
+
+	tests := []struct {
+		name        string
+		response    []InstrumentDTO
+		statusCode  int
+		wantTickers []exchanges.Ticker
+		wantFailed  int // tickers dropped because of conversion errors
+		wantErr     bool
+	}{
+		{
+			name:     "tradable perpetuals only",
+			response: []InstrumentDTO{btc, spot, halted},
+			wantTickers: []exchanges.Ticker{{
+				Symbol:        "BTC-PERP",
+				InstType:      InstrumentTypePerp,
+				Base:          "BTC",
+				Quote:         "USDC",
+				BidPrice:      50000.25,
+				BidQuantity:   1.5,
+				AskPrice:      50000.75,
+				AskQuantity:   2.5,
+				EventAt:       time.Date(2021, 11, 1, 4, 0, 0, 123e6, time.UTC),
+				EventAtSource: exchanges.EventAtSourceExchange,
+			}},
+		},
+		{
+			name:        "perpetual without a book",
+			response:    []InstrumentDTO{noBook},
+			wantTickers: []exchanges.Ticker{},
+			wantFailed:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, FetchInstrumentsPath, r.URL.Path)
+				if tt.statusCode != 0 {
+					w.WriteHeader(tt.statusCode)
+				}
+				json.NewEncoder(w).Encode(tt.response)
+			}))
+			defer server.Close()
+
+			client := NewCoinbase(Config{Name: "test", APIUrl: server.URL})
+			got, err := client.FetchTickers(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if tt.wantFailed > 0 {
+				var convErr *exchanges.ConversionError
+				require.ErrorAs(t, err, &convErr)
+				assert.Equal(t, tt.wantFailed, convErr.Failed)
+				assert.Equal(t, tt.wantTickers, got)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTickers, got)
+		})
+	}
+}
+
+func TestClient_FetchTickersThrottled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewCoinbase(Config{APIUrl: server.URL})
+	_, err := client.FetchTickers(context.Background())
+	require.ErrorIs(t, err, exchanges.ErrThrottled)
+
+	// the budget backs off until the end of the window instead of sending the next request
+	_, err = client.FetchTickers(context.Background())
+	require.ErrorIs(t, err, exchanges.ErrThrottled)
+	assert.Equal(t, int64(1), client.APIUsage().Throttled)
+}
+
+func TestClient_Conformance(t *testing.T) {
+	exchangestest.Run(t, exchangestest.Fixture{
+		New: func(apiURL, _ string, _ exchanges.WebsocketConfig) exchanges.Exchange {
+			return NewCoinbase(Config{Name: "test", APIUrl: apiURL, HTTPClient: http.DefaultClient})
+		},
+		Tickers: json.RawMessage(`[
+			{"symbol":"BTC-PERP","type":"PERP","base_asset_name":"BTC","quote_asset_name":"USDC","trading_state":"TRADING",
+			 "quote":{"best_bid_price":"50000.25","best_bid_size":"1.5","best_ask_price":"50000.75","best_ask_size":"2.5","trade_price":"50000.5","timestamp":"2021-11-01T04:00:00.123Z"}},
+			{"symbol":"ETH-PERP","type":"PERP","base_asset_name":"ETH","quote_asset_name":"USDC","trading_state":"TRADING",
+			 "quote":{"best_bid_price":"3000.10","best_bid_size":"10","best_ask_price":"3000.20","best_ask_size":"12","trade_price":"3000.15","timestamp":"2021-11-01T04:00:00.123Z"}}]`),
+		NoLiquidationStream: true,
+	})
+}
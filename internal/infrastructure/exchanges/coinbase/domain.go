@@ -0,0 +1,79 @@
+package coinbase
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+const (
+	// InstrumentTypePerp is the perpetual futures instrument type, the only one imported
+	InstrumentTypePerp = "PERP"
+
+	// TradingStateTrading is the trading state of the instruments open for trading
+	TradingStateTrading = "TRADING"
+)
+
+// InstrumentDTO represents an instrument of the Coinbase International API
+type InstrumentDTO struct {
+	Symbol       string   `json:"symbol"` // e.g. BTC-PERP
+	Type         string   `json:"type"`   // PERP or SPOT
+	Base         string   `json:"base_asset_name"`
+	Quote        string   `json:"quote_asset_name"`
+	TradingState string   `json:"trading_state"`
+	Book         QuoteDTO `json:"quote"`
+}
+
+// QuoteDTO represents the top of the book of an instrument
+type QuoteDTO struct {
+	BidPrice    string `json:"best_bid_price"`
+	BidQuantity string `json:"best_bid_size"`
+	AskPrice    string `json:"best_ask_price"`
+	AskQuantity string `json:"best_ask_size"`
+	TradePrice  string `json:"trade_price"`
+	Timestamp   string `json:"timestamp"` // RFC 3339
+}
+
+// toTicker converts an InstrumentDTO to an exchanges.Ticker
+func (ci InstrumentDTO) toTicker() (exchanges.Ticker, error) {
+	ticker := exchanges.Ticker{}
+	quote := ci.Book
+
+	bidPrice, err := strconv.ParseFloat(quote.BidPrice, 64)
+	if err != nil {
+		return ticker, fmt.Errorf("invalid bidPrice '%s': %w", quote.BidPrice, err)
+	}
+	askPrice, err := strconv.ParseFloat(quote.AskPrice, 64)
+	if err != nil {
+		return ticker, fmt.Errorf("invalid askPrice '%s': %w", quote.AskPrice, err)
+	}
+	bidQuantity, err := strconv.ParseFloat(quote.BidQuantity, 64)
+	if err != nil {
+		return ticker, fmt.Errorf("invalid bidQuantity '%s': %w", quote.BidQuantity, err)
+	}
+	askQuantity, err := strconv.ParseFloat(quote.AskQuantity, 64)
+	if err != nil {
+		return ticker, fmt.Errorf("invalid askQuantity '%s': %w", quote.AskQuantity, err)
+	}
+	// a missing timestamp is left for the EventAt fallback
+	var eventAt time.Time
+	if quote.Timestamp != "" {
+		if eventAt, err = time.Parse(time.RFC3339Nano, quote.Timestamp); err != nil {
+			return ticker, fmt.Errorf("invalid timestamp '%s': %w", quote.Timestamp, err)
+		}
+	}
+
+	ticker.Symbol = ci.Symbol
+	ticker.InstType = ci.Type
+	ticker.Base = ci.Base
+	ticker.Quote = ci.Quote
+	ticker.BidPrice = bidPrice
+	ticker.AskPrice = askPrice
+	ticker.BidQuantity = bidQuantity
+	ticker.AskQuantity = askQuantity
+	ticker.EventAt = eventAt.UTC()
+
+	return ticker, nil
+}
@@ -0,0 +1,60 @@
+package coinbase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentDTO_ToTicker(t *testing.T) {
+	valid := QuoteDTO{BidPrice: "50000.25", BidQuantity: "1.5", AskPrice: "50000.75", AskQuantity: "2.5", Timestamp: "2021-11-01T04:00:00Z"}
+
+	tests := []struct {
+		name    string
+		book    func(q *QuoteDTO)
+		want    exchanges.Ticker
+		wantErr bool
+	}{
+		{
+			name: "valid conversion",
+			book: func(q *QuoteDTO) {},
+			want: exchanges.Ticker{
+				Symbol: "BTC-PERP", InstType: InstrumentTypePerp, Base: "BTC", Quote: "USDC",
+				BidPrice: 50000.25, BidQuantity: 1.5, AskPrice: 50000.75, AskQuantity: 2.5,
+				EventAt: time.Date(2021, 11, 1, 4, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "missing timestamp is left for the fallback",
+			book: func(q *QuoteDTO) { q.Timestamp = "" },
+			want: exchanges.Ticker{
+				Symbol: "BTC-PERP", InstType: InstrumentTypePerp, Base: "BTC", Quote: "USDC",
+				BidPrice: 50000.25, BidQuantity: 1.5, AskPrice: 50000.75, AskQuantity: 2.5,
+			},
+		},
+		{name: "invalid bid price", book: func(q *QuoteDTO) { q.BidPrice = "" }, wantErr: true},
+		{name: "invalid ask price", book: func(q *QuoteDTO) { q.AskPrice = "invalid" }, wantErr: true},
+		{name: "invalid bid quantity", book: func(q *QuoteDTO) { q.BidQuantity = "invalid" }, wantErr: true},
+		{name: "invalid ask quantity", book: func(q *QuoteDTO) { q.AskQuantity = "invalid" }, wantErr: true},
+		{name: "invalid timestamp", book: func(q *QuoteDTO) { q.Timestamp = "1635739200000" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := valid
+			tt.book(&book)
+			dto := InstrumentDTO{Symbol: "BTC-PERP", Type: InstrumentTypePerp, Base: "BTC", Quote: "USDC", TradingState: TradingStateTrading, Book: book}
+
+			got, err := dto.toTicker()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Package coinglass provides a liquidation source polling the liquidation orders of an exchange from the
+// Coinglass API, or from any aggregator serving the same endpoint. It also serves the past liquidation orders
+// of the exchange, which Binance, Bybit and OKX don't serve over REST
+package coinglass
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+const (
+	// DefaultName is the source name recorded with the liquidations
+	DefaultName = "coinglass"
+
+	// DefaultPollInterval is the interval between two requests of the latest liquidation orders
+	DefaultPollInterval = 5 * time.Second
+
+	// DefaultChannelBuffer is the default size for channels
+	DefaultChannelBuffer = 100
+
+	// APIURL is the base URL of the Coinglass API
+	APIURL = "https://open-api-v4.coinglass.com/api"
+
+	// LiquidationOrdersPath is the endpoint of the liquidation orders of an exchange,
+	// the API key is sent in the CG-API-KEY header
+	LiquidationOrdersPath = "/futures/liquidation/order"
+)
+
+// Config holds the configuration for the Coinglass client
+type Config struct {
+	Name         string        // source name recorded with the liquidations, defaults to DefaultName
+	APIUrl       string        // defaults to APIURL
+	Exchange     string        // exchange the liquidations are requested for, as named by Coinglass, e.g. Binance
+	PollInterval time.Duration // defaults to DefaultPollInterval
+	HTTPClient   *http.Client  // sends the API key header, see exchanges.NewHTTPClient
+}
+
+// Client implements exchanges.LiquidationSource and exchanges.LiquidationHistoryFetcher with the Coinglass API
+type Client struct {
+	name         string
+	httpURL      string
+	exchange     string
+	pollInterval time.Duration
+	httpClient   *http.Client
+}
+
+// NewCoinglass creates a new Coinglass client with the provided configuration
+func NewCoinglass(cfg Config) *Client {
+	if cfg.Name == "" {
+		cfg.Name = DefaultName
+	}
+	if cfg.APIUrl == "" {
+		cfg.APIUrl = APIURL
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+
+	return &Client{
+		name:         cfg.Name,
+		httpURL:      cfg.APIUrl,
+		exchange:     cfg.Exchange,
+		pollInterval: cfg.PollInterval,
+		httpClient:   cfg.HTTPClient,
+	}
+}
+
+// GetName returns the name of the source
+func (c *Client) GetName() string {
+	return c.name
+}
+
+// SubscribeLiquidations polls the liquidation orders that happened since the subscription
+func (c *Client) SubscribeLiquidations(ctx context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+	out := make(chan exchanges.Liquidation, DefaultChannelBuffer)
+	errCh := make(chan error, DefaultChannelBuffer)
+
+	go c.poll(ctx, out, errCh)
+
+	return out, errCh
+}
+
+// poll requests the liquidation orders every poll interval until ctx is canceled
+func (c *Client) poll(ctx context.Context, out chan<- exchanges.Liquidation, errCh chan<- error) {
+	defer close(out)
+	defer close(errCh)
+
+	cursor := newOrderCursor(time.Now())
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		orders, err := c.fetchLiquidationOrders(ctx, cursor.since, 0)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case errCh <- err:
+			default:
+			}
+			continue
+		}
+
+		for _, order := range cursor.next(orders) {
+			liquidation, err := order.toLiquidation(c.name)
+			if err != nil {
+				select {
+				case errCh <- fmt.Errorf("converting liquidation of %s: %w", order.Symbol, err):
+				default:
+				}
+				continue
+			}
+			select {
+			case out <- liquidation:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// FetchLiquidations retrieves the liquidation orders of the exchange within [from, to), it lets the importer
+// backfill the liquidations missed while it was down from exchanges not serving their past liquidations
+func (c *Client) FetchLiquidations(ctx context.Context, from, to time.Time) ([]exchanges.Liquidation, error) {
+	orders, err := c.fetchLiquidationOrders(ctx, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := newOrderCursor(from)
+	liquidations := make([]exchanges.Liquidation, 0, len(orders))
+	for _, order := range cursor.next(orders) {
+		if order.Time >= to.UnixMilli() {
+			break
+		}
+		liquidation, err := order.toLiquidation(c.name)
+		if err != nil {
+			return nil, fmt.Errorf("converting liquidation of %s: %w", order.Symbol, err)
+		}
+		liquidations = append(liquidations, liquidation)
+	}
+	return liquidations, nil
+}
+
+// fetchLiquidationOrders retrieves the liquidation orders of the exchange since the given time, and until the
+// given one unless 0, in milliseconds, oldest first
+func (c *Client) fetchLiquidationOrders(ctx context.Context, since, until int64) ([]LiquidationOrderDTO, error) {
+	query := url.Values{}
+	query.Set("exchange", c.exchange)
+	query.Set("start_time", strconv.FormatInt(since, 10))
+	if until > 0 {
+		query.Set("end_time", strconv.FormatInt(until, 10))
+	}
+	reqURL := c.httpURL + LiquidationOrdersPath + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for %s: %w", reqURL, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request for %s: %w", reqURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: status %s from %s", exchanges.ErrThrottled, resp.Status, reqURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+	}
+
+	var response LiquidationOrdersResponse
+	if err := exchanges.JSON.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decoding response from %s: %w", reqURL, err)
+	}
+	if response.Code != "0" {
+		return nil, fmt.Errorf("error %s from %s: %s", response.Code, reqURL, response.Msg)
+	}
+
+	slices.SortStableFunc(response.Data, func(a, b LiquidationOrderDTO) int {
+		return cmp.Compare(a.Time, b.Time)
+	})
+	return response.Data, nil
+}
+
+// orderCursor tracks the orders already delivered, successive polls overlap on the orders of the latest millisecond
+type orderCursor struct {
+	since int64               // time in milliseconds of the latest delivered order, or of the subscription
+	seen  map[string]struct{} // keys of the delivered orders of the since millisecond
+}
+
+func newOrderCursor(start time.Time) *orderCursor {
+	return &orderCursor{since: start.UnixMilli(), seen: make(map[string]struct{})}
+}
+
+// next returns the orders not delivered yet among the ones sorted by time and moves the cursor past them
+func (c *orderCursor) next(orders []LiquidationOrderDTO) []LiquidationOrderDTO {
+	var fresh []LiquidationOrderDTO
+	for _, order := range orders {
+		if order.Time < c.since {
+			continue
+		}
+		if order.Time > c.since {
+			c.since = order.Time
+			clear(c.seen)
+		}
+		key := order.key()
+		if _, ok := c.seen[key]; ok {
+			continue
+		}
+		c.seen[key] = struct{}{}
+		fresh = append(fresh, order)
+	}
+	return fresh
+}
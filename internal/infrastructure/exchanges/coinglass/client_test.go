@@ -0,0 +1,164 @@
+package coinglass
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCoinglass(t *testing.T) {
+	client := NewCoinglass(Config{Exchange: "Binance"})
+
+	assert.Equal(t, DefaultName, client.GetName())
+	assert.Equal(t, APIURL, client.httpURL)
+	assert.Equal(t, DefaultPollInterval, client.pollInterval)
+	assert.Equal(t, http.DefaultClient, client.httpClient)
+}
+
+func TestClient_SubscribeLiquidations(t *testing.T) {
+	now := time.Now().UnixMilli() + 1000
+	responses := []LiquidationOrdersResponse{
+		{Code: "0", Data: []LiquidationOrderDTO{
+			{Symbol: "ETHUSDT", BaseAsset: "ETH", Price: 2000, USDValue: 4000, Side: 2, Time: now + 10},
+			{Symbol: "BTCUSDT", BaseAsset: "BTC", Price: 50000, USDValue: 25000, Side: 1, Time: now},
+		}},
+		{Code: "50001", Msg: "rate limit"},
+		// the next poll starts at the latest order, which is returned again
+		{Code: "0", Data: []LiquidationOrderDTO{
+			{Symbol: "ETHUSDT", BaseAsset: "ETH", Price: 2000, USDValue: 4000, Side: 2, Time: now + 10},
+			{Symbol: "SOLUSDT", BaseAsset: "SOL", Price: 100, USDValue: 100, Side: 3, Time: now + 20},
+			{Symbol: "SOLUSDT", BaseAsset: "SOL", Price: 100, USDValue: 200, Side: 1, Time: now + 20},
+		}},
+	}
+
+	var (
+		mu       sync.Mutex
+		requests []*http.Request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r)
+		response := LiquidationOrdersResponse{Code: "0"}
+		if len(requests) <= len(responses) {
+			response = responses[len(requests)-1]
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := NewCoinglass(Config{
+		APIUrl:       server.URL,
+		Exchange:     "Binance",
+		PollInterval: time.Millisecond,
+		HTTPClient:   exchanges.NewHTTPClient(http.Header{"Cg-Api-Key": {"secret"}}),
+	})
+	liquidations, errs := client.SubscribeLiquidations(ctx)
+
+	var got []exchanges.Liquidation
+	for len(got) < 3 {
+		select {
+		case liquidation := <-liquidations:
+			got = append(got, liquidation)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for liquidations, got %d", len(got))
+		}
+	}
+
+	require.Len(t, got, 3)
+	assert.Equal(t, exchanges.Liquidation{
+		Symbol:     "BTCUSDT",
+		Base:       "BTC",
+		Quote:      "USD",
+		Side:       exchanges.LongLiquidated,
+		Price:      50000,
+		Quantity:   0.5,
+		TotalPrice: 25000,
+		EventAt:    time.UnixMilli(now).UTC(),
+		Source:     DefaultName,
+	}, got[0])
+	assert.Equal(t, "ETHUSDT", got[1].Symbol)
+	assert.Equal(t, exchanges.ShortLiquidated, got[1].Side)
+	assert.Equal(t, "SOLUSDT", got[2].Symbol)
+	assert.Equal(t, 2.0, got[2].Quantity)
+
+	var gotErrs []string
+	for len(gotErrs) < 2 {
+		select {
+		case err := <-errs:
+			gotErrs = append(gotErrs, err.Error())
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for errors, got %v", gotErrs)
+		}
+	}
+	assert.Contains(t, gotErrs[0], "error 50001")
+	assert.Equal(t, "converting liquidation of SOLUSDT: invalid side 3", gotErrs[1])
+
+	mu.Lock()
+	first := requests[0]
+	mu.Unlock()
+	assert.Equal(t, LiquidationOrdersPath, first.URL.Path)
+	assert.Equal(t, "Binance", first.URL.Query().Get("exchange"))
+	assert.NotEmpty(t, first.URL.Query().Get("start_time"))
+	assert.Equal(t, "secret", first.Header.Get("CG-API-KEY"))
+}
+
+func TestClient_FetchLiquidations(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(30 * time.Minute)
+
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_ = json.NewEncoder(w).Encode(LiquidationOrdersResponse{Code: "0", Data: []LiquidationOrderDTO{
+			{Symbol: "BTCUSD", BaseAsset: "BTC", Price: 50000, USDValue: 5000, Side: 2, Time: to.UnixMilli()},
+			{Symbol: "BTCUSDT", BaseAsset: "BTC", Price: 50000, USDValue: 25000, Side: 1, Time: from.UnixMilli()},
+			{Symbol: "ETHUSDT", BaseAsset: "ETH", Price: 2000, USDValue: 4000, Side: 2, Time: from.Add(time.Minute).UnixMilli()},
+		}})
+	}))
+	defer server.Close()
+
+	client := NewCoinglass(Config{APIUrl: server.URL, Exchange: "Bybit"})
+	liquidations, err := client.FetchLiquidations(context.Background(), from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bybit", query.Get("exchange"))
+	assert.Equal(t, strconv.FormatInt(from.UnixMilli(), 10), query.Get("start_time"))
+	assert.Equal(t, strconv.FormatInt(to.UnixMilli(), 10), query.Get("end_time"))
+	require.Len(t, liquidations, 2, "the order at the end of the range is left out")
+	assert.Equal(t, "BTCUSDT", liquidations[0].Symbol)
+	assert.Equal(t, "ETHUSDT", liquidations[1].Symbol)
+	assert.Equal(t, DefaultName, liquidations[1].Source)
+
+	server.Close()
+	_, err = client.FetchLiquidations(context.Background(), from, to)
+	assert.Error(t, err)
+}
+
+func TestOrderCursor_Next(t *testing.T) {
+	cursor := newOrderCursor(time.UnixMilli(100).UTC())
+	order := func(symbol string, at int64) LiquidationOrderDTO {
+		return LiquidationOrderDTO{Symbol: symbol, Price: 1, USDValue: 1, Side: 1, Time: at}
+	}
+
+	fresh := cursor.next([]LiquidationOrderDTO{order("OLD", 99), order("A", 100), order("B", 101)})
+	assert.Equal(t, []LiquidationOrderDTO{order("A", 100), order("B", 101)}, fresh)
+	assert.Equal(t, int64(101), cursor.since)
+
+	fresh = cursor.next([]LiquidationOrderDTO{order("B", 101), order("C", 101), order("D", 102)})
+	assert.Equal(t, []LiquidationOrderDTO{order("C", 101), order("D", 102)}, fresh)
+
+	assert.Empty(t, cursor.next([]LiquidationOrderDTO{order("D", 102)}))
+}
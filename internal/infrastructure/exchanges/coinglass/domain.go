@@ -0,0 +1,73 @@
+package coinglass
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+// liquidationSides maps the Coinglass side to the normalized one.
+// Coinglass reports the side of the liquidated position, so 1 means a long position was liquidated
+var liquidationSides = map[int]exchanges.LiquidationSide{
+	1: exchanges.LongLiquidated,
+	2: exchanges.ShortLiquidated,
+}
+
+// LiquidationOrdersResponse is the response of the liquidation orders endpoint
+type LiquidationOrdersResponse struct {
+	Code string                `json:"code"` // "0" on success
+	Msg  string                `json:"msg"`
+	Data []LiquidationOrderDTO `json:"data"`
+}
+
+// LiquidationOrderDTO represents a liquidation order reported by Coinglass
+type LiquidationOrderDTO struct {
+	ExchangeName string  `json:"exchange_name"`
+	Symbol       string  `json:"symbol"`     // symbol of the exchange, e.g. BTCUSDT on Binance or BTC-USDT-SWAP on OKX
+	BaseAsset    string  `json:"base_asset"` // e.g. BTC
+	Price        float64 `json:"price"`
+	USDValue     float64 `json:"usd_value"` // notional of the order in USD
+	Side         int     `json:"side"`      // 1 for a long liquidation, 2 for a short one
+	Time         int64   `json:"time"`      // in milliseconds
+}
+
+// key identifies the order among the ones returned by overlapping polls
+func (o LiquidationOrderDTO) key() string {
+	return o.Symbol + "|" + strconv.Itoa(o.Side) + "|" + strconv.FormatFloat(o.Price, 'f', -1, 64) + "|" +
+		strconv.FormatFloat(o.USDValue, 'f', -1, 64) + "|" + strconv.FormatInt(o.Time, 10)
+}
+
+// toLiquidation converts a LiquidationOrderDTO to an exchanges.Liquidation of the named source.
+// Coinglass only reports the USD notional, so the quantity is derived from it and the notional is quoted
+// in USD whatever the quote asset of the symbol
+func (o LiquidationOrderDTO) toLiquidation(source string) (exchanges.Liquidation, error) {
+	liquidation := exchanges.Liquidation{
+		Symbol:     o.Symbol,
+		Base:       o.BaseAsset,
+		Quote:      "USD",
+		Price:      o.Price,
+		TotalPrice: o.USDValue,
+		Source:     source,
+	}
+
+	if o.Symbol == "" {
+		return liquidation, fmt.Errorf("missing symbol")
+	}
+	if o.Price <= 0 {
+		return liquidation, fmt.Errorf("invalid price %v", o.Price)
+	}
+	if o.Time <= 0 {
+		return liquidation, fmt.Errorf("invalid time %d", o.Time)
+	}
+	side, ok := liquidationSides[o.Side]
+	if !ok {
+		return liquidation, fmt.Errorf("invalid side %d", o.Side)
+	}
+
+	liquidation.Side = side
+	liquidation.Quantity = o.USDValue / o.Price
+	liquidation.EventAt = time.UnixMilli(o.Time).UTC()
+	return liquidation, nil
+}
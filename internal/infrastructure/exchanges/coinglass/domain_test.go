@@ -0,0 +1,61 @@
+package coinglass
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiquidationOrderDTO_ToLiquidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		dto     LiquidationOrderDTO
+		want    exchanges.Liquidation
+		wantErr string
+	}{
+		{
+			name: "short liquidation",
+			dto:  LiquidationOrderDTO{Symbol: "BTC-USDT-SWAP", BaseAsset: "BTC", Price: 40000, USDValue: 10000, Side: 2, Time: 1635739200000},
+			want: exchanges.Liquidation{
+				Symbol:     "BTC-USDT-SWAP",
+				Base:       "BTC",
+				Quote:      "USD",
+				Side:       exchanges.ShortLiquidated,
+				Price:      40000,
+				Quantity:   0.25,
+				TotalPrice: 10000,
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
+				Source:     "aggregator",
+			},
+		},
+		{
+			name:    "missing symbol",
+			dto:     LiquidationOrderDTO{Price: 1, Side: 1, Time: 1},
+			wantErr: "missing symbol",
+		},
+		{
+			name:    "invalid price",
+			dto:     LiquidationOrderDTO{Symbol: "BTCUSDT", Side: 1, Time: 1},
+			wantErr: "invalid price 0",
+		},
+		{
+			name:    "invalid time",
+			dto:     LiquidationOrderDTO{Symbol: "BTCUSDT", Price: 1, Side: 1},
+			wantErr: "invalid time 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dto.toLiquidation("aggregator")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package exchanges
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MaxConversionErrors is the number of individual conversion errors kept in a ConversionError
+const MaxConversionErrors = 5
+
+// ConversionError reports tickers dropped because their exchange data could not be converted.
+// FetchTickers returns it together with the tickers that were converted, so callers can decide
+// whether the partial result is trustworthy
+type ConversionError struct {
+	Total  int     // number of tickers received from the exchange
+	Failed int     // number of tickers dropped
+	Errors []error // first MaxConversionErrors conversion errors
+}
+
+// NewConversionError returns a ConversionError for the given failures, or nil if there are none
+func NewConversionError(total int, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ConversionError{
+		Total:  total,
+		Failed: len(errs),
+		Errors: errs[:min(len(errs), MaxConversionErrors)],
+	}
+}
+
+// Error implements the error interface
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("failed to convert %d of %d tickers: %v", e.Failed, e.Total, errors.Join(e.Errors...))
+}
+
+// Unwrap returns the kept conversion errors
+func (e *ConversionError) Unwrap() []error {
+	return e.Errors
+}
+
+// FailureRatio returns the share of dropped tickers, from 0 to 1
+func (e *ConversionError) FailureRatio() float64 {
+	if e.Total == 0 {
+		return 0
+	}
+	return float64(e.Failed) / float64(e.Total)
+}
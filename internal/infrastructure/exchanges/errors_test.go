@@ -0,0 +1,34 @@
+package exchanges
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConversionError(t *testing.T) {
+	assert.NoError(t, NewConversionError(10, nil))
+
+	errSentinel := errors.New("invalid bidPrice")
+	errs := []error{errSentinel}
+	for i := range 9 {
+		errs = append(errs, fmt.Errorf("ticker %d", i))
+	}
+
+	err := NewConversionError(40, errs)
+	var convErr *ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, 40, convErr.Total)
+	assert.Equal(t, 10, convErr.Failed)
+	assert.Len(t, convErr.Errors, MaxConversionErrors)
+	assert.InDelta(t, 0.25, convErr.FailureRatio(), 1e-9)
+	assert.ErrorIs(t, err, errSentinel)
+	assert.Contains(t, err.Error(), "failed to convert 10 of 40 tickers")
+}
+
+func TestConversionError_FailureRatioEmpty(t *testing.T) {
+	assert.Zero(t, (&ConversionError{}).FailureRatio())
+}
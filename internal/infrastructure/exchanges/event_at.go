@@ -0,0 +1,74 @@
+package exchanges
+
+import (
+	"errors"
+	"time"
+)
+
+// EventAtSource tells where the EventAt of a ticker comes from
+type EventAtSource string
+
+const (
+	// EventAtSourceExchange is the timestamp the exchange gave for the ticker itself
+	EventAtSourceExchange EventAtSource = "exchange"
+
+	// EventAtSourceResponse is the time the exchange sent the response holding the ticker
+	EventAtSourceResponse EventAtSource = "response"
+
+	// EventAtSourceReceived is the local time the response holding the ticker was received
+	EventAtSourceReceived EventAtSource = "received"
+)
+
+// EventAtFallback tells how tickers the exchange gave no timestamp for are stamped
+type EventAtFallback string
+
+const (
+	// EventAtFallbackExchange stamps them with the response time, or the local receive time
+	// when the response carries none. It's the default
+	EventAtFallbackExchange EventAtFallback = "exchange"
+
+	// EventAtFallbackReceived stamps them with the local receive time
+	EventAtFallbackReceived EventAtFallback = "received"
+
+	// EventAtFallbackReject drops them as conversion errors
+	EventAtFallbackReject EventAtFallback = "reject"
+)
+
+// EventAtFallbacks lists the supported fallbacks
+var EventAtFallbacks = []EventAtFallback{EventAtFallbackExchange, EventAtFallbackReceived, EventAtFallbackReject}
+
+// ErrNoEventAt is returned for tickers without an exchange timestamp when the fallback rejects them
+var ErrNoEventAt = errors.New("no exchange timestamp")
+
+// Stamp fills EventAt and EventAtSource of a ticker converted without an exchange timestamp, responseAt is
+// the time the exchange sent the response (zero if unknown) and receivedAt the local time it was received.
+// Tickers stamped by the exchange only get their source recorded
+func (f EventAtFallback) Stamp(ticker *Ticker, responseAt, receivedAt time.Time) error {
+	if !ticker.EventAt.IsZero() {
+		ticker.EventAtSource = EventAtSourceExchange
+		return nil
+	}
+
+	switch f {
+	case EventAtFallbackReject:
+		return ErrNoEventAt
+	case EventAtFallbackReceived:
+		ticker.EventAt, ticker.EventAtSource = receivedAt, EventAtSourceReceived
+	default:
+		if responseAt.IsZero() {
+			ticker.EventAt, ticker.EventAtSource = receivedAt, EventAtSourceReceived
+			return nil
+		}
+		ticker.EventAt, ticker.EventAtSource = responseAt, EventAtSourceResponse
+	}
+	return nil
+}
+
+// UnixMilli converts a millisecond exchange timestamp to UTC, a missing (0) timestamp is the zero time rather than
+// the epoch
+func UnixMilli(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,47 @@
+package exchanges
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventAtFallback_Stamp(t *testing.T) {
+	exchangeAt := time.UnixMilli(1635739200000)
+	responseAt := time.UnixMilli(1635739201000)
+	receivedAt := time.UnixMilli(1635739201500)
+
+	tests := []struct {
+		name       string
+		fallback   EventAtFallback
+		eventAt    time.Time
+		responseAt time.Time
+		wantAt     time.Time
+		wantSource EventAtSource
+		wantErr    error
+	}{
+		{name: "exchange timestamp is kept", fallback: EventAtFallbackReject, eventAt: exchangeAt, responseAt: responseAt, wantAt: exchangeAt, wantSource: EventAtSourceExchange},
+		{name: "default uses the response time", responseAt: responseAt, wantAt: responseAt, wantSource: EventAtSourceResponse},
+		{name: "exchange uses the response time", fallback: EventAtFallbackExchange, responseAt: responseAt, wantAt: responseAt, wantSource: EventAtSourceResponse},
+		{name: "exchange without response time uses the receive time", fallback: EventAtFallbackExchange, wantAt: receivedAt, wantSource: EventAtSourceReceived},
+		{name: "received", fallback: EventAtFallbackReceived, responseAt: responseAt, wantAt: receivedAt, wantSource: EventAtSourceReceived},
+		{name: "reject", fallback: EventAtFallbackReject, responseAt: responseAt, wantErr: ErrNoEventAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticker := Ticker{Symbol: "BTCUSDT", EventAt: tt.eventAt}
+			err := tt.fallback.Stamp(&ticker, tt.responseAt, receivedAt)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantAt, ticker.EventAt)
+			assert.Equal(t, tt.wantSource, ticker.EventAtSource)
+		})
+	}
+}
+
+func TestUnixMilli(t *testing.T) {
+	assert.True(t, UnixMilli(0).IsZero())
+	assert.Equal(t, time.UnixMilli(1635739200000).UTC(), UnixMilli(1635739200000))
+	assert.Equal(t, time.UTC, UnixMilli(1635739200000).Location(), "exchange timestamps don't depend on the zone of the host")
+}
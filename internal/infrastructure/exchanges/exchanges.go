@@ -10,21 +10,121 @@ import (
 // Ticker represents a ticker data imported from an exchange
 type Ticker struct {
 	Symbol      string
+	InstType    string // exchange specific instrument type or category the ticker was fetched for, e.g. SWAP
+	Base        string // base asset, e.g. BTC; empty if unknown
+	Quote       string // quote asset prices are expressed in, e.g. USDT; empty if unknown
 	AskPrice    float64
 	BidPrice    float64
 	AskQuantity float64
 	BidQuantity float64
 	EventAt     time.Time
+
+	// EventAtSource tells whether EventAt is the exchange timestamp of the ticker or a fallback, see EventAtFallback
+	EventAtSource EventAtSource
+
+	// ContractValue is the USD face value of a contract of coin-margined (inverse) instruments
+	// whose quantities are contract counts, 0 for linear instruments quoted in base asset units
+	ContractValue float64
+
+	// OpenInterest is the size of the open positions in base asset units, 0 when the exchange doesn't report it
+	// with the tickers, see OpenInterestFetcher
+	OpenInterest float64
 }
 
-// Liquidation represents a liquidation data imported from an exchange
+// LiquidationSide is the normalized side of the liquidated position.
+// Exchanges report either the position side or the side of the forced order, so every
+// exchange maps its raw value explicitly instead of passing it through
+type LiquidationSide string
+
+const (
+	// LongLiquidated means a long position was force-closed with a sell order
+	LongLiquidated LiquidationSide = "LONG_LIQUIDATED"
+
+	// ShortLiquidated means a short position was force-closed with a buy order
+	ShortLiquidated LiquidationSide = "SHORT_LIQUIDATED"
+)
+
+// Liquidation represents a liquidation data imported from an exchange.
+// Whatever unit the exchange reports sizes in (base asset, contracts, USD), Quantity is in base asset units and
+// TotalPrice is Price times Quantity in the Quote asset, so liquidations of different exchanges compare as is
 type Liquidation struct {
 	Symbol     string
-	Side       string
+	Base       string // base asset, empty if unknown
+	Quote      string // quote asset of Price and TotalPrice, empty if unknown
+	Side       LiquidationSide
 	Price      float64
-	Quantity   float64
+	Quantity   float64 // in base asset units, see ContractSpec for exchanges reporting contracts
 	TotalPrice float64
 	EventAt    time.Time
+
+	// Raw is the exchange frame the liquidation was parsed from, shared by the liquidations of a frame
+	Raw []byte
+
+	// Source is the name of the LiquidationSource that reported the liquidation, empty for the exchange itself
+	Source string
+}
+
+// ContractSpec is the size of a contract of an instrument whose sizes are contract counts
+type ContractSpec struct {
+	Multiplier float64 // base asset units per contract of linear instruments, e.g. 0.01 BTC for BTC-USDT-SWAP on OKX
+	FaceValue  float64 // quote asset (USD) value per contract of coin-margined instruments, e.g. 100 USD for BTC-USD-SWAP
+}
+
+// BaseQuantity returns the base asset quantity of a number of contracts traded at a price.
+// A spec without a multiplier or a face value leaves the quantity as is
+func (c ContractSpec) BaseQuantity(contracts, price float64) float64 {
+	switch {
+	case c.FaceValue > 0 && price > 0:
+		return contracts * c.FaceValue / price
+	case c.Multiplier > 0:
+		return contracts * c.Multiplier
+	default:
+		return contracts
+	}
+}
+
+// TakerSide is the side of the order that took liquidity in a trade
+type TakerSide string
+
+const (
+	// TakerBuy means the buyer took the ask
+	TakerBuy TakerSide = "BUY"
+
+	// TakerSell means the seller hit the bid
+	TakerSell TakerSide = "SELL"
+)
+
+// Trade represents an aggregated trade imported from an exchange: the fills of a taker order at the same price
+type Trade struct {
+	Symbol   string
+	Base     string // base asset, empty if unknown
+	Quote    string // quote asset, empty if unknown
+	ID       int64  // exchange id of the aggregated trade
+	Side     TakerSide
+	Price    float64
+	Quantity float64
+	EventAt  time.Time
+
+	// ContractValue is the USD face value of a contract of coin-margined instruments, see Ticker.ContractValue
+	ContractValue float64
+}
+
+// FundingRate represents the current funding rate of a perpetual futures symbol imported from an exchange
+type FundingRate struct {
+	Symbol        string
+	Base          string  // base asset, empty if unknown
+	Quote         string  // quote asset, empty if unknown
+	Rate          float64 // rate of the current funding period as a fraction, e.g. 0.0001 for 0.01%
+	MarkPrice     float64 // 0 if the exchange doesn't report it with the rate
+	NextFundingAt time.Time
+	EventAt       time.Time
+}
+
+// OpenInterest represents the open interest of a futures symbol imported from an exchange
+type OpenInterest struct {
+	Symbol  string
+	Value   float64 // size of the open positions in base asset units
+	EventAt time.Time
 }
 
 // Exchange represents an exchange that can be queried for data
@@ -33,9 +133,59 @@ type Exchange interface {
 	// Required to create corresponding collections/tables etc
 	GetName() string
 
-	// FetchTickers fetches the latest tickers from the exchange
+	// FetchTickers fetches the latest tickers from the exchange.
+	// Tickers that can't be converted are dropped: the converted ones are returned together with
+	// a *ConversionError, so a non-nil error doesn't always mean the result is empty
 	FetchTickers(ctx context.Context) ([]Ticker, error)
 
 	// SubscribeLiquidations subscribes to liquidation events from the exchange
 	SubscribeLiquidations(ctx context.Context) (<-chan Liquidation, <-chan error)
 }
+
+// BookTickerSubscriber is implemented by exchanges able to stream top of the book updates of selected symbols.
+// It's used for high-resolution sampling, exchanges without it only support the regular ticks
+type BookTickerSubscriber interface {
+	// SubscribeBookTickers streams the best bid/ask of the given symbols on every change
+	SubscribeBookTickers(ctx context.Context, symbols []string) (<-chan Ticker, <-chan error)
+}
+
+// TradeSubscriber is implemented by exchanges able to stream the trades of selected symbols.
+// It's used for the per-tick buy/sell volumes, exchanges without it only import tickers and liquidations
+type TradeSubscriber interface {
+	// SubscribeTrades streams the aggregated trades of the given symbols
+	SubscribeTrades(ctx context.Context, symbols []string) (<-chan Trade, <-chan error)
+}
+
+// LiquidationSource is an additional provider of the liquidations of an exchange, e.g. a third-party aggregator.
+// It's used where the native liquidation stream is throttled or partial, the liquidations it reports must use
+// the symbols of the exchange and carry the name of the source
+type LiquidationSource interface {
+	// GetName returns the name of the source, recorded with its liquidations
+	GetName() string
+
+	// SubscribeLiquidations streams the liquidations reported by the source
+	SubscribeLiquidations(ctx context.Context) (<-chan Liquidation, <-chan error)
+}
+
+// LiquidationHistoryFetcher is implemented by exchanges and liquidation sources serving past liquidations over REST.
+// It's used to backfill the liquidations missed while the importer was down
+type LiquidationHistoryFetcher interface {
+	// FetchLiquidations returns the liquidations of all symbols that happened within [from, to)
+	FetchLiquidations(ctx context.Context, from, to time.Time) ([]Liquidation, error)
+}
+
+// FundingRateFetcher is implemented by exchanges serving the funding rates of their perpetual futures over REST.
+// It's used to store funding snapshots, exchanges without it only import tickers and liquidations
+type FundingRateFetcher interface {
+	// FetchFundingRates returns the current funding rate of every perpetual symbol of the imported markets.
+	// A *ConversionError is returned along with the rates converted when some of them fail conversion
+	FetchFundingRates(ctx context.Context) ([]FundingRate, error)
+}
+
+// OpenInterestFetcher is implemented by exchanges serving the open interest of their futures over REST, apart from
+// the tickers. Exchanges reporting it with the tickers fill Ticker.OpenInterest instead
+type OpenInterestFetcher interface {
+	// FetchOpenInterest returns the open interest of every futures symbol of the imported markets.
+	// A *ConversionError is returned along with the values converted when some of them fail conversion
+	FetchOpenInterest(ctx context.Context) ([]OpenInterest, error)
+}
@@ -0,0 +1,29 @@
+package exchanges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContractSpec_BaseQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      ContractSpec
+		contracts float64
+		price     float64
+		want      float64
+	}{
+		{name: "linear contracts", spec: ContractSpec{Multiplier: 0.01}, contracts: 250, price: 50000, want: 2.5},
+		{name: "coin-margined contracts", spec: ContractSpec{FaceValue: 100}, contracts: 20, price: 50000, want: 0.04},
+		{name: "usd sizes", spec: ContractSpec{FaceValue: 1}, contracts: 5000, price: 2500, want: 2},
+		{name: "base quantity", contracts: 1.5, price: 50000, want: 1.5},
+		{name: "coin-margined without a price", spec: ContractSpec{FaceValue: 100}, contracts: 20, want: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.spec.BaseQuantity(tt.contracts, tt.price), 1e-12)
+		})
+	}
+}
@@ -0,0 +1,362 @@
+// Package exchangestest is the conformance suite every exchanges.Exchange implementation must pass.
+// An adapter describes a valid tickers response and liquidation frame of its API, the suite serves them
+// from fake REST and websocket servers and checks the behavior shared by all exchanges:
+//
+//	func TestClient_Conformance(t *testing.T) {
+//		exchangestest.Run(t, exchangestest.Fixture{
+//			New: func(apiURL, wsURL string, ws exchanges.WebsocketConfig) exchanges.Exchange {
+//				return NewBinance(Config{APIUrl: apiURL, WSUrl: wsURL, Websocket: ws})
+//			},
+//			Tickers:     []TickerDTO{...},
+//			Liquidation: `{"e":"forceOrder",...}`,
+//		})
+//	}
+package exchangestest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Timeout bounds every wait of the suite, e.g. for a liquidation or for the channels to close
+const Timeout = 2 * time.Second
+
+// reconnectDelay is passed to the exchanges so the reconnect check doesn't wait for their default delay
+const reconnectDelay = 10 * time.Millisecond
+
+// Fixture describes an exchange implementation to the suite
+type Fixture struct {
+	// New creates the exchange for the fake REST API at apiURL and the fake websocket streams at wsURL,
+	// ws must be used as the websocket configuration of the liquidation stream
+	New func(apiURL, wsURL string, ws exchanges.WebsocketConfig) exchanges.Exchange
+
+	// Tickers is the body of a valid tickers response, JSON encoded and served on every REST path.
+	// Exchanges learn the symbols to subscribe liquidations for from it
+	Tickers any
+
+	// Responses are the bodies of the other REST paths the exchange needs, keyed by a path suffix, JSON encoded
+	// and served instead of Tickers, e.g. the contract sizes converting the liquidation sizes
+	Responses map[string]any
+
+	// Liquidation is a websocket frame carrying a single valid liquidation
+	Liquidation string
+
+	// NoLiquidationStream marks exchanges publishing no liquidation feed, Liquidation is left empty and
+	// the subscription must only deliver nothing until it is canceled
+	NoLiquidationStream bool
+}
+
+// Run runs the conformance suite against the exchange described by f
+func Run(t *testing.T, f Fixture) {
+	t.Helper()
+	require.NotNil(t, f.New, "Fixture.New is required")
+	require.NotNil(t, f.Tickers, "Fixture.Tickers is required")
+	require.True(t, f.NoLiquidationStream || f.Liquidation != "", "Fixture.Liquidation is required")
+
+	t.Run("name", func(t *testing.T) {
+		ex := f.New("http://api.invalid", "ws://ws.invalid", exchanges.WebsocketConfig{})
+		assert.NotEmpty(t, ex.GetName(), "the name keys the collections of the exchange")
+	})
+
+	t.Run("fetch tickers", func(t *testing.T) {
+		ex, _ := f.start(t, f.tickersAPI(), nil)
+		tickers, err := ex.FetchTickers(context.Background())
+		require.NoError(t, err)
+		require.NotEmpty(t, tickers)
+		for _, ticker := range tickers {
+			assertTicker(t, ticker)
+		}
+	})
+
+	t.Run("fetch tickers with a canceled context", func(t *testing.T) {
+		ex, _ := f.start(t, f.tickersAPI(), nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		tickers, err := ex.FetchTickers(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, tickers)
+	})
+
+	t.Run("fetch tickers on a server error", func(t *testing.T) {
+		ex, _ := f.start(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"error":"internal error"}`, http.StatusInternalServerError)
+		}), nil)
+		tickers, err := ex.FetchTickers(context.Background())
+		assert.Error(t, err)
+		assert.Empty(t, tickers)
+	})
+
+	t.Run("fetch tickers of a malformed response", func(t *testing.T) {
+		ex, _ := f.start(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"truncated`))
+		}), nil)
+		tickers, err := ex.FetchTickers(context.Background())
+		assert.Error(t, err)
+		assert.Empty(t, tickers)
+	})
+
+	if f.NoLiquidationStream {
+		t.Run("no liquidation stream", func(t *testing.T) {
+			ex, stream := f.start(t, f.tickersAPI(), nil)
+			sub := subscribe(t, ex)
+			select {
+			case liquidation := <-sub.liquidations:
+				assert.Fail(t, "unexpected liquidation", "%+v", liquidation)
+			case <-time.After(100 * time.Millisecond):
+			}
+			sub.cancel()
+			sub.waitClosed(t)
+			assert.Empty(t, sub.errs(), "shutting down is not an error")
+			assert.Zero(t, stream.connections.Load(), "no websocket is dialed")
+		})
+		return
+	}
+
+	t.Run("liquidations", func(t *testing.T) {
+		ex, _ := f.start(t, f.tickersAPI(), func(conn *websocket.Conn, _ int) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(f.Liquidation))
+		})
+		sub := subscribe(t, ex)
+		liquidation := sub.next(t)
+		assertLiquidation(t, liquidation)
+		assert.JSONEq(t, f.Liquidation, string(liquidation.Raw), "the frame is kept with the liquidation")
+	})
+
+	t.Run("malformed liquidation frame", func(t *testing.T) {
+		ex, _ := f.start(t, f.tickersAPI(), func(conn *websocket.Conn, _ int) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(`not json`))
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(f.Liquidation))
+		})
+		sub := subscribe(t, ex)
+		assertLiquidation(t, sub.next(t))
+		assert.NotEmpty(t, sub.errs(), "the malformed frame is reported")
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		ex, stream := f.start(t, f.tickersAPI(), func(conn *websocket.Conn, n int) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(f.Liquidation))
+			if n == 1 {
+				_ = conn.Close() // drop the first connection
+			}
+		})
+		sub := subscribe(t, ex)
+		assertLiquidation(t, sub.next(t))
+		assertLiquidation(t, sub.next(t))
+		assert.GreaterOrEqual(t, stream.connections.Load(), int32(2), "the exchange reconnects after the connection dropped")
+	})
+
+	t.Run("cancel closes the channels", func(t *testing.T) {
+		ex, _ := f.start(t, f.tickersAPI(), func(conn *websocket.Conn, _ int) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(f.Liquidation))
+		})
+		sub := subscribe(t, ex)
+		sub.next(t)
+
+		// the connection stays open and idle, so the exchange must not wait for the next frame
+		sub.cancel()
+		sub.waitClosed(t)
+		assert.Empty(t, sub.errs(), "shutting down is not an error")
+	})
+
+	t.Run("subscribe with a canceled context", func(t *testing.T) {
+		ex, _ := f.start(t, f.tickersAPI(), func(conn *websocket.Conn, _ int) {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(f.Liquidation))
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sub := newSubscription(ex, ctx, cancel)
+		sub.waitClosed(t)
+		assert.Empty(t, sub.liquidations, "nothing is delivered after the context was canceled")
+	})
+}
+
+// tickersAPI serves the fixture responses on their paths and the fixture tickers on every other path
+func (f Fixture) tickersAPI() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for suffix, body := range f.Responses {
+			if strings.HasSuffix(r.URL.Path, suffix) {
+				_ = json.NewEncoder(w).Encode(body)
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(f.Tickers)
+	})
+}
+
+// start serves api and the websocket stream and returns the exchange using them. The stream is called with every
+// accepted connection and its 1-based number; the connection stays open until the client or stream closes it
+func (f Fixture) start(t *testing.T, api http.Handler, stream func(conn *websocket.Conn, n int)) (exchanges.Exchange, *fakeStream) {
+	t.Helper()
+	apiServer := httptest.NewServer(api)
+	t.Cleanup(apiServer.Close)
+
+	fs := &fakeStream{handle: stream}
+	wsServer := httptest.NewServer(fs)
+	t.Cleanup(wsServer.Close)
+
+	wsURL := "ws" + strings.TrimPrefix(wsServer.URL, "http") + "/ws/stream"
+	return f.New(apiServer.URL, wsURL, exchanges.WebsocketConfig{ReconnectDelay: reconnectDelay}), fs
+}
+
+// fakeStream is a websocket server accepting any path and subscription message
+type fakeStream struct {
+	handle      func(conn *websocket.Conn, n int)
+	connections atomic.Int32
+}
+
+func (fs *fakeStream) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	n := int(fs.connections.Add(1))
+
+	// drain the subscription messages, the read fails once either side closed the connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if fs.handle != nil {
+		// give the exchange the time to send its subscription before the frames
+		time.Sleep(20 * time.Millisecond)
+		fs.handle(conn, n)
+	}
+	select {
+	case <-closed:
+	case <-r.Context().Done():
+	}
+}
+
+// subscription collects what SubscribeLiquidations delivers
+type subscription struct {
+	cancel       context.CancelFunc
+	liquidations chan exchanges.Liquidation
+	done         chan struct{} // closed once both channels of the exchange are closed
+
+	mu     sync.Mutex
+	errors []error
+}
+
+// subscribe fetches the tickers, as exchanges learn the symbols to subscribe for from them, and subscribes
+// to the liquidations until the test ends
+func subscribe(t *testing.T, ex exchanges.Exchange) *subscription {
+	t.Helper()
+	_, err := ex.FetchTickers(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := newSubscription(ex, ctx, cancel)
+	t.Cleanup(func() {
+		cancel()
+		<-sub.done
+	})
+	return sub
+}
+
+func newSubscription(ex exchanges.Exchange, ctx context.Context, cancel context.CancelFunc) *subscription {
+	sub := &subscription{
+		cancel:       cancel,
+		liquidations: make(chan exchanges.Liquidation, 100),
+		done:         make(chan struct{}),
+	}
+	out, errs := ex.SubscribeLiquidations(ctx)
+	go func() {
+		defer close(sub.done)
+		for out != nil || errs != nil {
+			select {
+			case liquidation, ok := <-out:
+				if !ok {
+					out = nil
+					continue
+				}
+				sub.liquidations <- liquidation
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				sub.mu.Lock()
+				sub.errors = append(sub.errors, err)
+				sub.mu.Unlock()
+			}
+		}
+	}()
+	return sub
+}
+
+// next returns the next liquidation, failing the test when none arrives within Timeout
+func (s *subscription) next(t *testing.T) exchanges.Liquidation {
+	t.Helper()
+	select {
+	case liquidation := <-s.liquidations:
+		return liquidation
+	case <-time.After(Timeout):
+		require.FailNow(t, "no liquidation received", "errors: %v", errors.Join(s.errs()...))
+		return exchanges.Liquidation{}
+	}
+}
+
+// waitClosed fails the test when the channels of the exchange are not closed within Timeout
+func (s *subscription) waitClosed(t *testing.T) {
+	t.Helper()
+	select {
+	case <-s.done:
+	case <-time.After(Timeout):
+		require.FailNow(t, "the liquidation channels were not closed after the context was canceled")
+	}
+}
+
+// errs returns the errors received so far, the errors reported for a canceled context excepted
+func (s *subscription) errs() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for _, err := range s.errors {
+		if !errors.Is(err, context.Canceled) {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// assertTicker checks the fields every exchange fills in a ticker
+func assertTicker(t *testing.T, ticker exchanges.Ticker) {
+	t.Helper()
+	assert.NotEmpty(t, ticker.Symbol, "symbol")
+	assert.Positive(t, ticker.AskPrice, "%s ask price", ticker.Symbol)
+	assert.Positive(t, ticker.BidPrice, "%s bid price", ticker.Symbol)
+	assert.LessOrEqual(t, ticker.BidPrice, ticker.AskPrice, "%s bid is not above the ask", ticker.Symbol)
+	assert.False(t, ticker.EventAt.IsZero(), "%s event time", ticker.Symbol)
+}
+
+// assertLiquidation checks the fields every exchange fills in a liquidation
+func assertLiquidation(t *testing.T, liquidation exchanges.Liquidation) {
+	t.Helper()
+	assert.NotEmpty(t, liquidation.Symbol, "symbol")
+	assert.Contains(t, []exchanges.LiquidationSide{exchanges.LongLiquidated, exchanges.ShortLiquidated}, liquidation.Side)
+	assert.Positive(t, liquidation.Price, "price")
+	assert.Positive(t, liquidation.Quantity, "quantity")
+	assert.False(t, liquidation.EventAt.IsZero(), "event time")
+	assert.True(t, json.Valid(liquidation.Raw), "the raw frame is kept")
+}
@@ -0,0 +1,27 @@
+package exchanges
+
+import "net/http"
+
+// NewHTTPClient returns the client of the REST requests of an exchange, sending the headers with every request,
+// e.g. the key of an API gateway or a user agent. Without headers it's the default client
+func NewHTTPClient(headers http.Header) *http.Client {
+	if len(headers) == 0 {
+		return http.DefaultClient
+	}
+	return &http.Client{Transport: &headerTransport{base: http.DefaultTransport, headers: headers.Clone()}}
+}
+
+// headerTransport sets the headers on every request before passing it to the base transport
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+// RoundTrip implements http.RoundTripper, the request of the caller is left untouched
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,40 @@
+package exchanges
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	assert.Same(t, http.DefaultClient, NewHTTPClient(nil))
+
+	received := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header
+	}))
+	defer server.Close()
+
+	headers := http.Header{}
+	headers.Set("User-Agent", "importer")
+	headers.Set("X-Api-Key", "secret")
+	client := NewHTTPClient(headers)
+	headers.Set("X-Api-Key", "changed")
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, http.NoBody)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	got := <-received
+	assert.Equal(t, "importer", got.Get("User-Agent"))
+	assert.Equal(t, "secret", got.Get("X-Api-Key"), "the headers are copied when the client is created")
+	assert.Equal(t, "application/json", got.Get("Accept"))
+	assert.Empty(t, req.Header.Get("X-Api-Key"), "the request of the caller is left untouched")
+}
@@ -0,0 +1,14 @@
+package exchanges
+
+import jsoniter "github.com/json-iterator/go"
+
+// JSON decodes the exchange payloads on the hot path: the websocket frames and the tickers fetched every second.
+// It behaves like encoding/json, except that keys are matched case-sensitively, as the payloads carry keys differing
+// only in case (Binance "e" and "E"). It decodes about twice as fast, while allocating every string separately;
+// see BenchmarkDecodeLiquidation and BenchmarkDecodeTickers of the binance package
+var JSON = jsoniter.Config{
+	EscapeHTML:             true,
+	SortMapKeys:            true,
+	ValidateJsonRawMessage: true,
+	CaseSensitive:          true,
+}.Froze()
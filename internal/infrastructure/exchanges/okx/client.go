@@ -3,10 +3,13 @@ package okx
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
@@ -32,8 +35,44 @@ const (
 	// FuturesWSUrl is the base URL for the OKX Futures Websocket API
 	FuturesWSUrl = "wss://ws.okx.com:8443/ws/v5/public"
 
-	// FetchTickersData is the endpoint to fetch tickers data
-	FetchTickersData = "/market/tickers?instType=SWAP"
+	// FetchTickersPath is the endpoint to fetch tickers data, the instType query parameter is required
+	FetchTickersPath = "/market/tickers"
+
+	// FetchTickersLimit is the number of FetchTickersPath requests an IP may send per FetchTickersWindow, the
+	// public endpoints have the same limit. Every REST request is charged to a budget of FetchTickersLimit, OKX
+	// doesn't report the usage in its responses so it's only accounted locally
+	FetchTickersLimit = 20
+
+	// FetchTickersWindow is the window of FetchTickersLimit
+	FetchTickersWindow = 2 * time.Second
+
+	// FetchFundingRatePath is the endpoint to fetch the funding rates of the swaps, the instId query parameter
+	// is required
+	FetchFundingRatePath = "/public/funding-rate"
+
+	// FundingRateAllSwaps is the instId of FetchFundingRatePath returning the funding rates of every swap at once
+	FundingRateAllSwaps = "ANY"
+
+	// FetchOpenInterestPath is the endpoint to fetch the open interest of all instruments of the instType query parameter
+	FetchOpenInterestPath = "/public/open-interest"
+
+	// FetchInstrumentsPath is the endpoint to fetch the contract specs of all instruments of the instType query parameter
+	FetchInstrumentsPath = "/public/instruments"
+
+	// DefaultContractsUpdateInterval is the minimum interval between the contract spec fetches of unknown instruments
+	DefaultContractsUpdateInterval = time.Minute
+)
+
+// Instrument types supported by the tickers endpoint and the liquidation-orders channel
+const (
+	// InstTypeSwap is the perpetual swap instrument type
+	InstTypeSwap = "SWAP"
+
+	// InstTypeFutures is the expiring (quarterly etc.) futures instrument type
+	InstTypeFutures = "FUTURES"
+
+	// InstTypeOption is the option instrument type
+	InstTypeOption = "OPTION"
 )
 
 // Config holds the configuration for the OKX client
@@ -41,7 +80,12 @@ type Config struct {
 	Name       string
 	APIUrl     string
 	WSUrl      string
+	InstTypes  []string // instrument types to import, defaults to SWAP only
 	HTTPClient *http.Client
+	Websocket  exchanges.WebsocketConfig // dialer of the liquidation stream
+
+	// EventAtFallback stamps the tickers without their own timestamp, defaults to the exchange response time
+	EventAtFallback exchanges.EventAtFallback
 }
 
 // Client implements an OKX exchange client
@@ -50,10 +94,23 @@ type Client struct {
 	httpURL    string
 	wsURL      string
 	httpClient *http.Client
+	instTypes  []string
+	wsConfig   exchanges.WebsocketConfig
+	budget     *exchanges.APIBudget
+	eventAt    exchanges.EventAtFallback
 
+	// symbol universes are kept per instrument type
 	tickersInfo struct {
-		availableTickers []string
-		updatedAt        time.Time
+		mu               sync.Mutex
+		availableTickers map[string][]string
+		updatedAt        map[string]time.Time
+	}
+
+	// contract specs of the swaps and futures keyed by instrument ID, converting the liquidation sizes to base units
+	contracts struct {
+		mu        sync.Mutex
+		specs     map[string]exchanges.ContractSpec
+		updatedAt time.Time
 	}
 }
 
@@ -68,68 +125,312 @@ func NewOKX(cfg Config) *Client {
 	if cfg.APIUrl == "" {
 		cfg.APIUrl = FuturesAPIURL
 	}
+	if len(cfg.InstTypes) == 0 {
+		cfg.InstTypes = []string{InstTypeSwap}
+	}
 
-	return &Client{
+	client := &Client{
 		name:       cfg.Name,
 		httpURL:    cfg.APIUrl,
 		wsURL:      cfg.WSUrl,
 		httpClient: cfg.HTTPClient,
+		instTypes:  cfg.InstTypes,
+		wsConfig:   cfg.Websocket,
+		eventAt:    cfg.EventAtFallback,
+		budget:     exchanges.NewAPIBudget(FetchTickersLimit, FetchTickersWindow),
 	}
+	client.tickersInfo.availableTickers = make(map[string][]string)
+	client.tickersInfo.updatedAt = make(map[string]time.Time)
+	client.contracts.specs = make(map[string]exchanges.ContractSpec)
+	return client
+}
+
+// APIUsage returns the REST requests sent within the current window
+func (oc *Client) APIUsage() exchanges.APIUsage {
+	return oc.budget.Usage()
 }
 
 //------------------------------------------------------------------------------
 // Fetch Tickers API Methods
 //------------------------------------------------------------------------------
 
-// FetchTickers retrieves current ticker information for all trading pairs
+// FetchTickers retrieves current ticker information for all trading pairs of the configured instrument types
 func (oc *Client) FetchTickers(ctx context.Context) ([]exchanges.Ticker, error) {
-	url := oc.httpURL + FetchTickersData
+	tickers := make([]exchanges.Ticker, 0)
+	var convErrs []error
+	for _, instType := range oc.instTypes {
+		instTickers, instConvErrs, err := oc.fetchInstTypeTickers(ctx, instType)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s tickers: %w", instType, err)
+		}
+		tickers = append(tickers, instTickers...)
+		convErrs = append(convErrs, instConvErrs...)
+	}
+	return tickers, exchanges.NewConversionError(len(tickers)+len(convErrs), convErrs)
+}
+
+// fetchInstTypeTickers retrieves tickers of a single instrument type along with the conversion errors
+func (oc *Client) fetchInstTypeTickers(ctx context.Context, instType string) ([]exchanges.Ticker, []error, error) {
+	url := oc.httpURL + FetchTickersPath + "?instType=" + instType
+
+	if err := oc.budget.Acquire(1); err != nil {
+		return nil, nil, err
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
-		return nil, fmt.Errorf("creating request for %s: %w", url, err)
+		return nil, nil, fmt.Errorf("creating request for %s: %w", url, err)
 	}
 
 	resp, err := oc.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("executing request for %s: %w", url, err)
+		return nil, nil, fmt.Errorf("executing request for %s: %w", url, err)
 	}
 	defer resp.Body.Close()
 
+	if oc.budget.BlockRetryAfter(resp) {
+		return nil, nil, fmt.Errorf("%w: status %s from %s", exchanges.ErrThrottled, resp.Status, url)
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+		return nil, nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
 	}
+	receivedAt := time.Now().UTC()
 
 	var response TickerResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("decoding response from %s: %w", url, err)
+	if err := exchanges.JSON.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, nil, fmt.Errorf("decoding response from %s: %w", url, err)
 	}
 
-	if len(oc.tickersInfo.availableTickers) == 0 || time.Since(oc.tickersInfo.updatedAt) > DefaultTickersUpdateInterval {
+	if oc.shouldUpdateTickers(instType) {
 		var availableTickers []string
 		for _, ticker := range response.Data {
 			availableTickers = append(availableTickers, ticker.InstID)
 		}
-		oc.setAvailableTickers(availableTickers)
+		oc.setAvailableTickers(instType, availableTickers)
 	}
 
-	return convertTickers(response.Data), nil
+	// the body carries no server time, the Date header is precise to the second
+	responseAt, _ := http.ParseTime(resp.Header.Get("Date"))
+	tickers, convErrs := convertTickers(response.Data, instType, oc.eventAt, responseAt, receivedAt)
+	return tickers, convErrs, nil
 }
 
-// convertTickers converts OKX-specific ticker DTOs to normalized tickers
-func convertTickers(okxTickers []TickerDTO) []exchanges.Ticker {
+// convertTickers converts OKX-specific ticker DTOs to normalized tickers, skipping and reporting invalid ones
+func convertTickers(okxTickers []TickerDTO, instType string, fallback exchanges.EventAtFallback, responseAt, receivedAt time.Time) ([]exchanges.Ticker, []error) {
 	tickers := make([]exchanges.Ticker, 0, len(okxTickers))
+	var errs []error
 
 	for _, ot := range okxTickers {
 		ticker, err := ot.toTicker()
 		if err != nil {
-			log.Printf("Warning: failed to convert ticker: %v", err)
+			errs = append(errs, fmt.Errorf("%s: %w", ot.InstID, err))
+			continue
+		}
+		if err := fallback.Stamp(&ticker, responseAt, receivedAt); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", ot.InstID, err))
 			continue
 		}
+		ticker.InstType = instType
 		tickers = append(tickers, ticker)
 	}
 
-	return tickers
+	return tickers, errs
+}
+
+//------------------------------------------------------------------------------
+// Fetch Funding Rates API Methods
+//------------------------------------------------------------------------------
+
+// FetchFundingRates retrieves the current funding rate of every perpetual swap in a single request charged to
+// the budget. The rates are those of the swaps of the latest tickers fetch, a rate that can't be converted is
+// reported as a conversion error of its instrument so the rates of the others are kept
+func (oc *Client) FetchFundingRates(ctx context.Context) ([]exchanges.FundingRate, error) {
+	if !slices.Contains(oc.instTypes, InstTypeSwap) {
+		return nil, nil
+	}
+	if oc.shouldUpdateTickers(InstTypeSwap) {
+		if _, _, err := oc.fetchInstTypeTickers(ctx, InstTypeSwap); err != nil {
+			return nil, fmt.Errorf("fetching swaps: %w", err)
+		}
+	}
+	oc.tickersInfo.mu.Lock()
+	swaps := make(map[string]bool, len(oc.tickersInfo.availableTickers[InstTypeSwap]))
+	for _, instID := range oc.tickersInfo.availableTickers[InstTypeSwap] {
+		swaps[instID] = true
+	}
+	oc.tickersInfo.mu.Unlock()
+
+	url := oc.httpURL + FetchFundingRatePath + "?instId=" + FundingRateAllSwaps
+	var response FundingRateResponse
+	if err := oc.get(ctx, url, &response); err != nil {
+		return nil, err
+	}
+	if response.Code != "0" {
+		return nil, fmt.Errorf("error code %s in the response from %s: %s", response.Code, url, response.Msg)
+	}
+
+	rates := make([]exchanges.FundingRate, 0, len(swaps))
+	var errs []error
+	total := 0
+	for _, dto := range response.Data {
+		if !swaps[dto.InstID] {
+			continue
+		}
+		total++
+		rate, err := dto.toFundingRate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", dto.InstID, err))
+			continue
+		}
+		rates = append(rates, rate)
+	}
+	slices.SortFunc(rates, func(a, b exchanges.FundingRate) int { return strings.Compare(a.Symbol, b.Symbol) })
+	return rates, exchanges.NewConversionError(total, errs)
+}
+
+//------------------------------------------------------------------------------
+// Fetch Open Interest API Methods
+//------------------------------------------------------------------------------
+
+// FetchOpenInterest retrieves the open interest of the swaps and futures of the configured instrument types,
+// one request per instrument type
+func (oc *Client) FetchOpenInterest(ctx context.Context) ([]exchanges.OpenInterest, error) {
+	var openInterest []exchanges.OpenInterest
+	var convErrs []error
+	for _, instType := range oc.instTypes {
+		if instType != InstTypeSwap && instType != InstTypeFutures {
+			continue
+		}
+		dtos, err := oc.fetchInstTypeOpenInterest(ctx, instType)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s open interest: %w", instType, err)
+		}
+		for _, dto := range dtos {
+			oi, err := dto.toOpenInterest()
+			if err != nil {
+				convErrs = append(convErrs, fmt.Errorf("%s: %w", dto.InstID, err))
+				continue
+			}
+			openInterest = append(openInterest, oi)
+		}
+	}
+	return openInterest, exchanges.NewConversionError(len(openInterest)+len(convErrs), convErrs)
+}
+
+// fetchInstTypeOpenInterest retrieves the open interest of all instruments of a single instrument type
+func (oc *Client) fetchInstTypeOpenInterest(ctx context.Context, instType string) ([]OpenInterestDTO, error) {
+	url := oc.httpURL + FetchOpenInterestPath + "?instType=" + instType
+
+	var response OpenInterestResponse
+	if err := oc.get(ctx, url, &response); err != nil {
+		return nil, err
+	}
+	if response.Code != "0" {
+		return nil, fmt.Errorf("error code %s in the response from %s: %s", response.Code, url, response.Msg)
+	}
+	return response.Data, nil
+}
+
+//------------------------------------------------------------------------------
+// Fetch Instruments API Methods
+//------------------------------------------------------------------------------
+
+// contractSpec returns the contract spec of an instrument. The specs of the swaps and futures are fetched on
+// a miss, at most once per DefaultContractsUpdateInterval; coin-margined instruments missing from them fall back
+// to the face value of their instrument ID
+func (oc *Client) contractSpec(ctx context.Context, instID string) (exchanges.ContractSpec, error) {
+	oc.contracts.mu.Lock()
+	defer oc.contracts.mu.Unlock()
+	if spec, ok := oc.contracts.specs[instID]; ok {
+		return spec, nil
+	}
+
+	var fetchErr error
+	if time.Since(oc.contracts.updatedAt) > DefaultContractsUpdateInterval {
+		oc.contracts.updatedAt = time.Now()
+		fetchErr = oc.fetchContractSpecs(ctx)
+		if errors.Is(fetchErr, exchanges.ErrThrottled) {
+			oc.contracts.updatedAt = time.Time{} // not sent, fetched again on the next miss
+		}
+		if spec, ok := oc.contracts.specs[instID]; ok {
+			return spec, nil
+		}
+	}
+	if _, quote, faceValue := splitInstID(instID); quote == "USD" {
+		return exchanges.ContractSpec{FaceValue: faceValue}, nil
+	}
+	if fetchErr != nil {
+		return exchanges.ContractSpec{}, fmt.Errorf("unknown contract size of %s: %w", instID, fetchErr)
+	}
+	return exchanges.ContractSpec{}, fmt.Errorf("unknown contract size of %s", instID)
+}
+
+// fetchContractSpecs retrieves the contract specs of the swaps and futures of the configured instrument types;
+// must be called with contracts.mu held
+func (oc *Client) fetchContractSpecs(ctx context.Context) error {
+	var errs []error
+	for _, instType := range oc.instTypes {
+		if instType != InstTypeSwap && instType != InstTypeFutures {
+			continue
+		}
+		dtos, err := oc.fetchInstTypeInstruments(ctx, instType)
+		if err != nil {
+			return fmt.Errorf("fetching %s instruments: %w", instType, err)
+		}
+		for _, dto := range dtos {
+			spec, err := dto.toContractSpec()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", dto.InstID, err))
+				continue
+			}
+			oc.contracts.specs[dto.InstID] = spec
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fetchInstTypeInstruments retrieves the instruments of a single instrument type
+func (oc *Client) fetchInstTypeInstruments(ctx context.Context, instType string) ([]InstrumentDTO, error) {
+	url := oc.httpURL + FetchInstrumentsPath + "?instType=" + instType
+
+	var response InstrumentResponse
+	if err := oc.get(ctx, url, &response); err != nil {
+		return nil, err
+	}
+	if response.Code != "0" {
+		return nil, fmt.Errorf("error code %s in the response from %s: %s", response.Code, url, response.Msg)
+	}
+	return response.Data, nil
+}
+
+// get sends a GET request charged to the budget, every REST endpoint shares the FetchTickersLimit of the IP,
+// and decodes the JSON response into v
+func (oc *Client) get(ctx context.Context, url string, v any) error {
+	if err := oc.budget.Acquire(1); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating request for %s: %w", url, err)
+	}
+	resp, err := oc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request for %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if oc.budget.BlockRetryAfter(resp) {
+		return fmt.Errorf("%w: status %s from %s", exchanges.ErrThrottled, resp.Status, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, resp.Status)
+	}
+	if err := exchanges.JSON.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", url, err)
+	}
+	return nil
 }
 
 //------------------------------------------------------------------------------
@@ -160,37 +461,48 @@ func (oc *Client) handleLiquidationSubscription(ctx context.Context, out chan<-
 			}
 		}
 
+		delay := oc.wsConfig.ReconnectDelayOr(DefaultReconnectDelay)
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			log.Printf("Reconnecting in %s...", DefaultReconnectDelay)
-			time.Sleep(DefaultReconnectDelay)
+			log.Printf("Reconnecting in %s...", delay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
 		}
 	}
 }
 
 // connectAndHandle establishes and manages a single websocket connection
 func (oc *Client) connectAndHandle(ctx context.Context, out chan<- exchanges.Liquidation, errCh chan<- error) error {
-	conn, _, err := websocket.DefaultDialer.Dial(oc.wsURL, nil)
+	conn, err := oc.wsConfig.Dial(ctx, oc.wsURL)
 	if err != nil {
 		return fmt.Errorf("websocket dial: %w", err)
 	}
 	defer conn.Close()
 
+	// unblock ReadMessage on shutdown
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
 	availableTickers := oc.getAvailableTickers()
 	if len(availableTickers) == 0 {
 		return nil
 	}
 
+	args := make([]any, 0, len(oc.instTypes))
+	for _, instType := range oc.instTypes {
+		args = append(args, map[string]any{
+			"channel":  "liquidation-orders",
+			"instType": instType,
+		})
+	}
 	subscribeMsg := map[string]any{
-		"op": "subscribe",
-		"args": []any{
-			map[string]any{
-				"channel":  "liquidation-orders",
-				"instType": "SWAP",
-			},
-		},
+		"op":   "subscribe",
+		"args": args,
 	}
 	if err := conn.WriteJSON(subscribeMsg); err != nil {
 		return fmt.Errorf("subscribing to liquidation channel: %w", err)
@@ -212,6 +524,9 @@ func (oc *Client) readMessages(ctx context.Context, conn *websocket.Conn, out ch
 
 			_, msg, err := conn.ReadMessage()
 			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
 				return fmt.Errorf("reading message: %w", err)
 			}
 
@@ -225,7 +540,7 @@ func (oc *Client) readMessages(ctx context.Context, conn *websocket.Conn, out ch
 // processMessage handles the deserialization and conversion of websocket messages
 func (oc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exchanges.Liquidation, errCh chan<- error) error {
 	var event LiquidationEvent
-	if err := json.Unmarshal(msg, &event); err != nil {
+	if err := exchanges.JSON.Unmarshal(msg, &event); err != nil {
 		select {
 		case errCh <- err:
 		default:
@@ -243,7 +558,16 @@ func (oc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exc
 	}
 
 	for _, data := range event.Data {
-		liquidation, err := data.toLiquidation()
+		spec, err := oc.contractSpec(ctx, data.InstID)
+		if err != nil {
+			select {
+			case errCh <- err:
+			default:
+				log.Printf("converting liquidation error: %v", err)
+			}
+			continue
+		}
+		liquidation, err := data.toLiquidation(spec)
 		if err != nil {
 			select {
 			case errCh <- err:
@@ -252,6 +576,7 @@ func (oc *Client) processMessage(ctx context.Context, msg []byte, out chan<- exc
 			}
 			continue
 		}
+		liquidation.Raw = msg
 
 		select {
 		case out <- liquidation:
@@ -272,13 +597,28 @@ func (oc *Client) GetName() string {
 	return oc.name
 }
 
-// setAvailableTickers updates the available tickers with proper locking
-func (oc *Client) setAvailableTickers(tickers []string) {
-	oc.tickersInfo.availableTickers = tickers
-	oc.tickersInfo.updatedAt = time.Now()
+// shouldUpdateTickers reports whether the symbol universe of the instrument type is missing or outdated
+func (oc *Client) shouldUpdateTickers(instType string) bool {
+	oc.tickersInfo.mu.Lock()
+	defer oc.tickersInfo.mu.Unlock()
+	return len(oc.tickersInfo.availableTickers[instType]) == 0 || time.Since(oc.tickersInfo.updatedAt[instType]) > DefaultTickersUpdateInterval
 }
 
-// getAvailableTickers safely retrieves the available tickers
+// setAvailableTickers updates the available tickers of an instrument type with proper locking
+func (oc *Client) setAvailableTickers(instType string, tickers []string) {
+	oc.tickersInfo.mu.Lock()
+	defer oc.tickersInfo.mu.Unlock()
+	oc.tickersInfo.availableTickers[instType] = tickers
+	oc.tickersInfo.updatedAt[instType] = time.Now()
+}
+
+// getAvailableTickers safely retrieves the available tickers of all instrument types
 func (oc *Client) getAvailableTickers() []string {
-	return append([]string{}, oc.tickersInfo.availableTickers...)
+	oc.tickersInfo.mu.Lock()
+	defer oc.tickersInfo.mu.Unlock()
+	var tickers []string
+	for _, instType := range oc.instTypes {
+		tickers = append(tickers, oc.tickersInfo.availableTickers[instType]...)
+	}
+	return tickers
 }
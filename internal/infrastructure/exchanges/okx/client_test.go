@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/exchangestest"
 	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,6 +54,7 @@ func TestClient_FetchTickers(t *testing.T) {
 		statusCode    int
 		expectError   bool
 		wantTickers   []exchanges.Ticker
+		wantFailed    int // tickers dropped because of conversion errors
 		contextCancel bool
 	}{
 		{
@@ -74,12 +77,16 @@ func TestClient_FetchTickers(t *testing.T) {
 			expectError: false,
 			wantTickers: []exchanges.Ticker{
 				{
-					Symbol:      "BTC-USDT-SWAP",
-					BidPrice:    50000.25,
-					BidQuantity: 1.5,
-					AskPrice:    50000.75,
-					AskQuantity: 2.5,
-					EventAt:     time.Unix(0, 1635739200000*int64(time.Millisecond)),
+					Symbol:        "BTC-USDT-SWAP",
+					Base:          "BTC",
+					Quote:         "USDT",
+					InstType:      InstTypeSwap,
+					BidPrice:      50000.25,
+					BidQuantity:   1.5,
+					AskPrice:      50000.75,
+					AskQuantity:   2.5,
+					EventAt:       time.Unix(0, 1635739200000*int64(time.Millisecond)).UTC(),
+					EventAtSource: exchanges.EventAtSourceExchange,
 				},
 			},
 		},
@@ -106,6 +113,7 @@ func TestClient_FetchTickers(t *testing.T) {
 			statusCode:  http.StatusOK,
 			expectError: false,
 			wantTickers: []exchanges.Ticker{},
+			wantFailed:  1,
 		},
 	}
 
@@ -138,6 +146,14 @@ func TestClient_FetchTickers(t *testing.T) {
 				return
 			}
 
+			if tt.wantFailed > 0 {
+				var convErr *exchanges.ConversionError
+				require.ErrorAs(t, err, &convErr)
+				assert.Equal(t, tt.wantFailed, convErr.Failed)
+				assert.Equal(t, tt.wantTickers, got)
+				return
+			}
+
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantTickers, got)
 		})
@@ -245,8 +261,9 @@ func TestClient_SubscribeLiquidations(t *testing.T) {
 			})
 
 			if !tt.skipTickerSetup {
-				client.setAvailableTickers(tt.availableTickers)
+				client.setAvailableTickers(InstTypeSwap, tt.availableTickers)
 			}
+			client.contracts.specs["BTC-USDT-SWAP"] = exchanges.ContractSpec{Multiplier: 0.01}
 
 			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
 			defer cancel()
@@ -310,3 +327,201 @@ func TestClient_SubscribeLiquidations(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_FetchTickersMultipleInstTypes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		instID := map[string]string{InstTypeSwap: "BTC-USDT-SWAP", InstTypeFutures: "BTC-USDT-250328"}[r.URL.Query().Get("instType")]
+		json.NewEncoder(w).Encode(TickerResponse{Code: "0", Data: []TickerDTO{{
+			InstID: instID, BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1", Timestamp: "1635739200000",
+		}}})
+	}))
+	defer server.Close()
+
+	client := NewOKX(Config{APIUrl: server.URL, InstTypes: []string{InstTypeSwap, InstTypeFutures}})
+	got, err := client.FetchTickers(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, "BTC-USDT-SWAP", got[0].Symbol)
+	assert.Equal(t, InstTypeSwap, got[0].InstType)
+	assert.Equal(t, "BTC-USDT-250328", got[1].Symbol)
+	assert.Equal(t, InstTypeFutures, got[1].InstType)
+	assert.Equal(t, []string{"BTC-USDT-SWAP", "BTC-USDT-250328"}, client.getAvailableTickers())
+}
+
+func TestClient_FetchFundingRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case FetchTickersPath:
+			assert.Equal(t, InstTypeSwap, r.URL.Query().Get("instType"))
+			json.NewEncoder(w).Encode(TickerResponse{Code: "0", Data: []TickerDTO{
+				{InstID: "BTC-USDT-SWAP", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1"},
+				{InstID: "ETH-USD-SWAP", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1"},
+				{InstID: "DOGE-USDT-SWAP", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1"},
+			}})
+		case FetchFundingRatePath:
+			assert.Equal(t, FundingRateAllSwaps, r.URL.Query().Get("instId"))
+			json.NewEncoder(w).Encode(FundingRateResponse{Code: "0", Data: []FundingRateDTO{
+				{InstID: "ETH-USD-SWAP", FundingRate: "0.0001", FundingTime: "1703088000000", Timestamp: "1703070685309"},
+				{InstID: "BTC-USDT-SWAP", FundingRate: "0.0001", FundingTime: "1703088000000", Timestamp: "1703070685309"},
+				{InstID: "DOGE-USDT-SWAP", FundingRate: "", FundingTime: "1703088000000", Timestamp: "1703070685309"},
+				{InstID: "SOL-USDT-SWAP", FundingRate: "0.0002", FundingTime: "1703088000000", Timestamp: "1703070685309"},
+			}})
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOKX(Config{APIUrl: server.URL})
+	got, err := client.FetchFundingRates(context.Background())
+
+	var convErr *exchanges.ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, 3, convErr.Total)
+	assert.Equal(t, 1, convErr.Failed)
+	assert.Equal(t, []exchanges.FundingRate{
+		{Symbol: "BTC-USDT-SWAP", Base: "BTC", Quote: "USDT", Rate: 0.0001, NextFundingAt: time.UnixMilli(1703088000000).UTC(), EventAt: time.UnixMilli(1703070685309).UTC()},
+		{Symbol: "ETH-USD-SWAP", Base: "ETH", Quote: "USD", Rate: 0.0001, NextFundingAt: time.UnixMilli(1703088000000).UTC(), EventAt: time.UnixMilli(1703070685309).UTC()},
+	}, got)
+
+	assert.Equal(t, 2.0, client.APIUsage().Used, "the swaps and then their rates in a single request, both charged to the budget")
+
+	futures := NewOKX(Config{APIUrl: server.URL, InstTypes: []string{InstTypeFutures}})
+	got, err = futures.FetchFundingRates(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, got, "expiring futures have no funding")
+}
+
+func TestClient_FetchFundingRatesErrorCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case FetchTickersPath:
+			json.NewEncoder(w).Encode(TickerResponse{Code: "0", Data: []TickerDTO{
+				{InstID: "BTC-USDT-SWAP", BidPrice: "1", BidQuantity: "1", AskPrice: "2", AskQuantity: "1"},
+			}})
+		case FetchFundingRatePath:
+			json.NewEncoder(w).Encode(FundingRateResponse{Code: "50011", Msg: "Too Many Requests"})
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewOKX(Config{APIUrl: server.URL}).FetchFundingRates(context.Background())
+	assert.ErrorContains(t, err, "error code 50011")
+}
+
+func TestClient_FetchOpenInterest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, FetchOpenInterestPath, r.URL.Path)
+		switch instType := r.URL.Query().Get("instType"); instType {
+		case InstTypeSwap:
+			json.NewEncoder(w).Encode(OpenInterestResponse{Code: "0", Data: []OpenInterestDTO{
+				{InstID: "BTC-USDT-SWAP", OI: "250000", OICcy: "2500", Timestamp: "1703070685309"},
+				{InstID: "ETH-USDT-SWAP", OI: "bad", OICcy: "bad", Timestamp: "1703070685309"},
+			}})
+		case InstTypeFutures:
+			json.NewEncoder(w).Encode(OpenInterestResponse{Code: "0", Data: []OpenInterestDTO{
+				{InstID: "BTC-USD-250328", OI: "1000", OICcy: "12.5", Timestamp: "1703070685309"},
+			}})
+		default:
+			t.Errorf("unexpected instType %s", instType)
+		}
+	}))
+	defer server.Close()
+
+	client := NewOKX(Config{APIUrl: server.URL, InstTypes: []string{InstTypeSwap, InstTypeFutures, InstTypeOption}})
+	got, err := client.FetchOpenInterest(context.Background())
+
+	var convErr *exchanges.ConversionError
+	require.ErrorAs(t, err, &convErr)
+	assert.Equal(t, 3, convErr.Total)
+	assert.Equal(t, 1, convErr.Failed)
+	assert.Equal(t, []exchanges.OpenInterest{
+		{Symbol: "BTC-USDT-SWAP", Value: 2500, EventAt: time.UnixMilli(1703070685309).UTC()},
+		{Symbol: "BTC-USD-250328", Value: 12.5, EventAt: time.UnixMilli(1703070685309).UTC()},
+	}, got)
+}
+
+func TestClient_FetchOpenInterestErrorCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(OpenInterestResponse{Code: "50011", Msg: "Too Many Requests"})
+	}))
+	defer server.Close()
+
+	_, err := NewOKX(Config{APIUrl: server.URL}).FetchOpenInterest(context.Background())
+	assert.ErrorContains(t, err, "Too Many Requests")
+}
+
+func TestClient_RequestsChargeTheBudget(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case FetchOpenInterestPath:
+			json.NewEncoder(w).Encode(OpenInterestResponse{Code: "0"})
+		case FetchInstrumentsPath:
+			json.NewEncoder(w).Encode(InstrumentResponse{Code: "0"})
+		}
+	}))
+	defer server.Close()
+	client := NewOKX(Config{APIUrl: server.URL})
+
+	_, err := client.FetchOpenInterest(context.Background())
+	require.NoError(t, err)
+	_, err = client.fetchInstTypeInstruments(context.Background(), InstTypeSwap)
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, client.APIUsage().Used, "open interest and instruments requests are charged")
+
+	client.budget.Block(time.Now().Add(time.Minute))
+	_, err = client.FetchOpenInterest(context.Background())
+	assert.ErrorIs(t, err, exchanges.ErrThrottled)
+	_, err = client.contractSpec(context.Background(), "BTC-USDT-SWAP")
+	assert.ErrorIs(t, err, exchanges.ErrThrottled)
+	assert.Equal(t, int32(2), requests.Load(), "throttled requests aren't sent")
+	assert.True(t, client.contracts.updatedAt.IsZero(), "throttled specs are fetched again on the next miss")
+}
+
+func TestClient_Conformance(t *testing.T) {
+	exchangestest.Run(t, exchangestest.Fixture{
+		New: func(apiURL, wsURL string, ws exchanges.WebsocketConfig) exchanges.Exchange {
+			return NewOKX(Config{Name: "test", APIUrl: apiURL, WSUrl: wsURL, HTTPClient: http.DefaultClient, Websocket: ws})
+		},
+		Tickers: json.RawMessage(`{"code":"0","data":[
+			{"instId":"BTC-USDT-SWAP","last":"50000.50","bidPx":"50000.25","bidSz":"1.5","askPx":"50000.75","askSz":"2.5","ts":"1635739200000"},
+			{"instId":"ETH-USDT-SWAP","last":"3000.15","bidPx":"3000.10","bidSz":"10","askPx":"3000.20","askSz":"12","ts":"1635739200000"}]}`),
+		Responses: map[string]any{
+			FetchInstrumentsPath: json.RawMessage(`{"code":"0","data":[
+				{"instId":"BTC-USDT-SWAP","ctVal":"0.01","ctMult":"1","ctValCcy":"BTC","ctType":"linear"}]}`),
+		},
+		Liquidation: `{"arg":{"channel":"liquidation-orders","instType":"SWAP"},"data":[{"details":[{"side":"sell","sz":"0.001","ts":"1635739200000","bkPx":"50000.50"}],"instId":"BTC-USDT-SWAP"}]}`,
+	})
+}
+
+func TestClient_ContractSpec(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, FetchInstrumentsPath, r.URL.Path)
+		assert.Equal(t, InstTypeSwap, r.URL.Query().Get("instType"))
+		json.NewEncoder(w).Encode(InstrumentResponse{Code: "0", Data: []InstrumentDTO{
+			{InstID: "BTC-USDT-SWAP", CtVal: "0.01", CtMult: "1", CtValCcy: "BTC", CtType: "linear"},
+			{InstID: "BTC-USD-SWAP", CtVal: "100", CtMult: "1", CtValCcy: "USD", CtType: "inverse"},
+		}})
+	}))
+	defer server.Close()
+	client := NewOKX(Config{APIUrl: server.URL})
+
+	spec, err := client.contractSpec(context.Background(), "BTC-USDT-SWAP")
+	require.NoError(t, err)
+	assert.Equal(t, exchanges.ContractSpec{Multiplier: 0.01}, spec)
+	spec, err = client.contractSpec(context.Background(), "BTC-USD-SWAP")
+	require.NoError(t, err)
+	assert.Equal(t, exchanges.ContractSpec{FaceValue: 100}, spec)
+
+	spec, err = client.contractSpec(context.Background(), "ETH-USD-SWAP")
+	require.NoError(t, err)
+	assert.Equal(t, exchanges.ContractSpec{FaceValue: 10}, spec, "coin-margined instruments fall back to the face value of their ID")
+	_, err = client.contractSpec(context.Background(), "NEW-USDT-SWAP")
+	assert.ErrorContains(t, err, "unknown contract size of NEW-USDT-SWAP")
+	assert.Equal(t, int32(1), requests.Load(), "the specs are fetched at most once per interval")
+}
@@ -47,21 +47,156 @@ func (ot TickerDTO) toTicker() (exchanges.Ticker, error) {
 	if err != nil {
 		return ticker, fmt.Errorf("invalid askQuantity '%s': %w", ot.AskQuantity, err)
 	}
-	ts, err := strconv.ParseInt(ot.Timestamp, 10, 64)
-	if err != nil {
-		return ticker, fmt.Errorf("invalid timestamp '%s': %w", ot.Timestamp, err)
+	// a missing timestamp is left for the EventAt fallback
+	var ts int64
+	if ot.Timestamp != "" {
+		if ts, err = strconv.ParseInt(ot.Timestamp, 10, 64); err != nil {
+			return ticker, fmt.Errorf("invalid timestamp '%s': %w", ot.Timestamp, err)
+		}
 	}
 
 	ticker.Symbol = ot.InstID
+	ticker.Base, ticker.Quote, ticker.ContractValue = splitInstID(ot.InstID)
 	ticker.BidPrice = bidPrice
 	ticker.AskPrice = askPrice
 	ticker.BidQuantity = bidQuantity
 	ticker.AskQuantity = askQuantity
-	ticker.EventAt = time.Unix(0, ts*int64(time.Millisecond))
+	ticker.EventAt = exchanges.UnixMilli(ts)
 
 	return ticker, nil
 }
 
+// splitInstID returns the assets of a swap or futures instrument ID (BTC-USDT-SWAP, BTC-USD-250328) and
+// the USD face value of coin-margined contracts: 100 USD for BTC and 10 USD for the other coins.
+// Options (BTC-USD-250328-100000-C) are premium quoted in the base coin and left without assets
+func splitInstID(instID string) (base, quote string, contractValue float64) {
+	parts := strings.Split(instID, "-")
+	if len(parts) != 2 && len(parts) != 3 {
+		return "", "", 0
+	}
+
+	base, quote = parts[0], parts[1]
+	if quote == "USD" {
+		contractValue = 10
+		if base == "BTC" {
+			contractValue = 100
+		}
+	}
+	return base, quote, contractValue
+}
+
+// InstrumentResponse represents the API response for the instruments of an instrument type
+type InstrumentResponse struct {
+	Code string          `json:"code"`
+	Msg  string          `json:"msg"`
+	Data []InstrumentDTO `json:"data"`
+}
+
+// InstrumentDTO represents the contract specification of a swap or futures instrument from the OKX API
+type InstrumentDTO struct {
+	InstID   string `json:"instId"`
+	CtVal    string `json:"ctVal"`    // contract value in CtValCcy
+	CtMult   string `json:"ctMult"`   // contract multiplier
+	CtValCcy string `json:"ctValCcy"` // the base coin for linear contracts, USD for inverse ones
+	CtType   string `json:"ctType"`   // "linear" or "inverse"
+}
+
+// toContractSpec converts an InstrumentDTO to the spec converting the contracts of the instrument to base units:
+// a linear contract is worth ctVal*ctMult base coins, an inverse one ctVal*ctMult USD
+func (oi InstrumentDTO) toContractSpec() (exchanges.ContractSpec, error) {
+	value, err := strconv.ParseFloat(oi.CtVal, 64)
+	if err != nil {
+		return exchanges.ContractSpec{}, fmt.Errorf("invalid ctVal '%s': %w", oi.CtVal, err)
+	}
+	multiplier := 1.0
+	if oi.CtMult != "" {
+		if multiplier, err = strconv.ParseFloat(oi.CtMult, 64); err != nil {
+			return exchanges.ContractSpec{}, fmt.Errorf("invalid ctMult '%s': %w", oi.CtMult, err)
+		}
+	}
+
+	switch oi.CtType {
+	case "linear":
+		return exchanges.ContractSpec{Multiplier: value * multiplier}, nil
+	case "inverse":
+		return exchanges.ContractSpec{FaceValue: value * multiplier}, nil
+	default:
+		return exchanges.ContractSpec{}, fmt.Errorf("invalid ctType '%s'", oi.CtType)
+	}
+}
+
+// FundingRateResponse represents the API response for the funding rate of a swap
+type FundingRateResponse struct {
+	Code string           `json:"code"`
+	Msg  string           `json:"msg"`
+	Data []FundingRateDTO `json:"data"`
+}
+
+// FundingRateDTO represents the funding rate of a swap from the OKX API
+type FundingRateDTO struct {
+	InstID      string `json:"instId"`
+	FundingRate string `json:"fundingRate"`
+	FundingTime string `json:"fundingTime"` // settlement of the current period
+	Timestamp   string `json:"ts"`
+}
+
+// toFundingRate converts a FundingRateDTO to an exchanges.FundingRate, OKX reports no mark price with it
+func (of FundingRateDTO) toFundingRate() (exchanges.FundingRate, error) {
+	rate, err := strconv.ParseFloat(of.FundingRate, 64)
+	if err != nil {
+		return exchanges.FundingRate{}, fmt.Errorf("invalid fundingRate '%s': %w", of.FundingRate, err)
+	}
+	fundingTime, err := strconv.ParseInt(of.FundingTime, 10, 64)
+	if err != nil {
+		return exchanges.FundingRate{}, fmt.Errorf("invalid fundingTime '%s': %w", of.FundingTime, err)
+	}
+	ts, err := strconv.ParseInt(of.Timestamp, 10, 64)
+	if err != nil {
+		return exchanges.FundingRate{}, fmt.Errorf("invalid timestamp '%s': %w", of.Timestamp, err)
+	}
+
+	fundingRate := exchanges.FundingRate{
+		Symbol:        of.InstID,
+		Rate:          rate,
+		NextFundingAt: exchanges.UnixMilli(fundingTime),
+		EventAt:       exchanges.UnixMilli(ts),
+	}
+	fundingRate.Base, fundingRate.Quote, _ = splitInstID(of.InstID)
+	return fundingRate, nil
+}
+
+// OpenInterestResponse represents the API response for the open interest of an instrument type
+type OpenInterestResponse struct {
+	Code string            `json:"code"`
+	Msg  string            `json:"msg"`
+	Data []OpenInterestDTO `json:"data"`
+}
+
+// OpenInterestDTO represents the open interest of an instrument from the OKX API
+type OpenInterestDTO struct {
+	InstID    string `json:"instId"`
+	OI        string `json:"oi"`    // in contracts
+	OICcy     string `json:"oiCcy"` // in base asset units
+	Timestamp string `json:"ts"`
+}
+
+// toOpenInterest converts an OpenInterestDTO to an exchanges.OpenInterest in base asset units
+func (oo OpenInterestDTO) toOpenInterest() (exchanges.OpenInterest, error) {
+	value, err := strconv.ParseFloat(oo.OICcy, 64)
+	if err != nil {
+		return exchanges.OpenInterest{}, fmt.Errorf("invalid oiCcy '%s': %w", oo.OICcy, err)
+	}
+	ts, err := strconv.ParseInt(oo.Timestamp, 10, 64)
+	if err != nil {
+		return exchanges.OpenInterest{}, fmt.Errorf("invalid timestamp '%s': %w", oo.Timestamp, err)
+	}
+	return exchanges.OpenInterest{
+		Symbol:  oo.InstID,
+		Value:   value,
+		EventAt: exchanges.UnixMilli(ts),
+	}, nil
+}
+
 // LiquidationEvent represents a liquidation websocket event
 type LiquidationEvent struct {
 	Arg struct {
@@ -71,6 +206,13 @@ type LiquidationEvent struct {
 	Data []LiquidationDTO `json:"data"`
 }
 
+// liquidationSides maps the lowercased OKX side to the normalized one.
+// OKX reports the side of the forced order, so "sell" means a long position was liquidated
+var liquidationSides = map[string]exchanges.LiquidationSide{
+	"sell": exchanges.LongLiquidated,
+	"buy":  exchanges.ShortLiquidated,
+}
+
 // LiquidationDTO represents a liquidation order from OKX
 type LiquidationDTO struct {
 	Details []struct {
@@ -82,8 +224,9 @@ type LiquidationDTO struct {
 	InstID string `json:"instId"`
 }
 
-// toLiquidation converts a LiquidationDTO to an exchanges.Liquidation
-func (ol LiquidationDTO) toLiquidation() (exchanges.Liquidation, error) {
+// toLiquidation converts a LiquidationDTO to an exchanges.Liquidation. OKX reports the sizes in contracts,
+// the spec of the instrument converts them to base asset units
+func (ol LiquidationDTO) toLiquidation(spec exchanges.ContractSpec) (exchanges.Liquidation, error) {
 	if len(ol.Details) > 1 {
 		fmt.Println(ol)
 	}
@@ -103,20 +246,17 @@ func (ol LiquidationDTO) toLiquidation() (exchanges.Liquidation, error) {
 	}
 
 	liquidation.Price = price
-	liquidation.Quantity = quantity
+	liquidation.Quantity = spec.BaseQuantity(quantity, price)
 	liquidation.Symbol = ol.InstID
-	liquidation.EventAt = time.Unix(0, ts*int64(time.Millisecond))
-	liquidation.TotalPrice = price * quantity
-
-	// Convert OKX-specific side to normalized format
-	switch strings.ToLower(ol.Details[0].Side) {
-	case "buy":
-		liquidation.Side = "BUY"
-	case "sell":
-		liquidation.Side = "SELL"
-	default:
+	liquidation.Base, liquidation.Quote, _ = splitInstID(ol.InstID)
+	liquidation.EventAt = time.Unix(0, ts*int64(time.Millisecond)).UTC()
+	liquidation.TotalPrice = price * liquidation.Quantity
+
+	side, ok := liquidationSides[strings.ToLower(ol.Details[0].Side)]
+	if !ok {
 		return liquidation, fmt.Errorf("invalid side '%s'", ol.Details[0].Side)
 	}
+	liquidation.Side = side
 
 	return liquidation, nil
 }
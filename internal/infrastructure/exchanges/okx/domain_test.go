@@ -1,6 +1,8 @@
 package okx
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -29,11 +31,13 @@ func TestTickerDTO_ToTicker(t *testing.T) {
 			},
 			want: exchanges.Ticker{
 				Symbol:      "BTC-USDT-SWAP",
+				Base:        "BTC",
+				Quote:       "USDT",
 				BidPrice:    50000.25,
 				BidQuantity: 1.5,
 				AskPrice:    50000.75,
 				AskQuantity: 2.5,
-				EventAt:     time.Unix(0, 1635739200000*int64(time.Millisecond)),
+				EventAt:     time.Unix(0, 1635739200000*int64(time.Millisecond)).UTC(),
 			},
 			wantErr: false,
 		},
@@ -117,6 +121,17 @@ func TestTickerDTO_ToTicker(t *testing.T) {
 	}
 }
 
+// liquidationDetail returns a liquidation of 1 BTC-USDT-SWAP contract at 100 on the side, OKX reports the side of
+// the forced order in any case
+func liquidationDetail(side string) LiquidationDTO {
+	var dto LiquidationDTO
+	frame := fmt.Sprintf(`{"instId":"BTC-USDT-SWAP","details":[{"side":"%s","sz":"1","ts":"1635739200000","bkPx":"100"}]}`, side)
+	if err := json.Unmarshal([]byte(frame), &dto); err != nil {
+		panic(err)
+	}
+	return dto
+}
+
 func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -144,10 +159,12 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 			},
 			want: exchanges.Liquidation{
 				Symbol:     "BTC-USDT-SWAP",
-				Side:       "SELL",
+				Base:       "BTC",
+				Quote:      "USDT",
+				Side:       exchanges.LongLiquidated,
 				Price:      50000.50,
 				Quantity:   0.001,
-				EventAt:    time.Unix(0, 1635739200000*int64(time.Millisecond)),
+				EventAt:    time.Unix(0, 1635739200000*int64(time.Millisecond)).UTC(),
 				TotalPrice: 50.0005,
 			},
 			wantErr: false,
@@ -172,10 +189,12 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 			},
 			want: exchanges.Liquidation{
 				Symbol:     "BTC-USDT-SWAP",
-				Side:       "BUY",
+				Base:       "BTC",
+				Quote:      "USDT",
+				Side:       exchanges.ShortLiquidated,
 				Price:      50000.50,
 				Quantity:   0.001,
-				EventAt:    time.Unix(0, 1635739200000*int64(time.Millisecond)),
+				EventAt:    time.Unix(0, 1635739200000*int64(time.Millisecond)).UTC(),
 				TotalPrice: 50.0005,
 			},
 			wantErr: false,
@@ -264,11 +283,41 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 			want:    exchanges.Liquidation{},
 			wantErr: true,
 		},
+		{
+			name: "upper case sell",
+			dto:  liquidationDetail("SELL"),
+			want: exchanges.Liquidation{
+				Symbol:     "BTC-USDT-SWAP",
+				Base:       "BTC",
+				Quote:      "USDT",
+				Side:       exchanges.LongLiquidated,
+				Price:      100,
+				Quantity:   1,
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
+				TotalPrice: 100,
+			},
+		},
+		{
+			name: "mixed case buy",
+			dto:  liquidationDetail("Buy"),
+			want: exchanges.Liquidation{
+				Symbol:     "BTC-USDT-SWAP",
+				Base:       "BTC",
+				Quote:      "USDT",
+				Side:       exchanges.ShortLiquidated,
+				Price:      100,
+				Quantity:   1,
+				EventAt:    time.UnixMilli(1635739200000).UTC(),
+				TotalPrice: 100,
+			},
+		},
+		{name: "position side", dto: liquidationDetail("long"), wantErr: true},
+		{name: "missing side", dto: liquidationDetail(""), wantErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.dto.toLiquidation()
+			got, err := tt.dto.toLiquidation(exchanges.ContractSpec{Multiplier: 1})
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -278,3 +327,105 @@ func TestLiquidationDTO_ToLiquidation(t *testing.T) {
 		})
 	}
 }
+
+// TestLiquidationDTO_ContractSizes checks the sizes in contracts are converted to base units, so the totals
+// compare with the ones of the exchanges reporting base quantities
+func TestLiquidationDTO_ContractSizes(t *testing.T) {
+	tests := []struct {
+		name         string
+		instID       string
+		sz           string
+		spec         exchanges.ContractSpec
+		wantQuantity float64
+		wantTotal    float64
+	}{
+		{name: "linear swap", instID: "BTC-USDT-SWAP", sz: "2", spec: exchanges.ContractSpec{Multiplier: 0.01}, wantQuantity: 0.02, wantTotal: 1000},
+		{name: "linear fraction", instID: "BTC-USDT-SWAP", sz: "0.001", spec: exchanges.ContractSpec{Multiplier: 0.01}, wantQuantity: 0.00001, wantTotal: 0.5},
+		{name: "inverse swap", instID: "BTC-USD-SWAP", sz: "20", spec: exchanges.ContractSpec{FaceValue: 100}, wantQuantity: 0.04, wantTotal: 2000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dto LiquidationDTO
+			require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{"instId":"%s","details":[{"side":"sell","sz":"%s","ts":"1","bkPx":"50000"}]}`, tt.instID, tt.sz)), &dto))
+
+			got, err := dto.toLiquidation(tt.spec)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantQuantity, got.Quantity, 1e-12)
+			assert.InDelta(t, tt.wantTotal, got.TotalPrice, 1e-9)
+		})
+	}
+}
+
+func TestInstrumentDTO_ToContractSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		dto     InstrumentDTO
+		want    exchanges.ContractSpec
+		wantErr bool
+	}{
+		{
+			name: "linear",
+			dto:  InstrumentDTO{InstID: "BTC-USDT-SWAP", CtVal: "0.01", CtMult: "1", CtValCcy: "BTC", CtType: "linear"},
+			want: exchanges.ContractSpec{Multiplier: 0.01},
+		},
+		{
+			name: "inverse",
+			dto:  InstrumentDTO{InstID: "ETH-USD-SWAP", CtVal: "10", CtMult: "1", CtValCcy: "USD", CtType: "inverse"},
+			want: exchanges.ContractSpec{FaceValue: 10},
+		},
+		{
+			name: "multiplier",
+			dto:  InstrumentDTO{InstID: "PEPE-USDT-SWAP", CtVal: "10000000", CtMult: "0.1", CtValCcy: "PEPE", CtType: "linear"},
+			want: exchanges.ContractSpec{Multiplier: 1000000},
+		},
+		{
+			name:    "invalid ctVal",
+			dto:     InstrumentDTO{InstID: "BTC-USDT-SWAP", CtVal: "", CtType: "linear"},
+			wantErr: true,
+		},
+		{
+			name:    "option",
+			dto:     InstrumentDTO{InstID: "BTC-USD-250328-100000-C", CtVal: "0.01", CtType: ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dto.toContractSpec()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want.Multiplier, got.Multiplier, 1e-9)
+			assert.InDelta(t, tt.want.FaceValue, got.FaceValue, 1e-9)
+		})
+	}
+}
+
+func TestSplitInstID(t *testing.T) {
+	tests := []struct {
+		instID            string
+		wantBase          string
+		wantQuote         string
+		wantContractValue float64
+	}{
+		{instID: "BTC-USDT-SWAP", wantBase: "BTC", wantQuote: "USDT"},
+		{instID: "ETH-USDC-250328", wantBase: "ETH", wantQuote: "USDC"},
+		{instID: "BTC-USD-SWAP", wantBase: "BTC", wantQuote: "USD", wantContractValue: 100},
+		{instID: "ETH-USD-250328", wantBase: "ETH", wantQuote: "USD", wantContractValue: 10},
+		{instID: "BTC-USD-250328-100000-C"},
+		{instID: "BTCUSDT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.instID, func(t *testing.T) {
+			base, quote, contractValue := splitInstID(tt.instID)
+			assert.Equal(t, tt.wantBase, base)
+			assert.Equal(t, tt.wantQuote, quote)
+			assert.Equal(t, tt.wantContractValue, contractValue)
+		})
+	}
+}
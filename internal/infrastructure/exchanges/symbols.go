@@ -0,0 +1,121 @@
+package exchanges
+
+import (
+	"slices"
+	"strings"
+)
+
+// Quote assets found as a suffix of concatenated symbols such as BTCUSDT.
+// Longer assets go first so BTCBUSD isn't split as BTCB/USD
+var knownQuotes = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "USD", "BTC", "ETH", "BNB"}
+
+// SplitSymbol splits a concatenated symbol (BTCUSDT) into its base and quote assets,
+// both are empty when the symbol doesn't end with a known quote asset
+func SplitSymbol(symbol string) (base, quote string) {
+	for _, q := range knownQuotes {
+		if b, ok := strings.CutSuffix(symbol, q); ok && b != "" {
+			return b, q
+		}
+	}
+	return "", ""
+}
+
+// SymbolMapper maps the symbols of every exchange (BTCUSDT, BTC-USDT-SWAP, BTC-PERP) to a canonical form, their base
+// and quote assets concatenated, and filters tickers by quote asset. The zero value maps without normalizing quotes
+// and keeps every ticker
+type SymbolMapper struct {
+	// QuoteAliases normalize the quote assets of the canonical symbols, e.g. USDT:USD and USDC:USD compare
+	// the stablecoin markets of different exchanges as one
+	QuoteAliases map[string]string
+
+	// Quotes are the quote assets Keep accepts, as quoted or normalized; all when empty
+	Quotes []string
+}
+
+// NormalizeQuote returns the alias of a quote asset, the asset itself without one
+func (m SymbolMapper) NormalizeQuote(quote string) string {
+	if alias, ok := m.QuoteAliases[quote]; ok {
+		return alias
+	}
+	return quote
+}
+
+// MapSymbol returns the canonical form of a symbol quoted in its name.
+// Dated futures and options trade at a basis to the spot price and have no canonical form
+func (m SymbolMapper) MapSymbol(symbol string) (string, bool) {
+	base, quote, ok := symbolAssets(symbol)
+	if !ok || quote == "" {
+		return "", false
+	}
+	return base + m.NormalizeQuote(quote), true
+}
+
+// Map returns the canonical form of the symbol of a ticker; the ticker quote completes the symbols not carrying
+// theirs, e.g. the USDC of BTC-PERP on Coinbase International
+func (m SymbolMapper) Map(t Ticker) (string, bool) {
+	base, quote, ok := symbolAssets(t.Symbol)
+	if !ok {
+		return "", false
+	}
+	if quote == "" {
+		quote = t.Quote
+	}
+	if quote == "" {
+		return "", false
+	}
+	return base + m.NormalizeQuote(quote), true
+}
+
+// Keep reports whether the ticker is quoted in one of the Quotes, tickers of an unknown quote asset are only kept
+// without Quotes
+func (m SymbolMapper) Keep(t Ticker) bool {
+	if len(m.Quotes) == 0 {
+		return true
+	}
+	quote := t.Quote
+	if quote == "" {
+		_, quote, _ = symbolAssets(t.Symbol)
+	}
+	if quote == "" {
+		return false
+	}
+	return slices.Contains(m.Quotes, quote) || slices.Contains(m.Quotes, m.NormalizeQuote(quote))
+}
+
+// Filter returns the tickers quoted in one of the Quotes, tickers is returned as is without Quotes
+func (m SymbolMapper) Filter(tickers []Ticker) []Ticker {
+	if len(m.Quotes) == 0 {
+		return tickers
+	}
+	kept := make([]Ticker, 0, len(tickers))
+	for _, t := range tickers {
+		if m.Keep(t) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// symbolAssets returns the base and quote assets of a perpetual or spot symbol: concatenated (BTCUSDT) or
+// dashed (BTC-USDT, BTC-USDT-SWAP); the quote is empty for BTC-PERP. Dated futures (BTCUSDT_250328,
+// BTC-USD-250328, BTC-28MAR25) and options are not mapped
+func symbolAssets(symbol string) (base, quote string, ok bool) {
+	if parts := strings.Split(symbol, "-"); len(parts) > 1 {
+		if len(parts) == 2 && parts[1] == "PERP" && parts[0] != "" {
+			return parts[0], "", true
+		}
+		if len(parts) > 3 || len(parts) == 3 && parts[2] != "SWAP" {
+			return "", "", false
+		}
+		if _, quote := SplitSymbol(parts[0] + parts[1]); quote != parts[1] {
+			return "", "", false
+		}
+		return parts[0], parts[1], true
+	}
+
+	if strings.Contains(symbol, "_") {
+		return "", "", false
+	}
+	base, quote = SplitSymbol(symbol)
+	return base, quote, base != ""
+}
@@ -0,0 +1,89 @@
+package exchanges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSymbol(t *testing.T) {
+	tests := []struct {
+		symbol    string
+		wantBase  string
+		wantQuote string
+	}{
+		{symbol: "BTCUSDT", wantBase: "BTC", wantQuote: "USDT"},
+		{symbol: "1000SHIBUSDC", wantBase: "1000SHIB", wantQuote: "USDC"},
+		{symbol: "BTCBUSD", wantBase: "BTC", wantQuote: "BUSD"},
+		{symbol: "ETHFDUSD", wantBase: "ETH", wantQuote: "FDUSD"},
+		{symbol: "BTCUSD", wantBase: "BTC", wantQuote: "USD"},
+		{symbol: "ETHBTC", wantBase: "ETH", wantQuote: "BTC"},
+		{symbol: "USDT"},
+		{symbol: "BTCUSDH25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			base, quote := SplitSymbol(tt.symbol)
+			assert.Equal(t, tt.wantBase, base)
+			assert.Equal(t, tt.wantQuote, quote)
+		})
+	}
+}
+
+func TestSymbolMapper_Map(t *testing.T) {
+	mapper := SymbolMapper{QuoteAliases: map[string]string{"USDT": "USD", "USDC": "USD"}}
+	tests := []struct {
+		ticker Ticker
+		want   string
+		wantOk bool
+	}{
+		{ticker: Ticker{Symbol: "BTCUSDT"}, want: "BTCUSD", wantOk: true},
+		{ticker: Ticker{Symbol: "BTC-USDT-SWAP"}, want: "BTCUSD", wantOk: true},
+		{ticker: Ticker{Symbol: "BTC-PERP", Quote: "USDC"}, want: "BTCUSD", wantOk: true},
+		{ticker: Ticker{Symbol: "ETHBTC"}, want: "ETHBTC", wantOk: true},
+		{ticker: Ticker{Symbol: "BTC-PERP"}},
+		{ticker: Ticker{Symbol: "BTC-USD-250328"}},
+		{ticker: Ticker{Symbol: "BTC-USD-250328-90000-C"}},
+		{ticker: Ticker{Symbol: "BTCUSDT_250328"}},
+		{ticker: Ticker{Symbol: "BTC-28MAR25"}},
+		{ticker: Ticker{Symbol: "BTCUSDH25", Quote: "USD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ticker.Symbol, func(t *testing.T) {
+			got, ok := mapper.Map(tt.ticker)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, ok := SymbolMapper{}.MapSymbol("BTC-USDT-SWAP")
+	assert.True(t, ok)
+	assert.Equal(t, "BTCUSDT", got, "quotes aren't normalized without aliases")
+}
+
+func TestSymbolMapper_Filter(t *testing.T) {
+	tickers := []Ticker{
+		{Symbol: "BTCUSDT", Quote: "USDT"},
+		{Symbol: "BTC-PERP", Quote: "USDC"},
+		{Symbol: "BTC-USD-SWAP"},
+		{Symbol: "ETHBTC", Quote: "BTC"},
+		{Symbol: "BTCUSDH25"},
+	}
+	symbols := func(tickers []Ticker) []string {
+		var result []string
+		for _, t := range tickers {
+			result = append(result, t.Symbol)
+		}
+		return result
+	}
+
+	assert.Equal(t, tickers, SymbolMapper{}.Filter(tickers), "every ticker is kept without quotes")
+	assert.Equal(t, []string{"BTCUSDT", "BTC-PERP"}, symbols(SymbolMapper{Quotes: []string{"USDT", "USDC"}}.Filter(tickers)))
+
+	stablecoins := SymbolMapper{QuoteAliases: map[string]string{"USDT": "USD", "USDC": "USD"}, Quotes: []string{"USD"}}
+	assert.Equal(t, []string{"BTCUSDT", "BTC-PERP", "BTC-USD-SWAP"}, symbols(stablecoins.Filter(tickers)), "normalized quotes are accepted")
+	stablecoins.Quotes = []string{"USDT"}
+	assert.Equal(t, []string{"BTCUSDT"}, symbols(stablecoins.Filter(tickers)), "quotes are accepted as quoted")
+}
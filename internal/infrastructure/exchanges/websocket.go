@@ -0,0 +1,74 @@
+package exchanges
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultPingTimeout is the time allowed to write a ping frame
+const DefaultPingTimeout = 5 * time.Second
+
+// WebsocketConfig configures the websocket connections of an exchange client, zero values keep the gorilla defaults
+type WebsocketConfig struct {
+	EnableCompression bool          // negotiate permessage-deflate, the server may still decline it
+	HandshakeTimeout  time.Duration // 0 uses the gorilla default of 45s
+	ReadBufferSize    int           // bytes, 0 uses the gorilla default of 4096
+	Headers           http.Header   // sent with the handshake request, e.g. a user agent
+	PingInterval      time.Duration // send a ping frame this often to keep the connection alive, 0 disables
+	ReconnectDelay    time.Duration // wait between reconnects, 0 uses the default delay of the client
+}
+
+// Dialer returns the dialer of the connections
+func (c WebsocketConfig) Dialer() *websocket.Dialer {
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = c.EnableCompression
+	if c.HandshakeTimeout > 0 {
+		dialer.HandshakeTimeout = c.HandshakeTimeout
+	}
+	if c.ReadBufferSize > 0 {
+		dialer.ReadBufferSize = c.ReadBufferSize
+	}
+	return &dialer
+}
+
+// ReconnectDelayOr returns the configured reconnect delay, or fallback when it's not set
+func (c WebsocketConfig) ReconnectDelayOr(fallback time.Duration) time.Duration {
+	if c.ReconnectDelay > 0 {
+		return c.ReconnectDelay
+	}
+	return fallback
+}
+
+// Dial connects to url with the configured dialer and headers and starts the pings if enabled.
+// The pings stop when ctx is canceled or the connection fails
+func (c WebsocketConfig) Dial(ctx context.Context, url string) (*websocket.Conn, error) {
+	conn, _, err := c.Dialer().DialContext(ctx, url, c.Headers.Clone())
+	if err != nil {
+		return nil, err
+	}
+	if c.PingInterval > 0 {
+		go keepAlive(ctx, conn, c.PingInterval)
+	}
+	return conn, nil
+}
+
+// keepAlive writes a ping frame every interval until ctx is canceled or writing fails, e.g. on a closed connection
+func keepAlive(ctx context.Context, conn *websocket.Conn, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// WriteControl is safe to call concurrently with the other methods of the connection
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(DefaultPingTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package exchanges
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebsocketConfig_Dialer(t *testing.T) {
+	dialer := WebsocketConfig{}.Dialer()
+	assert.Equal(t, websocket.DefaultDialer.HandshakeTimeout, dialer.HandshakeTimeout)
+	assert.False(t, dialer.EnableCompression)
+
+	dialer = WebsocketConfig{EnableCompression: true, HandshakeTimeout: 3 * time.Second, ReadBufferSize: 1 << 16}.Dialer()
+	assert.True(t, dialer.EnableCompression)
+	assert.Equal(t, 3*time.Second, dialer.HandshakeTimeout)
+	assert.Equal(t, 1<<16, dialer.ReadBufferSize)
+	assert.Zero(t, websocket.DefaultDialer.ReadBufferSize, "the default dialer is left untouched")
+}
+
+func TestWebsocketConfig_Dial(t *testing.T) {
+	pings := make(chan struct{}, 10)
+	headers := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		upgrader := websocket.Upgrader{EnableCompression: true}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetPingHandler(func(string) error {
+			pings <- struct{}{}
+			return nil
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cfg := WebsocketConfig{
+		EnableCompression: true,
+		Headers:           http.Header{"User-Agent": []string{"exchange-data-importer"}},
+		PingInterval:      10 * time.Millisecond,
+	}
+	conn, err := cfg.Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	header := <-headers
+	assert.Equal(t, "exchange-data-importer", header.Get("User-Agent"))
+	assert.Contains(t, header.Get("Sec-Websocket-Extensions"), "permessage-deflate")
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-pings:
+		case <-time.After(time.Second):
+			t.Fatal("no ping received")
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultChannelBuffer is the default capacity of the events channel of a ChannelNotifier
+const DefaultChannelBuffer = 1024
+
+// errChannelClosed is returned by the sends after Close
+var errChannelClosed = errors.New("channel notifier is closed")
+
+// ChannelNotifier delivers the events in-process on a channel, e.g. to the integration tests of another service
+// running the importer as a library. A send blocks while the channel is full until its context expires
+type ChannelNotifier struct {
+	events chan Event
+	done   chan struct{}
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+}
+
+// NewChannelNotifier creates a new ChannelNotifier buffering up to buffer events, 0 uses DefaultChannelBuffer
+func NewChannelNotifier(buffer int) *ChannelNotifier {
+	if buffer <= 0 {
+		buffer = DefaultChannelBuffer
+	}
+	return &ChannelNotifier{
+		events: make(chan Event, buffer),
+		done:   make(chan struct{}),
+	}
+}
+
+// Events returns the channel the events are delivered on, closed by Close
+func (n *ChannelNotifier) Events() <-chan Event {
+	return n.events
+}
+
+// Send delivers the event on the channel
+func (n *ChannelNotifier) Send(ctx context.Context, event Event) error {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return errChannelClosed
+	}
+
+	select {
+	case n.events <- event:
+		return nil
+	case <-n.done:
+		return errChannelClosed
+	case <-ctx.Done():
+		return fmt.Errorf("channel notifier is full (%d events): %w", cap(n.events), ctx.Err())
+	}
+}
+
+// Close closes the events channel once the pending sends returned, the events already buffered can still be read
+func (n *ChannelNotifier) Close() error {
+	n.closeOnce.Do(func() {
+		close(n.done)
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.closed = true
+		close(n.events)
+	})
+	return nil
+}
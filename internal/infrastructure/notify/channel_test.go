@@ -0,0 +1,43 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelNotifier_Send(t *testing.T) {
+	n := NewChannelNotifier(1)
+
+	require.NoError(t, n.Send(context.Background(), Event{EventType: "TICK_INFO", Data: "tick"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, n.Send(ctx, Event{EventType: "TICK_INFO"}), context.DeadlineExceeded, "a full channel blocks until the context expires")
+
+	event := <-n.Events()
+	assert.Equal(t, "TICK_INFO", event.EventType)
+	assert.Equal(t, "tick", event.Data)
+}
+
+func TestChannelNotifier_Close(t *testing.T) {
+	n := NewChannelNotifier(1)
+	require.NoError(t, n.Send(context.Background(), Event{EventType: "ALERTS"}))
+
+	blocked := make(chan error)
+	go func() { blocked <- n.Send(context.Background(), Event{EventType: "ALERTS"}) }()
+
+	require.NoError(t, n.Close())
+	require.NoError(t, n.Close(), "closing twice is a no-op")
+	assert.ErrorIs(t, <-blocked, errChannelClosed, "pending sends return on close")
+	assert.ErrorIs(t, n.Send(context.Background(), Event{}), errChannelClosed)
+
+	event, ok := <-n.Events()
+	assert.True(t, ok, "buffered events are still delivered")
+	assert.Equal(t, "ALERTS", event.EventType)
+	_, ok = <-n.Events()
+	assert.False(t, ok)
+}
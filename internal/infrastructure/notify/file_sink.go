@@ -0,0 +1,292 @@
+package notify
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+)
+
+const (
+	// DefaultFileSinkMaxBytes is the default size of a file before it's rotated
+	DefaultFileSinkMaxBytes = 100 << 20
+
+	// fileSinkTimeLayout is used in file names, so lexical order matches creation order
+	fileSinkTimeLayout = "20060102T150405.000000000Z"
+)
+
+// fileSinkHeader is written as the first row of every file
+var fileSinkHeader = []string{"time", "event_type", "data"}
+
+// FileSinkConfig configures a FileSinkNotifier
+type FileSinkConfig struct {
+	Dir       string        // directory the files are written to, created if missing
+	Prefix    string        // file name prefix, defaults to "events"
+	MaxBytes  int64         // rotate once this many CSV bytes (before compression) were written, 0 uses DefaultFileSinkMaxBytes
+	MaxAge    time.Duration // rotate once the file is older than this, 0 disables time-based rotation
+	Compress  bool          // gzip the files
+	Fsync     bool          // flush and fsync after every event
+	MaxFiles  int           // keep at most this many rotated files, 0 keeps all
+	Retention time.Duration // delete rotated files older than this, 0 keeps all
+}
+
+// FileSinkNotifier appends events as CSV rows to size and time rotated files.
+// Useful for deployments without a database or a broker. CSV is the only format, Parquet isn't implemented
+type FileSinkNotifier struct {
+	cfg   FileSinkConfig
+	ext   string
+	now   func() time.Time
+	codec codec.Codec
+
+	mu       sync.Mutex
+	file     *os.File
+	counter  *countingWriter
+	gz       *gzip.Writer
+	csv      *csv.Writer
+	openedAt time.Time
+	closed   bool
+}
+
+// NewFileSinkNotifier creates a new FileSinkNotifier; the first file is opened on the first event
+func NewFileSinkNotifier(cfg FileSinkConfig) (*FileSinkNotifier, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("file sink directory is required")
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = "events"
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultFileSinkMaxBytes
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating file sink directory: %w", err)
+	}
+
+	ext := ".csv"
+	if cfg.Compress {
+		ext += ".gz"
+	}
+	return &FileSinkNotifier{cfg: cfg, ext: ext, now: time.Now, codec: codec.JSON}, nil
+}
+
+// SetCodec sets the codec the event data is written with, JSON by default. The data of binary codecs is
+// written base64-encoded, so the files stay valid CSV
+func (n *FileSinkNotifier) SetCodec(c codec.Codec) error {
+	n.codec = c
+	return nil
+}
+
+// Send appends the event to the current file, rotating it first if needed
+func (n *FileSinkNotifier) Send(_ context.Context, event Event) error {
+	data, err := fileSinkData(n.codec, event.Data)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return fmt.Errorf("file sink is closed")
+	}
+	if n.shouldRotate() {
+		if err := n.rotate(); err != nil {
+			return err
+		}
+	}
+
+	eventTime := event.Time
+	if eventTime.IsZero() {
+		eventTime = n.now()
+	}
+	if err := n.csv.Write([]string{eventTime.UTC().Format(time.RFC3339Nano), event.EventType, data}); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+
+	if n.cfg.Fsync {
+		return n.sync()
+	}
+	return nil
+}
+
+// Close flushes and closes the current file
+func (n *FileSinkNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.closed = true
+	return n.closeFile()
+}
+
+// shouldRotate reports whether a new file has to be opened before writing
+func (n *FileSinkNotifier) shouldRotate() bool {
+	if n.file == nil {
+		return true
+	}
+	if n.cfg.MaxAge > 0 && n.now().Sub(n.openedAt) >= n.cfg.MaxAge {
+		return true
+	}
+	// the csv writer buffers up to 4KB, which is precise enough for rotation
+	return n.counter.n >= n.cfg.MaxBytes
+}
+
+// rotate closes the current file, opens a new one and applies the retention policy
+func (n *FileSinkNotifier) rotate() error {
+	if err := n.closeFile(); err != nil {
+		return err
+	}
+
+	n.openedAt = n.now()
+	path := filepath.Join(n.cfg.Dir, n.cfg.Prefix+"-"+n.openedAt.UTC().Format(fileSinkTimeLayout)+n.ext)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening file sink file: %w", err)
+	}
+
+	n.file = file
+	var w io.Writer = file
+	if n.cfg.Compress {
+		n.gz = gzip.NewWriter(file)
+		w = n.gz
+	}
+	n.counter = &countingWriter{w: w}
+	n.csv = csv.NewWriter(n.counter)
+
+	if err := n.csv.Write(fileSinkHeader); err != nil {
+		return fmt.Errorf("writing header: %w", err)
+	}
+
+	return n.applyRetention(filepath.Base(path))
+}
+
+// sync flushes the buffered writers and fsyncs the file
+func (n *FileSinkNotifier) sync() error {
+	n.csv.Flush()
+	if err := n.csv.Error(); err != nil {
+		return fmt.Errorf("flushing csv: %w", err)
+	}
+	if n.gz != nil {
+		if err := n.gz.Flush(); err != nil {
+			return fmt.Errorf("flushing gzip: %w", err)
+		}
+	}
+	if err := n.file.Sync(); err != nil {
+		return fmt.Errorf("syncing file: %w", err)
+	}
+	return nil
+}
+
+// closeFile flushes and closes the current file if any
+func (n *FileSinkNotifier) closeFile() error {
+	if n.file == nil {
+		return nil
+	}
+
+	var errs []error
+	n.csv.Flush()
+	errs = append(errs, n.csv.Error())
+	if n.gz != nil {
+		errs = append(errs, n.gz.Close())
+	}
+	errs = append(errs, n.file.Close())
+
+	n.file, n.counter, n.gz, n.csv = nil, nil, nil, nil
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("closing file sink file: %w", err)
+	}
+	return nil
+}
+
+// applyRetention deletes rotated files beyond MaxFiles or older than Retention; current is never deleted
+func (n *FileSinkNotifier) applyRetention(current string) error {
+	if n.cfg.MaxFiles <= 0 && n.cfg.Retention <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(n.cfg.Dir)
+	if err != nil {
+		return fmt.Errorf("listing file sink directory: %w", err)
+	}
+
+	var rotated []os.DirEntry
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == current || !n.isSinkFile(entry.Name()) {
+			continue
+		}
+		rotated = append(rotated, entry)
+	}
+	// newest first
+	slices.SortFunc(rotated, func(a, b os.DirEntry) int { return strings.Compare(b.Name(), a.Name()) })
+
+	var errs []error
+	for i, entry := range rotated {
+		expired := n.cfg.MaxFiles > 0 && i >= n.cfg.MaxFiles-1 // the current file counts towards MaxFiles
+		if !expired && n.cfg.Retention > 0 {
+			if info, err := entry.Info(); err == nil && n.now().Sub(info.ModTime()) > n.cfg.Retention {
+				expired = true
+			}
+		}
+		if expired {
+			if err := os.Remove(filepath.Join(n.cfg.Dir, entry.Name())); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("applying file sink retention: %w", err)
+	}
+	return nil
+}
+
+// isSinkFile reports whether the name is one the sink gives its files, <prefix>-<time><ext>, so the other files
+// of the directory, e.g. of a sink with a longer prefix, are left alone
+func (n *FileSinkNotifier) isSinkFile(name string) bool {
+	stamp, ok := strings.CutPrefix(name, n.cfg.Prefix+"-")
+	if !ok {
+		return false
+	}
+	if stamp, ok = strings.CutSuffix(stamp, n.ext); !ok {
+		return false
+	}
+	_, err := time.Parse(fileSinkTimeLayout, stamp)
+	return err == nil
+}
+
+// fileSinkData renders the event data as a CSV cell: strings as is, anything else encoded with the codec,
+// in base64 unless the codec is JSON
+func fileSinkData(c codec.Codec, data any) (string, error) {
+	if str, ok := data.(string); ok {
+		return str, nil
+	}
+	encoded, err := c.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("marshaling event data: %w", err)
+	}
+	if c == codec.JSON {
+		return string(encoded), nil
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// countingWriter counts the bytes written to the underlying writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	written, err := c.w.Write(p)
+	c.n += int64(written)
+	return written, err
+}
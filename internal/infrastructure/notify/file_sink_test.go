@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readSinkFiles returns the CSV records of every file in dir, in creation order
+func readSinkFiles(t *testing.T, dir string, compressed bool) [][][]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var files [][][]string
+	for _, entry := range entries {
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		require.NoError(t, err)
+
+		var r *csv.Reader
+		if compressed {
+			gz, err := gzip.NewReader(f)
+			require.NoError(t, err)
+			r = csv.NewReader(gz)
+		} else {
+			r = csv.NewReader(f)
+		}
+		records, err := r.ReadAll()
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		files = append(files, records)
+	}
+	return files
+}
+
+// testClock is a manually advanced clock
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func TestFileSinkNotifier_WritesEvents(t *testing.T) {
+	for _, compress := range []bool{false, true} {
+		t.Run(map[bool]string{false: "plain", true: "gzip"}[compress], func(t *testing.T) {
+			dir := t.TempDir()
+			n, err := NewFileSinkNotifier(FileSinkConfig{Dir: dir, Compress: compress, Fsync: true})
+			require.NoError(t, err)
+
+			eventTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+			require.NoError(t, n.Send(context.Background(), Event{Time: eventTime, EventType: "TICK_INFO", Data: "a,b\nc"}))
+			require.NoError(t, n.Send(context.Background(), Event{Time: eventTime, EventType: "MARKET_DATA", Data: map[string]int{"ll_1": 3}}))
+			require.NoError(t, n.Close())
+
+			assert.Error(t, n.Send(context.Background(), Event{}), "closed sink must reject events")
+
+			files := readSinkFiles(t, dir, compress)
+			require.Len(t, files, 1)
+			assert.Equal(t, [][]string{
+				fileSinkHeader,
+				{"2024-01-01T12:00:00Z", "TICK_INFO", "a,b\nc"},
+				{"2024-01-01T12:00:00Z", "MARKET_DATA", `{"ll_1":3}`},
+			}, files[0])
+		})
+	}
+}
+
+func TestFileSinkNotifier_Codec(t *testing.T) {
+	dir := t.TempDir()
+	n, err := NewFileSinkNotifier(FileSinkConfig{Dir: dir})
+	require.NoError(t, err)
+	require.NoError(t, n.SetCodec(codec.CBOR))
+
+	require.NoError(t, n.Send(context.Background(), Event{EventType: "TICK_INFO", Data: "text"}))
+	require.NoError(t, n.Send(context.Background(), Event{EventType: "MARKET_DATA", Data: map[string]int{"ll_1": 3}}))
+	require.NoError(t, n.Close())
+
+	files := readSinkFiles(t, dir, false)
+	require.Len(t, files, 1)
+	require.Len(t, files[0], 3)
+	assert.Equal(t, "text", files[0][1][2], "strings are written as is")
+
+	data, err := base64.StdEncoding.DecodeString(files[0][2][2])
+	require.NoError(t, err)
+	var decoded map[string]int
+	require.NoError(t, codec.CBOR.Unmarshal(data, &decoded))
+	assert.Equal(t, map[string]int{"ll_1": 3}, decoded)
+}
+
+func TestFileSinkNotifier_Rotation(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       FileSinkConfig
+		clockStep time.Duration
+		wantFiles int
+	}{
+		{
+			name:      "by size",
+			cfg:       FileSinkConfig{MaxBytes: 1, Fsync: true},
+			clockStep: time.Millisecond,
+			wantFiles: 5,
+		},
+		{
+			name:      "by age",
+			cfg:       FileSinkConfig{MaxAge: 2 * time.Second},
+			clockStep: time.Second, // files opened at 0s, 2s and 4s
+			wantFiles: 3,
+		},
+		{
+			name:      "keeps max files",
+			cfg:       FileSinkConfig{MaxBytes: 1, MaxFiles: 2, Fsync: true},
+			clockStep: time.Millisecond,
+			wantFiles: 2,
+		},
+		{
+			name:      "deletes expired files",
+			cfg:       FileSinkConfig{MaxBytes: 1, Retention: time.Millisecond, Fsync: true},
+			clockStep: time.Hour,
+			wantFiles: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Dir = t.TempDir()
+			n, err := NewFileSinkNotifier(tt.cfg)
+			require.NoError(t, err)
+			clock := &testClock{now: time.Now()}
+			n.now = clock.Now
+
+			for range 5 {
+				require.NoError(t, n.Send(context.Background(), Event{EventType: "TICK_INFO", Data: "tick"}))
+				clock.now = clock.now.Add(tt.clockStep)
+			}
+			require.NoError(t, n.Close())
+
+			files := readSinkFiles(t, tt.cfg.Dir, false)
+			assert.Len(t, files, tt.wantFiles)
+			for _, records := range files {
+				assert.Equal(t, fileSinkHeader, records[0])
+			}
+		})
+	}
+}
+
+func TestFileSinkNotifier_RetentionKeepsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	others := []string{"events-archive.csv", "events-eu-20250101T000000.000000000Z.csv", "events-20250101T000000.000000000Z.csv.bak"}
+	for _, name := range others {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("kept"), 0o644))
+	}
+
+	n, err := NewFileSinkNotifier(FileSinkConfig{Dir: dir, MaxBytes: 1, MaxFiles: 1, Fsync: true})
+	require.NoError(t, err)
+	for range 3 {
+		require.NoError(t, n.Send(context.Background(), Event{EventType: "TICK_INFO", Data: "tick"}))
+	}
+	require.NoError(t, n.Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Len(t, names, len(others)+1, "only the rotated files of the sink are deleted")
+	for _, name := range others {
+		assert.Contains(t, names, name)
+	}
+}
+
+func TestNewFileSinkNotifier_RequiresDir(t *testing.T) {
+	_, err := NewFileSinkNotifier(FileSinkConfig{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Annotation is the event data of a GrafanaNotifier whose annotations are updated in place
+type Annotation interface {
+	// AnnotationKey identifies the annotations of the same event, e.g. the fingerprint of an alert
+	AnnotationKey() string
+	// AnnotationFinal reports whether the annotation is the last of its key, e.g. the region of a resolved alert
+	AnnotationFinal() bool
+}
+
+// GrafanaNotifier posts the event data to the annotations HTTP API of Grafana. The data implementing Annotation
+// updates the annotation posted for its key instead of adding another one, so a resolved alert turns the mark
+// of its start into a region; other data is always posted
+type GrafanaNotifier struct {
+	url   string // of the annotations API, e.g. http://grafana:3000/api/annotations
+	token string
+
+	mu  sync.Mutex
+	ids map[string]int64 // annotation IDs of the keys not final yet
+
+	retries *retryQueue
+}
+
+// NewGrafanaNotifier creates a new GrafanaNotifier for the Grafana at url; a non-empty token is sent as a bearer token
+func NewGrafanaNotifier(url, token string) (*GrafanaNotifier, error) {
+	if url == "" {
+		return nil, fmt.Errorf("grafana url is required")
+	}
+
+	n := &GrafanaNotifier{url: strings.TrimSuffix(url, "/") + "/api/annotations", token: token, ids: make(map[string]int64)}
+	return n.WithRetry(DefaultRetryConfig()), nil
+}
+
+// WithRetry replaces the retry queue configuration; a zero QueueSize disables retries
+func (n *GrafanaNotifier) WithRetry(cfg RetryConfig) *GrafanaNotifier {
+	if n.retries != nil {
+		n.retries.close()
+	}
+	n.retries = newRetryQueue(cfg, n.annotate)
+	return n
+}
+
+// Close stops the retry worker
+func (n *GrafanaNotifier) Close() error {
+	n.retries.close()
+	return nil
+}
+
+// Send posts the annotation, or updates the one of its key
+func (n *GrafanaNotifier) Send(ctx context.Context, event Event) error {
+	err := n.annotate(ctx, event)
+	if err != nil && isTemporary(err) && n.retries.enabled() {
+		n.retries.push(event)
+		return fmt.Errorf("%w: %w", ErrQueuedForRetry, err)
+	}
+	return err
+}
+
+// annotate posts a new annotation, or patches the annotation posted for the key of the event data
+func (n *GrafanaNotifier) annotate(ctx context.Context, event Event) error {
+	annotation, keyed := event.Data.(Annotation)
+	if !keyed {
+		_, err := n.request(ctx, http.MethodPost, n.url, event.Data)
+		return err
+	}
+
+	key := annotation.AnnotationKey()
+	n.mu.Lock()
+	id, posted := n.ids[key]
+	n.mu.Unlock()
+
+	if posted {
+		if _, err := n.request(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", n.url, id), event.Data); err != nil {
+			return err
+		}
+	} else {
+		created, err := n.request(ctx, http.MethodPost, n.url, event.Data)
+		if err != nil {
+			return err
+		}
+		id = created
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if annotation.AnnotationFinal() {
+		delete(n.ids, key)
+	} else {
+		n.ids[key] = id
+	}
+	return nil
+}
+
+// request sends the JSON encoded data and returns the ID of the annotation in the response, 0 without one
+func (n *GrafanaNotifier) request(ctx context.Context, method, url string, data any) (int64, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling grafana annotation: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("creating grafana request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, temporaryError{err: fmt.Errorf("sending grafana annotation: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return 0, temporaryError{err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return 0, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		ID int64 `json:"id"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&response)
+	return response.ID, nil
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAnnotation is an annotation of an alert
+type testAnnotation struct {
+	Text     string `json:"text"`
+	key      string
+	resolved bool
+}
+
+func (a testAnnotation) AnnotationKey() string { return a.key }
+func (a testAnnotation) AnnotationFinal() bool { return a.resolved }
+
+func TestNewGrafanaNotifier_RequiresURL(t *testing.T) {
+	_, err := NewGrafanaNotifier("", "token")
+	assert.Error(t, err)
+}
+
+func TestGrafanaNotifier_UpdatesAnnotationsInPlace(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.Method == http.MethodPost {
+			_, _ = io.WriteString(w, `{"id":42,"message":"Annotation added"}`)
+		}
+	}))
+	defer srv.Close()
+
+	n, err := NewGrafanaNotifier(srv.URL+"/", "secret")
+	require.NoError(t, err)
+	defer n.Close()
+
+	send := func(data any) {
+		t.Helper()
+		require.NoError(t, n.Send(context.Background(), Event{EventType: "ANNOTATIONS", Data: data}))
+	}
+	send(testAnnotation{Text: "firing", key: "a"})
+	send(testAnnotation{Text: "resolved", key: "a", resolved: true})
+	send(testAnnotation{Text: "firing again", key: "a"})
+	send(map[string]string{"text": "unkeyed"})
+
+	assert.Equal(t, []string{
+		`POST /api/annotations {"text":"firing"}`,
+		`PATCH /api/annotations/42 {"text":"resolved"}`,
+		`POST /api/annotations {"text":"firing again"}`,
+		`POST /api/annotations {"text":"unkeyed"}`,
+	}, requests, "the resolved alert updates the annotation of its start, the next firing is a new one")
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// InfluxNotifier writes line protocol events to the InfluxDB v2 write API
+type InfluxNotifier struct {
+	writeURL string
+	token    string
+
+	retries *retryQueue
+}
+
+// NewInfluxNotifier creates a new InfluxNotifier
+func NewInfluxNotifier(baseURL, org, bucket, token string) (*InfluxNotifier, error) {
+	if baseURL == "" || org == "" || bucket == "" {
+		return nil, fmt.Errorf("influx url, org and bucket are required")
+	}
+
+	params := url.Values{}
+	params.Add("org", org)
+	params.Add("bucket", bucket)
+	params.Add("precision", "ms")
+
+	n := &InfluxNotifier{
+		writeURL: strings.TrimRight(baseURL, "/") + "/api/v2/write?" + params.Encode(),
+		token:    token,
+	}
+	return n.WithRetry(DefaultRetryConfig()), nil
+}
+
+// WithRetry replaces the retry queue configuration; a zero QueueSize disables retries
+func (n *InfluxNotifier) WithRetry(cfg RetryConfig) *InfluxNotifier {
+	if n.retries != nil {
+		n.retries.close()
+	}
+	n.retries = newRetryQueue(cfg, n.write)
+	return n
+}
+
+// Close stops the retry worker
+func (n *InfluxNotifier) Close() error {
+	n.retries.close()
+	return nil
+}
+
+// Send writes the event points to InfluxDB
+func (n *InfluxNotifier) Send(ctx context.Context, event Event) error {
+	err := n.write(ctx, event)
+	if err != nil && isTemporary(err) && n.retries.enabled() {
+		n.retries.push(event)
+		return fmt.Errorf("%w: %w", ErrQueuedForRetry, err)
+	}
+	return err
+}
+
+// write posts the line protocol body to the write endpoint
+func (n *InfluxNotifier) write(ctx context.Context, event Event) error {
+	lines, ok := event.Data.(string)
+	if !ok {
+		return fmt.Errorf("influx notifier expects string data, got %T", event.Data)
+	}
+	if lines == "" {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.writeURL, strings.NewReader(lines))
+	if err != nil {
+		return fmt.Errorf("creating influx request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Token "+n.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return temporaryError{err: fmt.Errorf("writing influx points: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return temporaryError{err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+	// the write API answers 204 No Content on success
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
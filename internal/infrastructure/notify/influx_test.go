@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInfluxNotifier_RequiresConfig(t *testing.T) {
+	_, err := NewInfluxNotifier("", "org", "bucket", "token")
+	assert.Error(t, err)
+
+	_, err = NewInfluxNotifier("http://localhost:8086", "org", "", "token")
+	assert.Error(t, err)
+}
+
+func TestInfluxNotifier_Send(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		data       any
+		wantErr    bool
+		wantQueued bool
+	}{
+		{name: "written", status: http.StatusNoContent, data: "tick ll_1=1i 1700000000000\n"},
+		{name: "bad request", status: http.StatusBadRequest, data: "tick ll_1=1i 1", wantErr: true},
+		{name: "server error is retried", status: http.StatusServiceUnavailable, data: "tick ll_1=1i 1", wantErr: true, wantQueued: true},
+		{name: "non-string data", status: http.StatusNoContent, data: 42, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq *http.Request
+			var gotBody string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotReq, gotBody = r, string(body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			n, err := NewInfluxNotifier(srv.URL+"/", "acme", "ticks", "secret")
+			require.NoError(t, err)
+			n.WithRetry(RetryConfig{})
+			if tt.wantQueued {
+				n.WithRetry(testRetryConfig())
+			}
+			defer n.Close()
+
+			err = n.Send(context.Background(), Event{EventType: "TIME_SERIES", Data: tt.data})
+			if !tt.wantErr {
+				require.NoError(t, err)
+				require.NotNil(t, gotReq)
+				assert.Equal(t, "/api/v2/write", gotReq.URL.Path)
+				assert.Equal(t, "acme", gotReq.URL.Query().Get("org"))
+				assert.Equal(t, "ticks", gotReq.URL.Query().Get("bucket"))
+				assert.Equal(t, "ms", gotReq.URL.Query().Get("precision"))
+				assert.Equal(t, "Token secret", gotReq.Header.Get("Authorization"))
+				assert.Equal(t, tt.data, gotBody)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantQueued, isTemporary(err))
+		})
+	}
+}
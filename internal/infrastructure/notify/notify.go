@@ -20,7 +20,25 @@ type Client interface {
 	Send(ctx context.Context, event Event) error
 }
 
+// BatchClient is a Client delivering several events at once, e.g. in a single round trip
+type BatchClient interface {
+	Client
+	SendBatch(ctx context.Context, events []Event) error
+}
+
 // Strategy defines how to format data for notifications
 type Strategy interface {
 	Format(data any) []Event
 }
+
+// HistoryStrategy is a Strategy consulting the data formatted before, e.g. to require a condition to persist for
+// several ticks before alerting, instead of keeping its own state
+type HistoryStrategy interface {
+	Strategy
+
+	// HistorySize returns the number of previous data points the strategy consults
+	HistorySize() int
+
+	// FormatWithHistory formats data given up to HistorySize previous data points of its type, oldest first
+	FormatWithHistory(data any, history []any) []Event
+}
@@ -3,35 +3,157 @@ package notify
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
 	"github.com/redis/go-redis/v9"
 )
 
+// DefaultRedisPipelineSize is the maximum number of events published in a single Redis pipeline
+const DefaultRedisPipelineSize = 100
+
 // RedisNotifier is a Redis-based implementation of domain.NotificationService
 type RedisNotifier struct {
-	client  *redis.Client
-	channel string
+	client    redis.UniversalClient
+	channel   string
+	pipeline  int
+	retries   *retryQueue
+	telemetry telemetry.Provider
+}
+
+// NewRedisNotifier creates a new RedisNotifier publishing with a standalone, cluster or sentinel client
+func NewRedisNotifier(client redis.UniversalClient, channel string) *RedisNotifier {
+	n := &RedisNotifier{
+		client:    client,
+		channel:   channel,
+		pipeline:  DefaultRedisPipelineSize,
+		telemetry: &telemetry.NoopProvider{},
+	}
+	return n.WithRetry(DefaultRetryConfig())
+}
+
+// WithRetry replaces the retry queue configuration; a zero QueueSize disables retries
+func (p *RedisNotifier) WithRetry(cfg RetryConfig) *RedisNotifier {
+	if p.retries != nil {
+		p.retries.close()
+	}
+	p.retries = newRetryQueue(cfg, p.publish)
+	return p
+}
+
+// WithPipeline sets the maximum number of events published in a single pipeline by SendBatch;
+// 0 or 1 publishes every event in its own round trip
+func (p *RedisNotifier) WithPipeline(size int) *RedisNotifier {
+	p.pipeline = size
+	return p
 }
 
-// NewRedisNotifier creates a new RedisNotifier
-func NewRedisNotifier(client *redis.Client, channel string) *RedisNotifier {
-	return &RedisNotifier{
-		client:  client,
-		channel: channel,
+// WithTelemetry sets the telemetry provider reporting the publish latency
+func (p *RedisNotifier) WithTelemetry(provider telemetry.Provider) *RedisNotifier {
+	if provider != nil {
+		p.telemetry = provider
 	}
+	return p
 }
 
 // Send event to the listeners
 func (p *RedisNotifier) Send(ctx context.Context, event Event) error {
+	err := p.publish(ctx, event)
+	if err != nil && isTemporary(err) && p.retries.enabled() {
+		p.retries.push(event)
+		return fmt.Errorf("%w: %w", ErrQueuedForRetry, err)
+	}
+	return err
+}
+
+// SendBatch publishes the events in order, in pipelines of up to the pipeline size: a round trip per pipeline
+// instead of per event. The events of a failed pipeline are queued for a retry
+func (p *RedisNotifier) SendBatch(ctx context.Context, events []Event) error {
+	if p.pipeline <= 1 {
+		var errs []error
+		for _, event := range events {
+			errs = append(errs, p.Send(ctx, event))
+		}
+		return errors.Join(errs...)
+	}
+
+	var errs []error
+	for chunk := range slices.Chunk(events, p.pipeline) {
+		failed, err := p.publishPipeline(ctx, chunk)
+		if err != nil && isTemporary(err) && p.retries.enabled() {
+			for _, event := range failed {
+				p.retries.push(event)
+			}
+			err = fmt.Errorf("%w: %w", ErrQueuedForRetry, err)
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// RetryStats returns the number of events waiting for a retry and the number of events dropped by the retry queue
+func (p *RedisNotifier) RetryStats() (queued int, dropped int64) {
+	return p.retries.Len(), p.retries.Dropped()
+}
+
+// Close stops the retry worker
+func (p *RedisNotifier) Close() error {
+	p.retries.close()
+	return nil
+}
+
+// publish marshals the event and publishes it to the channel
+func (p *RedisNotifier) publish(ctx context.Context, event Event) error {
 	data, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("marshaling event: %w", err)
 	}
 
-	if err := p.client.Publish(ctx, p.channel, data).Err(); err != nil {
-		return fmt.Errorf("publishing to Redis: %w", err)
+	start := time.Now()
+	err = p.client.Publish(ctx, p.channel, data).Err()
+	p.telemetry.Timing(telemetryRedisPublishDuration, time.Since(start), "channel:"+p.channel, "mode:single")
+	if err != nil {
+		return temporaryError{err: fmt.Errorf("publishing to Redis: %w", err)}
 	}
 
 	return nil
 }
+
+// publishPipeline publishes the events in a single pipeline and returns the events that failed
+func (p *RedisNotifier) publishPipeline(ctx context.Context, events []Event) ([]Event, error) {
+	payloads := make([][]byte, len(events))
+	for i, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling event: %w", err)
+		}
+		payloads[i] = data
+	}
+
+	start := time.Now()
+	cmds, err := p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, data := range payloads {
+			pipe.Publish(ctx, p.channel, data)
+		}
+		return nil
+	})
+	p.telemetry.Timing(telemetryRedisPublishDuration, time.Since(start), "channel:"+p.channel, "mode:pipeline")
+	p.telemetry.Gauge(telemetryRedisPipelineSize, float64(len(events)), "channel:"+p.channel)
+	if err == nil {
+		return nil, nil
+	}
+
+	var failed []Event
+	for i, cmd := range cmds {
+		if cmd.Err() != nil && i < len(events) {
+			failed = append(failed, events[i])
+		}
+	}
+	if len(failed) == 0 {
+		failed = events
+	}
+	return failed, temporaryError{err: fmt.Errorf("publishing %d of %d events to Redis: %w", len(failed), len(events), err)}
+}
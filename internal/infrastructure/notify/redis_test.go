@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedis returns a client of an in-memory Redis and a subscription to the channel
+func newTestRedis(t *testing.T, channel string) (*miniredis.Miniredis, *redis.Client, <-chan *redis.Message) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+
+	sub := client.Subscribe(context.Background(), channel)
+	t.Cleanup(func() { sub.Close() })
+	_, err := sub.Receive(context.Background()) // wait for the subscription
+	require.NoError(t, err)
+	return server, client, sub.Channel()
+}
+
+// receive returns the event types of the next n messages
+func receive(t *testing.T, messages <-chan *redis.Message, n int) []string {
+	t.Helper()
+	var types []string
+	for range n {
+		select {
+		case msg := <-messages:
+			var event Event
+			require.NoError(t, json.Unmarshal([]byte(msg.Payload), &event))
+			types = append(types, event.EventType)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d messages", len(types), n)
+		}
+	}
+	return types
+}
+
+func TestRedisNotifier_Send(t *testing.T) {
+	_, client, messages := newTestRedis(t, "importer:TICK_INFO")
+	n := NewRedisNotifier(client, "importer:TICK_INFO")
+	defer n.Close()
+
+	require.NoError(t, n.Send(context.Background(), Event{EventType: "TICK_INFO"}))
+	assert.Equal(t, []string{"TICK_INFO"}, receive(t, messages, 1))
+}
+
+func TestRedisNotifier_SendBatch(t *testing.T) {
+	for _, pipeline := range []int{1, 2, DefaultRedisPipelineSize} {
+		t.Run(fmt.Sprintf("pipeline %d", pipeline), func(t *testing.T) {
+			_, client, messages := newTestRedis(t, "importer:MARKET_DATA")
+			n := NewRedisNotifier(client, "importer:MARKET_DATA").WithPipeline(pipeline)
+			defer n.Close()
+
+			var events []Event
+			var want []string
+			for i := range 5 {
+				events = append(events, Event{EventType: fmt.Sprintf("MARKET_DATA_%d", i)})
+				want = append(want, fmt.Sprintf("MARKET_DATA_%d", i))
+			}
+			require.NoError(t, n.SendBatch(context.Background(), events))
+			assert.Equal(t, want, receive(t, messages, 5), "the events are published in order")
+		})
+	}
+}
+
+func TestRedisNotifier_SendBatchQueuesFailedPipeline(t *testing.T) {
+	server, client, _ := newTestRedis(t, "importer:MARKET_DATA")
+	n := NewRedisNotifier(client, "importer:MARKET_DATA").WithPipeline(2)
+	defer n.Close()
+	server.Close()
+
+	err := n.SendBatch(context.Background(), []Event{{EventType: "A"}, {EventType: "B"}, {EventType: "C"}})
+	require.ErrorIs(t, err, ErrQueuedForRetry)
+	queued, _ := n.RetryStats()
+	assert.Equal(t, 3, queued)
+}
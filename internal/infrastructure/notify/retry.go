@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQueuedForRetry is returned by Send when delivery failed with a transient error and the event was queued for a retry
+var ErrQueuedForRetry = errors.New("event queued for retry")
+
+// RetryConfig configures the retry queue of a notifier
+type RetryConfig struct {
+	QueueSize      int           // maximum number of events waiting for a retry, 0 disables retries
+	MaxAge         time.Duration // events older than this are dropped instead of retried
+	InitialBackoff time.Duration // delay before the first retry
+	MaxBackoff     time.Duration // upper bound of the exponential backoff
+	SendTimeout    time.Duration // timeout of a single retry attempt
+}
+
+// DefaultRetryConfig returns the retry configuration used by notifiers unless overridden
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		QueueSize:      100,
+		MaxAge:         time.Minute,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		SendTimeout:    5 * time.Second,
+	}
+}
+
+// temporaryError marks delivery failures that are worth retrying (rate limits, connection problems)
+type temporaryError struct {
+	err error
+}
+
+func (e temporaryError) Error() string { return e.err.Error() }
+func (e temporaryError) Unwrap() error { return e.err }
+
+// isTemporary reports whether the error was marked as retryable
+func isTemporary(err error) bool {
+	var tErr temporaryError
+	return errors.As(err, &tErr)
+}
+
+type retryItem struct {
+	id         uint64
+	event      Event
+	enqueuedAt time.Time
+}
+
+// retryQueue keeps failed events in a bounded FIFO and redelivers them in the background with exponential backoff.
+// The oldest event is dropped when the queue is full, so a long outage can't grow memory unbounded.
+type retryQueue struct {
+	cfg     RetryConfig
+	deliver func(ctx context.Context, event Event) error
+
+	mu      sync.Mutex
+	items   []retryItem
+	nextID  uint64
+	dropped int64
+
+	wake      chan struct{}
+	startOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+}
+
+func newRetryQueue(cfg RetryConfig, deliver func(ctx context.Context, event Event) error) *retryQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &retryQueue{
+		cfg:     cfg,
+		deliver: deliver,
+		wake:    make(chan struct{}, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// enabled reports whether retries are configured
+func (q *retryQueue) enabled() bool {
+	return q != nil && q.cfg.QueueSize > 0
+}
+
+// push adds an event to the queue and makes sure the background worker is running
+func (q *retryQueue) push(event Event) {
+	q.startOnce.Do(func() { go q.run() })
+
+	q.mu.Lock()
+	if len(q.items) >= q.cfg.QueueSize {
+		q.items = q.items[1:]
+		q.dropped++
+	}
+	q.nextID++
+	q.items = append(q.items, retryItem{id: q.nextID, event: event, enqueuedAt: time.Now()})
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Len returns the number of events waiting for a retry
+func (q *retryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Dropped returns the number of events discarded because the queue was full or they expired
+func (q *retryQueue) Dropped() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// close stops the background worker; pending events are discarded
+func (q *retryQueue) close() {
+	q.cancel()
+}
+
+// run redelivers queued events until the queue is closed
+func (q *retryQueue) run() {
+	backoff := q.cfg.InitialBackoff
+	for {
+		item, ok := q.peek()
+		if !ok {
+			select {
+			case <-q.ctx.Done():
+				return
+			case <-q.wake:
+				continue
+			}
+		}
+
+		if time.Since(item.enqueuedAt) > q.cfg.MaxAge {
+			q.pop(item.id, true)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(q.ctx, q.cfg.SendTimeout)
+		err := q.deliver(ctx, item.event)
+		cancel()
+
+		if err == nil || !isTemporary(err) {
+			// delivered, or failed in a way a retry won't fix
+			q.pop(item.id, err != nil)
+			backoff = q.cfg.InitialBackoff
+			continue
+		}
+
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, q.cfg.MaxBackoff)
+	}
+}
+
+func (q *retryQueue) peek() (retryItem, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return retryItem{}, false
+	}
+	return q.items[0], true
+}
+
+// pop removes the item from the head of the queue unless it was already evicted by push
+func (q *retryQueue) pop(id uint64, dropped bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 || q.items[0].id != id {
+		return
+	}
+	q.items = q.items[1:]
+	if dropped {
+		q.dropped++
+	}
+}
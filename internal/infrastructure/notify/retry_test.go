@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryConfig() RetryConfig {
+	return RetryConfig{
+		QueueSize:      2,
+		MaxAge:         time.Second,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		SendTimeout:    100 * time.Millisecond,
+	}
+}
+
+func TestRetryQueue_RedeliversTemporaryFailures(t *testing.T) {
+	var attempts atomic.Int32
+	q := newRetryQueue(testRetryConfig(), func(_ context.Context, _ Event) error {
+		if attempts.Add(1) < 3 {
+			return temporaryError{err: errors.New("rate limited")}
+		}
+		return nil
+	})
+	defer q.close()
+
+	q.push(Event{EventType: "test"})
+
+	assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, int64(0), q.Dropped())
+}
+
+func TestRetryQueue_DropsPermanentFailures(t *testing.T) {
+	var attempts atomic.Int32
+	q := newRetryQueue(testRetryConfig(), func(_ context.Context, _ Event) error {
+		attempts.Add(1)
+		return errors.New("bad request")
+	})
+	defer q.close()
+
+	q.push(Event{EventType: "test"})
+
+	assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), attempts.Load())
+	assert.Equal(t, int64(1), q.Dropped())
+}
+
+func TestRetryQueue_BoundedAndExpiring(t *testing.T) {
+	cfg := testRetryConfig()
+	cfg.MaxAge = 20 * time.Millisecond
+	q := newRetryQueue(cfg, func(_ context.Context, _ Event) error {
+		return temporaryError{err: errors.New("connection refused")}
+	})
+	defer q.close()
+
+	for i := 0; i < 5; i++ {
+		q.push(Event{EventType: "test"})
+	}
+	assert.LessOrEqual(t, q.Len(), cfg.QueueSize, "queue must not grow beyond its size")
+
+	assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, time.Millisecond, "expired events should be dropped")
+	assert.Equal(t, int64(5), q.Dropped())
+}
+
+func TestRedisNotifier_WithRetryDisabled(t *testing.T) {
+	n := &RedisNotifier{}
+	n.WithRetry(RetryConfig{})
+	defer n.Close()
+
+	assert.False(t, n.retries.enabled())
+}
@@ -21,6 +21,8 @@ type TelegramNotifier struct {
 	interval     time.Duration
 	lastSentTime time.Time
 	mu           sync.Mutex
+
+	retries *retryQueue
 }
 
 // NewTelegramNotifier creates a new TelegramNotifier
@@ -33,13 +35,34 @@ func NewTelegramNotifier(botToken, chatID string, intervalSeconds int) (*Telegra
 		intervalSeconds = MinIntervalSeconds
 	}
 
-	return &TelegramNotifier{
+	t := &TelegramNotifier{
 		botToken: botToken,
 		chatID:   chatID,
 		baseURL:  "https://api.telegram.org/bot",
 
 		interval: time.Duration(intervalSeconds) * time.Second,
-	}, nil
+	}
+	return t.WithRetry(DefaultRetryConfig()), nil
+}
+
+// WithRetry replaces the retry queue configuration; a zero QueueSize disables retries
+func (t *TelegramNotifier) WithRetry(cfg RetryConfig) *TelegramNotifier {
+	if t.retries != nil {
+		t.retries.close()
+	}
+	t.retries = newRetryQueue(cfg, t.sendMessage)
+	return t
+}
+
+// RetryStats returns the number of events waiting for a retry and the number of events dropped by the retry queue
+func (t *TelegramNotifier) RetryStats() (queued int, dropped int64) {
+	return t.retries.Len(), t.retries.Dropped()
+}
+
+// Close stops the retry worker
+func (t *TelegramNotifier) Close() error {
+	t.retries.close()
+	return nil
 }
 
 // Send sends a notification to a Telegram chat
@@ -53,6 +76,17 @@ func (t *TelegramNotifier) Send(ctx context.Context, event Event) error {
 	t.lastSentTime = now
 	t.mu.Unlock()
 
+	err := t.sendMessage(ctx, event)
+	if err != nil && isTemporary(err) && t.retries.enabled() {
+		// the event already passed the rate limit, so the retry bypasses it
+		t.retries.push(event)
+		return fmt.Errorf("%w: %w", ErrQueuedForRetry, err)
+	}
+	return err
+}
+
+// sendMessage posts the event to the Telegram API
+func (t *TelegramNotifier) sendMessage(ctx context.Context, event Event) error {
 	message, ok := event.Data.(string)
 	if !ok {
 		return fmt.Errorf("telegram notifier expects string data, got %T", event.Data)
@@ -72,10 +106,13 @@ func (t *TelegramNotifier) Send(ctx context.Context, event Event) error {
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("sending telegram message: %w", err)
+		return temporaryError{err: fmt.Errorf("sending telegram message: %w", err)}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return temporaryError{err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
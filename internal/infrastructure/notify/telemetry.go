@@ -0,0 +1,21 @@
+package notify
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for timings and gauges
+const (
+	// telemetryRedisPublishDuration measures a Redis publish round trip, tagged with the channel and the mode
+	// (single for a PUBLISH, pipeline for a pipeline of them)
+	telemetryRedisPublishDuration = "notifier.redis.publish.duration"
+
+	// telemetryRedisPipelineSize reports the number of events of the last Redis pipeline, tagged with the channel
+	telemetryRedisPipelineSize = "notifier.redis.pipeline.size"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryRedisPublishDuration, Kind: telemetry.KindTiming, Description: "Duration of a Redis publish round trip, a single PUBLISH or a pipeline of them", Tags: []string{"channel", "mode"}},
+		{Name: telemetryRedisPipelineSize, Kind: telemetry.KindGauge, Description: "Events published by the last Redis pipeline", Tags: []string{"channel"}},
+	}
+}
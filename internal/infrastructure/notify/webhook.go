@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// WebhookNotifier posts the event data as JSON to an HTTP endpoint, e.g. an Alertmanager webhook receiver
+type WebhookNotifier struct {
+	url   string
+	token string
+
+	retries *retryQueue
+}
+
+// NewWebhookNotifier creates a new WebhookNotifier; a non-empty token is sent as a bearer token
+func NewWebhookNotifier(url, token string) (*WebhookNotifier, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook url is required")
+	}
+
+	n := &WebhookNotifier{url: url, token: token}
+	return n.WithRetry(DefaultRetryConfig()), nil
+}
+
+// WithRetry replaces the retry queue configuration; a zero QueueSize disables retries
+func (n *WebhookNotifier) WithRetry(cfg RetryConfig) *WebhookNotifier {
+	if n.retries != nil {
+		n.retries.close()
+	}
+	n.retries = newRetryQueue(cfg, n.post)
+	return n
+}
+
+// Close stops the retry worker
+func (n *WebhookNotifier) Close() error {
+	n.retries.close()
+	return nil
+}
+
+// Send posts the event data to the webhook
+func (n *WebhookNotifier) Send(ctx context.Context, event Event) error {
+	err := n.post(ctx, event)
+	if err != nil && isTemporary(err) && n.retries.enabled() {
+		n.retries.push(event)
+		return fmt.Errorf("%w: %w", ErrQueuedForRetry, err)
+	}
+	return err
+}
+
+// post sends the JSON encoded event data
+func (n *WebhookNotifier) post(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("marshaling webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return temporaryError{err: fmt.Errorf("posting webhook: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return temporaryError{err: fmt.Errorf("unexpected status code: %d", resp.StatusCode)}
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
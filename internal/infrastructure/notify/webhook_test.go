@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookNotifier_RequiresURL(t *testing.T) {
+	_, err := NewWebhookNotifier("", "token")
+	assert.Error(t, err)
+}
+
+func TestWebhookNotifier_Send(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		data       any
+		wantErr    bool
+		wantQueued bool
+	}{
+		{name: "posted", status: http.StatusOK, data: map[string]string{"status": "firing"}},
+		{name: "bad request", status: http.StatusBadRequest, data: map[string]string{}, wantErr: true},
+		{name: "server error is retried", status: http.StatusBadGateway, data: map[string]string{}, wantErr: true, wantQueued: true},
+		{name: "unencodable data", status: http.StatusOK, data: func() {}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotReq *http.Request
+			var gotBody string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotReq, gotBody = r, string(body)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			n, err := NewWebhookNotifier(srv.URL+"/alerts", "secret")
+			require.NoError(t, err)
+			n.WithRetry(RetryConfig{})
+			if tt.wantQueued {
+				n.WithRetry(testRetryConfig())
+			}
+			defer n.Close()
+
+			err = n.Send(context.Background(), Event{EventType: "OPS_ALERT", Data: tt.data})
+			if !tt.wantErr {
+				require.NoError(t, err)
+				require.NotNil(t, gotReq)
+				assert.Equal(t, "/alerts", gotReq.URL.Path)
+				assert.Equal(t, "application/json", gotReq.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer secret", gotReq.Header.Get("Authorization"))
+				assert.JSONEq(t, `{"status":"firing"}`, gotBody)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantQueued, isTemporary(err))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Package instrument records the duration and errors of repository operations and logs the slow ones with their query
+package instrument
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"go.uber.org/zap"
+)
+
+// Recorder is shared by the repositories of a factory. A nil Recorder records nothing,
+// so repositories created without one keep working
+type Recorder struct {
+	telemetry telemetry.Provider
+	logger    *zap.Logger
+	slowOp    time.Duration
+}
+
+// NewRecorder creates a Recorder logging the operations taking longer than slowOp, 0 disables the log
+func NewRecorder(logger *zap.Logger, slowOp time.Duration) *Recorder {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Recorder{telemetry: &telemetry.NoopProvider{}, logger: logger, slowOp: slowOp}
+}
+
+// WithTelemetry sets the provider the operation metrics are sent to, it must be called before any operation runs
+func (r *Recorder) WithTelemetry(provider telemetry.Provider) *Recorder {
+	if r != nil && provider != nil {
+		r.telemetry = provider
+	}
+	return r
+}
+
+// Scope returns the scope of the operations on a collection or table
+func (r *Recorder) Scope(target string) *Scope {
+	if r == nil {
+		return nil
+	}
+	return &Scope{recorder: r, target: target}
+}
+
+// Scope records the operations on a collection or table
+type Scope struct {
+	recorder *Recorder
+	target   string
+}
+
+// Start starts an operation, query describes it in the slow operation log (a filter, a statement or a key).
+// It's meant to be deferred along with Done: defer r.ops.Start("tick.create", query).Done(&err)
+func (s *Scope) Start(op string, query any) Op {
+	return Op{scope: s, name: op, query: query, start: time.Now()}
+}
+
+// Op is a running repository operation
+type Op struct {
+	scope *Scope
+	name  string
+	query any
+	start time.Time
+}
+
+// Done records the duration of the operation and the error it returned through errp, if any
+func (o Op) Done(errp *error) {
+	if o.scope == nil {
+		return
+	}
+	r := o.scope.recorder
+	took := time.Since(o.start)
+	opTag := "op:" + o.name
+
+	r.telemetry.Timing(telemetryOpDuration, took, opTag)
+	var err error
+	if errp != nil {
+		err = *errp
+	}
+	if err != nil {
+		r.telemetry.IncrementCounter(telemetryOpErrors, 1, opTag)
+	}
+
+	if r.slowOp <= 0 || took < r.slowOp {
+		return
+	}
+	r.telemetry.IncrementCounter(telemetryOpSlow, 1, opTag)
+	fields := []zap.Field{
+		zap.String("op", o.name),
+		zap.String("target", o.scope.target),
+		zap.Duration("duration", took),
+		zap.Duration("threshold", r.slowOp),
+		zap.String("query", fmt.Sprint(o.query)),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	r.logger.Warn("Slow repository operation", fields...)
+}
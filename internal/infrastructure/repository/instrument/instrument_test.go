@@ -0,0 +1,100 @@
+package instrument
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingTelemetry records the tags of the emitted counters and timings
+type recordingTelemetry struct {
+	telemetry.NoopProvider
+	mu       sync.Mutex
+	counters map[string][]string
+	timings  map[string][]string
+}
+
+func newRecordingTelemetry() *recordingTelemetry {
+	return &recordingTelemetry{counters: map[string][]string{}, timings: map[string][]string{}}
+}
+
+func (p *recordingTelemetry) IncrementCounter(name string, _ int64, tags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.counters[name] = tags
+}
+
+func (p *recordingTelemetry) Timing(name string, _ time.Duration, tags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.timings[name] = tags
+}
+
+// bufferLogger returns a logger writing JSON lines to the returned buffer
+func bufferLogger() (*zap.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zap.DebugLevel)
+	return zap.New(core), &buf
+}
+
+func TestOp_Done(t *testing.T) {
+	t.Run("fast operation", func(t *testing.T) {
+		tel := newRecordingTelemetry()
+		logger, logs := bufferLogger()
+		scope := NewRecorder(logger, time.Hour).WithTelemetry(tel).Scope("ticks")
+
+		var err error
+		scope.Start("tick.create", "INSERT INTO ticks").Done(&err)
+
+		assert.Equal(t, []string{"op:tick.create"}, tel.timings[telemetryOpDuration])
+		assert.Empty(t, tel.counters)
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("failed slow operation", func(t *testing.T) {
+		tel := newRecordingTelemetry()
+		logger, logs := bufferLogger()
+		scope := NewRecorder(logger, time.Nanosecond).WithTelemetry(tel).Scope("ticks")
+
+		op := scope.Start("tick.get_range", "SELECT tick_json FROM ticks")
+		time.Sleep(time.Millisecond)
+		err := errors.New("database is locked")
+		op.Done(&err)
+
+		assert.Equal(t, []string{"op:tick.get_range"}, tel.timings[telemetryOpDuration])
+		assert.Equal(t, []string{"op:tick.get_range"}, tel.counters[telemetryOpErrors])
+		assert.Equal(t, []string{"op:tick.get_range"}, tel.counters[telemetryOpSlow])
+		assert.Contains(t, logs.String(), `"msg":"Slow repository operation"`)
+		assert.Contains(t, logs.String(), `"target":"ticks"`)
+		assert.Contains(t, logs.String(), `"query":"SELECT tick_json FROM ticks"`)
+		assert.Contains(t, logs.String(), `"error":"database is locked"`)
+	})
+
+	t.Run("slow log disabled", func(t *testing.T) {
+		tel := newRecordingTelemetry()
+		logger, logs := bufferLogger()
+		scope := NewRecorder(logger, 0).WithTelemetry(tel).Scope("ticks")
+
+		op := scope.Start("tick.create", "INSERT INTO ticks")
+		time.Sleep(time.Millisecond)
+		op.Done(nil)
+
+		assert.NotContains(t, tel.counters, telemetryOpSlow)
+		assert.Empty(t, logs.String())
+	})
+
+	t.Run("nil recorder", func(t *testing.T) {
+		var recorder *Recorder
+		scope := recorder.WithTelemetry(newRecordingTelemetry()).Scope("ticks")
+
+		err := errors.New("boom")
+		assert.NotPanics(t, func() { scope.Start("tick.create", nil).Done(&err) })
+	})
+}
@@ -0,0 +1,24 @@
+package instrument
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for counters and timings
+const (
+	// telemetryOpDuration measures the duration of repository operations, tagged with the operation
+	telemetryOpDuration = "repository.op.duration"
+
+	// telemetryOpErrors counts the repository operations that failed, tagged with the operation
+	telemetryOpErrors = "repository.op.errors"
+
+	// telemetryOpSlow counts the repository operations slower than the slow operation threshold
+	telemetryOpSlow = "repository.op.slow"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryOpDuration, Kind: telemetry.KindTiming, Description: "Duration of repository operations", Tags: []string{"op"}},
+		{Name: telemetryOpErrors, Kind: telemetry.KindCounter, Description: "Repository operations that failed", Tags: []string{"op"}},
+		{Name: telemetryOpSlow, Kind: telemetry.KindCounter, Description: "Repository operations slower than REPOSITORY_SLOW_OP", Tags: []string{"op"}},
+	}
+}
@@ -0,0 +1,15 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// DiscardAlertRepository drops the fired alerts, there is nothing to backtest against in memory
+type DiscardAlertRepository struct{}
+
+// Create discards the alert
+func (r *DiscardAlertRepository) Create(_ context.Context, _ domain.Alert) error {
+	return nil
+}
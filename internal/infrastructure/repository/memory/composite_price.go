@@ -0,0 +1,15 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// DiscardCompositePriceRepository drops composite prices, there is nothing to serve them from memory
+type DiscardCompositePriceRepository struct{}
+
+// CreateMany discards the composite prices
+func (r *DiscardCompositePriceRepository) CreateMany(_ context.Context, _ []domain.CompositePrice) error {
+	return nil
+}
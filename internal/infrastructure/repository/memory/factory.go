@@ -21,3 +21,48 @@ func (f *InMemoryRepoFactory) GetLiquidationRepository(_ string) (domain.Liquida
 		liquidations: make([]domain.Liquidation, 0),
 	}, nil
 }
+
+// GetSubTickRepository returns a SubTickRepository discarding the sub-ticks
+func (f *InMemoryRepoFactory) GetSubTickRepository(_ string) (domain.SubTickRepository, error) {
+	return &DiscardSubTickRepository{}, nil
+}
+
+// GetBarRepository returns a BarRepository discarding the bars
+func (f *InMemoryRepoFactory) GetBarRepository(_ string) (domain.BarRepository, error) {
+	return &DiscardBarRepository{}, nil
+}
+
+// GetTradeRepository returns a TradeRepository discarding the trades
+func (f *InMemoryRepoFactory) GetTradeRepository(_ string) (domain.TradeRepository, error) {
+	return &DiscardTradeRepository{}, nil
+}
+
+// GetSymbolRepository returns a SymbolRepository discarding the symbols
+func (f *InMemoryRepoFactory) GetSymbolRepository(_ string) (domain.SymbolRepository, error) {
+	return &DiscardSymbolRepository{}, nil
+}
+
+// GetFundingRateRepository returns a FundingRateRepository discarding the funding rates
+func (f *InMemoryRepoFactory) GetFundingRateRepository(_ string) (domain.FundingRateRepository, error) {
+	return &DiscardFundingRateRepository{}, nil
+}
+
+// GetRecomputedTickRepository returns a RecomputedTickRepository discarding the ticks
+func (f *InMemoryRepoFactory) GetRecomputedTickRepository(_ string) (domain.RecomputedTickRepository, error) {
+	return &DiscardRecomputedTickRepository{}, nil
+}
+
+// GetOutageRepository returns an OutageRepository discarding the outages
+func (f *InMemoryRepoFactory) GetOutageRepository(_ string) (domain.OutageRepository, error) {
+	return &DiscardOutageRepository{}, nil
+}
+
+// GetAlertRepository returns an AlertRepository discarding the alerts
+func (f *InMemoryRepoFactory) GetAlertRepository(_ string) (domain.AlertRepository, error) {
+	return &DiscardAlertRepository{}, nil
+}
+
+// GetCompositePriceRepository returns a CompositePriceRepository discarding the composite prices
+func (f *InMemoryRepoFactory) GetCompositePriceRepository() (domain.CompositePriceRepository, error) {
+	return &DiscardCompositePriceRepository{}, nil
+}
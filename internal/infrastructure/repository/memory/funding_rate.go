@@ -0,0 +1,15 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// DiscardFundingRateRepository drops funding rate snapshots
+type DiscardFundingRateRepository struct{}
+
+// CreateMany discards the funding rates
+func (r *DiscardFundingRateRepository) CreateMany(_ context.Context, _ []domain.FundingRate) error {
+	return nil
+}
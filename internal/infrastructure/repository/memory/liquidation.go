@@ -1,7 +1,9 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"slices"
 	"sync"
 	"time"
 
@@ -24,7 +26,90 @@ func (r *InMemoryLiquidationRepository) Create(_ context.Context, l domain.Liqui
 	return nil
 }
 
-// GetLiquidationsHistory returns liquidations history for the given time
+// CreateMany stores the liquidations at once
+func (r *InMemoryLiquidationRepository) CreateMany(_ context.Context, liquidations []domain.Liquidation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.liquidations = append(r.liquidations, liquidations...)
+	return nil
+}
+
+// CreateMissing stores the liquidations without a stored match, see domain.Liquidation.SameAs
+func (r *InMemoryLiquidationRepository) CreateMissing(_ context.Context, liquidations []domain.Liquidation) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := 0
+	for _, l := range liquidations {
+		if slices.ContainsFunc(r.liquidations, l.SameAs) {
+			continue
+		}
+		r.liquidations = append(r.liquidations, l)
+		created++
+	}
+	return created, nil
+}
+
+// GetRange returns the liquidations kept in memory that happened within [from, to)
+func (r *InMemoryLiquidationRepository) GetRange(_ context.Context, from, to time.Time) ([]domain.Liquidation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var liquidations []domain.Liquidation
+	for _, l := range r.liquidations {
+		if !l.EventAt.Before(from) && l.EventAt.Before(to) {
+			liquidations = append(liquidations, l)
+		}
+	}
+	slices.SortFunc(liquidations, func(a, b domain.Liquidation) int { return a.EventAt.Compare(b.EventAt) })
+	return liquidations, nil
+}
+
+// TopSymbolsByNotional returns the limit symbols with the largest USD notional liquidated within [from, to)
+// among the liquidations kept in memory
+func (r *InMemoryLiquidationRepository) TopSymbolsByNotional(ctx context.Context, from, to time.Time, limit int) ([]domain.LiquidationSymbolNotional, error) {
+	liquidations, _ := r.GetRange(ctx, from, to)
+
+	bySymbol := make(map[domain.TickerName]*domain.LiquidationSymbolNotional)
+	var top []domain.LiquidationSymbolNotional
+	for _, l := range liquidations {
+		total, ok := bySymbol[l.Order.Symbol]
+		if !ok {
+			total = &domain.LiquidationSymbolNotional{Symbol: l.Order.Symbol}
+			bySymbol[l.Order.Symbol] = total
+		}
+		total.Notional += l.Order.USDValue
+		if l.Order.Side == domain.OrderSideSell {
+			total.Long += l.Order.USDValue
+		} else {
+			total.Short += l.Order.USDValue
+		}
+		total.Count++
+	}
+	for _, total := range bySymbol {
+		top = append(top, *total)
+	}
+	slices.SortFunc(top, func(a, b domain.LiquidationSymbolNotional) int {
+		if c := cmp.Compare(b.Notional, a.Notional); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Symbol, b.Symbol)
+	})
+	return top[:min(limit, len(top))], nil
+}
+
+// LargestLiquidations returns the limit liquidations with the largest USD value within [from, to)
+// among the liquidations kept in memory
+func (r *InMemoryLiquidationRepository) LargestLiquidations(ctx context.Context, from, to time.Time, limit int) ([]domain.Liquidation, error) {
+	liquidations, _ := r.GetRange(ctx, from, to)
+
+	// stable, so equal values stay ordered by event time
+	slices.SortStableFunc(liquidations, func(a, b domain.Liquidation) int { return cmp.Compare(b.Order.USDValue, a.Order.USDValue) })
+	return liquidations[:min(limit, len(liquidations))], nil
+}
+
+// GetLiquidationsHistory returns liquidations history for the given time, counted by their window time
 func (r *InMemoryLiquidationRepository) GetLiquidationsHistory(_ context.Context, timeAt time.Time) (domain.LiquidationsHistory, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -38,31 +123,32 @@ func (r *InMemoryLiquidationRepository) GetLiquidationsHistory(_ context.Context
 	sixtySecondsAgo := timeAt.Add(-60 * time.Second)
 
 	for _, l := range r.liquidations {
-		if l.EventAt.Before(sixtySecondsAgo) {
+		windowAt := l.WindowTime()
+		if windowAt.Before(sixtySecondsAgo) {
 			continue
 		}
 
 		if l.Order.Side == domain.OrderSideSell {
-			if l.EventAt.After(oneSecondAgo) {
+			if windowAt.After(oneSecondAgo) {
 				history.LongLiquidations1s++
 			}
-			if l.EventAt.After(twoSecondsAgo) {
+			if windowAt.After(twoSecondsAgo) {
 				history.LongLiquidations2s++
 			}
-			if l.EventAt.After(fiveSecondsAgo) {
+			if windowAt.After(fiveSecondsAgo) {
 				history.LongLiquidations5s++
 			}
-			if l.EventAt.After(sixtySecondsAgo) {
+			if windowAt.After(sixtySecondsAgo) {
 				history.LongLiquidations60s++
 			}
 		} else {
-			if l.EventAt.After(oneSecondAgo) {
+			if windowAt.After(oneSecondAgo) {
 				history.ShortLiquidations1s++
 			}
-			if l.EventAt.After(twoSecondsAgo) {
+			if windowAt.After(twoSecondsAgo) {
 				history.ShortLiquidations2s++
 			}
-			if l.EventAt.After(tenSecondsAgo) {
+			if windowAt.After(tenSecondsAgo) {
 				history.ShortLiquidations10s++
 			}
 		}
@@ -74,11 +160,11 @@ func (r *InMemoryLiquidationRepository) GetLiquidationsHistory(_ context.Context
 	return history, nil
 }
 
-// cleanup removes liquidations older than the given time
+// cleanup removes liquidations counted before the given time
 func (r *InMemoryLiquidationRepository) cleanup(before time.Time) {
 	newLiquidations := make([]domain.Liquidation, 0)
 	for _, l := range r.liquidations {
-		if l.EventAt.After(before) {
+		if windowAt := l.WindowTime(); windowAt.After(before) {
 			newLiquidations = append(newLiquidations, l)
 		}
 	}
@@ -0,0 +1,15 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// DiscardSubTickRepository drops sub-ticks, there is nothing to serve them from memory
+type DiscardSubTickRepository struct{}
+
+// CreateMany discards the sub-ticks
+func (r *DiscardSubTickRepository) CreateMany(_ context.Context, _ []domain.SubTick) error {
+	return nil
+}
@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// DiscardSymbolRepository drops the symbols, the registry is kept in memory by the importer
+type DiscardSymbolRepository struct{}
+
+// SaveMany discards the symbols
+func (r *DiscardSymbolRepository) SaveMany(_ context.Context, _ []domain.Symbol) error {
+	return nil
+}
+
+// GetAll returns no symbols
+func (r *DiscardSymbolRepository) GetAll(_ context.Context) ([]domain.Symbol, error) {
+	return nil, nil
+}
@@ -0,0 +1,51 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NewAlertRepository creates a new Alert repository and ensures the required indexes
+func NewAlertRepository(db *mongo.Collection) (*Alert, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &Alert{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Alert is a repository for storing the fired market alerts
+type Alert struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// Create stores a fired alert in the database
+func (r *Alert) Create(ctx context.Context, alert domain.Alert) (err error) {
+	defer r.ops.Start("alert.create", alert.TickKey).Done(&err)
+
+	if _, err := r.db.InsertOne(ctx, alert); err != nil {
+		return fmt.Errorf("error inserting alert: %w", err)
+	}
+	return nil
+}
+
+// ensureIndexes creates the index used to join the alerts with the ticks that triggered them
+func (r *Alert) ensureIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tick_key", Value: 1}},
+	})
+	return err
+}
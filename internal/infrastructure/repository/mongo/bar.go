@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewBarRepository creates a new Bar repository and ensures the required indexes
+func NewBarRepository(db *mongo.Collection) (*Bar, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &Bar{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Bar is a repository for storing finalized minute bars
+type Bar struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// CreateMany stores a batch of bars in the database
+func (r *Bar) CreateMany(ctx context.Context, bars []domain.Bar) (err error) {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	defer r.ops.Start("bar.create_many", fmt.Sprintf("%d documents", len(bars))).Done(&err)
+
+	docs := make([]any, len(bars))
+	for i := range bars {
+		docs[i] = bars[i]
+	}
+	_, err = r.db.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error inserting bars: %w", err)
+	}
+
+	return nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *Bar) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "s", Value: 1},
+				{Key: "st", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "st", Value: 1},
+			},
+		},
+	}
+
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewCompositePriceRepository creates a new CompositePrice repository and ensures the required indexes
+func NewCompositePriceRepository(db *mongo.Collection) (*CompositePrice, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &CompositePrice{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// CompositePrice is a repository for storing cross-exchange composite prices
+type CompositePrice struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// CreateMany stores a batch of composite prices in the database
+func (r *CompositePrice) CreateMany(ctx context.Context, prices []domain.CompositePrice) (err error) {
+	if len(prices) == 0 {
+		return nil
+	}
+
+	defer r.ops.Start("composite_price.create_many", fmt.Sprintf("%d documents", len(prices))).Done(&err)
+
+	docs := make([]any, len(prices))
+	for i := range prices {
+		docs[i] = prices[i]
+	}
+	_, err = r.db.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error inserting composite prices: %w", err)
+	}
+
+	return nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *CompositePrice) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "s", Value: 1},
+				{Key: "ct", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "ct", Value: 1},
+			},
+		},
+	}
+
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -5,39 +5,180 @@ import (
 	"fmt"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Factory is a factory for creating mongo repositories
 type Factory struct {
 	client *mongo.Client
+	codec  codec.Codec
+	ops    *instrument.Recorder
 }
 
 // NewMongoRepoFactory creates a new Factory
 func NewMongoRepoFactory(client *mongo.Client) (*Factory, error) {
-	return &Factory{client: client}, nil
+	return &Factory{client: client, codec: codec.BSON}, nil
+}
+
+// SetCodec sets the codec the repositories created afterwards store ticks with, BSON documents by default.
+// With another codec a tick is stored as a blob next to the fields it's queried by, documents of either kind
+// are read back. Liquidations stay documents whatever the codec: the leaderboards aggregate their fields
+func (f *Factory) SetCodec(c codec.Codec) error {
+	f.codec = c
+	return nil
+}
+
+// WithInstrumentation makes the repositories created afterwards record their operations
+func (f *Factory) WithInstrumentation(recorder *instrument.Recorder) *Factory {
+	f.ops = recorder
+	return f
+}
+
+// collection returns the named collection of the exchange database
+func (f *Factory) collection(name string) *mongo.Collection {
+	return f.client.Database("exchange").Collection(name)
 }
 
 // GetTickRepository returns a new TickRepository
 func (f *Factory) GetTickRepository(name string) (domain.TickRepository, error) {
-	db := f.client.Database("exchange").Collection(name + "_tick")
-
-	// create required indexes
-	_, err := db.Indexes().CreateOne(context.Background(), mongo.IndexModel{
-		Keys: map[string]any{"created_at": 1},
-	})
+	repo, err := NewTickRepository(f.collection(name + "_tick"))
 	if err != nil {
-		return nil, fmt.Errorf("error creating index for tick repository: %w", err)
+		return nil, fmt.Errorf("error creating tick repository: %w", err)
 	}
-
-	return &Tick{db: db}, nil
+	repo.codec = f.codec
+	repo.ops = f.ops.Scope(name + "_tick")
+	return repo, nil
 }
 
 // GetLiquidationRepository returns a new LiquidationRepository
 func (f *Factory) GetLiquidationRepository(name string) (domain.LiquidationRepository, error) {
-	repo, err := NewLiquidationRepository(f.client.Database("exchange").Collection(name + "_liquidation"))
+	repo, err := NewLiquidationRepository(f.collection(name + "_liquidation"))
 	if err != nil {
 		return nil, fmt.Errorf("error creating liquidation repository: %w", err)
 	}
+	repo.ops = f.ops.Scope(name + "_liquidation")
+	return repo, nil
+}
+
+// GetSubTickRepository returns a new SubTickRepository
+func (f *Factory) GetSubTickRepository(name string) (domain.SubTickRepository, error) {
+	repo, err := NewSubTickRepository(f.collection(name + "_sub_tick"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating sub-tick repository: %w", err)
+	}
+	repo.ops = f.ops.Scope(name + "_sub_tick")
+	return repo, nil
+}
+
+// GetBarRepository returns a new BarRepository
+func (f *Factory) GetBarRepository(name string) (domain.BarRepository, error) {
+	repo, err := NewBarRepository(f.collection(name + "_bar"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating bar repository: %w", err)
+	}
+	repo.ops = f.ops.Scope(name + "_bar")
+	return repo, nil
+}
+
+// GetTradeRepository returns a new TradeRepository
+func (f *Factory) GetTradeRepository(name string) (domain.TradeRepository, error) {
+	repo, err := NewTradeRepository(f.collection(name + "_trade"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating trade repository: %w", err)
+	}
+	repo.ops = f.ops.Scope(name + "_trade")
+	return repo, nil
+}
+
+// GetSymbolRepository returns a new SymbolRepository
+func (f *Factory) GetSymbolRepository(name string) (domain.SymbolRepository, error) {
+	repo, err := NewSymbolRepository(f.collection(name + "_symbol"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating symbol repository: %w", err)
+	}
+	repo.ops = f.ops.Scope(name + "_symbol")
+	return repo, nil
+}
+
+// GetFundingRateRepository returns a new FundingRateRepository
+func (f *Factory) GetFundingRateRepository(name string) (domain.FundingRateRepository, error) {
+	repo, err := NewFundingRateRepository(f.collection(name + "_funding_rate"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating funding rate repository: %w", err)
+	}
+	repo.ops = f.ops.Scope(name + "_funding_rate")
+	return repo, nil
+}
+
+// GetRecomputedTickRepository returns a new RecomputedTickRepository
+func (f *Factory) GetRecomputedTickRepository(name string) (domain.RecomputedTickRepository, error) {
+	repo, err := NewRecomputedTickRepository(f.collection(name + "_tick_recomputed"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating recomputed tick repository: %w", err)
+	}
+	repo.codec = f.codec
+	repo.ops = f.ops.Scope(name + "_tick_recomputed")
+	return repo, nil
+}
+
+// GetOutageRepository returns a new OutageRepository
+func (f *Factory) GetOutageRepository(name string) (domain.OutageRepository, error) {
+	repo, err := NewOutageRepository(f.collection(name + "_outage"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating outage repository: %w", err)
+	}
+	repo.ops = f.ops.Scope(name + "_outage")
+	return repo, nil
+}
+
+// GetAlertRepository returns a new AlertRepository
+func (f *Factory) GetAlertRepository(name string) (domain.AlertRepository, error) {
+	repo, err := NewAlertRepository(f.collection(name + "_alert"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating alert repository: %w", err)
+	}
+	repo.ops = f.ops.Scope(name + "_alert")
+	return repo, nil
+}
+
+// GetCompositePriceRepository returns a new CompositePriceRepository.
+// The collection is shared by the importers of all exchanges
+func (f *Factory) GetCompositePriceRepository() (domain.CompositePriceRepository, error) {
+	repo, err := NewCompositePriceRepository(f.collection("composite_price"))
+	if err != nil {
+		return nil, fmt.Errorf("error creating composite price repository: %w", err)
+	}
+	repo.ops = f.ops.Scope("composite_price")
 	return repo, nil
 }
+
+// Close disconnects the mongo client, waiting for in-flight operations
+func (f *Factory) Close(ctx context.Context) error {
+	return f.client.Disconnect(ctx)
+}
+
+// storesBlobs reports whether documents are stored as a blob of the codec, in a "blob" field next to the fields
+// they are queried by and a "codec" field naming the codec, rather than as BSON documents
+func storesBlobs(c codec.Codec) bool {
+	return c != nil && c != codec.BSON
+}
+
+// decode decodes a document, or the blob of a document stored with another codec than BSON
+func decode(raw bson.Raw, v any) error {
+	name, ok := raw.Lookup("codec").StringValueOK()
+	if !ok {
+		return bson.Unmarshal(raw, v)
+	}
+	c, err := codec.Lookup(name)
+	if err != nil {
+		return err
+	}
+	_, data, ok := raw.Lookup("blob").BinaryOK()
+	if !ok {
+		return fmt.Errorf("document stored with codec %s has no blob", name)
+	}
+	return c.Unmarshal(data, v)
+}
@@ -0,0 +1,35 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDecode(t *testing.T) {
+	startAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tick := domain.Tick{StartAt: startAt, CreatedAt: startAt.Add(time.Second), LL5: 3}
+
+	document, err := bson.Marshal(tickDocument{Tick: tick, Key: startAt})
+	require.NoError(t, err)
+	var got domain.Tick
+	require.NoError(t, decode(document, &got))
+	assert.Equal(t, tick, got)
+
+	data, err := codec.CBOR.Marshal(tick)
+	require.NoError(t, err)
+	document, err = bson.Marshal(tickBlob{Key: startAt, CreatedAt: tick.CreatedAt, Codec: codec.CBOR.Name(), Blob: data})
+	require.NoError(t, err)
+	got = domain.Tick{}
+	require.NoError(t, decode(document, &got))
+	assert.Equal(t, tick, got)
+
+	document, err = bson.Marshal(bson.M{"codec": "cbor"})
+	require.NoError(t, err)
+	assert.EqualError(t, decode(document, &got), "document stored with codec cbor has no blob")
+}
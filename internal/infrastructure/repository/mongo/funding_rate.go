@@ -0,0 +1,74 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewFundingRateRepository creates a new FundingRate repository and ensures the required indexes
+func NewFundingRateRepository(db *mongo.Collection) (*FundingRate, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &FundingRate{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// FundingRate is a repository for storing funding rate snapshots
+type FundingRate struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// CreateMany stores a snapshot of funding rates in the database
+func (r *FundingRate) CreateMany(ctx context.Context, rates []domain.FundingRate) (err error) {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	defer r.ops.Start("funding_rate.create_many", fmt.Sprintf("%d documents", len(rates))).Done(&err)
+
+	docs := make([]any, len(rates))
+	for i := range rates {
+		docs[i] = rates[i]
+	}
+	_, err = r.db.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error inserting funding rates: %w", err)
+	}
+
+	return nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *FundingRate) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "s", Value: 1},
+				{Key: "ct", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "ct", Value: 1},
+			},
+		},
+	}
+
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
+}
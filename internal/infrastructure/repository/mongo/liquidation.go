@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -29,12 +30,15 @@ func NewLiquidationRepository(db *mongo.Collection) (*Liquidation, error) {
 
 // Liquidation is a repository for storing liquidation snapshots
 type Liquidation struct {
-	db *mongo.Collection
+	db  *mongo.Collection
+	ops *instrument.Scope
 }
 
 // Create method stores a liquidation in the database
-func (r *Liquidation) Create(ctx context.Context, liquidation domain.Liquidation) error {
-	_, err := r.db.InsertOne(ctx, liquidation)
+func (r *Liquidation) Create(ctx context.Context, liquidation domain.Liquidation) (err error) {
+	defer r.ops.Start("liquidation.create", liquidation.Order.Symbol).Done(&err)
+
+	_, err = r.db.InsertOne(ctx, liquidation)
 	if err != nil {
 		return fmt.Errorf("error inserting liquidation: %w", err)
 	}
@@ -42,6 +46,132 @@ func (r *Liquidation) Create(ctx context.Context, liquidation domain.Liquidation
 	return nil
 }
 
+// CreateMany stores a batch of liquidations with a single insert
+func (r *Liquidation) CreateMany(ctx context.Context, liquidations []domain.Liquidation) (err error) {
+	defer r.ops.Start("liquidation.create_many", fmt.Sprintf("%d liquidations", len(liquidations))).Done(&err)
+
+	if len(liquidations) == 0 {
+		return nil
+	}
+	documents := make([]any, len(liquidations))
+	for i, liquidation := range liquidations {
+		documents[i] = liquidation
+	}
+	_, err = r.db.InsertMany(ctx, documents)
+	if err != nil {
+		return fmt.Errorf("error inserting liquidations: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMissing stores the liquidations without a stored match, see domain.Liquidation.SameAs
+func (r *Liquidation) CreateMissing(ctx context.Context, liquidations []domain.Liquidation) (created int, err error) {
+	defer r.ops.Start("liquidation.create_missing", fmt.Sprintf("%d liquidations", len(liquidations))).Done(&err)
+
+	for _, liquidation := range liquidations {
+		filter := bson.M{
+			"order.s":  liquidation.Order.Symbol,
+			"order.sd": liquidation.Order.Side,
+			"order.p":  liquidation.Order.Price,
+			"order.q":  liquidation.Order.Quantity,
+			"et": bson.M{
+				"$gte": liquidation.EventAt.Add(-domain.LiquidationMatchWindow),
+				"$lte": liquidation.EventAt.Add(domain.LiquidationMatchWindow),
+			},
+		}
+		count, err := r.db.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+		if err != nil {
+			return created, fmt.Errorf("error looking up liquidation: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := r.Create(ctx, liquidation); err != nil {
+			return created, err
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+// GetRange returns the liquidations that happened within [from, to) ordered by their event time
+func (r *Liquidation) GetRange(ctx context.Context, from, to time.Time) (liquidations []domain.Liquidation, err error) {
+	filter := bson.M{"et": bson.M{"$gte": from, "$lt": to}}
+	defer r.ops.Start("liquidation.get_range", filter).Done(&err)
+
+	cursor, err := r.db.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "et", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("error finding liquidations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &liquidations); err != nil {
+		return nil, fmt.Errorf("error decoding liquidations: %w", err)
+	}
+	return liquidations, nil
+}
+
+// TopSymbolsByNotional returns the limit symbols with the largest USD notional liquidated within [from, to)
+func (r *Liquidation) TopSymbolsByNotional(ctx context.Context, from, to time.Time, limit int) (top []domain.LiquidationSymbolNotional, err error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"et": bson.M{"$gte": from, "$lt": to}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$order.s",
+			"n":     bson.M{"$sum": "$order.usd"},
+			"long":  bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$order.sd", domain.OrderSideSell}}, "$order.usd", 0}}},
+			"short": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$order.sd", domain.OrderSideBuy}}, "$order.usd", 0}}},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "n", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	defer r.ops.Start("liquidation.top_symbols", pipeline).Done(&err)
+
+	cursor, err := r.db.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating liquidations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Symbol domain.TickerName `bson:"_id"`
+		N      float64           `bson:"n"`
+		Long   float64           `bson:"long"`
+		Short  float64           `bson:"short"`
+		Count  int64             `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("error decoding liquidation totals: %w", err)
+	}
+	for _, row := range rows {
+		top = append(top, domain.LiquidationSymbolNotional{Symbol: row.Symbol, Notional: row.N, Long: row.Long, Short: row.Short, Count: row.Count})
+	}
+	return top, nil
+}
+
+// LargestLiquidations returns the limit liquidations with the largest USD value within [from, to)
+func (r *Liquidation) LargestLiquidations(ctx context.Context, from, to time.Time, limit int) (liquidations []domain.Liquidation, err error) {
+	filter := bson.M{"et": bson.M{"$gte": from, "$lt": to}}
+	defer r.ops.Start("liquidation.largest", filter).Done(&err)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "order.usd", Value: -1}, {Key: "et", Value: 1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.db.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error finding liquidations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &liquidations); err != nil {
+		return nil, fmt.Errorf("error decoding liquidations: %w", err)
+	}
+	return liquidations, nil
+}
+
 // GetLiquidationsHistory returns liquidation history for specified time ranges
 func (r *Liquidation) GetLiquidationsHistory(ctx context.Context, timeAt time.Time) (history domain.LiquidationsHistory, err error) {
 	type liquidationsParams struct {
@@ -58,6 +188,8 @@ func (r *Liquidation) GetLiquidationsHistory(ctx context.Context, timeAt time.Ti
 		{2, domain.ShortLiquidation, &history.ShortLiquidations2s},
 		{10, domain.ShortLiquidation, &history.ShortLiquidations10s},
 	}
+	defer r.ops.Start("liquidation.get_history", timeAt).Done(&err)
+
 	for _, tr := range timeRanges {
 		count, err := r.getLiquidationsCount(ctx, timeAt, tr.Seconds, tr.Side)
 		if err != nil {
@@ -70,18 +202,16 @@ func (r *Liquidation) GetLiquidationsHistory(ctx context.Context, timeAt time.Ti
 	return history, nil
 }
 
+// getLiquidationsCount counts the liquidations of a side by their window time, which already accounts for the
+// liquidations arriving after their window was counted. Liquidations stored before window times existed have none
+// and are left out, the windows span a minute at most
 func (r *Liquidation) getLiquidationsCount(ctx context.Context, timeAt time.Time, seconds int, liquidationType domain.LiquidationType) (int64, error) {
 	filter := bson.M{
 		"order.sd": string(liquidationType),
-		"st": bson.M{
+		"wt": bson.M{
 			"$gte": timeAt.Add(time.Duration(-seconds) * time.Second),
 			"$lte": timeAt,
 		},
-		// sometimes the event could come with a delay
-		"et": bson.M{
-			"$gte": timeAt.Add(time.Duration(-seconds*5) * time.Second),
-			"$lte": timeAt,
-		},
 	}
 
 	count, err := r.db.CountDocuments(ctx, filter)
@@ -110,6 +240,21 @@ func (r *Liquidation) ensureIndexes(ctx context.Context) error {
 				{Key: "order.sd", Value: 1},
 			},
 		},
+		{
+			Keys: bson.D{
+				{Key: "wt", Value: 1},
+				{Key: "order.sd", Value: 1},
+			},
+		},
+		{
+			// covers the leaderboards, which group and rank the liquidations of a range of event times
+			Keys: bson.D{
+				{Key: "et", Value: 1},
+				{Key: "order.s", Value: 1},
+				{Key: "order.sd", Value: 1},
+				{Key: "order.usd", Value: 1},
+			},
+		},
 		{
 			Keys: bson.D{
 				{Key: "st", Value: 1},
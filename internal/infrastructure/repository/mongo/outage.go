@@ -0,0 +1,54 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NewOutageRepository creates a new Outage repository and ensures the required indexes
+func NewOutageRepository(db *mongo.Collection) (*Outage, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &Outage{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Outage is a repository for storing exchange outage records
+type Outage struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// Create stores an outage record in the database
+func (r *Outage) Create(ctx context.Context, outage domain.Outage) (err error) {
+	defer r.ops.Start("outage.create", outage.StartAt).Done(&err)
+
+	if _, err := r.db.InsertOne(ctx, outage); err != nil {
+		return fmt.Errorf("error inserting outage: %w", err)
+	}
+	return nil
+}
+
+// ensureIndexes creates the index used to find the outages overlapping a time range
+func (r *Outage) ensureIndexes(ctx context.Context) error {
+	_, err := r.db.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "start_at", Value: 1},
+			{Key: "end_at", Value: 1},
+		},
+	})
+	return err
+}
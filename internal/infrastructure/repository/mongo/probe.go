@@ -0,0 +1,27 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Probe writes a document to the selfcheck collection, reads it back and deletes it, checking the database is
+// writable without leaving anything behind in the collections of the importer
+func (f *Factory) Probe(ctx context.Context, name string) error {
+	collection := f.collection("selfcheck")
+	id := fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
+
+	if _, err := collection.InsertOne(ctx, bson.M{"_id": id, "at": time.Now().UTC()}); err != nil {
+		return fmt.Errorf("error writing probe document: %w", err)
+	}
+	if err := collection.FindOne(ctx, bson.M{"_id": id}).Err(); err != nil {
+		return fmt.Errorf("error reading probe document back: %w", err)
+	}
+	if _, err := collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("error deleting probe document: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewRecomputedTickRepository creates a new RecomputedTick repository and ensures the required indexes
+func NewRecomputedTickRepository(db *mongo.Collection) (*RecomputedTick, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &RecomputedTick{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// RecomputedTick is a repository for storing tick snapshots with recomputed indicators
+type RecomputedTick struct {
+	db    *mongo.Collection
+	codec codec.Codec
+	ops   *instrument.Scope
+}
+
+// recomputedTickBlob is a recomputed tick snapshot stored with a codec other than BSON, see storesBlobs
+type recomputedTickBlob struct {
+	IndicatorsVersion int       `bson:"indicators_version"`
+	StartAt           time.Time `bson:"start_at"`
+	Codec             string    `bson:"codec"`
+	Blob              []byte    `bson:"blob"`
+}
+
+// SaveMany upserts a batch of tick snapshots, replacing the ones of the same indicators version and start time
+func (r *RecomputedTick) SaveMany(ctx context.Context, ticks []domain.Tick) (err error) {
+	if len(ticks) == 0 {
+		return nil
+	}
+
+	defer r.ops.Start("recomputed_tick.save_many", fmt.Sprintf("%d documents", len(ticks))).Done(&err)
+
+	models := make([]mongo.WriteModel, len(ticks))
+	for i := range ticks {
+		var document any = ticks[i]
+		if storesBlobs(r.codec) {
+			data, err := r.codec.Marshal(ticks[i])
+			if err != nil {
+				return fmt.Errorf("error encoding recomputed tick: %w", err)
+			}
+			document = recomputedTickBlob{IndicatorsVersion: ticks[i].IndicatorsVersion, StartAt: ticks[i].StartAt, Codec: r.codec.Name(), Blob: data}
+		}
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.D{
+				{Key: "indicators_version", Value: ticks[i].IndicatorsVersion},
+				{Key: "start_at", Value: ticks[i].StartAt},
+			}).
+			SetReplacement(document).
+			SetUpsert(true)
+	}
+	_, err = r.db.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error saving recomputed ticks: %w", err)
+	}
+
+	return nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *RecomputedTick) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "indicators_version", Value: 1},
+				{Key: "start_at", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// subTickTTL is how long sub-ticks are kept, they are only useful for short term analysis
+const subTickTTL = 60 * 60 * 24 * 3 // 3 days
+
+// NewSubTickRepository creates a new SubTick repository and ensures the required indexes
+func NewSubTickRepository(db *mongo.Collection) (*SubTick, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &SubTick{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// SubTick is a repository for storing high-resolution sub-tick samples
+type SubTick struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// CreateMany stores a batch of sub-ticks in the database
+func (r *SubTick) CreateMany(ctx context.Context, subTicks []domain.SubTick) (err error) {
+	if len(subTicks) == 0 {
+		return nil
+	}
+
+	defer r.ops.Start("sub_tick.create_many", fmt.Sprintf("%d documents", len(subTicks))).Done(&err)
+
+	docs := make([]any, len(subTicks))
+	for i := range subTicks {
+		docs[i] = subTicks[i]
+	}
+	_, err = r.db.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error inserting sub-ticks: %w", err)
+	}
+
+	return nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *SubTick) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "s", Value: 1},
+				{Key: "ct", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "ct", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(subTickTTL),
+		},
+	}
+
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
+}
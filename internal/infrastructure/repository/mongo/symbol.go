@@ -0,0 +1,85 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NewSymbolRepository creates a new Symbol repository and ensures the required indexes
+func NewSymbolRepository(db *mongo.Collection) (*Symbol, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &Symbol{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Symbol is a repository for storing the symbol registry of an exchange
+type Symbol struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// SaveMany upserts a batch of symbols, replacing the records of the same symbols
+func (r *Symbol) SaveMany(ctx context.Context, symbols []domain.Symbol) (err error) {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	defer r.ops.Start("symbol.save_many", fmt.Sprintf("%d documents", len(symbols))).Done(&err)
+
+	models := make([]mongo.WriteModel, len(symbols))
+	for i := range symbols {
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.D{{Key: "s", Value: symbols[i].Symbol}}).
+			SetReplacement(symbols[i]).
+			SetUpsert(true)
+	}
+	_, err = r.db.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error saving symbols: %w", err)
+	}
+
+	return nil
+}
+
+// GetAll returns the stored symbols
+func (r *Symbol) GetAll(ctx context.Context) (symbols []domain.Symbol, err error) {
+	defer r.ops.Start("symbol.get_all", "all documents").Done(&err)
+
+	cursor, err := r.db.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("error finding symbols: %w", err)
+	}
+	if err := cursor.All(ctx, &symbols); err != nil {
+		return nil, fmt.Errorf("error decoding symbols: %w", err)
+	}
+
+	return symbols, nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *Symbol) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "s", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	}
+
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
+}
@@ -6,34 +6,115 @@ import (
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// NewTickRepository creates a new Tick repository and ensures the required indexes
+func NewTickRepository(db *mongo.Collection) (*Tick, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &Tick{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
 // Tick is a repository for storing tick snapshots
 type Tick struct {
-	db *mongo.Collection
+	db    *mongo.Collection
+	codec codec.Codec
+	ops   *instrument.Scope
 }
 
-// Create method stores a tick snapshot in the database
-func (r *Tick) Create(ctx context.Context, tick domain.Tick) error {
-	_, err := r.db.InsertOne(ctx, tick)
+// tickDocument is a stored tick snapshot with its storage key
+type tickDocument struct {
+	domain.Tick `bson:",inline"`
+	Key         time.Time `bson:"key"`
+}
+
+// tickBlob is a tick snapshot stored with a codec other than BSON, see storesBlobs
+type tickBlob struct {
+	Key       time.Time `bson:"key"`
+	CreatedAt time.Time `bson:"created_at"`
+	Codec     string    `bson:"codec"`
+	Blob      []byte    `bson:"blob"`
+}
+
+// Create method upserts a tick snapshot, replacing the one stored for the same second
+func (r *Tick) Create(ctx context.Context, tick domain.Tick) (err error) {
+	key := tick.StorageKey()
+	defer r.ops.Start("tick.create", key).Done(&err)
+
+	var document any = tickDocument{Tick: tick, Key: key}
+	if storesBlobs(r.codec) {
+		data, err := r.codec.Marshal(tick)
+		if err != nil {
+			return fmt.Errorf("error encoding tick snapshot: %w", err)
+		}
+		document = tickBlob{Key: key, CreatedAt: tick.CreatedAt, Codec: r.codec.Name(), Blob: data}
+	}
+	_, err = r.db.ReplaceOne(ctx,
+		bson.D{{Key: "key", Value: key}},
+		document,
+		options.Replace().SetUpsert(true),
+	)
 	if err != nil {
-		return fmt.Errorf("error inserting tick snapshot: %w", err)
+		return fmt.Errorf("error upserting tick snapshot: %w", err)
 	}
 
 	return nil
 }
 
 // GetHistorySince method returns a list of tick snapshots since the specified time
-func (r *Tick) GetHistorySince(ctx context.Context, since time.Time) ([]domain.Tick, error) {
+func (r *Tick) GetHistorySince(ctx context.Context, since time.Time) (ticks []domain.Tick, err error) {
 	filter := map[string]any{
 		"created_at": map[string]any{
 			"$gte": since,
 		},
 	}
-	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	defer r.ops.Start("tick.get_history_since", filter).Done(&err)
+
+	return r.find(ctx, filter, 0)
+}
+
+// GetPage method returns at most limit tick snapshots since the specified time
+func (r *Tick) GetPage(ctx context.Context, since time.Time, limit int) (ticks []domain.Tick, err error) {
+	filter := map[string]any{
+		"created_at": map[string]any{
+			"$gte": since,
+		},
+	}
+	defer r.ops.Start("tick.get_page", filter).Done(&err)
+
+	return r.find(ctx, filter, int64(limit))
+}
+
+// GetRange method returns a list of tick snapshots created within [from, to)
+func (r *Tick) GetRange(ctx context.Context, from, to time.Time) (ticks []domain.Tick, err error) {
+	filter := map[string]any{
+		"created_at": map[string]any{
+			"$gte": from,
+			"$lt":  to,
+		},
+	}
+	defer r.ops.Start("tick.get_range", filter).Done(&err)
+
+	return r.find(ctx, filter, 0)
+}
+
+// find returns the tick snapshots matching the filter ordered by creation time, at most limit of them unless it's 0
+func (r *Tick) find(ctx context.Context, filter map[string]any, limit int64) ([]domain.Tick, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}).SetLimit(limit)
 
 	cursor, err := r.db.Find(ctx, filter, findOptions)
 	if err != nil {
@@ -44,12 +125,28 @@ func (r *Tick) GetHistorySince(ctx context.Context, since time.Time) ([]domain.T
 	var history []domain.Tick
 	for cursor.Next(ctx) {
 		var tick domain.Tick
-		if err := cursor.Decode(&tick); err != nil {
+		if err := decode(cursor.Current, &tick); err != nil {
 			return nil, fmt.Errorf("error decoding tick snapshot: %w", err)
 		}
 		history = append(history, tick)
 	}
 
 	return history, nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *Tick) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "created_at", Value: 1}},
+		},
+		{
+			// ticks stored before the key was introduced have none and are left out of the index
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{Key: "key", Value: bson.D{{Key: "$exists", Value: true}}}}),
+		},
+	}
 
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
 }
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tradeTTL is how long trades are kept, the ticks keep their per-second volumes
+const tradeTTL = 60 * 60 * 24 * 7 // 7 days
+
+// NewTradeRepository creates a new Trade repository and ensures the required indexes
+func NewTradeRepository(db *mongo.Collection) (*Trade, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is required")
+	}
+	repo := &Trade{
+		db: db,
+	}
+
+	if err := repo.ensureIndexes(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// Trade is a repository for storing aggregated trades
+type Trade struct {
+	db  *mongo.Collection
+	ops *instrument.Scope
+}
+
+// CreateMany stores a batch of trades in the database
+func (r *Trade) CreateMany(ctx context.Context, trades []domain.Trade) (err error) {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	defer r.ops.Start("trade.create_many", fmt.Sprintf("%d documents", len(trades))).Done(&err)
+
+	docs := make([]any, len(trades))
+	for i := range trades {
+		docs[i] = trades[i]
+	}
+	_, err = r.db.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		return fmt.Errorf("error inserting trades: %w", err)
+	}
+
+	return nil
+}
+
+// ensureIndexes creates the required indexes for optimal query performance
+func (r *Trade) ensureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "s", Value: 1},
+				{Key: "et", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "et", Value: 1},
+			},
+			Options: options.Index().SetExpireAfterSeconds(tradeTTL),
+		},
+	}
+
+	_, err := r.db.Indexes().CreateMany(ctx, indexes)
+	return err
+}
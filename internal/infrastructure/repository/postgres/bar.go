@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// BarRepository is a repository for the finalized minute bars of an exchange.
+type BarRepository struct {
+	db       *sql.DB
+	exchange string
+	ops      *instrument.Scope
+}
+
+func (r *BarRepository) init() error {
+	barTable := `
+	CREATE TABLE IF NOT EXISTS bars (
+	  exchange TEXT NOT NULL,
+	  symbol TEXT NOT NULL,
+	  start_at TIMESTAMPTZ NOT NULL,
+	  open DOUBLE PRECISION,
+	  high DOUBLE PRECISION,
+	  low DOUBLE PRECISION,
+	  close DOUBLE PRECISION,
+	  max_spread DOUBLE PRECISION,
+	  liq_notional DOUBLE PRECISION,
+	  samples INTEGER
+	);
+	CREATE INDEX IF NOT EXISTS bars_exchange_symbol_start_at ON bars (exchange, symbol, start_at);
+	`
+	if _, err := r.db.Exec(barTable); err != nil {
+		return fmt.Errorf("failed to create bars table: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts a batch of bars in a single transaction.
+func (r *BarRepository) CreateMany(ctx context.Context, bars []domain.Bar) (err error) {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO bars (exchange, symbol, start_at, open, high, low, close, max_spread, liq_notional, samples)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+	defer r.ops.Start("bar.create_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare bar insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, b := range bars {
+		if _, err := stmt.ExecContext(ctx, r.exchange, string(b.Symbol), b.StartAt, b.Open, b.High, b.Low, b.Close, b.MaxSpread, b.LiqNotional, b.Samples); err != nil {
+			return fmt.Errorf("failed to insert bar: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit bars: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Package postgres stores the importer data in PostgreSQL, or TimescaleDB with hypertables. Ticks and liquidations
+// keep their JSON document in a JSONB column next to the columns they are queried by, so they can be analyzed in SQL
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// DriverName is the database/sql driver the factory opens the database with, registered by
+// github.com/jackc/pgx/v5/stdlib when the binary is built with the postgres build tag
+const DriverName = "pgx"
+
+// Partitioning of the ticks and liquidations tables by time
+const (
+	PartitioningNone      = "none"      // plain tables
+	PartitioningMonthly   = "monthly"   // native range partitions of a month, created when the first row of the month is stored
+	PartitioningTimescale = "timescale" // TimescaleDB hypertables, chunks are managed by the extension
+)
+
+// Factory implements a repository factory using PostgreSQL.
+type Factory struct {
+	db           *sql.DB
+	partitioning string
+	partitions   *partitions // nil unless the partitioning is monthly
+	ops          *instrument.Recorder
+}
+
+// NewPostgresRepoFactory opens the database at url, tables are created with the partitioning when missing.
+func NewPostgresRepoFactory(ctx context.Context, url, partitioning string) (*Factory, error) {
+	if !slices.Contains(sql.Drivers(), DriverName) {
+		return nil, fmt.Errorf("postgres driver %q is not linked in, build the binary with -tags postgres", DriverName)
+	}
+	db, err := sql.Open(DriverName, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres db: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	factory := &Factory{db: db, partitioning: partitioning}
+	if partitioning == PartitioningMonthly {
+		factory.partitions = newPartitions(db)
+	}
+	return factory, nil
+}
+
+// SetCodec accepts JSON only: ticks and liquidations are JSONB documents, so they can be queried in SQL.
+func (f *Factory) SetCodec(c codec.Codec) error {
+	if c != codec.JSON {
+		return fmt.Errorf("postgres stores JSONB documents, codec %q is not supported", c.Name())
+	}
+	return nil
+}
+
+// WithInstrumentation makes the repositories created afterwards record their operations.
+func (f *Factory) WithInstrumentation(recorder *instrument.Recorder) *Factory {
+	f.ops = recorder
+	return f
+}
+
+// GetTickRepository returns a TickRepository instance.
+func (f *Factory) GetTickRepository(name string) (domain.TickRepository, error) {
+	repo := &TickRepository{
+		db:         f.db,
+		exchange:   name,
+		partitions: f.partitions,
+		ops:        f.ops.Scope("ticks"),
+	}
+	if err := repo.init(f.partitioning); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetLiquidationRepository returns a LiquidationRepository instance.
+func (f *Factory) GetLiquidationRepository(name string) (domain.LiquidationRepository, error) {
+	repo := &LiquidationRepository{
+		db:         f.db,
+		exchange:   name,
+		partitions: f.partitions,
+		ops:        f.ops.Scope("liquidations"),
+	}
+	if err := repo.init(f.partitioning); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetSubTickRepository returns a SubTickRepository instance.
+func (f *Factory) GetSubTickRepository(name string) (domain.SubTickRepository, error) {
+	repo := &SubTickRepository{
+		db:       f.db,
+		exchange: name,
+		ops:      f.ops.Scope("sub_ticks"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetBarRepository returns a BarRepository instance.
+func (f *Factory) GetBarRepository(name string) (domain.BarRepository, error) {
+	repo := &BarRepository{
+		db:       f.db,
+		exchange: name,
+		ops:      f.ops.Scope("bars"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetTradeRepository returns a TradeRepository instance.
+func (f *Factory) GetTradeRepository(name string) (domain.TradeRepository, error) {
+	repo := &TradeRepository{
+		db:       f.db,
+		exchange: name,
+		ops:      f.ops.Scope("trades"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetSymbolRepository returns a SymbolRepository instance.
+func (f *Factory) GetSymbolRepository(name string) (domain.SymbolRepository, error) {
+	repo := &SymbolRepository{
+		db:       f.db,
+		exchange: name,
+		ops:      f.ops.Scope("symbols"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetFundingRateRepository returns a FundingRateRepository instance.
+func (f *Factory) GetFundingRateRepository(name string) (domain.FundingRateRepository, error) {
+	repo := &FundingRateRepository{
+		db:       f.db,
+		exchange: name,
+		ops:      f.ops.Scope("funding_rates"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetOutageRepository returns an OutageRepository instance.
+func (f *Factory) GetOutageRepository(_ string) (domain.OutageRepository, error) {
+	repo := &OutageRepository{
+		db:  f.db,
+		ops: f.ops.Scope("outages"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetAlertRepository returns an AlertRepository instance.
+func (f *Factory) GetAlertRepository(_ string) (domain.AlertRepository, error) {
+	repo := &AlertRepository{
+		db:  f.db,
+		ops: f.ops.Scope("alerts"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Close closes the database once pending statements are done.
+func (f *Factory) Close(_ context.Context) error {
+	return f.db.Close()
+}
+
+// createTimeTable creates a table partitioned by its time column with the partitioning, its columns must include
+// the time column in the primary key when there is one
+func createTimeTable(db *sql.DB, table, columns, timeColumn, partitioning string) error {
+	var query string
+	switch partitioning {
+	case PartitioningMonthly:
+		query = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (%s) PARTITION BY RANGE (%s)`, table, columns, timeColumn)
+	default:
+		query = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (%s)`, table, columns)
+	}
+	if _, err := db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create %s table: %w", table, err)
+	}
+
+	if partitioning == PartitioningTimescale {
+		hypertable := `SELECT create_hypertable($1, $2, if_not_exists => TRUE, migrate_data => TRUE)`
+		if _, err := db.Exec(hypertable, table, timeColumn); err != nil {
+			return fmt.Errorf("failed to turn %s into a hypertable: %w", table, err)
+		}
+	}
+	return nil
+}
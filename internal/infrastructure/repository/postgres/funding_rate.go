@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// FundingRateRepository is a repository for the funding rate snapshots of an exchange.
+type FundingRateRepository struct {
+	db       *sql.DB
+	exchange string
+	ops      *instrument.Scope
+}
+
+func (r *FundingRateRepository) init() error {
+	fundingRateTable := `
+	CREATE TABLE IF NOT EXISTS funding_rates (
+	  exchange TEXT NOT NULL,
+	  symbol TEXT NOT NULL,
+	  rate DOUBLE PRECISION,
+	  mark_price DOUBLE PRECISION,
+	  next_funding_at TIMESTAMPTZ,
+	  event_at TIMESTAMPTZ,
+	  created_at TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS funding_rates_exchange_symbol_created_at ON funding_rates (exchange, symbol, created_at);
+	`
+	if _, err := r.db.Exec(fundingRateTable); err != nil {
+		return fmt.Errorf("failed to create funding_rates table: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts a snapshot of funding rates in a single transaction.
+func (r *FundingRateRepository) CreateMany(ctx context.Context, rates []domain.FundingRate) (err error) {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO funding_rates (exchange, symbol, rate, mark_price, next_funding_at, event_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	defer r.ops.Start("funding_rate.create_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare funding rate insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, f := range rates {
+		if _, err := stmt.ExecContext(ctx, r.exchange, string(f.Symbol), f.Rate, f.MarkPrice, f.NextFundingAt, f.EventAt, f.CreatedAt); err != nil {
+			return fmt.Errorf("failed to insert funding rate: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit funding rates: %w", err)
+	}
+	return nil
+}
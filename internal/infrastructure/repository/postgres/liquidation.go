@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// LiquidationRepository is a repository for the liquidations of an exchange.
+type LiquidationRepository struct {
+	db         *sql.DB
+	exchange   string
+	partitions *partitions
+	ops        *instrument.Scope
+}
+
+func (r *LiquidationRepository) init(partitioning string) error {
+	columns := `
+	  exchange TEXT NOT NULL,
+	  event_at TIMESTAMPTZ NOT NULL,
+	  stored_at TIMESTAMPTZ NOT NULL,
+	  window_at TIMESTAMPTZ NOT NULL,
+	  symbol TEXT NOT NULL,
+	  side TEXT NOT NULL,
+	  usd DOUBLE PRECISION NOT NULL,
+	  liquidation JSONB NOT NULL
+	`
+	if err := createTimeTable(r.db, "liquidations", columns, "event_at", partitioning); err != nil {
+		return err
+	}
+	indexes := `
+	CREATE INDEX IF NOT EXISTS liquidations_exchange_window_at ON liquidations (exchange, window_at);
+	CREATE INDEX IF NOT EXISTS liquidations_exchange_event_at ON liquidations (exchange, event_at);
+	`
+	if _, err := r.db.Exec(indexes); err != nil {
+		return fmt.Errorf("failed to create liquidations indexes: %w", err)
+	}
+
+	return nil
+}
+
+// Create inserts a new liquidation into the database.
+func (r *LiquidationRepository) Create(ctx context.Context, l domain.Liquidation) (err error) {
+	query := `INSERT INTO liquidations (exchange, event_at, stored_at, window_at, symbol, side, usd, liquidation)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	defer r.ops.Start("liquidation.create", query).Done(&err)
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal liquidation: %w", err)
+	}
+	if err := r.partitions.ensure(ctx, "liquidations", l.EventAt); err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query, r.exchange, l.EventAt, l.StoredAt, l.WindowTime(), string(l.Order.Symbol), string(l.Order.Side), l.Order.USDValue, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to insert liquidation: %w", err)
+	}
+	return nil
+}
+
+// CreateMany inserts a batch of liquidations in a single transaction.
+func (r *LiquidationRepository) CreateMany(ctx context.Context, liquidations []domain.Liquidation) (err error) {
+	if len(liquidations) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO liquidations (exchange, event_at, stored_at, window_at, symbol, side, usd, liquidation)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	defer r.ops.Start("liquidation.create_many", query).Done(&err)
+
+	for _, l := range liquidations {
+		if err := r.partitions.ensure(ctx, "liquidations", l.EventAt); err != nil {
+			return err
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare liquidation insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, l := range liquidations {
+		data, err := json.Marshal(l)
+		if err != nil {
+			return fmt.Errorf("failed to marshal liquidation: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx, r.exchange, l.EventAt, l.StoredAt, l.WindowTime(), string(l.Order.Symbol), string(l.Order.Side), l.Order.USDValue, string(data)); err != nil {
+			return fmt.Errorf("failed to insert liquidation: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit liquidations: %w", err)
+	}
+	return nil
+}
+
+// GetRange returns the liquidations of the exchange that happened within [from, to).
+func (r *LiquidationRepository) GetRange(ctx context.Context, from, to time.Time) (liquidations []domain.Liquidation, err error) {
+	query := `SELECT liquidation FROM liquidations WHERE exchange = $1 AND event_at >= $2 AND event_at < $3 ORDER BY event_at ASC`
+	defer r.ops.Start("liquidation.get_range", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, r.exchange, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query liquidations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan liquidation row: %w", err)
+		}
+		var liq domain.Liquidation
+		if err := json.Unmarshal(data, &liq); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal liquidation: %w", err)
+		}
+		liquidations = append(liquidations, liq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate liquidation rows: %w", err)
+	}
+	return liquidations, nil
+}
+
+// GetLiquidationsHistory returns the liquidations history of the last 60 seconds, counted by their window time
+// in a single aggregation.
+func (r *LiquidationRepository) GetLiquidationsHistory(ctx context.Context, timeAt time.Time) (history domain.LiquidationsHistory, err error) {
+	query := `SELECT
+	  COUNT(*) FILTER (WHERE side = $2 AND window_at >= $5),
+	  COUNT(*) FILTER (WHERE side = $2 AND window_at >= $6),
+	  COUNT(*) FILTER (WHERE side = $2 AND window_at >= $7),
+	  COUNT(*) FILTER (WHERE side = $2),
+	  COUNT(*) FILTER (WHERE side = $3 AND window_at >= $5),
+	  COUNT(*) FILTER (WHERE side = $3 AND window_at >= $6),
+	  COUNT(*) FILTER (WHERE side = $3 AND window_at >= $8)
+	FROM liquidations WHERE exchange = $1 AND window_at BETWEEN $9 AND $4`
+	defer r.ops.Start("liquidation.get_history", query).Done(&err)
+
+	// long liquidations are forced sells, short liquidations forced buys
+	err = r.db.QueryRowContext(ctx, query,
+		r.exchange, string(domain.OrderSideSell), string(domain.OrderSideBuy), timeAt,
+		timeAt.Add(-time.Second), timeAt.Add(-2*time.Second), timeAt.Add(-5*time.Second), timeAt.Add(-10*time.Second),
+		timeAt.Add(-60*time.Second),
+	).Scan(
+		&history.LongLiquidations1s, &history.LongLiquidations2s, &history.LongLiquidations5s, &history.LongLiquidations60s,
+		&history.ShortLiquidations1s, &history.ShortLiquidations2s, &history.ShortLiquidations10s,
+	)
+	if err != nil {
+		return domain.LiquidationsHistory{}, fmt.Errorf("failed to count liquidations: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// OutageRepository is a repository for exchange outage records.
+type OutageRepository struct {
+	db  *sql.DB
+	ops *instrument.Scope
+}
+
+func (r *OutageRepository) init() error {
+	outageTable := `
+	CREATE TABLE IF NOT EXISTS outages (
+	  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+	  exchange TEXT NOT NULL,
+	  stage TEXT,
+	  reason TEXT,
+	  start_at TIMESTAMPTZ,
+	  end_at TIMESTAMPTZ,
+	  failures INTEGER,
+	  last_error TEXT
+	);
+	CREATE INDEX IF NOT EXISTS outages_exchange_start_at_end_at ON outages (exchange, start_at, end_at);
+	`
+	if _, err := r.db.Exec(outageTable); err != nil {
+		return fmt.Errorf("failed to create outages table: %w", err)
+	}
+
+	return nil
+}
+
+// Create inserts a new outage record into the database.
+func (r *OutageRepository) Create(ctx context.Context, o domain.Outage) (err error) {
+	query := `INSERT INTO outages (exchange, stage, reason, start_at, end_at, failures, last_error) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	defer r.ops.Start("outage.create", query).Done(&err)
+
+	_, err = r.db.ExecContext(ctx, query, o.Exchange, o.Stage, string(o.Reason), o.StartAt, o.EndAt, o.Failures, o.LastError)
+	if err != nil {
+		return fmt.Errorf("failed to insert outage: %w", err)
+	}
+	return nil
+}
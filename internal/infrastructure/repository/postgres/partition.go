@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// partitions creates the monthly partitions of the tables partitioned by time, each once per process
+type partitions struct {
+	db      *sql.DB
+	mu      sync.Mutex
+	created map[string]struct{}
+}
+
+func newPartitions(db *sql.DB) *partitions {
+	return &partitions{db: db, created: make(map[string]struct{})}
+}
+
+// ensure creates the partition of the table covering the month of at unless it was created already,
+// nothing is done on a nil receiver, for the tables not partitioned monthly
+func (p *partitions) ensure(ctx context.Context, table string, at time.Time) error {
+	if p == nil {
+		return nil
+	}
+	name, from, to := monthPartition(table, at)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.created[name]; ok {
+		return nil
+	}
+
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+		name, table, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	if _, err := p.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create partition %s: %w", name, err)
+	}
+	p.created[name] = struct{}{}
+	return nil
+}
+
+// monthPartition returns the name and bounds [from, to) of the partition of the table covering the UTC month of at
+func monthPartition(table string, at time.Time) (string, time.Time, time.Time) {
+	at = at.UTC()
+	from := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return fmt.Sprintf("%s_%s", table, from.Format("2006_01")), from, from.AddDate(0, 1, 0)
+}
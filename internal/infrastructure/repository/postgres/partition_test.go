@@ -0,0 +1,17 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonthPartition(t *testing.T) {
+	at := time.Date(2024, 12, 31, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+
+	name, from, to := monthPartition("ticks", at)
+	assert.Equal(t, "ticks_2025_01", name, "partitions follow the UTC month")
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), to)
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// SubTickRepository is a repository for the high-resolution sub-ticks of an exchange.
+type SubTickRepository struct {
+	db       *sql.DB
+	exchange string
+	ops      *instrument.Scope
+}
+
+func (r *SubTickRepository) init() error {
+	subTickTable := `
+	CREATE TABLE IF NOT EXISTS sub_ticks (
+	  exchange TEXT NOT NULL,
+	  symbol TEXT NOT NULL,
+	  event_at TIMESTAMPTZ NOT NULL,
+	  created_at TIMESTAMPTZ NOT NULL,
+	  ask DOUBLE PRECISION,
+	  bid DOUBLE PRECISION,
+	  ask_qty DOUBLE PRECISION,
+	  bid_qty DOUBLE PRECISION
+	);
+	CREATE INDEX IF NOT EXISTS sub_ticks_exchange_symbol_created_at ON sub_ticks (exchange, symbol, created_at);
+	`
+	if _, err := r.db.Exec(subTickTable); err != nil {
+		return fmt.Errorf("failed to create sub_ticks table: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts a batch of sub-ticks in a single transaction.
+func (r *SubTickRepository) CreateMany(ctx context.Context, subTicks []domain.SubTick) (err error) {
+	if len(subTicks) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO sub_ticks (exchange, symbol, event_at, created_at, ask, bid, ask_qty, bid_qty) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	defer r.ops.Start("sub_tick.create_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare sub-tick insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range subTicks {
+		if _, err := stmt.ExecContext(ctx, r.exchange, string(s.Symbol), s.EventAt, s.CreatedAt, s.Ask, s.Bid, s.AskQty, s.BidQty); err != nil {
+			return fmt.Errorf("failed to insert sub-tick: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sub-ticks: %w", err)
+	}
+	return nil
+}
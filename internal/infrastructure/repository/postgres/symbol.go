@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// SymbolRepository is a repository for the symbol registry of an exchange.
+type SymbolRepository struct {
+	db       *sql.DB
+	exchange string
+	ops      *instrument.Scope
+}
+
+func (r *SymbolRepository) init() error {
+	symbolTable := `
+	CREATE TABLE IF NOT EXISTS symbols (
+	  exchange TEXT NOT NULL,
+	  symbol TEXT NOT NULL,
+	  inst_type TEXT,
+	  base TEXT,
+	  quote TEXT,
+	  contract_value DOUBLE PRECISION,
+	  status TEXT NOT NULL,
+	  first_seen_at TIMESTAMPTZ NOT NULL,
+	  last_seen_at TIMESTAMPTZ NOT NULL,
+	  delisted_at TIMESTAMPTZ,
+	  PRIMARY KEY (exchange, symbol)
+	);
+	`
+	if _, err := r.db.Exec(symbolTable); err != nil {
+		return fmt.Errorf("failed to create symbols table: %w", err)
+	}
+
+	return nil
+}
+
+// SaveMany upserts a batch of symbols in a single transaction.
+func (r *SymbolRepository) SaveMany(ctx context.Context, symbols []domain.Symbol) (err error) {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO symbols (exchange, symbol, inst_type, base, quote, contract_value, status, first_seen_at, last_seen_at, delisted_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	ON CONFLICT (exchange, symbol) DO UPDATE SET
+	  inst_type = EXCLUDED.inst_type,
+	  base = EXCLUDED.base,
+	  quote = EXCLUDED.quote,
+	  contract_value = EXCLUDED.contract_value,
+	  status = EXCLUDED.status,
+	  first_seen_at = EXCLUDED.first_seen_at,
+	  last_seen_at = EXCLUDED.last_seen_at,
+	  delisted_at = EXCLUDED.delisted_at`
+	defer r.ops.Start("symbol.save_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare symbol upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range symbols {
+		delistedAt := sql.NullTime{Time: s.DelistedAt, Valid: !s.DelistedAt.IsZero()}
+		if _, err := stmt.ExecContext(ctx, r.exchange, string(s.Symbol), s.InstType, s.Base, s.Quote, s.ContractValue, string(s.Status),
+			s.FirstSeenAt, s.LastSeenAt, delistedAt); err != nil {
+			return fmt.Errorf("failed to upsert symbol: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit symbols: %w", err)
+	}
+	return nil
+}
+
+// GetAll returns the stored symbols of the exchange.
+func (r *SymbolRepository) GetAll(ctx context.Context) (symbols []domain.Symbol, err error) {
+	query := `SELECT symbol, COALESCE(inst_type, ''), COALESCE(base, ''), COALESCE(quote, ''), COALESCE(contract_value, 0), status,
+	first_seen_at, last_seen_at, delisted_at
+	FROM symbols WHERE exchange = $1`
+	defer r.ops.Start("symbol.get_all", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, r.exchange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbols: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			s          domain.Symbol
+			delistedAt sql.NullTime
+		)
+		if err := rows.Scan(&s.Symbol, &s.InstType, &s.Base, &s.Quote, &s.ContractValue, &s.Status,
+			&s.FirstSeenAt, &s.LastSeenAt, &delistedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol row: %w", err)
+		}
+		s.DelistedAt = delistedAt.Time
+		symbols = append(symbols, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate symbol rows: %w", err)
+	}
+	return symbols, nil
+}
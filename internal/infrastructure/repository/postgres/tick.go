@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// TickRepository is a repository for the ticks of an exchange.
+type TickRepository struct {
+	db         *sql.DB
+	exchange   string
+	partitions *partitions
+	ops        *instrument.Scope
+}
+
+func (r *TickRepository) init(partitioning string) error {
+	columns := `
+	  exchange TEXT NOT NULL,
+	  start_second TIMESTAMPTZ NOT NULL,
+	  start_at TIMESTAMPTZ NOT NULL,
+	  created_at TIMESTAMPTZ NOT NULL,
+	  tick JSONB NOT NULL,
+	  PRIMARY KEY (exchange, start_second)
+	`
+	if err := createTimeTable(r.db, "ticks", columns, "start_second", partitioning); err != nil {
+		return err
+	}
+	index := `CREATE INDEX IF NOT EXISTS ticks_exchange_created_at ON ticks (exchange, created_at)`
+	if _, err := r.db.Exec(index); err != nil {
+		return fmt.Errorf("failed to create ticks index: %w", err)
+	}
+
+	return nil
+}
+
+// Create upserts a tick, replacing the one stored for the same exchange and second.
+func (r *TickRepository) Create(ctx context.Context, ts domain.Tick) (err error) {
+	query := `INSERT INTO ticks (exchange, start_second, start_at, created_at, tick) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (exchange, start_second) DO UPDATE SET
+	  start_at = excluded.start_at,
+	  created_at = excluded.created_at,
+	  tick = excluded.tick`
+	defer r.ops.Start("tick.create", query).Done(&err)
+
+	data, err := json.Marshal(ts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tick: %w", err)
+	}
+	if err := r.partitions.ensure(ctx, "ticks", ts.StorageKey()); err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, query, r.exchange, ts.StorageKey(), ts.StartAt, ts.CreatedAt, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to upsert tick: %w", err)
+	}
+	return nil
+}
+
+// GetHistorySince returns all ticks of the exchange created since the given time.
+func (r *TickRepository) GetHistorySince(ctx context.Context, since time.Time) (ticks []domain.Tick, err error) {
+	query := `SELECT tick FROM ticks WHERE exchange = $1 AND created_at >= $2 ORDER BY created_at ASC`
+	defer r.ops.Start("tick.get_history_since", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, r.exchange, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTicks(rows)
+}
+
+// GetPage returns at most limit ticks of the exchange created since the given time.
+func (r *TickRepository) GetPage(ctx context.Context, since time.Time, limit int) (ticks []domain.Tick, err error) {
+	query := `SELECT tick FROM ticks WHERE exchange = $1 AND created_at >= $2 ORDER BY created_at ASC LIMIT $3`
+	defer r.ops.Start("tick.get_page", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, r.exchange, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTicks(rows)
+}
+
+// GetRange returns all ticks of the exchange created within [from, to).
+func (r *TickRepository) GetRange(ctx context.Context, from, to time.Time) (ticks []domain.Tick, err error) {
+	query := `SELECT tick FROM ticks WHERE exchange = $1 AND created_at >= $2 AND created_at < $3 ORDER BY created_at ASC`
+	defer r.ops.Start("tick.get_range", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, r.exchange, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTicks(rows)
+}
+
+// scanTicks decodes the tick documents of the rows.
+func scanTicks(rows *sql.Rows) ([]domain.Tick, error) {
+	var ticks []domain.Tick
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to scan tick row: %w", err)
+		}
+		var tick domain.Tick
+		if err := json.Unmarshal(data, &tick); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tick: %w", err)
+		}
+		ticks = append(ticks, tick)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tick rows: %w", err)
+	}
+	return ticks, nil
+}
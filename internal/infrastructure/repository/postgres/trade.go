@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// TradeRepository is a repository for the aggregated trades of an exchange.
+type TradeRepository struct {
+	db       *sql.DB
+	exchange string
+	ops      *instrument.Scope
+}
+
+func (r *TradeRepository) init() error {
+	tradeTable := `
+	CREATE TABLE IF NOT EXISTS trades (
+	  exchange TEXT NOT NULL,
+	  symbol TEXT NOT NULL,
+	  trade_id BIGINT,
+	  side TEXT NOT NULL,
+	  price DOUBLE PRECISION,
+	  quantity DOUBLE PRECISION,
+	  usd_value DOUBLE PRECISION,
+	  event_at TIMESTAMPTZ NOT NULL,
+	  stored_at TIMESTAMPTZ NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS trades_exchange_symbol_event_at ON trades (exchange, symbol, event_at);
+	`
+	if _, err := r.db.Exec(tradeTable); err != nil {
+		return fmt.Errorf("failed to create trades table: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts a batch of trades in a single transaction.
+func (r *TradeRepository) CreateMany(ctx context.Context, trades []domain.Trade) (err error) {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO trades (exchange, symbol, trade_id, side, price, quantity, usd_value, event_at, stored_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	defer r.ops.Start("trade.create_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare trade insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, t := range trades {
+		if _, err := stmt.ExecContext(ctx, r.exchange, string(t.Symbol), t.ID, string(t.Side), t.Price, t.Quantity, t.USDValue, t.EventAt, t.StoredAt); err != nil {
+			return fmt.Errorf("failed to insert trade: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trades: %w", err)
+	}
+	return nil
+}
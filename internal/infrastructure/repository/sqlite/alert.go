@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// AlertRepository is a repository for the fired market alerts.
+type AlertRepository struct {
+	db  *sql.DB
+	ops *instrument.Scope
+}
+
+func (r *AlertRepository) init() error {
+	alertTable := `
+	CREATE TABLE IF NOT EXISTS alerts (
+	  id INTEGER PRIMARY KEY AUTOINCREMENT,
+	  exchange TEXT,
+	  notifier TEXT,
+	  tick_key DATETIME,
+	  thresholds_json TEXT,
+	  symbols_json TEXT,
+	  message TEXT,
+	  created_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS alerts_exchange_tick_key ON alerts (exchange, tick_key);
+	`
+	if _, err := r.db.Exec(alertTable); err != nil {
+		return fmt.Errorf("failed to create alerts table: %w", err)
+	}
+
+	return nil
+}
+
+// Create inserts a fired alert into the database.
+func (r *AlertRepository) Create(ctx context.Context, a domain.Alert) (err error) {
+	query := `INSERT INTO alerts (exchange, notifier, tick_key, thresholds_json, symbols_json, message, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	defer r.ops.Start("alert.create", query).Done(&err)
+
+	thresholds, err := json.Marshal(a.Thresholds)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert thresholds: %w", err)
+	}
+	symbols, err := json.Marshal(a.Symbols)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert symbols: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, query, a.Exchange, a.Notifier, a.TickKey, string(thresholds), string(symbols), a.Message, a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert alert: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build cgo && !sqlite_purego
+
+package sqlite
+
+import (
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3" // registers the cgo driver
+)
+
+// Driver is the name of the database/sql driver of the build: the cgo driver by default,
+// the pure-Go one in builds without cgo or with the sqlite_purego tag
+const Driver = "sqlite3"
+
+// DSN returns the data source name of the database file at path, with foreign keys enforced
+func DSN(path string) string {
+	return fmt.Sprintf("file:%s?cache=shared&_foreign_keys=on", path)
+}
@@ -0,0 +1,19 @@
+//go:build !cgo || sqlite_purego
+
+package sqlite
+
+import (
+	"fmt"
+
+	_ "modernc.org/sqlite" // registers the pure-Go driver
+)
+
+// Driver is the name of the database/sql driver of the build: the cgo driver by default,
+// the pure-Go one in builds without cgo or with the sqlite_purego tag
+const Driver = "sqlite"
+
+// DSN returns the data source name of the database file at path, with foreign keys enforced. Times are written
+// in the format of the cgo driver, so a database file can be opened by either build
+func DSN(path string) string {
+	return fmt.Sprintf("file:%s?cache=shared&_pragma=foreign_keys(1)&_time_format=sqlite", path)
+}
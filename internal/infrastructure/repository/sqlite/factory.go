@@ -1,32 +1,53 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
 )
 
 // Factory implements a repository factory using SQLite.
 type Factory struct {
-	db *sql.DB
+	db    *sql.DB
+	codec codec.Codec
+	ops   *instrument.Recorder
 }
 
-// NewSQLiteRepoFactory opens (or creates) a SQLite database file (dsn)
+// NewSQLiteRepoFactory opens (or creates) a SQLite database file (dsn, see DSN) with the driver of the build
 // and creates the necessary tables if they do not exist.
 func NewSQLiteRepoFactory(dsn string) (*Factory, error) {
-	db, err := sql.Open("sqlite3", dsn)
+	db, err := sql.Open(Driver, dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite db: %w", err)
 	}
 
-	return &Factory{db: db}, nil
+	return &Factory{db: db, codec: codec.JSON}, nil
+}
+
+// SetCodec sets the codec the repositories created afterwards store ticks and liquidations with, JSON by default.
+// Rows keep the name of their codec, so changing it doesn't break reading the rows stored before
+func (f *Factory) SetCodec(c codec.Codec) error {
+	f.codec = c
+	return nil
+}
+
+// WithInstrumentation makes the repositories created afterwards record their operations.
+func (f *Factory) WithInstrumentation(recorder *instrument.Recorder) *Factory {
+	f.ops = recorder
+	return f
 }
 
 // GetTickRepository returns a TickRepository instance.
-func (f *Factory) GetTickRepository(_ string) (domain.TickRepository, error) {
+func (f *Factory) GetTickRepository(name string) (domain.TickRepository, error) {
 	repo := &TickRepository{
-		db: f.db,
+		db:       f.db,
+		codec:    f.codec,
+		exchange: name,
+		ops:      f.ops.Scope("ticks"),
 	}
 	if err := repo.init(); err != nil {
 		return nil, err
@@ -37,10 +58,151 @@ func (f *Factory) GetTickRepository(_ string) (domain.TickRepository, error) {
 // GetLiquidationRepository returns a LiquidationRepository instance.
 func (f *Factory) GetLiquidationRepository(_ string) (domain.LiquidationRepository, error) {
 	repo := &LiquidationRepository{
-		db: f.db,
+		db:    f.db,
+		codec: f.codec,
+		ops:   f.ops.Scope("liquidations"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetSubTickRepository returns a SubTickRepository instance.
+func (f *Factory) GetSubTickRepository(_ string) (domain.SubTickRepository, error) {
+	repo := &SubTickRepository{
+		db:  f.db,
+		ops: f.ops.Scope("sub_ticks"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetBarRepository returns a BarRepository instance.
+func (f *Factory) GetBarRepository(_ string) (domain.BarRepository, error) {
+	repo := &BarRepository{
+		db:  f.db,
+		ops: f.ops.Scope("bars"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetTradeRepository returns a TradeRepository instance.
+func (f *Factory) GetTradeRepository(_ string) (domain.TradeRepository, error) {
+	repo := &TradeRepository{
+		db:  f.db,
+		ops: f.ops.Scope("trades"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetSymbolRepository returns a SymbolRepository instance.
+func (f *Factory) GetSymbolRepository(_ string) (domain.SymbolRepository, error) {
+	repo := &SymbolRepository{
+		db:  f.db,
+		ops: f.ops.Scope("symbols"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetFundingRateRepository returns a FundingRateRepository instance.
+func (f *Factory) GetFundingRateRepository(_ string) (domain.FundingRateRepository, error) {
+	repo := &FundingRateRepository{
+		db:  f.db,
+		ops: f.ops.Scope("funding_rates"),
 	}
 	if err := repo.init(); err != nil {
 		return nil, err
 	}
 	return repo, nil
 }
+
+// GetRecomputedTickRepository returns a RecomputedTickRepository instance.
+func (f *Factory) GetRecomputedTickRepository(_ string) (domain.RecomputedTickRepository, error) {
+	repo := &RecomputedTickRepository{
+		db:    f.db,
+		codec: f.codec,
+		ops:   f.ops.Scope("recomputed_ticks"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetOutageRepository returns an OutageRepository instance.
+func (f *Factory) GetOutageRepository(_ string) (domain.OutageRepository, error) {
+	repo := &OutageRepository{
+		db:  f.db,
+		ops: f.ops.Scope("outages"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GetAlertRepository returns an AlertRepository instance.
+func (f *Factory) GetAlertRepository(_ string) (domain.AlertRepository, error) {
+	repo := &AlertRepository{
+		db:  f.db,
+		ops: f.ops.Scope("alerts"),
+	}
+	if err := repo.init(); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Close closes the database once pending statements are done.
+func (f *Factory) Close(_ context.Context) error {
+	return f.db.Close()
+}
+
+// addColumn adds the column to the table unless it already exists.
+func addColumn(db *sql.DB, table, column, definition string) error {
+	var exists bool
+	query := `SELECT COUNT(*) > 0 FROM pragma_table_info(?) WHERE name = ?`
+	if err := db.QueryRow(query, table, column).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to inspect %s table: %w", table, err)
+	}
+	if exists {
+		return nil
+	}
+	if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s column to %s table: %w", column, table, err)
+	}
+	return nil
+}
+
+// encode serializes v with the codec. JSON is stored as text so the rows stay readable in the sqlite3 shell
+func encode(c codec.Codec, v any) (any, error) {
+	data, err := c.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if c == codec.JSON {
+		return string(data), nil
+	}
+	return data, nil
+}
+
+// decode deserializes a row stored with the named codec, NULL for the rows stored before codecs existed
+func decode(name sql.NullString, data []byte, v any) error {
+	c, err := codec.Lookup(name.String)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(data, v)
+}
@@ -0,0 +1,47 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFactory_Ticks stores and reads ticks back with the driver of the build, run it with
+// CGO_ENABLED=0 or -tags sqlite_purego to cover the pure-Go driver
+func TestFactory_Ticks(t *testing.T) {
+	f, err := NewSQLiteRepoFactory(DSN(filepath.Join(t.TempDir(), "ticks.db")))
+	require.NoError(t, err)
+	defer f.Close(context.Background())
+
+	repo, err := f.GetTickRepository("binance-perp")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	for n := range 3 {
+		start := at.Add(time.Duration(n) * time.Second)
+		require.NoError(t, repo.Create(ctx, domain.Tick{StartAt: start, CreatedAt: start.Add(100 * time.Millisecond)}))
+	}
+	// stored again for the same second, it replaces the tick
+	require.NoError(t, repo.Create(ctx, domain.Tick{StartAt: at.Add(2 * time.Second), CreatedAt: at.Add(2500 * time.Millisecond)}))
+
+	ticks, err := repo.GetHistorySince(ctx, at.Add(time.Second))
+	require.NoError(t, err)
+	require.Len(t, ticks, 2)
+	assert.True(t, ticks[0].StartAt.Equal(at.Add(time.Second)))
+	assert.True(t, ticks[1].CreatedAt.Equal(at.Add(2500*time.Millisecond)))
+
+	ticks, err = repo.GetRange(ctx, at, at.Add(time.Second))
+	require.NoError(t, err)
+	assert.Len(t, ticks, 1)
+
+	ticks, err = repo.(domain.TickPageRepository).GetPage(ctx, at, 2)
+	require.NoError(t, err)
+	require.Len(t, ticks, 2)
+	assert.True(t, ticks[1].StartAt.Equal(at.Add(time.Second)))
+}
@@ -3,16 +3,19 @@ package sqlite
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
 )
 
 // LiquidationRepository is a repository for liquidations.
 type LiquidationRepository struct {
-	db *sql.DB
+	db    *sql.DB
+	codec codec.Codec
+	ops   *instrument.Scope
 }
 
 func (r *LiquidationRepository) init() error {
@@ -27,46 +30,249 @@ func (r *LiquidationRepository) init() error {
 	if _, err := r.db.Exec(liqTable); err != nil {
 		return fmt.Errorf("failed to create ticks table: %w", err)
 	}
+	// rows stored before codecs existed keep a NULL codec and are read as JSON
+	if err := addColumn(r.db, "liquidations", "codec", "TEXT"); err != nil {
+		return err
+	}
+	// rows stored before window times existed keep a NULL window_at and are counted at their event time
+	if err := addColumn(r.db, "liquidations", "window_at", "DATETIME"); err != nil {
+		return err
+	}
+	// rows stored before the leaderboards existed keep NULL symbol, side and usd and are left out of them
+	for _, column := range []struct{ name, definition string }{{"symbol", "TEXT"}, {"side", "TEXT"}, {"usd", "REAL"}} {
+		if err := addColumn(r.db, "liquidations", column.name, column.definition); err != nil {
+			return err
+		}
+	}
+	index := `CREATE INDEX IF NOT EXISTS liquidations_event_at_usd ON liquidations (event_at, usd)`
+	if _, err := r.db.Exec(index); err != nil {
+		return fmt.Errorf("failed to create liquidations index: %w", err)
+	}
 
 	return nil
 }
 
 // Create inserts a new liquidation into the database.
-func (r *LiquidationRepository) Create(ctx context.Context, l domain.Liquidation) error {
-	data, err := json.Marshal(l)
+func (r *LiquidationRepository) Create(ctx context.Context, l domain.Liquidation) (err error) {
+	query := `INSERT INTO liquidations (event_at, stored_at, window_at, symbol, side, usd, liquidation_json, codec) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	defer r.ops.Start("liquidation.create", query).Done(&err)
+
+	data, err := encode(r.codec, l)
 	if err != nil {
 		return fmt.Errorf("failed to marshal liquidation: %w", err)
 	}
-	query := `INSERT INTO liquidations (event_at, stored_at, liquidation_json) VALUES (?, ?, ?)`
-	_, err = r.db.ExecContext(ctx, query, l.EventAt, l.StoredAt, string(data))
+	_, err = r.db.ExecContext(ctx, query, l.EventAt, l.StoredAt, l.WindowTime(), l.Order.Symbol, l.Order.Side, l.Order.USDValue, data, r.codec.Name())
 	if err != nil {
 		return fmt.Errorf("failed to insert liquidation: %w", err)
 	}
 	return nil
 }
 
-// GetLiquidationsHistory returns the liquidations history for the last 60 seconds.
-func (r *LiquidationRepository) GetLiquidationsHistory(ctx context.Context, timeAt time.Time) (domain.LiquidationsHistory, error) {
+// CreateMany inserts a batch of liquidations in a single transaction.
+func (r *LiquidationRepository) CreateMany(ctx context.Context, liquidations []domain.Liquidation) (err error) {
+	if len(liquidations) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO liquidations (event_at, stored_at, window_at, symbol, side, usd, liquidation_json, codec) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	defer r.ops.Start("liquidation.create_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare liquidation insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, l := range liquidations {
+		data, err := encode(r.codec, l)
+		if err != nil {
+			return fmt.Errorf("failed to marshal liquidation: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx, l.EventAt, l.StoredAt, l.WindowTime(), l.Order.Symbol, l.Order.Side, l.Order.USDValue, data, r.codec.Name()); err != nil {
+			return fmt.Errorf("failed to insert liquidation: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit liquidations: %w", err)
+	}
+	return nil
+}
+
+// CreateMissing inserts the liquidations without a stored match, see domain.Liquidation.SameAs.
+func (r *LiquidationRepository) CreateMissing(ctx context.Context, liquidations []domain.Liquidation) (created int, err error) {
+	defer r.ops.Start("liquidation.create_missing", fmt.Sprintf("%d liquidations", len(liquidations))).Done(&err)
+
+	for _, l := range liquidations {
+		exists, err := r.exists(ctx, l)
+		if err != nil {
+			return created, err
+		}
+		if exists {
+			continue
+		}
+
+		if err := r.Create(ctx, l); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// exists reports whether a liquidation matching l is stored.
+func (r *LiquidationRepository) exists(ctx context.Context, l domain.Liquidation) (bool, error) {
+	query := `SELECT liquidation_json, codec FROM liquidations WHERE event_at BETWEEN ? AND ?`
+	rows, err := r.db.QueryContext(ctx, query, l.EventAt.Add(-domain.LiquidationMatchWindow), l.EventAt.Add(domain.LiquidationMatchWindow))
+	if err != nil {
+		return false, fmt.Errorf("failed to query liquidations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			data      []byte
+			codecName sql.NullString
+		)
+		if err := rows.Scan(&data, &codecName); err != nil {
+			return false, fmt.Errorf("failed to scan liquidation row: %w", err)
+		}
+		var stored domain.Liquidation
+		if err := decode(codecName, data, &stored); err != nil {
+			return false, fmt.Errorf("failed to unmarshal liquidation: %w", err)
+		}
+		if stored.SameAs(l) {
+			return true, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to iterate liquidation rows: %w", err)
+	}
+	return false, nil
+}
+
+// GetRange returns the liquidations that happened within [from, to).
+func (r *LiquidationRepository) GetRange(ctx context.Context, from, to time.Time) (liquidations []domain.Liquidation, err error) {
+	query := `SELECT liquidation_json, codec FROM liquidations WHERE event_at >= ? AND event_at < ? ORDER BY event_at ASC`
+	defer r.ops.Start("liquidation.get_range", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query liquidations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			data      []byte
+			codecName sql.NullString
+		)
+		if err := rows.Scan(&data, &codecName); err != nil {
+			return nil, fmt.Errorf("failed to scan liquidation row: %w", err)
+		}
+		var liq domain.Liquidation
+		if err := decode(codecName, data, &liq); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal liquidation: %w", err)
+		}
+		liquidations = append(liquidations, liq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate liquidation rows: %w", err)
+	}
+	return liquidations, nil
+}
+
+// TopSymbolsByNotional returns the limit symbols with the largest USD notional liquidated within [from, to).
+func (r *LiquidationRepository) TopSymbolsByNotional(ctx context.Context, from, to time.Time, limit int) (top []domain.LiquidationSymbolNotional, err error) {
+	query := `SELECT symbol, SUM(usd) AS notional,
+		SUM(CASE WHEN side = 'SELL' THEN usd ELSE 0 END), SUM(CASE WHEN side = 'BUY' THEN usd ELSE 0 END), COUNT(*)
+		FROM liquidations WHERE event_at >= ? AND event_at < ? AND symbol IS NOT NULL
+		GROUP BY symbol ORDER BY notional DESC, symbol ASC LIMIT ?`
+	defer r.ops.Start("liquidation.top_symbols", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query liquidation totals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var total domain.LiquidationSymbolNotional
+		if err := rows.Scan(&total.Symbol, &total.Notional, &total.Long, &total.Short, &total.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan liquidation totals row: %w", err)
+		}
+		top = append(top, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate liquidation totals rows: %w", err)
+	}
+	return top, nil
+}
+
+// LargestLiquidations returns the limit liquidations with the largest USD value within [from, to).
+func (r *LiquidationRepository) LargestLiquidations(ctx context.Context, from, to time.Time, limit int) (liquidations []domain.Liquidation, err error) {
+	query := `SELECT liquidation_json, codec FROM liquidations WHERE event_at >= ? AND event_at < ? AND usd IS NOT NULL
+		ORDER BY usd DESC, event_at ASC LIMIT ?`
+	defer r.ops.Start("liquidation.largest", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query liquidations: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			data      []byte
+			codecName sql.NullString
+		)
+		if err := rows.Scan(&data, &codecName); err != nil {
+			return nil, fmt.Errorf("failed to scan liquidation row: %w", err)
+		}
+		var liq domain.Liquidation
+		if err := decode(codecName, data, &liq); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal liquidation: %w", err)
+		}
+		liquidations = append(liquidations, liq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate liquidation rows: %w", err)
+	}
+	return liquidations, nil
+}
+
+// GetLiquidationsHistory returns the liquidations history for the last 60 seconds, counted by their window time.
+func (r *LiquidationRepository) GetLiquidationsHistory(ctx context.Context, timeAt time.Time) (history domain.LiquidationsHistory, err error) {
 	// For simplicity, consider a window of the last 60 seconds.
 	windowStart := timeAt.Add(-60 * time.Second)
-	query := `SELECT liquidation_json FROM liquidations WHERE event_at BETWEEN ? AND ?`
+	query := `SELECT liquidation_json, codec FROM liquidations WHERE COALESCE(window_at, event_at) BETWEEN ? AND ?`
+	defer r.ops.Start("liquidation.get_history", query).Done(&err)
+
 	rows, err := r.db.QueryContext(ctx, query, windowStart, timeAt)
 	if err != nil {
 		return domain.LiquidationsHistory{}, fmt.Errorf("failed to query liquidations: %w", err)
 	}
 	defer rows.Close()
 
-	var history domain.LiquidationsHistory
 	for rows.Next() {
-		var liqJSON string
-		if err := rows.Scan(&liqJSON); err != nil {
+		var (
+			data      []byte
+			codecName sql.NullString
+		)
+		if err := rows.Scan(&data, &codecName); err != nil {
 			return domain.LiquidationsHistory{}, fmt.Errorf("failed to scan liquidation row: %w", err)
 		}
 		var liq domain.Liquidation
-		if err := json.Unmarshal([]byte(liqJSON), &liq); err != nil {
+		if err := decode(codecName, data, &liq); err != nil {
 			return domain.LiquidationsHistory{}, fmt.Errorf("failed to unmarshal liquidation: %w", err)
 		}
-		delta := timeAt.Sub(liq.EventAt).Seconds()
+		delta := timeAt.Sub(liq.WindowTime()).Seconds()
 
 		// For long liquidations, the order side should be SELL.
 		if liq.Order.Side == domain.OrderSideSell {
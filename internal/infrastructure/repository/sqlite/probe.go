@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Probe writes a row to the selfcheck table, reads it back and deletes it, checking the database is writable
+// without leaving anything behind in the tables of the importer.
+func (f *Factory) Probe(ctx context.Context, name string) error {
+	if _, err := f.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS selfcheck (id TEXT PRIMARY KEY, at DATETIME)`); err != nil {
+		return fmt.Errorf("failed to create selfcheck table: %w", err)
+	}
+
+	id := fmt.Sprintf("%s-%d", name, time.Now().UnixNano())
+	if _, err := f.db.ExecContext(ctx, `INSERT INTO selfcheck (id, at) VALUES (?, ?)`, id, time.Now().UTC()); err != nil {
+		return fmt.Errorf("failed to write probe row: %w", err)
+	}
+
+	var readID string
+	if err := f.db.QueryRowContext(ctx, `SELECT id FROM selfcheck WHERE id = ?`, id).Scan(&readID); err != nil {
+		return fmt.Errorf("failed to read probe row back: %w", err)
+	}
+
+	if _, err := f.db.ExecContext(ctx, `DELETE FROM selfcheck WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete probe row: %w", err)
+	}
+	return nil
+}
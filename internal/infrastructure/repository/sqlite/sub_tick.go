@@ -0,0 +1,70 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// SubTickRepository is a repository for high-resolution sub-ticks.
+type SubTickRepository struct {
+	db  *sql.DB
+	ops *instrument.Scope
+}
+
+func (r *SubTickRepository) init() error {
+	subTickTable := `
+	CREATE TABLE IF NOT EXISTS sub_ticks (
+	  id INTEGER PRIMARY KEY AUTOINCREMENT,
+	  symbol TEXT,
+	  event_at DATETIME,
+	  created_at DATETIME,
+	  ask REAL,
+	  bid REAL,
+	  ask_qty REAL,
+	  bid_qty REAL
+	);
+	CREATE INDEX IF NOT EXISTS sub_ticks_symbol_created_at ON sub_ticks (symbol, created_at);
+	`
+	if _, err := r.db.Exec(subTickTable); err != nil {
+		return fmt.Errorf("failed to create sub_ticks table: %w", err)
+	}
+
+	return nil
+}
+
+// CreateMany inserts a batch of sub-ticks in a single transaction.
+func (r *SubTickRepository) CreateMany(ctx context.Context, subTicks []domain.SubTick) (err error) {
+	if len(subTicks) == 0 {
+		return nil
+	}
+
+	query := `INSERT INTO sub_ticks (symbol, event_at, created_at, ask, bid, ask_qty, bid_qty) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	defer r.ops.Start("sub_tick.create_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare sub-tick insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range subTicks {
+		if _, err := stmt.ExecContext(ctx, string(s.Symbol), s.EventAt, s.CreatedAt, s.Ask, s.Bid, s.AskQty, s.BidQty); err != nil {
+			return fmt.Errorf("failed to insert sub-tick: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sub-ticks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+)
+
+// SymbolRepository is a repository for the symbol registry.
+type SymbolRepository struct {
+	db  *sql.DB
+	ops *instrument.Scope
+}
+
+func (r *SymbolRepository) init() error {
+	symbolTable := `
+	CREATE TABLE IF NOT EXISTS symbols (
+	  symbol TEXT PRIMARY KEY,
+	  inst_type TEXT,
+	  base TEXT,
+	  quote TEXT,
+	  contract_value REAL,
+	  status TEXT,
+	  first_seen_at DATETIME,
+	  last_seen_at DATETIME,
+	  delisted_at DATETIME
+	);
+	`
+	if _, err := r.db.Exec(symbolTable); err != nil {
+		return fmt.Errorf("failed to create symbols table: %w", err)
+	}
+
+	return nil
+}
+
+// SaveMany upserts a batch of symbols in a single transaction.
+func (r *SymbolRepository) SaveMany(ctx context.Context, symbols []domain.Symbol) (err error) {
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	query := `INSERT OR REPLACE INTO symbols (symbol, inst_type, base, quote, contract_value, status, first_seen_at, last_seen_at, delisted_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	defer r.ops.Start("symbol.save_many", query).Done(&err)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }() // no-op after commit
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare symbol upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range symbols {
+		delistedAt := sql.NullTime{Time: s.DelistedAt, Valid: !s.DelistedAt.IsZero()}
+		if _, err := stmt.ExecContext(ctx, string(s.Symbol), s.InstType, s.Base, s.Quote, s.ContractValue, string(s.Status),
+			s.FirstSeenAt, s.LastSeenAt, delistedAt); err != nil {
+			return fmt.Errorf("failed to upsert symbol: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit symbols: %w", err)
+	}
+	return nil
+}
+
+// GetAll returns the stored symbols.
+func (r *SymbolRepository) GetAll(ctx context.Context) (symbols []domain.Symbol, err error) {
+	query := `SELECT symbol, inst_type, base, quote, contract_value, status, first_seen_at, last_seen_at, delisted_at FROM symbols`
+	defer r.ops.Start("symbol.get_all", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbols: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			s          domain.Symbol
+			delistedAt sql.NullTime
+		)
+		if err := rows.Scan(&s.Symbol, &s.InstType, &s.Base, &s.Quote, &s.ContractValue, &s.Status,
+			&s.FirstSeenAt, &s.LastSeenAt, &delistedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan symbol row: %w", err)
+		}
+		s.DelistedAt = delistedAt.Time
+		symbols = append(symbols, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate symbol rows: %w", err)
+	}
+	return symbols, nil
+}
@@ -3,16 +3,20 @@ package sqlite
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/codec"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
 )
 
 // TickRepository is a repository for ticks.
 type TickRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	codec    codec.Codec
+	exchange string
+	ops      *instrument.Scope
 }
 
 func (r *TickRepository) init() error {
@@ -28,44 +32,103 @@ func (r *TickRepository) init() error {
 		return fmt.Errorf("failed to create ticks table: %w", err)
 	}
 
+	// tables created before ticks were upserted lack the storage key columns, their rows keep NULL keys.
+	// Rows stored before codecs existed keep a NULL codec and are read as JSON
+	for column, definition := range map[string]string{"exchange": "TEXT", "start_second": "DATETIME", "codec": "TEXT"} {
+		if err := addColumn(r.db, "ticks", column, definition); err != nil {
+			return err
+		}
+	}
+	keyIndex := `CREATE UNIQUE INDEX IF NOT EXISTS ticks_storage_key ON ticks (exchange, start_second)`
+	if _, err := r.db.Exec(keyIndex); err != nil {
+		return fmt.Errorf("failed to create ticks storage key index: %w", err)
+	}
+
 	return nil
 }
 
-// Create inserts a new tick into the database.
-func (r *TickRepository) Create(ctx context.Context, ts domain.Tick) error {
-	// Serialize the tick to JSON.
-	data, err := json.Marshal(ts)
+// Create upserts a tick, replacing the one stored for the same exchange and second.
+func (r *TickRepository) Create(ctx context.Context, ts domain.Tick) (err error) {
+	query := `INSERT INTO ticks (exchange, start_second, start_at, created_at, tick_json, codec) VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT (exchange, start_second) DO UPDATE SET
+	  start_at = excluded.start_at,
+	  created_at = excluded.created_at,
+	  tick_json = excluded.tick_json,
+	  codec = excluded.codec`
+	defer r.ops.Start("tick.create", query).Done(&err)
+
+	data, err := encode(r.codec, ts)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tick: %w", err)
 	}
-	query := `INSERT INTO ticks (start_at, created_at, tick_json) VALUES (?, ?, ?)`
-	_, err = r.db.ExecContext(ctx, query, ts.StartAt, ts.CreatedAt, string(data))
+	_, err = r.db.ExecContext(ctx, query, r.exchange, ts.StorageKey(), ts.StartAt, ts.CreatedAt, data, r.codec.Name())
 	if err != nil {
-		return fmt.Errorf("failed to insert tick: %w", err)
+		return fmt.Errorf("failed to upsert tick: %w", err)
 	}
 	return nil
 }
 
 // GetHistorySince returns all ticks created since the given time.
-func (r *TickRepository) GetHistorySince(ctx context.Context, since time.Time) ([]domain.Tick, error) {
-	query := `SELECT tick_json FROM ticks WHERE created_at >= ? ORDER BY created_at ASC`
+func (r *TickRepository) GetHistorySince(ctx context.Context, since time.Time) (ticks []domain.Tick, err error) {
+	query := `SELECT tick_json, codec FROM ticks WHERE created_at >= ? ORDER BY created_at ASC`
+	defer r.ops.Start("tick.get_history_since", query).Done(&err)
+
 	rows, err := r.db.QueryContext(ctx, query, since)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query ticks: %w", err)
 	}
 	defer rows.Close()
 
+	return scanTicks(rows)
+}
+
+// GetPage returns at most limit ticks created since the given time.
+func (r *TickRepository) GetPage(ctx context.Context, since time.Time, limit int) (ticks []domain.Tick, err error) {
+	query := `SELECT tick_json, codec FROM ticks WHERE created_at >= ? ORDER BY created_at ASC LIMIT ?`
+	defer r.ops.Start("tick.get_page", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTicks(rows)
+}
+
+// GetRange returns all ticks created within [from, to).
+func (r *TickRepository) GetRange(ctx context.Context, from, to time.Time) (ticks []domain.Tick, err error) {
+	query := `SELECT tick_json, codec FROM ticks WHERE created_at >= ? AND created_at < ? ORDER BY created_at ASC`
+	defer r.ops.Start("tick.get_range", query).Done(&err)
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ticks: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTicks(rows)
+}
+
+// scanTicks decodes tick_json rows with their codec.
+func scanTicks(rows *sql.Rows) ([]domain.Tick, error) {
 	var ticks []domain.Tick
 	for rows.Next() {
-		var tickJSON string
-		if err := rows.Scan(&tickJSON); err != nil {
+		var (
+			data      []byte
+			codecName sql.NullString
+		)
+		if err := rows.Scan(&data, &codecName); err != nil {
 			return nil, fmt.Errorf("failed to scan tick row: %w", err)
 		}
 		var tick domain.Tick
-		if err := json.Unmarshal([]byte(tickJSON), &tick); err != nil {
+		if err := decode(codecName, data, &tick); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal tick: %w", err)
 		}
 		ticks = append(ticks, tick)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate tick rows: %w", err)
+	}
 	return ticks, nil
 }
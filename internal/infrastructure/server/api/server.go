@@ -0,0 +1,509 @@
+// Package api serves the recent ticks, ticker histories and liquidations of the importers over HTTP, so the data
+// can be inspected without querying the repositories directly
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/importer"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// DefaultTicksLimit is the number of ticks returned without a limit parameter
+	DefaultTicksLimit = 100
+
+	// MaxTicksLimit bounds the limit parameter
+	MaxTicksLimit = 3600
+
+	// DefaultWindow is the range of the ticks and liquidations returned without a since or window parameter
+	DefaultWindow = time.Minute
+
+	// MaxWindow bounds the window parameter and how far back since may go
+	MaxWindow = 24 * time.Hour
+
+	// DefaultLatencyLimit is the number of slowest symbols returned without a limit parameter
+	DefaultLatencyLimit = 10
+
+	// MaxLatencyLimit bounds the limit parameter of the latency endpoint
+	MaxLatencyLimit = 1000
+)
+
+// shutdownTimeout bounds the shutdown of the HTTP server on Close
+const shutdownTimeout = 5 * time.Second
+
+// Source is the data of the importer of an exchange, implemented by importer.Importer
+type Source interface {
+	Ticks(ctx context.Context, since time.Time, limit int) ([]domain.Tick, error)
+	LatestTick() (*domain.Tick, bool)
+	TickerHistory(symbol domain.TickerName) ([]domain.Ticker, bool)
+	RollingStats(symbol domain.TickerName) (domain.RollingStats, bool)
+	Liquidations(ctx context.Context, since time.Time) ([]domain.Liquidation, bool, error)
+	SlowestSymbols(k int) []importer.SymbolLatency
+	SymbolLatencyHistogram() []importer.LatencyBucket
+	Symbols() []domain.Symbol // nil when the symbol registry is disabled
+}
+
+// Admin changes the runtime settings of the importers, implemented by bootstrap.Apps
+type Admin interface {
+	LogLevel() zapcore.Level
+	SetLogLevel(level string) error
+}
+
+// Health reports the health of the importers, implemented by bootstrap.Apps
+type Health interface {
+	NotifierCircuits() map[string][]notifier.Circuit // keyed by exchange name
+	Build() any                                      // revision, build date and Go version of the binary, served as JSON
+}
+
+// Config configures the API server
+type Config struct {
+	Addr    string            // listen address, e.g. :8080
+	Token   string            // (optional) bearer token required from the clients
+	Sources map[string]Source // importers keyed by exchange name
+	Admin   Admin             // (optional) serves the admin routes, they aren't served when nil
+	Health  Health            // (optional) serves the health route, it isn't served when nil
+	Logger  *zap.Logger
+}
+
+// Server serves the endpoints of the API:
+//
+//	GET /ticks?since=&limit=             ticks started since a time (RFC3339) or a duration ago, oldest first
+//	GET /ticks/latest                    latest published tick
+//	GET /tickers/{symbol}/history        in-memory history of a symbol, one ticker per minute
+//	GET /tickers/{symbol}/stats          high, low, change and liquidated notional of a symbol over the last 24h
+//	GET /liquidations?window=|since=     liquidations within the window or since a time, oldest first
+//	GET /symbols                         symbol registry of the exchange, sorted by symbol
+//	GET /latency?limit=                  symbols whose tickers take the longest to build and the latency histogram
+//	GET /health                          build of the binary and state of the notifier circuit breakers of every exchange
+//	GET /admin/log-level                 current log level
+//	PUT /admin/log-level?level=          change the log level, e.g. to debug, until the next restart
+//
+// Every data endpoint takes an exchange parameter, required when several exchanges are imported
+type Server struct {
+	cfg       Config
+	exchanges []string
+	handler   http.Handler
+	http      *http.Server
+	listener  net.Listener
+	logger    *zap.Logger
+	now       func() time.Time
+}
+
+// NewServer listens on the configured address and serves the API until Close
+func NewServer(cfg Config) (*Server, error) {
+	s, err := newServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", cfg.Addr, err)
+	}
+	s.listener = listener
+	s.http = &http.Server{Handler: s.handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("API server stopped", zap.Error(err))
+		}
+	}()
+	s.logger.Info("API server listening", zap.String("addr", listener.Addr().String()), zap.Strings("exchanges", s.exchanges))
+	return s, nil
+}
+
+// newServer returns a server without a listener, its endpoints are served by Handler
+func newServer(cfg Config) (*Server, error) {
+	if len(cfg.Sources) == 0 {
+		return nil, fmt.Errorf("at least one source is required")
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = zap.NewNop()
+	}
+
+	exchanges := make([]string, 0, len(cfg.Sources))
+	for name := range cfg.Sources {
+		exchanges = append(exchanges, name)
+	}
+	slices.Sort(exchanges)
+
+	s := &Server{cfg: cfg, exchanges: exchanges, logger: cfg.Logger, now: time.Now}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ticks", s.handleTicks)
+	mux.HandleFunc("GET /ticks/latest", s.handleLatestTick)
+	mux.HandleFunc("GET /tickers/{symbol}/history", s.handleTickerHistory)
+	mux.HandleFunc("GET /tickers/{symbol}/stats", s.handleRollingStats)
+	mux.HandleFunc("GET /liquidations", s.handleLiquidations)
+	mux.HandleFunc("GET /symbols", s.handleSymbols)
+	mux.HandleFunc("GET /latency", s.handleLatency)
+	if cfg.Health != nil {
+		mux.HandleFunc("GET /health", s.handleHealth)
+	}
+	if cfg.Admin != nil {
+		mux.HandleFunc("GET /admin/log-level", s.handleLogLevel)
+		mux.HandleFunc("PUT /admin/log-level", s.handleSetLogLevel)
+	}
+	s.handler = s.authorize(mux)
+	return s, nil
+}
+
+// Handler returns the handler of the endpoints
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// Addr returns the address the server listens on, e.g. to find the port picked for :0
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Close stops the server, waiting for the requests in flight
+func (s *Server) Close() error {
+	if s.http == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.http.Shutdown(ctx)
+}
+
+// ticksResponse is the response of GET /ticks
+type ticksResponse struct {
+	Exchange string        `json:"exchange"`
+	Ticks    []domain.Tick `json:"ticks"`
+}
+
+// handleTicks serves the ticks started since the since parameter, at most limit of them
+func (s *Server) handleTicks(w http.ResponseWriter, r *http.Request) {
+	exchange, source, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	since, err := s.since(r)
+	if err != nil {
+		s.error(w, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := s.limit(r, DefaultTicksLimit, MaxTicksLimit)
+	if err != nil {
+		s.error(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ticks, err := source.Ticks(r.Context(), since, limit)
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.write(w, ticksResponse{Exchange: exchange, Ticks: nonNil(ticks)})
+}
+
+// latestTickResponse is the response of GET /ticks/latest
+type latestTickResponse struct {
+	Exchange string       `json:"exchange"`
+	Tick     *domain.Tick `json:"tick"`
+}
+
+// handleLatestTick serves the snapshot of the latest published tick, without reading the history or the repository
+func (s *Server) handleLatestTick(w http.ResponseWriter, r *http.Request) {
+	exchange, source, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	tick, ok := source.LatestTick()
+	if !ok {
+		s.error(w, http.StatusNotFound, errors.New("no tick published yet"))
+		return
+	}
+	s.write(w, latestTickResponse{Exchange: exchange, Tick: tick})
+}
+
+// tickerHistoryResponse is the response of GET /tickers/{symbol}/history
+type tickerHistoryResponse struct {
+	Exchange string          `json:"exchange"`
+	Symbol   string          `json:"symbol"`
+	History  []domain.Ticker `json:"history"`
+}
+
+// handleTickerHistory serves the in-memory history of a symbol
+func (s *Server) handleTickerHistory(w http.ResponseWriter, r *http.Request) {
+	exchange, source, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	symbol := strings.ToUpper(r.PathValue("symbol"))
+	history, ok := source.TickerHistory(domain.TickerName(symbol))
+	if !ok {
+		s.error(w, http.StatusNotFound, fmt.Errorf("no history of %s", symbol))
+		return
+	}
+	s.write(w, tickerHistoryResponse{Exchange: exchange, Symbol: symbol, History: history})
+}
+
+// rollingStatsResponse is the response of GET /tickers/{symbol}/stats
+type rollingStatsResponse struct {
+	Exchange string              `json:"exchange"`
+	Symbol   string              `json:"symbol"`
+	Stats    domain.RollingStats `json:"stats"`
+}
+
+// handleRollingStats serves the statistics of a symbol over the last 24 hours
+func (s *Server) handleRollingStats(w http.ResponseWriter, r *http.Request) {
+	exchange, source, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	symbol := strings.ToUpper(r.PathValue("symbol"))
+	stats, ok := source.RollingStats(domain.TickerName(symbol))
+	if !ok {
+		s.error(w, http.StatusNotFound, fmt.Errorf("no stats of %s", symbol))
+		return
+	}
+	s.write(w, rollingStatsResponse{Exchange: exchange, Symbol: symbol, Stats: stats})
+}
+
+// liquidationsResponse is the response of GET /liquidations
+type liquidationsResponse struct {
+	Exchange     string               `json:"exchange"`
+	Since        time.Time            `json:"since"`
+	Complete     bool                 `json:"complete"` // false when older liquidations were evicted from memory and can't be read back
+	Liquidations []domain.Liquidation `json:"liquidations"`
+}
+
+// handleLiquidations serves the liquidations within the window parameter, or since the since parameter
+func (s *Server) handleLiquidations(w http.ResponseWriter, r *http.Request) {
+	exchange, source, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	since, err := s.since(r)
+	if err != nil {
+		s.error(w, http.StatusBadRequest, err)
+		return
+	}
+
+	liquidations, complete, err := source.Liquidations(r.Context(), since)
+	if err != nil {
+		s.error(w, http.StatusInternalServerError, err)
+		return
+	}
+	for i := range liquidations {
+		// the archived exchange frames are for reparsing, not for the API
+		liquidations[i].Raw = nil
+	}
+	s.write(w, liquidationsResponse{Exchange: exchange, Since: since, Complete: complete, Liquidations: nonNil(liquidations)})
+}
+
+// symbolsResponse is the response of GET /symbols
+type symbolsResponse struct {
+	Exchange string          `json:"exchange"`
+	Symbols  []domain.Symbol `json:"symbols"`
+}
+
+// handleSymbols serves the symbol registry of the exchange, listed and delisted symbols alike
+func (s *Server) handleSymbols(w http.ResponseWriter, r *http.Request) {
+	exchange, source, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	symbols := source.Symbols()
+	if symbols == nil {
+		s.error(w, http.StatusNotFound, errors.New("symbol registry disabled"))
+		return
+	}
+	s.write(w, symbolsResponse{Exchange: exchange, Symbols: symbols})
+}
+
+// latencyResponse is the response of GET /latency
+type latencyResponse struct {
+	Exchange  string                   `json:"exchange"`
+	Slowest   []importer.SymbolLatency `json:"slowest"`
+	Histogram []importer.LatencyBucket `json:"histogram"`
+}
+
+// handleLatency serves the limit symbols whose tickers take the longest to build and the latency histogram
+func (s *Server) handleLatency(w http.ResponseWriter, r *http.Request) {
+	exchange, source, ok := s.source(w, r)
+	if !ok {
+		return
+	}
+	limit, err := s.limit(r, DefaultLatencyLimit, MaxLatencyLimit)
+	if err != nil {
+		s.error(w, http.StatusBadRequest, err)
+		return
+	}
+	s.write(w, latencyResponse{
+		Exchange:  exchange,
+		Slowest:   nonNil(source.SlowestSymbols(limit)),
+		Histogram: nonNil(source.SymbolLatencyHistogram()),
+	})
+}
+
+// Health statuses of healthResponse
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded" // a notifier circuit isn't closed, its client is skipped or probed
+)
+
+// healthResponse is the response of GET /health
+type healthResponse struct {
+	Status    string                    `json:"status"`
+	Build     any                       `json:"build"`
+	Exchanges map[string]exchangeHealth `json:"exchanges"`
+}
+
+// exchangeHealth is the health of the importer of an exchange
+type exchangeHealth struct {
+	Circuits []notifier.Circuit `json:"circuits"`
+}
+
+// handleHealth serves the health of every exchange, the process is degraded while a notifier circuit isn't closed
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	response := healthResponse{Status: HealthOK, Build: s.cfg.Health.Build(), Exchanges: make(map[string]exchangeHealth, len(s.exchanges))}
+	circuits := s.cfg.Health.NotifierCircuits()
+	for _, exchange := range s.exchanges {
+		health := exchangeHealth{Circuits: nonNil(circuits[exchange])}
+		for _, circuit := range health.Circuits {
+			if circuit.State != notifier.CircuitClosed {
+				response.Status = HealthDegraded
+			}
+		}
+		response.Exchanges[exchange] = health
+	}
+	s.write(w, response)
+}
+
+// logLevelResponse is the response of the log level routes
+type logLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// handleLogLevel serves the current log level
+func (s *Server) handleLogLevel(w http.ResponseWriter, _ *http.Request) {
+	s.write(w, logLevelResponse{Level: s.cfg.Admin.LogLevel().String()})
+}
+
+// handleSetLogLevel changes the log level to the level parameter
+func (s *Server) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	if err := s.cfg.Admin.SetLogLevel(r.URL.Query().Get("level")); err != nil {
+		s.error(w, http.StatusBadRequest, fmt.Errorf("level: %w", err))
+		return
+	}
+	s.handleLogLevel(w, r)
+}
+
+// source returns the source of the exchange parameter, it can be omitted with a single exchange
+func (s *Server) source(w http.ResponseWriter, r *http.Request) (string, Source, bool) {
+	exchange := r.URL.Query().Get("exchange")
+	if exchange == "" && len(s.exchanges) == 1 {
+		exchange = s.exchanges[0]
+	}
+	source, ok := s.cfg.Sources[exchange]
+	if !ok {
+		s.error(w, http.StatusBadRequest, fmt.Errorf("exchange: one of %s is required, got %q", strings.Join(s.exchanges, ", "), exchange))
+		return "", nil, false
+	}
+	return exchange, source, true
+}
+
+// since returns the start of the requested range: the since parameter, a time (RFC3339) or a duration ago,
+// or the window parameter, a duration ago. It's DefaultWindow ago without either
+func (s *Server) since(r *http.Request) (time.Time, error) {
+	now := s.now()
+	query := r.URL.Query()
+	name, value := "since", query.Get("since")
+	if value == "" {
+		name, value = "window", query.Get("window")
+	}
+	if value == "" {
+		return now.Add(-DefaultWindow), nil
+	}
+
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil || name == "window" {
+		window, parseErr := time.ParseDuration(value)
+		if parseErr != nil || window <= 0 {
+			return time.Time{}, fmt.Errorf("%s: must be a positive duration or an RFC3339 time, got %q", name, value)
+		}
+		since = now.Add(-window)
+	}
+	if now.Sub(since) > MaxWindow {
+		return time.Time{}, fmt.Errorf("%s: must be within %s, got %q", name, MaxWindow, value)
+	}
+	return since, nil
+}
+
+// limit returns the limit parameter, from 1 to maxLimit, or defaultLimit without one
+func (s *Server) limit(r *http.Request, defaultLimit, maxLimit int) (int, error) {
+	value := r.URL.Query().Get("limit")
+	if value == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 || limit > maxLimit {
+		return 0, fmt.Errorf("limit: must be a number from 1 to %d, got %q", maxLimit, value)
+	}
+	return limit, nil
+}
+
+// authorize requires the bearer token of the config when it's set
+func (s *Server) authorize(next http.Handler) http.Handler {
+	if s.cfg.Token == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Token)) != 1 {
+			s.error(w, http.StatusUnauthorized, fmt.Errorf("unauthorized"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errorResponse is the response of a failed request
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// error writes the error as JSON with the status code
+func (s *Server) error(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		s.logger.Error("API request failed", zap.Error(err))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
+
+// write writes the response as JSON
+func (s *Server) write(w http.ResponseWriter, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Warn("Failed to write API response", zap.Error(err))
+	}
+}
+
+// nonNil returns an empty slice in place of nil, so empty results are encoded as []
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
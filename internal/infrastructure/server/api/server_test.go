@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/importer"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeSource serves fixed data and records the queries
+type fakeSource struct {
+	ticks        []domain.Tick
+	latest       *domain.Tick
+	history      map[domain.TickerName][]domain.Ticker
+	stats        map[domain.TickerName]domain.RollingStats
+	liquidations []domain.Liquidation
+	complete     bool
+	latencies    []importer.SymbolLatency
+	symbols      []domain.Symbol
+	err          error
+
+	since time.Time
+	limit int
+}
+
+func (f *fakeSource) Ticks(_ context.Context, since time.Time, limit int) ([]domain.Tick, error) {
+	f.since, f.limit = since, limit
+	return f.ticks, f.err
+}
+
+func (f *fakeSource) LatestTick() (*domain.Tick, bool) {
+	return f.latest, f.latest != nil
+}
+
+func (f *fakeSource) TickerHistory(symbol domain.TickerName) ([]domain.Ticker, bool) {
+	history, ok := f.history[symbol]
+	return history, ok
+}
+
+func (f *fakeSource) RollingStats(symbol domain.TickerName) (domain.RollingStats, bool) {
+	stats, ok := f.stats[symbol]
+	return stats, ok
+}
+
+func (f *fakeSource) Liquidations(_ context.Context, since time.Time) ([]domain.Liquidation, bool, error) {
+	f.since = since
+	return f.liquidations, f.complete, f.err
+}
+
+func (f *fakeSource) SlowestSymbols(k int) []importer.SymbolLatency {
+	f.limit = k
+	return f.latencies[:min(k, len(f.latencies))]
+}
+
+func (f *fakeSource) SymbolLatencyHistogram() []importer.LatencyBucket {
+	return []importer.LatencyBucket{{UpperBound: time.Millisecond, Count: int64(len(f.latencies))}, {Count: 0}}
+}
+
+func (f *fakeSource) Symbols() []domain.Symbol {
+	return f.symbols
+}
+
+// fakeAdmin keeps a log level
+type fakeAdmin struct {
+	level zapcore.Level
+}
+
+func (f *fakeAdmin) LogLevel() zapcore.Level {
+	return f.level
+}
+
+func (f *fakeAdmin) SetLogLevel(level string) error {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	f.level = parsed
+	return nil
+}
+
+// fakeHealth reports fixed circuits and build
+type fakeHealth struct {
+	circuits map[string][]notifier.Circuit
+	build    map[string]string
+}
+
+func (f *fakeHealth) NotifierCircuits() map[string][]notifier.Circuit {
+	return f.circuits
+}
+
+func (f *fakeHealth) Build() any {
+	return f.build
+}
+
+var now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestServer(t *testing.T, cfg Config) *Server {
+	t.Helper()
+	s, err := newServer(cfg)
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+// get serves the request and decodes the JSON response into out
+func get(t *testing.T, s *Server, target string, out any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out))
+	return rec.Code
+}
+
+func TestServer_Ticks(t *testing.T) {
+	source := &fakeSource{ticks: []domain.Tick{{StartAt: now.Add(-time.Second)}}}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source}})
+
+	var response ticksResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/ticks", &response))
+	assert.Equal(t, "binance-perp", response.Exchange, "the single exchange is the default one")
+	assert.Len(t, response.Ticks, 1)
+	assert.Equal(t, now.Add(-DefaultWindow), source.since)
+	assert.Equal(t, DefaultTicksLimit, source.limit)
+
+	get(t, s, "/ticks?since=5m&limit=10", &response)
+	assert.Equal(t, now.Add(-5*time.Minute), source.since)
+	assert.Equal(t, 10, source.limit)
+
+	get(t, s, "/ticks?since=2025-01-01T11:30:00Z", &response)
+	assert.Equal(t, now.Add(-30*time.Minute), source.since)
+
+	source.ticks = nil
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ticks", nil))
+	assert.JSONEq(t, `{"exchange":"binance-perp","ticks":[]}`, rec.Body.String())
+}
+
+func TestServer_BadRequests(t *testing.T) {
+	source := &fakeSource{err: errors.New("db down")}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source, "bybit-linear": source}})
+
+	tests := []struct {
+		target    string
+		code      int
+		wantError string
+	}{
+		{"/ticks", http.StatusBadRequest, `exchange: one of binance-perp, bybit-linear is required, got ""`},
+		{"/ticks?exchange=okx-swap", http.StatusBadRequest, `exchange: one of binance-perp, bybit-linear is required, got "okx-swap"`},
+		{"/ticks?exchange=binance-perp&limit=0", http.StatusBadRequest, `limit: must be a number from 1 to 3600, got "0"`},
+		{"/ticks?exchange=binance-perp&since=yesterday", http.StatusBadRequest, `since: must be a positive duration or an RFC3339 time, got "yesterday"`},
+		{"/liquidations?exchange=binance-perp&window=48h", http.StatusBadRequest, `window: must be within 24h0m0s, got "48h"`},
+		{"/liquidations?exchange=binance-perp&window=-1m", http.StatusBadRequest, `window: must be a positive duration or an RFC3339 time, got "-1m"`},
+		{"/ticks?exchange=binance-perp", http.StatusInternalServerError, "db down"},
+		{"/tickers/BTCUSDT/history?exchange=bybit-linear", http.StatusNotFound, "no history of BTCUSDT"},
+		{"/tickers/BTCUSDT/stats?exchange=bybit-linear", http.StatusNotFound, "no stats of BTCUSDT"},
+		{"/latency?exchange=bybit-linear&limit=5000", http.StatusBadRequest, `limit: must be a number from 1 to 1000, got "5000"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var response errorResponse
+			assert.Equal(t, tt.code, get(t, s, tt.target, &response))
+			assert.Equal(t, tt.wantError, response.Error)
+		})
+	}
+}
+
+func TestServer_TickerHistory(t *testing.T) {
+	source := &fakeSource{history: map[domain.TickerName][]domain.Ticker{"BTCUSDT": {{Symbol: "BTCUSDT", Ask: 100}}}}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source}})
+
+	var response tickerHistoryResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/tickers/btcusdt/history", &response))
+	assert.Equal(t, "BTCUSDT", response.Symbol)
+	require.Len(t, response.History, 1)
+	assert.Equal(t, 100.0, response.History[0].Ask)
+}
+
+func TestServer_LatestTick(t *testing.T) {
+	source := &fakeSource{}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source}})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ticks/latest", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "no tick before the first one is published")
+
+	source.latest = &domain.Tick{StartAt: now, Avg: domain.TickAvg{Change1m: 1.5}}
+	var response latestTickResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/ticks/latest", &response))
+	assert.Equal(t, "binance-perp", response.Exchange)
+	require.NotNil(t, response.Tick)
+	assert.True(t, now.Equal(response.Tick.StartAt))
+	assert.Equal(t, 1.5, response.Tick.Avg.Change1m)
+}
+
+func TestServer_Symbols(t *testing.T) {
+	source := &fakeSource{}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source}})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/symbols", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "the registry is disabled")
+
+	source.symbols = []domain.Symbol{}
+	var response symbolsResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/symbols", &response))
+	assert.NotNil(t, response.Symbols, "an empty registry is an empty list")
+
+	source.symbols = []domain.Symbol{
+		{Symbol: "BTCUSDT", Status: domain.SymbolActive, FirstSeenAt: now, LastSeenAt: now},
+		{Symbol: "LUNAUSDT", Status: domain.SymbolDelisted, DelistedAt: now},
+	}
+	assert.Equal(t, http.StatusOK, get(t, s, "/symbols", &response))
+	assert.Equal(t, "binance-perp", response.Exchange)
+	require.Len(t, response.Symbols, 2)
+	assert.Equal(t, domain.SymbolActive, response.Symbols[0].Status)
+	assert.Equal(t, domain.TickerName("LUNAUSDT"), response.Symbols[1].Symbol)
+	assert.True(t, now.Equal(response.Symbols[1].DelistedAt))
+}
+
+func TestServer_RollingStats(t *testing.T) {
+	source := &fakeSource{stats: map[domain.TickerName]domain.RollingStats{"BTCUSDT": {High: 110, Low: 90, Change: 5}}}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source}})
+
+	var response rollingStatsResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/tickers/btcusdt/stats", &response))
+	assert.Equal(t, "BTCUSDT", response.Symbol)
+	assert.Equal(t, domain.RollingStats{High: 110, Low: 90, Change: 5}, response.Stats)
+}
+
+func TestServer_Liquidations(t *testing.T) {
+	source := &fakeSource{
+		liquidations: []domain.Liquidation{{EventAt: now.Add(-time.Second), Raw: []byte("frame")}},
+		complete:     true,
+	}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source}})
+
+	var response liquidationsResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/liquidations?window=60s", &response))
+	assert.Equal(t, now.Add(-time.Minute), source.since)
+	assert.Equal(t, now.Add(-time.Minute), response.Since)
+	assert.True(t, response.Complete)
+	require.Len(t, response.Liquidations, 1)
+	assert.Nil(t, response.Liquidations[0].Raw, "raw frames aren't served")
+}
+
+func TestServer_Latency(t *testing.T) {
+	source := &fakeSource{latencies: []importer.SymbolLatency{
+		{Symbol: "ETHUSDT", Count: 2, Avg: 2 * time.Millisecond},
+		{Symbol: "BTCUSDT", Count: 2, Avg: time.Millisecond},
+	}}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": source}})
+
+	var response latencyResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/latency", &response))
+	assert.Equal(t, DefaultLatencyLimit, source.limit)
+	assert.Len(t, response.Slowest, 2)
+	assert.Len(t, response.Histogram, 2)
+
+	get(t, s, "/latency?limit=1", &response)
+	require.Len(t, response.Slowest, 1)
+	assert.Equal(t, domain.TickerName("ETHUSDT"), response.Slowest[0].Symbol)
+}
+
+func TestServer_LogLevel(t *testing.T) {
+	admin := &fakeAdmin{level: zapcore.InfoLevel}
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": &fakeSource{}}, Admin: admin})
+
+	var response logLevelResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/admin/log-level", &response))
+	assert.Equal(t, "info", response.Level)
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-level?level=debug", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"debug"}`, rec.Body.String())
+	assert.Equal(t, zapcore.DebugLevel, admin.level)
+
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-level?level=loud", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, zapcore.DebugLevel, admin.level)
+
+	s = newTestServer(t, Config{Sources: map[string]Source{"binance-perp": &fakeSource{}}})
+	rec = httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/admin/log-level?level=debug", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "no admin routes without an admin")
+}
+
+func TestServer_Health(t *testing.T) {
+	health := &fakeHealth{
+		circuits: map[string][]notifier.Circuit{
+			"binance-perp": {{Client: "notify.RedisNotifier", State: notifier.CircuitClosed}},
+		},
+		build: map[string]string{"revision": "abc123", "go_version": "go1.23.4"},
+	}
+	sources := map[string]Source{"binance-perp": &fakeSource{}, "bybit-linear": &fakeSource{}}
+	s := newTestServer(t, Config{Sources: sources, Health: health})
+
+	var response healthResponse
+	assert.Equal(t, http.StatusOK, get(t, s, "/health", &response))
+	assert.Equal(t, HealthOK, response.Status)
+	assert.Equal(t, map[string]any{"revision": "abc123", "go_version": "go1.23.4"}, response.Build)
+	require.Len(t, response.Exchanges["binance-perp"].Circuits, 1)
+	assert.Equal(t, "notify.RedisNotifier", response.Exchanges["binance-perp"].Circuits[0].Client)
+	assert.NotNil(t, response.Exchanges["bybit-linear"].Circuits, "exchanges without a breaker have no circuits")
+
+	health.circuits["bybit-linear"] = []notifier.Circuit{{Client: "notify.WebhookNotifier", State: notifier.CircuitOpen, Failures: 5}}
+	response = healthResponse{}
+	assert.Equal(t, http.StatusOK, get(t, s, "/health", &response))
+	assert.Equal(t, HealthDegraded, response.Status, "an open circuit degrades the health")
+
+	s = newTestServer(t, Config{Sources: sources})
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code, "no health route without a health")
+}
+
+func TestServer_Token(t *testing.T) {
+	s := newTestServer(t, Config{Sources: map[string]Source{"binance-perp": &fakeSource{}}, Token: "secret"})
+
+	var response errorResponse
+	assert.Equal(t, http.StatusUnauthorized, get(t, s, "/ticks", &response))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ticks", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestNewServer(t *testing.T) {
+	_, err := NewServer(Config{Addr: "127.0.0.1:0"})
+	assert.EqualError(t, err, "at least one source is required")
+
+	s, err := NewServer(Config{Addr: "127.0.0.1:0", Sources: map[string]Source{"binance-perp": &fakeSource{}}})
+	require.NoError(t, err)
+	defer s.Close()
+
+	resp, err := http.Get("http://" + s.Addr() + "/ticks")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Post("http://"+s.Addr()+"/ticks", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
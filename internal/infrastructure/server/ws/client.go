@@ -0,0 +1,236 @@
+package ws
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Actions of the client messages changing the subscriptions
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// Message is a message of a client changing its subscriptions, e.g. {"action":"subscribe","topics":["TICK_INFO"]}
+type Message struct {
+	Action string   `json:"action"`
+	Topics []string `json:"topics"`
+}
+
+// Subscription is the data of the EventSubscribed messages, the current topics of a client and its encoding
+type Subscription struct {
+	Topics      []string `json:"topics"`
+	Compression string   `json:"compression,omitempty"`
+	Delta       bool     `json:"delta,omitempty"`
+}
+
+// client is a connection of the server with its subscriptions and its queue of events
+type client struct {
+	server *Server
+	conn   *websocket.Conn
+	send   chan *websocket.PreparedMessage
+
+	// encoding negotiated on connect, see Server
+	compress bool
+	delta    bool
+
+	mu     sync.RWMutex
+	topics []string
+	seqs   map[string]uint64 // by event type, sequence of the last event queued when the client negotiated delta frames
+
+	// dropped counts the events dropped since the last EventDropped message
+	dropped atomic.Int64
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newClient(s *Server, conn *websocket.Conn, topics []string) *client {
+	return &client{
+		server: s,
+		conn:   conn,
+		send:   make(chan *websocket.PreparedMessage, s.cfg.Buffer),
+		topics: topics,
+		done:   make(chan struct{}),
+	}
+}
+
+// subscribed tells whether the client is subscribed to the event type
+func (c *client) subscribed(eventType string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Contains(c.topics, eventType)
+}
+
+// synced returns the sequence of the last event of the type queued for the client, 0 when it missed the last one
+func (c *client) synced(eventType string) uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.seqs[eventType]
+}
+
+// sync records the sequence of the last event of the type queued for the client
+func (c *client) sync(eventType string, seq uint64) {
+	if !c.delta {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.seqs == nil {
+		c.seqs = make(map[string]uint64)
+	}
+	c.seqs[eventType] = seq
+}
+
+// subscription returns the data of the EventSubscribed message confirming the topics
+func (c *client) subscription(topics []string) Subscription {
+	subscription := Subscription{Topics: topics, Delta: c.delta}
+	if c.compress {
+		subscription.Compression = CompressionZstd
+	}
+	return subscription
+}
+
+// enqueue queues the message without blocking, it returns false when the buffer is full and the message is dropped
+func (c *client) enqueue(msg *websocket.PreparedMessage) bool {
+	select {
+	case c.send <- msg:
+		return true
+	default:
+		c.dropped.Add(1)
+		return false
+	}
+}
+
+// reply queues a control message for the client
+func (c *client) reply(eventType string, data any) {
+	payload, err := json.Marshal(notify.Event{Time: time.Now().UTC(), EventType: eventType, Data: data})
+	if err != nil {
+		return
+	}
+	msg, err := c.server.prepare(payload, c.compress)
+	if err != nil {
+		return
+	}
+	c.enqueue(msg)
+}
+
+// writeLoop writes the queued messages and pings the client until the connection is closed. The count of
+// the dropped events is written first once the client catches up
+func (c *client) writeLoop() {
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	defer c.close(websocket.CloseNormalClosure, "")
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			if dropped := c.dropped.Swap(0); dropped > 0 {
+				if !c.writeDropped(dropped) {
+					return
+				}
+			}
+			_ = c.conn.SetWriteDeadline(time.Now().Add(c.server.cfg.WriteTimeout))
+			if err := c.conn.WritePreparedMessage(msg); err != nil {
+				c.server.logger.Debug("WebSocket write failed", zap.Error(err))
+				return
+			}
+		case <-ping.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.server.cfg.WriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeDropped writes the EventDropped message, it returns false when the write failed
+func (c *client) writeDropped(dropped int64) bool {
+	payload, err := json.Marshal(notify.Event{
+		Time:      time.Now().UTC(),
+		EventType: EventDropped,
+		Data:      map[string]int64{"count": dropped},
+	})
+	if err != nil {
+		return false
+	}
+	msg, err := c.server.prepare(payload, c.compress)
+	if err != nil {
+		return false
+	}
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.server.cfg.WriteTimeout))
+	return c.conn.WritePreparedMessage(msg) == nil
+}
+
+// readLoop applies the subscription messages of the client until the connection is closed
+func (c *client) readLoop() {
+	defer func() {
+		c.server.remove(c)
+		c.close(websocket.CloseNormalClosure, "")
+		c.server.logger.Info("WebSocket client disconnected", zap.String("remote", c.conn.RemoteAddr().String()))
+	}()
+
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg Message
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				c.reply(EventError, map[string]string{"message": "invalid message: " + err.Error()})
+				continue
+			}
+			return
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.apply(msg)
+	}
+}
+
+// apply changes the subscriptions of the client and confirms them
+func (c *client) apply(msg Message) {
+	topics, err := c.server.parseTopics(msg.Topics)
+	if err != nil {
+		c.reply(EventError, map[string]string{"message": err.Error()})
+		return
+	}
+
+	c.mu.Lock()
+	switch msg.Action {
+	case ActionSubscribe:
+		c.topics = slices.Compact(slices.Sorted(slices.Values(append(slices.Clone(c.topics), topics...))))
+	case ActionUnsubscribe:
+		c.topics = slices.DeleteFunc(slices.Clone(c.topics), func(t string) bool { return slices.Contains(topics, t) })
+	default:
+		c.mu.Unlock()
+		c.reply(EventError, map[string]string{"message": "unknown action " + msg.Action})
+		return
+	}
+	current := slices.Clone(c.topics)
+	c.mu.Unlock()
+
+	c.reply(EventSubscribed, c.subscription(current))
+}
+
+// close sends a close frame and closes the connection once
+func (c *client) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(time.Second))
+		_ = c.conn.Close()
+	})
+}
@@ -0,0 +1,116 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PatchOp is an operation of a JSON Patch (RFC 6902) turning the data of an event into the data of the next one
+type PatchOp struct {
+	Op    string `json:"op"` // add, remove or replace
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON leaves the value out of the remove operations only, a null value is still a value
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+	type plain PatchOp
+	return json.Marshal(plain(op))
+}
+
+// DeltaEvent is the event sent to a client negotiating delta frames when the data of its type changed little since
+// the previous event: Patch applied to the data of the previous event of the type gives the data of this one
+type DeltaEvent struct {
+	Time      time.Time `json:"ct"`
+	EventType string    `json:"event_type"`
+	Patch     []PatchOp `json:"patch"`
+}
+
+// stream is the state of an event type for the clients negotiating delta frames
+type stream struct {
+	seq        uint64    // of the last event of the type, a client received it when its synced sequence matches
+	data       any       // decoded JSON document of the data of the last event, nil when it wasn't decoded
+	keyframeAt time.Time // when a full event was last sent to every client
+}
+
+// decodeData decodes the data of a JSON encoded event, numbers kept as written
+func decodeData(payload []byte) (any, error) {
+	var event struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(event.Data))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// diff appends the operations turning the decoded JSON document from into to. Objects are patched key by key,
+// arrays of the same length item by item, other changes replace the value
+func diff(ops []PatchOp, path string, from, to any) []PatchOp {
+	switch to := to.(type) {
+	case map[string]any:
+		from, ok := from.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range slices.Sorted(maps.Keys(from)) {
+			if _, kept := to[key]; !kept {
+				ops = append(ops, PatchOp{Op: "remove", Path: path + "/" + escapePointer(key)})
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(to)) {
+			previous, existed := from[key]
+			if !existed {
+				ops = append(ops, PatchOp{Op: "add", Path: path + "/" + escapePointer(key), Value: to[key]})
+				continue
+			}
+			ops = diff(ops, path+"/"+escapePointer(key), previous, to[key])
+		}
+		return ops
+	case []any:
+		from, ok := from.([]any)
+		if !ok || len(from) != len(to) {
+			break
+		}
+		for i := range to {
+			ops = diff(ops, path+"/"+strconv.Itoa(i), from[i], to[i])
+		}
+		return ops
+	default:
+		if equalScalars(from, to) {
+			return ops
+		}
+	}
+	return append(ops, PatchOp{Op: "replace", Path: path, Value: to})
+}
+
+// equalScalars compares the decoded JSON scalars, numbers as written
+func equalScalars(a, b any) bool {
+	switch a.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return a == b
+}
+
+// escapePointer escapes a key as a JSON Pointer (RFC 6901) reference token
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
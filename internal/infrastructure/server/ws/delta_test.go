@@ -0,0 +1,56 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     []PatchOp
+	}{
+		{name: "unchanged", from: `{"a":1,"b":[1,2]}`, to: `{"a":1,"b":[1,2]}`, want: []PatchOp{}},
+		{name: "numbers as written", from: `{"a":1.50}`, to: `{"a":1.5}`, want: []PatchOp{{Op: "replace", Path: "/a", Value: json.Number("1.5")}}},
+		{
+			name: "keys added and removed",
+			from: `{"a":1,"b":2}`,
+			to:   `{"b":2,"c/d":3}`,
+			want: []PatchOp{{Op: "remove", Path: "/a"}, {Op: "add", Path: "/c~1d", Value: json.Number("3")}},
+		},
+		{
+			name: "arrays of the same length item by item",
+			from: `{"tickers":[{"s":"BTC","ask":1},{"s":"ETH","ask":2}]}`,
+			to:   `{"tickers":[{"s":"BTC","ask":1},{"s":"ETH","ask":3}]}`,
+			want: []PatchOp{{Op: "replace", Path: "/tickers/1/ask", Value: json.Number("3")}},
+		},
+		{
+			name: "arrays of another length replaced",
+			from: `{"tickers":[1]}`,
+			to:   `{"tickers":[1,2]}`,
+			want: []PatchOp{{Op: "replace", Path: "/tickers", Value: []any{json.Number("1"), json.Number("2")}}},
+		},
+		{name: "type changed", from: `{"a":{"b":1}}`, to: `{"a":null}`, want: []PatchOp{{Op: "replace", Path: "/a", Value: nil}}},
+		{name: "document replaced", from: `[1]`, to: `"x"`, want: []PatchOp{{Op: "replace", Path: "", Value: "x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, err := decodeData([]byte(`{"data":` + tt.from + `}`))
+			require.NoError(t, err)
+			to, err := decodeData([]byte(`{"data":` + tt.to + `}`))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, diff([]PatchOp{}, "", from, to))
+		})
+	}
+}
+
+func TestPatchOp_MarshalJSON(t *testing.T) {
+	encoded, err := json.Marshal([]PatchOp{{Op: "remove", Path: "/a"}, {Op: "replace", Path: "/b", Value: nil}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"remove","path":"/a"},{"op":"replace","path":"/b","value":null}]`, string(encoded))
+}
@@ -0,0 +1,447 @@
+// Package ws serves the notification events to WebSocket clients, so external consumers can subscribe to
+// the topics of the importer without a broker in between
+package ws
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/gorilla/websocket"
+	"github.com/klauspost/compress/zstd"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultPath is the path of the WebSocket endpoint
+	DefaultPath = "/ws"
+
+	// DefaultBuffer is the number of events queued per client before its events are dropped
+	DefaultBuffer = 1024
+
+	// DefaultWriteTimeout bounds the write of an event to a client
+	DefaultWriteTimeout = 10 * time.Second
+
+	// DefaultKeyframeInterval is how often the clients negotiating delta frames are sent full events
+	DefaultKeyframeInterval = 10 * time.Second
+
+	// CompressionZstd is the compression query parameter sending every message as a zstd frame of its JSON
+	CompressionZstd = "zstd"
+)
+
+// Event types of the control messages sent to the clients, next to the notification events
+const (
+	// EventSubscribed confirms the topics a client is subscribed to after a connection or a (un)subscribe message
+	EventSubscribed = "WS_SUBSCRIBED"
+
+	// EventDropped tells a client how many events were dropped while its buffer was full
+	EventDropped = "WS_DROPPED"
+
+	// EventError reports a rejected client message
+	EventError = "WS_ERROR"
+)
+
+const (
+	// pingInterval is how often the clients are pinged, they're disconnected when no pong arrives within pongWait
+	pingInterval = 30 * time.Second
+	pongWait     = pingInterval + 10*time.Second
+
+	// maxMessageSize bounds the messages read from the clients
+	maxMessageSize = 4096
+
+	// shutdownTimeout bounds the shutdown of the HTTP server on Close
+	shutdownTimeout = 5 * time.Second
+)
+
+// Config configures the WebSocket server
+type Config struct {
+	Addr         string   // listen address, e.g. :8090
+	Path         string   // path of the endpoint, DefaultPath when empty
+	Topics       []string // topics the clients may subscribe to
+	Token        string   // (optional) bearer token required from the clients
+	Buffer       int      // events queued per client, 0 uses DefaultBuffer
+	WriteTimeout time.Duration
+
+	// KeyframeInterval is how often the clients negotiating delta frames are sent a full event of each type,
+	// 0 uses DefaultKeyframeInterval
+	KeyframeInterval time.Duration
+
+	Logger *zap.Logger
+}
+
+// Stats reports the clients of the server
+type Stats struct {
+	Clients int   // connected clients
+	Dropped int64 // events dropped because the buffer of a client was full
+}
+
+// Server is a notify.Client sending every event to the connected clients subscribed to its event type.
+// Each client has a bounded buffer: when a client reads slower than the events arrive, the events are dropped
+// for that client only and it's sent an EventDropped message with their count once it catches up.
+//
+// Clients negotiate on connect binary messages compressed with zstd (compression=zstd) and delta frames
+// (delta=true): a DeltaEvent patching the data of the previous event of the type, with a full event every
+// KeyframeInterval, after a dropped event and when the patch isn't smaller than the event
+type Server struct {
+	cfg      Config
+	topics   []string
+	upgrader websocket.Upgrader
+	http     *http.Server
+	listener net.Listener
+	logger   *zap.Logger
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	closed  bool
+
+	// streams are the states of the event types for the delta frames, each locked by the Send of its type
+	streamsMu sync.Mutex
+	streams   map[string]*lockedStream
+
+	encoder func() (*zstd.Encoder, error)
+
+	dropped   atomic.Int64
+	closeOnce sync.Once
+}
+
+// lockedStream serializes the Send calls of an event type, so the clients receive its events in sequence
+type lockedStream struct {
+	sync.Mutex
+	stream
+}
+
+// NewServer listens on the configured address and serves the WebSocket endpoint until Close
+func NewServer(cfg Config) (*Server, error) {
+	s, err := newServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", cfg.Addr, err)
+	}
+	s.listener = listener
+
+	mux := http.NewServeMux()
+	mux.Handle(s.cfg.Path, s)
+	s.http = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := s.http.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("WebSocket server stopped", zap.Error(err))
+		}
+	}()
+	s.logger.Info("WebSocket server listening", zap.String("addr", listener.Addr().String()), zap.String("path", s.cfg.Path))
+	return s, nil
+}
+
+// newServer returns a server without a listener, serving the clients upgraded by ServeHTTP
+func newServer(cfg Config) (*Server, error) {
+	if len(cfg.Topics) == 0 {
+		return nil, fmt.Errorf("at least one topic is required")
+	}
+	if cfg.Path == "" {
+		cfg.Path = DefaultPath
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = DefaultBuffer
+	}
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = DefaultWriteTimeout
+	}
+	if cfg.KeyframeInterval <= 0 {
+		cfg.KeyframeInterval = DefaultKeyframeInterval
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = zap.NewNop()
+	}
+
+	return &Server{
+		cfg:     cfg,
+		topics:  slices.Compact(slices.Sorted(slices.Values(cfg.Topics))),
+		logger:  cfg.Logger,
+		clients: make(map[*client]struct{}),
+		streams: make(map[string]*lockedStream),
+		encoder: sync.OnceValues(func() (*zstd.Encoder, error) {
+			return zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+		}),
+	}, nil
+}
+
+// Addr returns the address the server listens on, e.g. to find the port picked for :0
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+// Stats returns the connected clients and the events dropped so far
+func (s *Server) Stats() Stats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Stats{Clients: len(s.clients), Dropped: s.dropped.Load()}
+}
+
+// Send queues the event for every client subscribed to its event type. It never blocks on a client and never
+// fails because of one, so a slow client can't trip the circuit breaker of the others
+func (s *Server) Send(_ context.Context, event notify.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshaling websocket event: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return fmt.Errorf("websocket server is closed")
+	}
+
+	st := s.stream(event.EventType)
+	st.Lock()
+	defer st.Unlock()
+
+	// every message is prepared once, the frames are shared by the clients negotiating the same encoding
+	f := &frames{server: s, event: payload}
+	st.seq++
+	deltas := false
+	for c := range s.clients {
+		if c.subscribed(event.EventType) && c.delta {
+			deltas = true
+			break
+		}
+	}
+	if deltas {
+		f.patch = s.patch(&st.stream, event, payload)
+	} else {
+		// nobody follows the type, a client negotiating delta frames later starts with a full event
+		st.data = nil
+	}
+
+	for c := range s.clients {
+		if !c.subscribed(event.EventType) {
+			continue
+		}
+		patched := c.delta && f.patch != nil && c.synced(event.EventType) == st.seq-1
+		msg, err := f.message(patched, c.compress)
+		if err != nil {
+			return err
+		}
+		if !c.enqueue(msg) {
+			s.dropped.Add(1)
+			// the next event of the type is sent in full, the client missed the data the patch applies to
+			c.sync(event.EventType, 0)
+			continue
+		}
+		c.sync(event.EventType, st.seq)
+	}
+	return nil
+}
+
+// stream returns the state of the event type
+func (s *Server) stream(eventType string) *lockedStream {
+	s.streamsMu.Lock()
+	defer s.streamsMu.Unlock()
+	st, ok := s.streams[eventType]
+	if !ok {
+		st = &lockedStream{}
+		s.streams[eventType] = st
+	}
+	return st
+}
+
+// patch records the data of the event as the last of its type and returns the DeltaEvent encoding it, nil when
+// the event is sent in full: on a keyframe, when the previous data is unknown or when the patch isn't smaller
+func (s *Server) patch(st *stream, event notify.Event, payload []byte) []byte {
+	previous := st.data
+	data, err := decodeData(payload)
+	if err != nil {
+		st.data = nil
+		return nil
+	}
+	st.data = data
+
+	now := time.Now()
+	if previous == nil || now.Sub(st.keyframeAt) >= s.cfg.KeyframeInterval {
+		st.keyframeAt = now
+		return nil
+	}
+	patch, err := json.Marshal(DeltaEvent{Time: event.Time, EventType: event.EventType, Patch: diff([]PatchOp{}, "", previous, data)})
+	if err != nil || len(patch) >= len(payload) {
+		return nil
+	}
+	return patch
+}
+
+// frames prepares the messages of an event in the encodings negotiated by the clients, each at most once
+type frames struct {
+	server   *Server
+	event    []byte
+	patch    []byte                           // DeltaEvent of the event, nil when it's sent in full
+	prepared [2][2]*websocket.PreparedMessage // by patched and compressed
+}
+
+// message returns the event or its patch, compressed or not
+func (f *frames) message(patched, compressed bool) (*websocket.PreparedMessage, error) {
+	p, c := 0, 0
+	payload := f.event
+	if patched {
+		p, payload = 1, f.patch
+	}
+	if compressed {
+		c = 1
+	}
+	if f.prepared[p][c] == nil {
+		msg, err := f.server.prepare(payload, compressed)
+		if err != nil {
+			return nil, fmt.Errorf("preparing websocket event: %w", err)
+		}
+		f.prepared[p][c] = msg
+	}
+	return f.prepared[p][c], nil
+}
+
+// prepare returns the message of a JSON payload, a binary zstd frame when compressed
+func (s *Server) prepare(payload []byte, compressed bool) (*websocket.PreparedMessage, error) {
+	if !compressed {
+		return websocket.NewPreparedMessage(websocket.TextMessage, payload)
+	}
+	encoder, err := s.encoder()
+	if err != nil {
+		return nil, fmt.Errorf("creating zstd encoder: %w", err)
+	}
+	return websocket.NewPreparedMessage(websocket.BinaryMessage, encoder.EncodeAll(payload, nil))
+}
+
+// Close disconnects the clients and stops the server, it's safe to call more than once
+func (s *Server) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		clients := make([]*client, 0, len(s.clients))
+		for c := range s.clients {
+			clients = append(clients, c)
+		}
+		s.mu.Unlock()
+
+		// hijacked connections aren't closed by the HTTP server shutdown
+		for _, c := range clients {
+			c.close(websocket.CloseGoingAway, "server shutting down")
+		}
+		if s.http != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			err = s.http.Shutdown(ctx)
+		}
+	})
+	return err
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection subscribed to the topics of the topics query parameter,
+// every topic of the server when it's missing. The compression and delta query parameters negotiate the encoding
+// of the messages
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.cfg.Token != "" && !s.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	topics := s.topics
+	if query := r.URL.Query().Get("topics"); query != "" {
+		var err error
+		if topics, err = s.parseTopics(strings.Split(query, ",")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	compression := r.URL.Query().Get("compression")
+	if compression != "" && compression != CompressionZstd {
+		http.Error(w, fmt.Sprintf("compression %s is not supported, use %s", compression, CompressionZstd), http.StatusBadRequest)
+		return
+	}
+	delta := false
+	if query := r.URL.Query().Get("delta"); query != "" {
+		var err error
+		if delta, err = strconv.ParseBool(query); err != nil {
+			http.Error(w, fmt.Sprintf("invalid delta %q", query), http.StatusBadRequest)
+			return
+		}
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already replied with an error
+		s.logger.Debug("WebSocket upgrade failed", zap.Error(err))
+		return
+	}
+
+	// the confirmation is queued first, so it comes before any event
+	c := newClient(s, conn, topics)
+	c.compress = compression == CompressionZstd
+	c.delta = delta
+	c.reply(EventSubscribed, c.subscription(topics))
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		c.close(websocket.CloseGoingAway, "server shutting down")
+		return
+	}
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	s.logger.Info("WebSocket client connected",
+		zap.String("remote", r.RemoteAddr),
+		zap.Strings("topics", topics),
+		zap.String("compression", compression),
+		zap.Bool("delta", delta),
+	)
+	go c.writeLoop()
+	go c.readLoop()
+}
+
+// authorized checks the bearer token of the Authorization header or of the token query parameter
+func (s *Server) authorized(r *http.Request) bool {
+	token := r.URL.Query().Get("token")
+	if header, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		token = header
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Token)) == 1
+}
+
+// parseTopics returns the sorted topics, failing on the ones the server doesn't serve
+func (s *Server) parseTopics(requested []string) ([]string, error) {
+	var topics []string
+	for _, t := range requested {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !slices.Contains(s.topics, t) {
+			return nil, fmt.Errorf("topic %s is not served, available topics: %s", t, strings.Join(s.topics, ","))
+		}
+		topics = append(topics, t)
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("at least one topic is required")
+	}
+	return slices.Compact(slices.Sorted(slices.Values(topics))), nil
+}
+
+// remove forgets a disconnected client
+func (s *Server) remove(c *client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, c)
+}
@@ -0,0 +1,229 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/gorilla/websocket"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// received is an event read by a test client
+type received struct {
+	EventType string         `json:"event_type"`
+	Data      map[string]any `json:"data"`
+	Patch     []PatchOp      `json:"patch"`
+}
+
+func startServer(t *testing.T, cfg Config) (*Server, string) {
+	t.Helper()
+	s, err := newServer(cfg)
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(s)
+	t.Cleanup(func() {
+		_ = s.Close()
+		httpServer.Close()
+	})
+	return s, "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func read(t *testing.T, conn *websocket.Conn) received {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var event received
+	require.NoError(t, conn.ReadJSON(&event))
+	return event
+}
+
+func send(t *testing.T, s *Server, eventType string, data map[string]any) {
+	t.Helper()
+	require.NoError(t, s.Send(context.Background(), notify.Event{Time: time.Now(), EventType: eventType, Data: data}))
+}
+
+func TestServer_Subscriptions(t *testing.T) {
+	s, url := startServer(t, Config{Topics: []string{"TICK_INFO", "ALERT_MARKET_STATE"}})
+
+	all := dial(t, url)
+	assert.Equal(t, []any{"ALERT_MARKET_STATE", "TICK_INFO"}, read(t, all).Data["topics"], "every topic by default")
+
+	alerts := dial(t, url+"?topics=ALERT_MARKET_STATE")
+	confirmed := read(t, alerts)
+	assert.Equal(t, EventSubscribed, confirmed.EventType)
+	assert.Equal(t, []any{"ALERT_MARKET_STATE"}, confirmed.Data["topics"])
+	require.Eventually(t, func() bool { return s.Stats().Clients == 2 }, time.Second, time.Millisecond)
+
+	send(t, s, "TICK_INFO", map[string]any{"n": 1})
+	send(t, s, "ALERT_MARKET_STATE", map[string]any{"n": 2})
+	assert.Equal(t, received{EventType: "TICK_INFO", Data: map[string]any{"n": float64(1)}}, read(t, all))
+	assert.Equal(t, "ALERT_MARKET_STATE", read(t, all).EventType)
+	assert.Equal(t, received{EventType: "ALERT_MARKET_STATE", Data: map[string]any{"n": float64(2)}}, read(t, alerts))
+
+	require.NoError(t, alerts.WriteJSON(Message{Action: ActionSubscribe, Topics: []string{"TICK_INFO"}}))
+	assert.Equal(t, []any{"ALERT_MARKET_STATE", "TICK_INFO"}, read(t, alerts).Data["topics"])
+	require.NoError(t, alerts.WriteJSON(Message{Action: ActionUnsubscribe, Topics: []string{"ALERT_MARKET_STATE"}}))
+	assert.Equal(t, []any{"TICK_INFO"}, read(t, alerts).Data["topics"])
+
+	require.NoError(t, alerts.WriteJSON(Message{Action: ActionSubscribe, Topics: []string{"MARKET_DATA"}}))
+	rejected := read(t, alerts)
+	assert.Equal(t, EventError, rejected.EventType)
+	assert.Contains(t, rejected.Data["message"], "topic MARKET_DATA is not served")
+
+	send(t, s, "ALERT_MARKET_STATE", map[string]any{"n": 3})
+	send(t, s, "TICK_INFO", map[string]any{"n": 4})
+	assert.Equal(t, received{EventType: "TICK_INFO", Data: map[string]any{"n": float64(4)}}, read(t, alerts), "unsubscribed topics aren't sent")
+}
+
+func TestServer_Compression(t *testing.T) {
+	s, url := startServer(t, Config{Topics: []string{"TICK_INFO"}})
+	conn := dial(t, url+"?compression=zstd")
+	decoder, err := zstd.NewReader(nil)
+	require.NoError(t, err)
+	defer decoder.Close()
+
+	readCompressed := func() received {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+		kind, payload, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.BinaryMessage, kind)
+		payload, err = decoder.DecodeAll(payload, nil)
+		require.NoError(t, err)
+		var event received
+		require.NoError(t, json.Unmarshal(payload, &event))
+		return event
+	}
+
+	confirmed := readCompressed()
+	assert.Equal(t, EventSubscribed, confirmed.EventType)
+	assert.Equal(t, CompressionZstd, confirmed.Data["compression"])
+
+	send(t, s, "TICK_INFO", map[string]any{"n": 1})
+	assert.Equal(t, received{EventType: "TICK_INFO", Data: map[string]any{"n": float64(1)}}, readCompressed())
+}
+
+func TestServer_DeltaFrames(t *testing.T) {
+	s, url := startServer(t, Config{Topics: []string{"MARKET_DATA"}, KeyframeInterval: time.Hour})
+	deltas := dial(t, url+"?delta=true")
+	assert.Equal(t, true, read(t, deltas).Data["delta"])
+	full := dial(t, url)
+	read(t, full)
+	require.Eventually(t, func() bool { return s.Stats().Clients == 2 }, time.Second, time.Millisecond)
+
+	symbols := strings.Repeat("BTCUSDT,", 20)
+	send(t, s, "MARKET_DATA", map[string]any{"ask": 1, "bid": 0.5, "symbols": symbols})
+	assert.Equal(t, map[string]any{"ask": float64(1), "bid": 0.5, "symbols": symbols}, read(t, deltas).Data, "the first event is sent in full")
+	send(t, s, "MARKET_DATA", map[string]any{"ask": 2, "bid": 0.5, "symbols": symbols})
+	assert.Equal(t, received{EventType: "MARKET_DATA", Patch: []PatchOp{{Op: "replace", Path: "/ask", Value: float64(2)}}}, read(t, deltas))
+
+	read(t, full)
+	assert.Equal(t, map[string]any{"ask": float64(2), "bid": 0.5, "symbols": symbols}, read(t, full).Data, "other clients get full events")
+
+	// a client missing an event gets the next one in full
+	var c *client
+	s.mu.RLock()
+	for candidate := range s.clients {
+		if candidate.delta {
+			c = candidate
+		}
+	}
+	s.mu.RUnlock()
+	c.sync("MARKET_DATA", 0)
+	send(t, s, "MARKET_DATA", map[string]any{"ask": 3, "bid": 0.5, "symbols": symbols})
+	assert.Equal(t, map[string]any{"ask": float64(3), "bid": 0.5, "symbols": symbols}, read(t, deltas).Data)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?compression=gzip", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_, resp, err = websocket.DefaultDialer.Dial(url+"?delta=maybe", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_Rejected(t *testing.T) {
+	_, err := newServer(Config{})
+	assert.EqualError(t, err, "at least one topic is required")
+
+	_, url := startServer(t, Config{Topics: []string{"TICK_INFO"}, Token: "secret"})
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	_, resp, err = websocket.DefaultDialer.Dial(url+"?token=secret&topics=MARKET_DATA", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Authorization": []string{"Bearer secret"}})
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, EventSubscribed, read(t, conn).EventType)
+}
+
+func TestServer_SlowClient(t *testing.T) {
+	s, err := newServer(Config{Topics: []string{"MARKET_DATA"}, Buffer: 2})
+	require.NoError(t, err)
+	slow := newClient(s, nil, []string{"MARKET_DATA"})
+	s.clients[slow] = struct{}{}
+
+	for range 5 {
+		send(t, s, "MARKET_DATA", map[string]any{})
+	}
+	assert.Len(t, slow.send, 2)
+	assert.Equal(t, int64(3), slow.dropped.Load())
+	assert.Equal(t, Stats{Clients: 1, Dropped: 3}, s.Stats())
+}
+
+func TestServer_DroppedReported(t *testing.T) {
+	s, url := startServer(t, Config{Topics: []string{"MARKET_DATA"}})
+	conn := dial(t, url)
+	read(t, conn)
+
+	var c *client
+	require.Eventually(t, func() bool {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		for c = range s.clients {
+		}
+		return c != nil
+	}, time.Second, time.Millisecond)
+	c.dropped.Store(3)
+
+	send(t, s, "MARKET_DATA", map[string]any{"n": 1})
+	assert.Equal(t, received{EventType: EventDropped, Data: map[string]any{"count": float64(3)}}, read(t, conn),
+		"the dropped events are reported before the next event")
+	assert.Equal(t, "MARKET_DATA", read(t, conn).EventType)
+}
+
+func TestNewServer(t *testing.T) {
+	s, err := NewServer(Config{Addr: "127.0.0.1:0", Topics: []string{"TICK_INFO"}})
+	require.NoError(t, err)
+
+	conn := dial(t, "ws://"+s.Addr()+DefaultPath)
+	assert.Equal(t, EventSubscribed, read(t, conn).EventType)
+
+	require.NoError(t, s.Close())
+	require.NoError(t, s.Close(), "closing twice is a no-op")
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "clients are told the server is going away, got %v", err)
+	assert.Error(t, s.Send(context.Background(), notify.Event{EventType: "TICK_INFO"}))
+
+	s, err = NewServer(Config{Addr: s.Addr(), Topics: []string{"TICK_INFO"}})
+	require.NoError(t, err, "the address is released on close")
+	assert.NoError(t, s.Close())
+}
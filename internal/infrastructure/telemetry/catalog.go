@@ -0,0 +1,20 @@
+package telemetry
+
+// MetricKind is the type of an emitted metric
+type MetricKind string
+
+// Metric kinds
+const (
+	KindCounter MetricKind = "counter"
+	KindGauge   MetricKind = "gauge"
+	KindTiming  MetricKind = "timing"
+	KindSpan    MetricKind = "span"
+)
+
+// Metric describes an emitted metric or span, so dashboards can be generated from the catalog
+type Metric struct {
+	Name        string     `json:"name"`
+	Kind        MetricKind `json:"kind"`
+	Description string     `json:"description"`
+	Tags        []string   `json:"tags,omitempty"` // metric specific tags, CommonTags are always added
+}
@@ -0,0 +1,80 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MultiProvider sends the metrics and spans to several providers, e.g. the Datadog traces and the Prometheus metrics
+type MultiProvider struct {
+	providers []Provider
+}
+
+// NewMultiProvider creates a new MultiProvider of the given providers
+func NewMultiProvider(providers ...Provider) *MultiProvider {
+	return &MultiProvider{providers: providers}
+}
+
+// Initialize initializes every provider, returning their joined errors
+func (mp *MultiProvider) Initialize(ctx context.Context) error {
+	var errs []error
+	for _, p := range mp.providers {
+		errs = append(errs, p.Initialize(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown stops every provider
+func (mp *MultiProvider) Shutdown() {
+	for _, p := range mp.providers {
+		p.Shutdown()
+	}
+}
+
+// multiSpan finishes and tags the spans of every provider
+type multiSpan []Span
+
+func (s multiSpan) SetTag(key string, value any) {
+	for _, span := range s {
+		span.SetTag(key, value)
+	}
+}
+
+func (s multiSpan) Finish() {
+	for _, span := range s {
+		span.Finish()
+	}
+}
+
+// StartSpan starts a span with every provider, the context carries all of them
+func (mp *MultiProvider) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	spans := make(multiSpan, 0, len(mp.providers))
+	for _, p := range mp.providers {
+		var span Span
+		span, ctx = p.StartSpan(ctx, operationName)
+		spans = append(spans, span)
+	}
+	return spans, ctx
+}
+
+// IncrementCounter increments a counter metric of every provider
+func (mp *MultiProvider) IncrementCounter(name string, value int64, tags ...string) {
+	for _, p := range mp.providers {
+		p.IncrementCounter(name, value, tags...)
+	}
+}
+
+// Gauge sets a gauge metric of every provider
+func (mp *MultiProvider) Gauge(name string, value float64, tags ...string) {
+	for _, p := range mp.providers {
+		p.Gauge(name, value, tags...)
+	}
+}
+
+// Timing records a timing metric of every provider
+func (mp *MultiProvider) Timing(name string, value time.Duration, tags ...string) {
+	for _, p := range mp.providers {
+		p.Timing(name, value, tags...)
+	}
+}
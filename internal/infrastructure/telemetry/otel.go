@@ -0,0 +1,339 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OTLP protocols of the exporters
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http"
+)
+
+// otelScope is the instrumentation scope of the tracer and the meter
+const otelScope = "github.com/ayankousky/exchange-data-importer"
+
+// otelShutdownTimeout bounds the flush of the pending spans and metrics on shutdown
+const otelShutdownTimeout = 5 * time.Second
+
+// OTelConfig holds configuration for the OpenTelemetry exporters
+type OTelConfig struct {
+	Endpoint       string        // host:port of the OTLP receiver, OTEL_EXPORTER_OTLP_ENDPOINT or localhost when empty
+	Protocol       string        // OTLPProtocolGRPC (port 4317) or OTLPProtocolHTTP (port 4318)
+	Insecure       bool          // export without TLS, e.g. to a collector next to the importer
+	ServiceName    string        // service.name of the resource
+	ServiceEnv     string        // deployment.environment of the resource
+	Tags           []string      // "key:value" attributes of the resource, e.g. the revision
+	Metrics        []Metric      // catalog of the emitted metrics, their descriptions become the instrument descriptions
+	ExportInterval time.Duration // interval of the metric exports, 60s when zero
+	EnableTracing  bool
+	EnableMetrics  bool
+}
+
+// OTelProvider exports the spans and metrics over OTLP, e.g. to an OpenTelemetry collector, Grafana Tempo or Mimir.
+// Counters become int64 counters, gauges float64 gauges and timings float64 histograms in seconds; the "key:value"
+// tags become attributes
+type OTelProvider struct {
+	config  *OTelConfig
+	catalog map[string]Metric
+
+	// spanExporter and metricReader replace the OTLP exporters when set, e.g. in-memory ones in tests
+	spanExporter sdktrace.SpanExporter
+	metricReader sdkmetric.Reader
+
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+	tracer         trace.Tracer
+	meter          metric.Meter
+
+	mu         sync.Mutex
+	counters   map[string]metric.Int64Counter
+	gauges     map[string]metric.Float64Gauge
+	histograms map[string]metric.Float64Histogram
+}
+
+// NewOTelProvider creates a new OTelProvider with the given config
+func NewOTelProvider(config *OTelConfig) *OTelProvider {
+	catalog := make(map[string]Metric, len(config.Metrics))
+	for _, m := range config.Metrics {
+		catalog[m.Name] = m
+	}
+	return &OTelProvider{
+		config:     config,
+		catalog:    catalog,
+		counters:   make(map[string]metric.Int64Counter),
+		gauges:     make(map[string]metric.Float64Gauge),
+		histograms: make(map[string]metric.Float64Histogram),
+	}
+}
+
+// Initialize sets up the configured exporters
+func (op *OTelProvider) Initialize(ctx context.Context) error {
+	if op.tracerProvider != nil || op.meterProvider != nil {
+		return nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, op.resourceAttributes()...))
+	if err != nil {
+		return fmt.Errorf("failed to create otel resource: %w", err)
+	}
+
+	if op.config.EnableTracing {
+		exporter := op.spanExporter
+		if exporter == nil {
+			if exporter, err = op.newSpanExporter(ctx); err != nil {
+				return fmt.Errorf("failed to create otlp trace exporter: %w", err)
+			}
+		}
+		op.tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+		op.tracer = op.tracerProvider.Tracer(otelScope)
+	}
+
+	if op.config.EnableMetrics {
+		reader := op.metricReader
+		if reader == nil {
+			exporter, err := op.newMetricExporter(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to create otlp metric exporter: %w", err)
+			}
+			var opts []sdkmetric.PeriodicReaderOption
+			if op.config.ExportInterval > 0 {
+				opts = append(opts, sdkmetric.WithInterval(op.config.ExportInterval))
+			}
+			reader = sdkmetric.NewPeriodicReader(exporter, opts...)
+		}
+		op.meterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res))
+		op.meter = op.meterProvider.Meter(otelScope)
+	}
+	return nil
+}
+
+// newSpanExporter creates the OTLP span exporter of the configured protocol
+func (op *OTelProvider) newSpanExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	if op.config.Protocol == OTLPProtocolHTTP {
+		var opts []otlptracehttp.Option
+		if op.config.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(op.config.Endpoint))
+		}
+		if op.config.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	var opts []otlptracegrpc.Option
+	if op.config.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(op.config.Endpoint))
+	}
+	if op.config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// newMetricExporter creates the OTLP metric exporter of the configured protocol
+func (op *OTelProvider) newMetricExporter(ctx context.Context) (sdkmetric.Exporter, error) {
+	if op.config.Protocol == OTLPProtocolHTTP {
+		var opts []otlpmetrichttp.Option
+		if op.config.Endpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(op.config.Endpoint))
+		}
+		if op.config.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	var opts []otlpmetricgrpc.Option
+	if op.config.Endpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(op.config.Endpoint))
+	}
+	if op.config.Insecure {
+		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// resourceAttributes returns the attributes of the service, its environment and the config tags
+func (op *OTelProvider) resourceAttributes() []attribute.KeyValue {
+	attrs := tagAttributes(op.config.Tags)
+	if op.config.ServiceName != "" {
+		attrs = append(attrs, semconv.ServiceName(op.config.ServiceName))
+	}
+	if op.config.ServiceEnv != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(op.config.ServiceEnv))
+	}
+	return attrs
+}
+
+// Shutdown flushes the pending spans and metrics and stops the exporters
+func (op *OTelProvider) Shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if op.tracerProvider != nil {
+		errs = append(errs, op.tracerProvider.Shutdown(ctx))
+	}
+	if op.meterProvider != nil {
+		errs = append(errs, op.meterProvider.Shutdown(ctx))
+	}
+	if err := errors.Join(errs...); err != nil {
+		fmt.Printf("failed to stop otel exporters: %v\n", err)
+	}
+}
+
+// otelSpan is a simple wrapper for an OpenTelemetry span
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetTag sets an attribute of the span. The Datadog error tags (error=true, error.message) and error values
+// also mark the span as failed
+func (s *otelSpan) SetTag(key string, value any) {
+	switch v := value.(type) {
+	case error:
+		s.span.RecordError(v)
+		s.span.SetStatus(codes.Error, v.Error())
+	case bool:
+		if key == "error" && v {
+			s.span.SetStatus(codes.Error, "")
+		}
+	case string:
+		if key == "error.message" {
+			s.span.SetStatus(codes.Error, v)
+		}
+	}
+	s.span.SetAttributes(anyAttribute(key, value))
+}
+
+func (s *otelSpan) Finish() {
+	s.span.End()
+}
+
+// StartSpan starts a new span, a child of the span in the context if any
+func (op *OTelProvider) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	if op.tracer == nil {
+		return &noopSpan{}, ctx
+	}
+
+	component, _, _ := strings.Cut(operationName, ".")
+	ctx, span := op.tracer.Start(ctx, operationName, trace.WithAttributes(attribute.String("component", component)))
+	return &otelSpan{span: span}, ctx
+}
+
+// IncrementCounter increments a counter metric
+func (op *OTelProvider) IncrementCounter(name string, value int64, tags ...string) {
+	if op.meter == nil {
+		return
+	}
+	op.mu.Lock()
+	counter, ok := op.counters[name]
+	if !ok {
+		var err error
+		if counter, err = op.meter.Int64Counter(name, metric.WithDescription(op.description(name))); err != nil {
+			op.mu.Unlock()
+			fmt.Printf("failed to create otel counter %s: %v\n", name, err)
+			return
+		}
+		op.counters[name] = counter
+	}
+	op.mu.Unlock()
+	counter.Add(context.Background(), value, metric.WithAttributes(tagAttributes(tags)...))
+}
+
+// Gauge sets a gauge metric
+func (op *OTelProvider) Gauge(name string, value float64, tags ...string) {
+	if op.meter == nil {
+		return
+	}
+	op.mu.Lock()
+	gauge, ok := op.gauges[name]
+	if !ok {
+		var err error
+		if gauge, err = op.meter.Float64Gauge(name, metric.WithDescription(op.description(name))); err != nil {
+			op.mu.Unlock()
+			fmt.Printf("failed to create otel gauge %s: %v\n", name, err)
+			return
+		}
+		op.gauges[name] = gauge
+	}
+	op.mu.Unlock()
+	gauge.Record(context.Background(), value, metric.WithAttributes(tagAttributes(tags)...))
+}
+
+// Timing records a timing metric in a histogram, in seconds
+func (op *OTelProvider) Timing(name string, value time.Duration, tags ...string) {
+	if op.meter == nil {
+		return
+	}
+	op.mu.Lock()
+	histogram, ok := op.histograms[name]
+	if !ok {
+		var err error
+		histogram, err = op.meter.Float64Histogram(name, metric.WithDescription(op.description(name)), metric.WithUnit("s"))
+		if err != nil {
+			op.mu.Unlock()
+			fmt.Printf("failed to create otel histogram %s: %v\n", name, err)
+			return
+		}
+		op.histograms[name] = histogram
+	}
+	op.mu.Unlock()
+	histogram.Record(context.Background(), value.Seconds(), metric.WithAttributes(tagAttributes(tags)...))
+}
+
+// description returns the description of a metric in the catalog
+func (op *OTelProvider) description(name string) string {
+	return op.catalog[name].Description
+}
+
+// tagAttributes returns the attributes of "key:value" tags
+func tagAttributes(tags []string) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(tags))
+	for _, tag := range tags {
+		key, value := splitTag(tag)
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	return attrs
+}
+
+// anyAttribute returns the attribute of a span tag, keeping the type of numbers and booleans
+func anyAttribute(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,169 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTestOTelProvider returns an initialized provider exporting to memory
+func newTestOTelProvider(t *testing.T) (*OTelProvider, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	spans, reader := tracetest.NewInMemoryExporter(), sdkmetric.NewManualReader()
+	op := NewOTelProvider(&OTelConfig{
+		ServiceName:   "importer",
+		ServiceEnv:    "test",
+		Tags:          []string{"revision:abc1234"},
+		Metrics:       []Metric{{Name: "tick.fetch.errors", Kind: KindCounter, Description: "Failed ticker fetches"}},
+		EnableTracing: true,
+		EnableMetrics: true,
+	})
+	op.spanExporter, op.metricReader = spans, reader
+	require.NoError(t, op.Initialize(context.Background()))
+	return op, spans, reader
+}
+
+func TestOTelProvider_Spans(t *testing.T) {
+	op, spans, _ := newTestOTelProvider(t)
+	defer op.Shutdown()
+
+	parent, ctx := op.StartSpan(context.Background(), "importTick")
+	child, _ := op.StartSpan(ctx, "fetchTickers.binance")
+	child.SetTag("tickers.count", 42)
+	child.SetTag("error", true)
+	child.SetTag("error.message", "timeout")
+	child.Finish()
+	parent.SetTag("exchange", "binance-perp")
+	parent.Finish()
+	require.NoError(t, op.tracerProvider.ForceFlush(context.Background()))
+
+	got := spans.GetSpans()
+	require.Len(t, got, 2)
+	assert.Equal(t, "fetchTickers.binance", got[0].Name)
+	assert.Equal(t, got[1].SpanContext.SpanID(), got[0].Parent.SpanID(), "the span in the context is the parent")
+	assert.Contains(t, got[0].Attributes, attribute.String("component", "fetchTickers"))
+	assert.Contains(t, got[0].Attributes, attribute.Int("tickers.count", 42))
+	assert.Equal(t, codes.Error, got[0].Status.Code)
+	assert.Equal(t, "timeout", got[0].Status.Description)
+	assert.Contains(t, got[1].Attributes, attribute.String("exchange", "binance-perp"))
+	assert.Equal(t, codes.Unset, got[1].Status.Code)
+
+	service, _ := got[1].Resource.Set().Value("service.name")
+	assert.Equal(t, "importer", service.AsString())
+	revision, _ := got[1].Resource.Set().Value("revision")
+	assert.Equal(t, "abc1234", revision.AsString())
+}
+
+func TestOTelProvider_SpanError(t *testing.T) {
+	op, spans, _ := newTestOTelProvider(t)
+	defer op.Shutdown()
+
+	span, _ := op.StartSpan(context.Background(), "storeTick")
+	span.SetTag("error", errors.New("disk full"))
+	span.Finish()
+	require.NoError(t, op.tracerProvider.ForceFlush(context.Background()))
+
+	got := spans.GetSpans()
+	require.Len(t, got, 1)
+	assert.Equal(t, codes.Error, got[0].Status.Code)
+	assert.Equal(t, "disk full", got[0].Status.Description)
+	require.Len(t, got[0].Events, 1, "the error is recorded as an event")
+}
+
+func TestOTelProvider_Metrics(t *testing.T) {
+	op, _, reader := newTestOTelProvider(t)
+	defer op.Shutdown()
+
+	op.IncrementCounter("tick.fetch.errors", 2, "exchange:binance-perp")
+	op.IncrementCounter("tick.fetch.errors", 1, "exchange:binance-perp")
+	op.Gauge("tick.quality.score", 0.9, "component:fetch")
+	op.Timing("tick.fetch.duration", 250*time.Millisecond)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	byName := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		byName[m.Name] = m
+	}
+
+	counter := byName["tick.fetch.errors"]
+	assert.Equal(t, "Failed ticker fetches", counter.Description)
+	sum, ok := counter.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 1)
+	assert.Equal(t, int64(3), sum.DataPoints[0].Value)
+	exchange, _ := sum.DataPoints[0].Attributes.Value("exchange")
+	assert.Equal(t, "binance-perp", exchange.AsString())
+
+	gauge, ok := byName["tick.quality.score"].Data.(metricdata.Gauge[float64])
+	require.True(t, ok)
+	require.Len(t, gauge.DataPoints, 1)
+	assert.Equal(t, 0.9, gauge.DataPoints[0].Value)
+
+	timing := byName["tick.fetch.duration"]
+	assert.Equal(t, "s", timing.Unit)
+	histogram, ok := timing.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, histogram.DataPoints, 1)
+	assert.Equal(t, 0.25, histogram.DataPoints[0].Sum)
+}
+
+func TestOTelProvider_Disabled(t *testing.T) {
+	op := NewOTelProvider(&OTelConfig{})
+	require.NoError(t, op.Initialize(context.Background()))
+
+	span, ctx := op.StartSpan(context.Background(), "importTick")
+	assert.IsType(t, &noopSpan{}, span)
+	assert.Equal(t, context.Background(), ctx)
+	assert.NotPanics(t, func() {
+		op.IncrementCounter("tick.fetch.errors", 1)
+		op.Gauge("tick.quality.score", 1)
+		op.Timing("tick.fetch.duration", time.Second)
+		op.Shutdown()
+	})
+}
+
+func TestOTelProvider_ExportsOverHTTP(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	op := NewOTelProvider(&OTelConfig{
+		Endpoint:      strings.TrimPrefix(collector.URL, "http://"),
+		Protocol:      OTLPProtocolHTTP,
+		Insecure:      true,
+		EnableTracing: true,
+		EnableMetrics: true,
+	})
+	require.NoError(t, op.Initialize(context.Background()))
+
+	span, _ := op.StartSpan(context.Background(), "importTick")
+	span.Finish()
+	op.IncrementCounter("tick.fetch.errors", 1)
+	op.Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"/v1/traces", "/v1/metrics"}, paths, "the shutdown flushes the spans and metrics")
+}
@@ -0,0 +1,286 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultPrometheusPath is the path the metrics are served on
+const DefaultPrometheusPath = "/metrics"
+
+// prometheusShutdownTimeout bounds the shutdown of the metrics endpoint
+const prometheusShutdownTimeout = 5 * time.Second
+
+// invalidPrometheusName matches the characters not allowed in Prometheus metric and label names
+var invalidPrometheusName = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// PrometheusConfig holds configuration for the Prometheus metrics endpoint
+type PrometheusConfig struct {
+	Addr      string    // listen address of the endpoint, e.g. :9090
+	Path      string    // path of the endpoint, DefaultPrometheusPath when empty
+	Namespace string    // prefix of the metric names, e.g. the service name
+	Tags      []string  // "key:value" constant labels of every metric, e.g. the revision
+	Metrics   []Metric  // catalog of the emitted metrics, their descriptions and tags become help texts and labels
+	Buckets   []float64 // histogram buckets of the timings in seconds, prometheus.DefBuckets when empty
+}
+
+// PrometheusProvider serves the metrics on an HTTP endpoint for Prometheus to scrape. Counters become counters
+// (name_total), gauges gauges and timings histograms (name_seconds); the "key:value" tags become labels.
+// A metric has the common tags and the tags of its catalog entry as labels, a metric missing from the catalog
+// the tags of its first use; tags outside its labels are dropped and missing labels are empty.
+// Prometheus has no tracing, spans are no-ops
+type PrometheusProvider struct {
+	config   *PrometheusConfig
+	registry *prometheus.Registry
+	catalog  map[string]Metric
+	server   *http.Server
+	listener net.Listener
+
+	mu         sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	gauges     map[string]*prometheus.GaugeVec
+	histograms map[string]*prometheus.HistogramVec
+	labels     map[string][]string // label names of every created metric
+}
+
+// NewPrometheusProvider creates a new PrometheusProvider with the given config
+func NewPrometheusProvider(config *PrometheusConfig) *PrometheusProvider {
+	catalog := make(map[string]Metric, len(config.Metrics))
+	for _, metric := range config.Metrics {
+		catalog[metric.Name] = metric
+	}
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	return &PrometheusProvider{
+		config:     config,
+		registry:   registry,
+		catalog:    catalog,
+		counters:   make(map[string]*prometheus.CounterVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+		labels:     make(map[string][]string),
+	}
+}
+
+// Initialize starts serving the metrics endpoint
+func (pp *PrometheusProvider) Initialize(_ context.Context) error {
+	if pp.server != nil {
+		return nil
+	}
+	path := pp.config.Path
+	if path == "" {
+		path = DefaultPrometheusPath
+	}
+
+	listener, err := net.Listen("tcp", pp.config.Addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", pp.config.Addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, pp.Handler())
+	pp.listener = listener
+	pp.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := pp.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("prometheus metrics endpoint stopped: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+// Handler returns the handler serving the metrics
+func (pp *PrometheusProvider) Handler() http.Handler {
+	return promhttp.HandlerFor(pp.registry, promhttp.HandlerOpts{})
+}
+
+// Addr returns the address the endpoint listens on, e.g. to find the port picked for :0
+func (pp *PrometheusProvider) Addr() string {
+	if pp.listener == nil {
+		return ""
+	}
+	return pp.listener.Addr().String()
+}
+
+// Shutdown stops the metrics endpoint
+func (pp *PrometheusProvider) Shutdown() {
+	if pp.server == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusShutdownTimeout)
+	defer cancel()
+	if err := pp.server.Shutdown(ctx); err != nil {
+		fmt.Printf("failed to stop prometheus metrics endpoint: %v\n", err)
+	}
+}
+
+// StartSpan returns a no-op span, Prometheus has no tracing
+func (pp *PrometheusProvider) StartSpan(ctx context.Context, _ string) (Span, context.Context) {
+	return &noopSpan{}, ctx
+}
+
+// IncrementCounter increments a counter metric
+func (pp *PrometheusProvider) IncrementCounter(name string, value int64, tags ...string) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	counter, ok := pp.counters[name]
+	if !ok {
+		labels := pp.labelNames(name, tags)
+		counter = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        pp.metricName(name) + "_total",
+			Help:        pp.help(name),
+			ConstLabels: pp.constLabels(),
+		}, labels)
+		if !pp.register(name, counter, labels) {
+			return
+		}
+		pp.counters[name] = counter
+	}
+	counter.WithLabelValues(pp.labelValues(name, tags)...).Add(float64(value))
+}
+
+// Gauge sets a gauge metric
+func (pp *PrometheusProvider) Gauge(name string, value float64, tags ...string) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	gauge, ok := pp.gauges[name]
+	if !ok {
+		labels := pp.labelNames(name, tags)
+		gauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        pp.metricName(name),
+			Help:        pp.help(name),
+			ConstLabels: pp.constLabels(),
+		}, labels)
+		if !pp.register(name, gauge, labels) {
+			return
+		}
+		pp.gauges[name] = gauge
+	}
+	gauge.WithLabelValues(pp.labelValues(name, tags)...).Set(value)
+}
+
+// Timing records a timing metric in a histogram, in seconds
+func (pp *PrometheusProvider) Timing(name string, value time.Duration, tags ...string) {
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+	histogram, ok := pp.histograms[name]
+	if !ok {
+		labels := pp.labelNames(name, tags)
+		histogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        pp.metricName(name) + "_seconds",
+			Help:        pp.help(name),
+			ConstLabels: pp.constLabels(),
+			Buckets:     pp.config.Buckets,
+		}, labels)
+		if !pp.register(name, histogram, labels) {
+			return
+		}
+		pp.histograms[name] = histogram
+	}
+	histogram.WithLabelValues(pp.labelValues(name, tags)...).Observe(value.Seconds())
+}
+
+// register registers a new metric and keeps its label names; must be called with mu held
+func (pp *PrometheusProvider) register(name string, collector prometheus.Collector, labels []string) bool {
+	if err := pp.registry.Register(collector); err != nil {
+		fmt.Printf("failed to register prometheus metric %s: %v\n", name, err)
+		return false
+	}
+	pp.labels[name] = labels
+	return true
+}
+
+// metricName returns the Prometheus name of a metric, e.g. importer_tick_fetch_duration for tick.fetch.duration
+func (pp *PrometheusProvider) metricName(name string) string {
+	if pp.config.Namespace != "" {
+		name = pp.config.Namespace + "_" + name
+	}
+	return sanitizePrometheusName(name)
+}
+
+// help returns the description of a metric in the catalog
+func (pp *PrometheusProvider) help(name string) string {
+	if metric, ok := pp.catalog[name]; ok && metric.Description != "" {
+		return metric.Description
+	}
+	return name
+}
+
+// labelNames returns the sorted label names of a new metric: the common tags and the tags of its catalog entry,
+// the keys of the given tags when it's missing from the catalog
+func (pp *PrometheusProvider) labelNames(name string, tags []string) []string {
+	keys := slices.Clone(CommonTags)
+	if metric, ok := pp.catalog[name]; ok {
+		keys = append(keys, metric.Tags...)
+	} else {
+		for _, tag := range tags {
+			key, _ := splitTag(tag)
+			keys = append(keys, key)
+		}
+	}
+	for i, key := range keys {
+		keys[i] = sanitizePrometheusName(key)
+	}
+	constLabels := pp.constLabels()
+	keys = slices.DeleteFunc(keys, func(key string) bool {
+		_, ok := constLabels[key]
+		return ok
+	})
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// labelValues returns the values of the labels of a metric from the tags; must be called with mu held
+func (pp *PrometheusProvider) labelValues(name string, tags []string) []string {
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value := splitTag(tag)
+		values[sanitizePrometheusName(key)] = value
+	}
+	labels := pp.labels[name]
+	result := make([]string, len(labels))
+	for i, label := range labels {
+		result[i] = values[label]
+	}
+	return result
+}
+
+// constLabels returns the labels of the config tags
+func (pp *PrometheusProvider) constLabels() prometheus.Labels {
+	labels := make(prometheus.Labels, len(pp.config.Tags))
+	for _, tag := range pp.config.Tags {
+		key, value := splitTag(tag)
+		labels[sanitizePrometheusName(key)] = value
+	}
+	return labels
+}
+
+// splitTag splits a "key:value" tag, a tag without a value is "true"
+func splitTag(tag string) (string, string) {
+	key, value, ok := strings.Cut(tag, ":")
+	if !ok {
+		return tag, "true"
+	}
+	return key, value
+}
+
+// sanitizePrometheusName replaces the characters Prometheus doesn't allow in names with underscores
+func sanitizePrometheusName(name string) string {
+	name = invalidPrometheusName.ReplaceAllString(name, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
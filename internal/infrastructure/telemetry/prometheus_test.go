@@ -0,0 +1,86 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape returns the metrics served by the provider
+func scrape(t *testing.T, pp *PrometheusProvider) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	pp.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestPrometheusProvider_Metrics(t *testing.T) {
+	pp := NewPrometheusProvider(&PrometheusConfig{
+		Namespace: "importer",
+		Tags:      []string{"revision:abc1234"},
+		Metrics: []Metric{
+			{Name: "tick.fetch.errors", Kind: KindCounter, Description: "Failed ticker fetches"},
+			{Name: "notifier.sent", Kind: KindCounter, Description: "Sent notifications", Tags: []string{"notifier"}},
+		},
+	})
+	tagged := NewTaggedProvider(pp, map[string]string{TagExchange: "binance-perp"})
+
+	tagged.IncrementCounter("tick.fetch.errors", 2)
+	tagged.IncrementCounter("tick.fetch.errors", 1)
+	tagged.IncrementCounter("notifier.sent", 1, "notifier:redis", "unknown:dropped")
+	tagged.Gauge("tick.quality.score", 0.9, "component:fetch")
+	tagged.Timing("tick.fetch.duration", 250*time.Millisecond)
+	pp.Gauge("tick.quality.score", 0.5, "component:coverage", "other:dropped")
+
+	body := scrape(t, pp)
+	assert.Contains(t, body, "# HELP importer_tick_fetch_errors_total Failed ticker fetches")
+	assert.Contains(t, body, `importer_tick_fetch_errors_total{exchange="binance-perp",repository="",revision="abc1234",symbols=""} 3`)
+	assert.Contains(t, body, `importer_notifier_sent_total{exchange="binance-perp",notifier="redis",repository="",revision="abc1234",symbols=""} 1`,
+		"tags outside the labels of the metric are dropped")
+	assert.Contains(t, body, `importer_tick_quality_score{component="fetch",exchange="binance-perp",repository="",revision="abc1234",symbols=""} 0.9`,
+		"a metric missing from the catalog takes the tags of its first use")
+	assert.Contains(t, body, `importer_tick_quality_score{component="coverage",exchange="",repository="",revision="abc1234",symbols=""} 0.5`)
+	assert.Contains(t, body, `importer_tick_fetch_duration_seconds_sum{exchange="binance-perp",repository="",revision="abc1234",symbols=""} 0.25`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestPrometheusProvider_Endpoint(t *testing.T) {
+	pp := NewPrometheusProvider(&PrometheusConfig{Addr: "127.0.0.1:0", Path: "/prom"})
+	require.NoError(t, pp.Initialize(context.Background()))
+	defer pp.Shutdown()
+
+	pp.IncrementCounter("liquidations.errors", 1)
+	resp, err := http.Get("http://" + pp.Addr() + "/prom")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "liquidations_errors_total")
+}
+
+func TestSanitizePrometheusName(t *testing.T) {
+	assert.Equal(t, "importer_tick_fetch_duration", sanitizePrometheusName("importer.tick.fetch-duration"))
+	assert.Equal(t, "_24h_volume", sanitizePrometheusName("24h.volume"))
+}
+
+func TestMultiProvider(t *testing.T) {
+	first, second := newRecordingProvider(), newRecordingProvider()
+	mp := NewMultiProvider(first, second)
+
+	mp.Gauge("tick.quality.score", 1, "component:fetch")
+	assert.Equal(t, []string{"component:fetch"}, first.tags["tick.quality.score"])
+	assert.Equal(t, []string{"component:fetch"}, second.tags["tick.quality.score"])
+
+	span, _ := mp.StartSpan(context.Background(), "importTick")
+	span.SetTag("exchange", "binance-perp")
+	assert.Equal(t, "binance-perp", first.spanTags["exchange"])
+	assert.Equal(t, "binance-perp", second.spanTags["exchange"])
+}
@@ -0,0 +1,132 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Common tags added to every metric and span of the importer
+const (
+	// TagExchange is the exchange the importer reads from
+	TagExchange = "exchange"
+
+	// TagSymbols is the bucket of the number of symbols fetched in the last tick, see SymbolCountBucket
+	TagSymbols = "symbols"
+
+	// TagRepository is the repository backend ticks are stored in
+	TagRepository = "repository"
+)
+
+// CommonTags lists the tags every metric and span is tagged with
+var CommonTags = []string{TagExchange, TagSymbols, TagRepository}
+
+// Tagger is implemented by providers that allow updating their common tags at runtime
+type Tagger interface {
+	// SetTag sets a tag added to every metric and span
+	SetTag(key, value string)
+}
+
+// TaggedProvider decorates a Provider with common tags added to every metric and span
+type TaggedProvider struct {
+	Provider
+
+	mu       sync.Mutex
+	tags     map[string]string
+	rendered atomic.Pointer[[]string] // "key:value" tags, rebuilt on every SetTag
+}
+
+// NewTaggedProvider creates a new TaggedProvider with the given initial tags
+func NewTaggedProvider(base Provider, tags map[string]string) *TaggedProvider {
+	p := &TaggedProvider{Provider: base, tags: make(map[string]string, len(tags))}
+	maps.Copy(p.tags, tags)
+	p.render()
+	return p
+}
+
+// SetTag sets a tag added to every metric and span; an empty value removes the tag
+func (p *TaggedProvider) SetTag(key, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tags[key] == value {
+		return
+	}
+	if value == "" {
+		delete(p.tags, key)
+	} else {
+		p.tags[key] = value
+	}
+	p.render()
+}
+
+// Tags returns the common tags in "key:value" format
+func (p *TaggedProvider) Tags() []string {
+	return *p.rendered.Load()
+}
+
+// StartSpan starts a new span tagged with the common tags
+func (p *TaggedProvider) StartSpan(ctx context.Context, operationName string) (Span, context.Context) {
+	span, ctx := p.Provider.StartSpan(ctx, operationName)
+
+	p.mu.Lock()
+	for key, value := range p.tags {
+		span.SetTag(key, value)
+	}
+	p.mu.Unlock()
+
+	return span, ctx
+}
+
+// IncrementCounter increments a counter metric with the common tags
+func (p *TaggedProvider) IncrementCounter(name string, value int64, tags ...string) {
+	p.Provider.IncrementCounter(name, value, p.withTags(tags)...)
+}
+
+// Gauge sets a gauge metric with the common tags
+func (p *TaggedProvider) Gauge(name string, value float64, tags ...string) {
+	p.Provider.Gauge(name, value, p.withTags(tags)...)
+}
+
+// Timing records a timing metric with the common tags
+func (p *TaggedProvider) Timing(name string, value time.Duration, tags ...string) {
+	p.Provider.Timing(name, value, p.withTags(tags)...)
+}
+
+// withTags appends the metric specific tags to the common ones
+func (p *TaggedProvider) withTags(tags []string) []string {
+	common := p.Tags()
+	if len(tags) == 0 {
+		return common
+	}
+	return append(slices.Clip(common), tags...)
+}
+
+// render rebuilds the "key:value" tags; must be called with mu held
+func (p *TaggedProvider) render() {
+	rendered := make([]string, 0, len(p.tags))
+	for _, key := range slices.Sorted(maps.Keys(p.tags)) {
+		rendered = append(rendered, fmt.Sprintf("%s:%s", key, p.tags[key]))
+	}
+	p.rendered.Store(&rendered)
+}
+
+// SymbolCountBucket groups the number of symbols into a low-cardinality tag value
+func SymbolCountBucket(count int) string {
+	switch {
+	case count < 100:
+		return "lt_100"
+	case count < 250:
+		return "100_249"
+	case count < 500:
+		return "250_499"
+	case count < 1000:
+		return "500_999"
+	default:
+		return "gte_1000"
+	}
+}
@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingProvider records the tags of every metric and span
+type recordingProvider struct {
+	NoopProvider
+
+	mu       sync.Mutex
+	tags     map[string][]string
+	spanTags map[string]any
+}
+
+func newRecordingProvider() *recordingProvider {
+	return &recordingProvider{tags: map[string][]string{}, spanTags: map[string]any{}}
+}
+
+func (p *recordingProvider) record(name string, tags []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tags[name] = tags
+}
+
+func (p *recordingProvider) IncrementCounter(name string, _ int64, tags ...string) {
+	p.record(name, tags)
+}
+func (p *recordingProvider) Gauge(name string, _ float64, tags ...string) { p.record(name, tags) }
+func (p *recordingProvider) Timing(name string, _ time.Duration, tags ...string) {
+	p.record(name, tags)
+}
+
+func (p *recordingProvider) StartSpan(ctx context.Context, _ string) (Span, context.Context) {
+	return &recordingSpan{p: p}, ctx
+}
+
+type recordingSpan struct{ p *recordingProvider }
+
+func (s *recordingSpan) SetTag(key string, value any) { s.p.spanTags[key] = value }
+func (s *recordingSpan) Finish()                      {}
+
+func TestTaggedProvider(t *testing.T) {
+	base := newRecordingProvider()
+	p := NewTaggedProvider(base, map[string]string{TagRepository: "mongo", TagExchange: "binance"})
+
+	p.IncrementCounter("counter", 1, "topic:a")
+	p.SetTag(TagSymbols, SymbolCountBucket(300))
+	p.Gauge("gauge", 1)
+	p.SetTag(TagRepository, "")
+	p.Timing("timing", time.Second, "symbol:BTC")
+	span, _ := p.StartSpan(context.Background(), "op")
+	span.Finish()
+
+	assert.Equal(t, []string{"exchange:binance", "repository:mongo", "topic:a"}, base.tags["counter"])
+	assert.Equal(t, []string{"exchange:binance", "repository:mongo", "symbols:250_499"}, base.tags["gauge"])
+	assert.Equal(t, []string{"exchange:binance", "symbols:250_499", "symbol:BTC"}, base.tags["timing"])
+	assert.Equal(t, map[string]any{"exchange": "binance", "symbols": "250_499"}, base.spanTags)
+}
+
+func TestTaggedProvider_DoesNotShareTagSlices(t *testing.T) {
+	base := newRecordingProvider()
+	p := NewTaggedProvider(base, map[string]string{TagExchange: "okx"})
+
+	p.IncrementCounter("a", 1, "topic:a")
+	p.IncrementCounter("b", 1, "topic:b")
+
+	assert.Equal(t, []string{"exchange:okx", "topic:a"}, base.tags["a"])
+	assert.Equal(t, []string{"exchange:okx", "topic:b"}, base.tags["b"])
+	assert.Equal(t, []string{"exchange:okx"}, p.Tags())
+}
+
+func TestSymbolCountBucket(t *testing.T) {
+	tests := map[int]string{0: "lt_100", 99: "lt_100", 100: "100_249", 499: "250_499", 500: "500_999", 5000: "gte_1000"}
+	for count, want := range tests {
+		assert.Equal(t, want, SymbolCountBucket(count), count)
+	}
+}
@@ -0,0 +1,32 @@
+// Package telemetrytest provides telemetry providers recording what the code under test emits
+package telemetrytest
+
+import (
+	"sync"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+)
+
+// Counting is a telemetry.Provider summing the counter increments by name, the other metrics are dropped
+type Counting struct {
+	telemetry.NoopProvider
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+// IncrementCounter adds value to the counter, ignoring the tags
+func (c *Counting) IncrementCounter(name string, value int64, _ ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counters == nil {
+		c.counters = make(map[string]int64)
+	}
+	c.counters[name] += value
+}
+
+// Counter returns the sum of the increments of the counter
+func (c *Counting) Counter(name string) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counters[name]
+}
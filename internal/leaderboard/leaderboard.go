@@ -0,0 +1,120 @@
+// Package leaderboard ranks the stored liquidations of an exchange: the symbols with the largest notional liquidated
+// within a window and the largest single liquidations of the current UTC day, both computed by the repository
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+)
+
+// Config holds the configuration of the leaderboards
+type Config struct {
+	Window time.Duration // range of the symbols board, ending now
+	Limit  int           // entries per board
+}
+
+// Report holds the leaderboards of an exchange
+type Report struct {
+	Exchange   string            `json:"exchange"`
+	At         time.Time         `json:"at"`
+	TopSymbols SymbolsBoard      `json:"top_symbols"`
+	Largest    LiquidationsBoard `json:"largest_liquidations"`
+}
+
+// SymbolsBoard ranks the symbols by the USD notional liquidated within [From, To)
+type SymbolsBoard struct {
+	From    time.Time                          `json:"from"`
+	To      time.Time                          `json:"to"`
+	Symbols []domain.LiquidationSymbolNotional `json:"symbols"`
+}
+
+// LiquidationsBoard ranks the single liquidations within [From, To) by their USD value
+type LiquidationsBoard struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Liquidations []Entry   `json:"liquidations"`
+}
+
+// Entry is a ranked liquidation
+type Entry struct {
+	EventAt  time.Time         `json:"et"`
+	Symbol   domain.TickerName `json:"symbol"`
+	Side     domain.OrderSide  `json:"side"` // SELL for a long liquidation, BUY for a short one
+	Price    float64           `json:"price"`
+	Quantity float64           `json:"quantity"`
+	Notional float64           `json:"notional"` // USD, 0 without a known rate
+	Source   string            `json:"source,omitempty"`
+}
+
+// Build queries both leaderboards as of now
+func Build(ctx context.Context, repo domain.LiquidationLeaderboardRepository, exchange string, cfg Config, now time.Time) (Report, error) {
+	now = now.UTC()
+	report := Report{
+		Exchange:   exchange,
+		At:         now,
+		TopSymbols: SymbolsBoard{From: now.Add(-cfg.Window), To: now},
+		Largest:    LiquidationsBoard{From: now.Truncate(24 * time.Hour), To: now},
+	}
+
+	symbols, err := repo.TopSymbolsByNotional(ctx, report.TopSymbols.From, report.TopSymbols.To, cfg.Limit)
+	if err != nil {
+		return report, fmt.Errorf("ranking symbols: %w", err)
+	}
+	report.TopSymbols.Symbols = symbols
+
+	largest, err := repo.LargestLiquidations(ctx, report.Largest.From, report.Largest.To, cfg.Limit)
+	if err != nil {
+		return report, fmt.Errorf("ranking liquidations: %w", err)
+	}
+	for _, l := range largest {
+		report.Largest.Liquidations = append(report.Largest.Liquidations, Entry{
+			EventAt:  l.EventAt.UTC(),
+			Symbol:   l.Order.Symbol,
+			Side:     l.Order.Side,
+			Price:    l.Order.Price,
+			Quantity: l.Order.Quantity,
+			Notional: l.Order.USDValue,
+			Source:   l.Source,
+		})
+	}
+	return report, nil
+}
+
+// Print writes both leaderboards as aligned tables
+func Print(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "TOP SYMBOLS BY LIQUIDATED NOTIONAL: %s, %s - %s\n", report.Exchange, formatTime(report.TopSymbols.From), formatTime(report.TopSymbols.To))
+	fmt.Fprintln(tw, "#\tSYMBOL\tNOTIONAL USD\tLONG USD\tSHORT USD\tCOUNT")
+	for i, s := range report.TopSymbols.Symbols {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\n", i+1, s.Symbol, formatUSD(s.Notional), formatUSD(s.Long), formatUSD(s.Short), s.Count)
+	}
+	// flush per board so the columns of a board do not widen the other
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("print top symbols: %w", err)
+	}
+
+	fmt.Fprintf(tw, "\nLARGEST LIQUIDATIONS: %s, %s - %s\n", report.Exchange, formatTime(report.Largest.From), formatTime(report.Largest.To))
+	fmt.Fprintln(tw, "#\tTIME\tSYMBOL\tSIDE\tPRICE\tQUANTITY\tNOTIONAL USD")
+	for i, l := range report.Largest.Liquidations {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%g\t%g\t%s\n", i+1, formatTime(l.EventAt), l.Symbol, l.Side, l.Price, l.Quantity, formatUSD(l.Notional))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("print largest liquidations: %w", err)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.DateTime)
+}
+
+func formatUSD(v float64) string {
+	return strconv.FormatFloat(v, 'f', 0, 64)
+}
@@ -0,0 +1,71 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/memory"
+)
+
+func liquidation(at time.Time, symbol domain.TickerName, side domain.OrderSide, usd float64) domain.Liquidation {
+	return domain.Liquidation{
+		EventAt: at,
+		Order:   domain.Order{EventAt: at, Symbol: symbol, Side: side, Price: 100, Quantity: usd / 100, USDValue: usd},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	repo := &memory.InMemoryLiquidationRepository{}
+	for _, l := range []domain.Liquidation{
+		liquidation(now.Add(-13*time.Hour), "BTCUSDT", domain.OrderSideSell, 900_000), // yesterday, outside both boards
+		liquidation(now.Add(-20*time.Minute), "BTCUSDT", domain.OrderSideSell, 50_000),
+		liquidation(now.Add(-10*time.Minute), "BTCUSDT", domain.OrderSideBuy, 20_000),
+		liquidation(now.Add(-5*time.Minute), "ETHUSDT", domain.OrderSideSell, 60_000),
+		liquidation(now.Add(-time.Minute), "SOLUSDT", domain.OrderSideBuy, 1_000),
+		liquidation(now.Add(-45*time.Minute), "XRPUSDT", domain.OrderSideSell, 500_000), // outside the window, still today
+	} {
+		require.NoError(t, repo.Create(context.Background(), l))
+	}
+
+	report, err := Build(context.Background(), repo, "binance-perp", Config{Window: 30 * time.Minute, Limit: 2}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, now.Add(-30*time.Minute), report.TopSymbols.From)
+	assert.Equal(t, []domain.LiquidationSymbolNotional{
+		{Symbol: "BTCUSDT", Notional: 70_000, Long: 50_000, Short: 20_000, Count: 2},
+		{Symbol: "ETHUSDT", Notional: 60_000, Long: 60_000, Count: 1},
+	}, report.TopSymbols.Symbols)
+
+	assert.Equal(t, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), report.Largest.From, "the day starts at UTC midnight")
+	require.Len(t, report.Largest.Liquidations, 2)
+	assert.Equal(t, domain.TickerName("XRPUSDT"), report.Largest.Liquidations[0].Symbol)
+	assert.Equal(t, 500_000.0, report.Largest.Liquidations[0].Notional)
+	assert.Equal(t, domain.TickerName("ETHUSDT"), report.Largest.Liquidations[1].Symbol)
+}
+
+func TestPrint(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	report := Report{
+		Exchange:   "binance-perp",
+		TopSymbols: SymbolsBoard{From: now.Add(-time.Hour), To: now, Symbols: []domain.LiquidationSymbolNotional{{Symbol: "BTCUSDT", Notional: 70_000.4, Long: 50_000, Short: 20_000.4, Count: 2}}},
+		Largest: LiquidationsBoard{From: now.Truncate(24 * time.Hour), To: now, Liquidations: []Entry{
+			{EventAt: now.Add(-time.Minute), Symbol: "ETHUSDT", Side: domain.OrderSideSell, Price: 3000.5, Quantity: 20, Notional: 60_010},
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, report))
+
+	out := buf.String()
+	assert.Contains(t, out, "TOP SYMBOLS BY LIQUIDATED NOTIONAL: binance-perp, 2025-03-01 11:00:00 - 2025-03-01 12:00:00")
+	assert.Regexp(t, `1\s+BTCUSDT\s+70000\s+50000\s+20000\s+2`, out)
+	assert.Contains(t, out, "LARGEST LIQUIDATIONS: binance-perp, 2025-03-01 00:00:00 - 2025-03-01 12:00:00")
+	assert.Regexp(t, `1\s+2025-03-01 11:59:00\s+ETHUSDT\s+SELL\s+3000.5\s+20\s+60010`, out)
+}
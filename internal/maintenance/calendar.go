@@ -0,0 +1,155 @@
+// Package maintenance describes the scheduled maintenance windows of an exchange, during which its outages are expected.
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// weekdays maps the day names of a window spec to their weekday
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Window is a weekly maintenance window in UTC, it may span midnight
+type Window struct {
+	spec  string
+	days  [7]bool       // days the window starts on, indexed by time.Weekday
+	start time.Duration // since midnight
+	end   time.Duration // since midnight, at or before start when the window ends the next day
+}
+
+// ParseWindow parses a window spec of the form "<days> <HH:MM>-<HH:MM>" in UTC. Days are * for every day,
+// a day (Tue), a range (Mon-Fri) or a comma-separated list of both (Mon,Wed-Thu). A window ending at or before
+// its start ends the next day, e.g. "Sat 23:00-01:00"
+func ParseWindow(spec string) (Window, error) {
+	w := Window{spec: strings.TrimSpace(spec)}
+	days, hours, ok := strings.Cut(w.spec, " ")
+	if !ok {
+		return Window{}, fmt.Errorf("window %q: expected <days> <HH:MM>-<HH:MM>", spec)
+	}
+	if err := w.parseDays(days); err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", spec, err)
+	}
+
+	from, to, ok := strings.Cut(strings.TrimSpace(hours), "-")
+	if !ok {
+		return Window{}, fmt.Errorf("window %q: expected a <HH:MM>-<HH:MM> time range, got %q", spec, hours)
+	}
+	var err error
+	if w.start, err = parseClock(from); err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", spec, err)
+	}
+	if w.end, err = parseClock(to); err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", spec, err)
+	}
+	return w, nil
+}
+
+// parseDays sets the days the window starts on
+func (w *Window) parseDays(days string) error {
+	if days == "*" {
+		for day := range w.days {
+			w.days[day] = true
+		}
+		return nil
+	}
+	for _, part := range strings.Split(days, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		from, ok := weekdays[strings.ToLower(first)]
+		if !ok {
+			return fmt.Errorf("unknown day %q", first)
+		}
+		to := from
+		if isRange {
+			if to, ok = weekdays[strings.ToLower(last)]; !ok {
+				return fmt.Errorf("unknown day %q", last)
+			}
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			w.days[day] = true
+			if day == to {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock parses a HH:MM time of day
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls within the window
+func (w Window) Contains(t time.Time) bool {
+	midnight, sinceMidnight := splitDay(t)
+	day := midnight.Weekday()
+	if w.start < w.end {
+		return w.days[day] && sinceMidnight >= w.start && sinceMidnight < w.end
+	}
+	// the window spans midnight, its end belongs to the day after it started
+	yesterday := (day + 6) % 7
+	return (w.days[day] && sinceMidnight >= w.start) || (w.days[yesterday] && sinceMidnight < w.end)
+}
+
+// End returns the end of the occurrence of the window starting before t, the one containing t if any
+func (w Window) End(t time.Time) time.Time {
+	midnight, sinceMidnight := splitDay(t)
+	if w.start >= w.end && sinceMidnight >= w.start {
+		return midnight.AddDate(0, 0, 1).Add(w.end)
+	}
+	return midnight.Add(w.end)
+}
+
+// splitDay returns the UTC midnight of the day of t and the time elapsed since
+func splitDay(t time.Time) (time.Time, time.Duration) {
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return midnight, t.Sub(midnight)
+}
+
+// String returns the spec the window was parsed from
+func (w Window) String() string {
+	return w.spec
+}
+
+// Calendar is the set of maintenance windows of an exchange, empty when it has none
+type Calendar []Window
+
+// Parse parses the window specs of a calendar, see ParseWindow
+func Parse(specs []string) (Calendar, error) {
+	calendar := make(Calendar, 0, len(specs))
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		w, err := ParseWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		calendar = append(calendar, w)
+	}
+	return calendar, nil
+}
+
+// Active returns the window t falls within, false outside of all windows
+func (c Calendar) Active(t time.Time) (Window, bool) {
+	for _, w := range c {
+		if w.Contains(t) {
+			return w, true
+		}
+	}
+	return Window{}, false
+}
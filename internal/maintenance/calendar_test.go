@@ -0,0 +1,82 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{spec: "* 02:00-02:30"},
+		{spec: "Tue 06:00-08:00"},
+		{spec: "mon-fri 23:30-00:15"},
+		{spec: "Sat,Mon-Tue 01:00-02:00"},
+		{spec: "Tue", wantErr: `window "Tue": expected <days> <HH:MM>-<HH:MM>`},
+		{spec: "Tues 06:00-08:00", wantErr: `window "Tues 06:00-08:00": unknown day "Tues"`},
+		{spec: "Tue 06:00", wantErr: `window "Tue 06:00": expected a <HH:MM>-<HH:MM> time range, got "06:00"`},
+		{spec: "Tue 06:00-25:00", wantErr: `window "Tue 06:00-25:00": invalid time "25:00", expected HH:MM`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			w, err := ParseWindow(tt.spec)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.spec, w.String())
+		})
+	}
+}
+
+func TestWindow_Contains(t *testing.T) {
+	// 2025-01-07 is a Tuesday
+	at := func(day int, clock string) time.Time {
+		c, err := time.Parse("15:04", clock)
+		require.NoError(t, err)
+		return time.Date(2025, 1, day, c.Hour(), c.Minute(), 0, 0, time.UTC)
+	}
+
+	weekly, err := ParseWindow("Tue 06:00-08:00")
+	require.NoError(t, err)
+	assert.True(t, weekly.Contains(at(7, "06:00")))
+	assert.True(t, weekly.Contains(at(7, "07:59")))
+	assert.False(t, weekly.Contains(at(7, "08:00")))
+	assert.False(t, weekly.Contains(at(8, "07:00")), "another day")
+	assert.True(t, weekly.Contains(time.Date(2025, 1, 7, 9, 0, 0, 0, time.FixedZone("UTC+2", 2*3600))), "compared in UTC")
+
+	overnight, err := ParseWindow("Fri-Sat 23:00-01:00")
+	require.NoError(t, err)
+	assert.True(t, overnight.Contains(at(10, "23:30")), "friday")
+	assert.True(t, overnight.Contains(at(11, "00:30")), "saturday morning, started friday")
+	assert.True(t, overnight.Contains(at(12, "00:30")), "sunday morning, started saturday")
+	assert.False(t, overnight.Contains(at(10, "00:30")), "friday morning, started thursday")
+	assert.False(t, overnight.Contains(at(11, "01:00")))
+
+	assert.Equal(t, at(7, "08:00"), weekly.End(at(7, "06:30")))
+	assert.Equal(t, at(11, "01:00"), overnight.End(at(10, "23:30")))
+	assert.Equal(t, at(11, "01:00"), overnight.End(at(11, "00:30")))
+}
+
+func TestCalendar_Active(t *testing.T) {
+	calendar, err := Parse([]string{"Tue 06:00-08:00", " ", "* 02:00-02:10"})
+	require.NoError(t, err)
+	require.Len(t, calendar, 2)
+
+	w, ok := calendar.Active(time.Date(2025, 1, 9, 2, 5, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "* 02:00-02:10", w.String())
+
+	_, ok = calendar.Active(time.Date(2025, 1, 9, 3, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	_, err = Parse([]string{"Tue 06:00-08:00", "never"})
+	assert.EqualError(t, err, `window "never": expected <days> <HH:MM>-<HH:MM>`)
+}
@@ -0,0 +1,41 @@
+// Package metrics enumerates every metric and span emitted by the importer,
+// so dashboards and monitors can be generated programmatically
+package metrics
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/ayankousky/exchange-data-importer/internal/composite"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/importer"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/repository/instrument"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+	"github.com/ayankousky/exchange-data-importer/internal/outage"
+	"github.com/ayankousky/exchange-data-importer/internal/supervisor"
+)
+
+// Catalog returns all emitted metrics sorted by name
+func Catalog() []telemetry.Metric {
+	catalog := slices.Concat(
+		importer.Metrics(),
+		instrument.Metrics(),
+		composite.Metrics(),
+		eventbus.Metrics(),
+		notifier.Metrics(),
+		notify.Metrics(),
+		opsalert.Metrics(),
+		outage.Metrics(),
+		supervisor.Metrics(),
+	)
+	slices.SortFunc(catalog, func(a, b telemetry.Metric) int { return strings.Compare(a.Name, b.Name) })
+	return catalog
+}
+
+// CommonTags returns the tags added to every metric and span
+func CommonTags() []string {
+	return slices.Clone(telemetry.CommonTags)
+}
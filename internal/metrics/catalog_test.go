@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalog(t *testing.T) {
+	catalog := Catalog()
+	assert.NotEmpty(t, catalog)
+
+	seen := make(map[string]bool, len(catalog))
+	for _, metric := range catalog {
+		assert.NotEmpty(t, metric.Name)
+		assert.NotEmpty(t, metric.Description, metric.Name)
+		assert.Contains(t, []telemetry.MetricKind{telemetry.KindCounter, telemetry.KindGauge, telemetry.KindTiming, telemetry.KindSpan}, metric.Kind, metric.Name)
+		assert.False(t, seen[metric.Name], "duplicate metric %s", metric.Name)
+		seen[metric.Name] = true
+
+		for _, tag := range metric.Tags {
+			assert.NotContains(t, CommonTags(), tag, "%s repeats a common tag", metric.Name)
+		}
+	}
+}
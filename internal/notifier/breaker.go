@@ -0,0 +1,127 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+)
+
+// CircuitBreakerConfig configures the circuit breaker of every subscribed client
+type CircuitBreakerConfig struct {
+	Threshold int           // consecutive failed sends opening the circuit, 0 disables the breaker
+	Cooldown  time.Duration // time an open circuit skips the client before a probe event is sent
+}
+
+// CircuitState is the state of the circuit breaker of a client
+type CircuitState string
+
+const (
+	// CircuitClosed delivers events to the client
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen skips the client without sending until the cooldown elapsed
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen sends a single probe event, the circuit closes when it's delivered and opens again otherwise
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// Circuit is a snapshot of the circuit breaker of a client
+type Circuit struct {
+	Client   string       `json:"client"`
+	State    CircuitState `json:"state"`
+	Failures int          `json:"failures"` // consecutive failed sends
+	Since    time.Time    `json:"since"`    // time of the last state change, zero while the circuit never opened
+}
+
+// breaker is the circuit breaker of a client shared by all its subscriptions.
+// A client failing Threshold times in a row is skipped for Cooldown, so a down backend fails fast
+// instead of adding its send timeout to every notification
+type breaker struct {
+	client string
+	cfg    CircuitBreakerConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	since    time.Time
+}
+
+func newBreaker(client notify.Client, cfg CircuitBreakerConfig) *breaker {
+	return &breaker{
+		client: clientName(client),
+		cfg:    cfg,
+		now:    time.Now,
+		state:  CircuitClosed,
+	}
+}
+
+// clientName returns the name of the client type, e.g. notify.RedisNotifier
+func clientName(client notify.Client) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", client), "*")
+}
+
+// allow reports whether an event may be sent. Once the cooldown of an open circuit elapsed it lets a single
+// probe through and rejects the other events until the probe is recorded
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.since) < b.cfg.Cooldown {
+			return false
+		}
+		b.state, b.since = CircuitHalfOpen, b.now()
+		return true
+	case CircuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// record registers the result of an allowed send and returns the new state, changed is set on a transition.
+// An event queued for retry (notify.ErrQueuedForRetry) is neither a success nor a failure: the client retries it
+// on its own, so it leaves the failures as they are. A probe queued for retry tells nothing about the client either,
+// the circuit opens again and sends another probe after the cooldown
+func (b *breaker) record(err error) (state CircuitState, changed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	previous := b.state
+	if errors.Is(err, notify.ErrQueuedForRetry) {
+		if b.state == CircuitHalfOpen {
+			b.state, b.since = CircuitOpen, b.now()
+		}
+		return b.state, b.state != previous
+	}
+	if err == nil {
+		b.failures = 0
+		if b.state != CircuitClosed {
+			b.state, b.since = CircuitClosed, b.now()
+		}
+		return b.state, b.state != previous
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.cfg.Threshold {
+		b.state = CircuitOpen
+		if previous != CircuitOpen {
+			b.since = b.now()
+		}
+	}
+	return b.state, b.state != previous
+}
+
+// circuit returns the snapshot of the breaker
+func (b *breaker) circuit() Circuit {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return Circuit{Client: b.client, State: b.state, Failures: b.failures, Since: b.since}
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	notifyMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry/telemetrytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newBreaker(&notifyMocks.ClientMock{}, CircuitBreakerConfig{Threshold: 2, Cooldown: time.Minute})
+	b.now = func() time.Time { return now }
+	errDown := errors.New("down")
+
+	assert.True(t, b.allow())
+	_, changed := b.record(errDown)
+	assert.False(t, changed, "a single failure keeps the circuit closed")
+	b.record(nil)
+	b.record(errDown)
+	state, changed := b.record(errDown)
+	assert.Equal(t, CircuitOpen, state, "consecutive failures open the circuit")
+	assert.True(t, changed)
+	assert.Equal(t, Circuit{Client: "mocks.ClientMock", State: CircuitOpen, Failures: 2, Since: now}, b.circuit())
+
+	assert.False(t, b.allow(), "an open circuit fails fast")
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow(), "a probe is let through after the cooldown")
+	assert.False(t, b.allow(), "only a single probe is in flight")
+	state, _ = b.record(errDown)
+	assert.Equal(t, CircuitOpen, state, "a failed probe opens the circuit again")
+	assert.False(t, b.allow())
+
+	now = now.Add(time.Minute)
+	require.True(t, b.allow())
+	state, changed = b.record(nil)
+	assert.Equal(t, CircuitClosed, state, "a delivered probe closes the circuit")
+	assert.True(t, changed)
+	assert.True(t, b.allow())
+	assert.Zero(t, b.circuit().Failures)
+}
+
+func TestBreaker_QueuedForRetry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newBreaker(&notifyMocks.ClientMock{}, CircuitBreakerConfig{Threshold: 2, Cooldown: time.Minute})
+	b.now = func() time.Time { return now }
+	errDown, errQueued := errors.New("down"), fmt.Errorf("%w: timeout", notify.ErrQueuedForRetry)
+
+	b.record(errDown)
+	state, changed := b.record(errQueued)
+	assert.Equal(t, CircuitClosed, state, "a queued event isn't a failure")
+	assert.False(t, changed)
+	assert.Equal(t, 1, b.circuit().Failures, "nor a success resetting the failures")
+
+	b.record(errDown)
+	now = now.Add(time.Minute)
+	require.True(t, b.allow())
+	state, changed = b.record(errQueued)
+	assert.Equal(t, CircuitOpen, state, "a queued probe opens the circuit again")
+	assert.True(t, changed)
+	assert.Equal(t, 2, b.circuit().Failures)
+	assert.False(t, b.allow(), "the next probe waits for the cooldown")
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow())
+}
+
+func TestNotifier_CircuitBreakerSkipsFailingClient(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	client := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			if failing.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	}
+	strategy := &notifyMocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			return []notify.Event{{EventType: string(MarketDataTopic)}}
+		},
+	}
+
+	tel := &telemetrytest.Counting{}
+	n := New(zap.NewNop()).WithTelemetry(tel).WithCircuitBreaker(CircuitBreakerConfig{Threshold: 2, Cooldown: 50 * time.Millisecond})
+	n.Subscribe(string(MarketDataTopic), client, strategy)
+
+	n.Notify(context.Background(), &domain.Tick{})
+	n.Notify(context.Background(), &domain.Tick{})
+	assert.Equal(t, CircuitOpen, n.Circuits()[0].State)
+
+	n.Notify(context.Background(), &domain.Tick{})
+	assert.Len(t, client.SendCalls(), 2, "an open circuit skips the client")
+	assert.Equal(t, int64(1), tel.Counter(telemetryNotifierCircuitRejected))
+
+	failing.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	n.Notify(context.Background(), &domain.Tick{})
+	assert.Len(t, client.SendCalls(), 3, "a probe is sent after the cooldown")
+	assert.Equal(t, CircuitClosed, n.Circuits()[0].State, "the delivered probe closed the circuit")
+
+	n.Notify(context.Background(), &domain.Tick{})
+	assert.Len(t, client.SendCalls(), 4)
+}
+
+func TestNotifier_CircuitBreakerSharedByClient(t *testing.T) {
+	client := &notifyMocks.ClientMock{}
+	n := New(zap.NewNop()).WithCircuitBreaker(CircuitBreakerConfig{Threshold: 1, Cooldown: time.Minute})
+	n.Subscribe(string(MarketDataTopic), client, &notifyMocks.StrategyMock{})
+	n.Subscribe(string(TickInfoTopic), client, &notifyMocks.StrategyMock{})
+	n.Subscribe(string(TickInfoTopic), &notifyMocks.ClientMock{}, &notifyMocks.StrategyMock{})
+
+	assert.Len(t, n.Circuits(), 2)
+	assert.Same(t, n.handlers[MarketDataTopic][0].breaker, n.handlers[TickInfoTopic][0].breaker)
+}
+
+func TestNotifier_CircuitBreakerDisabled(t *testing.T) {
+	n := New(zap.NewNop()).WithCircuitBreaker(CircuitBreakerConfig{})
+	n.Subscribe(string(MarketDataTopic), &notifyMocks.ClientMock{}, &notifyMocks.StrategyMock{})
+	assert.Empty(t, n.Circuits())
+	assert.Nil(t, n.handlers[MarketDataTopic][0].breaker)
+}
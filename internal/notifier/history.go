@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils"
+)
+
+// history keeps the latest data points the notifier was given per data type, for the notify.HistoryStrategy
+// subscriptions. It keeps nothing until a strategy needs a history
+type history struct {
+	mu     sync.Mutex
+	size   int
+	byType map[reflect.Type]*utils.Ring[any]
+}
+
+func newHistory() *history {
+	return &history{byType: make(map[reflect.Type]*utils.Ring[any])}
+}
+
+// require makes the history keep at least size data points of every type
+func (h *history) require(size int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.size = max(h.size, size)
+}
+
+// previous returns the kept data points of the type of data, oldest first
+func (h *history) previous(data any) []any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ring, ok := h.byType[reflect.TypeOf(data)]
+	if !ok {
+		return nil
+	}
+	return ring.Values()
+}
+
+// add keeps data, dropping the oldest data point of its type once size are kept
+func (h *history) add(data any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.size == 0 {
+		return
+	}
+	key := reflect.TypeOf(data)
+	ring, ok := h.byType[key]
+	if !ok || ring.Cap() < h.size {
+		grown := utils.NewRing[any](h.size)
+		if ok {
+			for _, value := range ring.Values() {
+				grown.Push(value)
+			}
+		}
+		ring = grown
+		h.byType[key] = ring
+	}
+	ring.Push(data)
+}
+
+// format formats data with the strategy, handing a notify.HistoryStrategy the previous data points it consults
+func format(strategy notify.Strategy, data any, previous []any) []notify.Event {
+	hs, ok := strategy.(notify.HistoryStrategy)
+	if !ok {
+		return strategy.Format(data)
+	}
+	size := max(hs.HistorySize(), 0)
+	if len(previous) > size {
+		previous = previous[len(previous)-size:]
+	}
+	return hs.FormatWithHistory(data, previous)
+}
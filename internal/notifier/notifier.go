@@ -2,114 +2,378 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
 	"go.uber.org/zap"
 )
 
-// Topic represents a notification topic
-type Topic string
+// DefaultSendTimeout is the maximum time a single client is given to deliver one event
+const DefaultSendTimeout = 5 * time.Second
 
-// Validate checks if the topic exists
-func (t Topic) Validate() error {
-	switch t {
-	case MarketDataTopic, AlertTopic, TickInfoTopic:
-		return nil
-	default:
-		return fmt.Errorf("invalid topic: '%s'", t)
-	}
-}
-
-const (
-	// MarketDataTopic is the event type for ticker data
-	MarketDataTopic Topic = "MARKET_DATA"
-
-	// AlertTopic is the event triggered when something significant happens in the market
-	AlertTopic Topic = "ALERT_MARKET_STATE"
-
-	// TickInfoTopic is the event triggered to send common information about the tick
-	TickInfoTopic Topic = "TICK_INFO"
-)
+// errClientPanic is returned by send when the client panicked while delivering an event
+var errClientPanic = errors.New("client panic")
 
 // Notifier is the service responsible for handling notifications
 type Notifier struct {
-	handlers map[Topic][]handler
-	logger   *zap.Logger
+	handlers    map[Topic][]handler
+	sendTimeout time.Duration
+	breakerCfg  CircuitBreakerConfig
+	breakers    map[notify.Client]*breaker
+	history     *history
+	telemetry   telemetry.Provider
+	logger      *zap.Logger
 }
 
 type handler struct {
 	client   notify.Client
 	strategy notify.Strategy
+	breaker  *breaker // nil when the circuit breaker is disabled
+	shadow   string   // name of a shadow subscription, empty for subscriptions delivering to their client
 }
 
 // New creates a new Notifier
 func New(logger *zap.Logger) *Notifier {
 	return &Notifier{
-		handlers: make(map[Topic][]handler),
-		logger:   logger.With(zap.String("component", "notifier")),
+		handlers:    make(map[Topic][]handler),
+		sendTimeout: DefaultSendTimeout,
+		breakers:    make(map[notify.Client]*breaker),
+		history:     newHistory(),
+		telemetry:   &telemetry.NoopProvider{},
+		logger:      logger.With(zap.String("component", "notifier")),
 	}
 }
 
-// Subscribe subscribes client to a topic with a given strategy
-func (s *Notifier) Subscribe(topicString string, client notify.Client, strategy notify.Strategy) {
-	if client == nil {
-		s.logger.Error("Cannot subscribe with nil client",
-			zap.String("topic", topicString),
-		)
-		return
+// WithTelemetry sets the telemetry provider used to report delivery problems
+func (s *Notifier) WithTelemetry(provider telemetry.Provider) *Notifier {
+	if provider != nil {
+		s.telemetry = provider
 	}
+	return s
+}
+
+// WithSendTimeout overrides the per-client Send timeout
+func (s *Notifier) WithSendTimeout(timeout time.Duration) *Notifier {
+	if timeout > 0 {
+		s.sendTimeout = timeout
+	}
+	return s
+}
 
+// WithCircuitBreaker skips a client for cfg.Cooldown after cfg.Threshold consecutive failed sends.
+// It applies to the clients subscribed afterwards
+func (s *Notifier) WithCircuitBreaker(cfg CircuitBreakerConfig) *Notifier {
+	if cfg.Threshold > 0 {
+		s.breakerCfg = cfg
+	}
+	return s
+}
+
+// Circuits returns the state of the circuit breaker of every subscribed client, sorted by client
+func (s *Notifier) Circuits() []Circuit {
+	circuits := make([]Circuit, 0, len(s.breakers))
+	for _, b := range s.breakers {
+		circuits = append(circuits, b.circuit())
+	}
+	slices.SortFunc(circuits, func(a, b Circuit) int { return strings.Compare(a.Client, b.Client) })
+	return circuits
+}
+
+// Subscribe subscribes client to a topic with a given strategy, it fails on an unknown topic
+// instead of subscribing a client that would never receive an event
+func (s *Notifier) Subscribe(topicString string, client notify.Client, strategy notify.Strategy) error {
+	if client == nil {
+		return fmt.Errorf("cannot subscribe to %s with nil client", topicString)
+	}
 	if strategy == nil {
-		s.logger.Error("Cannot subscribe with nil strategy",
-			zap.String("topic", topicString),
-		)
-		return
+		return fmt.Errorf("cannot subscribe to %s with nil strategy", topicString)
 	}
 
 	topic := Topic(topicString)
 	if err := topic.Validate(); err != nil {
-		s.logger.Error("Cannot subscribe to invalid topic",
-			zap.String("topic", topicString),
-			zap.Error(err),
-		)
-		return
+		return err
 	}
 
 	s.handlers[topic] = append(s.handlers[topic], handler{
 		client:   client,
 		strategy: strategy,
+		breaker:  s.breaker(client),
 	})
+	s.requireHistory(strategy)
+	return nil
 }
 
-// Notify sends a notification to all subscribers of the topic
+// SubscribeShadow subscribes a strategy to a topic in shadow mode: its events are formatted, logged and
+// counted under name but never delivered, so a new strategy or threshold can be evaluated against live data
+func (s *Notifier) SubscribeShadow(topicString string, name string, strategy notify.Strategy) error {
+	if name == "" {
+		return fmt.Errorf("cannot subscribe to %s in shadow mode without a name", topicString)
+	}
+	if strategy == nil {
+		return fmt.Errorf("cannot subscribe to %s with nil strategy", topicString)
+	}
+
+	topic := Topic(topicString)
+	if err := topic.Validate(); err != nil {
+		return err
+	}
+
+	s.handlers[topic] = append(s.handlers[topic], handler{
+		strategy: strategy,
+		shadow:   name,
+	})
+	s.requireHistory(strategy)
+	return nil
+}
+
+// requireHistory makes the notifier keep the previous data points a notify.HistoryStrategy consults
+func (s *Notifier) requireHistory(strategy notify.Strategy) {
+	if hs, ok := strategy.(notify.HistoryStrategy); ok {
+		s.history.require(hs.HistorySize())
+	}
+}
+
+// breaker returns the circuit breaker shared by the subscriptions of the client, nil when disabled
+func (s *Notifier) breaker(client notify.Client) *breaker {
+	if s.breakerCfg.Threshold <= 0 {
+		return nil
+	}
+	b, ok := s.breakers[client]
+	if !ok {
+		b = newBreaker(client, s.breakerCfg)
+		s.breakers[client] = b
+	}
+	return b
+}
+
+// Notify sends a notification to all subscribers of the topic.
+// Every subscription is delivered in its own goroutine, so a slow or panicking client
+// cannot delay or break delivery to the others. MARKET_DATA is delivered before the other topics,
+// so consumers receive the market data of a tick before its alerts. Notify returns once all deliveries finished.
+// Strategies implementing notify.HistoryStrategy are also given the previous data points of the type of data
 func (s *Notifier) Notify(ctx context.Context, data any) {
 	if data == nil {
 		s.logger.Warn("Received nil data for notification")
 		return
 	}
 
-	s.notify(ctx, MarketDataTopic, data)
-	s.notify(ctx, TickInfoTopic, data)
-	s.notify(ctx, AlertTopic, data)
+	previous := s.history.previous(data)
+	wg := sync.WaitGroup{}
+	s.notify(ctx, &wg, MarketDataTopic, data, previous)
+	wg.Wait()
+
+	for _, topic := range Topics() {
+		if topic != MarketDataTopic {
+			s.notify(ctx, &wg, topic, data, previous)
+		}
+	}
+	wg.Wait()
+	s.history.add(data)
 }
 
-func (s *Notifier) notify(ctx context.Context, topic Topic, data any) {
+func (s *Notifier) notify(ctx context.Context, wg *sync.WaitGroup, topic Topic, data any, previous []any) {
 	handlers, exists := s.handlers[topic]
 	if !exists {
 		return
 	}
 
-	for _, h := range handlers {
-		events := h.strategy.Format(data)
-		for _, event := range events {
-			if err := h.client.Send(ctx, event); err != nil {
-				s.logger.Error("Failed to send notification",
-					zap.String("topic", string(topic)),
-					zap.Error(err),
+	// the events formatted by every handler, compared once all handlers formatted the data
+	counts := make([]int, len(handlers))
+	var formatted sync.WaitGroup
+	for i, h := range handlers {
+		wg.Add(1)
+		formatted.Add(1)
+		go func() {
+			defer wg.Done()
+			defer formatted.Done()
+			counts[i] = s.deliver(ctx, topic, h, data, previous)
+		}()
+	}
+
+	if slices.ContainsFunc(handlers, func(h handler) bool { return h.shadow != "" }) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			formatted.Wait()
+			s.compareShadows(topic, handlers, counts)
+		}()
+	}
+}
+
+// compareShadows warns about the shadow subscriptions of a topic whose number of events differs from that of
+// every live subscription of the topic, e.g. a shadow alert fired without any live one. The events of each
+// subscription are counted, not compared, as their formats may differ
+func (s *Notifier) compareShadows(topic Topic, handlers []handler, counts []int) {
+	var live []int
+	for i, h := range handlers {
+		if h.shadow == "" {
+			live = append(live, counts[i])
+		}
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	for i, h := range handlers {
+		if h.shadow == "" || slices.Contains(live, counts[i]) {
+			continue
+		}
+		s.telemetry.IncrementCounter(telemetryNotifierShadowMismatches, 1, fmt.Sprintf("topic:%s", topic), fmt.Sprintf("shadow:%s", h.shadow))
+		s.logger.Warn("Shadow notification mismatch",
+			zap.String("topic", string(topic)),
+			zap.String("shadow", h.shadow),
+			zap.Int("shadow_events", counts[i]),
+			zap.Ints("live_events", live),
+		)
+	}
+}
+
+// deliver formats the data with the handler strategy and sends every event to the handler client,
+// it returns the number of events formatted
+func (s *Notifier) deliver(ctx context.Context, topic Topic, h handler, data any, previous []any) (formatted int) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.telemetry.IncrementCounter(telemetryNotifierPanics, 1, fmt.Sprintf("topic:%s", topic))
+			s.logger.Error("Notification handler panic",
+				zap.String("topic", string(topic)),
+				zap.Any("panic", r),
+				zap.ByteString("stack", debug.Stack()),
+			)
+		}
+	}()
+
+	events := format(h.strategy, data, previous)
+	formatted = len(events)
+	if h.shadow != "" {
+		s.shadow(topic, h.shadow, events)
+		return formatted
+	}
+	if batcher, ok := h.client.(notify.BatchClient); ok && len(events) > 1 {
+		s.deliverBatch(ctx, topic, h, batcher, events)
+		return formatted
+	}
+	for _, event := range events {
+		if h.breaker != nil && !h.breaker.allow() {
+			s.telemetry.IncrementCounter(telemetryNotifierCircuitRejected, 1, fmt.Sprintf("topic:%s", topic), fmt.Sprintf("client:%s", h.breaker.client))
+			continue
+		}
+		err := s.send(ctx, event.EventType, func(ctx context.Context) error { return h.client.Send(ctx, event) })
+		if h.breaker != nil && ctx.Err() == nil {
+			s.recordSend(h.breaker, err)
+		}
+		if err != nil {
+			s.reportSendError(topic, err)
+		}
+	}
+	return formatted
+}
+
+// deliverBatch sends the events to a batch client in a single send, counted as one by the circuit breaker
+func (s *Notifier) deliverBatch(ctx context.Context, topic Topic, h handler, client notify.BatchClient, events []notify.Event) {
+	if h.breaker != nil && !h.breaker.allow() {
+		s.telemetry.IncrementCounter(telemetryNotifierCircuitRejected, int64(len(events)), fmt.Sprintf("topic:%s", topic), fmt.Sprintf("client:%s", h.breaker.client))
+		return
+	}
+	err := s.send(ctx, events[0].EventType, func(ctx context.Context) error { return client.SendBatch(ctx, events) })
+	if h.breaker != nil && ctx.Err() == nil {
+		s.recordSend(h.breaker, err)
+	}
+	if err != nil {
+		s.reportSendError(topic, err)
+	}
+}
+
+// reportSendError logs a failed send and counts the timeouts and panics
+func (s *Notifier) reportSendError(topic Topic, err error) {
+	if errors.Is(err, notify.ErrQueuedForRetry) {
+		s.logger.Warn("Notification queued for retry",
+			zap.String("topic", string(topic)),
+			zap.Error(err),
+		)
+		return
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		s.telemetry.IncrementCounter(telemetryNotifierDeadlineExceeded, 1, fmt.Sprintf("topic:%s", topic))
+	case errors.Is(err, errClientPanic):
+		s.telemetry.IncrementCounter(telemetryNotifierPanics, 1, fmt.Sprintf("topic:%s", topic))
+	}
+	s.logger.Error("Failed to send notification",
+		zap.String("topic", string(topic)),
+		zap.Error(err),
+	)
+}
+
+// shadow logs and counts the events of a shadow subscription instead of sending them, logged at debug level
+// as a shadow of MARKET_DATA formats every tick
+func (s *Notifier) shadow(topic Topic, name string, events []notify.Event) {
+	for _, event := range events {
+		s.telemetry.IncrementCounter(telemetryNotifierShadowEvents, 1, fmt.Sprintf("topic:%s", topic), fmt.Sprintf("shadow:%s", name))
+		s.logger.Debug("Shadow notification",
+			zap.String("topic", string(topic)),
+			zap.String("shadow", name),
+			zap.String("event_type", event.EventType),
+			zap.Any("data", event.Data),
+		)
+	}
+}
+
+// recordSend registers the result of a send with the circuit breaker and reports its transitions
+func (s *Notifier) recordSend(b *breaker, err error) {
+	state, changed := b.record(err)
+	if !changed {
+		return
+	}
+
+	open := 0.0
+	if state == CircuitOpen {
+		open = 1
+	}
+	s.telemetry.Gauge(telemetryNotifierCircuitOpen, open, fmt.Sprintf("client:%s", b.client))
+	switch state {
+	case CircuitOpen:
+		s.logger.Warn("Notification client circuit opened",
+			zap.String("client", b.client),
+			zap.Duration("cooldown", b.cfg.Cooldown),
+			zap.Error(err),
+		)
+	case CircuitClosed:
+		s.logger.Info("Notification client circuit closed", zap.String("client", b.client))
+	}
+}
+
+// send delivers a single event, or a batch of them, with its own timeout.
+// Clients that ignore the context are abandoned once the timeout expires, so they can't hold up Notify.
+func (s *Notifier) send(ctx context.Context, eventType string, deliver func(ctx context.Context) error) error {
+	sendCtx, cancel := context.WithTimeout(ctx, s.sendTimeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				s.logger.Error("Notification client panic",
+					zap.String("event_type", eventType),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
 				)
-				continue
+				result <- fmt.Errorf("%w: %v", errClientPanic, r)
 			}
-		}
+		}()
+		result <- deliver(sendCtx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-sendCtx.Done():
+		return fmt.Errorf("send aborted after %s: %w", s.sendTimeout, sendCtx.Err())
 	}
 }
@@ -2,11 +2,14 @@ package notifier
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
 	notifyMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry/telemetrytest"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
@@ -18,6 +21,7 @@ func TestNotifier_Subscribe(t *testing.T) {
 		client   notify.Client
 		strategy notify.Strategy
 		wantLen  int
+		wantErr  bool
 	}{
 		{
 			name:     "subscribe to invalid topic",
@@ -25,6 +29,7 @@ func TestNotifier_Subscribe(t *testing.T) {
 			client:   &notifyMocks.ClientMock{},
 			strategy: &notifyMocks.StrategyMock{},
 			wantLen:  0,
+			wantErr:  true,
 		},
 		{
 			name:     "subscribe with nil client",
@@ -32,6 +37,7 @@ func TestNotifier_Subscribe(t *testing.T) {
 			client:   nil,
 			strategy: &notifyMocks.StrategyMock{},
 			wantLen:  0,
+			wantErr:  true,
 		},
 		{
 			name:     "subscribe with nil strategy",
@@ -39,6 +45,7 @@ func TestNotifier_Subscribe(t *testing.T) {
 			client:   &notifyMocks.ClientMock{},
 			strategy: nil,
 			wantLen:  0,
+			wantErr:  true,
 		},
 		{
 			name:     "subscribe to valid topic",
@@ -70,7 +77,8 @@ func TestNotifier_Subscribe(t *testing.T) {
 		n.Subscribe(string(AlertTopic), &notifyMocks.ClientMock{}, &notifyMocks.StrategyMock{})
 
 		t.Run(tt.name, func(t *testing.T) {
-			n.Subscribe(tt.topic, tt.client, tt.strategy)
+			err := n.Subscribe(tt.topic, tt.client, tt.strategy)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
 			assert.Len(t, n.handlers[Topic(tt.topic)], tt.wantLen)
 		})
 	}
@@ -240,3 +248,212 @@ func TestTopic_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifier_NotifyIsolatesClients(t *testing.T) {
+	event := notify.Event{EventType: string(MarketDataTopic), Data: "test data"}
+	strategy := &notifyMocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			return []notify.Event{event}
+		},
+	}
+
+	tel := &telemetrytest.Counting{}
+	n := New(zap.NewNop()).WithTelemetry(tel).WithSendTimeout(50 * time.Millisecond)
+
+	slowClient := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	stuckClient := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			time.Sleep(time.Second) // ignores the context
+			return nil
+		},
+	}
+	panicClient := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			panic("boom")
+		},
+	}
+	healthyClient := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			return nil
+		},
+	}
+
+	n.Subscribe(string(MarketDataTopic), slowClient, strategy)
+	n.Subscribe(string(MarketDataTopic), stuckClient, strategy)
+	n.Subscribe(string(MarketDataTopic), panicClient, strategy)
+	n.Subscribe(string(MarketDataTopic), healthyClient, strategy)
+
+	start := time.Now()
+	assert.NotPanics(t, func() {
+		n.Notify(context.Background(), &domain.Tick{})
+	})
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "slow clients should not delay Notify beyond the send timeout")
+	assert.Len(t, healthyClient.SendCalls(), 1)
+	assert.Equal(t, int64(2), tel.Counter(telemetryNotifierDeadlineExceeded))
+	assert.Equal(t, int64(1), tel.Counter(telemetryNotifierPanics))
+}
+
+func TestNotifier_NotifyRecoversStrategyPanic(t *testing.T) {
+	tel := &telemetrytest.Counting{}
+	n := New(zap.NewNop()).WithTelemetry(tel)
+
+	client := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			return nil
+		},
+	}
+	n.Subscribe(string(AlertTopic), client, &notifyMocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			panic("bad strategy")
+		},
+	})
+	n.Subscribe(string(TickInfoTopic), client, &notifyMocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			return []notify.Event{{EventType: string(TickInfoTopic)}}
+		},
+	})
+
+	assert.NotPanics(t, func() {
+		n.Notify(context.Background(), &domain.Tick{})
+	})
+	assert.Len(t, client.SendCalls(), 1)
+	assert.Equal(t, int64(1), tel.Counter(telemetryNotifierPanics))
+}
+
+func TestNotifier_SubscribeShadow(t *testing.T) {
+	tel := &telemetrytest.Counting{}
+	n := New(zap.NewNop()).WithTelemetry(tel)
+
+	strategy := &notifyMocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			return []notify.Event{{EventType: string(AlertTopic)}, {EventType: string(AlertTopic)}}
+		},
+	}
+	client := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			return nil
+		},
+	}
+
+	assert.Error(t, n.SubscribeShadow("INVALID_TOPIC", "alerts-v2", strategy))
+	assert.Error(t, n.SubscribeShadow(string(AlertTopic), "", strategy))
+	assert.Error(t, n.SubscribeShadow(string(AlertTopic), "alerts-v2", nil))
+	assert.NoError(t, n.SubscribeShadow(string(AlertTopic), "alerts-v2", strategy))
+	assert.NoError(t, n.Subscribe(string(AlertTopic), client, strategy))
+
+	n.Notify(context.Background(), &domain.Tick{})
+
+	assert.Len(t, strategy.FormatCalls(), 2, "shadow subscriptions format the data")
+	assert.Len(t, client.SendCalls(), 2, "only the live subscription sends its events")
+	assert.Equal(t, int64(2), tel.Counter(telemetryNotifierShadowEvents))
+	assert.Zero(t, tel.Counter(telemetryNotifierShadowMismatches), "the shadow formatted as many events as the live subscription")
+
+	// a shadow with stricter thresholds formats no event when the live subscription alerts
+	silent := &notifyMocks.StrategyMock{FormatFunc: func(data any) []notify.Event { return nil }}
+	assert.NoError(t, n.SubscribeShadow(string(AlertTopic), "alerts-v3", silent))
+	n.Notify(context.Background(), &domain.Tick{})
+
+	assert.Equal(t, int64(1), tel.Counter(telemetryNotifierShadowMismatches))
+}
+
+func TestNotifier_NotifyDeliversMarketDataFirst(t *testing.T) {
+	n := New(zap.NewNop())
+
+	var mu sync.Mutex
+	var sent []string
+	client := func(delay time.Duration) *notifyMocks.ClientMock {
+		return &notifyMocks.ClientMock{
+			SendFunc: func(ctx context.Context, event notify.Event) error {
+				time.Sleep(delay)
+				mu.Lock()
+				defer mu.Unlock()
+				sent = append(sent, event.EventType)
+				return nil
+			},
+		}
+	}
+	strategy := func(topic Topic) *notifyMocks.StrategyMock {
+		return &notifyMocks.StrategyMock{
+			FormatFunc: func(data any) []notify.Event {
+				return []notify.Event{{EventType: string(topic)}}
+			},
+		}
+	}
+
+	assert.NoError(t, n.Subscribe(string(AlertTopic), client(0), strategy(AlertTopic)))
+	assert.NoError(t, n.Subscribe(string(MarketDataTopic), client(20*time.Millisecond), strategy(MarketDataTopic)))
+
+	n.Notify(context.Background(), &domain.Tick{})
+	assert.Equal(t, []string{string(MarketDataTopic), string(AlertTopic)}, sent)
+}
+
+// batchClient records the batches it receives
+type batchClient struct {
+	notifyMocks.ClientMock
+	batches [][]notify.Event
+}
+
+func (c *batchClient) SendBatch(_ context.Context, events []notify.Event) error {
+	c.batches = append(c.batches, events)
+	return nil
+}
+
+func TestNotifier_NotifySendsBatches(t *testing.T) {
+	n := New(zap.NewNop())
+	strategy := &notifyMocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			return []notify.Event{{EventType: "BTCUSDT"}, {EventType: "ETHUSDT"}, {EventType: "SOLUSDT"}}
+		},
+	}
+	client := &batchClient{}
+
+	assert.NoError(t, n.Subscribe(string(MarketDataTopic), client, strategy))
+	n.Notify(context.Background(), &domain.Tick{})
+
+	assert.Len(t, client.SendCalls(), 0, "the events of a batch client are not sent one by one")
+	if assert.Len(t, client.batches, 1) {
+		assert.Len(t, client.batches[0], 3)
+	}
+}
+
+// historyStrategy records the history it is given with every tick
+type historyStrategy struct {
+	size      int
+	histories [][]any
+}
+
+func (s *historyStrategy) Format(data any) []notify.Event { return s.FormatWithHistory(data, nil) }
+
+func (s *historyStrategy) HistorySize() int { return s.size }
+
+func (s *historyStrategy) FormatWithHistory(data any, history []any) []notify.Event {
+	if _, ok := data.(*domain.Tick); ok {
+		s.histories = append(s.histories, history)
+	}
+	return nil
+}
+
+func TestNotifier_NotifyGivesHistory(t *testing.T) {
+	n := New(zap.NewNop())
+	short, long := &historyStrategy{size: 1}, &historyStrategy{size: 2}
+	plain := &notifyMocks.StrategyMock{FormatFunc: func(data any) []notify.Event { return nil }}
+	assert.NoError(t, n.Subscribe(string(AlertTopic), &notifyMocks.ClientMock{}, short))
+	assert.NoError(t, n.SubscribeShadow(string(AlertTopic), "long", long))
+	assert.NoError(t, n.Subscribe(string(AlertTopic), &notifyMocks.ClientMock{}, plain))
+
+	ticks := []*domain.Tick{{LL5: 1}, {LL5: 2}, {LL5: 3}}
+	n.Notify(context.Background(), ticks[0])
+	n.Notify(context.Background(), []domain.Bar{{}})
+	n.Notify(context.Background(), ticks[1])
+	n.Notify(context.Background(), ticks[2])
+
+	assert.Equal(t, [][]any{nil, {ticks[0]}, {ticks[1]}}, short.histories, "the history is cut to the size of the strategy")
+	assert.Equal(t, [][]any{nil, {ticks[0]}, {ticks[0], ticks[1]}}, long.histories, "data of other types are kept apart")
+	assert.Len(t, plain.FormatCalls(), 4)
+}
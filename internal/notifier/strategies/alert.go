@@ -1,26 +1,90 @@
 package strategies
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"math"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
 	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
 	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
 )
 
 // AlertStrategy creates important information if the tick has abnormal values
 type AlertStrategy struct {
 	thresholds AlertStrategyThresholds
+	fairPrices FairPriceSource
+	record     func(domain.Alert)
 }
 
-// AlertStrategyThresholds defines thresholds for generating market alerts
+// FairPriceSource provides cross-exchange reference prices of exchange symbols, in their quote asset
+type FairPriceSource interface {
+	FairPrice(symbol domain.TickerName) (float64, bool)
+}
+
+// AlertStrategyThresholds defines thresholds for generating market alerts, a threshold of 0 disables its check
 type AlertStrategyThresholds struct {
 	AvgPrice1mChange    float64 // price change in 1 minute for the entire market
 	AvgPrice20mChange   float64 // price change in 20 minutes for the entire market
 	TickerPrice1mChange float64 // price change in 1 minute for a single ticker
+	FairPriceDeviation  float64 // deviation of a single ticker from its fair price, 0 disables the check
+
+	// CategoryPrice1mChange is the price change in 1 minute of the average of a symbol category, 0 disables the check
+	CategoryPrice1mChange float64
+
+	// TickerPrice1mChangeBySymbol overrides TickerPrice1mChange for the symbols matching a pattern, the first match wins;
+	// an override of 0 mutes the symbols
+	TickerPrice1mChangeBySymbol []SymbolThreshold
+
+	// Persistence is the number of consecutive ticks the thresholds must be crossed in before alerting, 0 or 1 alerts
+	// on the first one
+	Persistence int
+}
+
+// SymbolThreshold is a threshold applying to the symbols matching Pattern, a path.Match pattern such as BTC*
+type SymbolThreshold struct {
+	Pattern   string
+	Threshold float64
+}
+
+// NewSymbolThresholds orders thresholds keyed by pattern from the most specific one: exact symbols first,
+// then the patterns with the longest literal prefix, so BTCUSDT overrides BTC* which overrides *USDT
+func NewSymbolThresholds(byPattern map[string]float64) []SymbolThreshold {
+	thresholds := make([]SymbolThreshold, 0, len(byPattern))
+	for pattern, threshold := range byPattern {
+		thresholds = append(thresholds, SymbolThreshold{Pattern: pattern, Threshold: threshold})
+	}
+	literal := func(pattern string) int {
+		if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+			return i
+		}
+		return math.MaxInt
+	}
+	slices.SortFunc(thresholds, func(a, b SymbolThreshold) int {
+		return cmp.Or(
+			cmp.Compare(literal(b.Pattern), literal(a.Pattern)),
+			cmp.Compare(len(b.Pattern), len(a.Pattern)),
+			strings.Compare(a.Pattern, b.Pattern),
+		)
+	})
+	return thresholds
+}
+
+// tickerPrice1mChange returns the 1 minute price change threshold of the symbol
+func (t AlertStrategyThresholds) tickerPrice1mChange(symbol domain.TickerName) float64 {
+	for _, override := range t.TickerPrice1mChangeBySymbol {
+		if matched, _ := path.Match(override.Pattern, string(symbol)); matched {
+			return override.Threshold
+		}
+	}
+	return t.TickerPrice1mChange
 }
 
 // NewAlertStrategy creates a new AlertStrategy
@@ -28,31 +92,122 @@ func NewAlertStrategy(thresholds AlertStrategyThresholds) *AlertStrategy {
 	return &AlertStrategy{thresholds: thresholds}
 }
 
-// Format formats the tick data into a human-readable format
+// WithFairPrices sets the source of the fair prices tickers are compared to
+func (s *AlertStrategy) WithFairPrices(source FairPriceSource) *AlertStrategy {
+	s.fairPrices = source
+	return s
+}
+
+// WithRecorder sets the function every formatted alert is handed to, with the tick key and thresholds it fired with.
+// record is called synchronously from Format and must not block
+func (s *AlertStrategy) WithRecorder(record func(domain.Alert)) *AlertStrategy {
+	s.record = record
+	return s
+}
+
+// HistorySize returns the number of previous ticks the persistence of the alerts is checked on
+func (s *AlertStrategy) HistorySize() int {
+	return max(s.thresholds.Persistence-1, 0)
+}
+
+// Format formats the tick data into a human-readable format. Without the previous ticks,
+// alerts requiring a persistence over several ticks never fire, see FormatWithHistory
 func (s *AlertStrategy) Format(data any) []notify.Event {
+	return s.FormatWithHistory(data, nil)
+}
+
+// FormatWithHistory formats the tick data into a human-readable format if the thresholds were also crossed in
+// the previous ticks of the persistence, history holds the ticks formatted before, oldest first
+func (s *AlertStrategy) FormatWithHistory(data any, history []any) []notify.Event {
 	tick, ok := data.(*domain.Tick)
 	if !ok {
 		return nil
 	}
 
-	if tick == nil {
+	// the 20-minute changes of a warming up importer are incomplete, its alerts would be noise
+	if tick == nil || tick.WarmingUp {
 		return nil
 	}
 
-	message, hasAlerts := formatTickAlert(tick, s.thresholds)
-	if !hasAlerts {
+	message, symbols, hasAlerts := formatTickAlert(tick, s.thresholds, s.fairPrices)
+	if !hasAlerts || !s.persisted(history) {
 		return nil
 	}
 
+	now := time.Now()
+	if s.record != nil {
+		s.record(domain.Alert{
+			TickKey:    tick.StorageKey(),
+			Thresholds: s.thresholds.domain(),
+			Symbols:    symbols,
+			Message:    message,
+			CreatedAt:  now,
+		})
+	}
+
 	return []notify.Event{{
-		Time:      time.Now(),
+		Time:      now,
 		EventType: string(notifier.AlertTopic),
 		Data:      message,
 	}}
 }
 
+// persisted reports whether the thresholds were crossed in every previous tick the persistence requires
+func (s *AlertStrategy) persisted(history []any) bool {
+	required := s.HistorySize()
+	if len(history) < required {
+		return false
+	}
+	for _, data := range history[len(history)-required:] {
+		tick, ok := data.(*domain.Tick)
+		if !ok || tick == nil || tick.WarmingUp {
+			return false
+		}
+		if _, _, hasAlerts := formatTickAlert(tick, s.thresholds, s.fairPrices); !hasAlerts {
+			return false
+		}
+	}
+	return true
+}
+
+// domain returns the thresholds in the form they are stored with the fired alerts
+func (t AlertStrategyThresholds) domain() domain.AlertThresholds {
+	thresholds := domain.AlertThresholds{
+		AvgPrice1mChange:    t.AvgPrice1mChange,
+		AvgPrice20mChange:   t.AvgPrice20mChange,
+		TickerPrice1mChange: t.TickerPrice1mChange,
+		FairPriceDeviation:  t.FairPriceDeviation,
+
+		CategoryPrice1mChange: t.CategoryPrice1mChange,
+		Persistence:           t.Persistence,
+	}
+	if len(t.TickerPrice1mChangeBySymbol) > 0 {
+		thresholds.TickerPrice1mChangeBySymbol = make(map[string]float64, len(t.TickerPrice1mChangeBySymbol))
+		for _, override := range t.TickerPrice1mChangeBySymbol {
+			thresholds.TickerPrice1mChangeBySymbol[override.Pattern] = override.Threshold
+		}
+	}
+	return thresholds
+}
+
+// fairDeviation returns the fair price of the ticker and the % deviation of its mid price from it
+func fairDeviation(ticker *domain.Ticker, fairPrices FairPriceSource) (float64, float64, bool) {
+	if fairPrices == nil {
+		return 0, 0, false
+	}
+	fair, ok := fairPrices.FairPrice(ticker.Symbol)
+	if !ok || fair <= 0 {
+		return 0, 0, false
+	}
+	mid := (ticker.Ask + ticker.Bid) / 2
+	if ticker.QuoteRate > 0 {
+		mid /= ticker.QuoteRate
+	}
+	return fair, mathutils.PercDiff(mid, fair, 2), true
+}
+
 // formatTickerAlert formats a single ticker's data into a readable message
-func formatTickerAlert(ticker *domain.Ticker) string {
+func formatTickerAlert(ticker *domain.Ticker, fairPrices FairPriceSource) string {
 	parts := []string{
 		fmt.Sprintf("<b>%s</b>", string(ticker.Symbol)),
 		fmt.Sprintf("%.2f/%.2f", ticker.Ask, ticker.Bid),
@@ -75,20 +230,28 @@ func formatTickerAlert(ticker *domain.Ticker) string {
 	if ticker.RSI20 != 0 {
 		parts = append(parts, fmt.Sprintf("RSI: %.1f", ticker.RSI20))
 	}
+	if fair, deviation, ok := fairDeviation(ticker, fairPrices); ok {
+		parts = append(parts, fmt.Sprintf("fair: %.2f (%+.2f%%)", fair, deviation))
+	}
 
 	return strings.Join(parts, " | ")
 }
 
-// formatTickAlert formats a market tick into a readable message
-func formatTickAlert(tick *domain.Tick, thresholds AlertStrategyThresholds) (string, bool) {
+// crossed reports whether a change reached its threshold, a disabled (0) threshold is never crossed
+func crossed(change, threshold float64) bool {
+	return threshold > 0 && math.Abs(change) >= threshold
+}
+
+// formatTickAlert formats a market tick into a readable message, it also returns the tickers past their threshold
+func formatTickAlert(tick *domain.Tick, thresholds AlertStrategyThresholds, fairPrices FairPriceSource) (string, []domain.TickerName, bool) {
 	if tick == nil {
-		return "", false
+		return "", nil, false
 	}
 
 	var lines []string
 	hasAlert := false
 
-	if math.Abs(tick.Avg.Change1m) >= thresholds.AvgPrice1mChange {
+	if crossed(tick.Avg.Change1m, thresholds.AvgPrice1mChange) {
 		hasAlert = true
 		sign := ""
 		if tick.Avg.Change1m > 0 {
@@ -96,7 +259,7 @@ func formatTickAlert(tick *domain.Tick, thresholds AlertStrategyThresholds) (str
 		}
 		lines = append(lines, fmt.Sprintf("⚠️ <b>Significant Market Move</b>\nPrice Change 1m: %s%.2f%%", sign, tick.Avg.Change1m))
 	}
-	if math.Abs(tick.Avg.Change20m) >= thresholds.AvgPrice20mChange {
+	if crossed(tick.Avg.Change20m, thresholds.AvgPrice20mChange) {
 		hasAlert = true
 		sign := ""
 		if tick.Avg.Change20m > 0 {
@@ -105,10 +268,42 @@ func formatTickAlert(tick *domain.Tick, thresholds AlertStrategyThresholds) (str
 		lines = append(lines, fmt.Sprintf("Price Change 20m: %s%.2f%%", sign, tick.Avg.Change20m))
 	}
 
+	if thresholds.CategoryPrice1mChange > 0 {
+		var categoryMoves []string
+		for _, category := range slices.Sorted(maps.Keys(tick.CategoryAvg)) {
+			avg := tick.CategoryAvg[category]
+			if crossed(avg.Change1m, thresholds.CategoryPrice1mChange) {
+				categoryMoves = append(categoryMoves, fmt.Sprintf("<b>%s</b> (%d pairs) | 1m: %+.2f%% | 20m: %+.2f%%", category, avg.TickersCount, avg.Change1m, avg.Change20m))
+			}
+		}
+		if len(categoryMoves) > 0 {
+			hasAlert = true
+			lines = append(lines, strings.Join(append([]string{"🏷️ <b>Category Moves:</b>"}, categoryMoves...), "\n"))
+		}
+	}
+
+	// iterate in symbol order so the message is stable between ticks
+	symbols := make([]domain.TickerName, 0, len(tick.Data))
+	for symbol := range tick.Data {
+		symbols = append(symbols, symbol)
+	}
+	sort.Slice(symbols, func(i, j int) bool { return symbols[i] < symbols[j] })
+
 	var significantTickers []string
-	for _, ticker := range tick.Data {
-		if math.Abs(ticker.Change1m) >= thresholds.TickerPrice1mChange {
-			significantTickers = append(significantTickers, formatTickerAlert(ticker))
+	var significantSymbols []domain.TickerName
+	for _, symbol := range symbols {
+		ticker := tick.Data[symbol]
+		if ticker.Illiquid {
+			continue
+		}
+		moved := crossed(ticker.Change1m, thresholds.tickerPrice1mChange(symbol))
+		if !moved && thresholds.FairPriceDeviation > 0 {
+			_, deviation, ok := fairDeviation(ticker, fairPrices)
+			moved = ok && crossed(deviation, thresholds.FairPriceDeviation)
+		}
+		if moved {
+			significantTickers = append(significantTickers, formatTickerAlert(ticker, fairPrices))
+			significantSymbols = append(significantSymbols, symbol)
 			hasAlert = true
 		}
 	}
@@ -161,8 +356,8 @@ func formatTickAlert(tick *domain.Tick, thresholds AlertStrategyThresholds) (str
 	}
 
 	if !hasAlert {
-		return "", false
+		return "", nil, false
 	}
 
-	return strings.Join(lines, "\n\n"), true
+	return strings.Join(lines, "\n\n"), significantSymbols, true
 }
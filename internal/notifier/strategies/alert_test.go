@@ -2,10 +2,12 @@ package strategies
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
 	"github.com/ayankousky/exchange-data-importer/internal/notifier"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAlertStrategy_Format(t *testing.T) {
@@ -31,6 +33,22 @@ func TestAlertStrategy_Format(t *testing.T) {
 			},
 			wantEvents: true,
 		},
+		{
+			name: "should not generate alert while warming up",
+			thresholds: AlertStrategyThresholds{
+				AvgPrice1mChange:    1.0,
+				AvgPrice20mChange:   1000,
+				TickerPrice1mChange: 1000,
+			},
+			input: &domain.Tick{
+				WarmingUp: true,
+				Avg: domain.TickAvg{
+					Change1m:     1.5,
+					TickersCount: 10,
+				},
+			},
+			wantEvents: false,
+		},
 		{
 			name: "should not generate alert for normal market",
 			thresholds: AlertStrategyThresholds{
@@ -45,6 +63,31 @@ func TestAlertStrategy_Format(t *testing.T) {
 			},
 			wantEvents: false,
 		},
+		{
+			name:       "should not generate alert with disabled thresholds",
+			thresholds: AlertStrategyThresholds{},
+			input: &domain.Tick{
+				Avg: domain.TickAvg{Change1m: 0.5, Change20m: -1},
+				Data: map[domain.TickerName]*domain.Ticker{
+					"BTCUSDT": {Symbol: "BTCUSDT", Change1m: 0.1},
+				},
+			},
+			wantEvents: false,
+		},
+		{
+			name: "should skip illiquid tickers",
+			thresholds: AlertStrategyThresholds{
+				AvgPrice1mChange:    1000,
+				AvgPrice20mChange:   1000,
+				TickerPrice1mChange: 5,
+			},
+			input: &domain.Tick{
+				Data: map[domain.TickerName]*domain.Ticker{
+					"DUSTUSDT": {Symbol: "DUSTUSDT", Change1m: 50, Illiquid: true},
+				},
+			},
+			wantEvents: false,
+		},
 		{
 			name: "should handle nil input",
 			thresholds: AlertStrategyThresholds{
@@ -72,3 +115,167 @@ func TestAlertStrategy_Format(t *testing.T) {
 		})
 	}
 }
+
+// fairPrices is a FairPriceSource serving fixed prices
+type fairPrices map[domain.TickerName]float64
+
+func (f fairPrices) FairPrice(symbol domain.TickerName) (float64, bool) {
+	price, ok := f[symbol]
+	return price, ok
+}
+
+func TestAlertStrategy_FormatFairPrice(t *testing.T) {
+	thresholds := AlertStrategyThresholds{
+		AvgPrice1mChange:    1000,
+		AvgPrice20mChange:   1000,
+		TickerPrice1mChange: 1000,
+		FairPriceDeviation:  1,
+	}
+	tick := &domain.Tick{
+		Data: map[domain.TickerName]*domain.Ticker{
+			"BTCUSDT": {Symbol: "BTCUSDT", Ask: 102.5, Bid: 101.5},
+			"ETHUSDT": {Symbol: "ETHUSDT", Ask: 10.1, Bid: 9.9},
+		},
+	}
+
+	t.Run("without fair prices", func(t *testing.T) {
+		assert.Empty(t, NewAlertStrategy(thresholds).Format(tick))
+	})
+
+	t.Run("ticker deviating from its fair price", func(t *testing.T) {
+		strategy := NewAlertStrategy(thresholds).WithFairPrices(fairPrices{"BTCUSDT": 100, "ETHUSDT": 10})
+
+		events := strategy.Format(tick)
+		assert.Len(t, events, 1)
+		message := events[0].Data.(string)
+		assert.Contains(t, message, "<b>BTCUSDT</b> | 102.50/101.50 | fair: 100.00 (+2.00%)")
+		assert.NotContains(t, message, "ETHUSDT", "within the allowed deviation")
+	})
+}
+
+func TestAlertStrategy_FormatSymbolThresholds(t *testing.T) {
+	thresholds := AlertStrategyThresholds{
+		AvgPrice1mChange:    1000,
+		AvgPrice20mChange:   1000,
+		TickerPrice1mChange: 5,
+		TickerPrice1mChangeBySymbol: NewSymbolThresholds(map[string]float64{
+			"BTC*":     1,
+			"BTCDOMUS": 20,
+			"*USDT":    10,
+		}),
+	}
+	tick := &domain.Tick{
+		Data: map[domain.TickerName]*domain.Ticker{
+			"BTCUSDT":  {Symbol: "BTCUSDT", Change1m: 1.2},
+			"BTCDOMUS": {Symbol: "BTCDOMUS", Change1m: 8},
+			"PEPEUSDT": {Symbol: "PEPEUSDT", Change1m: 8},
+			"WIFUSDC":  {Symbol: "WIFUSDC", Change1m: 6},
+		},
+	}
+
+	events := NewAlertStrategy(thresholds).Format(tick)
+	assert.Len(t, events, 1)
+	message := events[0].Data.(string)
+	assert.Contains(t, message, "<b>BTCUSDT</b>", "BTC* lowers the threshold")
+	assert.NotContains(t, message, "BTCDOMUS", "the exact symbol wins over BTC*")
+	assert.NotContains(t, message, "PEPEUSDT", "*USDT raises the threshold")
+	assert.Contains(t, message, "<b>WIFUSDC</b>", "symbols matching no pattern use the default")
+}
+
+func TestAlertStrategy_FormatCategories(t *testing.T) {
+	thresholds := AlertStrategyThresholds{
+		AvgPrice1mChange:    1000,
+		AvgPrice20mChange:   1000,
+		TickerPrice1mChange: 1000,
+	}
+	tick := &domain.Tick{
+		CategoryAvg: map[string]domain.TickAvg{
+			"DeFi": {Change1m: -3.1, Change20m: -4, TickersCount: 12},
+			"L1":   {Change1m: 0.5, Change20m: 1, TickersCount: 8},
+			"meme": {Change1m: 3, TickersCount: 20},
+		},
+		Data: map[domain.TickerName]*domain.Ticker{},
+	}
+
+	assert.Empty(t, NewAlertStrategy(thresholds).Format(tick), "category alerts are disabled")
+
+	thresholds.CategoryPrice1mChange = 3
+	events := NewAlertStrategy(thresholds).Format(tick)
+	assert.Len(t, events, 1)
+	message := events[0].Data.(string)
+	assert.Contains(t, message, "🏷️ <b>Category Moves:</b>\n<b>DeFi</b> (12 pairs) | 1m: -3.10% | 20m: -4.00%\n<b>meme</b> (20 pairs) | 1m: +3.00% | 20m: +0.00%")
+	assert.NotContains(t, message, "<b>L1</b>")
+}
+
+func TestAlertStrategy_FormatRecordsAlerts(t *testing.T) {
+	thresholds := AlertStrategyThresholds{
+		AvgPrice1mChange:            1000,
+		AvgPrice20mChange:           1000,
+		TickerPrice1mChange:         5,
+		TickerPrice1mChangeBySymbol: NewSymbolThresholds(map[string]float64{"BTC*": 1}),
+	}
+	startAt := time.Date(2025, 1, 1, 12, 0, 0, 300_000_000, time.UTC)
+
+	var recorded []domain.Alert
+	strategy := NewAlertStrategy(thresholds).WithRecorder(func(alert domain.Alert) {
+		recorded = append(recorded, alert)
+	})
+
+	assert.Empty(t, strategy.Format(&domain.Tick{StartAt: startAt, Data: map[domain.TickerName]*domain.Ticker{
+		"ETHUSDT": {Symbol: "ETHUSDT", Change1m: 2},
+	}}))
+	assert.Empty(t, recorded, "nothing fired")
+
+	events := strategy.Format(&domain.Tick{StartAt: startAt, Data: map[domain.TickerName]*domain.Ticker{
+		"BTCUSDT": {Symbol: "BTCUSDT", Change1m: 1.2},
+		"ETHUSDT": {Symbol: "ETHUSDT", Change1m: 6},
+		"SOLUSDT": {Symbol: "SOLUSDT", Change1m: 2},
+	}})
+	require.Len(t, events, 1)
+	require.Len(t, recorded, 1)
+
+	alert := recorded[0]
+	assert.Equal(t, startAt.Truncate(time.Second), alert.TickKey)
+	assert.Equal(t, []domain.TickerName{"BTCUSDT", "ETHUSDT"}, alert.Symbols)
+	assert.Equal(t, events[0].Data, alert.Message)
+	assert.Equal(t, events[0].Time, alert.CreatedAt)
+	assert.Equal(t, domain.AlertThresholds{
+		AvgPrice1mChange:            1000,
+		AvgPrice20mChange:           1000,
+		TickerPrice1mChange:         5,
+		TickerPrice1mChangeBySymbol: map[string]float64{"BTC*": 1},
+	}, alert.Thresholds)
+}
+
+func TestAlertStrategy_FormatPersistence(t *testing.T) {
+	strategy := NewAlertStrategy(AlertStrategyThresholds{
+		AvgPrice1mChange:    1000,
+		AvgPrice20mChange:   1000,
+		TickerPrice1mChange: 5,
+		Persistence:         3,
+	})
+	moved := &domain.Tick{Data: map[domain.TickerName]*domain.Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Change1m: 6}}}
+	calm := &domain.Tick{Data: map[domain.TickerName]*domain.Ticker{"BTCUSDT": {Symbol: "BTCUSDT", Change1m: 1}}}
+	warmingUp := &domain.Tick{WarmingUp: true, Data: moved.Data}
+	assert.Equal(t, 2, strategy.HistorySize())
+
+	assert.Empty(t, strategy.Format(moved), "the previous ticks are unknown")
+	assert.Empty(t, strategy.FormatWithHistory(moved, []any{moved}), "crossed in 2 ticks only")
+	assert.Empty(t, strategy.FormatWithHistory(moved, []any{calm, moved}))
+	assert.Empty(t, strategy.FormatWithHistory(moved, []any{warmingUp, moved}))
+	assert.Empty(t, strategy.FormatWithHistory(calm, []any{moved, moved}))
+	assert.Len(t, strategy.FormatWithHistory(moved, []any{moved, moved}), 1)
+	assert.Len(t, strategy.FormatWithHistory(moved, []any{calm, moved, moved}), 1, "only the latest ticks count")
+
+	assert.Zero(t, NewAlertStrategy(AlertStrategyThresholds{}).HistorySize())
+}
+
+func TestNewSymbolThresholds(t *testing.T) {
+	thresholds := NewSymbolThresholds(map[string]float64{"*USDT": 10, "BTC*": 1, "BTCUSDT": 0.5, "BTCUSD?": 2, "ETH*": 1.5})
+
+	var patterns []string
+	for _, threshold := range thresholds {
+		patterns = append(patterns, threshold.Pattern)
+	}
+	assert.Equal(t, []string{"BTCUSDT", "BTCUSD?", "BTC*", "ETH*", "*USDT"}, patterns)
+}
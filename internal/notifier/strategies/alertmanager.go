@@ -0,0 +1,85 @@
+package strategies
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+)
+
+// alertmanagerWebhookVersion is the version of the Alertmanager webhook payload
+const alertmanagerWebhookVersion = "4"
+
+// AlertmanagerMessage is the Alertmanager webhook payload
+type AlertmanagerMessage struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+// AlertmanagerAlert is a single alert of an Alertmanager webhook payload
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertmanagerStrategy formats operational alerts as Alertmanager webhook payloads,
+// so receivers built for Alertmanager can ingest them without a custom translator
+type AlertmanagerStrategy struct {
+	receiver    string
+	externalURL string
+}
+
+// NewAlertmanagerStrategy creates a new AlertmanagerStrategy; receiver and externalURL are copied into every payload
+func NewAlertmanagerStrategy(receiver, externalURL string) *AlertmanagerStrategy {
+	return &AlertmanagerStrategy{receiver: receiver, externalURL: externalURL}
+}
+
+// Format formats an operational alert into a single webhook payload grouped by alertname
+func (s *AlertmanagerStrategy) Format(data any) []notify.Event {
+	alert, ok := data.(opsalert.Alert)
+	if !ok {
+		return nil
+	}
+
+	name := alert.Name()
+	message := AlertmanagerMessage{
+		Version:           alertmanagerWebhookVersion,
+		GroupKey:          fmt.Sprintf("{}:{%s=%q}", opsalert.LabelAlertName, name),
+		Status:            string(alert.Status),
+		Receiver:          s.receiver,
+		GroupLabels:       map[string]string{opsalert.LabelAlertName: name},
+		CommonLabels:      alert.Labels,
+		CommonAnnotations: alert.Annotations,
+		ExternalURL:       s.externalURL,
+		Alerts: []AlertmanagerAlert{{
+			Status:       string(alert.Status),
+			Labels:       alert.Labels,
+			Annotations:  alert.Annotations,
+			StartsAt:     alert.StartsAt,
+			EndsAt:       alert.EndsAt,
+			GeneratorURL: s.externalURL,
+			Fingerprint:  alert.Fingerprint(),
+		}},
+	}
+
+	return []notify.Event{{
+		Time:      alert.StartsAt,
+		EventType: string(notifier.OpsAlertTopic),
+		Data:      message,
+	}}
+}
@@ -0,0 +1,62 @@
+package strategies
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerStrategy_Format(t *testing.T) {
+	startsAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	alert := opsalert.Alert{
+		Status: opsalert.StatusResolved,
+		Labels: map[string]string{
+			opsalert.LabelAlertName: opsalert.NameTickGap,
+			opsalert.LabelSeverity:  opsalert.SeverityCritical,
+			"service":               "importer",
+		},
+		Annotations: map[string]string{
+			opsalert.AnnotationSummary:     "No ticks are being imported",
+			opsalert.AnnotationDescription: "No tick was built for 12s (threshold 10s)",
+		},
+		StartsAt: startsAt,
+		EndsAt:   startsAt.Add(time.Minute),
+	}
+
+	events := NewAlertmanagerStrategy("importer", "http://importer:8080").Format(alert)
+	require.Len(t, events, 1)
+	assert.Equal(t, string(notifier.OpsAlertTopic), events[0].EventType)
+
+	payload, err := json.Marshal(events[0].Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": "4",
+		"groupKey": "{}:{alertname=\"ImporterTickGap\"}",
+		"truncatedAlerts": 0,
+		"status": "resolved",
+		"receiver": "importer",
+		"groupLabels": {"alertname": "ImporterTickGap"},
+		"commonLabels": {"alertname": "ImporterTickGap", "severity": "critical", "service": "importer"},
+		"commonAnnotations": {"summary": "No ticks are being imported", "description": "No tick was built for 12s (threshold 10s)"},
+		"externalURL": "http://importer:8080",
+		"alerts": [{
+			"status": "resolved",
+			"labels": {"alertname": "ImporterTickGap", "severity": "critical", "service": "importer"},
+			"annotations": {"summary": "No ticks are being imported", "description": "No tick was built for 12s (threshold 10s)"},
+			"startsAt": "2025-01-01T12:00:00Z",
+			"endsAt": "2025-01-01T12:01:00Z",
+			"generatorURL": "http://importer:8080",
+			"fingerprint": "`+alert.Fingerprint()+`"
+		}]
+	}`, string(payload))
+}
+
+func TestAlertmanagerStrategy_IgnoresTicks(t *testing.T) {
+	assert.Empty(t, NewAlertmanagerStrategy("", "").Format(&domain.Tick{}))
+}
@@ -0,0 +1,29 @@
+package strategies
+
+import (
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+)
+
+// BarStrategy sends every finalized minute bar as a separate event
+type BarStrategy struct{}
+
+// Format formats the bars closed by a tick into one event per bar, stamped with the start of its minute
+func (s *BarStrategy) Format(data any) []notify.Event {
+	bars, ok := data.([]domain.Bar)
+	if !ok {
+		return nil
+	}
+
+	events := make([]notify.Event, 0, len(bars))
+	for _, bar := range bars {
+		events = append(events, notify.Event{
+			Time:      bar.StartAt,
+			EventType: string(notifier.BarsTopic),
+			Data:      bar,
+		})
+	}
+
+	return events
+}
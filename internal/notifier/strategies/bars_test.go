@@ -0,0 +1,35 @@
+package strategies
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarStrategy_Format(t *testing.T) {
+	startAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	bars := []domain.Bar{
+		{Symbol: "BTCUSDT", StartAt: startAt, Open: 100, High: 102, Low: 99, Close: 101, MaxSpread: 0.02, LiqNotional: 1500.5, Samples: 60},
+		{Symbol: "ETHUSDT", StartAt: startAt, Open: 10, High: 10, Low: 10, Close: 10, Samples: 1},
+	}
+
+	events := (&BarStrategy{}).Format(bars)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, string(notifier.BarsTopic), event.EventType)
+		assert.Equal(t, startAt, event.Time)
+	}
+
+	payload, err := json.Marshal(events[0].Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"s":"BTCUSDT","st":"2025-01-01T12:00:00Z","o":100,"h":102,"l":99,"c":101,"spr":0.02,"liq":1500.5,"n":60}`, string(payload))
+}
+
+func TestBarStrategy_IgnoresTicks(t *testing.T) {
+	assert.Empty(t, (&BarStrategy{}).Format(&domain.Tick{}))
+}
@@ -0,0 +1,71 @@
+package strategies
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/ayankousky/exchange-data-importer/internal/notifier/strategytest"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite strategy golden files instead of comparing against them")
+
+func TestStrategies_Golden(t *testing.T) {
+	tests := []struct {
+		name   string
+		golden string
+		run    func() []strategytest.TickEvent
+	}{
+		{
+			name:   "tick info",
+			golden: "testdata/tick_info.golden",
+			run: func() []strategytest.TickEvent {
+				ticks := strategytest.GenerateTicks(strategytest.GenerateConfig{Count: 12, Seed: 1})
+				return strategytest.Run(NewTickInfoStrategy(), ticks)
+			},
+		},
+		{
+			name:   "alert",
+			golden: "testdata/alert.golden",
+			run: func() []strategytest.TickEvent {
+				ticks := strategytest.GenerateTicks(strategytest.GenerateConfig{Count: 66, Seed: 7, Volatility: 1})
+				return strategytest.Run(NewAlertStrategy(AlertStrategyThresholds{
+					AvgPrice1mChange:    3,
+					AvgPrice20mChange:   1000,
+					TickerPrice1mChange: 8,
+				}), ticks)
+			},
+		},
+		{
+			name:   "market data",
+			golden: "testdata/market_data.golden",
+			run: func() []strategytest.TickEvent {
+				ticks := strategytest.GenerateTicks(strategytest.GenerateConfig{Count: 2, Seed: 3, Symbols: strategytest.DefaultSymbols[:2]})
+				return strategytest.Run(&MarketDataStrategy{}, ticks)
+			},
+		},
+		{
+			name:   "market data digest",
+			golden: "testdata/market_data_digest.golden",
+			run: func() []strategytest.TickEvent {
+				ticks := strategytest.GenerateTicks(strategytest.GenerateConfig{Count: 2, Seed: 3, Symbols: strategytest.DefaultSymbols[:2]})
+				return strategytest.Run(&MarketDataDigestStrategy{}, ticks)
+			},
+		},
+		{
+			name:   "market data from captured ticks",
+			golden: "testdata/market_data_captured.golden",
+			run: func() []strategytest.TickEvent {
+				ticks, err := strategytest.LoadTicks("testdata/ticks.json")
+				require.NoError(t, err)
+				return strategytest.Run(&MarketDataStrategy{}, ticks)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategytest.AssertGolden(t, tt.golden, tt.run(), *update)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package strategies
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+)
+
+// GrafanaAnnotation is the body of the Grafana annotations HTTP API (POST /api/annotations).
+// Without a dashboard it's an organization annotation, shown on the dashboards querying its tags
+type GrafanaAnnotation struct {
+	Time    int64    `json:"time"`              // start in epoch milliseconds
+	TimeEnd int64    `json:"timeEnd,omitempty"` // end in epoch milliseconds, set for a region
+	Tags    []string `json:"tags"`
+	Text    string   `json:"text"`
+
+	fingerprint string // of the alert, the annotation of a resolved alert updates the one of its start
+}
+
+// AnnotationKey returns the fingerprint of the alert, see notify.Annotation
+func (a GrafanaAnnotation) AnnotationKey() string {
+	return a.fingerprint
+}
+
+// AnnotationFinal reports whether the annotation is the region of a resolved alert, see notify.Annotation
+func (a GrafanaAnnotation) AnnotationFinal() bool {
+	return a.TimeEnd != 0
+}
+
+// GrafanaAnnotationStrategy formats operational alerts as Grafana annotations, so the events of the importer
+// appear on the existing dashboards: a firing alert is marked at its start and a resolved one as a region
+// covering how long it lasted. A notify.GrafanaNotifier turns the mark into the region instead of adding one
+type GrafanaAnnotationStrategy struct{}
+
+// Format formats an operational alert into a single annotation
+func (s *GrafanaAnnotationStrategy) Format(data any) []notify.Event {
+	alert, ok := data.(opsalert.Alert)
+	if !ok {
+		return nil
+	}
+
+	annotation := GrafanaAnnotation{
+		Time: alert.StartsAt.UnixMilli(),
+		Tags: annotationTags(alert),
+		Text: fmt.Sprintf("%s: %s", alert.Annotations[opsalert.AnnotationSummary], alert.Annotations[opsalert.AnnotationDescription]),
+
+		fingerprint: alert.Fingerprint(),
+	}
+	if alert.Status == opsalert.StatusResolved {
+		annotation.TimeEnd = alert.EndsAt.UnixMilli()
+		annotation.Text = "Resolved: " + alert.Annotations[opsalert.AnnotationSummary]
+	}
+
+	return []notify.Event{{
+		Time:      alert.StartsAt,
+		EventType: string(notifier.AnnotationTopic),
+		Data:      annotation,
+	}}
+}
+
+// annotationTags returns the labels of the alert as name:value tags in label order, followed by the status
+func annotationTags(alert opsalert.Alert) []string {
+	tags := make([]string, 0, len(alert.Labels)+2)
+	tags = append(tags, "importer")
+	for _, key := range slices.Sorted(maps.Keys(alert.Labels)) {
+		tags = append(tags, fmt.Sprintf("%s:%s", key, alert.Labels[key]))
+	}
+	return append(tags, fmt.Sprintf("status:%s", alert.Status))
+}
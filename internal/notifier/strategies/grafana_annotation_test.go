@@ -0,0 +1,84 @@
+package strategies
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/opsalert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrafanaAnnotationStrategy_Format(t *testing.T) {
+	startsAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	alert := opsalert.Alert{
+		Status: opsalert.StatusFiring,
+		Labels: map[string]string{
+			opsalert.LabelAlertName: opsalert.NameReconnectStorm,
+			opsalert.LabelSeverity:  opsalert.SeverityCritical,
+			"exchange":              "binance-perp",
+		},
+		Annotations: map[string]string{
+			opsalert.AnnotationSummary:     "Exchange websockets keep reconnecting",
+			opsalert.AnnotationDescription: "5 websocket failures within 5m0s (threshold 5)",
+		},
+		StartsAt: startsAt,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(a *opsalert.Alert)
+		want   string
+	}{
+		{
+			name: "firing alert marks its start",
+			want: `{
+				"time": 1735732800000,
+				"tags": ["importer", "alertname:ImporterWebsocketReconnectStorm", "exchange:binance-perp", "severity:critical", "status:firing"],
+				"text": "Exchange websockets keep reconnecting: 5 websocket failures within 5m0s (threshold 5)"
+			}`,
+		},
+		{
+			name: "resolved alert is a region",
+			modify: func(a *opsalert.Alert) {
+				a.Status = opsalert.StatusResolved
+				a.EndsAt = startsAt.Add(time.Minute)
+			},
+			want: `{
+				"time": 1735732800000,
+				"timeEnd": 1735732860000,
+				"tags": ["importer", "alertname:ImporterWebsocketReconnectStorm", "exchange:binance-perp", "severity:critical", "status:resolved"],
+				"text": "Resolved: Exchange websockets keep reconnecting"
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := alert
+			if tt.modify != nil {
+				tt.modify(&a)
+			}
+
+			events := (&GrafanaAnnotationStrategy{}).Format(a)
+			require.Len(t, events, 1)
+			assert.Equal(t, string(notifier.AnnotationTopic), events[0].EventType)
+			assert.Equal(t, startsAt, events[0].Time)
+
+			annotation, ok := events[0].Data.(GrafanaAnnotation)
+			require.True(t, ok)
+			assert.Equal(t, alert.Fingerprint(), annotation.AnnotationKey(), "the annotations of an alert share its fingerprint")
+			assert.Equal(t, a.Status == opsalert.StatusResolved, annotation.AnnotationFinal())
+
+			payload, err := json.Marshal(events[0].Data)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(payload))
+		})
+	}
+}
+
+func TestGrafanaAnnotationStrategy_IgnoresTicks(t *testing.T) {
+	assert.Empty(t, (&GrafanaAnnotationStrategy{}).Format(&domain.Tick{}))
+}
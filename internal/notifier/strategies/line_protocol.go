@@ -0,0 +1,154 @@
+package strategies
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+)
+
+const (
+	// lineProtocolTickMeasurement holds market averages and liquidation counts of a tick
+	lineProtocolTickMeasurement = "tick"
+
+	// lineProtocolTickerMeasurement holds per-symbol prices and indicators
+	lineProtocolTickerMeasurement = "ticker"
+)
+
+// LineProtocolStrategy formats every tick as a batch of InfluxDB line protocol points
+// (one "tick" point and one "ticker" point per symbol, millisecond precision),
+// so time-series databases can ingest the data without a custom bridge
+type LineProtocolStrategy struct {
+	tags string // pre-rendered ",key=value" tags added to every point
+}
+
+// NewLineProtocolStrategy creates a new LineProtocolStrategy adding the given tags (e.g. exchange) to every point.
+// Tags with an empty key or value are left out, line protocol rejects them
+func NewLineProtocolStrategy(tags map[string]string) *LineProtocolStrategy {
+	keys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if k != "" && v != "" {
+			keys = append(keys, k)
+		}
+	}
+	// Influx recommends sorted tags for write performance
+	slices.Sort(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteByte(',')
+		b.WriteString(escapeLineProtocol(k))
+		b.WriteByte('=')
+		b.WriteString(escapeLineProtocol(tags[k]))
+	}
+	return &LineProtocolStrategy{tags: b.String()}
+}
+
+// Format formats the tick data into a single event carrying all points as a string
+func (s *LineProtocolStrategy) Format(data any) []notify.Event {
+	tick, ok := data.(*domain.Tick)
+	if !ok || tick == nil {
+		return nil
+	}
+
+	timestamp := strconv.FormatInt(tick.StartAt.UnixMilli(), 10)
+	var b strings.Builder
+
+	writePoint(&b, lineProtocolTickMeasurement+s.tags, []lineField{
+		floatField("avg_change_1m", tick.Avg.Change1m),
+		floatField("avg_change_20m", tick.Avg.Change20m),
+		floatField("avg_max_10", tick.Avg.Max10),
+		floatField("avg_min_10", tick.Avg.Min10),
+		floatField("avg_ask_change", tick.Avg.AskChange),
+		floatField("avg_bid_change", tick.Avg.BidChange),
+		floatField("avg_buy_10", tick.AvgBuy10),
+		intField("tickers_count", int64(tick.Avg.TickersCount)),
+		intField("ll_1", tick.LL1),
+		intField("ll_2", tick.LL2),
+		intField("ll_5", tick.LL5),
+		intField("ll_60", tick.LL60),
+		intField("sl_1", tick.SL1),
+		intField("sl_2", tick.SL2),
+		intField("sl_10", tick.SL10),
+		intField("fetch_ms", tick.FetchDuration),
+		intField("handling_ms", tick.HandlingDuration),
+	}, timestamp)
+
+	symbols := make([]domain.TickerName, 0, len(tick.Data))
+	for symbol := range tick.Data {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+
+	for _, symbol := range symbols {
+		ticker := tick.Data[symbol]
+		series := lineProtocolTickerMeasurement + s.tags + ",symbol=" + escapeLineProtocol(string(symbol))
+		writePoint(&b, series, []lineField{
+			floatField("ask", ticker.Ask),
+			floatField("bid", ticker.Bid),
+			floatField("rsi_20", ticker.RSI20),
+			floatField("change_1m", ticker.Change1m),
+			floatField("change_20m", ticker.Change20m),
+			floatField("max_10", ticker.Max10),
+			floatField("min_10", ticker.Min10),
+			floatField("ask_change", ticker.AskChange),
+			floatField("bid_change", ticker.BidChange),
+		}, timestamp)
+	}
+
+	return []notify.Event{{
+		Time:      time.Now(),
+		EventType: string(notifier.TimeSeriesTopic),
+		Data:      b.String(),
+	}}
+}
+
+// lineField is a rendered field key and value, an empty value leaves the field out
+type lineField struct {
+	key   string
+	value string
+}
+
+// floatField renders a float field, NaN and infinite values are left out as line protocol can't represent them
+func floatField(key string, value float64) lineField {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return lineField{key: key}
+	}
+	return lineField{key: key, value: strconv.FormatFloat(value, 'f', -1, 64)}
+}
+
+func intField(key string, value int64) lineField {
+	return lineField{key: key, value: strconv.FormatInt(value, 10) + "i"}
+}
+
+// writePoint writes a point of the series (measurement and tags) with the fields left, a point without any is skipped
+func writePoint(b *strings.Builder, series string, fields []lineField, timestamp string) {
+	fields = slices.DeleteFunc(fields, func(f lineField) bool { return f.value == "" })
+	if len(fields) == 0 {
+		return
+	}
+	b.WriteString(series)
+	for i, f := range fields {
+		if i == 0 {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(f.key)
+		b.WriteByte('=')
+		b.WriteString(f.value)
+	}
+	b.WriteByte(' ')
+	b.WriteString(timestamp)
+	b.WriteByte('\n')
+}
+
+// escapeLineProtocol escapes commas, spaces and equal signs in tag keys and values
+func escapeLineProtocol(s string) string {
+	return strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`).Replace(s)
+}
@@ -0,0 +1,82 @@
+package strategies
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineProtocolStrategy_Format(t *testing.T) {
+	startAt := time.UnixMilli(1700000000123)
+	tick := &domain.Tick{
+		StartAt:  startAt,
+		LL1:      3,
+		SL10:     2,
+		AvgBuy10: 0.25,
+		Avg:      domain.TickAvg{Change1m: 1.5, TickersCount: 2},
+		Data: map[domain.TickerName]*domain.Ticker{
+			"ETHUSDT": {Symbol: "ETHUSDT", Ask: 2000.5, Bid: 2000, RSI20: 55.5},
+			"BTCUSDT": {Symbol: "BTCUSDT", Ask: 30000, Bid: 29999.5, Change1m: -0.1},
+		},
+	}
+
+	strategy := NewLineProtocolStrategy(map[string]string{"exchange": "binance", "env": "prod 1"})
+	events := strategy.Format(tick)
+	require.Len(t, events, 1)
+	assert.Equal(t, string(notifier.TimeSeriesTopic), events[0].EventType)
+
+	want := "tick,env=prod\\ 1,exchange=binance avg_change_1m=1.5,avg_change_20m=0,avg_max_10=0,avg_min_10=0," +
+		"avg_ask_change=0,avg_bid_change=0,avg_buy_10=0.25,tickers_count=2i,ll_1=3i,ll_2=0i,ll_5=0i,ll_60=0i," +
+		"sl_1=0i,sl_2=0i,sl_10=2i,fetch_ms=0i,handling_ms=0i 1700000000123\n" +
+		"ticker,env=prod\\ 1,exchange=binance,symbol=BTCUSDT ask=30000,bid=29999.5,rsi_20=0,change_1m=-0.1," +
+		"change_20m=0,max_10=0,min_10=0,ask_change=0,bid_change=0 1700000000123\n" +
+		"ticker,env=prod\\ 1,exchange=binance,symbol=ETHUSDT ask=2000.5,bid=2000,rsi_20=55.5,change_1m=0," +
+		"change_20m=0,max_10=0,min_10=0,ask_change=0,bid_change=0 1700000000123\n"
+	assert.Equal(t, want, events[0].Data)
+
+	assert.Empty(t, strategy.Format(nil))
+	assert.Empty(t, strategy.Format("not a tick"))
+}
+
+func TestLineProtocolStrategy_SkipsEmptyTags(t *testing.T) {
+	strategy := NewLineProtocolStrategy(map[string]string{"service": "", "": "x", "exchange": "binance"})
+	events := strategy.Format(&domain.Tick{StartAt: time.UnixMilli(1700000000123)})
+	require.Len(t, events, 1)
+
+	data := events[0].Data.(string)
+	assert.True(t, strings.HasPrefix(data, "tick,exchange=binance avg_change_1m=0,"), data)
+	assert.NotContains(t, data, "service=")
+}
+
+func TestLineProtocolStrategy_SkipsNonFiniteFields(t *testing.T) {
+	tick := &domain.Tick{
+		StartAt: time.UnixMilli(1700000000123),
+		Avg:     domain.TickAvg{Change1m: math.NaN(), Change20m: math.Inf(1)},
+		Data: map[domain.TickerName]*domain.Ticker{
+			"BTCUSDT": {Symbol: "BTCUSDT", Ask: 30000, Bid: 29999.5, RSI20: math.NaN(), Change1m: math.Inf(-1)},
+			"ETHUSDT": {
+				Symbol: "ETHUSDT", Ask: math.NaN(), Bid: math.NaN(), RSI20: math.NaN(), Change1m: math.NaN(),
+				Change20m: math.NaN(), Max10: math.NaN(), Min10: math.NaN(), AskChange: math.NaN(), BidChange: math.NaN(),
+			},
+		},
+	}
+
+	events := NewLineProtocolStrategy(nil).Format(tick)
+	require.Len(t, events, 1)
+
+	want := "tick avg_max_10=0,avg_min_10=0,avg_ask_change=0,avg_bid_change=0,avg_buy_10=0,tickers_count=0i," +
+		"ll_1=0i,ll_2=0i,ll_5=0i,ll_60=0i,sl_1=0i,sl_2=0i,sl_10=0i,fetch_ms=0i,handling_ms=0i 1700000000123\n" +
+		"ticker,symbol=BTCUSDT ask=30000,bid=29999.5,change_20m=0,max_10=0,min_10=0,ask_change=0,bid_change=0 " +
+		"1700000000123\n"
+	assert.Equal(t, want, events[0].Data, "non-finite fields and points left without fields are skipped")
+}
+
+func TestEscapeLineProtocol(t *testing.T) {
+	assert.Equal(t, `a\,b\ c\=d`, escapeLineProtocol("a,b c=d"))
+}
@@ -2,6 +2,7 @@ package strategies
 
 import (
 	"errors"
+	"slices"
 	"time"
 
 	"github.com/ayankousky/exchange-data-importer/internal/domain"
@@ -37,7 +38,9 @@ func newTickerNotification(tick *domain.Tick, symbol domain.TickerName) (*Ticker
 }
 
 // MarketDataStrategy sends updates about every ticker for every subscribed service
-type MarketDataStrategy struct{}
+type MarketDataStrategy struct {
+	Priority []domain.TickerName // symbols sent first, in this order
+}
 
 // Format formats the tick data into a human-readable format
 func (s *MarketDataStrategy) Format(data any) []notify.Event {
@@ -51,10 +54,10 @@ func (s *MarketDataStrategy) Format(data any) []notify.Event {
 	}
 
 	events := make([]notify.Event, 0, len(tick.Data))
-	for symbol := range tick.Data {
+	appendTicker := func(symbol domain.TickerName) {
 		notification, err := newTickerNotification(tick, symbol)
 		if err != nil {
-			continue
+			return
 		}
 
 		events = append(events, notify.Event{
@@ -64,5 +67,14 @@ func (s *MarketDataStrategy) Format(data any) []notify.Event {
 		})
 	}
 
+	for _, symbol := range s.Priority {
+		appendTicker(symbol)
+	}
+	for symbol := range tick.Data {
+		if !slices.Contains(s.Priority, symbol) {
+			appendTicker(symbol)
+		}
+	}
+
 	return events
 }
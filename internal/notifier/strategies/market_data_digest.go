@@ -0,0 +1,54 @@
+package strategies
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+)
+
+// MarketDataDigest carries all tickers of a tick in a single notification, the tick is sent once without its tickers
+type MarketDataDigest struct {
+	Tick    domain.Tick     `json:"tick"`
+	Tickers []domain.Ticker `json:"tickers"`
+}
+
+// MarketDataDigestStrategy sends a single event per tick with all its tickers, instead of an event per ticker
+// like MarketDataStrategy, so a client publishes once per tick rather than once per symbol
+type MarketDataDigestStrategy struct {
+	Priority []domain.TickerName // symbols listed first, in this order; the others follow in symbol order
+}
+
+// Format formats the tick into a single digest event
+func (s *MarketDataDigestStrategy) Format(data any) []notify.Event {
+	tick, ok := data.(*domain.Tick)
+	if !ok || tick == nil || len(tick.Data) == 0 {
+		return nil
+	}
+
+	digest := MarketDataDigest{Tick: *tick, Tickers: make([]domain.Ticker, 0, len(tick.Data))}
+	digest.Tick.Data = nil
+	for _, symbol := range s.Priority {
+		if ticker, exists := tick.Data[symbol]; exists {
+			digest.Tickers = append(digest.Tickers, *ticker)
+		}
+	}
+	others := make([]domain.TickerName, 0, len(tick.Data))
+	for symbol := range tick.Data {
+		if !slices.Contains(s.Priority, symbol) {
+			others = append(others, symbol)
+		}
+	}
+	slices.Sort(others)
+	for _, symbol := range others {
+		digest.Tickers = append(digest.Tickers, *tick.Data[symbol])
+	}
+
+	return []notify.Event{{
+		Time:      time.Now(),
+		EventType: string(notifier.MarketDataTopic),
+		Data:      digest,
+	}}
+}
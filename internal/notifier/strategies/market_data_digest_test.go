@@ -0,0 +1,44 @@
+package strategies
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketDataDigestStrategy_Format(t *testing.T) {
+	tick := &domain.Tick{StartAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC), Data: map[domain.TickerName]*domain.Ticker{}}
+	for _, symbol := range []domain.TickerName{"SOLUSDT", "ETHUSDT", "XRPUSDT", "BTCUSDT", "DOGEUSDT"} {
+		tick.Data[symbol] = &domain.Ticker{Symbol: symbol}
+	}
+	strategy := &MarketDataDigestStrategy{Priority: []domain.TickerName{"BTCUSDT", "ADAUSDT", "ETHUSDT"}}
+
+	events := strategy.Format(tick)
+	require.Len(t, events, 1)
+	assert.Equal(t, string(notifier.MarketDataTopic), events[0].EventType)
+
+	digest, ok := events[0].Data.(MarketDataDigest)
+	require.True(t, ok)
+	assert.Nil(t, digest.Tick.Data, "the tick is sent without its tickers")
+	assert.Equal(t, tick.StartAt, digest.Tick.StartAt)
+	assert.Len(t, tick.Data, 5, "the published tick is not modified")
+
+	var symbols []domain.TickerName
+	for _, ticker := range digest.Tickers {
+		symbols = append(symbols, ticker.Symbol)
+	}
+	assert.Equal(t, []domain.TickerName{"BTCUSDT", "ETHUSDT", "DOGEUSDT", "SOLUSDT", "XRPUSDT"}, symbols,
+		"priority symbols first, missing ones ignored, then the others in symbol order")
+}
+
+func TestMarketDataDigestStrategy_FormatIgnoresEmpty(t *testing.T) {
+	strategy := &MarketDataDigestStrategy{}
+	assert.Empty(t, strategy.Format(nil))
+	assert.Empty(t, strategy.Format((*domain.Tick)(nil)))
+	assert.Empty(t, strategy.Format(&domain.Tick{}))
+	assert.Empty(t, strategy.Format("not a tick"))
+}
@@ -71,3 +71,21 @@ func TestMarketDataStrategy_Format(t *testing.T) {
 		})
 	}
 }
+
+func TestMarketDataStrategy_FormatPriorityFirst(t *testing.T) {
+	tick := &domain.Tick{Data: map[domain.TickerName]*domain.Ticker{}}
+	for _, symbol := range []domain.TickerName{"SOLUSDT", "ETHUSDT", "XRPUSDT", "BTCUSDT", "DOGEUSDT"} {
+		tick.Data[symbol] = &domain.Ticker{Symbol: symbol}
+	}
+	strategy := &MarketDataStrategy{Priority: []domain.TickerName{"BTCUSDT", "ADAUSDT", "ETHUSDT"}}
+
+	events := strategy.Format(tick)
+
+	var symbols []domain.TickerName
+	for _, event := range events {
+		symbols = append(symbols, event.Data.(*TickerNotification).Ticker.Symbol)
+	}
+	assert.Len(t, symbols, 5)
+	assert.Equal(t, []domain.TickerName{"BTCUSDT", "ETHUSDT"}, symbols[:2], "priority symbols missing from the tick are ignored")
+	assert.ElementsMatch(t, []domain.TickerName{"SOLUSDT", "XRPUSDT", "DOGEUSDT"}, symbols[2:])
+}
@@ -0,0 +1,239 @@
+// Package strategytest provides a harness for testing notify.Strategy implementations.
+// It feeds generated or captured ticks through a strategy and compares the emitted events with golden files:
+//
+//	ticks := strategytest.GenerateTicks(strategytest.GenerateConfig{Count: 120, Seed: 1})
+//	events := strategytest.Run(strategy, ticks)
+//	strategytest.AssertGolden(t, "testdata/my_strategy.golden", events, *update)
+//
+// The package registers no flags, a test package declares its own -update flag to (re)write golden files after an
+// intended change:
+//
+//	var update = flag.Bool("update", false, "rewrite strategy golden files instead of comparing against them")
+package strategytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+)
+
+// DefaultSymbols are used by GenerateTicks when no symbols are configured
+var DefaultSymbols = []domain.TickerName{"BTCUSDT", "ETHUSDT", "SOLUSDT", "XRPUSDT", "DOGEUSDT"}
+
+// GenerateConfig configures synthetic tick generation
+type GenerateConfig struct {
+	Count      int                 // number of ticks, one per second
+	Symbols    []domain.TickerName // symbols present in every tick, DefaultSymbols if empty
+	Seed       uint64              // the same seed always produces the same ticks
+	Volatility float64             // max per-second price move in percent, 0.2 if zero
+	StartAt    time.Time           // time of the first tick, 2025-01-01 UTC if zero
+}
+
+// GenerateTicks produces a deterministic random walk of ticks with populated indicators
+func GenerateTicks(cfg GenerateConfig) []*domain.Tick {
+	if len(cfg.Symbols) == 0 {
+		cfg.Symbols = DefaultSymbols
+	}
+	if cfg.Volatility == 0 {
+		cfg.Volatility = 0.2
+	}
+	if cfg.StartAt.IsZero() {
+		cfg.StartAt = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	rnd := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15))
+	prices := make(map[domain.TickerName][]float64, len(cfg.Symbols))
+	for i, symbol := range cfg.Symbols {
+		prices[symbol] = []float64{math.Pow(10, float64(len(cfg.Symbols)-i))}
+	}
+
+	history := utils.NewRingBuffer[*domain.Tick](domain.MaxTickHistory)
+	ticks := make([]*domain.Tick, 0, cfg.Count)
+	for i := 0; i < cfg.Count; i++ {
+		at := cfg.StartAt.Add(time.Duration(i) * time.Second)
+		tick := &domain.Tick{
+			StartAt:   at,
+			FetchedAt: at,
+			CreatedAt: at,
+			LL5:       rnd.Int64N(50),
+			SL2:       rnd.Int64N(5),
+			Data:      make(map[domain.TickerName]*domain.Ticker, len(cfg.Symbols)),
+		}
+		// wider windows always include the narrower ones
+		tick.LL1 = tick.LL5 / 5
+		tick.LL2 = tick.LL5 / 2
+		tick.LL60 = tick.LL5 + rnd.Int64N(500)
+		tick.SL1 = tick.SL2 / 2
+		tick.SL10 = tick.SL2 + rnd.Int64N(20)
+
+		for _, symbol := range cfg.Symbols {
+			path := prices[symbol]
+			last := path[len(path)-1]
+			ask := mathutils.Round(last*(1+(rnd.Float64()*2-1)*cfg.Volatility/100), 6)
+			path = append(path, ask)
+			prices[symbol] = path
+			tick.SetTicker(newTicker(symbol, at, path))
+		}
+
+		history.Push(tick)
+		tick.CalculateIndicators(history, domain.DefaultPrecision())
+		ticks = append(ticks, tick)
+	}
+
+	return ticks
+}
+
+// newTicker builds a ticker from its price path where every element is one second
+func newTicker(symbol domain.TickerName, at time.Time, path []float64) *domain.Ticker {
+	n := len(path)
+	ask := path[n-1]
+	ticker := &domain.Ticker{
+		Symbol:    symbol,
+		EventAt:   at,
+		CreatedAt: at,
+		Ask:       ask,
+		Bid:       mathutils.Round(ask*0.9999, 6),
+		Max10:     ask,
+		Min10:     ask,
+	}
+
+	for _, p := range path[max(n-600, 0):] {
+		ticker.Max10 = math.Max(ticker.Max10, p)
+		ticker.Min10 = math.Min(ticker.Min10, p)
+	}
+	ticker.Max10Diff = mathutils.PercDiff(ask, ticker.Max10, 2)
+	ticker.Min10Diff = mathutils.PercDiff(ask, ticker.Min10, 2)
+	if n > 1 {
+		ticker.AskChange = mathutils.PercDiff(ask, path[n-2], 2)
+		ticker.BidChange = ticker.AskChange
+	}
+	if n > 60 {
+		ticker.Change1m = mathutils.PercDiff(ask, path[n-61], 2)
+	}
+	if n > 1200 {
+		ticker.Change20m = mathutils.PercDiff(ask, path[n-1201], 2)
+	}
+
+	return ticker
+}
+
+// LoadTicks reads captured ticks from a JSON file containing an array of domain.Tick
+func LoadTicks(path string) ([]*domain.Tick, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading ticks fixture: %w", err)
+	}
+
+	var ticks []*domain.Tick
+	if err := json.Unmarshal(data, &ticks); err != nil {
+		return nil, fmt.Errorf("decoding ticks fixture %s: %w", path, err)
+	}
+	return ticks, nil
+}
+
+// TickEvent is an event emitted by a strategy together with the index of the tick that produced it
+type TickEvent struct {
+	Tick  int
+	Event notify.Event
+}
+
+// Run feeds the ticks through the strategy in order and collects every emitted event.
+// A notify.HistoryStrategy is given the previous ticks it consults, as the notifier does
+func Run(strategy notify.Strategy, ticks []*domain.Tick) []TickEvent {
+	var result []TickEvent
+	for i, tick := range ticks {
+		var events []notify.Event
+		if hs, ok := strategy.(notify.HistoryStrategy); ok {
+			history := make([]any, 0, hs.HistorySize())
+			for _, previous := range ticks[max(i-hs.HistorySize(), 0):i] {
+				history = append(history, previous)
+			}
+			events = hs.FormatWithHistory(tick, history)
+		} else {
+			events = strategy.Format(tick)
+		}
+		for _, event := range events {
+			result = append(result, TickEvent{Tick: i, Event: event})
+		}
+	}
+	return result
+}
+
+// Render converts events into the stable text representation used by golden files.
+// Event times are omitted because strategies stamp them with the wall clock,
+// and events of the same tick are sorted since strategies may iterate over maps.
+func Render(events []TickEvent) (string, error) {
+	blocks := make([]string, 0, len(events))
+	byTick := make(map[int][]string)
+	order := make([]int, 0)
+
+	for _, e := range events {
+		body, err := renderData(e.Event.Data)
+		if err != nil {
+			return "", fmt.Errorf("rendering event of tick %d: %w", e.Tick, err)
+		}
+		if _, seen := byTick[e.Tick]; !seen {
+			order = append(order, e.Tick)
+		}
+		byTick[e.Tick] = append(byTick[e.Tick], fmt.Sprintf("### tick %d %s\n%s\n", e.Tick, e.Event.EventType, body))
+	}
+
+	for _, tick := range order {
+		tickBlocks := byTick[tick]
+		sort.Strings(tickBlocks)
+		blocks = append(blocks, tickBlocks...)
+	}
+
+	return strings.Join(blocks, "\n"), nil
+}
+
+func renderData(data any) (string, error) {
+	if s, ok := data.(string); ok {
+		return strings.TrimRight(s, "\n"), nil
+	}
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// AssertGolden compares rendered events with the golden file, or rewrites it when update is set
+func AssertGolden(t testing.TB, goldenPath string, events []TickEvent, update bool) {
+	t.Helper()
+
+	got, err := Render(events)
+	if err != nil {
+		t.Fatalf("rendering events: %v", err)
+	}
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o750); err != nil {
+			t.Fatalf("creating golden dir: %v", err)
+		}
+		if err := os.WriteFile(goldenPath, []byte(got), 0o600); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(filepath.Clean(goldenPath))
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if string(want) != got {
+		t.Errorf("events differ from %s (run with -update to accept)\n--- want\n%s\n--- got\n%s", goldenPath, want, got)
+	}
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for counters
+const (
+	// telemetryNotifierDeadlineExceeded counts Send calls that did not finish within the per-client timeout
+	telemetryNotifierDeadlineExceeded = "notifier.send.deadline_exceeded"
+
+	// telemetryNotifierPanics counts recovered panics raised by strategies or clients
+	telemetryNotifierPanics = "notifier.panics"
+
+	// telemetryNotifierCircuitRejected counts events skipped because the circuit of the client is open
+	telemetryNotifierCircuitRejected = "notifier.circuit.rejected"
+
+	// telemetryNotifierShadowEvents counts the events formatted by shadow subscriptions and not delivered
+	telemetryNotifierShadowEvents = "notifier.shadow.events"
+
+	// telemetryNotifierShadowMismatches counts the data a shadow subscription formatted into a number of events
+	// no live subscription of the topic did
+	telemetryNotifierShadowMismatches = "notifier.shadow.mismatches"
+
+	// telemetryNotifierCircuitOpen reports 1 while the circuit of a client is open and 0 once it closed
+	telemetryNotifierCircuitOpen = "notifier.circuit.open"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryNotifierDeadlineExceeded, Kind: telemetry.KindCounter, Description: "Send calls that did not finish within the per-client timeout", Tags: []string{"topic"}},
+		{Name: telemetryNotifierPanics, Kind: telemetry.KindCounter, Description: "Panics recovered from strategies or clients", Tags: []string{"topic"}},
+		{Name: telemetryNotifierCircuitRejected, Kind: telemetry.KindCounter, Description: "Events skipped because the circuit breaker of the client is open", Tags: []string{"topic", "client"}},
+		{Name: telemetryNotifierShadowEvents, Kind: telemetry.KindCounter, Description: "Events formatted by shadow subscriptions, logged instead of delivered", Tags: []string{"topic", "shadow"}},
+		{Name: telemetryNotifierShadowMismatches, Kind: telemetry.KindCounter, Description: "Data a shadow subscription formatted into a number of events no live subscription of the topic did", Tags: []string{"topic", "shadow"}},
+		{Name: telemetryNotifierCircuitOpen, Kind: telemetry.KindGauge, Description: "1 while the circuit breaker of the client is open, 0 once it closed", Tags: []string{"client"}},
+	}
+}
@@ -0,0 +1,76 @@
+package notifier
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+)
+
+// Topic represents a notification topic
+type Topic string
+
+const (
+	// MarketDataTopic is the event type for ticker data
+	MarketDataTopic Topic = "MARKET_DATA"
+
+	// AlertTopic is the event triggered when something significant happens in the market
+	AlertTopic Topic = "ALERT_MARKET_STATE"
+
+	// TickInfoTopic is the event triggered to send common information about the tick
+	TickInfoTopic Topic = "TICK_INFO"
+
+	// TimeSeriesTopic is the event carrying tick metrics for time-series databases
+	TimeSeriesTopic Topic = "TIME_SERIES"
+
+	// OpsAlertTopic is the event triggered when an operational alert of the importer fires or resolves
+	OpsAlertTopic Topic = "OPS_ALERT"
+
+	// BarsTopic is the event carrying the finalized 1-minute bar of a symbol
+	BarsTopic Topic = "MINUTE_BARS"
+
+	// AnnotationTopic is the event marking operational alerts as Grafana annotations
+	AnnotationTopic Topic = "ANNOTATIONS"
+)
+
+// topicName is the format of a topic: upper case words separated by underscores
+var topicName = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+
+// registry holds the known topics in registration order, the built-in ones first
+var registry = struct {
+	mu     sync.RWMutex
+	topics []Topic
+}{
+	topics: []Topic{MarketDataTopic, AlertTopic, TickInfoTopic, TimeSeriesTopic, OpsAlertTopic, BarsTopic, AnnotationTopic},
+}
+
+// RegisterTopic adds a custom topic, e.g. of a plugin strategy, so it can be subscribed to and configured
+// in the NOTIFY_*_TOPICS options. Topics must be registered before the options are validated
+func RegisterTopic(topic Topic) error {
+	if !topicName.MatchString(string(topic)) {
+		return fmt.Errorf("invalid topic name '%s': use upper case words separated by underscores", topic)
+	}
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if slices.Contains(registry.topics, topic) {
+		return fmt.Errorf("topic '%s' is already registered", topic)
+	}
+	registry.topics = append(registry.topics, topic)
+	return nil
+}
+
+// Validate checks if the topic exists
+func (t Topic) Validate() error {
+	if slices.Contains(Topics(), t) {
+		return nil
+	}
+	return fmt.Errorf("invalid topic: '%s'", t)
+}
+
+// Topics returns all known topics, the built-in ones first
+func Topics() []Topic {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return slices.Clone(registry.topics)
+}
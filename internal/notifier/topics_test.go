@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	notifyMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// registerTestTopic registers a topic for the duration of the test
+func registerTestTopic(t *testing.T, topic Topic) {
+	t.Helper()
+	require.NoError(t, RegisterTopic(topic))
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+		registry.topics = slices.DeleteFunc(registry.topics, func(registered Topic) bool { return registered == topic })
+	})
+}
+
+func TestRegisterTopic(t *testing.T) {
+	builtin := Topics()
+	registerTestTopic(t, "FUNDING_RATES")
+
+	assert.NoError(t, Topic("FUNDING_RATES").Validate())
+	assert.Equal(t, append(builtin, "FUNDING_RATES"), Topics(), "custom topics follow the built-in ones")
+
+	assert.ErrorContains(t, RegisterTopic("FUNDING_RATES"), "already registered")
+	assert.ErrorContains(t, RegisterTopic(MarketDataTopic), "already registered")
+	for _, invalid := range []Topic{"", "funding", "FUNDING-RATES", "_FUNDING", "FUNDING__RATES", "FUNDING RATES"} {
+		assert.ErrorContains(t, RegisterTopic(invalid), "invalid topic name", invalid)
+	}
+}
+
+func TestNotifier_NotifyCustomTopic(t *testing.T) {
+	registerTestTopic(t, "FUNDING_RATES")
+
+	client := &notifyMocks.ClientMock{
+		SendFunc: func(ctx context.Context, event notify.Event) error {
+			return nil
+		},
+	}
+	n := New(zap.NewNop())
+	n.Subscribe("FUNDING_RATES", client, &notifyMocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			return []notify.Event{{EventType: "FUNDING_RATES", Data: data}}
+		},
+	})
+
+	n.Notify(context.Background(), &domain.Tick{})
+	require.Len(t, client.SendCalls(), 1)
+	assert.Equal(t, "FUNDING_RATES", client.SendCalls()[0].Event.EventType)
+}
@@ -0,0 +1,99 @@
+// Package opsalert watches the importer events and raises operational alerts
+// (tick gaps, websocket reconnect storms, silent or spiking websocket streams, repository failures, spiking liquidation
+// storage, suspect liquidations, clock skew, warm-up) with resolve notifications.
+package opsalert
+
+import (
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Status is the state of an alert
+type Status string
+
+const (
+	// StatusFiring means the alert condition holds
+	StatusFiring Status = "firing"
+
+	// StatusResolved means the alert condition no longer holds
+	StatusResolved Status = "resolved"
+)
+
+// Alert names, used as the alertname label
+const (
+	// NameTickGap fires when no tick was built for longer than the configured gap
+	NameTickGap = "ImporterTickGap"
+
+	// NameReconnectStorm fires when the exchange websockets keep failing
+	NameReconnectStorm = "ImporterWebsocketReconnectStorm"
+
+	// NameRepositoryFailure fires when storing data fails
+	NameRepositoryFailure = "ImporterRepositoryFailure"
+
+	// NameStreamSilent fires when a websocket stream stops delivering messages without failing
+	NameStreamSilent = "ImporterWebsocketStreamSilent"
+
+	// NameStreamRateSpike fires when the message rate of a websocket stream spikes above its baseline
+	NameStreamRateSpike = "ImporterWebsocketStreamRateSpike"
+
+	// NameLiquidationStorageSpike fires while the rate of the stored liquidations exceeds its trailing baseline
+	NameLiquidationStorageSpike = "ImporterLiquidationStorageSpike"
+
+	// NameSuspectLiquidations fires when liquidations fail their sanity checks, e.g. a price far from the book
+	NameSuspectLiquidations = "ImporterSuspectLiquidations"
+
+	// NameClockSkew fires when the event times reported by the exchange and the times the importer received them
+	// drift apart, the clock of the exchange or of the host is off
+	NameClockSkew = "ImporterClockSkew"
+
+	// NameWarmingUp fires while the ticks are built before the history covers the warm-up, its resolve notification
+	// announces that the importer warmed up and market alerts are raised again
+	NameWarmingUp = "ImporterWarmingUp"
+
+	// NameSelfCheck is sent already resolved by the self-check as a test notification, it never fires
+	NameSelfCheck = "ImporterSelfCheck"
+)
+
+// Severities, used as the severity label
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Well-known label and annotation keys
+const (
+	LabelAlertName        = "alertname"
+	LabelSeverity         = "severity"
+	AnnotationSummary     = "summary"
+	AnnotationDescription = "description"
+)
+
+// Alert is a single operational alert transition
+type Alert struct {
+	Status      Status
+	Labels      map[string]string // always contains alertname and severity
+	Annotations map[string]string // summary and description
+	StartsAt    time.Time
+	EndsAt      time.Time // zero while firing
+}
+
+// Name returns the alertname label
+func (a Alert) Name() string {
+	return a.Labels[LabelAlertName]
+}
+
+// Fingerprint identifies the alert by its labels, so the firing and resolved notifications share it
+func (a Alert) Fingerprint() string {
+	h := fnv.New64a()
+	for _, key := range slices.Sorted(maps.Keys(a.Labels)) {
+		h.Write([]byte(key))
+		h.Write([]byte{0xff})
+		h.Write([]byte(a.Labels[key]))
+		h.Write([]byte{0xff})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
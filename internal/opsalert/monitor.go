@@ -0,0 +1,509 @@
+package opsalert
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"github.com/ayankousky/exchange-data-importer/internal/maintenance"
+	"go.uber.org/zap"
+)
+
+// evaluateInterval is how often the time based rules are evaluated
+const evaluateInterval = time.Second
+
+// streamStages are the degradation stages reported by the exchange websockets
+var streamStages = []string{eventbus.StageLiquidations, eventbus.StageSubTicksStream}
+
+// repositoryStages are the degradation stages reported when storing data fails
+var repositoryStages = []string{eventbus.StageStoreTick, eventbus.StageStoreLiquidation, eventbus.StageStoreSubTicks, eventbus.StageStoreBars}
+
+// Rules configures when the alerts fire; a zero threshold disables the rule
+type Rules struct {
+	TickGap                        time.Duration // fire when no tick was built for this long
+	ReconnectStormCount            int           // fire when the websockets failed this many times within ReconnectStormWindow
+	ReconnectStormWindow           time.Duration
+	RepositoryFailureResolveAfter  time.Duration // resolve once storing succeeded for this long
+	StreamSilence                  time.Duration // fire when a websocket stream without failures received no message for this long
+	LiquidationStreamSilence       time.Duration // StreamSilence of the liquidation streams, which stay quiet for long on calm markets
+	StreamSpikeFactor              float64       // fire when the rate of a stream exceeds its baseline this many times
+	StreamBaseline                 time.Duration // window of the moving average rate spikes are compared to, also the warm-up of a stream
+	SuspectLiquidationResolveAfter time.Duration // fire on a suspect liquidation and resolve once none was received for this long
+	ClockSkew                      time.Duration // fire when the median skew between the event and receive times exceeds this, either way
+}
+
+// DefaultRules returns the default alert rules
+func DefaultRules() Rules {
+	return Rules{
+		TickGap:                        10 * time.Second,
+		ReconnectStormCount:            5,
+		ReconnectStormWindow:           5 * time.Minute,
+		RepositoryFailureResolveAfter:  time.Minute,
+		StreamSilence:                  5 * time.Minute,
+		LiquidationStreamSilence:       time.Hour,
+		StreamSpikeFactor:              10,
+		StreamBaseline:                 10 * time.Minute,
+		SuspectLiquidationResolveAfter: 5 * time.Minute,
+		ClockSkew:                      30 * time.Second,
+	}
+}
+
+// skewSamples is the number of the latest event skews of a source the median skew is taken over, so a single
+// delayed event doesn't fire the clock skew alert
+const skewSamples = 20
+
+// Sources of the event skews
+const (
+	skewSourceTickers      = "tickers"
+	skewSourceLiquidations = "liquidations"
+)
+
+// minSpikeBaseline is the lowest baseline rate a spike is compared to, so a few messages on a mostly quiet stream
+// are not reported as a spike
+const minSpikeBaseline = 1.0
+
+// streamState is the measured message rate of a single websocket stream
+type streamState struct {
+	stage           string // degradation stage of the stream, e.g. eventbus.StageLiquidations
+	firstMeasuredAt time.Time
+	lastMessageAt   time.Time
+	lastFailureAt   time.Time
+	baseline        float64 // exponential moving average of the messages per second
+	rate            float64 // messages per second of the latest measurement
+	spiking         bool    // the latest measurement exceeded the baseline by the spike factor
+}
+
+// condition is the evaluated state of a single rule
+type condition struct {
+	firing      bool
+	severity    string
+	summary     string
+	description string
+}
+
+// Monitor turns importer events into operational alerts.
+// Handle consumes the bus events, Run evaluates the time based rules; every transition is passed to publish
+type Monitor struct {
+	rules       Rules
+	maintenance maintenance.Calendar
+	labels      map[string]string
+	publish     func(Alert)
+	now         func() time.Time
+
+	mu              sync.Mutex
+	lastTickAt      time.Time
+	streamFailures  []time.Time
+	lastRepoFailure time.Time
+	lastRepoStage   string
+	lastRepoErr     string
+	streams         map[string]*streamState // by streamName
+	storageRate     eventbus.StorageRate    // latest liquidation storage rate
+	lastSuspectAt   time.Time
+	lastSuspect     string
+	suspectCount    int // suspect liquidations since the alert last resolved
+	warmingUp       bool
+	skews           map[string][]time.Duration // latest event skews by source, see domain.EventSkew
+	active          map[string]Alert
+
+	telemetry telemetry.Provider
+	logger    *zap.Logger
+}
+
+// NewMonitor creates a new Monitor; labels are added to every alert, e.g. service and exchange
+func NewMonitor(rules Rules, labels map[string]string, publish func(Alert), logger *zap.Logger) *Monitor {
+	return &Monitor{
+		rules:     rules,
+		labels:    maps.Clone(labels),
+		publish:   publish,
+		now:       time.Now,
+		streams:   make(map[string]*streamState),
+		skews:     make(map[string][]time.Duration),
+		active:    make(map[string]Alert),
+		telemetry: &telemetry.NoopProvider{},
+		logger:    logger.With(zap.String("component", "opsalert")),
+	}
+}
+
+// WithTelemetry sets the telemetry provider used to count transitions
+func (m *Monitor) WithTelemetry(provider telemetry.Provider) *Monitor {
+	if provider != nil {
+		m.telemetry = provider
+	}
+	return m
+}
+
+// WithMaintenance sets the maintenance windows of the exchange, within them the tick gap, reconnect storm and stream
+// silence alerts are suppressed and their state restarts once the window ends
+func (m *Monitor) WithMaintenance(calendar maintenance.Calendar) *Monitor {
+	m.maintenance = calendar
+	return m
+}
+
+// Handle records an importer event, it matches eventbus.Handler
+func (m *Monitor) Handle(_ context.Context, event eventbus.Event) {
+	m.mu.Lock()
+	switch event.Type {
+	case eventbus.TickBuilt:
+		m.lastTickAt = event.Time
+		if tick, ok := event.Payload.(*domain.Tick); ok && tick != nil {
+			m.warmingUp = tick.WarmingUp
+			m.recordSkew(skewSourceTickers, time.Duration(tick.EventSkew)*time.Millisecond)
+		}
+	case eventbus.ImportDegraded:
+		degradation, ok := event.Payload.(eventbus.Degradation)
+		if !ok {
+			break
+		}
+		switch {
+		case slices.Contains(streamStages, degradation.Stage):
+			m.streamFailures = append(m.streamFailures, event.Time)
+			m.stream(degradation.Exchange, degradation.Stage, event.Time).lastFailureAt = event.Time
+		case slices.Contains(repositoryStages, degradation.Stage):
+			m.lastRepoFailure = event.Time
+			m.lastRepoStage = degradation.Stage
+			if degradation.Err != nil {
+				m.lastRepoErr = degradation.Err.Error()
+			}
+		}
+	case eventbus.StreamRateMeasured:
+		if rate, ok := event.Payload.(eventbus.StreamRate); ok {
+			m.measureStream(rate, event.Time)
+		}
+	case eventbus.LiquidationStorageMeasured:
+		if rate, ok := event.Payload.(eventbus.StorageRate); ok {
+			m.storageRate = rate
+		}
+	case eventbus.LiquidationReceived:
+		liq, ok := event.Payload.(domain.Liquidation)
+		if !ok {
+			break
+		}
+		if skew, ok := domain.EventSkew(liq.EventAt, liq.ReceivedAt); ok {
+			m.recordSkew(skewSourceLiquidations, skew)
+		}
+		if len(liq.Suspect) > 0 {
+			m.lastSuspectAt = event.Time
+			m.lastSuspect = fmt.Sprintf("%s %s", liq.Order.Symbol, strings.Join(liq.Suspect, ", "))
+			m.suspectCount++
+		}
+	}
+	m.mu.Unlock()
+
+	// failures should alert right away instead of on the next evaluation
+	if event.Type == eventbus.ImportDegraded {
+		m.Evaluate()
+	}
+}
+
+// Run evaluates the rules every second until ctx is canceled
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(evaluateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Evaluate()
+		}
+	}
+}
+
+// Evaluate checks every rule and publishes the alerts that started firing or got resolved
+func (m *Monitor) Evaluate() {
+	m.mu.Lock()
+	now := m.now()
+	if window, ok := m.maintenance.Active(now); ok {
+		m.suppressOutageRules(window.End(now))
+	}
+	conditions := map[string]condition{
+		NameTickGap:                 m.tickGap(now),
+		NameReconnectStorm:          m.reconnectStorm(now),
+		NameRepositoryFailure:       m.repositoryFailure(now),
+		NameStreamSilent:            m.streamSilent(now),
+		NameStreamRateSpike:         m.streamRateSpike(),
+		NameSuspectLiquidations:     m.suspectLiquidations(now),
+		NameWarmingUp:               m.warmUp(),
+		NameClockSkew:               m.clockSkew(),
+		NameLiquidationStorageSpike: m.liquidationStorageSpike(),
+	}
+
+	var transitions []Alert
+	for _, name := range slices.Sorted(maps.Keys(conditions)) {
+		cond := conditions[name]
+		active, isActive := m.active[name]
+		switch {
+		case cond.firing && !isActive:
+			alert := m.newAlert(name, cond, now)
+			m.active[name] = alert
+			transitions = append(transitions, alert)
+		case !cond.firing && isActive:
+			active.Status = StatusResolved
+			active.EndsAt = now
+			delete(m.active, name)
+			transitions = append(transitions, active)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, alert := range transitions {
+		m.report(alert)
+	}
+}
+
+// suppressOutageRules forgets the websocket failures of a maintenance window and restarts the tick gap and stream
+// silences at its end, so their rules neither fire within the window nor right after it ended
+func (m *Monitor) suppressOutageRules(end time.Time) {
+	m.lastTickAt = end
+	m.streamFailures = nil
+	for _, state := range m.streams {
+		state.lastMessageAt = end
+	}
+}
+
+// tickGap fires when no tick was built for longer than the configured gap, the gap starts with the first evaluation
+func (m *Monitor) tickGap(now time.Time) condition {
+	if m.rules.TickGap <= 0 {
+		return condition{}
+	}
+	if m.lastTickAt.IsZero() {
+		m.lastTickAt = now
+	}
+	gap := now.Sub(m.lastTickAt)
+	return condition{
+		firing:      gap > m.rules.TickGap,
+		severity:    SeverityCritical,
+		summary:     "No ticks are being imported",
+		description: fmt.Sprintf("No tick was built for %s (threshold %s)", gap.Truncate(time.Second), m.rules.TickGap),
+	}
+}
+
+// reconnectStorm fires when the websockets failed too often within the window
+func (m *Monitor) reconnectStorm(now time.Time) condition {
+	if m.rules.ReconnectStormCount <= 0 || m.rules.ReconnectStormWindow <= 0 {
+		return condition{}
+	}
+	windowStart := now.Add(-m.rules.ReconnectStormWindow)
+	m.streamFailures = slices.DeleteFunc(m.streamFailures, func(t time.Time) bool { return t.Before(windowStart) })
+
+	return condition{
+		firing:   len(m.streamFailures) >= m.rules.ReconnectStormCount,
+		severity: SeverityWarning,
+		summary:  "Exchange websockets keep reconnecting",
+		description: fmt.Sprintf("%d websocket failures within %s (threshold %d)",
+			len(m.streamFailures), m.rules.ReconnectStormWindow, m.rules.ReconnectStormCount),
+	}
+}
+
+// repositoryFailure fires on a storage failure and resolves once storing succeeded for the configured time
+func (m *Monitor) repositoryFailure(now time.Time) condition {
+	if m.lastRepoFailure.IsZero() {
+		return condition{}
+	}
+	return condition{
+		firing:      now.Sub(m.lastRepoFailure) < m.rules.RepositoryFailureResolveAfter,
+		severity:    SeverityCritical,
+		summary:     "Storing imported data fails",
+		description: fmt.Sprintf("Stage %s failed: %s", m.lastRepoStage, m.lastRepoErr),
+	}
+}
+
+// suspectLiquidations fires on a suspect liquidation and resolves once none was received for the configured time
+func (m *Monitor) suspectLiquidations(now time.Time) condition {
+	if m.rules.SuspectLiquidationResolveAfter <= 0 || m.lastSuspectAt.IsZero() {
+		return condition{}
+	}
+	firing := now.Sub(m.lastSuspectAt) < m.rules.SuspectLiquidationResolveAfter
+	if !firing {
+		m.suspectCount = 0
+	}
+	return condition{
+		firing:      firing,
+		severity:    SeverityWarning,
+		summary:     "Liquidations look wrong",
+		description: fmt.Sprintf("%d liquidations failed their sanity checks, latest: %s", m.suspectCount, m.lastSuspect),
+	}
+}
+
+// warmUp fires while the latest tick was built before the history covered the warm-up
+func (m *Monitor) warmUp() condition {
+	return condition{
+		firing:      m.warmingUp,
+		severity:    SeverityInfo,
+		summary:     "Importer is warming up",
+		description: "The history does not cover the warm-up yet, 20-minute indicators are incomplete and market alerts are suppressed",
+	}
+}
+
+// recordSkew keeps the event skew of a source, dropping the oldest one past skewSamples
+func (m *Monitor) recordSkew(source string, skew time.Duration) {
+	skews := append(m.skews[source], skew)
+	if len(skews) > skewSamples {
+		skews = skews[len(skews)-skewSamples:]
+	}
+	m.skews[source] = skews
+}
+
+// clockSkew fires while the median of the latest event skews of a source exceeds the configured skew, either way
+func (m *Monitor) clockSkew() condition {
+	if m.rules.ClockSkew <= 0 {
+		return condition{}
+	}
+	var skewed []string
+	for _, source := range slices.Sorted(maps.Keys(m.skews)) {
+		median := domain.MedianDuration(slices.Clone(m.skews[source]))
+		if median > m.rules.ClockSkew || median < -m.rules.ClockSkew {
+			skewed = append(skewed, fmt.Sprintf("%s %s", source, median.Round(time.Millisecond)))
+		}
+	}
+	return condition{
+		firing:   len(skewed) > 0,
+		severity: SeverityWarning,
+		summary:  "Exchange and importer clocks disagree",
+		description: fmt.Sprintf("Events are received later than the exchange stamped them by a median of %s (threshold %s), "+
+			"a negative skew means the exchange clock runs ahead", strings.Join(skewed, ", "), m.rules.ClockSkew),
+	}
+}
+
+// streamName names the stream of an exchange, the same stage of two exchanges being two streams
+func streamName(exchange, stage string) string {
+	if exchange == "" {
+		return stage
+	}
+	return exchange + " " + stage
+}
+
+// stream returns the state of a stream, a new stream is considered to have received a message at the given time
+func (m *Monitor) stream(exchange, stage string, at time.Time) *streamState {
+	name := streamName(exchange, stage)
+	state, ok := m.streams[name]
+	if !ok {
+		state = &streamState{stage: stage, firstMeasuredAt: at, lastMessageAt: at}
+		m.streams[name] = state
+	}
+	return state
+}
+
+// measureStream records a message rate measurement, comparing it to the baseline before adding it
+func (m *Monitor) measureStream(rate eventbus.StreamRate, at time.Time) {
+	state := m.stream(rate.Exchange, rate.Stream, at)
+	state.rate = rate.PerSecond()
+	if rate.Messages > 0 {
+		state.lastMessageAt = at
+	}
+
+	warmedUp := at.Sub(state.firstMeasuredAt) >= m.rules.StreamBaseline
+	state.spiking = m.rules.StreamSpikeFactor > 0 && m.rules.StreamBaseline > 0 && warmedUp &&
+		state.rate > m.rules.StreamSpikeFactor*max(state.baseline, minSpikeBaseline)
+
+	if m.rules.StreamBaseline > 0 {
+		weight := min(rate.Interval.Seconds()/m.rules.StreamBaseline.Seconds(), 1)
+		state.baseline += weight * (state.rate - state.baseline)
+	}
+}
+
+// silence returns the silence a stream of the stage may keep before it's reported, 0 if it never is
+func (m *Monitor) silence(stage string) time.Duration {
+	if stage == eventbus.StageLiquidations {
+		return m.rules.LiquidationStreamSilence
+	}
+	return m.rules.StreamSilence
+}
+
+// streamSilent fires when a stream received no message for longer than the configured silence although it did not
+// fail within that time, i.e. the connection looks healthy but delivers no data
+func (m *Monitor) streamSilent(now time.Time) condition {
+	var silent []string
+	for _, name := range slices.Sorted(maps.Keys(m.streams)) {
+		state := m.streams[name]
+		silence := m.silence(state.stage)
+		if silence <= 0 {
+			continue
+		}
+		if now.Sub(state.lastMessageAt) > silence && now.Sub(state.lastFailureAt) > silence {
+			silent = append(silent, fmt.Sprintf("%s for more than %s", name, silence))
+		}
+	}
+	return condition{
+		firing:   len(silent) > 0,
+		severity: SeverityCritical,
+		summary:  "Exchange websockets stopped sending data",
+		description: fmt.Sprintf("No message on %s while the connection reported no failure",
+			strings.Join(silent, ", ")),
+	}
+}
+
+// streamRateSpike fires while the latest rate of a stream exceeds its baseline by the configured factor
+func (m *Monitor) streamRateSpike() condition {
+	var spikes []string
+	for _, name := range slices.Sorted(maps.Keys(m.streams)) {
+		if state := m.streams[name]; state.spiking {
+			spikes = append(spikes, fmt.Sprintf("%s at %.1f/s (baseline %.1f/s)", name, state.rate, state.baseline))
+		}
+	}
+	return condition{
+		firing:   len(spikes) > 0,
+		severity: SeverityWarning,
+		summary:  "Exchange websocket message rate spiked",
+		description: fmt.Sprintf("Message rate of %s exceeds the baseline %g times",
+			strings.Join(spikes, ", "), m.rules.StreamSpikeFactor),
+	}
+}
+
+// liquidationStorageSpike fires while the importer flags the rate of the stored liquidations, the spike factor and
+// baseline are configured on the importer
+func (m *Monitor) liquidationStorageSpike() condition {
+	return condition{
+		firing:   m.storageRate.Spiking,
+		severity: SeverityWarning,
+		summary:  "Liquidations are stored faster than usual",
+		description: fmt.Sprintf("%.1f liquidations stored per second, far above the baseline of %.1f/s",
+			m.storageRate.PerSecond(), m.storageRate.Baseline),
+	}
+}
+
+// newAlert builds a firing alert with the common labels
+func (m *Monitor) newAlert(name string, cond condition, now time.Time) Alert {
+	labels := maps.Clone(m.labels)
+	if labels == nil {
+		labels = make(map[string]string, 2)
+	}
+	labels[LabelAlertName] = name
+	labels[LabelSeverity] = cond.severity
+
+	return Alert{
+		Status: StatusFiring,
+		Labels: labels,
+		Annotations: map[string]string{
+			AnnotationSummary:     cond.summary,
+			AnnotationDescription: cond.description,
+		},
+		StartsAt: now,
+	}
+}
+
+// report logs and publishes a transition
+func (m *Monitor) report(alert Alert) {
+	m.telemetry.IncrementCounter(telemetryAlertTransitions, 1,
+		fmt.Sprintf("alertname:%s", alert.Name()), fmt.Sprintf("status:%s", alert.Status))
+
+	fields := []zap.Field{
+		zap.String("alert", alert.Name()),
+		zap.String("description", alert.Annotations[AnnotationDescription]),
+	}
+	if alert.Status == StatusFiring {
+		m.logger.Warn("Operational alert firing", fields...)
+	} else {
+		m.logger.Info("Operational alert resolved", fields...)
+	}
+
+	m.publish(alert)
+}
@@ -0,0 +1,385 @@
+package opsalert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/maintenance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestMonitor returns a monitor with a manual clock and the published alerts
+func newTestMonitor(rules Rules) (*Monitor, *time.Time, *[]Alert) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var published []Alert
+	m := NewMonitor(rules, map[string]string{"service": "importer"}, func(a Alert) {
+		published = append(published, a)
+	}, zap.NewNop())
+	m.now = func() time.Time { return now }
+	return m, &now, &published
+}
+
+func TestMonitor_TickGap(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{TickGap: 10 * time.Second})
+
+	m.Evaluate() // starts the grace period
+	*now = now.Add(11 * time.Second)
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	firing := (*published)[0]
+	assert.Equal(t, StatusFiring, firing.Status)
+	assert.Equal(t, map[string]string{
+		LabelAlertName: NameTickGap,
+		LabelSeverity:  SeverityCritical,
+		"service":      "importer",
+	}, firing.Labels)
+	assert.Equal(t, "No tick was built for 11s (threshold 10s)", firing.Annotations[AnnotationDescription])
+	assert.True(t, firing.EndsAt.IsZero())
+
+	m.Evaluate()
+	assert.Len(t, *published, 1, "a firing alert is published once")
+
+	m.Handle(context.Background(), eventbus.Event{Type: eventbus.TickBuilt, Time: *now})
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	resolved := (*published)[1]
+	assert.Equal(t, StatusResolved, resolved.Status)
+	assert.Equal(t, firing.StartsAt, resolved.StartsAt)
+	assert.Equal(t, *now, resolved.EndsAt)
+	assert.Equal(t, firing.Fingerprint(), resolved.Fingerprint())
+}
+
+func TestMonitor_ReconnectStorm(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{ReconnectStormCount: 3, ReconnectStormWindow: time.Minute})
+	streamFailure := func() {
+		m.Handle(context.Background(), eventbus.Event{
+			Type:    eventbus.ImportDegraded,
+			Time:    *now,
+			Payload: eventbus.Degradation{Stage: eventbus.StageLiquidations, Err: errors.New("websocket error")},
+		})
+	}
+
+	streamFailure()
+	streamFailure()
+	assert.Empty(t, *published)
+
+	streamFailure()
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameReconnectStorm, (*published)[0].Name())
+	assert.Equal(t, StatusFiring, (*published)[0].Status)
+
+	*now = now.Add(2 * time.Minute)
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status)
+}
+
+func TestMonitor_RepositoryFailure(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{RepositoryFailureResolveAfter: time.Minute})
+
+	m.Handle(context.Background(), eventbus.Event{
+		Type:    eventbus.ImportDegraded,
+		Time:    *now,
+		Payload: eventbus.Degradation{Stage: eventbus.StageFetchTickers, Err: errors.New("timeout")},
+	})
+	assert.Empty(t, *published, "only storage stages count as repository failures")
+
+	m.Handle(context.Background(), eventbus.Event{
+		Type:    eventbus.ImportDegraded,
+		Time:    *now,
+		Payload: eventbus.Degradation{Stage: eventbus.StageStoreTick, Err: errors.New("disk full")},
+	})
+	require.Len(t, *published, 1, "storage failures fire right away")
+	assert.Equal(t, NameRepositoryFailure, (*published)[0].Name())
+	assert.Equal(t, "Stage store_tick failed: disk full", (*published)[0].Annotations[AnnotationDescription])
+
+	*now = now.Add(30 * time.Second)
+	m.Evaluate()
+	assert.Len(t, *published, 1)
+
+	*now = now.Add(31 * time.Second)
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status)
+}
+
+// measureStream hands the monitor the number of messages a stream of the exchange received within the last second
+func measureStream(m *Monitor, at time.Time, exchange, stream string, messages int64) {
+	m.Handle(context.Background(), eventbus.Event{
+		Type:    eventbus.StreamRateMeasured,
+		Time:    at,
+		Payload: eventbus.StreamRate{Exchange: exchange, Stream: stream, Messages: messages, Interval: time.Second},
+	})
+}
+
+func TestMonitor_StreamSilent(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{StreamSilence: time.Minute})
+
+	measureStream(m, *now, "binance", eventbus.StageSubTicksStream, 3)
+	measureStream(m, *now, "okx", eventbus.StageSubTicksStream, 3)
+	*now = now.Add(30 * time.Second)
+	measureStream(m, *now, "binance", eventbus.StageSubTicksStream, 0)
+	m.Handle(context.Background(), eventbus.Event{
+		Type:    eventbus.ImportDegraded,
+		Time:    *now,
+		Payload: eventbus.Degradation{Exchange: "binance", Stage: eventbus.StageSubTicksStream, Err: errors.New("websocket error")},
+	})
+	*now = now.Add(40 * time.Second)
+	measureStream(m, *now, "binance", eventbus.StageSubTicksStream, 0)
+	measureStream(m, *now, "okx", eventbus.StageSubTicksStream, 1)
+	m.Evaluate()
+	assert.Empty(t, *published, "a failing stream is covered by the reconnect storm")
+
+	*now = now.Add(21 * time.Second)
+	measureStream(m, *now, "binance", eventbus.StageSubTicksStream, 0)
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameStreamSilent, (*published)[0].Name())
+	assert.Equal(t, StatusFiring, (*published)[0].Status)
+	assert.Equal(t, "No message on binance sub_ticks_stream for more than 1m0s while the connection reported no failure",
+		(*published)[0].Annotations[AnnotationDescription], "the failure of binance didn't cover the silence of okx")
+
+	measureStream(m, *now, "binance", eventbus.StageSubTicksStream, 1)
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status)
+}
+
+func TestMonitor_LiquidationStreamSilent(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{StreamSilence: time.Minute})
+
+	measureStream(m, *now, "binance", eventbus.StageLiquidations, 3)
+	*now = now.Add(2 * time.Hour)
+	measureStream(m, *now, "binance", eventbus.StageLiquidations, 0)
+	m.Evaluate()
+	assert.Empty(t, *published, "quiet liquidation streams are exempt without their own silence")
+
+	m, now, published = newTestMonitor(Rules{StreamSilence: time.Minute, LiquidationStreamSilence: time.Hour})
+	measureStream(m, *now, "binance", eventbus.StageLiquidations, 3)
+	*now = now.Add(30 * time.Minute)
+	measureStream(m, *now, "binance", eventbus.StageLiquidations, 0)
+	m.Evaluate()
+	assert.Empty(t, *published, "within the silence of the liquidation streams")
+
+	*now = now.Add(31 * time.Minute)
+	measureStream(m, *now, "binance", eventbus.StageLiquidations, 0)
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	assert.Equal(t, "No message on binance liquidations_stream for more than 1h0m0s while the connection reported no failure",
+		(*published)[0].Annotations[AnnotationDescription])
+}
+
+func TestMonitor_StreamRateSpike(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{StreamSpikeFactor: 5, StreamBaseline: 10 * time.Second})
+
+	measureStream(m, *now, "", eventbus.StageSubTicksStream, 100)
+	m.Evaluate()
+	assert.Empty(t, *published, "spikes are not detected before the baseline is warmed up")
+
+	for range 20 {
+		*now = now.Add(time.Second)
+		measureStream(m, *now, "", eventbus.StageSubTicksStream, 10)
+	}
+	m.Evaluate()
+	assert.Empty(t, *published)
+
+	*now = now.Add(time.Second)
+	measureStream(m, *now, "", eventbus.StageSubTicksStream, 200)
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameStreamRateSpike, (*published)[0].Name())
+	assert.Equal(t, SeverityWarning, (*published)[0].Labels[LabelSeverity])
+
+	*now = now.Add(time.Second)
+	measureStream(m, *now, "", eventbus.StageSubTicksStream, 10)
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status)
+}
+
+func TestMonitor_LiquidationStorageSpike(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{})
+	measure := func(stored int64, spiking bool) {
+		m.Handle(context.Background(), eventbus.Event{
+			Type:    eventbus.LiquidationStorageMeasured,
+			Time:    *now,
+			Payload: eventbus.StorageRate{Stored: stored, Interval: time.Second, Baseline: 2, Spiking: spiking},
+		})
+		m.Evaluate()
+	}
+
+	measure(3, false)
+	assert.Empty(t, *published)
+
+	measure(40, true)
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameLiquidationStorageSpike, (*published)[0].Name())
+	assert.Equal(t, "40.0 liquidations stored per second, far above the baseline of 2.0/s",
+		(*published)[0].Annotations[AnnotationDescription])
+
+	*now = now.Add(time.Second)
+	measure(2, false)
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status)
+}
+
+func TestMonitor_DisabledRules(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{})
+
+	m.Evaluate()
+	*now = now.Add(time.Hour)
+	m.Handle(context.Background(), eventbus.Event{
+		Type:    eventbus.ImportDegraded,
+		Time:    *now,
+		Payload: eventbus.Degradation{Stage: eventbus.StageStoreTick, Err: errors.New("disk full")},
+	})
+	measureStream(m, *now, "", eventbus.StageLiquidations, 0)
+	*now = now.Add(time.Hour)
+	measureStream(m, *now, "", eventbus.StageLiquidations, 1000)
+	m.Evaluate()
+
+	assert.Empty(t, *published)
+}
+
+func TestAlert_Fingerprint(t *testing.T) {
+	a := Alert{Labels: map[string]string{LabelAlertName: NameTickGap, "exchange": "binance"}}
+	b := Alert{Labels: map[string]string{"exchange": "binance", LabelAlertName: NameTickGap}, Status: StatusResolved}
+	c := Alert{Labels: map[string]string{LabelAlertName: NameTickGap, "exchange": "okx"}}
+
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+	assert.NotEqual(t, a.Fingerprint(), c.Fingerprint())
+	assert.Len(t, a.Fingerprint(), 16)
+}
+
+func TestMonitor_SuspectLiquidations(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{SuspectLiquidationResolveAfter: time.Minute})
+	liquidation := func(suspect ...string) {
+		m.Handle(context.Background(), eventbus.Event{
+			Type:    eventbus.LiquidationReceived,
+			Time:    *now,
+			Payload: domain.Liquidation{Order: domain.Order{Symbol: "BTCUSDT"}, Suspect: suspect},
+		})
+	}
+
+	liquidation()
+	m.Evaluate()
+	assert.Empty(t, *published)
+
+	liquidation("price: sell at 4900 is -90% away from the bid 49900 (max 10%)")
+	liquidation("price: sell at 4800 is -90.38% away from the bid 49900 (max 10%)")
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameSuspectLiquidations, (*published)[0].Name())
+	assert.Equal(t, "2 liquidations failed their sanity checks, latest: BTCUSDT price: sell at 4800 is -90.38% away from the bid 49900 (max 10%)",
+		(*published)[0].Annotations[AnnotationDescription])
+
+	*now = now.Add(time.Minute)
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status)
+}
+
+func TestMonitor_ClockSkew(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{ClockSkew: 30 * time.Second})
+	liquidation := func(skew time.Duration) {
+		m.Handle(context.Background(), eventbus.Event{
+			Type:    eventbus.LiquidationReceived,
+			Time:    *now,
+			Payload: domain.Liquidation{EventAt: now.Add(-skew), StoredAt: *now, ReceivedAt: *now},
+		})
+	}
+	tick := func(skew time.Duration) {
+		m.Handle(context.Background(), eventbus.Event{Type: eventbus.TickBuilt, Time: *now, Payload: &domain.Tick{
+			FetchedAt: *now,
+			EventSkew: skew.Milliseconds(),
+		}})
+	}
+
+	liquidation(time.Second)
+	liquidation(time.Minute) // a single delayed liquidation
+	liquidation(time.Second)
+	tick(time.Second)
+	for range skewSamples {
+		// backfilled liquidations weren't received from the stream
+		m.Handle(context.Background(), eventbus.Event{
+			Type:    eventbus.LiquidationReceived,
+			Time:    *now,
+			Payload: domain.Liquidation{EventAt: now.Add(-time.Hour), StoredAt: now.Add(-time.Hour)},
+		})
+	}
+	m.Evaluate()
+	assert.Empty(t, *published)
+
+	for range skewSamples {
+		tick(-45 * time.Second)
+	}
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameClockSkew, (*published)[0].Name())
+	assert.Equal(t, "Events are received later than the exchange stamped them by a median of tickers -45s (threshold 30s), "+
+		"a negative skew means the exchange clock runs ahead", (*published)[0].Annotations[AnnotationDescription])
+
+	for range skewSamples / 2 {
+		tick(time.Second)
+	}
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status, "the median is back within the threshold")
+}
+
+func TestMonitor_WarmingUp(t *testing.T) {
+	m, now, published := newTestMonitor(Rules{})
+	tick := func(warmingUp bool) {
+		m.Handle(context.Background(), eventbus.Event{Type: eventbus.TickBuilt, Time: *now, Payload: &domain.Tick{WarmingUp: warmingUp}})
+	}
+
+	tick(true)
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameWarmingUp, (*published)[0].Name())
+	assert.Equal(t, SeverityInfo, (*published)[0].Labels[LabelSeverity])
+
+	*now = now.Add(20 * time.Minute)
+	tick(false)
+	m.Evaluate()
+	require.Len(t, *published, 2)
+	assert.Equal(t, StatusResolved, (*published)[1].Status, "warmed up")
+
+	tick(false)
+	m.Evaluate()
+	assert.Len(t, *published, 2)
+}
+
+func TestMonitor_Maintenance(t *testing.T) {
+	calendar, err := maintenance.Parse([]string{"* 00:00-01:00"})
+	require.NoError(t, err)
+	m, now, published := newTestMonitor(Rules{TickGap: 10 * time.Second, ReconnectStormCount: 2, ReconnectStormWindow: time.Hour})
+	m.WithMaintenance(calendar)
+
+	m.Evaluate()
+	for range 3 {
+		m.Handle(context.Background(), eventbus.Event{
+			Type:    eventbus.ImportDegraded,
+			Time:    *now,
+			Payload: eventbus.Degradation{Stage: eventbus.StageLiquidations, Err: errors.New("maintenance")},
+		})
+	}
+	*now = now.Add(59 * time.Minute)
+	m.Evaluate()
+	assert.Empty(t, *published, "suppressed within the window")
+
+	*now = now.Add(time.Minute + 5*time.Second)
+	m.Evaluate()
+	assert.Empty(t, *published, "the tick gap restarts at the end of the window")
+
+	*now = now.Add(10 * time.Second)
+	m.Evaluate()
+	require.Len(t, *published, 1)
+	assert.Equal(t, NameTickGap, (*published)[0].Name())
+}
@@ -0,0 +1,16 @@
+package opsalert
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for counters
+const (
+	// telemetryAlertTransitions counts firing and resolved alert transitions
+	telemetryAlertTransitions = "opsalert.transitions"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryAlertTransitions, Kind: telemetry.KindCounter, Description: "Operational alert transitions", Tags: []string{"alertname", "status"}},
+	}
+}
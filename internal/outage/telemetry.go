@@ -0,0 +1,24 @@
+package outage
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for counters and timings
+const (
+	// telemetryOutages counts ended outages
+	telemetryOutages = "outage.count"
+
+	// telemetryOutageDuration measures how long the outages lasted
+	telemetryOutageDuration = "outage.duration"
+
+	// telemetryOutageStoreErrors counts outages that failed to be stored
+	telemetryOutageStoreErrors = "outage.store.errors"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetryOutages, Kind: telemetry.KindCounter, Description: "Exchange outages", Tags: []string{"stage", "reason"}},
+		{Name: telemetryOutageDuration, Kind: telemetry.KindTiming, Description: "Exchange outage duration", Tags: []string{"stage"}},
+		{Name: telemetryOutageStoreErrors, Kind: telemetry.KindCounter, Description: "Outage records that failed to be stored"},
+	}
+}
@@ -0,0 +1,201 @@
+package outage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"go.uber.org/zap"
+)
+
+// DefaultResolveAfter is how long a stage without a recovery event must not fail for its outage to end.
+// It's well above the reconnect delay of the exchange websockets
+const DefaultResolveAfter = 30 * time.Second
+
+// evaluateInterval is how often quiet outages are resolved
+const evaluateInterval = time.Second
+
+// stage describes how a tracked import stage fails and recovers
+type stage struct {
+	reason      domain.OutageReason
+	recoveredBy eventbus.EventType // event proving data flows again, empty when only the absence of failures does
+}
+
+// stages are the import stages receiving data from the exchange; storage failures are covered by the operational alerts
+var stages = map[string]stage{
+	eventbus.StageFetchTickers:   {reason: domain.OutageHTTPFailure, recoveredBy: eventbus.TickBuilt},
+	eventbus.StageLiquidations:   {reason: domain.OutageWSDisconnect, recoveredBy: eventbus.LiquidationReceived},
+	eventbus.StageSubTicksStream: {reason: domain.OutageWSDisconnect},
+}
+
+// Tracker turns import degradations into outage records.
+// An outage starts with the first failure of a stage and ends with the event proving the stage works again,
+// or once the stage didn't fail for resolveAfter; it's stored when it ends
+type Tracker struct {
+	repo         domain.OutageRepository
+	resolveAfter time.Duration
+	now          func() time.Time
+
+	mu          sync.Mutex
+	open        map[string]*domain.Outage // keyed by stage
+	lastFailure map[string]time.Time
+
+	telemetry telemetry.Provider
+	logger    *zap.Logger
+}
+
+// NewTracker creates a new Tracker, a zero resolveAfter uses DefaultResolveAfter
+func NewTracker(repo domain.OutageRepository, resolveAfter time.Duration, logger *zap.Logger) *Tracker {
+	if resolveAfter <= 0 {
+		resolveAfter = DefaultResolveAfter
+	}
+	return &Tracker{
+		repo:         repo,
+		resolveAfter: resolveAfter,
+		now:          time.Now,
+		open:         make(map[string]*domain.Outage),
+		lastFailure:  make(map[string]time.Time),
+		telemetry:    &telemetry.NoopProvider{},
+		logger:       logger.With(zap.String("component", "outage")),
+	}
+}
+
+// WithTelemetry sets the telemetry provider used to count outages
+func (t *Tracker) WithTelemetry(provider telemetry.Provider) *Tracker {
+	if provider != nil {
+		t.telemetry = provider
+	}
+	return t
+}
+
+// Handle records an importer event, it matches eventbus.Handler
+func (t *Tracker) Handle(ctx context.Context, event eventbus.Event) {
+	var ended []domain.Outage
+
+	t.mu.Lock()
+	switch event.Type {
+	case eventbus.ImportDegraded:
+		if degradation, ok := event.Payload.(eventbus.Degradation); ok {
+			t.fail(degradation, event.Time)
+		}
+	default:
+		for name, s := range stages {
+			if s.recoveredBy == event.Type && t.open[name] != nil {
+				ended = append(ended, t.end(name, event.Time))
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	t.store(ctx, ended)
+}
+
+// fail opens the outage of the degraded stage or extends the open one, it expects t.mu to be held
+func (t *Tracker) fail(degradation eventbus.Degradation, at time.Time) {
+	s, tracked := stages[degradation.Stage]
+	if !tracked {
+		return
+	}
+
+	o := t.open[degradation.Stage]
+	if o == nil {
+		o = &domain.Outage{
+			Exchange: degradation.Exchange,
+			Stage:    degradation.Stage,
+			Reason:   s.reason,
+			StartAt:  at,
+		}
+		t.open[degradation.Stage] = o
+		t.logger.Warn("Exchange outage started",
+			zap.String("stage", degradation.Stage),
+			zap.String("reason", string(s.reason)),
+			zap.Error(degradation.Err),
+		)
+	}
+	o.Failures++
+	if degradation.Err != nil {
+		o.LastError = degradation.Err.Error()
+	}
+	t.lastFailure[degradation.Stage] = at
+}
+
+// end closes the open outage of the stage at the given time, it expects t.mu to be held
+func (t *Tracker) end(stage string, at time.Time) domain.Outage {
+	o := *t.open[stage]
+	o.EndAt = at
+	delete(t.open, stage)
+	delete(t.lastFailure, stage)
+	return o
+}
+
+// Run resolves quiet outages every second until ctx is canceled
+func (t *Tracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(evaluateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Evaluate(ctx)
+		}
+	}
+}
+
+// Evaluate ends the outages whose stage didn't fail for resolveAfter, at the time of their last failure
+func (t *Tracker) Evaluate(ctx context.Context) {
+	now := t.now()
+	var ended []domain.Outage
+
+	t.mu.Lock()
+	for name := range t.open {
+		if last := t.lastFailure[name]; now.Sub(last) >= t.resolveAfter {
+			ended = append(ended, t.end(name, last))
+		}
+	}
+	t.mu.Unlock()
+
+	t.store(ctx, ended)
+}
+
+// Close ends and stores the outages still open, e.g. on shutdown
+func (t *Tracker) Close(ctx context.Context) {
+	now := t.now()
+	var ended []domain.Outage
+
+	t.mu.Lock()
+	for name := range t.open {
+		ended = append(ended, t.end(name, now))
+	}
+	t.mu.Unlock()
+
+	t.store(ctx, ended)
+}
+
+// store persists ended outages
+func (t *Tracker) store(ctx context.Context, outages []domain.Outage) {
+	for _, o := range outages {
+		t.telemetry.IncrementCounter(telemetryOutages, 1,
+			fmt.Sprintf("stage:%s", o.Stage),
+			fmt.Sprintf("reason:%s", o.Reason),
+		)
+		t.telemetry.Timing(telemetryOutageDuration, o.Duration(), fmt.Sprintf("stage:%s", o.Stage))
+		t.logger.Info("Exchange outage ended",
+			zap.String("stage", o.Stage),
+			zap.String("reason", string(o.Reason)),
+			zap.Time("start_at", o.StartAt),
+			zap.Duration("duration", o.Duration()),
+			zap.Int("failures", o.Failures),
+		)
+
+		if err := t.repo.Create(ctx, o); err != nil {
+			t.telemetry.IncrementCounter(telemetryOutageStoreErrors, 1)
+			t.logger.Error("Failed to store outage", zap.String("stage", o.Stage), zap.Error(err))
+		}
+	}
+}
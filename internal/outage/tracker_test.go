@@ -0,0 +1,112 @@
+package outage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	domainMocks "github.com/ayankousky/exchange-data-importer/internal/domain/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestTracker returns a tracker with a manual clock and the stored outages
+func newTestTracker(resolveAfter time.Duration) (*Tracker, *time.Time, *[]domain.Outage) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var stored []domain.Outage
+	repo := &domainMocks.OutageRepositoryMock{
+		CreateFunc: func(ctx context.Context, outage domain.Outage) error {
+			stored = append(stored, outage)
+			return nil
+		},
+	}
+	tracker := NewTracker(repo, resolveAfter, zap.NewNop())
+	tracker.now = func() time.Time { return now }
+	return tracker, &now, &stored
+}
+
+// degraded returns an ImportDegraded event of the stage
+func degraded(stage string, at time.Time, err error) eventbus.Event {
+	return eventbus.Event{
+		Type:    eventbus.ImportDegraded,
+		Time:    at,
+		Payload: eventbus.Degradation{Exchange: "binance", Stage: stage, Err: err},
+	}
+}
+
+func TestTracker_RecoveredByEvent(t *testing.T) {
+	tracker, now, stored := newTestTracker(time.Minute)
+	ctx := context.Background()
+	start := *now
+
+	tracker.Handle(ctx, degraded(eventbus.StageFetchTickers, start, errors.New("503 Service Unavailable")))
+	tracker.Handle(ctx, degraded(eventbus.StageFetchTickers, start.Add(time.Second), errors.New("context deadline exceeded")))
+	tracker.Handle(ctx, eventbus.Event{Type: eventbus.LiquidationReceived, Time: start.Add(2 * time.Second)})
+	assert.Empty(t, *stored, "liquidations don't prove the tickers API works again")
+
+	tracker.Handle(ctx, eventbus.Event{Type: eventbus.TickBuilt, Time: start.Add(3 * time.Second)})
+	require.Len(t, *stored, 1)
+	assert.Equal(t, domain.Outage{
+		Exchange:  "binance",
+		Stage:     eventbus.StageFetchTickers,
+		Reason:    domain.OutageHTTPFailure,
+		StartAt:   start,
+		EndAt:     start.Add(3 * time.Second),
+		Failures:  2,
+		LastError: "context deadline exceeded",
+	}, (*stored)[0])
+
+	tracker.Handle(ctx, eventbus.Event{Type: eventbus.TickBuilt, Time: start.Add(4 * time.Second)})
+	assert.Len(t, *stored, 1, "an outage is stored once")
+}
+
+func TestTracker_ResolvedWhenQuiet(t *testing.T) {
+	tracker, now, stored := newTestTracker(30 * time.Second)
+	ctx := context.Background()
+	start := *now
+
+	tracker.Handle(ctx, degraded(eventbus.StageSubTicksStream, start, errors.New("websocket error")))
+	tracker.Handle(ctx, degraded(eventbus.StageSubTicksStream, start.Add(5*time.Second), errors.New("websocket dial")))
+
+	*now = start.Add(30 * time.Second)
+	tracker.Evaluate(ctx)
+	assert.Empty(t, *stored, "the stage failed 25s ago")
+
+	*now = start.Add(35 * time.Second)
+	tracker.Evaluate(ctx)
+	require.Len(t, *stored, 1)
+	assert.Equal(t, domain.OutageWSDisconnect, (*stored)[0].Reason)
+	assert.Equal(t, start.Add(5*time.Second), (*stored)[0].EndAt, "ends at the last failure")
+	assert.Equal(t, 2, (*stored)[0].Failures)
+}
+
+func TestTracker_IgnoresUntrackedStages(t *testing.T) {
+	tracker, now, stored := newTestTracker(time.Second)
+	ctx := context.Background()
+
+	tracker.Handle(ctx, degraded(eventbus.StageStoreTick, *now, errors.New("db is down")))
+	tracker.Handle(ctx, degraded(eventbus.StageConvertTickers, *now, errors.New("bad ticker")))
+	tracker.Close(ctx)
+
+	assert.Empty(t, *stored)
+}
+
+func TestTracker_Close(t *testing.T) {
+	tracker, now, stored := newTestTracker(time.Minute)
+	ctx := context.Background()
+	start := *now
+
+	tracker.Handle(ctx, degraded(eventbus.StageLiquidations, start, errors.New("websocket error")))
+	tracker.Handle(ctx, degraded(eventbus.StageFetchTickers, start, errors.New("503")))
+
+	*now = start.Add(10 * time.Second)
+	tracker.Close(ctx)
+	require.Len(t, *stored, 2)
+	for _, o := range *stored {
+		assert.Equal(t, start.Add(10*time.Second), o.EndAt, "open outages end at shutdown")
+	}
+}
@@ -0,0 +1,97 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Breaking returns the changes of next breaking consumers of the documents described by prev: removed
+// properties, properties no longer required, changed types, formats and constants. Added properties are compatible
+func Breaking(prev, next *Schema) []string {
+	c := &comparison{
+		prevDefs: prev.Defs,
+		nextDefs: next.Defs,
+		visiting: make(map[string]bool),
+	}
+	c.compare("$", prev, next)
+	return c.changes
+}
+
+// comparison walks two versions of a document side by side
+type comparison struct {
+	prevDefs, nextDefs map[string]*Schema
+	visiting           map[string]bool // pairs of definitions being compared, guards recursive types
+	changes            []string
+}
+
+func (c *comparison) compare(path string, prev, next *Schema) {
+	if prev.Ref != "" && next.Ref != "" {
+		pair := prev.Ref + "|" + next.Ref
+		if c.visiting[pair] {
+			return
+		}
+		c.visiting[pair] = true
+		defer delete(c.visiting, pair)
+	}
+	prev, next = resolve(prev, c.prevDefs), resolve(next, c.nextDefs)
+
+	// consumers of prev only handle its types, a missing type keyword allows any
+	widened := len(prev.Type) > 0 && (len(next.Type) == 0 || slices.ContainsFunc(next.Type, func(t string) bool {
+		return !slices.Contains(prev.Type, t)
+	}))
+	if widened {
+		c.addf("%s: type changed from %s to %s", path, typeName(prev.Type), typeName(next.Type))
+		return
+	}
+	if prev.Format != next.Format {
+		c.addf("%s: format changed from %q to %q", path, prev.Format, next.Format)
+	}
+	if prev.Const != next.Const {
+		c.addf("%s: constant changed from %q to %q", path, prev.Const, next.Const)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(prev.Properties)) {
+		property := path + "." + name
+		nextProperty, ok := next.Properties[name]
+		if !ok {
+			c.addf("%s: removed", property)
+			continue
+		}
+		if slices.Contains(prev.Required, name) && !slices.Contains(next.Required, name) {
+			c.addf("%s: no longer required", property)
+		}
+		c.compare(property, prev.Properties[name], nextProperty)
+	}
+	if prev.Items != nil && next.Items != nil {
+		c.compare(path+"[]", prev.Items, next.Items)
+	}
+	if prev.AdditionalProperties != nil && next.AdditionalProperties != nil {
+		c.compare(path+"{}", prev.AdditionalProperties, next.AdditionalProperties)
+	}
+}
+
+func (c *comparison) addf(format string, args ...any) {
+	c.changes = append(c.changes, fmt.Sprintf(format, args...))
+}
+
+// typeName renders a type keyword for change messages
+func typeName(t Types) string {
+	if len(t) == 0 {
+		return "any"
+	}
+	return strings.Join(t, "|")
+}
+
+// resolve returns the definition a schema refers to, or the schema itself
+func resolve(s *Schema, defs map[string]*Schema) *Schema {
+	name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+	if !ok {
+		return s
+	}
+	if def, ok := defs[name]; ok {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fileExt is the extension of the document files
+const fileExt = ".json"
+
+// Marshal returns the indented JSON of a document, ending with a newline
+func Marshal(doc *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Write writes the document of every payload to dir as <name>.json
+func Write(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create schema dir: %w", err)
+	}
+	for _, p := range Payloads() {
+		data, err := Marshal(Document(p))
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, p.Name+fileExt), data, 0o644); err != nil {
+			return fmt.Errorf("write schema %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Check compares the documents of a previous version in dir with the current payloads and returns
+// the breaking changes, prefixed with the file name. Payloads missing from dir are new and compatible
+func Check(dir string) ([]string, error) {
+	current := make(map[string]*Schema)
+	for _, p := range Payloads() {
+		current[p.Name] = Document(p)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fileExt))
+	if err != nil {
+		return nil, fmt.Errorf("list schemas: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no schemas in %s: %w", dir, fs.ErrNotExist)
+	}
+
+	var changes []string
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), fileExt)
+		prev, err := read(file)
+		if err != nil {
+			return nil, err
+		}
+		next, ok := current[name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s: payload removed", name))
+			continue
+		}
+		for _, change := range Breaking(prev, next) {
+			changes = append(changes, fmt.Sprintf("%s: %s", name, change))
+		}
+	}
+	return changes, nil
+}
+
+// read loads a document file
+func read(file string) (*Schema, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	var doc Schema
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse schema %s: %w", filepath.Base(file), err)
+	}
+	return &doc, nil
+}
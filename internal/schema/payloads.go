@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"reflect"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier/strategies"
+)
+
+// idPrefix prefixes the $id of the documents, followed by the file name
+const idPrefix = "urn:exchange-data-importer:schema:"
+
+// Payload describes a published event payload
+type Payload struct {
+	Name        string         // file name of the document without extension
+	Topic       notifier.Topic // notifier topic the payload is sent on, empty for payloads stored or consumed in-process
+	Description string
+	Type        reflect.Type
+}
+
+// Payloads returns the published payloads: the data of every notifier topic wrapped in its notify.Event envelope,
+// and the stored ticks and liquidations
+func Payloads() []Payload {
+	return []Payload{
+		{
+			Name:        "market_data",
+			Topic:       notifier.MarketDataTopic,
+			Description: "One event per ticker of a tick, the tick is sent without its tickers",
+			Type:        reflect.TypeFor[strategies.TickerNotification](),
+		},
+		{
+			Name:        "market_data_digest",
+			Topic:       notifier.MarketDataTopic,
+			Description: "All tickers of a tick in a single event (NOTIFY_REDIS_MARKET_DATA_FORMAT=digest), the tick is sent once without its tickers",
+			Type:        reflect.TypeFor[strategies.MarketDataDigest](),
+		},
+		{
+			Name:        "alert_market_state",
+			Topic:       notifier.AlertTopic,
+			Description: "Human-readable HTML message listing the abnormal market moves of a tick",
+			Type:        reflect.TypeFor[string](),
+		},
+		{
+			Name:        "tick_info",
+			Topic:       notifier.TickInfoTopic,
+			Description: "Human-readable line with the market averages of a tick",
+			Type:        reflect.TypeFor[string](),
+		},
+		{
+			Name:        "time_series",
+			Topic:       notifier.TimeSeriesTopic,
+			Description: "InfluxDB line protocol with the tick and ticker measurements of a tick",
+			Type:        reflect.TypeFor[string](),
+		},
+		{
+			Name:        "ops_alert",
+			Topic:       notifier.OpsAlertTopic,
+			Description: "Alertmanager webhook payload of an operational alert firing or resolving",
+			Type:        reflect.TypeFor[strategies.AlertmanagerMessage](),
+		},
+		{
+			Name:        "minute_bars",
+			Topic:       notifier.BarsTopic,
+			Description: "Finalized 1-minute bar of a symbol",
+			Type:        reflect.TypeFor[domain.Bar](),
+		},
+		{
+			Name:        "annotations",
+			Topic:       notifier.AnnotationTopic,
+			Description: "Grafana annotation of an operational alert, a region once it's resolved",
+			Type:        reflect.TypeFor[strategies.GrafanaAnnotation](),
+		},
+		{
+			Name:        "tick",
+			Description: "Snapshot of the tickers of an exchange with market averages, as stored",
+			Type:        reflect.TypeFor[domain.Tick](),
+		},
+		{
+			Name:        "liquidation",
+			Description: "Forced order of an exchange, as stored",
+			Type:        reflect.TypeFor[domain.Liquidation](),
+		},
+	}
+}
+
+// Document returns the schema document of a payload. Topic payloads are described with
+// the envelope the notifiers send, data holding the payload
+func Document(p Payload) *Schema {
+	doc := For(p.Type)
+	if p.Topic != "" {
+		data := doc
+		doc = &Schema{
+			Type: Types{"object"},
+			Properties: map[string]*Schema{
+				"ct":         {Type: Types{"string"}, Format: "date-time"},
+				"event_type": {Type: Types{"string"}, Const: string(p.Topic)},
+				"data":       data,
+			},
+			Required: []string{"ct", "data", "event_type"},
+			Defs:     data.Defs,
+		}
+		data.Defs = nil
+	}
+
+	doc.Schema = Dialect
+	doc.ID = idPrefix + p.Name
+	doc.Title = p.Name
+	if p.Topic != "" {
+		doc.Title = string(p.Topic)
+	}
+	doc.Description = p.Description
+	return doc
+}
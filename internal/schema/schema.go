@@ -0,0 +1,155 @@
+// Package schema describes the published event payloads as JSON Schema documents,
+// so downstream consumers can generate their code and detect breaking changes before deploys
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Dialect is the JSON Schema version of the generated documents
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema, limited to the keywords the payloads need
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Const                string             `json:"const,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Types is the type keyword, written as a string when it holds a single type
+type Types []string
+
+// MarshalJSON writes a single type as a string
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON reads a type written as a string or a list
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// nullable returns the types with null added, nil maps and slices are written as null
+func nullable(t ...string) Types {
+	return append(Types(t), "null")
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// For returns the schema of the JSON encoding of a Go type. Named structs referenced by the type are
+// put in $defs, fields without omitempty are required as encoding/json always writes them
+func For(t reflect.Type) *Schema {
+	g := &generator{defs: make(map[string]*Schema)}
+	root := g.schemaOf(t)
+	if name, ok := strings.CutPrefix(root.Ref, "#/$defs/"); ok {
+		root = g.defs[name]
+		delete(g.defs, name)
+	}
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+// generator collects the definitions of the named structs of a document
+type generator struct {
+	defs map[string]*Schema
+}
+
+// schemaOf returns the schema of a type, named structs are returned as references
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaOf(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json writes byte slices as base64 strings
+			return &Schema{Type: nullable("string"), ContentEncoding: "base64"}
+		}
+		return &Schema{Type: nullable("array"), Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: nullable("object"), AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // reserved while the fields are walked
+			g.defs[t.Name()] = g.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	default:
+		return &Schema{} // any value
+	}
+}
+
+// object returns the schema of the fields of a struct as encoding/json writes them
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Title: t.Name(), Type: Types{"object"}, Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// fields of untagged embedded structs are promoted, even when the struct type is unexported
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := g.object(field.Type)
+			for key, property := range embedded.Properties {
+				s.Properties[key] = property
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schemaOf(field.Type)
+		if !slices.Contains(strings.Split(opts, ","), "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+	slices.Sort(s.Required)
+	return s
+}
@@ -0,0 +1,157 @@
+package schema
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testLevel struct {
+	Code int `json:"code"`
+}
+
+type testEmbedded struct {
+	Source string `json:"src,omitempty"`
+}
+
+type testPayload struct {
+	testEmbedded
+	Name     string               `json:"name"`
+	At       time.Time            `json:"at"`
+	Price    float64              `json:"p,omitempty"`
+	Level    *testLevel           `json:"level"`
+	Levels   []testLevel          `json:"levels"`
+	ByName   map[string]testLevel `json:"by_name,omitempty"`
+	Extra    any                  `json:"extra"`
+	Blob     []byte               `json:"blob,omitempty"`
+	Untagged bool
+	Skipped  string `json:"-"`
+	hidden   string
+}
+
+func TestFor(t *testing.T) {
+	doc := For(reflect.TypeFor[testPayload]())
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"title": "testPayload",
+		"type": "object",
+		"properties": {
+			"src": {"type": "string"},
+			"name": {"type": "string"},
+			"at": {"type": "string", "format": "date-time"},
+			"p": {"type": "number"},
+			"level": {"$ref": "#/$defs/testLevel"},
+			"levels": {"type": ["array", "null"], "items": {"$ref": "#/$defs/testLevel"}},
+			"by_name": {"type": ["object", "null"], "additionalProperties": {"$ref": "#/$defs/testLevel"}},
+			"extra": {},
+			"blob": {"type": ["string", "null"], "contentEncoding": "base64"},
+			"Untagged": {"type": "boolean"}
+		},
+		"required": ["Untagged", "at", "extra", "level", "levels", "name"],
+		"$defs": {
+			"testLevel": {
+				"title": "testLevel",
+				"type": "object",
+				"properties": {"code": {"type": "integer"}},
+				"required": ["code"]
+			}
+		}
+	}`, string(data))
+}
+
+func TestTypes_JSON(t *testing.T) {
+	var doc Schema
+	require.NoError(t, json.Unmarshal([]byte(`{"type": "string", "items": {"type": ["array", "null"]}}`), &doc))
+	assert.Equal(t, Types{"string"}, doc.Type)
+	assert.Equal(t, Types{"array", "null"}, doc.Items.Type)
+}
+
+func TestBreaking(t *testing.T) {
+	prev := For(reflect.TypeFor[testPayload]())
+	assert.Empty(t, Breaking(prev, For(reflect.TypeFor[testPayload]())))
+
+	type testLevelV2 struct {
+		Code  string `json:"code"`
+		Label string `json:"label"`
+	}
+	type testPayloadV2 struct {
+		Name   string                 `json:"name,omitempty"`
+		At     int64                  `json:"at"`
+		Level  testLevelV2            `json:"level"`
+		Levels []testLevelV2          `json:"levels"`
+		ByName map[string]testLevelV2 `json:"by_name,omitempty"`
+		Extra  string                 `json:"extra"`
+		Added  bool                   `json:"added"`
+	}
+
+	assert.Equal(t, []string{
+		`$.Untagged: removed`,
+		`$.at: type changed from string to integer`,
+		`$.blob: removed`,
+		`$.by_name{}.code: type changed from integer to string`,
+		`$.level.code: type changed from integer to string`,
+		`$.levels[].code: type changed from integer to string`,
+		`$.name: no longer required`,
+		`$.p: removed`,
+		`$.src: removed`,
+	}, Breaking(prev, For(reflect.TypeFor[testPayloadV2]())), "added properties and narrowed types are compatible")
+}
+
+func TestPayloads(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, p := range Payloads() {
+		assert.False(t, seen[p.Name], "duplicate payload %s", p.Name)
+		seen[p.Name] = true
+		if p.Topic != "" {
+			assert.NoError(t, p.Topic.Validate(), p.Name)
+		}
+	}
+}
+
+// TestSchemaFilesUpToDate fails when a payload changes without regenerating the schema files with `go run ./cmd/schema`
+func TestSchemaFilesUpToDate(t *testing.T) {
+	dir := filepath.Join("..", "..", "schema")
+
+	for _, p := range Payloads() {
+		want, err := Marshal(Document(p))
+		require.NoError(t, err)
+		got, err := os.ReadFile(filepath.Join(dir, p.Name+fileExt))
+		require.NoError(t, err, "schema file of %s is missing, run `go run ./cmd/schema`", p.Name)
+		assert.Equal(t, string(want), string(got), "schema of %s is outdated, run `go run ./cmd/schema`", p.Name)
+	}
+
+	changes, err := Check(dir)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, Write(dir))
+
+	changes, err := Check(dir)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	prev := Document(Payloads()[0])
+	prev.Properties["removed"] = &Schema{Type: Types{"string"}}
+	data, err := Marshal(prev)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, Payloads()[0].Name+fileExt), data, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "legacy.json"), []byte(`{"type": "object"}`), 0o644))
+
+	changes, err = Check(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy: payload removed", "market_data: $.removed: removed"}, changes)
+
+	_, err = Check(t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package selfcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+)
+
+// Tickers fetches the tickers of the exchange once, it fails when the exchange returns none. Tickers the exchange
+// client couldn't convert are reported in the detail, the importer drops them too and builds the tick of the others
+func Tickers(exchange exchanges.Exchange) Probe {
+	return Probe{
+		Name:   "tickers",
+		Target: exchange.GetName(),
+		Run: func(ctx context.Context) (string, error) {
+			tickers, err := exchange.FetchTickers(ctx)
+			var conversionErr *exchanges.ConversionError
+			if err != nil && !errors.As(err, &conversionErr) {
+				return "", err
+			}
+			if len(tickers) == 0 {
+				if err != nil {
+					return "", err
+				}
+				return "", errors.New("no ticker returned")
+			}
+			if conversionErr != nil {
+				return fmt.Sprintf("%d tickers, %d of %d dropped: %v", len(tickers), conversionErr.Failed, conversionErr.Total, errors.Join(conversionErr.Errors...)), nil
+			}
+			return fmt.Sprintf("%d tickers", len(tickers)), nil
+		},
+	}
+}
+
+// Liquidations subscribes to the liquidations of the exchange for window. It fails when the stream reports
+// an error or closes early; a quiet market streaming no liquidation within the window passes
+func Liquidations(exchange exchanges.Exchange, window time.Duration) Probe {
+	return Probe{
+		Name:   "liquidations",
+		Target: exchange.GetName(),
+		Wait:   window,
+		Run: func(ctx context.Context) (string, error) {
+			streamCtx, cancel := context.WithTimeout(ctx, window)
+			defer cancel()
+
+			liquidations, errs := exchange.SubscribeLiquidations(streamCtx)
+			var received int
+			for {
+				select {
+				case _, ok := <-liquidations:
+					if !ok {
+						if streamCtx.Err() == nil {
+							return "", fmt.Errorf("stream closed after %d liquidations", received)
+						}
+						return fmt.Sprintf("%d liquidations within %s", received, window), nil
+					}
+					received++
+				case err, ok := <-errs:
+					if ok && err != nil {
+						return "", fmt.Errorf("stream: %w", err)
+					}
+					errs = nil // closed, keep reading the liquidations
+				case <-streamCtx.Done():
+					return fmt.Sprintf("%d liquidations within %s", received, window), nil
+				}
+			}
+		},
+	}
+}
+
+// Repository runs the write/read probe of a repository
+func Repository(target string, probe func(ctx context.Context) error) Probe {
+	return Probe{
+		Name:   "repository",
+		Target: target,
+		Run: func(ctx context.Context) (string, error) {
+			if err := probe(ctx); err != nil {
+				return "", err
+			}
+			return "wrote, read back and deleted a probe record", nil
+		},
+	}
+}
+
+// Notification sends a test event with a notifier client
+func Notification(target string, client notify.Client, event notify.Event) Probe {
+	return Probe{
+		Name:   "notification",
+		Target: target,
+		Run: func(ctx context.Context) (string, error) {
+			if err := client.Send(ctx, event); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("test event sent as %s", event.EventType), nil
+		},
+	}
+}
@@ -0,0 +1,103 @@
+// Package selfcheck verifies the wiring of an importer against the live services it depends on: the exchange,
+// the repository and the notifiers, reporting what passed and what failed
+package selfcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Probe checks a single dependency
+type Probe struct {
+	Name   string        // kind of check, e.g. tickers
+	Target string        // what is checked, e.g. the exchange or notifier name
+	Wait   time.Duration // time the probe listens for, added to the timeout of the run
+	Run    func(ctx context.Context) (string, error)
+}
+
+// Result is the outcome of a probe
+type Result struct {
+	Name     string        `json:"name"`
+	Target   string        `json:"target"`
+	Passed   bool          `json:"passed"`
+	Detail   string        `json:"detail"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Report holds the results of every probe in the order they ran
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Passed reports whether every probe passed
+func (r Report) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the number of probes that failed
+func (r Report) Failed() int {
+	var failed int
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Run runs the probes one after the other, each bounded by timeout plus the time it listens for.
+// A failing probe doesn't stop the others, so the report lists every problem at once
+func Run(ctx context.Context, probes []Probe, timeout time.Duration) Report {
+	report := Report{Results: make([]Result, 0, len(probes))}
+	for _, probe := range probes {
+		report.Results = append(report.Results, run(ctx, probe, timeout))
+	}
+	return report
+}
+
+func run(ctx context.Context, probe Probe, timeout time.Duration) Result {
+	probeCtx, cancel := context.WithTimeout(ctx, timeout+probe.Wait)
+	defer cancel()
+
+	startedAt := time.Now()
+	detail, err := probe.Run(probeCtx)
+	result := Result{
+		Name:     probe.Name,
+		Target:   probe.Target,
+		Passed:   err == nil,
+		Detail:   detail,
+		Duration: time.Since(startedAt),
+	}
+	if err != nil {
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// Print writes the report as an aligned table followed by a summary line
+func Print(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESULT\tCHECK\tTARGET\tDURATION\tDETAIL")
+	for _, result := range report.Results {
+		status := "PASS"
+		if !result.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", status, result.Name, result.Target, result.Duration.Round(time.Millisecond), result.Detail)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("print report: %w", err)
+	}
+
+	failed := report.Failed()
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed\n", len(report.Results)-failed, failed)
+	return err
+}
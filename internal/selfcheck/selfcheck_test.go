@@ -0,0 +1,147 @@
+package selfcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	exchangeMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	notifyMocks "github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify/mocks"
+)
+
+func newExchange(tickers []exchanges.Ticker, subscribe func(ctx context.Context) (<-chan exchanges.Liquidation, <-chan error)) *exchangeMocks.ExchangeMock {
+	return &exchangeMocks.ExchangeMock{
+		GetNameFunc: func() string { return "binance-perp" },
+		FetchTickersFunc: func(ctx context.Context) ([]exchanges.Ticker, error) {
+			return tickers, nil
+		},
+		SubscribeLiquidationsFunc: subscribe,
+	}
+}
+
+// stream returns a subscription sending the liquidations, then the error if any, and closing on ctx done
+func stream(liquidations int, streamErr error) func(ctx context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+	return func(ctx context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+		liqCh := make(chan exchanges.Liquidation)
+		errCh := make(chan error, 1)
+		go func() {
+			defer close(liqCh)
+			defer close(errCh)
+			for range liquidations {
+				select {
+				case liqCh <- exchanges.Liquidation{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if streamErr != nil {
+				errCh <- streamErr
+			}
+			<-ctx.Done()
+		}()
+		return liqCh, errCh
+	}
+}
+
+func TestRun(t *testing.T) {
+	exchange := newExchange([]exchanges.Ticker{{Symbol: "BTCUSDT"}, {Symbol: "ETHUSDT"}}, stream(3, nil))
+	sent := &notifyMocks.ClientMock{SendFunc: func(ctx context.Context, event notify.Event) error { return nil }}
+	failing := &notifyMocks.ClientMock{SendFunc: func(ctx context.Context, event notify.Event) error {
+		return errors.New("connection refused")
+	}}
+	event := notify.Event{EventType: "SELF_CHECK", Data: "self-check"}
+
+	report := Run(context.Background(), []Probe{
+		Tickers(exchange),
+		Liquidations(exchange, 50*time.Millisecond),
+		Repository("sqlite", func(ctx context.Context) error { return nil }),
+		Notification("redis", sent, event),
+		Notification("telegram", failing, event),
+	}, time.Second)
+
+	require.Len(t, report.Results, 5)
+	assert.False(t, report.Passed())
+	assert.Equal(t, 1, report.Failed())
+
+	assert.Equal(t, "2 tickers", report.Results[0].Detail)
+	assert.Equal(t, "3 liquidations within 50ms", report.Results[1].Detail)
+	assert.True(t, report.Results[1].Passed)
+	assert.True(t, report.Results[2].Passed)
+	assert.Equal(t, "test event sent as SELF_CHECK", report.Results[3].Detail)
+	require.Len(t, sent.SendCalls(), 1)
+	assert.Equal(t, event, sent.SendCalls()[0].Event)
+
+	assert.False(t, report.Results[4].Passed)
+	assert.Equal(t, "connection refused", report.Results[4].Detail)
+}
+
+func TestTickers_NoTicker(t *testing.T) {
+	report := Run(context.Background(), []Probe{Tickers(newExchange(nil, nil))}, time.Second)
+	assert.False(t, report.Passed())
+	assert.Equal(t, "no ticker returned", report.Results[0].Detail)
+}
+
+func TestTickers_ConversionError(t *testing.T) {
+	exchange := newExchange(nil, nil)
+	exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+		return []exchanges.Ticker{{Symbol: "BTCUSDT"}}, exchanges.NewConversionError(2, []error{errors.New("ETHUSDT: invalid ask")})
+	}
+	report := Run(context.Background(), []Probe{Tickers(exchange)}, time.Second)
+	assert.True(t, report.Passed(), "usable tickers pass")
+	assert.Equal(t, "1 tickers, 1 of 2 dropped: ETHUSDT: invalid ask", report.Results[0].Detail)
+
+	exchange.FetchTickersFunc = func(ctx context.Context) ([]exchanges.Ticker, error) {
+		return nil, fmt.Errorf("fetching: %w", exchanges.NewConversionError(1, []error{errors.New("BTCUSDT: invalid ask")}))
+	}
+	report = Run(context.Background(), []Probe{Tickers(exchange)}, time.Second)
+	assert.False(t, report.Passed(), "no usable ticker fails")
+	assert.Equal(t, "fetching: failed to convert 1 of 1 tickers: BTCUSDT: invalid ask", report.Results[0].Detail)
+}
+
+func TestLiquidations(t *testing.T) {
+	t.Run("stream error", func(t *testing.T) {
+		exchange := newExchange(nil, stream(1, errors.New("handshake failed")))
+		report := Run(context.Background(), []Probe{Liquidations(exchange, time.Second)}, time.Second)
+		assert.False(t, report.Passed())
+		assert.Equal(t, "stream: handshake failed", report.Results[0].Detail)
+	})
+
+	t.Run("stream closed early", func(t *testing.T) {
+		exchange := newExchange(nil, func(ctx context.Context) (<-chan exchanges.Liquidation, <-chan error) {
+			liqCh := make(chan exchanges.Liquidation)
+			close(liqCh)
+			return liqCh, make(chan error)
+		})
+		report := Run(context.Background(), []Probe{Liquidations(exchange, time.Second)}, time.Second)
+		assert.False(t, report.Passed())
+		assert.Equal(t, "stream closed after 0 liquidations", report.Results[0].Detail)
+	})
+
+	t.Run("quiet market", func(t *testing.T) {
+		exchange := newExchange(nil, stream(0, nil))
+		report := Run(context.Background(), []Probe{Liquidations(exchange, 20*time.Millisecond)}, time.Second)
+		assert.True(t, report.Passed())
+		assert.Equal(t, "0 liquidations within 20ms", report.Results[0].Detail)
+	})
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, Report{Results: []Result{
+		{Name: "tickers", Target: "binance-perp", Passed: true, Detail: "512 tickers", Duration: 120 * time.Millisecond},
+		{Name: "notification", Target: "telegram", Detail: "connection refused"},
+	}}))
+
+	out := buf.String()
+	assert.Regexp(t, `PASS\s+tickers\s+binance-perp\s+120ms\s+512 tickers`, out)
+	assert.Regexp(t, `FAIL\s+notification\s+telegram\s+0s\s+connection refused`, out)
+	assert.Contains(t, out, "1 passed, 1 failed")
+}
@@ -0,0 +1,108 @@
+package soak
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+)
+
+// retryStats is implemented by the clients with a retry queue
+type retryStats interface {
+	RetryStats() (queued int, dropped int64)
+}
+
+// Subscription is a client subscribed to a topic with the strategy formatting its events
+type Subscription struct {
+	Name     string // client name used in the report, e.g. redis
+	Topic    string
+	Client   notify.Client
+	Strategy notify.Strategy
+}
+
+// ClientStats reports the deliveries of a subscription
+type ClientStats struct {
+	Name  string
+	Topic string
+
+	Sent     int64         // events delivered
+	Queued   int64         // events that failed and were queued for a retry
+	Failed   int64         // events that failed without a retry
+	TimedOut int64         // failed events that ran out of time
+	Latency  time.Duration // average duration of a Send
+
+	// RetryQueue and RetryDropped are the events waiting in the retry queue of the client and the events it dropped,
+	// clients subscribed to several topics share their queue
+	RetryQueue   int
+	RetryDropped int64
+}
+
+// Attempts returns the number of events handed to the client
+func (s ClientStats) Attempts() int64 {
+	return s.Sent + s.Queued + s.Failed
+}
+
+// Throughput returns the events delivered per second
+func (s ClientStats) Throughput(elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Sent) / elapsed.Seconds()
+}
+
+// DropRate returns the % of the events handed to the client that were lost: failed or dropped by the retry queue
+func (s ClientStats) DropRate() float64 {
+	if s.Attempts() == 0 {
+		return 0
+	}
+	return float64(s.Failed+s.RetryDropped) / float64(s.Attempts()) * 100
+}
+
+// countingClient wraps a client and counts the outcome of every Send
+type countingClient struct {
+	sub Subscription
+
+	sent, queued, failed, timedOut atomic.Int64
+	latency                        atomic.Int64 // total nanoseconds spent in Send
+}
+
+// Send delivers the event with the wrapped client
+func (c *countingClient) Send(ctx context.Context, event notify.Event) error {
+	start := time.Now()
+	err := c.sub.Client.Send(ctx, event)
+	c.latency.Add(int64(time.Since(start)))
+
+	switch {
+	case err == nil:
+		c.sent.Add(1)
+	case errors.Is(err, notify.ErrQueuedForRetry):
+		c.queued.Add(1)
+	default:
+		c.failed.Add(1)
+		if errors.Is(err, context.DeadlineExceeded) {
+			c.timedOut.Add(1)
+		}
+	}
+	return err
+}
+
+// stats returns the counters of the client so far
+func (c *countingClient) stats() ClientStats {
+	s := ClientStats{
+		Name:     c.sub.Name,
+		Topic:    c.sub.Topic,
+		Sent:     c.sent.Load(),
+		Queued:   c.queued.Load(),
+		Failed:   c.failed.Load(),
+		TimedOut: c.timedOut.Load(),
+	}
+	if attempts := s.Attempts(); attempts > 0 {
+		s.Latency = time.Duration(c.latency.Load() / attempts)
+	}
+	if retries, ok := c.sub.Client.(retryStats); ok {
+		s.RetryQueue, s.RetryDropped = retries.RetryStats()
+	}
+	return s
+}
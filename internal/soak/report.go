@@ -0,0 +1,23 @@
+package soak
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Print renders the report as a table with one row per client
+func (r Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "elapsed %s, %d ticks published (%.1f/s), %d waiting, %d dropped by the event bus\n",
+		r.Elapsed.Round(time.Second), r.Published, r.TickRate(), r.Backlog, r.Dropped)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CLIENT\tTOPIC\tSENT\tEVENTS/S\tLATENCY\tQUEUED\tFAILED\tTIMEOUTS\tRETRY QUEUE\tRETRY DROPS\tDROP %")
+	for _, c := range r.Clients {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f\t%s\t%d\t%d\t%d\t%d\t%d\t%.2f\n",
+			c.Name, c.Topic, c.Sent, c.Throughput(r.Elapsed), c.Latency.Round(time.Microsecond),
+			c.Queued, c.Failed, c.TimedOut, c.RetryQueue, c.RetryDropped, c.DropRate())
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,147 @@
+// Package soak drives the notifier stack with synthetic ticks to measure how the configured clients
+// keep up with a given tick rate and symbol count, before the market does it for real
+package soak
+
+import (
+	"context"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/eventbus"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"go.uber.org/zap"
+)
+
+// subscriberName is the event bus subscription of the notifier
+const subscriberName = "notifier"
+
+// Config configures a soak run
+type Config struct {
+	Rate           float64       // ticks published per second
+	Symbols        int           // tickers per tick
+	Duration       time.Duration // how long ticks are published
+	ReportInterval time.Duration // interval of the progress reports, 0 disables them
+	SendTimeout    time.Duration // per-client Send timeout, notifier.DefaultSendTimeout if zero
+	Seed           uint64        // the same seed always produces the same ticks
+}
+
+// Report is the state of a soak run
+type Report struct {
+	Elapsed   time.Duration
+	Published int64 // ticks published on the event bus
+	Backlog   int   // ticks waiting for the notifier in the event bus
+	Dropped   int64 // ticks dropped by the event bus because the notifier lagged behind
+	Clients   []ClientStats
+}
+
+// TickRate returns the ticks published per second
+func (r Report) TickRate() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Published) / r.Elapsed.Seconds()
+}
+
+// Runner publishes synthetic ticks on an event bus feeding the notifier, like the importer does
+type Runner struct {
+	cfg     Config
+	clients []*countingClient
+	logger  *zap.Logger
+
+	// onReport receives the progress reports
+	onReport func(Report)
+}
+
+// New creates a Runner delivering the ticks to the subscriptions
+func New(cfg Config, subscriptions []Subscription, logger *zap.Logger) *Runner {
+	r := &Runner{
+		cfg:      cfg,
+		logger:   logger.With(zap.String("component", "soak")),
+		onReport: func(Report) {},
+	}
+	for _, sub := range subscriptions {
+		r.clients = append(r.clients, &countingClient{sub: sub})
+	}
+	return r
+}
+
+// OnReport sets the function receiving the progress reports
+func (r *Runner) OnReport(fn func(Report)) *Runner {
+	if fn != nil {
+		r.onReport = fn
+	}
+	return r
+}
+
+// Run publishes ticks until the duration elapsed or ctx is cancelled, waits for the notifier to drain
+// the event bus and returns the final report
+func (r *Runner) Run(ctx context.Context) Report {
+	n := notifier.New(r.logger)
+	if r.cfg.SendTimeout > 0 {
+		n.WithSendTimeout(r.cfg.SendTimeout)
+	}
+	for _, c := range r.clients {
+		if err := n.Subscribe(c.sub.Topic, c, c.sub.Strategy); err != nil {
+			r.logger.Error("Failed to subscribe soak client", zap.String("client", c.sub.Name), zap.Error(err))
+		}
+	}
+
+	bus := eventbus.New(r.logger)
+	bus.Subscribe(subscriberName, func(ctx context.Context, event eventbus.Event) {
+		n.Notify(ctx, event.Payload)
+	}, eventbus.TickBuilt)
+
+	runCtx, cancel := context.WithTimeout(ctx, r.cfg.Duration)
+	defer cancel()
+
+	gen := newGenerator(r.cfg.Symbols, r.cfg.Seed)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / r.cfg.Rate))
+	defer ticker.Stop()
+	reports := make(<-chan time.Time)
+	if r.cfg.ReportInterval > 0 {
+		reportTicker := time.NewTicker(r.cfg.ReportInterval)
+		defer reportTicker.Stop()
+		reports = reportTicker.C
+	}
+
+	start := time.Now()
+	var published int64
+	r.logger.Info("Soak test started",
+		zap.Float64("rate", r.cfg.Rate),
+		zap.Int("symbols", r.cfg.Symbols),
+		zap.Duration("duration", r.cfg.Duration),
+		zap.Int("clients", len(r.clients)),
+	)
+
+loop:
+	for {
+		select {
+		case <-runCtx.Done():
+			break loop
+		case at := <-ticker.C:
+			bus.Publish(eventbus.TickBuilt, gen.next(at))
+			published++
+		case <-reports:
+			r.onReport(r.report(bus, start, published))
+		}
+	}
+
+	bus.Close()
+	return r.report(bus, start, published)
+}
+
+// report collects the state of the event bus and of every client
+func (r *Runner) report(bus *eventbus.Bus, start time.Time, published int64) Report {
+	report := Report{
+		Elapsed:   time.Since(start),
+		Published: published,
+	}
+	for _, stats := range bus.Stats() {
+		if stats.Name == subscriberName {
+			report.Backlog, report.Dropped = stats.Queued, stats.Dropped
+		}
+	}
+	for _, c := range r.clients {
+		report.Clients = append(report.Clients, c.stats())
+	}
+	return report
+}
@@ -0,0 +1,131 @@
+package soak
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify/mocks"
+	"github.com/ayankousky/exchange-data-importer/internal/notifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// retryingClient queues every event for a retry and reports half of them as dropped by its retry queue
+type retryingClient struct {
+	mocks.ClientMock
+}
+
+func (c *retryingClient) RetryStats() (int, int64) {
+	calls := len(c.SendCalls())
+	return calls - calls/2, int64(calls / 2)
+}
+
+func TestRunner_Run(t *testing.T) {
+	perTicker := &mocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			tick := data.(*domain.Tick)
+			events := make([]notify.Event, 0, len(tick.Data))
+			for _, ticker := range tick.Data {
+				events = append(events, notify.Event{EventType: string(notifier.MarketDataTopic), Data: ticker})
+			}
+			return events
+		},
+	}
+	perTick := &mocks.StrategyMock{
+		FormatFunc: func(data any) []notify.Event {
+			return []notify.Event{{EventType: string(notifier.TickInfoTopic), Data: "tick"}}
+		},
+	}
+
+	fast := &mocks.ClientMock{SendFunc: func(ctx context.Context, event notify.Event) error { return nil }}
+	failing := &mocks.ClientMock{SendFunc: func(ctx context.Context, event notify.Event) error { return errors.New("down") }}
+	retrying := &retryingClient{mocks.ClientMock{SendFunc: func(ctx context.Context, event notify.Event) error {
+		return fmt.Errorf("%w: rate limited", notify.ErrQueuedForRetry)
+	}}}
+
+	var progress []Report
+	report := New(Config{Rate: 100, Symbols: 5, Duration: 300 * time.Millisecond, ReportInterval: 100 * time.Millisecond}, []Subscription{
+		{Name: "fast", Topic: string(notifier.MarketDataTopic), Client: fast, Strategy: perTicker},
+		{Name: "failing", Topic: string(notifier.TickInfoTopic), Client: failing, Strategy: perTick},
+		{Name: "retrying", Topic: string(notifier.TickInfoTopic), Client: retrying, Strategy: perTick},
+	}, zap.NewNop()).OnReport(func(r Report) {
+		progress = append(progress, r)
+	}).Run(context.Background())
+
+	require.Positive(t, report.Published)
+	assert.NotEmpty(t, progress)
+	assert.Zero(t, report.Backlog, "the event bus is drained at the end")
+	delivered := report.Published - report.Dropped
+
+	require.Len(t, report.Clients, 3)
+	assert.Equal(t, ClientStats{Name: "fast", Topic: "MARKET_DATA", Sent: delivered * 5}, withoutLatency(report.Clients[0]))
+	assert.InDelta(t, float64(delivered*5)/report.Elapsed.Seconds(), report.Clients[0].Throughput(report.Elapsed), 0.001)
+	assert.Zero(t, report.Clients[0].DropRate())
+
+	assert.Equal(t, ClientStats{Name: "failing", Topic: "TICK_INFO", Failed: delivered}, withoutLatency(report.Clients[1]))
+	assert.Equal(t, 100.0, report.Clients[1].DropRate())
+
+	retried := withoutLatency(report.Clients[2])
+	assert.Equal(t, delivered, retried.Queued)
+	assert.Equal(t, delivered/2, retried.RetryDropped)
+	assert.Equal(t, int(delivered-delivered/2), retried.RetryQueue)
+}
+
+func TestRunner_RunStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	report := New(Config{Rate: 1, Symbols: 1, Duration: time.Minute}, nil, zap.NewNop()).Run(ctx)
+	assert.Zero(t, report.Published)
+}
+
+func TestReport_Print(t *testing.T) {
+	report := Report{
+		Elapsed:   10 * time.Second,
+		Published: 20,
+		Dropped:   1,
+		Clients:   []ClientStats{{Name: "redis", Topic: "MARKET_DATA", Sent: 90, Failed: 10, Latency: time.Millisecond}},
+	}
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+	assert.Equal(t, "elapsed 10s, 20 ticks published (2.0/s), 0 waiting, 1 dropped by the event bus\n"+
+		"CLIENT  TOPIC        SENT  EVENTS/S  LATENCY  QUEUED  FAILED  TIMEOUTS  RETRY QUEUE  RETRY DROPS  DROP %\n"+
+		"redis   MARKET_DATA  90    9.0       1ms      0       10      0         0            0            10.00\n", buf.String())
+}
+
+func TestGenerator_Next(t *testing.T) {
+	gen := newGenerator(3, 1)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var tick *domain.Tick
+	for i := 0; i < pathLength; i++ {
+		tick = gen.next(start.Add(time.Duration(i) * time.Second))
+	}
+
+	require.Len(t, tick.Data, 3)
+	for _, symbol := range []domain.TickerName{"SOAK0USDT", "SOAK1USDT", "SOAK2USDT"} {
+		ticker := tick.Data[symbol]
+		require.NotNil(t, ticker, symbol)
+		assert.Positive(t, ticker.Ask)
+		assert.Less(t, ticker.Bid, ticker.Ask)
+		assert.LessOrEqual(t, ticker.Min10, ticker.Ask)
+		assert.GreaterOrEqual(t, ticker.Max10, ticker.Ask)
+		assert.NotZero(t, ticker.Change1m, "a minute of prices is kept")
+	}
+
+	other := newGenerator(3, 1)
+	assert.Equal(t, newGenerator(3, 1).next(start).Data["SOAK1USDT"].Ask, other.next(start).Data["SOAK1USDT"].Ask, "the same seed produces the same ticks")
+}
+
+func withoutLatency(s ClientStats) ClientStats {
+	s.Latency = 0
+	return s
+}
@@ -0,0 +1,97 @@
+package soak
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/domain"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils"
+	"github.com/ayankousky/exchange-data-importer/pkg/utils/mathutils"
+)
+
+// pathLength is the number of prices kept per symbol, enough for the 1 minute change at one tick per second
+const pathLength = 61
+
+// generator produces a random walk of ticks for a fixed set of symbols
+type generator struct {
+	rnd     *rand.Rand
+	symbols []domain.TickerName
+	paths   map[domain.TickerName][]float64
+	history *utils.RingBuffer[*domain.Tick]
+}
+
+func newGenerator(symbols int, seed uint64) *generator {
+	g := &generator{
+		rnd:     rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		symbols: make([]domain.TickerName, symbols),
+		paths:   make(map[domain.TickerName][]float64, symbols),
+		history: utils.NewRingBuffer[*domain.Tick](domain.MaxTickHistory),
+	}
+	for i := range g.symbols {
+		symbol := domain.TickerName(fmt.Sprintf("SOAK%dUSDT", i))
+		g.symbols[i] = symbol
+		g.paths[symbol] = []float64{math.Pow(10, float64(i%6))}
+	}
+	return g
+}
+
+// next returns the tick of the given time with every symbol moved by up to 0.5%
+func (g *generator) next(at time.Time) *domain.Tick {
+	tick := &domain.Tick{
+		StartAt:   at,
+		FetchedAt: at,
+		CreatedAt: at,
+		LL5:       g.rnd.Int64N(50),
+		SL2:       g.rnd.Int64N(5),
+		Data:      make(map[domain.TickerName]*domain.Ticker, len(g.symbols)),
+	}
+	tick.LL60 = tick.LL5 + g.rnd.Int64N(500)
+	tick.SL10 = tick.SL2 + g.rnd.Int64N(20)
+
+	for _, symbol := range g.symbols {
+		path := g.paths[symbol]
+		last := path[len(path)-1]
+		ask := mathutils.Round(last*(1+(g.rnd.Float64()*2-1)*0.005), 6)
+		path = append(path, ask)
+		if len(path) > pathLength {
+			path = path[1:]
+		}
+		g.paths[symbol] = path
+		tick.SetTicker(newTicker(symbol, at, path))
+	}
+
+	g.history.Push(tick)
+	tick.CalculateIndicators(g.history, domain.DefaultPrecision())
+	return tick
+}
+
+// newTicker builds a ticker from its recent price path, the last element being the current ask
+func newTicker(symbol domain.TickerName, at time.Time, path []float64) *domain.Ticker {
+	n := len(path)
+	ask := path[n-1]
+	ticker := &domain.Ticker{
+		Symbol:    symbol,
+		EventAt:   at,
+		CreatedAt: at,
+		Ask:       ask,
+		Bid:       mathutils.Round(ask*0.9999, 6),
+		Max10:     ask,
+		Min10:     ask,
+	}
+	for _, p := range path {
+		ticker.Max10 = math.Max(ticker.Max10, p)
+		ticker.Min10 = math.Min(ticker.Min10, p)
+	}
+	ticker.Max10Diff = mathutils.PercDiff(ask, ticker.Max10, 2)
+	ticker.Min10Diff = mathutils.PercDiff(ask, ticker.Min10, 2)
+	if n > 1 {
+		ticker.AskChange = mathutils.PercDiff(ask, path[n-2], 2)
+		ticker.BidChange = ticker.AskChange
+	}
+	if n == pathLength {
+		ticker.Change1m = mathutils.PercDiff(ask, path[0], 2)
+	}
+	return ticker
+}
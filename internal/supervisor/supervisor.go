@@ -0,0 +1,128 @@
+// Package supervisor keeps long-running subsystems alive by recovering their panics
+// and restarting them with exponential backoff.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultInitialBackoff is the delay before the first restart
+	DefaultInitialBackoff = time.Second
+
+	// DefaultMaxBackoff caps the delay between restarts
+	DefaultMaxBackoff = 30 * time.Second
+
+	// DefaultStableAfter is how long a subsystem must run without panicking for the backoff to reset
+	DefaultStableAfter = time.Minute
+)
+
+// Supervisor runs subsystems and restarts them when they panic.
+// A subsystem returning normally (with or without an error) is not restarted.
+type Supervisor struct {
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	stableAfter    time.Duration
+
+	telemetry telemetry.Provider
+	logger    *zap.Logger
+
+	wg sync.WaitGroup // subsystems started with Go, restarts included
+}
+
+// New creates a new Supervisor
+func New(logger *zap.Logger) *Supervisor {
+	return &Supervisor{
+		initialBackoff: DefaultInitialBackoff,
+		maxBackoff:     DefaultMaxBackoff,
+		stableAfter:    DefaultStableAfter,
+		telemetry:      &telemetry.NoopProvider{},
+		logger:         logger.With(zap.String("component", "supervisor")),
+	}
+}
+
+// WithTelemetry sets the telemetry provider used to count panics and restarts
+func (s *Supervisor) WithTelemetry(provider telemetry.Provider) *Supervisor {
+	if provider != nil {
+		s.telemetry = provider
+	}
+	return s
+}
+
+// WithBackoff overrides the restart backoff bounds
+func (s *Supervisor) WithBackoff(initial, maxBackoff time.Duration) *Supervisor {
+	if initial > 0 {
+		s.initialBackoff = initial
+	}
+	if maxBackoff >= s.initialBackoff {
+		s.maxBackoff = maxBackoff
+	}
+	return s
+}
+
+// Run executes fn and restarts it after every panic until it returns or ctx is canceled.
+// It returns the error of the last fn run, or ctx.Err() when canceled while waiting to restart.
+func (s *Supervisor) Run(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	backoff := s.initialBackoff
+	for {
+		startedAt := time.Now()
+		panicked, err := s.runOnce(ctx, name, fn)
+		if !panicked {
+			return err
+		}
+
+		// A subsystem that ran long enough is considered healthy again
+		if time.Since(startedAt) >= s.stableAfter {
+			backoff = s.initialBackoff
+		}
+
+		s.logger.Warn("Restarting subsystem", zap.String("subsystem", name), zap.Duration("backoff", backoff))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		s.telemetry.IncrementCounter(telemetrySupervisorRestarts, 1, fmt.Sprintf("subsystem:%s", name))
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
+
+// Go runs fn under supervision in a new goroutine, logging the final error. Wait awaits it
+func (s *Supervisor) Go(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.Run(ctx, name, fn); err != nil && ctx.Err() == nil {
+			s.logger.Error("Subsystem stopped", zap.String("subsystem", name), zap.Error(err))
+		}
+	}()
+}
+
+// Wait blocks until every subsystem started with Go returned, cancel their context first
+func (s *Supervisor) Wait() {
+	s.wg.Wait()
+}
+
+// runOnce executes fn a single time, converting a panic into a logged event
+func (s *Supervisor) runOnce(ctx context.Context, name string, fn func(ctx context.Context) error) (panicked bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicked = true
+			s.telemetry.IncrementCounter(telemetrySupervisorPanics, 1, fmt.Sprintf("subsystem:%s", name))
+			s.logger.Error("Subsystem panic",
+				zap.String("subsystem", name),
+				zap.Any("panic", r),
+				zap.ByteString("stack", debug.Stack()),
+			)
+		}
+	}()
+
+	return false, fn(ctx)
+}
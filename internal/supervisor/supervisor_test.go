@@ -0,0 +1,88 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry/telemetrytest"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSupervisor_RestartsAfterPanic(t *testing.T) {
+	tel := &telemetrytest.Counting{}
+	s := New(zap.NewNop()).WithTelemetry(tel).WithBackoff(time.Millisecond, 2*time.Millisecond)
+
+	runs := 0
+	err := s.Run(context.Background(), "test", func(ctx context.Context) error {
+		runs++
+		if runs < 3 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, runs)
+	assert.Equal(t, int64(2), tel.Counter(telemetrySupervisorPanics))
+	assert.Equal(t, int64(2), tel.Counter(telemetrySupervisorRestarts))
+}
+
+func TestSupervisor_DoesNotRestartOnError(t *testing.T) {
+	s := New(zap.NewNop()).WithBackoff(time.Millisecond, time.Millisecond)
+	wantErr := errors.New("stopped")
+
+	runs := 0
+	err := s.Run(context.Background(), "test", func(ctx context.Context) error {
+		runs++
+		return wantErr
+	})
+
+	assert.ErrorIs(t, err, wantErr)
+	assert.Equal(t, 1, runs)
+}
+
+func TestSupervisor_StopsWhenContextCanceledDuringBackoff(t *testing.T) {
+	s := New(zap.NewNop()).WithBackoff(time.Hour, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() {
+		done <- s.Run(ctx, "test", func(ctx context.Context) error {
+			panic("boom")
+		})
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("supervisor did not stop on context cancellation")
+	}
+}
+
+func TestSupervisor_WaitAwaitsRestartedSubsystems(t *testing.T) {
+	s := New(zap.NewNop()).WithBackoff(time.Millisecond, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs atomic.Int32
+	var stopped atomic.Bool
+	s.Go(ctx, "test", func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			panic("boom")
+		}
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond) // e.g. a last flush
+		stopped.Store(true)
+		return nil
+	})
+	assert.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	s.Wait()
+	assert.True(t, stopped.Load(), "the restarted subsystem returned before Wait")
+}
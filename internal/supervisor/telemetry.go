@@ -0,0 +1,20 @@
+package supervisor
+
+import "github.com/ayankousky/exchange-data-importer/internal/infrastructure/telemetry"
+
+// Telemetry constants for counters
+const (
+	// telemetrySupervisorPanics counts panics recovered from supervised subsystems
+	telemetrySupervisorPanics = "supervisor.panics"
+
+	// telemetrySupervisorRestarts counts restarts of supervised subsystems after a panic
+	telemetrySupervisorRestarts = "supervisor.restarts"
+)
+
+// Metrics returns the catalog entries of the metrics emitted by the package
+func Metrics() []telemetry.Metric {
+	return []telemetry.Metric{
+		{Name: telemetrySupervisorPanics, Kind: telemetry.KindCounter, Description: "Panics recovered from supervised subsystems", Tags: []string{"subsystem"}},
+		{Name: telemetrySupervisorRestarts, Kind: telemetry.KindCounter, Description: "Restarts of supervised subsystems after a panic", Tags: []string{"subsystem"}},
+	}
+}
@@ -0,0 +1,108 @@
+// Package symbolmeta annotates the symbols of an exchange with metadata loaded from a file: the categories they
+// belong to (L1, meme, DeFi...) and their market-cap tier, so tickers can be averaged and alerted on per category.
+package symbolmeta
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Meta is the metadata of a symbol
+type Meta struct {
+	Categories []string `json:"categories"`
+	Tier       string   `json:"tier"`
+}
+
+// Catalog holds the metadata of the symbols keyed by path.Match pattern, such as DOGE*
+type Catalog struct {
+	entries []entry // from the most specific pattern
+}
+
+type entry struct {
+	pattern string
+	meta    Meta
+}
+
+// Load reads a catalog from a JSON file mapping symbol patterns to their metadata, e.g.
+// {"BTC*": {"categories": ["L1"], "tier": "large"}, "UNI*": {"categories": ["DeFi"], "tier": "mid"}}
+func Load(file string) (*Catalog, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("reading symbol metadata: %w", err)
+	}
+	catalog, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return catalog, nil
+}
+
+// Parse parses a catalog, see Load. The patterns are ordered from the most specific one: exact symbols first,
+// then the patterns with the longest literal prefix, so DOGEUSDT overrides DOGE* which overrides *USDT
+func Parse(data []byte) (*Catalog, error) {
+	var byPattern map[string]Meta
+	if err := json.Unmarshal(data, &byPattern); err != nil {
+		return nil, fmt.Errorf("parsing symbol metadata: %w", err)
+	}
+
+	catalog := &Catalog{entries: make([]entry, 0, len(byPattern))}
+	for _, pattern := range slices.Sorted(maps.Keys(byPattern)) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q", pattern)
+		}
+		meta := byPattern[pattern]
+		for idx, category := range meta.Categories {
+			meta.Categories[idx] = strings.TrimSpace(category)
+			if meta.Categories[idx] == "" {
+				return nil, fmt.Errorf("%s: empty category", pattern)
+			}
+		}
+		meta.Categories = slices.Compact(slices.Sorted(slices.Values(meta.Categories)))
+		meta.Tier = strings.TrimSpace(meta.Tier)
+		catalog.entries = append(catalog.entries, entry{pattern: pattern, meta: meta})
+	}
+
+	literal := func(pattern string) int {
+		if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+			return i
+		}
+		return math.MaxInt
+	}
+	slices.SortStableFunc(catalog.entries, func(a, b entry) int {
+		return cmp.Or(
+			cmp.Compare(literal(b.pattern), literal(a.pattern)),
+			cmp.Compare(len(b.pattern), len(a.pattern)),
+		)
+	})
+	return catalog, nil
+}
+
+// Lookup returns the metadata of the most specific pattern matching the symbol, false for a nil catalog
+// or a symbol without metadata. The returned categories are shared and must not be modified
+func (c *Catalog) Lookup(symbol string) (Meta, bool) {
+	if c == nil {
+		return Meta{}, false
+	}
+	for _, e := range c.entries {
+		if matched, _ := path.Match(e.pattern, symbol); matched {
+			return e.meta, true
+		}
+	}
+	return Meta{}, false
+}
+
+// Len returns the number of patterns of the catalog
+func (c *Catalog) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.entries)
+}
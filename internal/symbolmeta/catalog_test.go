@@ -0,0 +1,83 @@
+package symbolmeta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "valid", data: `{"BTC*": {"categories": ["L1"], "tier": "large"}, "*USDT": {"tier": "small"}}`},
+		{name: "not json", data: `BTC*: L1`, wantErr: "parsing symbol metadata: invalid character 'B' looking for beginning of value"},
+		{name: "invalid pattern", data: `{"[BTC": {"tier": "large"}}`, wantErr: `invalid pattern "[BTC"`},
+		{name: "empty category", data: `{"BTC*": {"categories": ["L1", " "]}}`, wantErr: "BTC*: empty category"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCatalog_Lookup(t *testing.T) {
+	catalog, err := Parse([]byte(`{
+		"*USDT": {"tier": "small"},
+		"DOGE*": {"categories": ["meme"], "tier": "large"},
+		"DOGEUSDT": {"categories": ["meme", "L1", "meme"], "tier": "large"},
+		"UNI*": {"categories": [" DeFi "], "tier": "mid"}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, 4, catalog.Len())
+
+	tests := []struct {
+		symbol string
+		want   Meta
+		wantOk bool
+	}{
+		{symbol: "DOGEUSDT", want: Meta{Categories: []string{"L1", "meme"}, Tier: "large"}, wantOk: true},
+		{symbol: "DOGE-USDT-SWAP", want: Meta{Categories: []string{"meme"}, Tier: "large"}, wantOk: true},
+		{symbol: "UNIUSDT", want: Meta{Categories: []string{"DeFi"}, Tier: "mid"}, wantOk: true},
+		{symbol: "XRPUSDT", want: Meta{Tier: "small"}, wantOk: true},
+		{symbol: "XRPUSD_PERP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			meta, ok := catalog.Lookup(tt.symbol)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, meta)
+		})
+	}
+
+	var empty *Catalog
+	_, ok := empty.Lookup("BTCUSDT")
+	assert.False(t, ok)
+	assert.Zero(t, empty.Len())
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "symbols.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"BTC*": {"categories": ["L1"]}}`), 0o600))
+
+	catalog, err := Load(file)
+	require.NoError(t, err)
+	meta, ok := catalog.Lookup("BTCUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"L1"}, meta.Categories)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "reading symbol metadata")
+}
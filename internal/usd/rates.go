@@ -0,0 +1,83 @@
+package usd
+
+import (
+	"slices"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+)
+
+// Basis quote assets, prices quoted in them are taken as USD as is
+const (
+	USD  = "USD"
+	USDT = "USDT"
+)
+
+// stablecoins are assumed to hold their peg when no reference ticker prices them
+var stablecoins = []string{"USDC", "BUSD", "FDUSD", "TUSD"}
+
+// IsBasis reports whether prices quoted in the asset are already in the common USD basis
+func IsBasis(quote string) bool {
+	return quote == USD || quote == USDT
+}
+
+// Rates converts prices and notionals quoted in different assets into the common USD basis.
+// The rate of a quote asset is the mid price of its reference ticker, the ticker with that asset
+// as base quoted in USDT (preferred) or USD, e.g. USDCUSDT for USDC or BTCUSDT for BTC.
+// A nil *Rates only knows the basis and the stablecoin pegs
+type Rates struct {
+	rates map[string]float64
+}
+
+// NewRates derives the rates from the reference tickers of a fetch
+func NewRates(tickers []exchanges.Ticker) *Rates {
+	r := &Rates{rates: make(map[string]float64)}
+	for _, t := range tickers {
+		if t.Base == "" || !IsBasis(t.Quote) || t.AskPrice <= 0 || t.BidPrice <= 0 {
+			continue
+		}
+		if _, exists := r.rates[t.Base]; exists && t.Quote != USDT {
+			continue
+		}
+		r.rates[t.Base] = (t.AskPrice + t.BidPrice) / 2
+	}
+	return r
+}
+
+// Rate returns the USD value of one unit of the quote asset
+func (r *Rates) Rate(quote string) (float64, bool) {
+	if IsBasis(quote) {
+		return 1, true
+	}
+	if r != nil {
+		if rate, ok := r.rates[quote]; ok {
+			return rate, true
+		}
+	}
+	if slices.Contains(stablecoins, quote) {
+		return 1, true
+	}
+	return 0, false
+}
+
+// Notional returns the USD value of a quantity at a price quoted in the quote asset.
+// Quantities of coin-margined instruments are contract counts worth contractValue USD each
+func (r *Rates) Notional(price, quantity float64, quote string, contractValue float64) (float64, bool) {
+	if contractValue > 0 {
+		return quantity * contractValue, true
+	}
+	rate, ok := r.Rate(quote)
+	if !ok {
+		return 0, false
+	}
+	return price * quantity * rate, true
+}
+
+// TickerNotional returns the USD value of the thinner side of the top of the book
+func (r *Rates) TickerNotional(t exchanges.Ticker) (float64, bool) {
+	bid, ok := r.Notional(t.BidPrice, t.BidQuantity, t.Quote, t.ContractValue)
+	if !ok {
+		return 0, false
+	}
+	ask, _ := r.Notional(t.AskPrice, t.AskQuantity, t.Quote, t.ContractValue)
+	return min(bid, ask), true
+}
@@ -0,0 +1,86 @@
+package usd
+
+import (
+	"testing"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/exchanges"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRates_Rate(t *testing.T) {
+	rates := NewRates([]exchanges.Ticker{
+		{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", AskPrice: 50010, BidPrice: 49990},
+		{Symbol: "BTCUSD", Base: "BTC", Quote: "USD", AskPrice: 60000, BidPrice: 59000},
+		{Symbol: "ETHUSD", Base: "ETH", Quote: "USD", AskPrice: 2501, BidPrice: 2499},
+		{Symbol: "USDCUSDT", Base: "USDC", Quote: "USDT", AskPrice: 0.9992, BidPrice: 0.9990},
+		{Symbol: "ETHBTC", Base: "ETH", Quote: "BTC", AskPrice: 0.051, BidPrice: 0.05},
+		{Symbol: "BNBUSDT", Base: "BNB", Quote: "USDT"},
+	})
+
+	tests := []struct {
+		name     string
+		rates    *Rates
+		quote    string
+		wantRate float64
+		wantOK   bool
+	}{
+		{name: "usdt basis", rates: rates, quote: "USDT", wantRate: 1, wantOK: true},
+		{name: "usd basis", rates: rates, quote: "USD", wantRate: 1, wantOK: true},
+		{name: "usdt reference preferred", rates: rates, quote: "BTC", wantRate: 50000, wantOK: true},
+		{name: "usd reference", rates: rates, quote: "ETH", wantRate: 2500, wantOK: true},
+		{name: "stablecoin reference", rates: rates, quote: "USDC", wantRate: 0.9991, wantOK: true},
+		{name: "stablecoin peg without reference", rates: rates, quote: "FDUSD", wantRate: 1, wantOK: true},
+		{name: "reference without prices", rates: rates, quote: "BNB"},
+		{name: "unknown quote", rates: rates, quote: ""},
+		{name: "nil rates know the pegs", quote: "BUSD", wantRate: 1, wantOK: true},
+		{name: "nil rates know no references", quote: "BTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, ok := tt.rates.Rate(tt.quote)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.wantRate, rate, 1e-9)
+		})
+	}
+}
+
+func TestRates_Notional(t *testing.T) {
+	rates := NewRates([]exchanges.Ticker{
+		{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", AskPrice: 50000, BidPrice: 50000},
+	})
+
+	tests := []struct {
+		name          string
+		price         float64
+		quantity      float64
+		quote         string
+		contractValue float64
+		want          float64
+		wantOK        bool
+	}{
+		{name: "linear usdt", price: 2500, quantity: 2, quote: "USDT", want: 5000, wantOK: true},
+		{name: "btc quoted", price: 0.05, quantity: 10, quote: "BTC", want: 25000, wantOK: true},
+		{name: "coin-margined contracts", price: 50000, quantity: 20, quote: "USD", contractValue: 100, want: 2000, wantOK: true},
+		{name: "unknown quote", price: 1, quantity: 1, quote: "XYZ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notional, ok := rates.Notional(tt.price, tt.quantity, tt.quote, tt.contractValue)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.InDelta(t, tt.want, notional, 1e-9)
+		})
+	}
+}
+
+func TestRates_TickerNotional(t *testing.T) {
+	var rates *Rates
+
+	notional, ok := rates.TickerNotional(exchanges.Ticker{Quote: "USDT", AskPrice: 101, AskQuantity: 10, BidPrice: 100, BidQuantity: 2})
+	assert.True(t, ok)
+	assert.InDelta(t, 200, notional, 1e-9, "the thinner side counts")
+
+	_, ok = rates.TickerNotional(exchanges.Ticker{AskPrice: 101, AskQuantity: 10, BidPrice: 100, BidQuantity: 2})
+	assert.False(t, ok)
+}
@@ -0,0 +1,111 @@
+// Package embedded runs the importer as a library inside another Go service, e.g. as a fixture of its integration
+// tests: the events of the subscribed topics are delivered in-process on a channel instead of Redis or Telegram.
+//
+// The importer is configured with the same options as the binary, passed as command-line flags in Config.Args
+// (environment variables apply as well). Ticks are kept in memory unless a repository is configured.
+//
+//	importer, err := embedded.New(ctx, embedded.Config{
+//		Args:   []string{"--exchange.binance.enabled", "--exchange.binance.api-url", fakeExchange.URL},
+//		Topics: []string{"TICK_INFO", "ALERT_MARKET_STATE"},
+//	})
+//	go importer.Start(ctx)
+//	defer importer.Stop(context.Background())
+//	for event := range importer.Events() { ... }
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/bootstrap"
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+)
+
+// clientName names the in-process client in logs, metrics and the stored alerts
+const clientName = "embedded"
+
+// Event is an event delivered on a topic: its time, type (e.g. MARKET_DATA) and the payload formatted for the topic,
+// encoded as JSON as it's published to Redis
+type Event struct {
+	Time      time.Time       `json:"ct"`
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Config configures an embedded importer
+type Config struct {
+	Args   []string // options as command-line flags, e.g. --exchange.binance.enabled; the process arguments are ignored
+	Topics []string // topics whose events are delivered on Events, e.g. MARKET_DATA, TICK_INFO, ALERT_MARKET_STATE
+	Buffer int      // capacity of the events channel, 0 uses 1024; the importer blocks on sends while it's full
+}
+
+// Importer is an importer running in-process
+type Importer struct {
+	apps   *bootstrap.Apps
+	events chan Event
+}
+
+// New validates the options and builds the importer of every enabled exchange, subscribing the in-process client
+// to the topics. Nothing is imported before Start
+func New(ctx context.Context, cfg Config) (*Importer, error) {
+	if len(cfg.Topics) == 0 {
+		return nil, fmt.Errorf("at least one topic is required")
+	}
+
+	client := jsonClient{notify.NewChannelNotifier(cfg.Buffer)}
+	apps, err := bootstrap.NewBuilderWithArgs(cfg.Args).
+		ValidateOptions().
+		WithLogger(ctx).
+		WithTelemetry(ctx, bootstrap.NewBuildInfo(clientName, "")).
+		WithClient(clientName, client, cfg.Topics...).
+		BuildExchanges(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("building embedded importer: %w", err)
+	}
+
+	i := &Importer{apps: apps, events: make(chan Event)}
+	go i.forward(client.Events())
+	return i, nil
+}
+
+// Events returns the channel the events of the topics are delivered on, closed by Stop
+func (i *Importer) Events() <-chan Event {
+	return i.events
+}
+
+// forward delivers the buffered events on the events channel until the client is closed
+func (i *Importer) forward(events <-chan notify.Event) {
+	defer close(i.events)
+	for event := range events {
+		data, _ := event.Data.(json.RawMessage)
+		i.events <- Event{Time: event.Time, EventType: event.EventType, Data: data}
+	}
+}
+
+// jsonClient encodes the payloads of the events as JSON before they're buffered, so the internal types of the
+// importer don't leak out of the package and a payload failing to encode counts as a failed delivery
+type jsonClient struct {
+	*notify.ChannelNotifier
+}
+
+// Send encodes the payload of the event and buffers it
+func (c jsonClient) Send(ctx context.Context, event notify.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("encoding %s event: %w", event.EventType, err)
+	}
+	event.Data = json.RawMessage(data)
+	return c.ChannelNotifier.Send(ctx, event)
+}
+
+// Start starts the importers and blocks until ctx is canceled or an importer fails, call Stop afterwards
+func (i *Importer) Start(ctx context.Context) error {
+	return i.apps.Start(ctx)
+}
+
+// Stop shuts the importers down and closes the events channel
+func (i *Importer) Stop(ctx context.Context) error {
+	return i.apps.Stop(ctx)
+}
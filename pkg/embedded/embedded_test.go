@@ -0,0 +1,62 @@
+package embedded
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ayankousky/exchange-data-importer/internal/infrastructure/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(context.Background(), Config{Args: []string{"--exchange.binance.enabled"}})
+	assert.EqualError(t, err, "at least one topic is required")
+
+	_, err = New(context.Background(), Config{Topics: []string{"TICK_INFO"}})
+	assert.ErrorContains(t, err, "no exchange enabled")
+
+	importer, err := New(context.Background(), Config{
+		Args: []string{
+			"--exchange.binance.enabled",
+			"--exchange.binance.api-url", "https://dummy-api.binance.com",
+			"--exchange.binance.ws-url", "wss://dummy-ws.binance.com",
+		},
+		Topics: []string{"TICK_INFO", "ALERT_MARKET_STATE"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, importer.Events())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, importer.Stop(ctx))
+	_, ok := <-importer.Events()
+	assert.False(t, ok, "the events channel is closed on stop")
+}
+
+func TestJSONClient(t *testing.T) {
+	client := jsonClient{notify.NewChannelNotifier(2)}
+	importer := &Importer{events: make(chan Event)}
+	go importer.forward(client.Events())
+
+	at := time.UnixMilli(1700000000000)
+	payload := struct {
+		Symbol string  `json:"s"`
+		Ask    float64 `json:"ask"`
+	}{Symbol: "BTCUSDT", Ask: 30000}
+	require.NoError(t, client.Send(context.Background(), notify.Event{Time: at, EventType: "MARKET_DATA", Data: payload}))
+	assert.ErrorContains(t, client.Send(context.Background(), notify.Event{EventType: "MARKET_DATA", Data: math.NaN()}),
+		"encoding MARKET_DATA event")
+	require.NoError(t, client.Close())
+
+	event := <-importer.Events()
+	assert.Equal(t, at, event.Time)
+	assert.Equal(t, "MARKET_DATA", event.EventType)
+	assert.JSONEq(t, `{"s":"BTCUSDT","ask":30000}`, string(event.Data))
+
+	_, ok := <-importer.Events()
+	assert.False(t, ok, "the events channel is closed with the client")
+}
@@ -0,0 +1,41 @@
+package embedded_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ayankousky/exchange-data-importer/pkg/embedded"
+)
+
+func Example() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	importer, err := embedded.New(ctx, embedded.Config{
+		Args:   []string{"--exchange.binance.enabled", "--exchange.binance.api-url", "http://localhost:8080"},
+		Topics: []string{"MARKET_DATA"},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	go func() { _ = importer.Start(ctx) }()
+	defer func() { _ = importer.Stop(context.Background()) }()
+
+	for event := range importer.Events() {
+		// a MARKET_DATA event of the default ticker format
+		var data struct {
+			Ticker struct {
+				Symbol string  `json:"s"`
+				Ask    float64 `json:"ask"`
+				Bid    float64 `json:"bid"`
+			} `json:"ticker"`
+		}
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(event.EventType, data.Ticker.Symbol, data.Ticker.Ask, data.Ticker.Bid)
+	}
+}
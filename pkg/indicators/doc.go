@@ -0,0 +1,12 @@
+// Package indicators contains the market indicators computed by the importer.
+//
+// The functions here are the single source of truth for the values stored with every tick,
+// so services reading that data can reproduce them exactly: the same inputs fed in the same
+// order yield bit-for-bit identical float64 results.
+//
+// Every indicator comes in two flavours:
+//   - a batch function working on a slice of values (oldest first), e.g. RSI, WilderRSIOf
+//   - a streaming type fed one value at a time with Push, e.g. RollingRSI, WilderRSI, EMA, RollingMax
+//
+// Streaming types are not safe for concurrent use.
+package indicators
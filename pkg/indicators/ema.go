@@ -0,0 +1,53 @@
+package indicators
+
+// EMA is a streaming exponential moving average.
+// The first `period` values seed it with their simple average, after that every value
+// is blended in with the smoothing factor 2 / (period + 1).
+type EMA struct {
+	period int
+	alpha  float64
+	count  int
+	sum    float64
+	value  float64
+}
+
+// NewEMA creates an EMA over the given period
+func NewEMA(period int) *EMA {
+	period = max(period, 1)
+	return &EMA{
+		period: period,
+		alpha:  2 / float64(period+1),
+	}
+}
+
+// Push adds the next value and returns the updated average
+func (e *EMA) Push(value float64) float64 {
+	e.count++
+	if e.count <= e.period {
+		e.sum += value
+		e.value = e.sum / float64(e.count)
+		return e.value
+	}
+	e.value = value*e.alpha + e.value*(1-e.alpha)
+	return e.value
+}
+
+// Value returns the current average, 0 before the first Push
+func (e *EMA) Value() float64 {
+	return e.value
+}
+
+// Ready reports whether the seeding period is complete
+func (e *EMA) Ready() bool {
+	return e.count >= e.period
+}
+
+// EMASeries returns the EMA after each value of history
+func EMASeries(history []float64, period int) []float64 {
+	ema := NewEMA(period)
+	out := make([]float64, len(history))
+	for i, v := range history {
+		out[i] = ema.Push(v)
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEMA(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []float64
+		period  int
+		want    []float64
+	}{
+		{
+			name:    "seeds with simple average",
+			history: []float64{2, 4, 6},
+			period:  3,
+			want:    []float64{2, 3, 4},
+		},
+		{
+			name:    "smooths after seeding",
+			history: []float64{2, 4, 6, 8},
+			period:  3,
+			want:    []float64{2, 3, 4, 6},
+		},
+		{
+			name:    "period of one follows the input",
+			history: []float64{5, 1, 3},
+			period:  1,
+			want:    []float64{5, 1, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EMASeries(tt.history, tt.period))
+		})
+	}
+}
+
+func TestEMA_Ready(t *testing.T) {
+	ema := NewEMA(2)
+	assert.False(t, ema.Ready())
+	ema.Push(1)
+	assert.False(t, ema.Ready())
+	ema.Push(3)
+	assert.True(t, ema.Ready())
+	assert.Equal(t, 2.0, ema.Value())
+}
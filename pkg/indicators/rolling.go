@@ -0,0 +1,77 @@
+package indicators
+
+// Rolling tracks the maximum or minimum of the last `size` values in amortized O(1)
+// using a monotonic deque of candidates.
+type Rolling struct {
+	size   int
+	better func(a, b float64) bool
+
+	// candidates holds (index, value) pairs whose values are monotonic, the front being the extremum
+	indexes []int
+	values  []float64
+	count   int
+}
+
+// NewRollingMax creates a Rolling tracking the maximum of the last `size` values
+func NewRollingMax(size int) *Rolling {
+	return newRolling(size, func(a, b float64) bool { return a >= b })
+}
+
+// NewRollingMin creates a Rolling tracking the minimum of the last `size` values
+func NewRollingMin(size int) *Rolling {
+	return newRolling(size, func(a, b float64) bool { return a <= b })
+}
+
+func newRolling(size int, better func(a, b float64) bool) *Rolling {
+	return &Rolling{size: max(size, 1), better: better}
+}
+
+// Push adds the next value and returns the extremum of the window
+func (r *Rolling) Push(value float64) float64 {
+	// Drop candidates the new value dominates
+	for n := len(r.values); n > 0 && r.better(value, r.values[n-1]); n-- {
+		r.values = r.values[:n-1]
+		r.indexes = r.indexes[:n-1]
+	}
+	r.values = append(r.values, value)
+	r.indexes = append(r.indexes, r.count)
+	r.count++
+
+	// Drop the front once it falls out of the window
+	if r.indexes[0] <= r.count-1-r.size {
+		r.values = r.values[1:]
+		r.indexes = r.indexes[1:]
+	}
+	return r.values[0]
+}
+
+// Value returns the extremum of the window, 0 before the first Push
+func (r *Rolling) Value() float64 {
+	if len(r.values) == 0 {
+		return 0
+	}
+	return r.values[0]
+}
+
+// Len returns the number of values currently in the window
+func (r *Rolling) Len() int {
+	return min(r.count, r.size)
+}
+
+// Max returns the maximum of the last `size` values of history
+func Max(history []float64, size int) float64 {
+	return extremum(history, size, NewRollingMax)
+}
+
+// Min returns the minimum of the last `size` values of history
+func Min(history []float64, size int) float64 {
+	return extremum(history, size, NewRollingMin)
+}
+
+func extremum(history []float64, size int, newRolling func(int) *Rolling) float64 {
+	r := newRolling(size)
+	for _, v := range history[max(len(history)-size, 0):] {
+		r.Push(v)
+	}
+	return r.Value()
+}
@@ -0,0 +1,39 @@
+package indicators
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolling_MatchesBruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewPCG(3, 4))
+	for _, size := range []int{1, 2, 10, 20} {
+		rollingMax, rollingMin := NewRollingMax(size), NewRollingMin(size)
+		var history []float64
+
+		for i := 0; i < 300; i++ {
+			// Repeated values exercise the equality branch of the deque
+			v := float64(rnd.IntN(20))
+			history = append(history, v)
+
+			wantMax, wantMin := history[max(len(history)-size, 0)], history[max(len(history)-size, 0)]
+			for _, h := range history[max(len(history)-size, 0):] {
+				wantMax = max(wantMax, h)
+				wantMin = min(wantMin, h)
+			}
+
+			assert.Equal(t, wantMax, rollingMax.Push(v), "max size=%d step=%d", size, i)
+			assert.Equal(t, wantMin, rollingMin.Push(v), "min size=%d step=%d", size, i)
+			assert.Equal(t, wantMax, Max(history, size))
+			assert.Equal(t, wantMin, Min(history, size))
+		}
+		assert.Equal(t, size, rollingMax.Len())
+	}
+}
+
+func TestRolling_Empty(t *testing.T) {
+	assert.Equal(t, 0.0, NewRollingMax(5).Value())
+	assert.Equal(t, 0.0, Min(nil, 5))
+}
@@ -0,0 +1,95 @@
+package indicators
+
+// RSI calculates the Relative Strength Index over the last `period` values of history.
+// Up and down moves are summed without smoothing; a flat line yields 50.
+// When history is shorter than period all values are used, fewer than 2 values yield 0.
+func RSI(history []float64, period int) float64 {
+	if len(history) < 2 {
+		return 0
+	}
+
+	// We'll take the last `period` items in history
+	if len(history) < period {
+		period = len(history)
+	}
+	slice := history[len(history)-period:]
+
+	var up float64
+	var down float64
+
+	// Accumulate up/down moves
+	for i := 1; i < len(slice); i++ {
+		current, previous := slice[i], slice[i-1]
+		if current > previous {
+			up += current - previous
+		} else {
+			down += previous - current
+		}
+	}
+
+	return rsiFromMoves(up, down)
+}
+
+// rsiFromMoves turns accumulated up/down moves into the RSI value
+func rsiFromMoves(up, down float64) float64 {
+	// Handle edge cases
+	if up == 0 && down == 0 {
+		// Flat line => RSI is 50
+		return 50
+	}
+	if up == 0 {
+		// Pure downward movement
+		return 0
+	}
+	if down == 0 {
+		// Pure upward movement
+		return 100
+	}
+
+	// Standard RSI formula
+	return 100.0 - (100.0 / (1.0 + (up / down)))
+}
+
+// RollingRSI is the streaming counterpart of RSI: it keeps the last `period` values
+// and Value returns exactly what RSI would return for them.
+type RollingRSI struct {
+	values *window
+}
+
+// NewRollingRSI creates a RollingRSI over the given period
+func NewRollingRSI(period int) *RollingRSI {
+	return &RollingRSI{values: newWindow(max(period, 1))}
+}
+
+// Push adds the next value
+func (r *RollingRSI) Push(value float64) {
+	r.values.push(value)
+}
+
+// Len returns the number of values currently in the window
+func (r *RollingRSI) Len() int {
+	return r.values.len()
+}
+
+// Value returns the RSI of the values in the window
+func (r *RollingRSI) Value() float64 {
+	n := r.values.len()
+	if n < 2 {
+		return 0
+	}
+
+	// Sum in the same order as RSI so both produce identical floats
+	var up, down float64
+	previous := r.values.at(0)
+	for i := 1; i < n; i++ {
+		current := r.values.at(i)
+		if current > previous {
+			up += current - previous
+		} else {
+			down += previous - current
+		}
+		previous = current
+	}
+
+	return rsiFromMoves(up, down)
+}
@@ -0,0 +1,76 @@
+package indicators
+
+// WilderRSI is a streaming RSI using Wilder's smoothing: the first `period` moves seed
+// the average gain/loss with their simple mean, each following move is blended in as
+// avg = (avg*(period-1) + move) / period. Push and Peek are O(1).
+type WilderRSI struct {
+	period  int
+	count   int // values pushed
+	last    float64
+	avgGain float64
+	avgLoss float64
+}
+
+// NewWilderRSI creates a WilderRSI over the given period
+func NewWilderRSI(period int) *WilderRSI {
+	return &WilderRSI{period: max(period, 1)}
+}
+
+// Push commits the next value
+func (r *WilderRSI) Push(value float64) {
+	if r.count > 0 {
+		r.avgGain, r.avgLoss = r.next(value)
+	}
+	r.last = value
+	r.count++
+}
+
+// Peek returns the RSI as if value was pushed next, without committing it.
+// The second result is false until enough moves are available to seed the averages.
+func (r *WilderRSI) Peek(value float64) (float64, bool) {
+	if r.count == 0 || r.count < r.period {
+		return 0, false
+	}
+	gain, loss := r.next(value)
+	return rsiFromMoves(gain, loss), true
+}
+
+// Value returns the RSI of the committed values
+func (r *WilderRSI) Value() (float64, bool) {
+	if r.count <= r.period {
+		return 0, false
+	}
+	return rsiFromMoves(r.avgGain, r.avgLoss), true
+}
+
+// WilderRSIOf is the batch counterpart of WilderRSI: the RSI of a WilderRSI fed the values (oldest first).
+// The second result is false with fewer than period moves
+func WilderRSIOf(values []float64, period int) (float64, bool) {
+	r := NewWilderRSI(period)
+	for _, value := range values {
+		r.Push(value)
+	}
+	return r.Value()
+}
+
+// next returns the averages after moving from the last committed value to value
+func (r *WilderRSI) next(value float64) (float64, float64) {
+	var gain, loss float64
+	if value > r.last {
+		gain = value - r.last
+	} else {
+		loss = r.last - value
+	}
+
+	moves := r.count // number of moves including this one
+	p := float64(r.period)
+	switch {
+	case moves < r.period:
+		// Still seeding: keep running sums
+		return r.avgGain + gain, r.avgLoss + loss
+	case moves == r.period:
+		return (r.avgGain + gain) / p, (r.avgLoss + loss) / p
+	default:
+		return (r.avgGain*(p-1) + gain) / p, (r.avgLoss*(p-1) + loss) / p
+	}
+}
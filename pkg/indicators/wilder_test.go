@@ -0,0 +1,73 @@
+package indicators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWilderRSI(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		period int
+		want   float64
+		ready  bool
+	}{
+		{
+			name:   "not seeded",
+			values: []float64{1, 2, 3},
+			period: 3,
+			ready:  false,
+		},
+		{
+			name:   "seeded with simple average",
+			values: []float64{10, 12, 11, 13},
+			period: 3,
+			// gains 2+2, losses 1 => 100 - 100/(1+4)
+			want:  80,
+			ready: true,
+		},
+		{
+			name:   "smoothed after seeding",
+			values: []float64{10, 12, 11, 13, 10},
+			period: 3,
+			// avgGain (4/3*2+0)/3 = 8/9, avgLoss (1/3*2+3)/3 = 11/9
+			want:  100 - 100/(1+8.0/11.0),
+			ready: true,
+		},
+		{
+			name:   "pure upward movement",
+			values: []float64{1, 2, 3, 4, 5, 6},
+			period: 3,
+			want:   100,
+			ready:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsi := NewWilderRSI(tt.period)
+			for _, v := range tt.values[:len(tt.values)-1] {
+				rsi.Push(v)
+			}
+
+			// Peek must agree with pushing the value
+			last := tt.values[len(tt.values)-1]
+			peeked, peekReady := rsi.Peek(last)
+			rsi.Push(last)
+			value, ready := rsi.Value()
+
+			batch, batchReady := WilderRSIOf(tt.values, tt.period)
+			assert.Equal(t, ready, batchReady)
+			assert.Equal(t, value, batch, "the batch function matches the streaming type")
+
+			assert.Equal(t, tt.ready, ready)
+			assert.Equal(t, tt.ready, peekReady)
+			if tt.ready {
+				assert.InDelta(t, tt.want, value, 1e-9)
+				assert.Equal(t, value, peeked)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package indicators
+
+// window is a minimal fixed-size FIFO of float64 values used by the streaming indicators
+type window struct {
+	data  []float64
+	start int
+	size  int
+}
+
+func newWindow(capacity int) *window {
+	return &window{data: make([]float64, capacity)}
+}
+
+// push appends a value, evicting the oldest one when full. Returns the evicted value and whether eviction happened
+func (w *window) push(value float64) (float64, bool) {
+	if w.size < len(w.data) {
+		w.data[(w.start+w.size)%len(w.data)] = value
+		w.size++
+		return 0, false
+	}
+	evicted := w.data[w.start]
+	w.data[w.start] = value
+	w.start = (w.start + 1) % len(w.data)
+	return evicted, true
+}
+
+// at returns the i-th value, 0 being the oldest
+func (w *window) at(i int) float64 {
+	return w.data[(w.start+i)%len(w.data)]
+}
+
+func (w *window) len() int {
+	return w.size
+}
@@ -5,14 +5,14 @@ import (
 )
 
 // PercDiff calculates a percent difference between curr and prev,
-// then rounds to 'decimals' decimals. e.g. decimals=2 => 12.34
+// then rounds to 'decimals' decimals. e.g. decimals=2 => 12.34, negative decimals keep it unrounded
 func PercDiff(curr, prev float64, decimals int) float64 {
 	// Guard against divide by zero
 	if prev == 0 {
 		return 0
 	}
 	diff := (curr - prev) / prev * 100
-	if decimals == -1 {
+	if decimals < 0 {
 		return diff
 	}
 	return Round(diff, decimals)
@@ -33,3 +33,11 @@ func Round(val float64, decimals int) float64 {
 	p := math.Pow10(decimals)
 	return math.Round(val*p) / p
 }
+
+// RoundTo rounds a float64 to the specified number of decimal places, negative decimals keep it unrounded.
+func RoundTo(val float64, decimals int) float64 {
+	if decimals < 0 {
+		return val
+	}
+	return Round(val, decimals)
+}
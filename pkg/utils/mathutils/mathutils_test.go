@@ -76,3 +76,9 @@ func TestRound(t *testing.T) {
 		})
 	}
 }
+
+func TestRoundTo(t *testing.T) {
+	assert.Equal(t, 123.46, RoundTo(123.456, 2))
+	assert.Equal(t, 123.0, RoundTo(123.456, 0))
+	assert.Equal(t, 123.456, RoundTo(123.456, -1), "unrounded")
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"iter"
+	"slices"
+)
+
+// Ring is a fixed-size circular buffer storing up to `capacity` items of type T.
+// When full, new pushes overwrite the oldest items.
+// Ring is not safe for concurrent use; RingBuffer is the concurrency-safe variant.
+type Ring[T any] struct {
+	data     []T
+	start    int
+	size     int
+	capacity int
+
+	// shared is set when a View references data; the next write copies it first
+	shared bool
+}
+
+// NewRing allocates a new ring with the given capacity.
+func NewRing[T any](capacity int) *Ring[T] {
+	return &Ring[T]{
+		data:     make([]T, capacity),
+		capacity: capacity,
+	}
+}
+
+// Push adds a new item to the ring. If full, overwrites the oldest.
+func (r *Ring[T]) Push(value T) {
+	if r.shared {
+		// A snapshot still reads the current array: copy on write
+		r.data = slices.Clone(r.data)
+		r.shared = false
+	}
+
+	if r.size < r.capacity {
+		// Not full: place item at (start+size) % capacity
+		idx := (r.start + r.size) % r.capacity
+		r.data[idx] = value
+		r.size++
+	} else {
+		// Full: overwrite the oldest item at `start`
+		r.data[r.start] = value
+		r.start = (r.start + 1) % r.capacity
+	}
+}
+
+// At returns the element at index i (0=oldest, size-1=newest).
+func (r *Ring[T]) At(i int) T {
+	if i < 0 || i >= r.size {
+		panic("ring: index out of range")
+	}
+	return r.data[(r.start+i)%r.capacity]
+}
+
+// Len returns the number of items in the ring.
+func (r *Ring[T]) Len() int {
+	return r.size
+}
+
+// Cap returns the total ring capacity.
+func (r *Ring[T]) Cap() int {
+	return r.capacity
+}
+
+// Last returns the newest item, if any.
+func (r *Ring[T]) Last() (T, bool) {
+	var zero T
+	if r.size == 0 {
+		return zero, false
+	}
+	return r.data[(r.start+r.size-1)%r.capacity], true
+}
+
+// Values returns a copy of all items in order from oldest to newest.
+func (r *Ring[T]) Values() []T {
+	out := make([]T, r.size)
+	for i := range out {
+		out[i] = r.data[(r.start+i)%r.capacity]
+	}
+	return out
+}
+
+// Range calls fn for every item from oldest to newest until fn returns false.
+func (r *Ring[T]) Range(fn func(i int, value T) bool) {
+	for i := 0; i < r.size; i++ {
+		if !fn(i, r.data[(r.start+i)%r.capacity]) {
+			return
+		}
+	}
+}
+
+// Snapshot returns a read-only view of the current items without copying them.
+// Later pushes do not affect the view.
+func (r *Ring[T]) Snapshot() View[T] {
+	r.shared = true
+	return View[T]{data: r.data, start: r.start, size: r.size}
+}
+
+// View is an immutable point-in-time view of a ring, safe to read from any goroutine.
+type View[T any] struct {
+	data  []T
+	start int
+	size  int
+}
+
+// Len returns the number of items in the view.
+func (v View[T]) Len() int {
+	return v.size
+}
+
+// At returns the element at index i (0=oldest, Len()-1=newest).
+func (v View[T]) At(i int) T {
+	if i < 0 || i >= v.size {
+		panic("ring: index out of range")
+	}
+	return v.data[(v.start+i)%len(v.data)]
+}
+
+// Values returns a copy of all items in order from oldest to newest.
+func (v View[T]) Values() []T {
+	out := make([]T, v.size)
+	for i := range out {
+		out[i] = v.data[(v.start+i)%len(v.data)]
+	}
+	return out
+}
+
+// All returns an iterator over index/item pairs from oldest to newest.
+func (v View[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < v.size; i++ {
+			if !yield(i, v.data[(v.start+i)%len(v.data)]) {
+				return
+			}
+		}
+	}
+}
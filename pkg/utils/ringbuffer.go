@@ -2,21 +2,18 @@ package utils
 
 import "sync"
 
-// RingBuffer is a fixed-size circular buffer storing up to `capacity` items of type T.
+// RingBuffer is a concurrency-safe fixed-size circular buffer storing up to `capacity` items of type T.
 // When full, new pushes overwrite the oldest items.
+// Readers that need a consistent view while a writer keeps pushing should use Snapshot or Range.
 type RingBuffer[T any] struct {
-	data     []T
-	start    int
-	size     int
-	capacity int
-	mu       sync.RWMutex
+	ring Ring[T]
+	mu   sync.RWMutex
 }
 
 // NewRingBuffer allocates a new ring buffer with the given capacity.
 func NewRingBuffer[T any](capacity int) *RingBuffer[T] {
 	return &RingBuffer[T]{
-		data:     make([]T, capacity),
-		capacity: capacity,
+		ring: *NewRing[T](capacity),
 	}
 }
 
@@ -25,16 +22,7 @@ func (r *RingBuffer[T]) Push(value T) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if r.size < r.capacity {
-		// Not full: place item at (start+size) % capacity
-		idx := (r.start + r.size) % r.capacity
-		r.data[idx] = value
-		r.size++
-	} else {
-		// Full: overwrite the oldest item at `start`
-		r.data[r.start] = value
-		r.start = (r.start + 1) % r.capacity
-	}
+	r.ring.Push(value)
 }
 
 // At returns the element at index i (0=oldest, size-1=newest).
@@ -42,10 +30,7 @@ func (r *RingBuffer[T]) At(i int) T {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	if i < 0 || i >= r.size {
-		panic("ring: index out of range")
-	}
-	return r.data[(r.start+i)%r.capacity]
+	return r.ring.At(i)
 }
 
 // Len returns the number of items in the ring.
@@ -53,12 +38,12 @@ func (r *RingBuffer[T]) Len() int {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	return r.size
+	return r.ring.Len()
 }
 
 // Cap returns the total ring capacity.
 func (r *RingBuffer[T]) Cap() int {
-	return r.capacity
+	return r.ring.Cap()
 }
 
 // Last returns the newest item, if any.
@@ -66,12 +51,7 @@ func (r *RingBuffer[T]) Last() (T, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	var zero T
-	if r.size == 0 {
-		return zero, false
-	}
-	idx := (r.start + r.size - 1) % r.capacity
-	return r.data[idx], true
+	return r.ring.Last()
 }
 
 // Values returns a copy of all items in order from oldest to newest.
@@ -79,9 +59,25 @@ func (r *RingBuffer[T]) Values() []T {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	out := make([]T, r.size)
-	for i := 0; i < r.size; i++ {
-		out[i] = r.data[(r.start+i)%r.capacity]
+	return r.ring.Values()
+}
+
+// Snapshot returns a read-only view of the current items without copying them.
+// The view stays consistent while the buffer keeps being written to.
+func (r *RingBuffer[T]) Snapshot() View[T] {
+	// Snapshot marks the data as shared, so it needs the write lock
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ring.Snapshot()
+}
+
+// Range calls fn for every item from oldest to newest until fn returns false.
+// It iterates over a snapshot, so fn may safely call back into the buffer.
+func (r *RingBuffer[T]) Range(fn func(i int, value T) bool) {
+	for i, v := range r.Snapshot().All() {
+		if !fn(i, v) {
+			return
+		}
 	}
-	return out
 }
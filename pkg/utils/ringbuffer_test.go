@@ -3,6 +3,8 @@ package utils
 import (
 	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRingBuffer_Basic(t *testing.T) {
@@ -117,3 +119,85 @@ func TestRingBuffer_AtPanic(t *testing.T) {
 
 	rb.At(0) // Should panic
 }
+
+func TestRingBuffer_Range(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	for i := 1; i <= 5; i++ {
+		rb.Push(i)
+	}
+
+	var got []int
+	rb.Range(func(i int, v int) bool {
+		got = append(got, v)
+		return true
+	})
+	assert.Equal(t, []int{3, 4, 5}, got)
+
+	// Stops early and may push from inside the callback
+	got = nil
+	rb.Range(func(i int, v int) bool {
+		got = append(got, v)
+		rb.Push(v * 10)
+		return i < 1
+	})
+	assert.Equal(t, []int{3, 4}, got)
+	assert.Equal(t, []int{5, 30, 40}, rb.Values())
+}
+
+func TestRingBuffer_SnapshotIsStable(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	rb.Push(1)
+	rb.Push(2)
+
+	view := rb.Snapshot()
+	rb.Push(3)
+	rb.Push(4)
+
+	assert.Equal(t, 2, view.Len())
+	assert.Equal(t, []int{1, 2}, view.Values())
+	assert.Equal(t, 2, view.At(1))
+	assert.Equal(t, []int{2, 3, 4}, rb.Values())
+	assert.Panics(t, func() { view.At(2) })
+}
+
+func TestRingBuffer_SnapshotConcurrentWithPush(t *testing.T) {
+	rb := NewRingBuffer[int](16)
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			rb.Push(i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			view := rb.Snapshot()
+			prev := -1
+			for _, v := range view.All() {
+				// Items are pushed in increasing order, a stable view must preserve it
+				assert.Greater(t, v, prev)
+				prev = v
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestRing_Unsynchronized(t *testing.T) {
+	r := NewRing[string](2)
+	_, ok := r.Last()
+	assert.False(t, ok)
+
+	r.Push("a")
+	r.Push("b")
+	r.Push("c")
+
+	last, ok := r.Last()
+	assert.True(t, ok)
+	assert.Equal(t, "c", last)
+	assert.Equal(t, []string{"b", "c"}, r.Values())
+	assert.Equal(t, 2, r.Cap())
+}